@@ -27,26 +27,37 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/auditlog"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/catalog"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cls"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/deadletter"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/edp"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/entitlements"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/health"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logging"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/maintenance"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/metrics"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration"
 	orchestrate "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/handlers"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/manager"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/notification"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/report"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/binding"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/deprovisioning"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/input"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/provisioning"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/replay"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_cluster"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provider"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/quota"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime/components"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtimeoverrides"
@@ -55,7 +66,9 @@ import (
 	uaa "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager/xsuaa"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/subsystems"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/suspension"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -80,6 +93,37 @@ type Config struct {
 	// running in a separate testing deployment but with the production DB.
 	DisableProcessOperationsInProgress bool `envconfig:"default=false"`
 
+	// FailedProvisioningCleanup configures removing the resources left by failed provisioning operations on start,
+	// it is skipped together with the processing of operations in progress
+	FailedProvisioningCleanup process.FailedProvisioningCleanupConfig
+
+	// StartupProcessing defers the processing of operations in progress on start until the delay passed and the dependencies are ready
+	StartupProcessing process.StartupProcessingConfig
+
+	// SandboxedProvisioningSteps makes every provisioning step store its operation updates at once when the step finishes,
+	// so the operation is not left partially updated when the processing stops in the middle of the step
+	SandboxedProvisioningSteps bool `envconfig:"default=false"`
+
+	// RuntimeOverrides restricts the secrets from which the overrides of the runtimes are read
+	RuntimeOverrides runtimeoverrides.Config
+
+	// ProvisioningConcurrency limits the number of provisioning operations of a plan processed at the same time
+	ProvisioningConcurrency provisioning.ConcurrencyConfig
+
+	// StepConcurrency limits the number of operations executing a step at the same time
+	StepConcurrency process.StepConcurrencyConfig
+
+	// InstanceLock makes the operations changing the same instance processed one at a time
+	InstanceLock process.InstanceLockConfig
+
+	// IdleTrials configures the suspension of the trial runtimes without activity, the idle runtimes are checked
+	// only after the operations in progress are processed on start
+	IdleTrials suspension.IdleTrialsConfig
+
+	// TolerateRuntimeNotFoundOnDeprovisioning treats the runtime which does not exist in the Provisioner as already removed,
+	// otherwise the deprovisioning is retried until it times out
+	TolerateRuntimeNotFoundOnDeprovisioning bool `envconfig:"default=true"`
+
 	// DevelopmentMode if set to true then errors are returned in http
 	// responses, otherwise errors are only logged and generic message
 	// is returned to client.
@@ -87,7 +131,8 @@ type Config struct {
 	DevelopmentMode bool `envconfig:"default=false"`
 
 	// DumpProvisionerRequests enables dumping Provisioner requests. Must be disabled on Production environments
-	// because some data must not be visible in the log file.
+	// because some data must not be visible in the log file. The requests with the secrets redacted are stored on the operations
+	// and exposed by the admin endpoint.
 	DumpProvisionerRequests bool `envconfig:"default=false"`
 
 	// OperationTimeout is used to check on a top-level if any operation didn't exceed the time for processing.
@@ -98,6 +143,15 @@ type Config struct {
 	Port       string `envconfig:"default=8080"`
 	StatusPort string `envconfig:"default=8071"`
 
+	// FairProvisioningQueue makes the provisioning queue dispatch the operations round-robin across the subaccounts instead of in FIFO order
+	FairProvisioningQueue bool `envconfig:"default=false"`
+
+	// EventReplayEnabled exposes the /admin/events/replay endpoint which republishes the events of a stored operation
+	EventReplayEnabled bool `envconfig:"default=false"`
+
+	// TLS configures HTTPS and the TLS policy of the public and status servers
+	TLS httputil.TLSConfig
+
 	Provisioning input.Config
 	Director     director.Config
 	Database     storage.Config
@@ -111,14 +165,72 @@ type Config struct {
 	DefaultRequestRegion                 string `envconfig:"default=cf-eu10"`
 	UpdateProcessingEnabled              bool   `envconfig:"default=false"`
 
+	// RuntimeInfoShootTimeout limits the time of fetching a single shoot for the conditions returned by /info/runtimes
+	RuntimeInfoShootTimeout time.Duration `envconfig:"default=5s"`
+
+	// FeatureFlags configures the ConfigMap with flags which can be toggled without a restart,
+	// the values of UpdateProcessingEnabled, EnableOnDemandVersion and EDP.Disabled are the defaults
+	FeatureFlags featureflags.Config
+
+	// PlatformRegions lists the regions accepted in the request path, if empty any region is accepted
+	PlatformRegions []string `envconfig:"optional"`
+
 	Broker          broker.Config
 	CatalogFilePath string
+	// ProvisioningPresetsFilePath defines a path to the file with named sets of provisioning parameters
+	ProvisioningPresetsFilePath string `envconfig:"optional"`
+	// SeedsFilePath defines a path to the file with Gardener seeds which can be requested in each region
+	SeedsFilePath string `envconfig:"optional"`
+	// EncryptionKeyRegionsFilePath defines a path to the file with regions supporting customer-managed encryption keys for each hyperscaler
+	EncryptionKeyRegionsFilePath string `envconfig:"optional"`
+	// MachineTypesFilePath defines a path to the file with the default and allowed machine types for each plan
+	MachineTypesFilePath string `envconfig:"optional"`
+	// MachineImagesFilePath defines a path to the file with the machine images and their versions which can be requested for each hyperscaler
+	MachineImagesFilePath string `envconfig:"optional"`
+	// ResourceQuotasFilePath defines a path to the file with the maximum resource quotas which can be requested for each plan
+	ResourceQuotasFilePath string `envconfig:"optional"`
+	// CatalogMetadataFilePath defines a path to the file with the cost and SLA metadata added to the plans in the catalog
+	CatalogMetadataFilePath string `envconfig:"optional"`
+	// PriceTableFilePath defines a path to the file with the monthly prices per hyperscaler and region used to estimate the cost of the runtimes
+	PriceTableFilePath string `envconfig:"optional"`
+	// CatalogSnapshotsRetention defines the number of the catalog versions kept to return the differences between them
+	CatalogSnapshotsRetention int `envconfig:"default=10"`
 
 	Avs avs.Config
 	LMS lms.Config
 	IAS ias.Config
 	EDP edp.Config
 
+	// Entitlements configures the source of the regions in which each subaccount can provision runtimes
+	Entitlements entitlements.Config
+
+	// Quota configures the source of the runtime quota which each subaccount purchased per plan
+	Quota quota.Config
+
+	// OrchestrationReport configures the export of finished orchestrations reports
+	OrchestrationReport report.Config
+
+	// OrchestrationNotifications configures the delivery of the orchestration notifications to the targets defined in the orchestration request
+	OrchestrationNotifications notification.Config
+
+	// RuntimeResolver configures the listing of the shoots when the targets of the orchestrations are resolved
+	RuntimeResolver orchestrationExt.ResolverConfig
+
+	// Metrics configures the collectors of the operations and instances metrics
+	Metrics metrics.Config
+
+	// DeadLetter configures the sink receiving the context of the permanently failed operations
+	DeadLetter deadletter.Config
+
+	// Tracing configures the export of the OpenCensus traces of the operations to Zipkin, the traces are not exported by default
+	Tracing tracing.Config
+
+	// Maintenance configures the read-only maintenance mode in which the OSB API calls modifying instances and bindings are rejected
+	Maintenance maintenance.Config
+
+	// Dependencies configures the readiness checks of the dependencies, the provisioning queue is paused while the Provisioner is unhealthy
+	Dependencies health.DependencyConfig
+
 	// Service Manager services
 	XSUAA struct {
 		Disabled bool `envconfig:"default=true"`
@@ -126,11 +238,18 @@ type Config struct {
 	Ems struct {
 		Disabled                              bool `envconfig:"default=true"`
 		SkipDeprovisionAzureEventingAtUpgrade bool `envconfig:"default=false"`
+		// OfferingsFilePath defines a path to the file with the EMS offering and plan names in each platform region
+		OfferingsFilePath string `envconfig:"optional"`
 	}
 	Cls struct {
 		Disabled bool `envconfig:"default=true"`
 	}
 
+	ExternalWebhooks struct {
+		// ConfigFilePath defines a path to the file with the external webhook steps run during provisioning
+		ConfigFilePath string `envconfig:"optional"`
+	}
+
 	AuditLog auditlog.Config
 
 	VersionConfig struct {
@@ -142,6 +261,7 @@ type Config struct {
 	MaxPaginationPage          int `envconfig:"default=100"`
 
 	LogLevel string `envconfig:"default=info"`
+	Logging  logging.Config
 }
 
 func main() {
@@ -161,14 +281,23 @@ func main() {
 	logger.Info("Starting Kyma Environment Broker")
 
 	logs := logrus.New()
-	logs.SetFormatter(&logrus.JSONFormatter{})
+	logsFormatter, err := logging.NewFormatter(cfg.Logging)
+	fatalOnError(err)
+	logs.SetFormatter(logsFormatter)
 	if cfg.LogLevel != "" {
 		l, _ := logrus.ParseLevel(cfg.LogLevel)
 		logs.SetLevel(l)
 	}
 
+	fatalOnError(cfg.TLS.Validate())
+
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	fatalOnError(err)
+	defer shutdownTracing()
+
 	logger.Info("Registering healthz endpoint for health probes")
-	health.NewServer(cfg.Host, cfg.StatusPort, logs).ServeAsync()
+	healthServer := health.NewServer(cfg.Host, cfg.StatusPort, cfg.TLS, logs)
+	healthServer.ServeAsync()
 
 	// create provisioner client
 	provisionerClient := provisioner.NewProvisionerClient(cfg.Provisioning.URL, cfg.DumpProvisionerRequests)
@@ -179,6 +308,14 @@ func main() {
 	cli, err := initClient(k8sCfg)
 	fatalOnError(err)
 
+	// create feature flags provider
+	featureFlags := featureflags.NewConfigMapProvider(cli, cfg.FeatureFlags, map[string]bool{
+		featureflags.UpdateProcessing: cfg.UpdateProcessingEnabled,
+		featureflags.OnDemandVersion:  cfg.EnableOnDemandVersion,
+		featureflags.EDP:              !cfg.EDP.Disabled,
+	}, logs.WithField("service", "featureFlags"))
+	go featureFlags.Run(ctx)
+
 	// create director client
 	directorClient := director.NewDirectorClient(ctx, cfg.Director, logs.WithField("service", "directorClient"))
 
@@ -225,6 +362,8 @@ func main() {
 		components.Tracing: runtime.NewGenericComponentDisabler(components.Tracing),
 	}
 	optComponentsSvc := runtime.NewOptionalComponentsService(optionalComponentsDisablers)
+	optComponentsSvc.AddToggleableComponents(cfg.Broker.ToggleableComponents...)
+	fatalOnError(optComponentsSvc.SetComponentDependencies(cfg.Broker.ComponentDependencies))
 
 	disabledComponentsProvider := runtime.NewDisabledComponentsProvider()
 
@@ -251,6 +390,7 @@ func main() {
 
 	edpClient := edp.NewClient(cfg.EDP, logs.WithField("service", "edpClient"))
 
+	fatalOnError(cfg.Avs.Validate())
 	avsClient, err := avs.NewClient(ctx, cfg.Avs, logs)
 	fatalOnError(err)
 	avsDel := avs.NewDelegator(avsClient, cfg.Avs, db.Operations())
@@ -277,10 +417,48 @@ func main() {
 	eventBroker := event.NewPubSub(logs)
 
 	// metrics collectors
-	metrics.RegisterAll(eventBroker, db.Operations(), db.Instances())
+	metrics.RegisterAll(ctx, eventBroker, cfg.Metrics, db.Operations(), db.Instances(), db.Instances(), logs.WithField("service", "metrics"))
+
+	deadLetterSink, err := deadletter.NewSink(cfg.DeadLetter)
+	fatalOnError(err)
+	if deadLetterSink != nil {
+		deadletter.NewReporter(deadLetterSink, cfg.DeadLetter.Retries, cfg.DeadLetter.RetryInterval, logs).Subscribe(eventBroker)
+	}
+
+	maintenanceMode := maintenance.NewMode(cfg.Maintenance, logs)
+
+	// LMS certificates renewal tracking
+	if cfg.LMS.CertExpiryCheckInterval > 0 {
+		lmsCertReconciler := process.NewLMSCertificateExpiryReconciler(db.Instances(), db.Operations(), eventBroker, cfg.LMS.CertExpiryWindow, logs)
+		lmsCertReconciler.Run(ctx, cfg.LMS.CertExpiryCheckInterval)
+	}
+
+	// AVS evaluations deferred during the provisioning
+	if cfg.Avs.BestEffort {
+		deferredEvalReconciler := avs.NewDeferredEvaluationsReconciler(avsDel, db.Instances(), db.Operations(), internalEvalAssistant, externalEvalAssistant, logs)
+		deferredEvalReconciler.Run(ctx, cfg.Avs.DeferredReconcileInterval)
+	}
+
+	// orchestration reports
+	if !cfg.OrchestrationReport.Disabled {
+		reportExporter := report.NewExporter(db.Operations(), report.NewHTTPSink(cfg.OrchestrationReport), cfg.OrchestrationReport, logs.WithField("service", "orchestrationReport"))
+		eventBroker.Subscribe(process.OrchestrationFinished{}, reportExporter.OnOrchestrationFinished)
+	}
+
+	// orchestration notifications
+	if !cfg.OrchestrationNotifications.Disabled {
+		senders := map[orchestrationExt.NotificationTargetType]notification.Sender{
+			orchestrationExt.WebhookNotification: notification.NewWebhookSender(cfg.OrchestrationNotifications),
+		}
+		if cfg.OrchestrationNotifications.SMTPAddress != "" {
+			senders[orchestrationExt.EmailNotification] = notification.NewEmailSender(cfg.OrchestrationNotifications)
+		}
+		notification.NewNotifier(senders, logs.WithField("service", "orchestrationNotifications")).Subscribe(eventBroker)
+	}
 
 	//setup runtime overrides appender
-	runtimeOverrides := runtimeoverrides.NewRuntimeOverrides(ctx, cli)
+	runtimeOverrides, err := runtimeoverrides.NewRuntimeOverridesWithConfig(ctx, cli, cfg.RuntimeOverrides, logs.WithField("service", "runtimeOverrides"))
+	fatalOnError(err)
 
 	serviceManagerClientFactory := servicemanager.NewClientFactory(cfg.ServiceManager)
 
@@ -290,16 +468,84 @@ func main() {
 
 	// run queues
 	const workersAmount = 5
-	provisionManager := provisioning.NewManager(db.Operations(), eventBroker, logs.WithField("provisioning", "manager"))
-	provisionQueue := NewProvisioningProcessingQueue(ctx, provisionManager, workersAmount, &cfg, db, provisionerClient, directorClient, inputFactory,
+	stepLimiter, err := process.NewStepLimiter(cfg.StepConcurrency)
+	fatalOnError(err)
+	var instanceLocks *process.InstanceLocks
+	if cfg.InstanceLock.Enabled {
+		instanceLocks = process.NewInstanceLocks(cfg.InstanceLock)
+	}
+	provisioningLogs := logs
+	if cfg.Logging.OperationStream.Destination != "" {
+		sink, err := logging.NewOperationSink(cfg.Logging.OperationStream.Destination)
+		fatalOnError(err)
+		streamer := logging.NewOperationStreamer(cfg.Logging.OperationStream, sink, logs)
+		streamer.Run(ctx)
+		eventBroker.Subscribe(process.ProvisioningStepProcessed{}, func(ctx context.Context, ev interface{}) error {
+			if operation := ev.(process.ProvisioningStepProcessed).Operation; operation.IsFinished() {
+				streamer.Finish(operation.ID)
+			}
+			return nil
+		})
+		provisioningLogs = streamer.Logger(logs)
+	}
+	provisionManager := provisioning.NewManager(db.Operations(), eventBroker, provisioningLogs.WithField("provisioning", "manager"))
+	provisionManager.SetStepLimiter(stepLimiter)
+	provisioningDB := db
+	if cfg.SandboxedProvisioningSteps {
+		sandbox := process.NewSandboxedOperations(db.Operations())
+		provisionManager.SetSandbox(sandbox)
+		provisioningDB = sandbox.Storage(db)
+	}
+	provisioningLimiter, err := provisioning.NewConcurrencyLimiter(cfg.ProvisioningConcurrency)
+	fatalOnError(err)
+	provisionManager.SetConcurrencyLimiter(provisioningLimiter)
+	prometheus.MustRegister(metrics.NewProvisioningInFlightCollector(provisioningLimiter))
+	provisionQueue := NewProvisioningProcessingQueue(ctx, provisionManager, workersAmount, &cfg, provisioningDB, provisionerClient, directorClient, inputFactory,
 		avsDel, internalEvalAssistant, externalEvalCreator, internalEvalUpdater, runtimeVerConfigurator,
 		runtimeOverrides, serviceManagerClientFactory, bundleBuilder, iasTypeSetter, lmsClient, lmsTenantManager,
-		edpClient, accountProvider, clsConfig, clsClient, clsProvisioner, fileSystem, logs)
+		edpClient, featureFlags, accountProvider, clsConfig, clsClient, clsProvisioner, fileSystem, instanceLocks, logs)
+	startupProcessing := process.NewStartupProcessing(cfg.StartupProcessing, logs)
+	if cfg.Dependencies.ProvisionerURL != "" {
+		provisionerHealth := health.NewDependencyChecker("provisioner", cfg.Dependencies.ProvisionerURL, cfg.Dependencies.FailureThreshold, logs)
+		provisionerHealth.Run(ctx.Done(), cfg.Dependencies.CheckInterval)
+		provisionQueue.PauseWhen(func() bool { return !provisionerHealth.Healthy() })
+		startupProcessing.WaitFor(provisionerHealth.Ready)
+		healthServer.AddStatus(provisionerHealth.Name(), provisionerHealth.Status)
+	}
+	subsystemURLs := cfg.Dependencies.EnabledSubsystems(health.DisabledSubsystems{
+		Cls: cfg.Cls.Disabled,
+		Ems: cfg.Ems.Disabled,
+		IAS: cfg.IAS.Disabled,
+	})
+	for name, url := range subsystemURLs {
+		subsystemHealth := health.NewDependencyChecker(name, url, cfg.Dependencies.FailureThreshold, logs)
+		subsystemHealth.Run(ctx.Done(), cfg.Dependencies.CheckInterval)
+		healthServer.AddReadinessCheck(subsystemHealth.Name(), subsystemHealth.Ready)
+		healthServer.AddStatus(subsystemHealth.Name(), subsystemHealth.Status)
+	}
+	prometheus.MustRegister(metrics.NewQueuePausedGauge("provisioning", provisionQueue))
+	healthServer.AddStatus("provisioningQueue", func() interface{} {
+		return map[string]interface{}{"paused": provisionQueue.Paused(), "queued": provisionQueue.Snapshot()}
+	})
 
 	deprovisionManager := deprovisioning.NewManager(db.Operations(), eventBroker, logs.WithField("deprovisioning", "manager"))
-	deprovisionQueue := NewDeprovisioningProcessingQueue(ctx, workersAmount, deprovisionManager, &cfg, db, eventBroker, provisionerClient, avsDel, internalEvalAssistant, externalEvalAssistant, serviceManagerClientFactory, bundleBuilder, edpClient, accountProvider, clsConfig, clsClient, logs)
+	deprovisionManager.SetStepLimiter(stepLimiter)
+	deprovisionQueue := NewDeprovisioningProcessingQueue(ctx, workersAmount, deprovisionManager, &cfg, db, eventBroker, provisionerClient, avsDel, internalEvalAssistant, externalEvalAssistant, serviceManagerClientFactory, bundleBuilder, edpClient, featureFlags, accountProvider, clsConfig, clsClient, instanceLocks, logs)
+
+	bindingManager := binding.NewManager(db.Bindings(), binding.NewEmsCredentialsProvider(db.Operations(), cfg.Database.SecretKey), logs)
+	bindingQueue := process.NewQueue(bindingManager, logs)
+	bindingQueue.StoreWorkItems("binding", db.WorkItems())
+	bindingQueue.Run(ctx.Done(), workersAmount)
 
 	suspensionCtxHandler := suspension.NewContextUpdateHandler(db.Operations(), provisionQueue, deprovisionQueue, logs)
+	var idleTrialsReconciler *suspension.IdleTrialsReconciler
+	if cfg.IdleTrials.Enabled {
+		activitySource, err := suspension.NewActivitySource(cfg.IdleTrials)
+		fatalOnError(err)
+		idleTrialsReconciler = suspension.NewIdleTrialsReconciler(db.Instances(), suspensionCtxHandler, activitySource, cfg.IdleTrials.IdlePeriod, logs)
+	}
+	overridesUpdateQueue := NewOverridesUpdateProcessingQueue(ctx, workersAmount, db, runtimeOverrides, provisionerClient, eventBroker,
+		inputFactory, runtimeVerConfigurator, upgradeEvalManager, serviceManagerClientFactory, stepLimiter, instanceLocks, logs)
 
 	servicesConfig, err := broker.NewServicesConfigFromFile(cfg.CatalogFilePath)
 	fatalOnError(err)
@@ -310,79 +556,199 @@ func main() {
 	plansValidator, err := broker.NewPlansSchemaValidator(defaultPlansConfig)
 	fatalOnError(err)
 
+	provisioningPresets, err := broker.NewProvisioningPresetsFromFile(cfg.ProvisioningPresetsFilePath)
+	fatalOnError(err)
+
+	allowedSeeds, err := broker.NewAllowedSeedsFromFile(cfg.SeedsFilePath)
+	fatalOnError(err)
+
+	encryptionKeyRegions, err := broker.NewEncryptionKeyRegionsFromFile(cfg.EncryptionKeyRegionsFilePath)
+	fatalOnError(err)
+
+	planMachineTypes, err := broker.NewPlanMachineTypesFromFile(cfg.MachineTypesFilePath)
+	fatalOnError(err)
+
+	planResourceQuotas, err := broker.NewPlanResourceQuotasFromFile(cfg.ResourceQuotasFilePath)
+	fatalOnError(err)
+
+	machineImages, err := broker.NewMachineImagesFromFile(cfg.MachineImagesFilePath)
+	fatalOnError(err)
+	parametersValidators := broker.NewDefaultParametersValidators()
+	machineImages.Register(parametersValidators)
+	broker.KubernetesConfigAllowlist{
+		KubeletFeatureGates:   cfg.Broker.KubeletFeatureGates,
+		APIServerFeatureGates: cfg.Broker.APIServerFeatureGates,
+	}.Register(parametersValidators)
+
+	plansCatalogMetadata, err := broker.NewPlansCatalogMetadataFromFile(cfg.CatalogMetadataFilePath)
+	fatalOnError(err)
+
+	rateLimitOverrides, err := broker.NewRateLimitOverridesFromFile(cfg.Broker.ProvisionRateLimit.OverridesFilePath)
+	fatalOnError(err)
+	provisionRateLimiter := broker.NewSubaccountRateLimiter(broker.RateLimit{
+		Interval: cfg.Broker.ProvisionRateLimit.Interval,
+		Burst:    cfg.Broker.ProvisionRateLimit.Burst,
+	}, rateLimitOverrides)
+
+	instanceNameTemplate, err := broker.NewInstanceNameTemplate(cfg.Broker.InstanceNameTemplate)
+	fatalOnError(err)
+
+	lastOperationEndpoint := broker.NewLastOperation(db.Operations(), db.Instances(), logs)
+	operationCache := broker.NewOperationCache(cfg.Broker.OperationCache)
+	operationCache.Subscribe(eventBroker)
+	lastOperationEndpoint.SetOperationCache(operationCache)
+
 	// create KymaEnvironmentBroker endpoints
+	provisionEndpoint := broker.NewProvision(cfg.Broker, cfg.Gardener, db.Operations(), db.Instances(), provisionQueue, inputFactory, plansValidator, defaultPlansConfig,
+		broker.ProvisionOptions{
+			Presets:              provisioningPresets,
+			AllowedSeeds:         allowedSeeds,
+			EncryptionKeyRegions: encryptionKeyRegions,
+			MachineTypes:         planMachineTypes,
+			ResourceQuotas:       planResourceQuotas,
+			ParametersValidators: parametersValidators,
+			RateLimiter:          provisionRateLimiter,
+			NameTemplate:         instanceNameTemplate,
+			FeatureFlags:         featureFlags,
+		}, logs)
+	provisionEndpoint.SetComponentTogglesValidator(optComponentsSvc)
+	deprovisionEndpoint := broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs)
+	updateEndpoint := broker.NewUpdate(db.Instances(), db.Operations(), suspensionCtxHandler, featureFlags, overridesUpdateQueue, logs)
+	if instanceLocks != nil && cfg.InstanceLock.RejectConflicts {
+		deprovisionEndpoint.SetInstanceLocks(instanceLocks)
+		updateEndpoint.SetInstanceLocks(instanceLocks)
+	}
+	getInstanceEndpoint := broker.NewGetInstance(db.Instances(), logs)
+	if cfg.IdleTrials.Enabled {
+		// the platform does not know about the idle suspension, so the next call for the instance resumes it
+		updateEndpoint.SetInstanceResumer(suspensionCtxHandler)
+		getInstanceEndpoint.SetInstanceResumer(suspensionCtxHandler)
+	}
 	kymaEnvBroker := &broker.KymaEnvironmentBroker{
-		broker.NewServices(cfg.Broker, servicesConfig, logs),
-		broker.NewProvision(cfg.Broker, cfg.Gardener, db.Operations(), db.Instances(), provisionQueue, inputFactory, plansValidator, defaultPlansConfig, cfg.EnableOnDemandVersion, logs),
-		broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs),
-		broker.NewUpdate(db.Instances(), db.Operations(), suspensionCtxHandler, cfg.UpdateProcessingEnabled, logs),
-		broker.NewGetInstance(db.Instances(), logs),
-		broker.NewLastOperation(db.Operations(), db.Instances(), logs),
-		broker.NewBind(logs),
-		broker.NewUnbind(logs),
-		broker.NewGetBinding(logs),
-		broker.NewLastBindingOperation(logs),
+		broker.NewServices(cfg.Broker, servicesConfig, plansCatalogMetadata, logs),
+		provisionEndpoint,
+		deprovisionEndpoint,
+		updateEndpoint,
+		getInstanceEndpoint,
+		lastOperationEndpoint,
+		broker.NewBind(db.Instances(), db.Bindings(), bindingQueue, logs),
+		broker.NewUnbind(db.Bindings(), bindingQueue, logs),
+		broker.NewGetBinding(db.Bindings(), logs),
+		broker.NewLastBindingOperation(db.Bindings(), logs),
 	}
 
+	// save the served catalog, so the clients can get the changes since the catalog version they cached
+	catalogServices, err := kymaEnvBroker.Services(ctx)
+	fatalOnError(err)
+	_, err = catalog.NewSnapshotter(db.CatalogSnapshots(), cfg.CatalogSnapshotsRetention, logs).Save(catalogServices)
+	fatalOnError(err)
+
 	// create server
 	router := mux.NewRouter()
 
 	// create info endpoints
 	respWriter := httputil.NewResponseWriter(logs, cfg.DevelopmentMode)
-	runtimesInfoHandler := appinfo.NewRuntimeInfoHandler(db.Instances(), defaultPlansConfig, cfg.DefaultRequestRegion, respWriter)
+	runtimesInfoHandler := appinfo.NewRuntimeInfoHandler(db.Instances(), defaultPlansConfig, cfg.DefaultRequestRegion, respWriter, gardenerShoots, cfg.RuntimeInfoShootTimeout)
 	router.Handle("/info/runtimes", runtimesInfoHandler)
+	// the CSV inventory is streamed, so it is not buffered by the ETag and gzip middleware
+	router.HandleFunc("/info/runtimes.csv", runtimesInfoHandler.ServeCSV)
+	router.Handle("/info/subaccounts/{subaccount_id}", middleware.AddETagAndGzip(appinfo.NewSubAccountInfoHandler(db.Instances(), respWriter)))
+	priceTable, err := appinfo.NewPriceTableFromFile(cfg.PriceTableFilePath)
+	fatalOnError(err)
+	router.Handle("/info/cost_estimates", middleware.AddETagAndGzip(appinfo.NewCostEstimateHandler(priceTable, respWriter)))
 
 	// create metrics endpoint
-	router.Handle("/metrics", promhttp.Handler())
+	router.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, metrics.Handler(prometheus.DefaultGatherer)))
 
 	gardenerNamespace := fmt.Sprintf("garden-%s", cfg.Gardener.Project)
 
 	runtimeLister := orchestration.NewRuntimeLister(db.Instances(), db.Operations(), runtime.NewConverter(cfg.DefaultRequestRegion), logs)
-	runtimeResolver := orchestrationExt.NewGardenerRuntimeResolver(gardenerClient, gardenerNamespace, runtimeLister, logs)
+	runtimeResolver := orchestrationExt.NewGardenerRuntimeResolverWithConfig(gardenerClient, gardenerNamespace, runtimeLister, cfg.RuntimeResolver, logs)
 
-	kymaQueue := NewKymaOrchestrationProcessingQueue(ctx, db, runtimeOverrides, provisionerClient, eventBroker, inputFactory, nil, time.Minute, runtimeVerConfigurator, runtimeResolver, upgradeEvalManager,
-		&cfg, accountProvider, serviceManagerClientFactory, clsConfig, fileSystem, logs)
-	clusterQueue := NewClusterOrchestrationProcessingQueue(ctx, db, provisionerClient, eventBroker, inputFactory, nil, time.Minute, runtimeResolver, upgradeEvalManager, logs)
+	kymaQueue := NewKymaOrchestrationProcessingQueue(ctx, db, runtimeOverrides, provisionerClient, eventBroker, inputFactory, nil, time.Minute, runtimeVerConfigurator, runtimeResolver, upgradeEvalManager, avsDel,
+		&cfg, accountProvider, serviceManagerClientFactory, clsConfig, fileSystem, stepLimiter, instanceLocks, logs)
+	clusterQueue := NewClusterOrchestrationProcessingQueue(ctx, db, provisionerClient, eventBroker, inputFactory, nil, time.Minute, runtimeResolver, upgradeEvalManager, accountProvider, stepLimiter, instanceLocks, logs)
 
 	// TODO: in case of cluster upgrade the same Azure Zones must be send to the Provisioner
 	orchestrationHandler := orchestrate.NewOrchestrationHandler(db, kymaQueue, clusterQueue, cfg.MaxPaginationPage, logs)
 
 	if !cfg.DisableProcessOperationsInProgress {
-		err = processOperationsInProgressByType(internal.OperationTypeProvision, db.Operations(), provisionQueue, logs)
-		fatalOnError(err)
-		err = processOperationsInProgressByType(internal.OperationTypeDeprovision, db.Operations(), deprovisionQueue, logs)
-		fatalOnError(err)
-		err = reprocessOrchestrations(orchestrationExt.UpgradeKymaOrchestration, db.Orchestrations(), db.Operations(), kymaQueue, logs)
-		fatalOnError(err)
-		err = reprocessOrchestrations(orchestrationExt.UpgradeClusterOrchestration, db.Orchestrations(), db.Operations(), clusterQueue, logs)
-		fatalOnError(err)
+		startupProcessing.Run(ctx.Done(), func() {
+			err := processWorkItems(provisionQueue, logs)
+			fatalOnError(err)
+			err = processWorkItems(deprovisionQueue, logs)
+			fatalOnError(err)
+			err = processWorkItems(bindingQueue, logs)
+			fatalOnError(err)
+			if cfg.FailedProvisioningCleanup.Enabled {
+				cleaner := process.NewFailedProvisioningCleaner(db.Operations(), db.Instances(), deprovisionQueue, cfg.FailedProvisioningCleanup.Limit, logs)
+				fatalOnError(cleaner.ScheduleCleanup())
+			}
+			err = processOverridesUpdatesInProgress(db.Operations(), overridesUpdateQueue, logs)
+			fatalOnError(err)
+			err = reprocessOrchestrations(orchestrationExt.UpgradeKymaOrchestration, db.Orchestrations(), db.Operations(), kymaQueue, logs)
+			fatalOnError(err)
+			err = reprocessOrchestrations(orchestrationExt.UpgradeClusterOrchestration, db.Orchestrations(), db.Operations(), clusterQueue, logs)
+			fatalOnError(err)
+		})
 	} else {
 		logger.Info("Skipping processing operation in progress on start")
 	}
+	if idleTrialsReconciler != nil {
+		// the idle trials are checked after the startup wait even if the operations in progress are not reprocessed
+		startupProcessing.Run(ctx.Done(), func() {
+			idleTrialsReconciler.Run(ctx, cfg.IdleTrials.CheckInterval)
+		})
+	}
 
 	// create OSB API endpoints
-	router.Use(middleware.AddRegionToContext(cfg.DefaultRequestRegion))
+	router.Use(middleware.AddRegionToContext(cfg.DefaultRequestRegion, cfg.PlatformRegions...))
+	router.Use(middleware.AddRetryAfterHeader)
+	router.Use(middleware.AddCorrelationIDToContext)
+	router.Use(middleware.AddFetchParametersToContext)
+	router.Use(middleware.AddOperationPriorityToContext)
+	router.Use(middleware.AddVerboseDetails)
 	for _, prefix := range []string{
 		"/oauth/",          // oauth2 handled by Ory
 		"/oauth/{region}/", // oauth2 handled by Ory with region
 	} {
 		route := router.PathPrefix(prefix).Subrouter()
-		broker.AttachRoutes(route, kymaEnvBroker, logger)
+		route.Use(maintenanceMode.RejectMutatingRequests)
+		broker.AttachRoutes(route, kymaEnvBroker, cfg.Broker.MaxParametersSize, logger)
 	}
 
 	// create /orchestration
 	orchestrationHandler.AttachRoutes(router)
 
+	// create admin endpoints
+	if cfg.EventReplayEnabled {
+		router.Handle("/admin/events/replay", replay.NewHandler(db.Operations(), eventBroker, logs))
+	}
+	router.PathPrefix("/admin/orchestrations/").Handler(orchestrate.NewForceCompleteHandler(db.Orchestrations(), db.Operations(), logs))
+	router.PathPrefix("/admin/instances/").Handler(featureflags.NewInstanceFlagsHandler(db.Instances(), db.InstanceFlags(), upgrade_kyma.StepFlags, logs))
+	router.Handle("/admin/maintenance", maintenance.NewHandler(maintenanceMode))
+	router.PathPrefix("/admin/subsystems/").Handler(subsystems.NewMissingInstancesHandler(db.SubsystemMarkers(), []string{internal.SubsystemEDP}, logs))
+	if cfg.DumpProvisionerRequests {
+		router.PathPrefix("/admin/operations/").Handler(provisioner.NewRequestDumpHandler(db.Operations(), logs))
+	}
+
+	// the ETag and gzip middleware buffers the whole response, so it is used only by the bounded JSON GET endpoints
+	bounded := router.NewRoute().Subrouter()
+	bounded.Use(middleware.AddETagAndGzip)
+
 	// create list runtimes endpoint
 	runtimeHandler := runtime.NewHandler(db.Instances(), db.Operations(), cfg.MaxPaginationPage, cfg.DefaultRequestRegion)
-	runtimeHandler.AttachRoutes(router)
+	runtimeHandler.AttachRoutes(bounded)
+
+	// create catalog diff endpoint
+	catalog.NewHandler(db.CatalogSnapshots()).AttachRoutes(bounded)
 
 	router.StrictSlash(true).PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir("/swagger"))))
 	svr := handlers.CustomLoggingHandler(os.Stdout, router, func(writer io.Writer, params handlers.LogFormatterParams) {
 		logs.Infof("Call handled: method=%s url=%s statusCode=%d size=%d", params.Request.Method, params.URL.Path, params.StatusCode, params.Size)
 	})
 
-	fatalOnError(http.ListenAndServe(cfg.Host+":"+cfg.Port, svr))
+	fatalOnError(httputil.ListenAndServe(cfg.Host+":"+cfg.Port, svr, cfg.TLS.Enabled, cfg.TLS))
 }
 
 // TODO: delete this function after all SKR clusters are migrated to 1.20!
@@ -402,15 +768,28 @@ func newAuditLogStep(fileSystem afero.Fs, cfg *Config, ops storage.Operations) p
 	return auditLogStep
 }
 
-// queues all in progress operations by type
-func processOperationsInProgressByType(opType internal.OperationType, op storage.Operations, queue *process.Queue, log logrus.FieldLogger) error {
-	operations, err := op.GetNotFinishedOperationsByType(opType)
+// queues the operations which were in the queue before the restart
+func processWorkItems(queue *process.Queue, log logrus.FieldLogger) error {
+	resumed, err := queue.Recover()
 	if err != nil {
-		return errors.Wrap(err, "while getting in progress operations from storage")
+		return errors.Wrap(err, "while resuming the work items from storage")
+	}
+	log.Infof("Resumed the processing of %d operations", resumed)
+	return nil
+}
+
+// queues the in progress upgrade Kyma operations created by the instance update, the ones created by orchestrations are resumed with the orchestration
+func processOverridesUpdatesInProgress(op storage.Operations, queue *process.Queue, log logrus.FieldLogger) error {
+	operations, err := op.GetNotFinishedOperationsByType(internal.OperationTypeUpgradeKyma)
+	if err != nil {
+		return errors.Wrap(err, "while getting in progress upgrade Kyma operations from storage")
 	}
 	for _, operation := range operations {
+		if operation.OrchestrationID != "" {
+			continue
+		}
 		queue.Add(operation.ID)
-		log.Infof("Resuming the processing of %s operation ID: %s", opType, operation.ID)
+		log.Infof("Resuming the processing of overrides update operation ID: %s", operation.ID)
 	}
 	return nil
 }
@@ -505,6 +884,23 @@ func initClient(cfg *rest.Config) (client.Client, error) {
 	return cli, nil
 }
 
+// lockInstances makes the executor process the operation only when it holds the lock of its instance,
+// the executor is returned as it is when the instance locks are disabled
+func lockInstances(executor process.Executor, operations storage.Operations, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) process.Executor {
+	if instanceLocks == nil {
+		return executor
+	}
+	return process.NewInstanceLockedExecutor(executor, operations, instanceLocks, logs.WithField("service", "instanceLocks"))
+}
+
+// lockOrchestratedInstances is lockInstances for the executors of the operations run by the orchestrations
+func lockOrchestratedInstances(executor orchestrationExt.OperationExecutor, operations storage.Operations, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) orchestrationExt.OperationExecutor {
+	if instanceLocks == nil {
+		return executor
+	}
+	return process.NewInstanceLockedOperationExecutor(executor, operations, instanceLocks, logs.WithField("service", "instanceLocks"))
+}
+
 func fatalOnError(err error) {
 	if err != nil {
 		log.Fatal(err)
@@ -517,20 +913,40 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 	externalEvalCreator *provisioning.ExternalEvalCreator, internalEvalUpdater *provisioning.InternalEvalUpdater,
 	runtimeVerConfigurator *runtimeversion.RuntimeVersionConfigurator, runtimeOverrides provisioning.RuntimeOverridesAppender,
 	smcf provisioning.SMClientFactory, bundleBuilder ias.BundleBuilder, iasTypeSetter *provisioning.IASType,
-	lmsClient lms.Client, lmsTenantManager provisioning.LmsTenantProvider, edpClient provisioning.EDPClient,
+	lmsClient lms.Client, lmsTenantManager provisioning.LmsTenantProvider, edpClient provisioning.EDPClient, featureFlags featureflags.Provider,
 	accountProvider hyperscaler.AccountProvider, clsConfig *cls.Config, clsClient provisioning.ClsBindingProvider,
-	clsProvisioner provisioning.ClsProvisioner, fileSystem afero.Fs, logs logrus.FieldLogger) *process.Queue {
+	clsProvisioner provisioning.ClsProvisioner, fileSystem afero.Fs, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) *process.Queue {
 
 	provisioningInit := provisioning.NewInitialisationStep(db.Operations(), db.Instances(),
 		provisionerClient, directorClient, inputFactory, externalEvalCreator, internalEvalUpdater, iasTypeSetter,
 		cfg.Provisioning.Timeout, cfg.OperationTimeout, runtimeVerConfigurator, smcf)
 	provisionManager.InitStep(provisioningInit)
 
+	emsOfferings, err := provisioning.NewEmsOfferingsFromFile(cfg.Ems.OfferingsFilePath)
+	fatalOnError(err)
+
+	// the entitlements are cached, so the steps which check them do not call the entitlements service one after another
+	entitlementsClient := entitlements.NewCachedClient(entitlements.NewClient(cfg.Entitlements), cfg.Entitlements)
+	// the remaining quota is not cached, the provisionings accepted one after another would see the quota before each of them
+	quotaClient := quota.NewClient(cfg.Quota)
+
 	provisioningSteps := []struct {
 		disabled bool
 		weight   int
 		step     provisioning.Step
 	}{
+		{
+			weight: 1,
+			step:   provisioning.NewCheckCredentialsAvailabilityStep(db.Operations(), accountProvider),
+		},
+		{
+			weight: 1,
+			step:   provisioning.NewEntitledRegionStep(db.Operations(), entitlementsClient, cfg.Entitlements),
+		},
+		{
+			weight: 1,
+			step:   provisioning.NewQuotaStep(db.Operations(), quotaClient, cfg.Quota),
+		},
 		{
 			weight: 1,
 			step: provisioning.NewServiceManagerOfferingStep("XSUAA_Offering",
@@ -541,8 +957,8 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 		},
 		{
 			weight: 1,
-			step: provisioning.NewServiceManagerOfferingStep("EMS_Offering",
-				provisioning.EmsOfferingName, provisioning.EmsPlanName, func(op *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo {
+			step: provisioning.NewRegionalServiceManagerOfferingStep("EMS_Offering",
+				emsOfferings, func(op *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo {
 					return &op.Ems.Instance
 				}, db.Operations()),
 			disabled: cfg.Ems.Disabled,
@@ -584,13 +1000,12 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 		},
 		{
 			weight:   2,
-			step:     provisioning.NewLmsActivationStep(cfg.LMS, provisioning.NewProvideLmsTenantStep(lmsTenantManager, db.Operations(), cfg.LMS.Region, cfg.LMS.Mandatory)),
+			step:     provisioning.NewLmsActivationStep(cfg.LMS, provisioning.NewProvideLmsTenantStep(lmsTenantManager, db.Operations(), cfg.LMS.Region, cfg.LMS.IsMandatory)),
 			disabled: !cfg.Cls.Disabled,
 		},
 		{
-			weight:   2,
-			step:     provisioning.NewEDPRegistrationStep(db.Operations(), edpClient, cfg.EDP),
-			disabled: cfg.EDP.Disabled,
+			weight: 2,
+			step:   provisioning.NewFeatureFlagStep(featureFlags, featureflags.EDP, provisioning.NewEDPRegistrationStep(db.Operations(), db.SubsystemMarkers(), edpClient, cfg.EDP)),
 		},
 		{
 			weight: 3,
@@ -614,7 +1029,7 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 		},
 		{
 			weight:   5,
-			step:     provisioning.NewLmsActivationStep(cfg.LMS, provisioning.NewLmsCertificatesStep(lmsClient, db.Operations(), cfg.LMS.Mandatory)),
+			step:     provisioning.NewLmsActivationStep(cfg.LMS, provisioning.NewLmsCertificatesStep(lmsClient, db.Operations(), cfg.LMS.IsMandatory)),
 			disabled: !cfg.Cls.Disabled,
 		},
 		{
@@ -660,7 +1075,18 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 		}
 	}
 
-	queue := process.NewQueue(provisionManager, logs)
+	externalWebhooks, err := provisioning.NewExternalWebhooksFromFile(cfg.ExternalWebhooks.ConfigFilePath)
+	fatalOnError(err)
+	for _, webhook := range externalWebhooks {
+		provisionManager.AddStep(webhook.Weight, provisioning.NewExternalWebhookStep(db.Operations(), webhook))
+	}
+
+	executor := lockInstances(provisionManager, db.Operations(), instanceLocks, logs)
+	queue := process.NewQueue(executor, logs)
+	if cfg.FairProvisioningQueue {
+		queue = process.NewFairQueue(executor, logs)
+	}
+	queue.StoreWorkItems("provisioning", db.WorkItems())
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
@@ -669,8 +1095,8 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *provi
 func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, deprovisionManager *deprovisioning.Manager, cfg *Config, db storage.BrokerStorage, pub event.Publisher,
 	provisionerClient provisioner.Client, avsDel *avs.Delegator, internalEvalAssistant *avs.InternalEvalAssistant,
 	externalEvalAssistant *avs.ExternalEvalAssistant, smcf *servicemanager.ClientFactory, bundleBuilder ias.BundleBuilder,
-	edpClient deprovisioning.EDPClient, accountProvider hyperscaler.AccountProvider,
-	clsConfig *cls.Config, clsClient cls.InstanceRemover, logs logrus.FieldLogger) *process.Queue {
+	edpClient deprovisioning.EDPClient, featureFlags featureflags.Provider, accountProvider hyperscaler.AccountProvider,
+	clsConfig *cls.Config, clsClient cls.InstanceRemover, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) *process.Queue {
 
 	deprovisioningInit := deprovisioning.NewInitialisationStep(db.Operations(), db.Instances(), provisionerClient, accountProvider, smcf, cfg.OperationTimeout)
 	deprovisionManager.InitStep(deprovisioningInit)
@@ -682,7 +1108,7 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 		step     deprovisioning.Step
 	}{
 		{
-			weight: 1,
+			weight: deprovisioning.AvsEvaluationsRemovalWeight(cfg.Avs.RemoveEvaluationsLast),
 			step:   deprovisioning.NewAvsEvaluationsRemovalStep(avsDel, db.Operations(), externalEvalAssistant, internalEvalAssistant),
 		},
 		{
@@ -692,9 +1118,8 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 					deprovisioning.NewDeprovisionAzureEventHubStep(db.Operations(), azure.NewAzureProvider(), accountProvider, ctx))),
 		},
 		{
-			weight:   1,
-			step:     deprovisioning.NewEDPDeregistrationStep(edpClient, cfg.EDP),
-			disabled: cfg.EDP.Disabled,
+			weight: 1,
+			step:   deprovisioning.NewFeatureFlagStep(featureFlags, featureflags.EDP, deprovisioning.NewEDPDeregistrationStep(db.SubsystemMarkers(), edpClient, cfg.EDP)),
 		},
 		{
 			weight:   1,
@@ -731,9 +1156,13 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 			step:     deprovisioning.NewSkipForTrialPlanStep(deprovisioning.NewClsDeprovisionStep(clsConfig, clsDeprovisioner, db.Operations())),
 			disabled: cfg.Cls.Disabled,
 		},
+		{
+			weight: 3,
+			step:   deprovisioning.NewRevokeCredentialsStep(db.Operations(), db.Instances(), provisionerClient),
+		},
 		{
 			weight: 10,
-			step:   deprovisioning.NewRemoveRuntimeStep(db.Operations(), db.Instances(), provisionerClient),
+			step:   deprovisioning.NewRemoveRuntimeStep(db.Operations(), db.Instances(), provisionerClient, cfg.TolerateRuntimeNotFoundOnDeprovisioning),
 		},
 	}
 	for _, step := range deprovisioningSteps {
@@ -742,7 +1171,8 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 		}
 	}
 
-	queue := process.NewQueue(deprovisionManager, logs)
+	queue := process.NewQueue(lockInstances(deprovisionManager, db.Operations(), instanceLocks, logs), logs)
+	queue.StoreWorkItems("deprovisioning", db.WorkItems())
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
@@ -752,28 +1182,35 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 	runtimeOverrides upgrade_kyma.RuntimeOverridesAppender, provisionerClient provisioner.Client,
 	pub event.Publisher, inputFactory input.CreatorForPlan, icfg *upgrade_kyma.TimeSchedule,
 	pollingInterval time.Duration, runtimeVerConfigurator *runtimeversion.RuntimeVersionConfigurator,
-	runtimeResolver orchestrationExt.RuntimeResolver, upgradeEvalManager *avs.EvaluationManager,
+	runtimeResolver orchestrationExt.RuntimeResolver, upgradeEvalManager *avs.EvaluationManager, avsDel *avs.Delegator,
 	cfg *Config, accountProvider hyperscaler.AccountProvider, smcf *servicemanager.ClientFactory,
-	clsConfig *cls.Config, fileSystem afero.Fs, logs logrus.FieldLogger) *process.Queue {
+	clsConfig *cls.Config, fileSystem afero.Fs, stepLimiter *process.StepLimiter, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) *process.Queue {
 
 	//CLS
 	clsClient := cls.NewClient(clsConfig)
 	clsProvisioner := cls.NewProvisioner(db.CLSInstances(), clsClient)
 
 	upgradeKymaManager := upgrade_kyma.NewManager(db.Operations(), pub, logs.WithField("upgradeKyma", "manager"))
+	upgradeKymaManager.SetStepLimiter(stepLimiter)
 	upgradeKymaInit := upgrade_kyma.NewInitialisationStep(db.Operations(), db.Orchestrations(), db.Instances(),
 		provisionerClient, inputFactory, upgradeEvalManager, icfg, runtimeVerConfigurator, smcf)
 
 	upgradeKymaManager.InitStep(upgradeKymaInit)
+
+	emsOfferings, err := provisioning.NewEmsOfferingsFromFile(cfg.Ems.OfferingsFilePath)
+	fatalOnError(err)
+
 	upgradeKymaSteps := []struct {
 		disabled bool
 		weight   int
 		step     upgrade_kyma.Step
+		// toggle allows to enable or disable the step for the instance by the instance flag
+		toggle *upgrade_kyma.StepToggle
 	}{
 		{
 			weight: 1,
-			step: upgrade_kyma.NewServiceManagerOfferingStep("EMS_Offering",
-				provisioning.EmsOfferingName, provisioning.EmsPlanName, func(op *internal.UpgradeKymaOperation) *internal.ServiceManagerInstanceInfo {
+			step: upgrade_kyma.NewRegionalServiceManagerOfferingStep("EMS_Offering",
+				emsOfferings, func(op *internal.UpgradeKymaOperation) *internal.ServiceManagerInstanceInfo {
 					return &op.Ems.Instance
 				}, db.Operations()),
 			disabled: cfg.Ems.Disabled,
@@ -788,9 +1225,16 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 			step:   upgrade_kyma.NewOverridesFromSecretsAndConfigStep(db.Operations(), runtimeOverrides, runtimeVerConfigurator),
 		},
 		{
-			weight:   3,
-			step:     upgrade_kyma.NewDeprovisionAzureEventHubStep(db.Operations(), azure.NewAzureProvider(), accountProvider, ctx),
-			disabled: cfg.Ems.SkipDeprovisionAzureEventingAtUpgrade,
+			weight: 3,
+			step: upgrade_kyma.NewAvsEvaluationReconcileStep(db.Operations(), db.Instances(), avsDel,
+				avs.NewInternalEvalAssistant(cfg.Avs), avs.NewExternalEvalAssistant(cfg.Avs)),
+			disabled: cfg.Avs.Disabled,
+			toggle:   &upgrade_kyma.StepToggle{Flag: upgrade_kyma.ReconcileAvsEvaluationsFlag, EnabledByDefault: true},
+		},
+		{
+			weight: 3,
+			step:   upgrade_kyma.NewDeprovisionAzureEventHubStep(db.Operations(), azure.NewAzureProvider(), accountProvider, ctx),
+			toggle: &upgrade_kyma.StepToggle{Flag: upgrade_kyma.DeprovisionAzureEventHubsFlag, EnabledByDefault: !cfg.Ems.SkipDeprovisionAzureEventingAtUpgrade},
 		},
 		{
 			weight:   3,
@@ -833,14 +1277,19 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 			step:   upgrade_kyma.NewUpgradeKymaStep(db.Operations(), db.RuntimeStates(), provisionerClient, icfg),
 		},
 	}
+	upgradeKymaManager.SetInstanceFlags(db.InstanceFlags())
 	for _, step := range upgradeKymaSteps {
-		if !step.disabled {
+		switch {
+		case step.disabled:
+		case step.toggle != nil:
+			upgradeKymaManager.AddToggledStep(step.weight, step.step, *step.toggle)
+		default:
 			upgradeKymaManager.AddStep(step.weight, step.step)
 		}
 	}
 
 	orchestrateKymaManager := manager.NewUpgradeKymaManager(db.Orchestrations(), db.Operations(), db.Instances(),
-		upgradeKymaManager, runtimeResolver, pollingInterval, smcf, logs.WithField("upgradeKyma", "orchestration"))
+		lockOrchestratedInstances(upgradeKymaManager, db.Operations(), instanceLocks, logs), runtimeResolver, pollingInterval, smcf, pub, logs.WithField("upgradeKyma", "orchestration"))
 	queue := process.NewQueue(orchestrateKymaManager, logs)
 
 	queue.Run(ctx.Done(), 3)
@@ -848,11 +1297,34 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 	return queue
 }
 
+// NewOverridesUpdateProcessingQueue creates the queue of the instance updates which change only the Kyma overrides.
+// The overrides are computed again and Kyma is reconciled, the cluster is not changed.
+func NewOverridesUpdateProcessingQueue(ctx context.Context, workersAmount int, db storage.BrokerStorage, runtimeOverrides upgrade_kyma.RuntimeOverridesAppender,
+	provisionerClient provisioner.Client, pub event.Publisher, inputFactory input.CreatorForPlan, runtimeVerConfigurator *runtimeversion.RuntimeVersionConfigurator,
+	upgradeEvalManager *avs.EvaluationManager, smcf *servicemanager.ClientFactory, stepLimiter *process.StepLimiter, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) *process.Queue {
+
+	overridesUpdateManager := upgrade_kyma.NewManager(db.Operations(), pub, logs.WithField("overridesUpdate", "manager"))
+	overridesUpdateManager.SetStepLimiter(stepLimiter)
+	overridesUpdateManager.InitStep(upgrade_kyma.NewInitialisationStep(db.Operations(), db.Orchestrations(), db.Instances(),
+		provisionerClient, inputFactory, upgradeEvalManager, nil, runtimeVerConfigurator, smcf))
+
+	overridesUpdateManager.AddStep(1, upgrade_kyma.NewOverridesFromSecretsAndConfigStep(db.Operations(), runtimeOverrides, runtimeVerConfigurator))
+	overridesUpdateManager.AddStep(1, upgrade_kyma.NewServiceManagerOverridesStep(db.Operations()))
+	overridesUpdateManager.AddStep(2, upgrade_kyma.NewUpgradeKymaStep(db.Operations(), db.RuntimeStates(), provisionerClient, nil))
+
+	queue := process.NewQueue(lockInstances(overridesUpdateManager, db.Operations(), instanceLocks, logs), logs)
+	queue.Run(ctx.Done(), workersAmount)
+
+	return queue
+}
+
 func NewClusterOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerStorage, provisionerClient provisioner.Client,
 	pub event.Publisher, inputFactory input.CreatorForPlan, icfg *upgrade_cluster.TimeSchedule, pollingInterval time.Duration,
-	runtimeResolver orchestrationExt.RuntimeResolver, upgradeEvalManager *avs.EvaluationManager, logs logrus.FieldLogger) *process.Queue {
+	runtimeResolver orchestrationExt.RuntimeResolver, upgradeEvalManager *avs.EvaluationManager, accountProvider hyperscaler.AccountProvider,
+	stepLimiter *process.StepLimiter, instanceLocks *process.InstanceLocks, logs logrus.FieldLogger) *process.Queue {
 
 	upgradeClusterManager := upgrade_cluster.NewManager(db.Operations(), pub, logs.WithField("upgradeCluster", "manager"))
+	upgradeClusterManager.SetStepLimiter(stepLimiter)
 	upgradeClusterInit := upgrade_cluster.NewInitialisationStep(db.Operations(), db.Orchestrations(), provisionerClient, inputFactory, upgradeEvalManager, icfg)
 	upgradeClusterManager.InitStep(upgradeClusterInit)
 
@@ -861,6 +1333,14 @@ func NewClusterOrchestrationProcessingQueue(ctx context.Context, db storage.Brok
 		weight   int
 		step     upgrade_cluster.Step
 	}{
+		{
+			weight: 1,
+			step:   upgrade_cluster.NewResolveCredentialsStep(db.Operations(), accountProvider),
+		},
+		{
+			weight: 5,
+			step:   upgrade_cluster.NewDrainWorkerPoolStep(db.Operations()),
+		},
 		{
 			weight: 10,
 			step:   upgrade_cluster.NewUpgradeClusterStep(db.Operations(), db.RuntimeStates(), provisionerClient, icfg),
@@ -873,7 +1353,7 @@ func NewClusterOrchestrationProcessingQueue(ctx context.Context, db storage.Brok
 	}
 
 	orchestrateClusterManager := manager.NewUpgradeClusterManager(db.Orchestrations(), db.Operations(), db.Instances(),
-		upgradeClusterManager, runtimeResolver, pollingInterval, logs.WithField("upgradeCluster", "orchestration"))
+		lockOrchestratedInstances(upgradeClusterManager, db.Operations(), instanceLocks, logs), runtimeResolver, pollingInterval, pub, logs.WithField("upgradeCluster", "orchestration"))
 	queue := process.NewQueue(orchestrateClusterManager, logs)
 
 	queue.Run(ctx.Done(), 3)
@@ -25,6 +25,7 @@ type AccountPool interface {
 	IsSecretBindingUsed(hyperscalerType Type, tenantName string) (bool, error)
 	IsSecretBindingDirty(hyperscalerType Type, tenantName string) (bool, error)
 	IsSecretBindingInternal(hyperscalerType Type, tenantName string) (bool, error)
+	IsSecretBindingAvailable(hyperscalerType Type, tenantName string) (bool, error)
 }
 
 func NewAccountPool(secretBindingsClient gardener_apis.SecretBindingInterface, shootsClient gardener_apis.ShootInterface) AccountPool {
@@ -106,6 +107,24 @@ func (p *secretBindingsAccountPool) IsSecretBindingUsed(hyperscalerType Type, te
 	return false, nil
 }
 
+// IsSecretBindingAvailable checks if CredentialsSecretBinding can provide a secret binding for the tenant, either the one already
+// assigned to the tenant or an unassigned one. The unassigned secret binding is not claimed, another tenant can take it in the meantime.
+func (p *secretBindingsAccountPool) IsSecretBindingAvailable(hyperscalerType Type, tenantName string) (bool, error) {
+	for _, labelSelector := range []string{
+		fmt.Sprintf("tenantName=%s,hyperscalerType=%s", tenantName, hyperscalerType),
+		fmt.Sprintf("shared!=true, !tenantName, !dirty, hyperscalerType=%s", hyperscalerType),
+	} {
+		secretBinding, err := p.getSecretBinding(labelSelector)
+		if err != nil {
+			return false, errors.Wrapf(err, "looking for a secret binding available for the tenant %s and hyperscaler %s", tenantName, hyperscalerType)
+		}
+		if secretBinding != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (p *secretBindingsAccountPool) CredentialsSecretBinding(hyperscalerType Type, tenantName string) (*v1beta1.SecretBinding, error) {
 	labelSelector := fmt.Sprintf("tenantName=%s,hyperscalerType=%s", tenantName, hyperscalerType)
 	secretBinding, err := p.getSecretBinding(labelSelector)
@@ -74,6 +74,36 @@ func TestCredentialsSecretBinding(t *testing.T) {
 	}
 }
 
+func TestSecretsAccountPool_IsSecretBindingAvailable(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tenantName      string
+		hyperscalerType Type
+		expected        bool
+		assigned        bool
+	}{
+		"secret binding assigned to the tenant": {"tenant1", Azure, true, true},
+		"unassigned secret binding":             {"tenant3", GCP, true, false},
+		"no unassigned secret binding":          {"tenant5", Azure, false, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			pool := newTestAccountPool()
+
+			// when
+			available, err := pool.IsSecretBindingAvailable(tc.hyperscalerType, tc.tenantName)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, available)
+
+			// the unassigned secret binding is not claimed
+			assigned, err := pool.(*secretBindingsAccountPool).getSecretBinding("tenantName=" + tc.tenantName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.assigned, assigned != nil)
+		})
+	}
+}
+
 func TestSecretsAccountPool_IsSecretBindingInternal(t *testing.T) {
 	t.Run("should return true if internal secret binding found", func(t *testing.T) {
 		//given
@@ -13,6 +13,7 @@ type AccountProvider interface {
 	GardenerCredentials(hyperscalerType Type, tenantName string) (Credentials, error)
 	GardenerSharedCredentials(hyperscalerType Type) (Credentials, error)
 	MarkUnusedGardenerSecretBindingAsDirty(hyperscalerType Type, tenantName string) error
+	GardenerCredentialsAvailable(hyperscalerType Type, tenantName string) (bool, error)
 }
 
 type Credentials struct {
@@ -79,6 +80,16 @@ func (p *accountProvider) GardenerSharedCredentials(hyperscalerType Type) (Crede
 	return p.credentialsFromBoundSecret(secretBinding, hyperscalerType)
 }
 
+// GardenerCredentialsAvailable checks if GardenerCredentials can provide the credentials for the tenant without claiming them
+func (p *accountProvider) GardenerCredentialsAvailable(hyperscalerType Type, tenantName string) (bool, error) {
+	if p.gardenerPool == nil {
+		return false,
+			errors.New("failed to check Gardener Credentials. Gardener Account pool is not configured")
+	}
+
+	return p.gardenerPool.IsSecretBindingAvailable(hyperscalerType, tenantName)
+}
+
 func (p *accountProvider) MarkUnusedGardenerSecretBindingAsDirty(hyperscalerType Type, tenantName string) error {
 	if p.gardenerPool == nil {
 		return errors.New("failed to release subscription for tenant. Gardener Account pool is not configured")
@@ -33,6 +33,27 @@ func (_m *AccountProvider) GardenerCredentials(hyperscalerType hyperscaler.Type,
 	return r0, r1
 }
 
+// GardenerCredentialsAvailable provides a mock function with given fields: hyperscalerType, tenantName
+func (_m *AccountProvider) GardenerCredentialsAvailable(hyperscalerType hyperscaler.Type, tenantName string) (bool, error) {
+	ret := _m.Called(hyperscalerType, tenantName)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(hyperscaler.Type, string) bool); ok {
+		r0 = rf(hyperscalerType, tenantName)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(hyperscaler.Type, string) error); ok {
+		r1 = rf(hyperscalerType, tenantName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GardenerSharedCredentials provides a mock function with given fields: hyperscalerType
 func (_m *AccountProvider) GardenerSharedCredentials(hyperscalerType hyperscaler.Type) (hyperscaler.Credentials, error) {
 	ret := _m.Called(hyperscalerType)
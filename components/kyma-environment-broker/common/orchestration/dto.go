@@ -3,7 +3,9 @@ package orchestration
 import (
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/pkg/errors"
 )
 
 // Parameters hold the attributes of orchestration create (upgrade) requests.
@@ -11,8 +13,16 @@ type Parameters struct {
 	Targets  TargetSpec   `json:"targets"`
 	Strategy StrategySpec `json:"strategy,omitempty"`
 	DryRun   bool         `json:"dryRun,omitempty"`
+	// Notifications lists the targets which are notified when the orchestration starts, progresses and finishes
+	Notifications []NotificationTarget `json:"notifications,omitempty"`
+	// RetryOf is the ID of the orchestration whose failed runtimes are targeted by this orchestration
+	RetryOf string `json:"retryOf,omitempty"`
+	// Retry defines how many times the failed operation of a runtime is run again within this orchestration
+	Retry RetryPolicy `json:"retry,omitempty"`
 	// upgrade kyma specific parameters
 	Kyma KymaParameters `json:""`
+	// upgrade cluster specific parameters
+	Cluster ClusterParameters `json:"cluster,omitempty"`
 }
 
 // KymaParameters hold the attributes of kyma upgrade specific orchestration create requests.
@@ -20,6 +30,34 @@ type KymaParameters struct {
 	Version string `json:"kymaVersion,omitempty"`
 }
 
+// ClusterParameters hold the attributes of cluster upgrade specific orchestration create requests.
+type ClusterParameters struct {
+	// Drain rolls the nodes of the worker pool out in a controlled manner, the settings of the runtime are used if nil
+	Drain *DrainParameters `json:"drain,omitempty"`
+}
+
+// DrainParameters define how many nodes of the worker pool are added above the desired number (MaxSurge)
+// and how many nodes can be cordoned and drained at once (MaxUnavailable) during the upgrade
+type DrainParameters struct {
+	MaxSurge       int `json:"maxSurge"`
+	MaxUnavailable int `json:"maxUnavailable"`
+}
+
+// Validate checks if the nodes can be rolled out with the drain settings
+func (p DrainParameters) Validate() error {
+	if p.MaxSurge < 0 {
+		return errors.New("drain.maxSurge must not be negative")
+	}
+	if p.MaxUnavailable < 0 {
+		return errors.New("drain.maxUnavailable must not be negative")
+	}
+	// Gardener cannot roll the nodes out if no node can be added nor drained
+	if p.MaxSurge == 0 && p.MaxUnavailable == 0 {
+		return errors.New("drain.maxSurge and drain.maxUnavailable must not be both zero")
+	}
+	return nil
+}
+
 const (
 	// StateParam parameter used in list orchestrations / operations queries to filter by state
 	StateParam = "state"
@@ -94,6 +132,42 @@ type StrategySpec struct {
 	Type     StrategyType         `json:"type"`
 	Schedule ScheduleType         `json:"schedule,omitempty"`
 	Parallel ParallelStrategySpec `json:"parallel,omitempty"`
+	// QuarantineAfter is the number of the failed operations of the runtime after which the last one is quarantined
+	// and not run again anymore, 0 means the operations are run again as long as the retry policy allows
+	QuarantineAfter int `json:"quarantineAfter,omitempty"`
+}
+
+// RetryPolicy defines the attempts of the whole operation of a runtime within the orchestration, the failed operation
+// is followed by a new operation for the same runtime until it succeeds or the attempts are exhausted
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of operations run for a runtime, 0 and 1 mean the failed operation is not run again
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Delay is the duration, e.g. 10m, after which the operation which failed is run again
+	Delay string `json:"delay,omitempty"`
+}
+
+// DelayDuration returns the parsed delay, the empty delay means the operation is run again at once
+func (p RetryPolicy) DelayDuration() (time.Duration, error) {
+	if p.Delay == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(p.Delay)
+}
+
+type NotificationTargetType string
+
+const (
+	WebhookNotification NotificationTargetType = "webhook"
+	EmailNotification   NotificationTargetType = "email"
+)
+
+// NotificationTarget defines the channel to which the orchestration notifications are sent
+type NotificationTarget struct {
+	Type NotificationTargetType `json:"type"`
+	// URL is the endpoint which receives the notifications with the POST request, required for the webhook type
+	URL string `json:"url,omitempty"`
+	// Email is the address of the notifications recipient, required for the email type
+	Email string `json:"email,omitempty"`
 }
 
 // TargetSpec is the targets part common for all orchestration trigger/status API
@@ -127,12 +201,29 @@ type OperationResponse struct {
 	MaintenanceWindowEnd   time.Time `json:"maintenanceWindowEnd"`
 	State                  string    `json:"state"`
 	Description            string    `json:"description"`
+	CurrentStep            string    `json:"currentStep,omitempty"`
+	Error                  string    `json:"error,omitempty"`
+	Quarantined            bool      `json:"quarantined,omitempty"`
+	Attempt                int       `json:"attempt,omitempty"`
+	RetriedBy              string    `json:"retriedBy,omitempty"`
+
+	FailureReason *runtime.FailureReason `json:"failureReason,omitempty"`
 }
 
 type OperationResponseList struct {
 	Data       []OperationResponse `json:"data"`
 	Count      int                 `json:"count"`
 	TotalCount int                 `json:"totalCount"`
+	Summary    *OperationsSummary  `json:"summary,omitempty"`
+}
+
+// OperationsSummary holds the number of operations in each state for the whole orchestration
+type OperationsSummary struct {
+	Pending    int `json:"pending"`
+	InProgress int `json:"inProgress"`
+	Succeeded  int `json:"succeeded"`
+	Failed     int `json:"failed"`
+	Canceled   int `json:"canceled"`
 }
 
 type OperationDetailResponse struct {
@@ -23,6 +23,12 @@ type RuntimeOperation struct {
 	Runtime `json:""`
 	ID      string `json:"-"`
 	DryRun  bool   `json:"dryRun"`
+	// Quarantined is set when the orchestration stopped running the operation again after its repeated failures
+	Quarantined bool `json:"quarantined,omitempty"`
+	// Attempt is the number of the operation run for the runtime within the orchestration, starting from 1
+	Attempt int `json:"attempt,omitempty"`
+	// RetriedBy is the ID of the operation which runs the failed operation again
+	RetriedBy string `json:"retriedBy,omitempty"`
 }
 
 //go:generate mockery --name=RuntimeResolver --output=automock --outpkg=automock --case=underscore
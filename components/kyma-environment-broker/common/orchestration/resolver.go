@@ -2,6 +2,7 @@ package orchestration
 
 import (
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,10 +22,19 @@ type RuntimeLister interface {
 	ListAllRuntimes() ([]runtime.RuntimeDTO, error)
 }
 
+// ResolverConfig configures the listing of the shoots by the GardenerRuntimeResolver
+type ResolverConfig struct {
+	// ListPageSize limits the number of shoots returned by Gardener in a single request, 0 means all shoots are listed at once
+	ListPageSize int64 `envconfig:"default=500"`
+	// Concurrency is the number of the pages of shoots resolved in parallel
+	Concurrency int `envconfig:"default=4"`
+}
+
 // GardenerRuntimeResolver is the default resolver which implements the RuntimeResolver interface.
 // This resolver uses the Shoot resources on the Gardener cluster to resolve the runtime targets.
 //
-// Naive implementation, listing all the shoots and perfom filtering on the result.
+// The shoots are listed page by page, so the requests to Gardener are still limited by the rate limiter of the client,
+// and the targets are resolved for the listed pages in parallel.
 // The logic could be optimized with k8s client cache using shoot lister / indexer.
 // The implementation is thread safe, i.e. it is safe to call Resolve() from multiple threads concurrently.
 type GardenerRuntimeResolver struct {
@@ -33,6 +43,7 @@ type GardenerRuntimeResolver struct {
 	runtimeLister     RuntimeLister
 	runtimes          map[string]runtime.RuntimeDTO
 	mutex             sync.RWMutex
+	cfg               ResolverConfig
 	logger            logrus.FieldLogger
 }
 
@@ -43,66 +54,142 @@ const (
 	maintenanceWindowFormat = "150405-0700"
 )
 
-// NewGardenerRuntimeResolver constructs a GardenerRuntimeResolver with the mandatory input parameters.
+// NewGardenerRuntimeResolver constructs a GardenerRuntimeResolver with the mandatory input parameters,
+// all the shoots are listed at once and resolved sequentially.
 func NewGardenerRuntimeResolver(gardenerClient gardenerclient.CoreV1beta1Interface, gardenerNamespace string, lister RuntimeLister, logger logrus.FieldLogger) *GardenerRuntimeResolver {
+	return NewGardenerRuntimeResolverWithConfig(gardenerClient, gardenerNamespace, lister, ResolverConfig{Concurrency: 1}, logger)
+}
+
+// NewGardenerRuntimeResolverWithConfig constructs a GardenerRuntimeResolver which lists the shoots in pages and resolves them in parallel.
+func NewGardenerRuntimeResolverWithConfig(gardenerClient gardenerclient.CoreV1beta1Interface, gardenerNamespace string, lister RuntimeLister, cfg ResolverConfig, logger logrus.FieldLogger) *GardenerRuntimeResolver {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
 	return &GardenerRuntimeResolver{
 		gardenerClient:    gardenerClient,
 		gardenerNamespace: gardenerNamespace,
 		runtimeLister:     lister,
 		runtimes:          map[string]runtime.RuntimeDTO{},
+		cfg:               cfg,
 		logger:            logger.WithField("orchestration", "resolver"),
 	}
 }
 
-// Resolve given an input slice of target specs to include and exclude, returns back a list of unique Runtime objects
+// Resolve given an input slice of target specs to include and exclude, returns back a list of unique Runtime objects sorted by the runtime ID
 func (resolver *GardenerRuntimeResolver) Resolve(targets TargetSpec) ([]Runtime, error) {
-	runtimeIncluded := map[string]bool{}
-	runtimeExcluded := map[string]bool{}
-	runtimes := []Runtime{}
-	shoots, err := resolver.getAllShoots()
+	err := resolver.syncRuntimeOperations()
 	if err != nil {
+		return nil, errors.Wrap(err, "while syncing runtimes")
+	}
+
+	var (
+		mutex           sync.Mutex
+		runtimeIncluded = map[string]Runtime{}
+		runtimeExcluded = map[string]bool{}
+		resolveErr      error
+	)
+	pages := make(chan []gardenerapi.Shoot)
+	listErr := make(chan error, 1)
+	go func() {
+		defer close(pages)
+		listErr <- resolver.listShoots(pages)
+	}()
+
+	forEachConcurrently(resolver.cfg.Concurrency, pages, func(shoots []gardenerapi.Shoot) {
+		included, excluded, err := resolver.resolvePage(targets, shoots)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			resolveErr = err
+			return
+		}
+		for _, id := range excluded {
+			runtimeExcluded[id] = true
+		}
+		for _, r := range included {
+			// the shoot can be listed twice when the shoots change between the pages, the result must not depend on the order of the pages
+			if existing, found := runtimeIncluded[r.RuntimeID]; found && existing.ShootName <= r.ShootName {
+				continue
+			}
+			runtimeIncluded[r.RuntimeID] = r
+		}
+	})
+
+	if err := <-listErr; err != nil {
 		return nil, errors.Wrapf(err, "while listing gardener shoots in namespace %s", resolver.gardenerNamespace)
 	}
-	err = resolver.syncRuntimeOperations()
-	if err != nil {
-		return nil, errors.Wrap(err, "while syncing runtimes")
+	if resolveErr != nil {
+		return nil, resolveErr
 	}
 
-	// Assemble IDs of runtimes to exclude
+	runtimes := make([]Runtime, 0, len(runtimeIncluded))
+	for id, r := range runtimeIncluded {
+		if !runtimeExcluded[id] {
+			runtimes = append(runtimes, r)
+		}
+	}
+	sort.Slice(runtimes, func(i, j int) bool {
+		return runtimes[i].RuntimeID < runtimes[j].RuntimeID
+	})
+
+	return runtimes, nil
+}
+
+// listShoots sends the pages of the shoots to the channel until all the shoots are listed
+func (resolver *GardenerRuntimeResolver) listShoots(pages chan<- []gardenerapi.Shoot) error {
+	opts := metav1.ListOptions{Limit: resolver.cfg.ListPageSize}
+	for {
+		shootList, err := resolver.gardenerClient.Shoots(resolver.gardenerNamespace).List(opts)
+		if err != nil {
+			return err
+		}
+		pages <- shootList.Items
+		if shootList.Continue == "" {
+			return nil
+		}
+		opts.Continue = shootList.Continue
+	}
+}
+
+// resolvePage returns the runtimes of the shoots to include and the IDs of the runtimes to exclude
+func (resolver *GardenerRuntimeResolver) resolvePage(targets TargetSpec, shoots []gardenerapi.Shoot) ([]Runtime, []string, error) {
+	var excluded []string
 	for _, rt := range targets.Exclude {
 		runtimesToExclude, err := resolver.resolveRuntimeTarget(rt, shoots)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		for _, r := range runtimesToExclude {
-			runtimeExcluded[r.RuntimeID] = true
+			excluded = append(excluded, r.RuntimeID)
 		}
 	}
 
-	// Include runtimes which are not excluded
+	var included []Runtime
 	for _, rt := range targets.Include {
 		runtimesToAdd, err := resolver.resolveRuntimeTarget(rt, shoots)
 		if err != nil {
-			return nil, err
-		}
-		for _, r := range runtimesToAdd {
-			if !runtimeExcluded[r.RuntimeID] && !runtimeIncluded[r.RuntimeID] {
-				runtimeIncluded[r.RuntimeID] = true
-				runtimes = append(runtimes, r)
-			}
+			return nil, nil, err
 		}
+		included = append(included, runtimesToAdd...)
 	}
 
-	return runtimes, nil
+	return included, excluded, nil
 }
 
-func (resolver *GardenerRuntimeResolver) getAllShoots() ([]gardenerapi.Shoot, error) {
-	shootList, err := resolver.gardenerClient.Shoots(resolver.gardenerNamespace).List(metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+// forEachConcurrently calls the function for every page from the channel, at most concurrency calls are executed at once
+func forEachConcurrently(concurrency int, pages <-chan []gardenerapi.Shoot, fn func([]gardenerapi.Shoot)) {
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pages {
+				fn(page)
+			}
+		}()
 	}
-
-	return shootList.Items, nil
+	wg.Wait()
 }
 
 func (resolver *GardenerRuntimeResolver) syncRuntimeOperations() error {
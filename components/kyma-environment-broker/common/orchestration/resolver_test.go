@@ -3,6 +3,7 @@ package orchestration
 import (
 	"fmt"
 	"io/ioutil"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -247,6 +248,80 @@ func TestResolver_Resolve_StorageFailure(t *testing.T) {
 	assert.Len(t, runtimes, 0)
 }
 
+func TestResolver_Resolve_Paginated(t *testing.T) {
+	// given
+	const shootsCount = 250
+	const pageSize = 20
+	var shoots []gardenerapi.Shoot
+	var runtimes []runtime.RuntimeDTO
+	// the shoots are listed in the reversed order to check the result is sorted
+	for id := shootsCount; id > 0; id-- {
+		shoots = append(shoots, fixShoot(id, globalAccountID1, region1))
+		runtimes = append(runtimes, fixRuntimeDTO(id, globalAccountID1, plan1, runtimeOpState{provision: string(brokerapi.Succeeded)}))
+	}
+	// the shoot changed between the pages is listed twice
+	shoots = append(shoots, shoots[pageSize])
+
+	lister := &RuntimeListerMock{}
+	lister.On("ListAllRuntimes").Return(runtimes, nil)
+	defer lister.AssertExpectations(t)
+	client, listCalls := newPaginatedFakeGardenerClient(shoots, pageSize)
+	resolver := NewGardenerRuntimeResolverWithConfig(client, shootNamespace, lister, ResolverConfig{ListPageSize: pageSize, Concurrency: 4}, newLogDummy())
+
+	// when
+	resolved, err := resolver.Resolve(TargetSpec{
+		Include: []RuntimeTarget{{Target: TargetAll}},
+		Exclude: []RuntimeTarget{{RuntimeID: "runtime-id-7"}, {SubAccount: "^subaccount-id-13$"}},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, (len(shoots)+pageSize-1)/pageSize, *listCalls)
+	require.Len(t, resolved, shootsCount-2)
+	seen := map[string]bool{}
+	for i, r := range resolved {
+		assert.False(t, seen[r.RuntimeID], "runtime %s resolved twice", r.RuntimeID)
+		seen[r.RuntimeID] = true
+		if i > 0 {
+			assert.True(t, resolved[i-1].RuntimeID < r.RuntimeID, "runtimes are not sorted")
+		}
+	}
+	assert.False(t, seen["runtime-id-7"])
+	assert.False(t, seen["runtime-id-13"])
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	// given
+	const concurrency = 3
+	pages := make(chan []gardenerapi.Shoot)
+	go func() {
+		defer close(pages)
+		for id := 0; id < 20; id++ {
+			pages <- []gardenerapi.Shoot{fixShoot(id, globalAccountID1, region1)}
+		}
+	}()
+	var inFlight, maxInFlight, processed int32
+
+	// when
+	forEachConcurrently(concurrency, pages, func([]gardenerapi.Shoot) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&processed, 1)
+	})
+
+	// then
+	assert.Equal(t, int32(20), processed)
+	assert.True(t, maxInFlight <= concurrency, "%d pages were processed at once", maxInFlight)
+	assert.True(t, maxInFlight > 1, "pages were not processed in parallel")
+}
+
 var (
 	shoot1 = fixShoot(1, globalAccountID1, region1)
 	shoot2 = fixShoot(2, globalAccountID1, region2)
@@ -384,6 +459,30 @@ func newFakeGardenerClient() *gardenerclient_fake.FakeCoreV1beta1 {
 	return client
 }
 
+// newPaginatedFakeGardenerClient returns the shoots in pages linked with the continue token, the fake client does not pass the limit to the reactor
+func newPaginatedFakeGardenerClient(shoots []gardenerapi.Shoot, pageSize int) (*gardenerclient_fake.FakeCoreV1beta1, *int) {
+	fake := &k8stesting.Fake{}
+	client := &gardenerclient_fake.FakeCoreV1beta1{
+		Fake: fake,
+	}
+	calls := 0
+	fake.AddReactor("list", "shoots", func(action k8stesting.Action) (bool, k8s.Object, error) {
+		start := calls * pageSize
+		calls++
+		end := start + pageSize
+		sl := &gardenerapi.ShootList{}
+		if end < len(shoots) {
+			sl.Continue = fmt.Sprintf("page-%d", calls)
+		} else {
+			end = len(shoots)
+		}
+		sl.Items = shoots[start:end]
+		return true, sl, nil
+	})
+
+	return client, &calls
+}
+
 func newRuntimeListerMock() *RuntimeListerMock {
 	lister := &RuntimeListerMock{}
 	lister.On("ListAllRuntimes").Maybe().Return(
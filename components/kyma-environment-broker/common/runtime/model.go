@@ -43,6 +43,24 @@ type Operation struct {
 	CreatedAt       time.Time `json:"createdAt"`
 	OperationID     string    `json:"operationID"`
 	OrchestrationID string    `json:"orchestrationID,omitempty"`
+
+	FailureReason *FailureReason    `json:"failureReason,omitempty"`
+	Progress      []ProgressMessage `json:"progress,omitempty"`
+}
+
+// ProgressMessage is the timestamped progress reported while the operation is processed
+type ProgressMessage struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// FailureReason describes why an operation failed and how the failure can be remediated
+type FailureReason struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+	Retryable   bool   `json:"retryable"`
+	Subsystem   string `json:"subsystem,omitempty"`
 }
 
 type RuntimesPage struct {
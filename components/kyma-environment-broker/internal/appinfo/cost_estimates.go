@@ -0,0 +1,152 @@
+package appinfo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// costEstimateHyperscalers maps the plans which can be estimated to the hyperscalers of their prices
+var costEstimateHyperscalers = map[string]string{
+	broker.AzurePlanID:     "azure",
+	broker.AzureLitePlanID: "azure",
+	broker.AWSPlanID:       "aws",
+	broker.GCPPlanID:       "gcp",
+	broker.OpenStackPlanID: "openstack",
+}
+
+// RegionPrices defines the monthly price of the cluster without nodes and the monthly price of a single node in the region
+type RegionPrices struct {
+	Cluster float64 `yaml:"cluster"`
+	Node    float64 `yaml:"node"`
+}
+
+// PriceTable holds the monthly prices per hyperscaler and region which the cost estimates are computed from
+type PriceTable struct {
+	Currency     string                             `yaml:"currency"`
+	Hyperscalers map[string]map[string]RegionPrices `yaml:"hyperscalers"`
+}
+
+// NewPriceTableFromFile reads the price table from the YAML file, empty path means that no estimate is available
+func NewPriceTableFromFile(path string) (PriceTable, error) {
+	if path == "" {
+		return PriceTable{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return PriceTable{}, errors.Wrapf(err, "while reading %s file with price table", path)
+	}
+	var priceTable PriceTable
+	if err := yaml.Unmarshal(yamlFile, &priceTable); err != nil {
+		return PriceTable{}, errors.Wrap(err, "while unmarshaling YAML file with price table")
+	}
+	known := map[string]struct{}{}
+	for _, hyperscaler := range costEstimateHyperscalers {
+		known[hyperscaler] = struct{}{}
+	}
+	for hyperscaler, regions := range priceTable.Hyperscalers {
+		if _, found := known[hyperscaler]; !found {
+			return PriceTable{}, errors.Errorf("prices are configured for unknown hyperscaler %q", hyperscaler)
+		}
+		for region, prices := range regions {
+			if prices.Cluster < 0 || prices.Node < 0 {
+				return PriceTable{}, errors.Errorf("prices of hyperscaler %q in region %q cannot be negative", hyperscaler, region)
+			}
+		}
+	}
+
+	return priceTable, nil
+}
+
+// Estimate computes the monthly cost of the cluster of the plan with the given number of nodes in the region,
+// false is returned when the prices of the plan in the region are not known
+func (t PriceTable) Estimate(planID, region string, nodes int) (CostEstimateDTO, bool) {
+	hyperscaler, found := costEstimateHyperscalers[planID]
+	if !found {
+		return CostEstimateDTO{}, false
+	}
+	prices, found := t.Hyperscalers[hyperscaler][region]
+	if !found {
+		return CostEstimateDTO{}, false
+	}
+
+	nodesCost := prices.Node * float64(nodes)
+	return CostEstimateDTO{
+		Plan:        broker.PlanNamesMapping[planID],
+		Region:      region,
+		Nodes:       nodes,
+		Currency:    t.Currency,
+		ClusterCost: prices.Cluster,
+		NodesCost:   nodesCost,
+		MonthlyCost: prices.Cluster + nodesCost,
+	}, true
+}
+
+// CostEstimateHandler serves the rough monthly cost of the runtime of the plan in the region with the number of nodes
+// given in the plan, region and nodes query parameters
+type CostEstimateHandler struct {
+	priceTable PriceTable
+	respWriter ResponseWriter
+}
+
+func NewCostEstimateHandler(priceTable PriceTable, respWriter ResponseWriter) *CostEstimateHandler {
+	return &CostEstimateHandler{
+		priceTable: priceTable,
+		respWriter: respWriter,
+	}
+}
+
+func (h *CostEstimateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	planName, region := query.Get("plan"), query.Get("region")
+	planID, found := broker.PlanIDsMapping[planName]
+	if !found {
+		h.writeBadRequest(w, r, fmt.Sprintf("unknown plan %q", planName))
+		return
+	}
+	if region == "" {
+		h.writeBadRequest(w, r, "region is required")
+		return
+	}
+	nodes, err := strconv.Atoi(query.Get("nodes"))
+	if err != nil || nodes <= 0 {
+		h.writeBadRequest(w, r, "nodes must be a positive number")
+		return
+	}
+
+	estimate, available := h.priceTable.Estimate(planID, region, nodes)
+	if !available {
+		unavailable := httputil.ErrorDTO{
+			Status:    http.StatusNotFound,
+			RequestID: r.Header.Get("X-Request-Id"),
+			Message:   fmt.Sprintf("estimate unavailable for the plan %s in the region %s", planName, region),
+		}
+		if err := httputil.JSONEncodeWithCode(w, unavailable, http.StatusNotFound); err != nil {
+			h.respWriter.InternalServerError(w, r, err, "while encoding response to JSON")
+		}
+		return
+	}
+
+	if err := httputil.JSONEncode(w, estimate); err != nil {
+		h.respWriter.InternalServerError(w, r, err, "while encoding response to JSON")
+		return
+	}
+}
+
+func (h *CostEstimateHandler) writeBadRequest(w http.ResponseWriter, r *http.Request, message string) {
+	badRequest := httputil.ErrorDTO{
+		Status:    http.StatusBadRequest,
+		RequestID: r.Header.Get("X-Request-Id"),
+		Message:   message,
+	}
+	if err := httputil.JSONEncodeWithCode(w, badRequest, http.StatusBadRequest); err != nil {
+		h.respWriter.InternalServerError(w, r, err, "while encoding response to JSON")
+	}
+}
@@ -0,0 +1,115 @@
+package appinfo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/appinfo"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostEstimateHandler(t *testing.T) {
+	// given
+	priceTable, err := appinfo.NewPriceTableFromFile("testdata/price_table.yaml")
+	require.NoError(t, err)
+	handler := appinfo.NewCostEstimateHandler(priceTable, httputil.NewResponseWriter(logger.NewLogDummy(), true))
+
+	t.Run("should estimate the monthly cost", func(t *testing.T) {
+		// when
+		recorder := callCostEstimates(handler, "plan=azure_lite&region=westeurope&nodes=3")
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var estimate appinfo.CostEstimateDTO
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &estimate))
+		assert.Equal(t, appinfo.CostEstimateDTO{
+			Plan:        broker.AzureLitePlanName,
+			Region:      "westeurope",
+			Nodes:       3,
+			Currency:    "EUR",
+			ClusterCost: 75.5,
+			NodesCost:   420,
+			MonthlyCost: 495.5,
+		}, estimate)
+	})
+
+	for name, query := range map[string]string{
+		"region without prices":       "plan=gcp&region=us-central1&nodes=3",
+		"hyperscaler without prices":  "plan=aws&region=eu-central-1&nodes=3",
+		"plan which is not estimated": "plan=trial&region=westeurope&nodes=1",
+	} {
+		t.Run("should return estimate unavailable for "+name, func(t *testing.T) {
+			// when
+			recorder := callCostEstimates(handler, query)
+
+			// then
+			require.Equal(t, http.StatusNotFound, recorder.Code)
+			var errDTO httputil.ErrorDTO
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &errDTO))
+			assert.Contains(t, errDTO.Message, "estimate unavailable")
+		})
+	}
+
+	for name, query := range map[string]string{
+		"unknown plan":       "plan=unknown&region=westeurope&nodes=3",
+		"missing region":     "plan=azure&nodes=3",
+		"invalid nodes":      "plan=azure&region=westeurope&nodes=none",
+		"non-positive nodes": "plan=azure&region=westeurope&nodes=0",
+	} {
+		t.Run("should reject "+name, func(t *testing.T) {
+			// when
+			recorder := callCostEstimates(handler, query)
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestNewPriceTableFromFile(t *testing.T) {
+	t.Run("should return empty price table for empty path", func(t *testing.T) {
+		// when
+		priceTable, err := appinfo.NewPriceTableFromFile("")
+
+		// then
+		require.NoError(t, err)
+		_, available := priceTable.Estimate(broker.AzurePlanID, "westeurope", 1)
+		assert.False(t, available)
+	})
+
+	for name, content := range map[string]string{
+		"unknown hyperscaler": "hyperscalers:\n  alibaba:\n    cn-beijing:\n      node: 1\n",
+		"negative price":      "hyperscalers:\n  azure:\n    westeurope:\n      node: -1\n",
+	} {
+		t.Run("should reject "+name, func(t *testing.T) {
+			// given
+			dir, err := ioutil.TempDir("", "price-table")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "price_table.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+			// when
+			_, err = appinfo.NewPriceTableFromFile(path)
+
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
+
+func callCostEstimates(handler http.Handler, query string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/info/cost_estimates?"+query, nil))
+	return recorder
+}
@@ -14,6 +14,9 @@ type (
 		ServicePlanID     string    `json:"servicePlanId"`
 		ServicePlanName   string    `json:"servicePlanName"`
 		Status            StatusDTO `json:"status"`
+
+		// ShootConditions are filled in only on request, see the shoot_conditions query parameter
+		ShootConditions *ShootConditionsDTO `json:"shootConditions,omitempty"`
 	}
 
 	StatusDTO struct {
@@ -22,10 +25,49 @@ type (
 		DeletedAt      *time.Time          `json:"deletedAt,omitempty"`
 		Provisioning   *OperationStatusDTO `json:"provisioning,omitempty"`
 		Deprovisioning *OperationStatusDTO `json:"deprovisioning,omitempty"`
+		// SuspensionReason is set only for the suspended runtimes
+		SuspensionReason string `json:"suspensionReason,omitempty"`
 	}
 
 	OperationStatusDTO struct {
 		State       string `json:"state"`
 		Description string `json:"description"`
 	}
+
+	SubAccountDTO struct {
+		SubAccountID string                       `json:"subaccountId"`
+		Total        int                          `json:"total"`
+		States       map[string]int               `json:"states"`
+		Plans        map[string]SubAccountPlanDTO `json:"plans"`
+	}
+
+	SubAccountPlanDTO struct {
+		Total  int            `json:"total"`
+		States map[string]int `json:"states"`
+	}
+
+	CostEstimateDTO struct {
+		Plan        string  `json:"plan"`
+		Region      string  `json:"region"`
+		Nodes       int     `json:"nodes"`
+		Currency    string  `json:"currency"`
+		ClusterCost float64 `json:"clusterCost"`
+		NodesCost   float64 `json:"nodesCost"`
+		MonthlyCost float64 `json:"monthlyCost"`
+	}
+
+	ShootConditionsDTO struct {
+		// Stale is set when the shoot could not be fetched from Gardener, the conditions are unknown then
+		Stale      bool                `json:"stale"`
+		Error      string              `json:"error,omitempty"`
+		Conditions []ShootConditionDTO `json:"conditions,omitempty"`
+	}
+
+	ShootConditionDTO struct {
+		Type               string     `json:"type"`
+		Status             string     `json:"status"`
+		Reason             string     `json:"reason,omitempty"`
+		Message            string     `json:"message,omitempty"`
+		LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	}
 )
@@ -28,14 +28,20 @@ type RuntimeInfoHandler struct {
 	respWriter              ResponseWriter
 	plansConfig             broker.PlansConfig
 	defaultSubaccountRegion string
+
+	// shoots is optional, without it the shoot conditions are never returned
+	shoots       ShootGetter
+	shootTimeout time.Duration
 }
 
-func NewRuntimeInfoHandler(instanceFinder InstanceFinder, plansConfig broker.PlansConfig, region string, respWriter ResponseWriter) *RuntimeInfoHandler {
+func NewRuntimeInfoHandler(instanceFinder InstanceFinder, plansConfig broker.PlansConfig, region string, respWriter ResponseWriter, shoots ShootGetter, shootTimeout time.Duration) *RuntimeInfoHandler {
 	return &RuntimeInfoHandler{
 		instanceFinder:          instanceFinder,
 		respWriter:              respWriter,
 		plansConfig:             plansConfig,
 		defaultSubaccountRegion: region,
+		shoots:                  shoots,
+		shootTimeout:            shootTimeout,
 	}
 }
 
@@ -51,6 +57,10 @@ func (h *RuntimeInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.respWriter.InternalServerError(w, r, err, "while mapping instance model to dto")
 	}
 
+	if h.shoots != nil && wantShootConditions(r.URL.Query()) {
+		h.addShootConditions(dto, allInstances)
+	}
+
 	if err := httputil.JSONEncode(w, dto); err != nil {
 		h.respWriter.InternalServerError(w, r, err, "while encoding response to JSON")
 		return
@@ -77,9 +87,10 @@ func (h *RuntimeInfoHandler) mapToDTO(instances []internal.InstanceWithOperation
 				ServicePlanID:     inst.ServicePlanID,
 				ServicePlanName:   h.planNameOrDefault(inst),
 				Status: StatusDTO{
-					CreatedAt: getIfNotZero(inst.CreatedAt),
-					UpdatedAt: getIfNotZero(inst.UpdatedAt),
-					DeletedAt: getIfNotZero(inst.DeletedAt),
+					CreatedAt:        getIfNotZero(inst.CreatedAt),
+					UpdatedAt:        getIfNotZero(inst.UpdatedAt),
+					DeletedAt:        getIfNotZero(inst.DeletedAt),
+					SuspensionReason: inst.SuspensionReason,
 				},
 			})
 			idx = len(items) - 1
@@ -1,6 +1,7 @@
 package appinfo_test
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -95,7 +96,7 @@ func TestRuntimeInfoHandlerSuccess(t *testing.T) {
 				memStorage = newInMemoryStorage(t, tc.instances, tc.provisionOp, tc.deprovisionOp)
 			)
 
-			handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer)
+			handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, nil, 0)
 
 			// when
 			handler.ServeHTTP(respSpy, fixReq)
@@ -126,7 +127,7 @@ func TestRuntimeInfoHandlerFailures(t *testing.T) {
 	storageMock := &automock.InstanceFinder{}
 	defer storageMock.AssertExpectations(t)
 	storageMock.On("FindAllJoinedWithOperations", mock.Anything).Return(nil, errors.New("ups.. internal info"))
-	handler := appinfo.NewRuntimeInfoHandler(storageMock, broker.PlansConfig{}, "", writer)
+	handler := appinfo.NewRuntimeInfoHandler(storageMock, broker.PlansConfig{}, "", writer, nil, 0)
 
 	// when
 	handler.ServeHTTP(respSpy, fixReq)
@@ -138,6 +139,101 @@ func TestRuntimeInfoHandlerFailures(t *testing.T) {
 	assert.JSONEq(t, expBody, respSpy.Body.String())
 }
 
+func TestRuntimeInfoHandlerCSV(t *testing.T) {
+	// given
+	var (
+		fixReq     = httptest.NewRequest("GET", "http://example.com/info/runtimes.csv", nil)
+		respSpy    = httptest.NewRecorder()
+		writer     = httputil.NewResponseWriter(logger.NewLogDummy(), true)
+		memStorage = newInMemoryStorage(t,
+			[]internal.Instance{fixInstance(1), fixInstance(2)},
+			[]internal.ProvisioningOperation{fixProvisionOperation(1), fixProvisionOperation(2)},
+			[]internal.DeprovisioningOperation{fixDeprovisionOperation(2)})
+	)
+
+	handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, nil, 0)
+
+	// when
+	handler.ServeCSV(respSpy, fixReq)
+
+	// then
+	assert.Equal(t, http.StatusOK, respSpy.Result().StatusCode)
+	assert.Equal(t, "text/csv", respSpy.Result().Header.Get("Content-Type"))
+
+	records, err := csv.NewReader(respSpy.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"instance_id", "runtime_id", "subaccount_id", "global_account_id", "plan", "region", "state", "created_at", "updated_at"},
+		{"InstanceID field. IDX: 1", "RuntimeID field. IDX: 1", "SubAccountID field. IDX: 1", "GlobalAccountID field. IDX: 1",
+			"ServicePlanName field. IDX: 1", "region-value-idx-1", "provisioning succeeded", "2020-04-21T00:00:24Z", "2020-04-21T00:01:23Z"},
+		{"InstanceID field. IDX: 2", "RuntimeID field. IDX: 2", "SubAccountID field. IDX: 2", "GlobalAccountID field. IDX: 2",
+			"ServicePlanName field. IDX: 2", "region-value-idx-2", "deprovisioning succeeded", "2020-04-21T00:00:25Z", "2020-04-21T00:02:23Z"},
+	}, records)
+}
+
+func TestRuntimeInfoHandlerCSV_Pages(t *testing.T) {
+	// given
+	var instances []internal.Instance
+	for i := 1; i <= 250; i++ {
+		instances = append(instances, fixInstance(i))
+	}
+	memStorage := newInMemoryStorage(t, instances, nil, nil)
+	writer := httputil.NewResponseWriter(logger.NewLogDummy(), true)
+	handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, nil, 0)
+	respSpy := httptest.NewRecorder()
+
+	// when
+	handler.ServeCSV(respSpy, httptest.NewRequest("GET", "http://example.com/info/runtimes.csv", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, respSpy.Result().StatusCode)
+	records, err := csv.NewReader(respSpy.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 251)
+	for i, record := range records[1:] {
+		assert.Equal(t, fmt.Sprintf("InstanceID field. IDX: %d", i+1), record[0])
+	}
+}
+
+func TestRuntimeInfoHandlerCSV_Failures(t *testing.T) {
+	t.Run("should return error if the first page cannot be fetched", func(t *testing.T) {
+		// given
+		storageMock := &automock.InstanceFinder{}
+		defer storageMock.AssertExpectations(t)
+		storageMock.On("FindAllJoinedWithOperations", mock.Anything, mock.Anything).Return(nil, errors.New("ups.. internal info"))
+		handler := appinfo.NewRuntimeInfoHandler(storageMock, broker.PlansConfig{}, "", httputil.NewResponseWriter(logger.NewLogDummy(), true), nil, 0)
+		respSpy := httptest.NewRecorder()
+
+		// when
+		handler.ServeCSV(respSpy, httptest.NewRequest("GET", "http://example.com/info/runtimes.csv", nil))
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, respSpy.Result().StatusCode)
+	})
+
+	t.Run("should abort the response if the next page cannot be fetched", func(t *testing.T) {
+		// given
+		var page []internal.InstanceWithOperation
+		for i := 1; i <= 100; i++ {
+			page = append(page, internal.InstanceWithOperation{Instance: fixInstance(i)})
+		}
+		storageMock := &automock.InstanceFinder{}
+		storageMock.On("FindAllJoinedWithOperations", mock.Anything, mock.Anything).Return(page, nil).Once()
+		storageMock.On("FindAllJoinedWithOperations", mock.Anything, mock.Anything).Return(nil, errors.New("ups.. internal info")).Once()
+		handler := appinfo.NewRuntimeInfoHandler(storageMock, broker.PlansConfig{}, "", httputil.NewResponseWriter(logger.NewLogDummy(), true), nil, 0)
+		respSpy := httptest.NewRecorder()
+
+		// when
+		serve := func() {
+			handler.ServeCSV(respSpy, httptest.NewRequest("GET", "http://example.com/info/runtimes.csv", nil))
+		}
+
+		// then
+		assert.PanicsWithValue(t, http.ErrAbortHandler, serve)
+		storageMock.AssertExpectations(t)
+	})
+}
+
 func assertJSONWithGoldenFile(t *testing.T, gotRawJSON []byte) {
 	t.Helper()
 	g := goldie.New(t, goldie.WithNameSuffix(".golden.json"))
@@ -0,0 +1,103 @@
+package appinfo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/predicate"
+)
+
+// csvPageSize is the number of instances fetched from the storage and sent to the client at once
+const csvPageSize = 100
+
+var runtimeInventoryCSVHeader = []string{
+	"instance_id", "runtime_id", "subaccount_id", "global_account_id", "plan", "region", "state", "created_at", "updated_at",
+}
+
+// ServeCSV writes the same runtimes as ServeHTTP as the CSV inventory, one row per instance.
+// The instances are fetched from the storage page by page and every page is sent to the client before the next one
+// is fetched, the inventory is never loaded in memory as a whole.
+func (h *RuntimeInfoHandler) ServeCSV(w http.ResponseWriter, r *http.Request) {
+	runtimes, err := h.runtimesPage(1)
+	if err != nil {
+		h.respWriter.InternalServerError(w, r, err, "while fetching instances")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="runtimes.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	// errors of writing the response cannot be reported to the client anymore, the streaming is stopped
+	if err := writer.Write(runtimeInventoryCSVHeader); err != nil {
+		return
+	}
+	for page := 1; ; page++ {
+		for _, runtime := range runtimes {
+			if err := writer.Write(runtimeInventoryCSVRow(runtime)); err != nil {
+				return
+			}
+		}
+		writer.Flush()
+		if writer.Error() != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(runtimes) < csvPageSize {
+			return
+		}
+
+		runtimes, err = h.runtimesPage(page + 1)
+		if err != nil {
+			// the status is already sent, the connection is aborted so the client does not take the truncated inventory as complete
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func (h *RuntimeInfoHandler) runtimesPage(page int) ([]*RuntimeDTO, error) {
+	instances, err := h.instanceFinder.FindAllJoinedWithOperations(predicate.InstancesPage(page, csvPageSize), predicate.SortAscByCreatedAt())
+	if err != nil {
+		return nil, err
+	}
+	return h.mapToDTO(instances)
+}
+
+func runtimeInventoryCSVRow(runtime *RuntimeDTO) []string {
+	return []string{
+		runtime.ServiceInstanceID,
+		runtime.RuntimeID,
+		runtime.SubAccountID,
+		runtime.GlobalAccountID,
+		runtime.ServicePlanName,
+		runtime.SubAccountRegion,
+		runtimeState(runtime.Status),
+		formatCSVTime(runtime.Status.CreatedAt),
+		formatCSVTime(runtime.Status.UpdatedAt),
+	}
+}
+
+// runtimeState returns the state of the last operation of the runtime, e.g. "provisioning succeeded"
+func runtimeState(status StatusDTO) string {
+	switch {
+	case status.Deprovisioning != nil:
+		return fmt.Sprintf("deprovisioning %s", status.Deprovisioning.State)
+	case status.Provisioning != nil:
+		return fmt.Sprintf("provisioning %s", status.Provisioning.State)
+	default:
+		return ""
+	}
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
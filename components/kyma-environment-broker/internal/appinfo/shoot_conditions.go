@@ -0,0 +1,111 @@
+package appinfo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	gardenerapi "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	shootConditionsParam = "shoot_conditions"
+
+	// maxConcurrentShootLookups limits the number of parallel calls to Gardener
+	maxConcurrentShootLookups = 10
+)
+
+type ShootGetter interface {
+	Get(name string, options metav1.GetOptions) (*gardenerapi.Shoot, error)
+}
+
+// addShootConditions fills in the Gardener shoot conditions of the runtimes. The lookup is best-effort,
+// a shoot which cannot be fetched within the timeout is marked as stale instead of failing the whole response.
+func (h *RuntimeInfoHandler) addShootConditions(items []*RuntimeDTO, instances []internal.InstanceWithOperation) {
+	shootNames := make(map[string]string, len(items))
+	for _, inst := range instances {
+		shootNames[inst.InstanceID] = shootName(inst.Instance)
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentShootLookups)
+	for _, item := range items {
+		if item.RuntimeID == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(item *RuntimeDTO) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			item.ShootConditions = h.getShootConditions(shootNames[item.ServiceInstanceID])
+		}(item)
+	}
+	wg.Wait()
+}
+
+func (h *RuntimeInfoHandler) getShootConditions(name string) *ShootConditionsDTO {
+	if name == "" {
+		return &ShootConditionsDTO{Stale: true, Error: "shoot name is unknown"}
+	}
+
+	type result struct {
+		shoot *gardenerapi.Shoot
+		err   error
+	}
+	// buffered, so the goroutine does not leak when the lookup times out
+	done := make(chan result, 1)
+	go func() {
+		shoot, err := h.shoots.Get(name, metav1.GetOptions{})
+		done <- result{shoot: shoot, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return &ShootConditionsDTO{Stale: true, Error: fmt.Sprintf("while getting shoot %s: %s", name, res.err)}
+		}
+		return toShootConditionsDTO(res.shoot)
+	case <-time.After(h.shootTimeout):
+		return &ShootConditionsDTO{Stale: true, Error: fmt.Sprintf("timeout while getting shoot %s", name)}
+	}
+}
+
+func toShootConditionsDTO(shoot *gardenerapi.Shoot) *ShootConditionsDTO {
+	conditions := make([]ShootConditionDTO, 0, len(shoot.Status.Conditions))
+	for _, c := range shoot.Status.Conditions {
+		conditions = append(conditions, ShootConditionDTO{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: getIfNotZero(c.LastTransitionTime.Time),
+		})
+	}
+	return &ShootConditionsDTO{Conditions: conditions}
+}
+
+// shootName returns the shoot name from the instance details or, if it is not stored,
+// from the dashboard URL which has the form https://console.{shootName}.{domain}
+func shootName(instance internal.Instance) string {
+	if instance.InstanceDetails.ShootName != "" {
+		return instance.InstanceDetails.ShootName
+	}
+	parsed, err := url.Parse(instance.DashboardURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(parsed.Host, ".")
+	if len(parts) <= 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+func wantShootConditions(query url.Values) bool {
+	return query.Get(shootConditionsParam) == "true"
+}
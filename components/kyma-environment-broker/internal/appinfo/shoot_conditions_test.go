@@ -0,0 +1,139 @@
+package appinfo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gardenerapi "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardenerfake "github.com/gardener/gardener/pkg/client/core/clientset/versioned/fake"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/appinfo"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const fixGardenerNamespace = "garden-kyma"
+
+func TestRuntimeInfoHandlerShootConditions(t *testing.T) {
+	// given
+	healthy := fixShoot("healthy", gardenerapi.ConditionTrue, gardenerapi.ConditionTrue)
+	unhealthy := fixShoot("unhealthy", gardenerapi.ConditionTrue, gardenerapi.ConditionFalse)
+	shoots := gardenerfake.NewSimpleClientset(healthy, unhealthy).CoreV1beta1().Shoots(fixGardenerNamespace)
+
+	memStorage := newInMemoryStorage(t, []internal.Instance{
+		fixInstanceWithShoot(1, "healthy"),
+		fixInstanceWithShoot(2, "unhealthy"),
+		fixInstanceWithShoot(3, "removed"),
+	}, nil, nil)
+	writer := httputil.NewResponseWriter(logger.NewLogDummy(), true)
+	handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, shoots, time.Second)
+
+	// when
+	runtimes := serveRuntimes(t, handler, "http://example.com/info/runtimes?shoot_conditions=true")
+
+	// then
+	require.Len(t, runtimes, 3)
+	conditions := map[string]*appinfo.ShootConditionsDTO{}
+	for _, r := range runtimes {
+		conditions[r.ServiceInstanceID] = r.ShootConditions
+	}
+
+	healthyConditions := conditions[fixInstanceWithShoot(1, "").InstanceID]
+	require.NotNil(t, healthyConditions)
+	assert.False(t, healthyConditions.Stale)
+	assert.Equal(t, []appinfo.ShootConditionDTO{
+		{Type: string(gardenerapi.ShootAPIServerAvailable), Status: string(gardenerapi.ConditionTrue), Reason: "Checked"},
+		{Type: string(gardenerapi.ShootControlPlaneHealthy), Status: string(gardenerapi.ConditionTrue), Reason: "Checked"},
+	}, healthyConditions.Conditions)
+
+	unhealthyConditions := conditions[fixInstanceWithShoot(2, "").InstanceID]
+	require.NotNil(t, unhealthyConditions)
+	assert.False(t, unhealthyConditions.Stale)
+	assert.Equal(t, string(gardenerapi.ConditionFalse), unhealthyConditions.Conditions[1].Status)
+
+	unavailableConditions := conditions[fixInstanceWithShoot(3, "").InstanceID]
+	require.NotNil(t, unavailableConditions)
+	assert.True(t, unavailableConditions.Stale)
+	assert.Contains(t, unavailableConditions.Error, "removed")
+	assert.Empty(t, unavailableConditions.Conditions)
+}
+
+func TestRuntimeInfoHandlerShootConditionsTimeout(t *testing.T) {
+	// given
+	memStorage := newInMemoryStorage(t, []internal.Instance{fixInstanceWithShoot(1, "slow")}, nil, nil)
+	writer := httputil.NewResponseWriter(logger.NewLogDummy(), true)
+	handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, &slowShootGetter{delay: time.Second}, 10*time.Millisecond)
+
+	// when
+	runtimes := serveRuntimes(t, handler, "http://example.com/info/runtimes?shoot_conditions=true")
+
+	// then
+	require.Len(t, runtimes, 1)
+	require.NotNil(t, runtimes[0].ShootConditions)
+	assert.True(t, runtimes[0].ShootConditions.Stale)
+	assert.Contains(t, runtimes[0].ShootConditions.Error, "timeout")
+}
+
+func TestRuntimeInfoHandlerShootConditionsNotRequested(t *testing.T) {
+	// given
+	shoots := gardenerfake.NewSimpleClientset(fixShoot("healthy", gardenerapi.ConditionTrue, gardenerapi.ConditionTrue)).CoreV1beta1().Shoots(fixGardenerNamespace)
+	memStorage := newInMemoryStorage(t, []internal.Instance{fixInstanceWithShoot(1, "healthy")}, nil, nil)
+	writer := httputil.NewResponseWriter(logger.NewLogDummy(), true)
+	handler := appinfo.NewRuntimeInfoHandler(memStorage.Instances(), broker.PlansConfig{}, "default-region", writer, shoots, time.Second)
+
+	// when
+	runtimes := serveRuntimes(t, handler, "http://example.com/info/runtimes")
+
+	// then
+	require.Len(t, runtimes, 1)
+	assert.Nil(t, runtimes[0].ShootConditions)
+}
+
+func serveRuntimes(t *testing.T, handler http.Handler, target string) []appinfo.RuntimeDTO {
+	t.Helper()
+	respSpy := httptest.NewRecorder()
+	handler.ServeHTTP(respSpy, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, respSpy.Result().StatusCode)
+
+	var runtimes []appinfo.RuntimeDTO
+	require.NoError(t, json.Unmarshal(respSpy.Body.Bytes(), &runtimes))
+	return runtimes
+}
+
+func fixInstanceWithShoot(idx int, shootName string) internal.Instance {
+	instance := fixInstance(idx)
+	instance.DashboardURL = fmt.Sprintf("https://console.%s.kyma.example.com", shootName)
+	return instance
+}
+
+func fixShoot(name string, apiServer, controlPlane gardenerapi.ConditionStatus) *gardenerapi.Shoot {
+	return &gardenerapi.Shoot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fixGardenerNamespace,
+		},
+		Status: gardenerapi.ShootStatus{
+			Conditions: []gardenerapi.Condition{
+				{Type: gardenerapi.ShootAPIServerAvailable, Status: apiServer, Reason: "Checked"},
+				{Type: gardenerapi.ShootControlPlaneHealthy, Status: controlPlane, Reason: "Checked"},
+			},
+		},
+	}
+}
+
+type slowShootGetter struct {
+	delay time.Duration
+}
+
+func (g *slowShootGetter) Get(name string, _ metav1.GetOptions) (*gardenerapi.Shoot, error) {
+	time.Sleep(g.delay)
+	return fixShoot(name, gardenerapi.ConditionTrue, gardenerapi.ConditionTrue), nil
+}
@@ -0,0 +1,98 @@
+package appinfo
+
+import (
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+)
+
+const (
+	instanceStateProvisioning   = "provisioning"
+	instanceStateSucceeded      = "succeeded"
+	instanceStateFailed         = "failed"
+	instanceStateDeprovisioning = "deprovisioning"
+	instanceStateSuspended      = "suspended"
+)
+
+type SubAccountStatsGetter interface {
+	GetInstanceStateCountsForSubAccount(subAccountID string) ([]internal.InstanceStateCount, error)
+}
+
+// SubAccountInfoHandler serves the number of instances of the subaccount per plan and per state,
+// the subaccount without instances gets zeros
+type SubAccountInfoHandler struct {
+	statsGetter SubAccountStatsGetter
+	respWriter  ResponseWriter
+}
+
+func NewSubAccountInfoHandler(statsGetter SubAccountStatsGetter, respWriter ResponseWriter) *SubAccountInfoHandler {
+	return &SubAccountInfoHandler{
+		statsGetter: statsGetter,
+		respWriter:  respWriter,
+	}
+}
+
+func (h *SubAccountInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subAccountID := mux.Vars(r)["subaccount_id"]
+
+	counts, err := h.statsGetter.GetInstanceStateCountsForSubAccount(subAccountID)
+	if err != nil {
+		h.respWriter.InternalServerError(w, r, err, "while fetching instance counts of the subaccount")
+		return
+	}
+
+	if err := httputil.JSONEncode(w, toSubAccountDTO(subAccountID, counts)); err != nil {
+		h.respWriter.InternalServerError(w, r, err, "while encoding response to JSON")
+		return
+	}
+}
+
+func toSubAccountDTO(subAccountID string, counts []internal.InstanceStateCount) SubAccountDTO {
+	dto := SubAccountDTO{
+		SubAccountID: subAccountID,
+		States:       map[string]int{},
+		Plans:        map[string]SubAccountPlanDTO{},
+	}
+	for _, count := range counts {
+		planName := broker.PlanNamesMapping[count.ServicePlanID]
+		if planName == "" {
+			planName = count.ServicePlanID
+		}
+		state := instanceState(count.OperationType, count.OperationState)
+
+		plan, found := dto.Plans[planName]
+		if !found {
+			plan = SubAccountPlanDTO{States: map[string]int{}}
+		}
+		plan.Total += count.Total
+		plan.States[state] += count.Total
+		dto.Plans[planName] = plan
+
+		dto.Total += count.Total
+		dto.States[state] += count.Total
+	}
+
+	return dto
+}
+
+// instanceState classifies the instance the same way as the instances per plan metric,
+// the instance without the provisioning operation is provisioning
+func instanceState(operationType internal.OperationType, state domain.LastOperationState) string {
+	switch {
+	case state == domain.Failed:
+		return instanceStateFailed
+	case operationType == internal.OperationTypeDeprovision && state == domain.Succeeded:
+		return instanceStateSuspended
+	case operationType == internal.OperationTypeDeprovision:
+		return instanceStateDeprovisioning
+	case state == domain.Succeeded:
+		return instanceStateSucceeded
+	default:
+		return instanceStateProvisioning
+	}
+}
@@ -0,0 +1,97 @@
+package appinfo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/appinfo"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubAccountInfoHandler(t *testing.T) {
+	// given
+	memStorage := storage.NewMemoryStorage()
+	for id, planID := range map[string]string{
+		"azure-succeeded":   broker.AzurePlanID,
+		"azure-failed":      broker.AzurePlanID,
+		"azure-in-progress": broker.AzurePlanID,
+		"trial-suspended":   broker.TrialPlanID,
+		"other-subaccount":  broker.AzurePlanID,
+	} {
+		instance := fixture.FixInstance(id)
+		instance.SubAccountID = "sa-1"
+		if id == "other-subaccount" {
+			instance.SubAccountID = "sa-2"
+		}
+		instance.ServicePlanID = planID
+		require.NoError(t, memStorage.Instances().Insert(instance))
+	}
+	for id, state := range map[string]domain.LastOperationState{
+		"azure-succeeded":  domain.Succeeded,
+		"azure-failed":     domain.Failed,
+		"trial-suspended":  domain.Succeeded,
+		"other-subaccount": domain.Succeeded,
+	} {
+		provisioning := fixture.FixProvisioningOperation("prov-"+id, id)
+		provisioning.CreatedAt = time.Now().Add(-time.Hour)
+		provisioning.State = state
+		require.NoError(t, memStorage.Operations().InsertProvisioningOperation(provisioning))
+	}
+	suspension := fixture.FixDeprovisioningOperation("deprov-trial-suspended", "trial-suspended")
+	suspension.State = domain.Succeeded
+	require.NoError(t, memStorage.Operations().InsertDeprovisioningOperation(suspension))
+
+	router := mux.NewRouter()
+	router.Handle("/info/subaccounts/{subaccount_id}", appinfo.NewSubAccountInfoHandler(memStorage.Instances(), httputil.NewResponseWriter(logger.NewLogDummy(), true)))
+
+	t.Run("should return aggregates of the subaccount", func(t *testing.T) {
+		// when
+		dto := callSubAccountInfo(t, router, "sa-1")
+
+		// then
+		assert.Equal(t, appinfo.SubAccountDTO{
+			SubAccountID: "sa-1",
+			Total:        4,
+			States:       map[string]int{"succeeded": 1, "failed": 1, "provisioning": 1, "suspended": 1},
+			Plans: map[string]appinfo.SubAccountPlanDTO{
+				"azure": {Total: 3, States: map[string]int{"succeeded": 1, "failed": 1, "provisioning": 1}},
+				"trial": {Total: 1, States: map[string]int{"suspended": 1}},
+			},
+		}, dto)
+	})
+
+	t.Run("should return zeros for the subaccount without instances", func(t *testing.T) {
+		// when
+		dto := callSubAccountInfo(t, router, "sa-without-instances")
+
+		// then
+		assert.Equal(t, appinfo.SubAccountDTO{
+			SubAccountID: "sa-without-instances",
+			States:       map[string]int{},
+			Plans:        map[string]appinfo.SubAccountPlanDTO{},
+		}, dto)
+	})
+}
+
+func callSubAccountInfo(t *testing.T, router *mux.Router, subAccountID string) appinfo.SubAccountDTO {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/info/subaccounts/"+subAccountID, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var dto appinfo.SubAccountDTO
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &dto))
+	return dto
+}
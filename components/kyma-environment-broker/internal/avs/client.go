@@ -17,6 +17,9 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// ErrEvaluationNotFound is returned when the requested evaluation does not exist in AVS
+var ErrEvaluationNotFound = errors.New("evaluation not found")
+
 type Client struct {
 	httpClient *http.Client
 	avsConfig  Config
@@ -74,7 +77,7 @@ func (c *Client) GetEvaluation(evaluationID int64) (*BasicEvaluationCreateRespon
 	}
 	request.Header.Set("Content-Type", "application/json")
 
-	response, err := c.execute(request, false, true)
+	response, err := c.execute(request, true, true)
 	if err != nil {
 		return &responseObject, errors.Wrap(err, "while executing GetEvaluation request")
 	}
@@ -83,6 +86,9 @@ func (c *Client) GetEvaluation(evaluationID int64) (*BasicEvaluationCreateRespon
 			err = kebError.AsTemporaryError(closeErr, "while closing GetEvaluation response")
 		}
 	}()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrEvaluationNotFound
+	}
 
 	err = json.NewDecoder(response.Body).Decode(&responseObject)
 	if err != nil {
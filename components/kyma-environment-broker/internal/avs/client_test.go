@@ -175,7 +175,7 @@ func TestClient_GetEvaluation(t *testing.T) {
 		_, err = client.GetEvaluation(1)
 
 		// Then
-		assert.Contains(t, err.Error(), "404")
+		assert.Equal(t, ErrEvaluationNotFound, err)
 	})
 }
 
@@ -1,5 +1,18 @@
 package avs
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+)
+
+const (
+	InternalCheck = "internal"
+	ExternalCheck = "external"
+)
+
 type Config struct {
 	OauthTokenEndpoint          string
 	OauthUsername               string
@@ -21,12 +34,71 @@ type Config struct {
 	GardenerShootNameTagClassId int
 	GardenerSeedNameTagClassId  int
 	RegionTagClassId            int
-	TrialApiKey                 string `envconfig:"optional"`
-	TrialInternalTesterAccessId int64  `envconfig:"optional"`
-	TrialParentId               int64  `envconfig:"optional"`
-	TrialGroupId                int64  `envconfig:"optional"`
+	// PropagatedLabels lists the customer labels added to the internal evaluation as tags of the LabelTagClassId class
+	PropagatedLabels            []string `envconfig:"optional"`
+	LabelTagClassId             int      `envconfig:"optional"`
+	TrialApiKey                 string   `envconfig:"optional"`
+	TrialInternalTesterAccessId int64    `envconfig:"optional"`
+	TrialParentId               int64    `envconfig:"optional"`
+	TrialGroupId                int64    `envconfig:"optional"`
+	// PlanChecks lists the evaluations created for the given plans in the <plan>:<check>[+<check>] format,
+	// for example "trial:internal", the plans which are not listed get both the internal and the external evaluation
+	PlanChecks []string `envconfig:"optional"`
+	// BestEffort lets the provisioning continue when AVS is unavailable, the evaluations which cannot be created
+	// are marked as deferred and created by the reconciler running every DeferredReconcileInterval
+	BestEffort                bool          `envconfig:"default=false"`
+	DeferredReconcileInterval time.Duration `envconfig:"default=10m"`
+	// RemoveEvaluationsLast moves the removal of the evaluations to the end of the deprovisioning, just before the runtime is removed
+	RemoveEvaluationsLast bool `envconfig:"default=false"`
 }
 
 func (c Config) IsTrialConfigured() bool {
 	return c.TrialApiKey != "" && c.TrialInternalTesterAccessId != 0 && c.TrialParentId != 0 && c.TrialGroupId != 0
 }
+
+// Validate checks if the plan checks refer to the known plans and evaluations
+func (c Config) Validate() error {
+	_, err := c.planChecks()
+	return err
+}
+
+// IsCheckEnabled returns true if the given evaluation must be created for the plan
+func (c Config) IsCheckEnabled(planID, check string) bool {
+	planChecks, err := c.planChecks()
+	if err != nil {
+		return true
+	}
+	checks, found := planChecks[broker.PlanNamesMapping[planID]]
+	if !found {
+		return true
+	}
+	return checks[check]
+}
+
+func (c Config) planChecks() (map[string]map[string]bool, error) {
+	planChecks := make(map[string]map[string]bool, len(c.PlanChecks))
+	for _, entry := range c.PlanChecks {
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || !isKnownPlanName(parts[0]) {
+			return nil, fmt.Errorf("invalid plan checks entry '%s'", entry)
+		}
+		checks := map[string]bool{}
+		for _, check := range strings.Split(parts[1], "+") {
+			if check != InternalCheck && check != ExternalCheck {
+				return nil, fmt.Errorf("unknown check '%s' of the plan %s", check, parts[0])
+			}
+			checks[check] = true
+		}
+		planChecks[parts[0]] = checks
+	}
+	return planChecks, nil
+}
+
+func isKnownPlanName(name string) bool {
+	for _, planName := range broker.PlanNamesMapping {
+		if planName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package avs
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_IsCheckEnabled(t *testing.T) {
+	// given
+	cfg := Config{PlanChecks: []string{"trial:internal", "azure:internal+external"}}
+
+	// then
+	assert.NoError(t, cfg.Validate())
+	assert.True(t, cfg.IsCheckEnabled(broker.TrialPlanID, InternalCheck))
+	assert.False(t, cfg.IsCheckEnabled(broker.TrialPlanID, ExternalCheck))
+	assert.True(t, cfg.IsCheckEnabled(broker.AzurePlanID, ExternalCheck))
+	assert.True(t, cfg.IsCheckEnabled(broker.GCPPlanID, InternalCheck))
+	assert.True(t, cfg.IsCheckEnabled(broker.GCPPlanID, ExternalCheck))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for name, entry := range map[string]string{
+		"missing checks": "trial",
+		"unknown plan":   "unknown:internal",
+		"unknown check":  "trial:internal+synthetic",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			cfg := Config{PlanChecks: []string{entry}}
+
+			// then
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
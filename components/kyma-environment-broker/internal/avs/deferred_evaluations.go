@@ -0,0 +1,119 @@
+package avs
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DeferredEvaluationsReconciler creates the evaluations which were deferred during the provisioning because AVS
+// was unavailable. Only succeeded provisioning operations are reconciled, so the operations processed at the same time
+// are not modified. The avs-bridge of the runtime gets the internal evaluation with the next Kyma upgrade.
+type DeferredEvaluationsReconciler struct {
+	delegator         *Delegator
+	instances         storage.Instances
+	operations        storage.Operations
+	internalAssistant *InternalEvalAssistant
+	externalAssistant *ExternalEvalAssistant
+	log               logrus.FieldLogger
+}
+
+func NewDeferredEvaluationsReconciler(delegator *Delegator, instances storage.Instances, operations storage.Operations,
+	internalAssistant *InternalEvalAssistant, externalAssistant *ExternalEvalAssistant, log logrus.FieldLogger) *DeferredEvaluationsReconciler {
+	return &DeferredEvaluationsReconciler{
+		delegator:         delegator,
+		instances:         instances,
+		operations:        operations,
+		internalAssistant: internalAssistant,
+		externalAssistant: externalAssistant,
+		log:               log.WithField("service", "DeferredEvaluationsReconciler"),
+	}
+}
+
+// Run reconciles the deferred evaluations every interval until the context is done
+func (r *DeferredEvaluationsReconciler) Run(ctx context.Context, interval time.Duration) {
+	go wait.Until(func() {
+		if err := r.Reconcile(); err != nil {
+			r.log.Errorf("while reconciling deferred AVS evaluations: %s", err)
+		}
+	}, interval, ctx.Done())
+}
+
+// Reconcile creates the deferred evaluations of all instances once
+func (r *DeferredEvaluationsReconciler) Reconcile() error {
+	instances, _, _, err := r.instances.List(dbmodel.InstanceFilter{})
+	if err != nil {
+		return errors.Wrap(err, "while listing instances")
+	}
+
+	created := 0
+	for _, instance := range instances {
+		log := r.log.WithField("instanceID", instance.InstanceID)
+		operation, err := r.operations.GetProvisioningOperationByInstanceID(instance.InstanceID)
+		switch {
+		case dberr.IsNotFound(err):
+			continue
+		case err != nil:
+			log.Errorf("while getting provisioning operation: %s", err)
+			continue
+		}
+		if operation.State != domain.Succeeded || !r.isDeferred(operation.Avs) {
+			continue
+		}
+
+		log = log.WithField("operationID", operation.ID)
+		if err := r.reconcileOperation(*operation, instance.DashboardURL, log); err != nil {
+			log.Errorf("while creating deferred evaluations: %s", err)
+			continue
+		}
+		created++
+	}
+	if created > 0 {
+		r.log.Infof("Created deferred AVS evaluations of %d instances", created)
+	}
+
+	return nil
+}
+
+func (r *DeferredEvaluationsReconciler) isDeferred(lifecycleData internal.AvsLifecycleData) bool {
+	return r.internalAssistant.isDeferred(lifecycleData) || r.externalAssistant.isDeferred(lifecycleData)
+}
+
+// reconcileOperation creates the deferred evaluations of the operation, the operation is stored after every created
+// evaluation so the evaluation is not created twice if the next one fails
+func (r *DeferredEvaluationsReconciler) reconcileOperation(operation internal.ProvisioningOperation, dashboardURL string, log logrus.FieldLogger) error {
+	for _, eval := range []struct {
+		assistant EvalAssistant
+		url       string
+	}{
+		{assistant: r.internalAssistant},
+		{assistant: r.externalAssistant, url: dashboardURL},
+	} {
+		if !eval.assistant.isDeferred(operation.Avs) {
+			continue
+		}
+		lifecycleData := operation.Avs
+		if err := r.delegator.ReconcileEvaluation(log, operation.Operation, &lifecycleData, eval.assistant, eval.url); err != nil {
+			return err
+		}
+		eval.assistant.setDeferred(&lifecycleData, false)
+
+		operation.Avs = lifecycleData
+		updated, err := r.operations.UpdateProvisioningOperation(operation)
+		if err != nil {
+			return errors.Wrap(err, "while updating provisioning operation")
+		}
+		operation = *updated
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package avs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegator_CreateEvaluation_AvsUnavailable(t *testing.T) {
+	for name, tc := range map[string]struct {
+		bestEffort     bool
+		expectDeferred bool
+	}{
+		"strict mode retries":        {bestEffort: false, expectDeferred: false},
+		"best-effort mode continues": {bestEffort: true, expectDeferred: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			client, server, avsCfg, log := newUnavailableAvsTestParams(t)
+			avsCfg.BestEffort = tc.bestEffort
+			memoryStorage := storage.NewMemoryStorage()
+			delegator := NewDelegator(client, avsCfg, memoryStorage.Operations())
+
+			operation := fixture.FixProvisioningOperation("prov-id", "inst-id")
+			require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+
+			// when
+			operation, repeat, err := delegator.CreateEvaluation(log, operation, NewInternalEvalAssistant(avsCfg), "")
+
+			// then
+			require.NoError(t, err)
+			assert.Empty(t, server.Evaluations.BasicEvals)
+			assert.Zero(t, operation.Avs.AvsEvaluationInternalId)
+			assert.Equal(t, tc.expectDeferred, operation.Avs.AVSInternalEvaluationDeferred)
+			if tc.expectDeferred {
+				assert.Zero(t, repeat)
+			} else {
+				assert.NotZero(t, repeat)
+			}
+		})
+	}
+}
+
+func TestDeferredEvaluationsReconciler_Reconcile(t *testing.T) {
+	// given
+	unavailableClient, server, avsCfg, log := newUnavailableAvsTestParams(t)
+	avsCfg.BestEffort = true
+	iea := NewInternalEvalAssistant(avsCfg)
+	eea := NewExternalEvalAssistant(avsCfg)
+	memoryStorage := storage.NewMemoryStorage()
+
+	instance := fixture.FixInstance("inst-id")
+	require.NoError(t, memoryStorage.Instances().Insert(instance))
+	operation := fixture.FixProvisioningOperation("prov-id", instance.InstanceID)
+	operation.State = domain.InProgress
+	require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+
+	// AVS is unavailable during the provisioning
+	delegator := NewDelegator(unavailableClient, avsCfg, memoryStorage.Operations())
+	operation, _, err := delegator.CreateEvaluation(log, operation, iea, "")
+	require.NoError(t, err)
+	operation, _, err = delegator.CreateEvaluation(log, operation, eea, instance.DashboardURL)
+	require.NoError(t, err)
+	require.True(t, operation.Avs.AVSInternalEvaluationDeferred)
+	require.True(t, operation.Avs.AVSExternalEvaluationDeferred)
+
+	// AVS is available again
+	availableServer := FixMockAvsServer(server)
+	defer availableServer.Close()
+	avsCfg.ApiEndpoint = fmt.Sprintf("%s/api/v2/evaluationmetadata", availableServer.URL)
+	client, err := NewClient(context.TODO(), avsCfg, log)
+	require.NoError(t, err)
+	reconciler := NewDeferredEvaluationsReconciler(NewDelegator(client, avsCfg, memoryStorage.Operations()),
+		memoryStorage.Instances(), memoryStorage.Operations(), iea, eea, log)
+
+	// when the provisioning is still in progress
+	err = reconciler.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, server.Evaluations.BasicEvals)
+
+	// when the provisioning succeeded
+	operation.State = domain.Succeeded
+	_, err = memoryStorage.Operations().UpdateProvisioningOperation(operation)
+	require.NoError(t, err)
+	err = reconciler.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	stored, err := memoryStorage.Operations().GetProvisioningOperationByID(operation.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Avs.AVSInternalEvaluationDeferred)
+	assert.False(t, stored.Avs.AVSExternalEvaluationDeferred)
+	assert.Contains(t, server.Evaluations.BasicEvals, stored.Avs.AvsEvaluationInternalId)
+	assert.Contains(t, server.Evaluations.BasicEvals, stored.Avs.AVSEvaluationExternalId)
+	assert.Len(t, server.Evaluations.BasicEvals, 2)
+
+	// when reconciled again nothing is created
+	err = reconciler.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, server.Evaluations.BasicEvals, 2)
+}
+
+// newUnavailableAvsTestParams returns the client of AVS which issues the tokens but responds with 503 Service Unavailable
+// to the evaluation requests, the returned configuration refers to the token endpoint of the returned mock server
+func newUnavailableAvsTestParams(t *testing.T) (*Client, *MockAvsServer, Config, *logrus.Logger) {
+	server := NewMockAvsServer(t)
+	mockServer := FixMockAvsServer(server)
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	avsCfg := Config{
+		OauthTokenEndpoint: fmt.Sprintf("%s/oauth/token", mockServer.URL),
+		ApiEndpoint:        fmt.Sprintf("%s/api/v2/evaluationmetadata", unavailable.URL),
+	}
+	log := logrus.New()
+	client, err := NewClient(context.TODO(), avsCfg, log)
+	require.NoError(t, err)
+
+	return client, server, avsCfg, log
+}
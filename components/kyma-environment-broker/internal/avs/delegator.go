@@ -42,6 +42,9 @@ func (del *Delegator) CreateEvaluation(log logrus.FieldLogger, operation interna
 	if evalAssistant.IsAlreadyCreated(operation.Avs) {
 		log.Infof("step has already been finished previously")
 		updatedOperation = operation
+	} else if !evalAssistant.IsEnabledForPlan(operation.ProvisioningParameters.PlanID) {
+		log.Infof("evaluation is not configured for the plan, skipping")
+		return operation, 0, nil
 	} else {
 		log.Infof("making avs calls to create the Evaluation")
 		evaluationObject, err := evalAssistant.CreateBasicEvaluationRequest(operation, url)
@@ -53,6 +56,12 @@ func (del *Delegator) CreateEvaluation(log logrus.FieldLogger, operation interna
 		evalResp, err := del.client.CreateEvaluation(evaluationObject)
 		switch {
 		case err == nil:
+		case kebError.IsTemporaryError(err) && del.avsConfig.BestEffort:
+			log.Warnf("AVS is unavailable, deferring the creation of the evaluation: %s", err)
+			updatedOperation, d = del.provisionManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+				evalAssistant.setDeferred(&operation.Avs, true)
+			}, log)
+			return updatedOperation, d, nil
 		case kebError.IsTemporaryError(err):
 			errMsg := "cannot create AVS evaluation (temporary)"
 			log.Errorf("%s: %s", errMsg, err)
@@ -78,6 +87,11 @@ func (del *Delegator) AddTags(log logrus.FieldLogger, operation internal.Provisi
 	var updatedOperation internal.ProvisioningOperation
 	d := 0 * time.Second
 
+	if !evalAssistant.IsAlreadyCreated(operation.Avs) {
+		log.Infof("evaluation has not been created, skipping adding tags")
+		return operation, 0, nil
+	}
+
 	log.Infof("making avs calls to add tags to the Evaluation")
 	evalID := evalAssistant.GetEvaluationId(operation.Avs)
 
@@ -180,11 +194,93 @@ func (del *Delegator) SetStatus(log logrus.FieldLogger, lifecycleData *internal.
 	return nil
 }
 
+// ReconcileEvaluation brings the evaluation back to the state expected by KEB. A missing evaluation is recreated,
+// otherwise the configured tags which were removed are added again and a check disabled outside KEB is re-enabled.
+// The lifecycle data is updated in place, the caller is responsible for persisting it.
+func (del *Delegator) ReconcileEvaluation(log logrus.FieldLogger, operation internal.Operation, lifecycleData *internal.AvsLifecycleData, evalAssistant EvalAssistant, url string) error {
+	if evalAssistant.IsAlreadyDeleted(*lifecycleData) {
+		log.Infof("evaluation has been deleted, skipping reconciliation")
+		return nil
+	}
+	if !evalAssistant.IsAlreadyCreated(*lifecycleData) && !evalAssistant.IsEnabledForPlan(operation.ProvisioningParameters.PlanID) {
+		log.Infof("evaluation is not configured for the plan, skipping reconciliation")
+		return nil
+	}
+
+	if evalAssistant.IsAlreadyCreated(*lifecycleData) {
+		evalID := evalAssistant.GetEvaluationId(*lifecycleData)
+		eval, err := del.client.GetEvaluation(evalID)
+		switch {
+		case err == nil:
+			return del.reconcileExistingEvaluation(log, eval, lifecycleData, evalAssistant)
+		case err == ErrEvaluationNotFound:
+			log.Infof("evaluation [%d] does not exist in AVS, recreating it", evalID)
+		default:
+			return errors.Wrapf(err, "while getting evaluation [%d]", evalID)
+		}
+	}
+
+	evaluationObject, err := evalAssistant.CreateBasicEvaluationRequest(internal.ProvisioningOperation{Operation: operation}, url)
+	if err != nil {
+		return errors.Wrap(err, "while creating evaluation request")
+	}
+	evalResp, err := del.client.CreateEvaluation(evaluationObject)
+	if err != nil {
+		return errors.Wrap(err, "while creating evaluation")
+	}
+	log.Infof("evaluation [%d] created", evalResp.Id)
+	evalAssistant.SetEvalId(lifecycleData, evalResp.Id)
+	evalAssistant.SetEvalStatus(lifecycleData, evalResp.Status)
+
+	return nil
+}
+
+func (del *Delegator) reconcileExistingEvaluation(log logrus.FieldLogger, eval *BasicEvaluationCreateResponse, lifecycleData *internal.AvsLifecycleData, evalAssistant EvalAssistant) error {
+	for _, tag := range evalAssistant.ProvideTags() {
+		if hasTag(eval.Tags, tag) {
+			continue
+		}
+		log.Infof("adding missing tag %s to evaluation [%d]", tag.Content, eval.Id)
+		if _, err := del.client.AddTag(eval.Id, tag); err != nil {
+			return errors.Wrapf(err, "while adding tag to evaluation [%d]", eval.Id)
+		}
+	}
+
+	// maintenance is driven by the evaluation manager, any other status recorded by KEB is the desired one
+	status := evalAssistant.GetEvalStatus(*lifecycleData)
+	if !ValidStatus(status) || status == StatusMaintenance {
+		status = StatusActive
+	}
+	if eval.Status != status && eval.Status != StatusMaintenance {
+		log.Infof("changing status of evaluation [%d] from %s to %s", eval.Id, eval.Status, status)
+		if _, err := del.client.SetStatus(eval.Id, status); err != nil {
+			return errors.Wrapf(err, "while setting status of evaluation [%d]", eval.Id)
+		}
+		evalAssistant.SetEvalStatus(lifecycleData, status)
+	}
+
+	return nil
+}
+
+func hasTag(tags []*Tag, tag *Tag) bool {
+	for _, t := range tags {
+		if t.TagClassId == tag.TagClassId && t.Content == tag.Content {
+			return true
+		}
+	}
+	return false
+}
+
 func (del *Delegator) DeleteAvsEvaluation(deProvisioningOperation internal.DeprovisioningOperation, logger logrus.FieldLogger, assistant EvalAssistant) (internal.DeprovisioningOperation, error) {
 	if assistant.IsAlreadyDeleted(deProvisioningOperation.Avs) {
 		logger.Infof("Evaluations have been deleted previously")
 		return deProvisioningOperation, nil
 	}
+	// the evaluations are removed according to the lifecycle data, the plan checks could have changed since the provisioning
+	if !assistant.IsAlreadyCreated(deProvisioningOperation.Avs) {
+		logger.Infof("Evaluation has not been created, nothing to remove")
+		return deProvisioningOperation, nil
+	}
 
 	if err := del.tryDeleting(assistant, deProvisioningOperation, logger); err != nil {
 		return deProvisioningOperation, err
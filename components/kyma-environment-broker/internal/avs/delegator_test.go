@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
 	"github.com/sirupsen/logrus"
@@ -435,3 +437,52 @@ func TestDelegator_SetStatus(t *testing.T) {
 		assert.Equal(t, original, internalEA.GetOriginalEvalStatus(op.Avs))
 	})
 }
+
+func TestDelegator_PlanChecks(t *testing.T) {
+	// given
+	client, server, avsCfg, _, _, log := newTestParams(t)
+	avsCfg.PlanChecks = []string{"trial:internal"}
+	iea := NewInternalEvalAssistant(avsCfg)
+	eea := NewExternalEvalAssistant(avsCfg)
+
+	memoryStorage := storage.NewMemoryStorage()
+	delegator := NewDelegator(client, avsCfg, memoryStorage.Operations())
+
+	provisioning := fixture.FixProvisioningOperation("prov-id", "inst-id")
+	provisioning.ProvisioningParameters.PlanID = broker.TrialPlanID
+	err := memoryStorage.Operations().InsertProvisioningOperation(provisioning)
+	assert.NoError(t, err)
+
+	// when
+	provisioning, _, err = delegator.CreateEvaluation(log, provisioning, iea, "")
+	assert.NoError(t, err)
+	provisioning, _, err = delegator.CreateEvaluation(log, provisioning, eea, "https://dashboard.example.com")
+	assert.NoError(t, err)
+
+	// then
+	assert.NotZero(t, provisioning.Avs.AvsEvaluationInternalId)
+	assert.Zero(t, provisioning.Avs.AVSEvaluationExternalId)
+	assert.Len(t, server.Evaluations.BasicEvals, 1)
+	assert.Contains(t, server.Evaluations.BasicEvals, provisioning.Avs.AvsEvaluationInternalId)
+
+	// given
+	deprovisioning := fixture.FixDeprovisioningOperation("deprov-id", "inst-id")
+	deprovisioning.ProvisioningParameters = provisioning.ProvisioningParameters
+	deprovisioning.Avs = provisioning.Avs
+	err = memoryStorage.Operations().InsertDeprovisioningOperation(deprovisioning)
+	assert.NoError(t, err)
+	// the plan checks changed since the provisioning, the removal must follow what was created
+	avsCfg.PlanChecks = nil
+	delegator = NewDelegator(client, avsCfg, memoryStorage.Operations())
+
+	// when
+	deprovisioning, err = delegator.DeleteAvsEvaluation(deprovisioning, log, NewInternalEvalAssistant(avsCfg))
+	assert.NoError(t, err)
+	deprovisioning, err = delegator.DeleteAvsEvaluation(deprovisioning, log, NewExternalEvalAssistant(avsCfg))
+	assert.NoError(t, err)
+
+	// then
+	assert.Empty(t, server.Evaluations.BasicEvals)
+	assert.True(t, deprovisioning.Avs.AVSInternalEvaluationDeleted)
+	assert.False(t, deprovisioning.Avs.AVSExternalEvaluationDeleted)
+}
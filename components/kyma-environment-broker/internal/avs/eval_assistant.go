@@ -9,6 +9,7 @@ import (
 type EvalAssistant interface {
 	CreateBasicEvaluationRequest(operations internal.ProvisioningOperation, url string) (*BasicEvaluationCreateRequest, error)
 	AppendOverrides(inputCreator internal.ProvisionerInputCreator, evaluationId int64, pp internal.ProvisioningParameters)
+	IsEnabledForPlan(planID string) bool
 	IsAlreadyCreated(lifecycleData internal.AvsLifecycleData) bool
 	IsValid(lifecycleData internal.AvsLifecycleData) bool
 	IsInMaintenance(lifecycleData internal.AvsLifecycleData) bool
@@ -19,7 +20,10 @@ type EvalAssistant interface {
 	IsAlreadyDeleted(lifecycleData internal.AvsLifecycleData) bool
 	GetEvaluationId(lifecycleData internal.AvsLifecycleData) int64
 	ProvideParentId(pp internal.ProvisioningParameters) int64
+	ProvideTags() []*Tag
 	markDeleted(lifecycleData *internal.AvsLifecycleData)
+	isDeferred(lifecycleData internal.AvsLifecycleData) bool
+	setDeferred(lifecycleData *internal.AvsLifecycleData, deferred bool)
 	provideRetryConfig() *RetryConfig
 }
 
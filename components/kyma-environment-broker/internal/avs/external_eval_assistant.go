@@ -28,6 +28,10 @@ func (eea *ExternalEvalAssistant) AppendOverrides(inputCreator internal.Provisio
 	//do nothing
 }
 
+func (eea *ExternalEvalAssistant) IsEnabledForPlan(planID string) bool {
+	return eea.avsConfig.IsCheckEnabled(planID, ExternalCheck)
+}
+
 func (eea *ExternalEvalAssistant) IsAlreadyCreated(lifecycleData internal.AvsLifecycleData) bool {
 	return lifecycleData.AVSEvaluationExternalId != 0
 }
@@ -103,6 +107,14 @@ func (eea *ExternalEvalAssistant) markDeleted(lifecycleData *internal.AvsLifecyc
 	lifecycleData.AVSExternalEvaluationDeleted = true
 }
 
+func (eea *ExternalEvalAssistant) isDeferred(lifecycleData internal.AvsLifecycleData) bool {
+	return lifecycleData.AVSExternalEvaluationDeferred
+}
+
+func (eea *ExternalEvalAssistant) setDeferred(lifecycleData *internal.AvsLifecycleData, deferred bool) {
+	lifecycleData.AVSExternalEvaluationDeferred = deferred
+}
+
 func (eea *ExternalEvalAssistant) provideRetryConfig() *RetryConfig {
 	return eea.retryConfig
 }
@@ -50,6 +50,10 @@ func (iec *InternalEvalAssistant) AppendOverrides(inputCreator internal.Provisio
 	})
 }
 
+func (iec *InternalEvalAssistant) IsEnabledForPlan(planID string) bool {
+	return iec.avsConfig.IsCheckEnabled(planID, InternalCheck)
+}
+
 func (iec *InternalEvalAssistant) IsAlreadyCreated(lifecycleData internal.AvsLifecycleData) bool {
 	return lifecycleData.AvsEvaluationInternalId != 0
 }
@@ -134,6 +138,14 @@ func (iec *InternalEvalAssistant) markDeleted(lifecycleData *internal.AvsLifecyc
 	lifecycleData.AVSInternalEvaluationDeleted = true
 }
 
+func (iec *InternalEvalAssistant) isDeferred(lifecycleData internal.AvsLifecycleData) bool {
+	return lifecycleData.AVSInternalEvaluationDeferred
+}
+
+func (iec *InternalEvalAssistant) setDeferred(lifecycleData *internal.AvsLifecycleData, deferred bool) {
+	lifecycleData.AVSInternalEvaluationDeferred = deferred
+}
+
 func (iec *InternalEvalAssistant) provideRetryConfig() *RetryConfig {
 	return iec.retryConfig
 }
@@ -0,0 +1,50 @@
+package avs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const maxTagContentLength = 255
+
+// tagContentRegex allows the printable ASCII characters, AVS rejects the other ones in the tag content
+var tagContentRegex = regexp.MustCompile(`^[\x20-\x7E]+$`)
+
+// LabelTags returns the evaluation tags of the customer labels which are configured to be propagated to AVS,
+// the content of a tag is "<label>=<value>". The labels which AVS does not accept are skipped and reported in the returned error.
+func LabelTags(config Config, labels map[string]string) ([]*Tag, error) {
+	var tags []*Tag
+	var invalid []string
+	for _, key := range config.PropagatedLabels {
+		value, found := labels[key]
+		if !found {
+			continue
+		}
+		content := fmt.Sprintf("%s=%s", key, value)
+		switch {
+		case value == "":
+			invalid = append(invalid, fmt.Sprintf("%s: value must not be empty", key))
+			continue
+		case len(content) > maxTagContentLength:
+			invalid = append(invalid, fmt.Sprintf("%s: tag content must be no more than %d characters", key, maxTagContentLength))
+			continue
+		case !tagContentRegex.MatchString(content):
+			invalid = append(invalid, fmt.Sprintf("%s: value must consist of printable ASCII characters", key))
+			continue
+		}
+		tags = append(tags, &Tag{
+			Content:    content,
+			TagClassId: config.LabelTagClassId,
+		})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Content < tags[j].Content })
+
+	if len(invalid) > 0 {
+		return tags, errors.Errorf("labels not accepted by AVS: %s", strings.Join(invalid, ", "))
+	}
+	return tags, nil
+}
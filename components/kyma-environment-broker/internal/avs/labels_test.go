@@ -0,0 +1,30 @@
+package avs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelTags(t *testing.T) {
+	// given
+	config := Config{PropagatedLabels: []string{"owner", "environment", "team", "description"}, LabelTagClassId: 42}
+	labels := map[string]string{
+		"owner":       "team-a",
+		"environment": "production",
+		"team":        "zespół",
+		"description": strings.Repeat("a", 250),
+		"cost-center": "1234",
+	}
+
+	// when
+	tags, err := LabelTags(config, labels)
+
+	// then
+	assert.EqualError(t, err, "labels not accepted by AVS: team: value must consist of printable ASCII characters, description: tag content must be no more than 255 characters")
+	assert.Equal(t, []*Tag{
+		{Content: "environment=production", TagClassId: 42},
+		{Content: "owner=team-a", TagClassId: 42},
+	}, tags)
+}
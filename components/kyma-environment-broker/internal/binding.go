@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+)
+
+// BindingOperationType defines the type of the last asynchronous operation performed on a binding
+type BindingOperationType string
+
+const (
+	BindingOperationTypeBind   BindingOperationType = "bind"
+	BindingOperationTypeUnbind BindingOperationType = "unbind"
+)
+
+// Binding holds information about the service binding and its last asynchronous operation.
+// Credentials are available only when the bind operation succeeded.
+type Binding struct {
+	ID         string
+	InstanceID string
+
+	OperationID   string
+	OperationType BindingOperationType
+	State         domain.LastOperationState
+	Description   string
+
+	Credentials map[string]interface{}
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Version int
+}
+
+// IsReady returns true if the binding credentials are available
+func (b *Binding) IsReady() bool {
+	return b.OperationType == BindingOperationTypeBind && b.State == domain.Succeeded
+}
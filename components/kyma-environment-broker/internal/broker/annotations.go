@@ -0,0 +1,73 @@
+package broker
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxAnnotationsCount      = 20
+	maxAnnotationValueLength = 256
+	maxAnnotationsSize       = 4096
+	maxAnnotationNameLength  = 63
+	maxAnnotationPrefixLen   = 253
+)
+
+var (
+	annotationNameRegex   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	annotationPrefixRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// reservedAnnotationDomains are managed by Kubernetes, Gardener or the Kyma Control Plane and cannot be set by users
+	reservedAnnotationDomains = []string{
+		"kubernetes.io",
+		"k8s.io",
+		"gardener.cloud",
+		"kyma-project.io",
+	}
+)
+
+// validateAnnotations checks if the custom annotations can be applied to the cluster
+func validateAnnotations(annotations map[string]string) error {
+	if len(annotations) > maxAnnotationsCount {
+		return errors.Errorf("too many annotations: %d, at most %d are allowed", len(annotations), maxAnnotationsCount)
+	}
+
+	size := 0
+	for key, value := range annotations {
+		if err := validateAnnotationKey(key); err != nil {
+			return err
+		}
+		if len(value) > maxAnnotationValueLength {
+			return errors.Errorf("value of annotation %q must be no more than %d characters", key, maxAnnotationValueLength)
+		}
+		size += len(key) + len(value)
+	}
+	if size > maxAnnotationsSize {
+		return errors.Errorf("total size of annotations must be no more than %d bytes", maxAnnotationsSize)
+	}
+
+	return nil
+}
+
+func validateAnnotationKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) == 0 || len(prefix) > maxAnnotationPrefixLen || !annotationPrefixRegex.MatchString(prefix) {
+			return errors.Errorf("annotation %q has invalid prefix, it must be a DNS subdomain", key)
+		}
+		for _, domain := range reservedAnnotationDomains {
+			if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+				return errors.Errorf("annotation %q uses reserved prefix %q", key, domain)
+			}
+		}
+	}
+	if len(name) == 0 || len(name) > maxAnnotationNameLength || !annotationNameRegex.MatchString(name) {
+		return errors.Errorf("annotation %q has invalid name, it must consist of at most %d alphanumeric characters, '-', '_' or '.'", key, maxAnnotationNameLength)
+	}
+
+	return nil
+}
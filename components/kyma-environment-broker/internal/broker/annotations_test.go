@@ -0,0 +1,89 @@
+package broker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	tooMany := map[string]string{}
+	for i := 0; i <= maxAnnotationsCount; i++ {
+		tooMany[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	tooBig := map[string]string{}
+	for i := 0; i < maxAnnotationsCount; i++ {
+		tooBig[fmt.Sprintf("key-%d", i)] = strings.Repeat("v", maxAnnotationValueLength)
+	}
+
+	for name, tc := range map[string]struct {
+		annotations map[string]string
+		expectErr   bool
+	}{
+		"no annotations": {
+			annotations: nil,
+		},
+		"valid annotations": {
+			annotations: map[string]string{
+				"team":                 "kyma",
+				"example.com/project":  "control-plane",
+				"ticket_id.v1":         "ABC-123",
+				"my-company.io/ticket": "",
+			},
+		},
+		"reserved kubernetes prefix": {
+			annotations: map[string]string{"kubernetes.io/name": "value"},
+			expectErr:   true,
+		},
+		"reserved gardener subdomain prefix": {
+			annotations: map[string]string{"shoot.gardener.cloud/status": "value"},
+			expectErr:   true,
+		},
+		"reserved kyma prefix": {
+			annotations: map[string]string{"kcp.provisioner.kyma-project.io/licence-type": "value"},
+			expectErr:   true,
+		},
+		"invalid name": {
+			annotations: map[string]string{"-team": "value"},
+			expectErr:   true,
+		},
+		"invalid prefix": {
+			annotations: map[string]string{"Example.com/team": "value"},
+			expectErr:   true,
+		},
+		"empty name": {
+			annotations: map[string]string{"example.com/": "value"},
+			expectErr:   true,
+		},
+		"too long name": {
+			annotations: map[string]string{strings.Repeat("a", maxAnnotationNameLength+1): "value"},
+			expectErr:   true,
+		},
+		"too long value": {
+			annotations: map[string]string{"team": strings.Repeat("v", maxAnnotationValueLength+1)},
+			expectErr:   true,
+		},
+		"too many annotations": {
+			annotations: tooMany,
+			expectErr:   true,
+		},
+		"too big annotations": {
+			annotations: tooBig,
+			expectErr:   true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validateAnnotations(tc.annotations)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
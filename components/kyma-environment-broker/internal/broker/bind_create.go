@@ -2,27 +2,108 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/google/uuid"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type BindEndpoint struct {
+	instancesStorage storage.Instances
+	bindingsStorage  storage.Bindings
+	queue            Queue
+
 	log logrus.FieldLogger
 }
 
-func NewBind(log logrus.FieldLogger) *BindEndpoint {
-	return &BindEndpoint{log: log.WithField("service", "BindEndpoint")}
+func NewBind(instances storage.Instances, bindings storage.Bindings, queue Queue, log logrus.FieldLogger) *BindEndpoint {
+	return &BindEndpoint{
+		instancesStorage: instances,
+		bindingsStorage:  bindings,
+		queue:            queue,
+		log:              log.WithField("service", "BindEndpoint"),
+	}
 }
 
 // Bind creates a new service binding
 //   PUT /v2/service_instances/{instance_id}/service_bindings/{binding_id}
+//
+// Credentials are produced by a background process, so the binding is always created asynchronously
+// and the client must poll the binding last operation endpoint
 func (b *BindEndpoint) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
-	b.log.Infof("Bind instanceID:", instanceID)
-	b.log.Infof("Bind parameters: %s", string(details.RawParameters))
-	b.log.Infof("Bind context: %s", string(details.RawContext))
-	b.log.Infof("Bind asyncAllowed:", asyncAllowed)
+	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "bindingID": bindingID})
+
+	if !asyncAllowed {
+		return domain.Binding{}, apiresponses.ErrAsyncRequired
+	}
+
+	_, err := b.instancesStorage.GetByID(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.Binding{}, apiresponses.ErrInstanceDoesNotExist
+	case err != nil:
+		logger.Errorf("unable to get instance from the storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New("unable to get instance from the storage"), http.StatusInternalServerError, fmt.Sprintf("could not get instance from DB, instanceID %s", instanceID))
+	}
+
+	existing, err := b.bindingsStorage.GetByID(bindingID)
+	switch {
+	case err == nil:
+		return b.handleExistingBinding(existing, instanceID)
+	case !dberr.IsNotFound(err):
+		logger.Errorf("unable to get binding from the storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New("unable to get binding from the storage"), http.StatusInternalServerError, fmt.Sprintf("could not get binding from DB, bindingID %s", bindingID))
+	}
+
+	binding := internal.Binding{
+		ID:            bindingID,
+		InstanceID:    instanceID,
+		OperationID:   uuid.New().String(),
+		OperationType: internal.BindingOperationTypeBind,
+		State:         domain.InProgress,
+		Description:   "Binding is being created",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	err = b.bindingsStorage.Insert(binding)
+	if err != nil {
+		logger.Errorf("unable to save binding: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New("unable to save binding"), http.StatusInternalServerError, fmt.Sprintf("could not save binding, bindingID %s", bindingID))
+	}
+	logger.Infof("binding operation %s created", binding.OperationID)
+	b.queue.Add(binding.ID)
+
+	return domain.Binding{
+		IsAsync:       true,
+		OperationData: binding.OperationID,
+	}, nil
+}
+
+func (b *BindEndpoint) handleExistingBinding(binding *internal.Binding, instanceID string) (domain.Binding, error) {
+	if binding.InstanceID != instanceID || binding.OperationType != internal.BindingOperationTypeBind {
+		return domain.Binding{}, apiresponses.ErrBindingAlreadyExists
+	}
+	if binding.State == domain.InProgress {
+		return domain.Binding{
+			IsAsync:       true,
+			OperationData: binding.OperationID,
+		}, nil
+	}
+	if binding.IsReady() {
+		return domain.Binding{
+			AlreadyExists: true,
+			Credentials:   binding.Credentials,
+		}, nil
+	}
 
-	return domain.Binding{}, errors.New("not supported")
+	return domain.Binding{}, apiresponses.ErrBindingAlreadyExists
 }
@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bindingID = "binding-001"
+
+func TestBindEndpoint_AsyncBind(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Instances().Insert(fixInstance())
+	require.NoError(t, err)
+
+	queue := &automock.Queue{}
+	queue.On("Add", bindingID).Once()
+	defer queue.AssertExpectations(t)
+
+	bindEndpoint := NewBind(memoryStorage.Instances(), memoryStorage.Bindings(), queue, logrus.StandardLogger())
+	lastOperationEndpoint := NewLastBindingOperation(memoryStorage.Bindings(), logrus.StandardLogger())
+	getEndpoint := NewGetBinding(memoryStorage.Bindings(), logrus.StandardLogger())
+
+	// when
+	binding, err := bindEndpoint.Bind(context.TODO(), instanceID, bindingID, domain.BindDetails{}, true)
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, binding.IsAsync)
+	assert.NotEmpty(t, binding.OperationData)
+
+	// when
+	lastOp, err := lastOperationEndpoint.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{OperationData: binding.OperationData})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, domain.InProgress, lastOp.State)
+
+	// when
+	_, err = getEndpoint.GetBinding(context.TODO(), instanceID, bindingID)
+
+	// then
+	assertFailureStatus(t, err, http.StatusNotFound)
+
+	// when the background process finishes the binding
+	stored, err := memoryStorage.Bindings().GetByID(bindingID)
+	require.NoError(t, err)
+	stored.State = domain.Succeeded
+	stored.Credentials = map[string]interface{}{"publishUrl": "https://ems.local"}
+	_, err = memoryStorage.Bindings().Update(*stored)
+	require.NoError(t, err)
+
+	lastOp, err = lastOperationEndpoint.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{OperationData: binding.OperationData})
+	require.NoError(t, err)
+	spec, err := getEndpoint.GetBinding(context.TODO(), instanceID, bindingID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, domain.Succeeded, lastOp.State)
+	assert.Equal(t, map[string]interface{}{"publishUrl": "https://ems.local"}, spec.Credentials)
+}
+
+func TestBindEndpoint_BindRequiresAsync(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Instances().Insert(fixInstance())
+	require.NoError(t, err)
+
+	bindEndpoint := NewBind(memoryStorage.Instances(), memoryStorage.Bindings(), &automock.Queue{}, logrus.StandardLogger())
+
+	// when
+	_, err = bindEndpoint.Bind(context.TODO(), instanceID, bindingID, domain.BindDetails{}, false)
+
+	// then
+	assert.Equal(t, apiresponses.ErrAsyncRequired, err)
+}
+
+func TestBindEndpoint_BindNotExistingInstance(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	bindEndpoint := NewBind(memoryStorage.Instances(), memoryStorage.Bindings(), &automock.Queue{}, logrus.StandardLogger())
+
+	// when
+	_, err := bindEndpoint.Bind(context.TODO(), instanceID, bindingID, domain.BindDetails{}, true)
+
+	// then
+	assert.Equal(t, apiresponses.ErrInstanceDoesNotExist, err)
+}
+
+func TestUnbindEndpoint_AsyncUnbind(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Instances().Insert(fixInstance())
+	require.NoError(t, err)
+
+	queue := &automock.Queue{}
+	queue.On("Add", bindingID).Twice()
+	defer queue.AssertExpectations(t)
+
+	bindEndpoint := NewBind(memoryStorage.Instances(), memoryStorage.Bindings(), queue, logrus.StandardLogger())
+	unbindEndpoint := NewUnbind(memoryStorage.Bindings(), queue, logrus.StandardLogger())
+	lastOperationEndpoint := NewLastBindingOperation(memoryStorage.Bindings(), logrus.StandardLogger())
+
+	_, err = bindEndpoint.Bind(context.TODO(), instanceID, bindingID, domain.BindDetails{}, true)
+	require.NoError(t, err)
+
+	// when
+	spec, err := unbindEndpoint.Unbind(context.TODO(), instanceID, bindingID, domain.UnbindDetails{}, true)
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, spec.IsAsync)
+
+	lastOp, err := lastOperationEndpoint.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{OperationData: spec.OperationData})
+	require.NoError(t, err)
+	assert.Equal(t, domain.InProgress, lastOp.State)
+
+	// when the background process removes the binding
+	err = memoryStorage.Bindings().Delete(bindingID)
+	require.NoError(t, err)
+	_, err = lastOperationEndpoint.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{OperationData: spec.OperationData})
+
+	// then
+	assertFailureStatus(t, err, http.StatusGone)
+}
+
+func assertFailureStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	require.Error(t, err)
+	failure, ok := err.(*apiresponses.FailureResponse)
+	require.True(t, ok)
+	assert.Equal(t, status, failure.ValidatedStatusCode(nil))
+}
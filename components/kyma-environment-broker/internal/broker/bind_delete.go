@@ -2,26 +2,86 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/google/uuid"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type UnbindEndpoint struct {
+	bindingsStorage storage.Bindings
+	queue           Queue
+
 	log logrus.FieldLogger
 }
 
-func NewUnbind(log logrus.FieldLogger) *UnbindEndpoint {
-	return &UnbindEndpoint{log: log.WithField("service", "UnbindEndpoint")}
+func NewUnbind(bindings storage.Bindings, queue Queue, log logrus.FieldLogger) *UnbindEndpoint {
+	return &UnbindEndpoint{
+		bindingsStorage: bindings,
+		queue:           queue,
+		log:             log.WithField("service", "UnbindEndpoint"),
+	}
 }
 
 // Unbind deletes an existing service binding
 //   DELETE /v2/service_instances/{instance_id}/service_bindings/{binding_id}
+//
+// The binding is removed by the background process if the client accepts incomplete operations,
+// otherwise it is removed immediately
 func (b *UnbindEndpoint) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, asyncAllowed bool) (domain.UnbindSpec, error) {
-	b.log.Infof("Unbind instanceID: %s", instanceID)
-	b.log.Infof("Unbind details: %+v", details)
-	b.log.Infof("Unbind asyncAllowed: %v", asyncAllowed)
+	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "bindingID": bindingID})
+
+	binding, err := b.bindingsStorage.GetByID(bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
+	case err != nil:
+		logger.Errorf("unable to get binding from the storage: %s", err)
+		return domain.UnbindSpec{}, apiresponses.NewFailureResponse(errors.New("unable to get binding from the storage"), http.StatusInternalServerError, fmt.Sprintf("could not get binding from DB, bindingID %s", bindingID))
+	}
+	if binding.InstanceID != instanceID {
+		return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
+	}
+
+	if !asyncAllowed {
+		if err := b.bindingsStorage.Delete(bindingID); err != nil {
+			logger.Errorf("unable to delete binding: %s", err)
+			return domain.UnbindSpec{}, apiresponses.NewFailureResponse(errors.New("unable to delete binding"), http.StatusInternalServerError, fmt.Sprintf("could not delete binding, bindingID %s", bindingID))
+		}
+		return domain.UnbindSpec{}, nil
+	}
+
+	if binding.OperationType == internal.BindingOperationTypeUnbind && binding.State == domain.InProgress {
+		return domain.UnbindSpec{
+			IsAsync:       true,
+			OperationData: binding.OperationID,
+		}, nil
+	}
+
+	binding.OperationID = uuid.New().String()
+	binding.OperationType = internal.BindingOperationTypeUnbind
+	binding.State = domain.InProgress
+	binding.Description = "Binding is being deleted"
+	binding.UpdatedAt = time.Now()
+	binding, err = b.bindingsStorage.Update(*binding)
+	if err != nil {
+		logger.Errorf("unable to update binding: %s", err)
+		return domain.UnbindSpec{}, apiresponses.NewFailureResponse(errors.New("unable to update binding"), http.StatusInternalServerError, fmt.Sprintf("could not update binding, bindingID %s", bindingID))
+	}
+	logger.Infof("unbinding operation %s created", binding.OperationID)
+	b.queue.Add(binding.ID)
 
-	return domain.UnbindSpec{}, errors.New("not supported")
+	return domain.UnbindSpec{
+		IsAsync:       true,
+		OperationData: binding.OperationID,
+	}, nil
 }
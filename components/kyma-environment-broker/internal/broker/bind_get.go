@@ -2,25 +2,53 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type GetBindingEndpoint struct {
+	bindingsStorage storage.Bindings
+
 	log logrus.FieldLogger
 }
 
-func NewGetBinding(log logrus.FieldLogger) *GetBindingEndpoint {
-	return &GetBindingEndpoint{log: log.WithField("service", "GetBindingEndpoint")}
+func NewGetBinding(bindings storage.Bindings, log logrus.FieldLogger) *GetBindingEndpoint {
+	return &GetBindingEndpoint{
+		bindingsStorage: bindings,
+		log:             log.WithField("service", "GetBindingEndpoint"),
+	}
 }
 
 // GetBinding fetches an existing service binding
 //   GET /v2/service_instances/{instance_id}/service_bindings/{binding_id}
+//
+// The binding is not found until the bind operation succeeded
 func (b *GetBindingEndpoint) GetBinding(ctx context.Context, instanceID, bindingID string) (domain.GetBindingSpec, error) {
-	b.log.Infof("GetBinding instanceID: %s", instanceID)
-	b.log.Infof("GetBinding bindingID: %s", bindingID)
+	binding, err := b.bindingsStorage.GetByID(bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.GetBindingSpec{}, bindingNotFound(bindingID)
+	case err != nil:
+		b.log.Errorf("unable to get binding %s from the storage: %s", bindingID, err)
+		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New("unable to get binding from the storage"), http.StatusInternalServerError, fmt.Sprintf("could not get binding from DB, bindingID %s", bindingID))
+	}
+	if binding.InstanceID != instanceID || !binding.IsReady() {
+		return domain.GetBindingSpec{}, bindingNotFound(bindingID)
+	}
+
+	return domain.GetBindingSpec{
+		Credentials: binding.Credentials,
+	}, nil
+}
 
-	return domain.GetBindingSpec{}, errors.New("not supported")
+func bindingNotFound(bindingID string) error {
+	return apiresponses.NewFailureResponse(errors.New("binding does not exist"), http.StatusNotFound, fmt.Sprintf("binding with ID %s is not found", bindingID))
 }
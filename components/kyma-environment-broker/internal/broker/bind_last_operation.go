@@ -2,26 +2,57 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type LastBindingOperationEndpoint struct {
+	bindingsStorage storage.Bindings
+
 	log logrus.FieldLogger
 }
 
-func NewLastBindingOperation(log logrus.FieldLogger) *LastBindingOperationEndpoint {
-	return &LastBindingOperationEndpoint{log: log.WithField("service", "LastBindingOperationEndpoint")}
+func NewLastBindingOperation(bindings storage.Bindings, log logrus.FieldLogger) *LastBindingOperationEndpoint {
+	return &LastBindingOperationEndpoint{
+		bindingsStorage: bindings,
+		log:             log.WithField("service", "LastBindingOperationEndpoint"),
+	}
 }
 
 // LastBindingOperation fetches last operation state for a service binding
 //   GET /v2/service_instances/{instance_id}/service_bindings/{binding_id}/last_operation
 func (b *LastBindingOperationEndpoint) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
-	b.log.Infof("LastBindingOperation instanceID: %s", instanceID)
-	b.log.Infof("LastBindingOperation bindingID: %s", bindingID)
-	b.log.Infof("LastBindingOperation details: %+v", details)
+	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "bindingID": bindingID, "operationID": details.OperationData})
+
+	binding, err := b.bindingsStorage.GetByID(bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		// the binding is removed when the unbind operation is finished
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New("binding does not exist"), http.StatusGone, fmt.Sprintf("binding with ID %s is not found in DB", bindingID))
+	case err != nil:
+		logger.Errorf("unable to get binding from the storage: %s", err)
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New("unable to get binding from the storage"), http.StatusInternalServerError, fmt.Sprintf("could not get binding from DB, bindingID %s", bindingID))
+	}
+
+	if binding.InstanceID != instanceID {
+		err := errors.New("binding does not exist")
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+	if details.OperationData != "" && details.OperationData != binding.OperationID {
+		err := errors.New("operation does not exist")
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
 
-	return domain.LastOperation{}, errors.New("not supported")
+	return domain.LastOperation{
+		State:       binding.State,
+		Description: binding.Description,
+	}, nil
 }
@@ -0,0 +1,17 @@
+package broker
+
+import (
+	"github.com/pkg/errors"
+)
+
+// validateRegionNotBlocked checks that the hyperscaler region is not being decommissioned. Only the region requested
+// in the parameters is checked, the runtimes provisioned without the region parameter use the default region of the plan.
+func validateRegionNotBlocked(region *string, blocked map[string]struct{}) error {
+	if region == nil {
+		return nil
+	}
+	if _, found := blocked[*region]; found {
+		return errors.Errorf("provisioning in the region %q is blocked because the region is being decommissioned, choose another region", *region)
+	}
+	return nil
+}
@@ -31,6 +31,46 @@ type KymaEnvironmentBroker struct {
 type Config struct {
 	EnablePlans          EnablePlans `envconfig:"default=azure"`
 	OnlySingleTrialPerGA bool        `envconfig:"default=true"`
+
+	// ProvisionRateLimit limits the number of provisioning requests per subaccount
+	ProvisionRateLimit RateLimitConfig
+
+	// SupportedKubernetesVersions lists the Kubernetes versions which can be requested in the provisioning parameters
+	SupportedKubernetesVersions []string `envconfig:"optional"`
+
+	// ToggleableComponents lists the components which can be enabled or disabled in the provisioning parameters
+	ToggleableComponents []string `envconfig:"optional"`
+	// ComponentDependencies lists the components required by other components in the <component>:<dependency>[+<dependency>] format,
+	// the component toggles which leave a component without a required one are rejected
+	ComponentDependencies []string `envconfig:"optional"`
+
+	// SingleInstancePlans lists the plans in which a subaccount can have only one instance,
+	// unless the allowMultiple provisioning parameter is set
+	SingleInstancePlans EnablePlans `envconfig:"optional"`
+
+	// MaxParametersSize limits the size in bytes of the provisioning, update and binding request bodies,
+	// zero or a negative value disables the limit
+	MaxParametersSize int `envconfig:"default=65536"`
+
+	// BlockedRegions lists the hyperscaler regions which are being decommissioned, new runtimes cannot be provisioned
+	// in them, while the existing runtimes can still be updated and deprovisioned
+	BlockedRegions []string `envconfig:"optional"`
+
+	// OperationCache configures the cache of the operations polled by the last operation endpoint
+	OperationCache OperationCacheConfig
+
+	// DefaultOperationPriority is the priority of the provisioning operations queued without
+	// the X-Operation-Priority header, the operations with a higher priority are processed first
+	DefaultOperationPriority int `envconfig:"default=0"`
+
+	// KubeletFeatureGates and APIServerFeatureGates list the feature gates which can be requested
+	// in the kubernetesConfig provisioning parameter
+	KubeletFeatureGates   []string `envconfig:"optional"`
+	APIServerFeatureGates []string `envconfig:"optional"`
+
+	// InstanceNameTemplate is the text/template of the instance names, e.g. {{.PlanName}}-{{.Region}}, which derives
+	// the name if it is not specified and rejects the specified names not following it, empty accepts any name
+	InstanceNameTemplate string `envconfig:"optional"`
 }
 
 type ServicesConfig map[string]Service
@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+)
+
+// validateComponentToggles checks if the toggled components are on the allowlist. The names are compared as case insensitive
+// and the returned toggles use the names from the allowlist, so they match the names of the registered disablers.
+func validateComponentToggles(toggles *internal.ComponentToggles, allowed []string) (*internal.ComponentToggles, error) {
+	if toggles == nil {
+		return nil, nil
+	}
+	allowlist := map[string]string{}
+	for _, name := range allowed {
+		allowlist[strings.ToLower(name)] = name
+	}
+
+	normalize := func(names []string) ([]string, error) {
+		var out []string
+		for _, name := range names {
+			component, found := allowlist[strings.ToLower(name)]
+			if !found {
+				if len(allowed) == 0 {
+					return nil, errors.New("toggling components is not supported")
+				}
+				return nil, errors.Errorf("component %q cannot be toggled, allowed components: %s", name, strings.Join(allowed, ", "))
+			}
+			out = append(out, component)
+		}
+		return out, nil
+	}
+	enable, err := normalize(toggles.Enable)
+	if err != nil {
+		return nil, err
+	}
+	disable, err := normalize(toggles.Disable)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range enable {
+		for _, other := range disable {
+			if name == other {
+				return nil, errors.Errorf("component %q cannot be enabled and disabled at the same time", name)
+			}
+		}
+	}
+
+	return &internal.ComponentToggles{Enable: enable, Disable: disable}, nil
+}
@@ -0,0 +1,99 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+// backupRetentionRange is the number of days for which the backups of the etcd of the plan can be kept
+type backupRetentionRange struct {
+	min int
+	max int
+}
+
+var backupRetentionRanges = map[string]backupRetentionRange{
+	AzurePlanID:     {min: 1, max: 30},
+	AWSPlanID:       {min: 1, max: 30},
+	GCPPlanID:       {min: 1, max: 30},
+	OpenStackPlanID: {min: 1, max: 30},
+	AzureLitePlanID: {min: 1, max: 7},
+}
+
+// controlPlaneHAPolicy tells if the plan allows the highly available control plane, i.e. the etcd and the API server
+// replicated across the zones, and which control plane the plan gets when the provisioning parameters do not specify it
+type controlPlaneHAPolicy struct {
+	allowed          bool
+	enabledByDefault bool
+}
+
+// the trial and azure_lite plans keep the single-node control plane to save the costs
+var controlPlaneHAPolicies = map[string]controlPlaneHAPolicy{
+	AzurePlanID:     {allowed: true},
+	AWSPlanID:       {allowed: true},
+	GCPPlanID:       {allowed: true},
+	OpenStackPlanID: {allowed: true},
+	AzureLitePlanID: {},
+	TrialPlanID:     {},
+}
+
+// ControlPlaneHAOrDefault returns the requested control plane high availability or the default of the plan,
+// the result is stored with the provisioning parameters of the operation
+func ControlPlaneHAOrDefault(planID string, requested *bool) *bool {
+	if requested != nil {
+		return requested
+	}
+	enabled := controlPlaneHAPolicies[planID].enabledByDefault
+	return &enabled
+}
+
+// registerControlPlaneValidators adds the validators of the etcd encryption, the backup retention and the control plane
+// high availability, the trial plan always uses the defaults
+func registerControlPlaneValidators(validators PlanParametersValidators) {
+	for planID, policy := range controlPlaneHAPolicies {
+		validators.Register(planID, controlPlaneHAValidator(policy))
+	}
+	for planID, retention := range backupRetentionRanges {
+		validators.Register(planID, controlPlaneValidator(retention))
+	}
+	validators.Register(TrialPlanID, ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		var violations []ParameterError
+		if parameters.EtcdEncryption != nil {
+			violations = append(violations, ParameterError{Parameter: "etcdEncryption", Message: "is not supported for the plan"})
+		}
+		if parameters.BackupRetentionDays != nil {
+			violations = append(violations, ParameterError{Parameter: "backupRetentionDays", Message: "is not supported for the plan"})
+		}
+		return violations
+	}))
+}
+
+func controlPlaneValidator(retention backupRetentionRange) ParametersValidator {
+	return ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		var violations []ParameterError
+		if encryption := parameters.EtcdEncryption; encryption != nil && encryption.KeyRef != nil {
+			switch {
+			case !encryption.Enabled:
+				violations = append(violations, ParameterError{Parameter: "etcdEncryption.keyRef", Message: "can be specified only when the etcd encryption is enabled"})
+			case *encryption.KeyRef == "":
+				violations = append(violations, ParameterError{Parameter: "etcdEncryption.keyRef", Message: "must not be empty"})
+			}
+		}
+		if days := parameters.BackupRetentionDays; days != nil && (*days < retention.min || *days > retention.max) {
+			violations = append(violations, ParameterError{
+				Parameter: "backupRetentionDays",
+				Message:   fmt.Sprintf("must be between %d and %d days for the plan, got %d", retention.min, retention.max, *days),
+			})
+		}
+		return violations
+	})
+}
+
+func controlPlaneHAValidator(policy controlPlaneHAPolicy) ParametersValidator {
+	return ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		if parameters.ControlPlaneHA != nil && *parameters.ControlPlaneHA && !policy.allowed {
+			return []ParameterError{{Parameter: "controlPlaneHA", Message: "is not supported for the plan, the plan has a single-node control plane"}}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,132 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlPlaneValidators(t *testing.T) {
+	// given
+	validators := NewDefaultParametersValidators()
+
+	for name, tc := range map[string]struct {
+		planID         string
+		parameters     internal.ProvisioningParametersDTO
+		expectedErrors []ParameterError
+	}{
+		"defaults": {
+			planID: AzurePlanID,
+		},
+		"etcd encryption with customer-managed key and backup retention": {
+			planID: GCPPlanID,
+			parameters: internal.ProvisioningParametersDTO{
+				EtcdEncryption:      &internal.EtcdEncryption{Enabled: true, KeyRef: ptr.String("projects/p/locations/europe-west3/keyRings/r/cryptoKeys/k")},
+				BackupRetentionDays: ptr.Integer(30),
+			},
+		},
+		"etcd encryption disabled": {
+			planID:     AWSPlanID,
+			parameters: internal.ProvisioningParametersDTO{EtcdEncryption: &internal.EtcdEncryption{Enabled: false}},
+		},
+		"key of disabled etcd encryption": {
+			planID:     AWSPlanID,
+			parameters: internal.ProvisioningParametersDTO{EtcdEncryption: &internal.EtcdEncryption{Enabled: false, KeyRef: ptr.String("arn:aws:kms:key")}},
+			expectedErrors: []ParameterError{
+				{Parameter: "etcdEncryption.keyRef", Message: "can be specified only when the etcd encryption is enabled"},
+			},
+		},
+		"empty key": {
+			planID:     AzurePlanID,
+			parameters: internal.ProvisioningParametersDTO{EtcdEncryption: &internal.EtcdEncryption{Enabled: true, KeyRef: ptr.String("")}},
+			expectedErrors: []ParameterError{
+				{Parameter: "etcdEncryption.keyRef", Message: "must not be empty"},
+			},
+		},
+		"backup retention below the range": {
+			planID:     OpenStackPlanID,
+			parameters: internal.ProvisioningParametersDTO{BackupRetentionDays: ptr.Integer(0)},
+			expectedErrors: []ParameterError{
+				{Parameter: "backupRetentionDays", Message: "must be between 1 and 30 days for the plan, got 0"},
+			},
+		},
+		"backup retention above the range of the plan": {
+			planID:     AzureLitePlanID,
+			parameters: internal.ProvisioningParametersDTO{BackupRetentionDays: ptr.Integer(14)},
+			expectedErrors: []ParameterError{
+				{Parameter: "backupRetentionDays", Message: "must be between 1 and 7 days for the plan, got 14"},
+			},
+		},
+		"highly available control plane": {
+			planID:     AzurePlanID,
+			parameters: internal.ProvisioningParametersDTO{ControlPlaneHA: ptr.Bool(true)},
+		},
+		"highly available control plane of azure_lite plan": {
+			planID:     AzureLitePlanID,
+			parameters: internal.ProvisioningParametersDTO{ControlPlaneHA: ptr.Bool(true)},
+			expectedErrors: []ParameterError{
+				{Parameter: "controlPlaneHA", Message: "is not supported for the plan, the plan has a single-node control plane"},
+			},
+		},
+		"single-node control plane of trial plan": {
+			planID:     TrialPlanID,
+			parameters: internal.ProvisioningParametersDTO{ControlPlaneHA: ptr.Bool(false)},
+		},
+		"highly available control plane of trial plan": {
+			planID:     TrialPlanID,
+			parameters: internal.ProvisioningParametersDTO{ControlPlaneHA: ptr.Bool(true)},
+			expectedErrors: []ParameterError{
+				{Parameter: "controlPlaneHA", Message: "is not supported for the plan, the plan has a single-node control plane"},
+			},
+		},
+		"trial plan": {
+			planID: TrialPlanID,
+			parameters: internal.ProvisioningParametersDTO{
+				EtcdEncryption:      &internal.EtcdEncryption{Enabled: true},
+				BackupRetentionDays: ptr.Integer(1),
+			},
+			expectedErrors: []ParameterError{
+				{Parameter: "etcdEncryption", Message: "is not supported for the plan"},
+				{Parameter: "backupRetentionDays", Message: "is not supported for the plan"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validators.Validate(tc.planID, tc.parameters)
+
+			// then
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, ParametersValidationError{}, err)
+			assert.Equal(t, tc.expectedErrors, err.(ParametersValidationError).Errors)
+		})
+	}
+}
+
+func TestControlPlaneHAOrDefault(t *testing.T) {
+	for name, tc := range map[string]struct {
+		planID    string
+		requested *bool
+		expected  bool
+	}{
+		"default of production plan": {planID: AWSPlanID, expected: false},
+		"default of trial plan":      {planID: TrialPlanID, expected: false},
+		"requested HA":               {planID: GCPPlanID, requested: ptr.Bool(true), expected: true},
+		"requested single node":      {planID: OpenStackPlanID, requested: ptr.Bool(false), expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			got := ControlPlaneHAOrDefault(tc.planID, tc.requested)
+
+			// then
+			require.NotNil(t, got)
+			assert.Equal(t, tc.expected, *got)
+		})
+	}
+}
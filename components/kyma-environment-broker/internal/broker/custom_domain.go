@@ -0,0 +1,58 @@
+package broker
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pkg/errors"
+)
+
+const maxCustomDomainLength = 253
+
+var customDomainRegexp = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// normalizeCustomDomain validates the custom domain and returns it in lower case without the trailing dot,
+// so the same domain written differently is stored and compared as one value
+func normalizeCustomDomain(customDomain *string) (*string, error) {
+	if customDomain == nil {
+		return nil, nil
+	}
+	normalized := strings.TrimSuffix(strings.ToLower(*customDomain), ".")
+	if len(normalized) > maxCustomDomainLength || !customDomainRegexp.MatchString(normalized) {
+		return nil, errors.Errorf("custom domain %q is not a valid domain name", *customDomain)
+	}
+
+	return ptr.String(normalized), nil
+}
+
+// isCustomDomainClaimed returns true if the custom domain is used by an instance other than the given one.
+// The domain is released when the instance is removed at the end of the deprovisioning.
+func (b *ProvisionEndpoint) isCustomDomainClaimed(customDomain *string, instanceID string) (bool, error) {
+	if customDomain == nil {
+		return false, nil
+	}
+
+	instance, err := b.instanceStorage.GetByCustomDomain(*customDomain)
+	switch {
+	case dberr.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, errors.Wrap(err, "while getting instance by custom domain")
+	}
+
+	return instance.InstanceID != instanceID, nil
+}
+
+// isCustomDomainConflict returns true if the instance was rejected by the storage because its custom domain is already used
+func isCustomDomainConflict(err error, instance internal.Instance) bool {
+	dbErr, ok := err.(dberr.Error)
+	return ok && dbErr.Code() == dberr.CodeAlreadyExists && instance.CustomDomain() != ""
+}
+
+func customDomainClaimedError(customDomain string) error {
+	return errors.Errorf("the custom domain %s is already used by another instance", customDomain)
+}
@@ -0,0 +1,78 @@
+package broker
+
+import (
+	"io/ioutil"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	azureHyperscaler = "azure"
+	awsHyperscaler   = "aws"
+	gcpHyperscaler   = "gcp"
+)
+
+// gcpKeyRegex matches the Cloud KMS key resource name, e.g. projects/<project>/locations/<region>/keyRings/<ring>/cryptoKeys/<key>
+var gcpKeyRegex = regexp.MustCompile(`^projects/[a-z][a-z0-9-]{4,28}[a-z0-9]/locations/([a-z0-9-]+)/keyRings/[a-zA-Z0-9_-]{1,63}/cryptoKeys/[a-zA-Z0-9_-]{1,63}$`)
+
+// EncryptionKeyRegions maps a hyperscaler to the regions in which clusters can be provisioned
+// with disks encrypted by customer-managed keys
+type EncryptionKeyRegions map[string][]string
+
+// NewEncryptionKeyRegionsFromFile reads the regions supporting customer-managed keys from the YAML file,
+// empty path means no encryption key can be requested
+func NewEncryptionKeyRegionsFromFile(path string) (EncryptionKeyRegions, error) {
+	if path == "" {
+		return EncryptionKeyRegions{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with encryption key regions", path)
+	}
+	var regionsConfig struct {
+		Regions EncryptionKeyRegions `yaml:"regions"`
+	}
+	err = yaml.Unmarshal(yamlFile, &regionsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with encryption key regions")
+	}
+	if regionsConfig.Regions == nil {
+		return EncryptionKeyRegions{}, nil
+	}
+
+	return regionsConfig.Regions, nil
+}
+
+// Validate checks if the encryption key reference is a Cloud KMS key from the region of the cluster
+// and if customer-managed keys are supported in the region. The Provisioner applies the keys only
+// to GCP clusters, so the key is rejected for the other plans. No key means that platform-managed keys are used.
+func (r EncryptionKeyRegions) Validate(planID string, key, region *string) error {
+	if key == nil {
+		return nil
+	}
+	if planID != GCPPlanID {
+		return errors.New("customer-managed encryption keys are supported only for the GCP plan")
+	}
+	if region == nil || *region == "" {
+		return errors.New("region must be specified together with the encryption key")
+	}
+
+	matches := gcpKeyRegex.FindStringSubmatch(*key)
+	if matches == nil {
+		return errors.Errorf("encryption key %q is not a valid GCP Cloud KMS key name", *key)
+	}
+	// KMS keys are regional, the disks can be encrypted only with a key from the region of the cluster
+	if matches[1] != *region {
+		return errors.Errorf("encryption key from region %q cannot be used for a cluster in region %q", matches[1], *region)
+	}
+
+	for _, supported := range r[gcpHyperscaler] {
+		if supported == *region {
+			return nil
+		}
+	}
+
+	return errors.Errorf("customer-managed encryption keys are not supported in region %q", *region)
+}
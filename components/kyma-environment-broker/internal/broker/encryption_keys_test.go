@@ -0,0 +1,101 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptionKeyRegions_Validate(t *testing.T) {
+	// given
+	regions, err := NewEncryptionKeyRegionsFromFile("testdata/encryption_key_regions.yaml")
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		planID    string
+		key       *string
+		region    *string
+		expectErr bool
+	}{
+		"platform-managed key": {
+			planID: AzurePlanID,
+			region: ptr.String("eastus"),
+		},
+		"valid GCP key": {
+			planID: GCPPlanID,
+			key:    ptr.String("projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks"),
+			region: ptr.String("europe-west3"),
+		},
+		"invalid GCP key format": {
+			planID:    GCPPlanID,
+			key:       ptr.String("projects/kyma-project/keyRings/kyma/cryptoKeys/disks"),
+			region:    ptr.String("europe-west3"),
+			expectErr: true,
+		},
+		"key of other hyperscaler": {
+			planID:    GCPPlanID,
+			key:       ptr.String("arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			region:    ptr.String("europe-west3"),
+			expectErr: true,
+		},
+		"key from other region": {
+			planID:    GCPPlanID,
+			key:       ptr.String("projects/kyma-project/locations/us-east1/keyRings/kyma/cryptoKeys/disks"),
+			region:    ptr.String("europe-west3"),
+			expectErr: true,
+		},
+		"unsupported region": {
+			planID:    GCPPlanID,
+			key:       ptr.String("projects/kyma-project/locations/us-east1/keyRings/kyma/cryptoKeys/disks"),
+			region:    ptr.String("us-east1"),
+			expectErr: true,
+		},
+		"key without region": {
+			planID:    GCPPlanID,
+			key:       ptr.String("projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks"),
+			expectErr: true,
+		},
+		"Azure plan": {
+			planID:    AzurePlanID,
+			key:       ptr.String("https://kyma-vault.vault.azure.net/keys/disks"),
+			region:    ptr.String("westeurope"),
+			expectErr: true,
+		},
+		"AWS plan": {
+			planID:    AWSPlanID,
+			key:       ptr.String("arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			region:    ptr.String("eu-central-1"),
+			expectErr: true,
+		},
+		"unsupported plan": {
+			planID:    TrialPlanID,
+			key:       ptr.String("projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks"),
+			region:    ptr.String("europe-west3"),
+			expectErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := regions.Validate(tc.planID, tc.key, tc.region)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewEncryptionKeyRegionsFromFile_EmptyPath(t *testing.T) {
+	// when
+	regions, err := NewEncryptionKeyRegionsFromFile("")
+
+	// then
+	require.NoError(t, err)
+	assert.Error(t, regions.Validate(GCPPlanID, ptr.String("projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks"), ptr.String("europe-west3")))
+	assert.NoError(t, regions.Validate(GCPPlanID, nil, nil))
+}
@@ -0,0 +1,94 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+)
+
+// allowedIngressAnnotationPrefixes are the prefixes of the annotations which configure the load balancers of the cloud providers
+// and the DNS entries of the ingress gateway, other annotations of the service cannot be changed
+var allowedIngressAnnotationPrefixes = []string{
+	"service.beta.kubernetes.io/",
+	"service.kubernetes.io/",
+	"networking.gke.io/",
+	"cloud.google.com/",
+	"external-dns.alpha.kubernetes.io/",
+}
+
+// loadBalancerSKUHyperscalers maps the plans in which the load balancer SKU can be requested to their hyperscalers
+var loadBalancerSKUHyperscalers = map[string]string{
+	AzurePlanID:     azureHyperscaler,
+	AzureLitePlanID: azureHyperscaler,
+	AWSPlanID:       awsHyperscaler,
+	GCPPlanID:       gcpHyperscaler,
+	OpenStackPlanID: openstackHyperscaler,
+}
+
+// validateIngress checks the load balancer type, the load balancer SKU supported by the hyperscaler of the plan
+// and the annotations of the ingress gateway.
+// No configuration means that the ingress gateway is exposed with the default external load balancer.
+func validateIngress(planID string, ingress *internal.IngressConfig) error {
+	if ingress == nil {
+		return nil
+	}
+	if ingress.LoadBalancerType != nil {
+		switch *ingress.LoadBalancerType {
+		case internal.IngressLoadBalancerExternal, internal.IngressLoadBalancerInternal:
+		default:
+			return errors.Errorf("load balancer type %q is not supported, supported types: %s, %s",
+				*ingress.LoadBalancerType, internal.IngressLoadBalancerExternal, internal.IngressLoadBalancerInternal)
+		}
+	}
+	if err := validateLoadBalancerSKU(planID, ingress.LoadBalancerSKU); err != nil {
+		return err
+	}
+
+	if len(ingress.Annotations) > maxAnnotationsCount {
+		return errors.Errorf("too many ingress annotations: %d, at most %d are allowed", len(ingress.Annotations), maxAnnotationsCount)
+	}
+	for key, value := range ingress.Annotations {
+		if err := validateIngressAnnotationKey(key); err != nil {
+			return err
+		}
+		if len(value) > maxAnnotationValueLength {
+			return errors.Errorf("value of ingress annotation %q must be no more than %d characters", key, maxAnnotationValueLength)
+		}
+	}
+
+	return nil
+}
+
+func validateLoadBalancerSKU(planID string, sku *string) error {
+	if sku == nil {
+		return nil
+	}
+	hyperscaler, found := loadBalancerSKUHyperscalers[planID]
+	if !found {
+		return errors.New("load balancer SKU cannot be requested for the plan")
+	}
+	for _, supported := range internal.LoadBalancerSKUs[hyperscaler] {
+		if *sku == supported {
+			return nil
+		}
+	}
+	return errors.Errorf("load balancer SKU %q is not supported by %s, supported SKUs: %s",
+		*sku, hyperscaler, strings.Join(internal.LoadBalancerSKUs[hyperscaler], ", "))
+}
+
+func validateIngressAnnotationKey(key string) error {
+	for _, prefix := range allowedIngressAnnotationPrefixes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, prefix)
+		if len(name) == 0 || len(name) > maxAnnotationNameLength || !annotationNameRegex.MatchString(name) {
+			return errors.Errorf("ingress annotation %q has invalid name, it must consist of at most %d alphanumeric characters, '-', '_' or '.'", key, maxAnnotationNameLength)
+		}
+		return nil
+	}
+
+	return errors.Errorf("ingress annotation %q is not allowed, allowed prefixes: %s", key, strings.Join(allowedIngressAnnotationPrefixes, ", "))
+}
@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIngress(t *testing.T) {
+	tooMany := map[string]string{}
+	for i := 0; i <= maxAnnotationsCount; i++ {
+		tooMany[fmt.Sprintf("service.beta.kubernetes.io/key-%d", i)] = "value"
+	}
+
+	for name, tc := range map[string]struct {
+		planID    string
+		ingress   *internal.IngressConfig
+		expectErr bool
+	}{
+		"no ingress configuration": {},
+		"internal load balancer with annotations": {
+			ingress: &internal.IngressConfig{
+				LoadBalancerType: ptr.String(internal.IngressLoadBalancerInternal),
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/azure-load-balancer-internal-subnet": "ingress",
+					"external-dns.alpha.kubernetes.io/hostname":                      "kyma.example.com",
+				},
+			},
+		},
+		"external load balancer": {
+			ingress: &internal.IngressConfig{
+				LoadBalancerType: ptr.String(internal.IngressLoadBalancerExternal),
+			},
+		},
+		"annotations only": {
+			ingress: &internal.IngressConfig{
+				Annotations: map[string]string{"networking.gke.io/internal-load-balancer-allow-global-access": "true"},
+			},
+		},
+		"invalid load balancer type": {
+			ingress: &internal.IngressConfig{
+				LoadBalancerType: ptr.String("NodePort"),
+			},
+			expectErr: true,
+		},
+		"annotation with not allowed prefix": {
+			ingress: &internal.IngressConfig{
+				Annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+			},
+			expectErr: true,
+		},
+		"annotation without prefix": {
+			ingress: &internal.IngressConfig{
+				Annotations: map[string]string{"team": "kyma"},
+			},
+			expectErr: true,
+		},
+		"annotation with invalid name": {
+			ingress: &internal.IngressConfig{
+				Annotations: map[string]string{"service.beta.kubernetes.io/-invalid": "true"},
+			},
+			expectErr: true,
+		},
+		"annotation with too long value": {
+			ingress: &internal.IngressConfig{
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-extra-security-groups": strings.Repeat("v", maxAnnotationValueLength+1)},
+			},
+			expectErr: true,
+		},
+		"too many annotations": {
+			ingress:   &internal.IngressConfig{Annotations: tooMany},
+			expectErr: true,
+		},
+		"load balancer SKU supported by the hyperscaler": {
+			planID:  AWSPlanID,
+			ingress: &internal.IngressConfig{LoadBalancerSKU: ptr.String("nlb")},
+		},
+		"load balancer SKU not supported by the hyperscaler": {
+			planID:    AzurePlanID,
+			ingress:   &internal.IngressConfig{LoadBalancerSKU: ptr.String("basic")},
+			expectErr: true,
+		},
+		"load balancer SKU of another hyperscaler": {
+			planID:    GCPPlanID,
+			ingress:   &internal.IngressConfig{LoadBalancerSKU: ptr.String("nlb")},
+			expectErr: true,
+		},
+		"load balancer SKU in the plan without the SKU support": {
+			planID:    TrialPlanID,
+			ingress:   &internal.IngressConfig{LoadBalancerSKU: ptr.String("standard")},
+			expectErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			planID := tc.planID
+			if planID == "" {
+				planID = AzurePlanID
+			}
+			err := validateIngress(planID, tc.ingress)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/gardener"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
@@ -22,6 +24,7 @@ import (
 )
 
 //go:generate mockery -name=Queue -output=automock -outpkg=automock -case=underscore
+//go:generate mockery -name=ProvisioningQueue -output=automock -outpkg=automock -case=underscore
 //go:generate mockery -name=PlanValidator -output=automock -outpkg=automock -case=underscore
 
 type (
@@ -29,21 +32,50 @@ type (
 		Add(operationId string)
 	}
 
+	ProvisioningQueue interface {
+		AddWithPriority(operationId, subAccountID string, priority int)
+	}
+
 	PlanValidator interface {
 		IsPlanSupport(planID string) bool
 	}
+
+	ComponentTogglesValidator interface {
+		ValidateToggles(toggles *internal.ComponentToggles) error
+	}
+
+	InstanceLocks interface {
+		Holder(instanceID string) (string, bool)
+	}
 )
 
 type ProvisionEndpoint struct {
 	operationsStorage    storage.Provisioning
 	instanceStorage      storage.Instances
-	queue                Queue
+	queue                ProvisioningQueue
 	builderFactory       PlanValidator
 	enabledPlanIDs       map[string]struct{}
 	onlySingleTrialPerGA bool
 	plansConfig          PlansConfig
 	plansSchemaValidator PlansSchemaValidator
-	kymaVerOnDemand      bool
+	presets              ProvisioningPresets
+	allowedSeeds         AllowedSeeds
+	encryptionKeyRegions EncryptionKeyRegions
+	machineTypes         PlanMachineTypes
+	resourceQuotas       PlanResourceQuotas
+	parametersValidators PlanParametersValidators
+	rateLimiter          *SubaccountRateLimiter
+	nameTemplate         InstanceNameTemplate
+	featureFlags         featureflags.Provider
+
+	supportedKubernetesVersions []string
+	toggleableComponents        []string
+	// togglesValidator is optional, without it the dependencies between the toggled components are not checked
+	togglesValidator ComponentTogglesValidator
+	// singleInstancePlanIDs holds the plans in which the duplicated instances of a subaccount are rejected
+	singleInstancePlanIDs map[string]struct{}
+	blockedRegions        map[string]struct{}
+	defaultPriority       int
 
 	shootDomain  string
 	shootProject string
@@ -51,21 +83,51 @@ type ProvisionEndpoint struct {
 	log logrus.FieldLogger
 }
 
+// ProvisionOptions holds the optional dependencies of the provision endpoint, the zero values disable the related features
+type ProvisionOptions struct {
+	Presets              ProvisioningPresets
+	AllowedSeeds         AllowedSeeds
+	EncryptionKeyRegions EncryptionKeyRegions
+	MachineTypes         PlanMachineTypes
+	ResourceQuotas       PlanResourceQuotas
+	ParametersValidators PlanParametersValidators
+	// RateLimiter limits the provisioning requests per subaccount, the requests are not limited if nil
+	RateLimiter  *SubaccountRateLimiter
+	NameTemplate InstanceNameTemplate
+	// FeatureFlags enables the features of the provisioning, all features are disabled if nil
+	FeatureFlags featureflags.Provider
+}
+
 func NewProvision(cfg Config,
 	gardenerConfig gardener.Config,
 	operationsStorage storage.Operations,
 	instanceStorage storage.Instances,
-	queue Queue,
+	queue ProvisioningQueue,
 	builderFactory PlanValidator,
 	validator PlansSchemaValidator,
 	plansConfig PlansConfig,
-	kvod bool,
+	opts ProvisionOptions,
 	log logrus.FieldLogger) *ProvisionEndpoint {
+	if opts.RateLimiter == nil {
+		opts.RateLimiter = NewSubaccountRateLimiter(RateLimit{}, nil)
+	}
+	if opts.FeatureFlags == nil {
+		opts.FeatureFlags = featureflags.Static{}
+	}
+
 	enabledPlanIDs := map[string]struct{}{}
 	for _, planName := range cfg.EnablePlans {
 		id := PlanIDsMapping[planName]
 		enabledPlanIDs[id] = struct{}{}
 	}
+	singleInstancePlanIDs := map[string]struct{}{}
+	for _, planName := range cfg.SingleInstancePlans {
+		singleInstancePlanIDs[PlanIDsMapping[planName]] = struct{}{}
+	}
+	blockedRegions := map[string]struct{}{}
+	for _, region := range cfg.BlockedRegions {
+		blockedRegions[region] = struct{}{}
+	}
 
 	return &ProvisionEndpoint{
 		plansSchemaValidator: validator,
@@ -77,37 +139,58 @@ func NewProvision(cfg Config,
 		enabledPlanIDs:       enabledPlanIDs,
 		onlySingleTrialPerGA: cfg.OnlySingleTrialPerGA,
 		plansConfig:          plansConfig,
-		kymaVerOnDemand:      kvod,
+		presets:              opts.Presets,
+		allowedSeeds:         opts.AllowedSeeds,
+		encryptionKeyRegions: opts.EncryptionKeyRegions,
+		machineTypes:         opts.MachineTypes,
+		resourceQuotas:       opts.ResourceQuotas,
+		parametersValidators: opts.ParametersValidators,
+		rateLimiter:          opts.RateLimiter,
+		nameTemplate:         opts.NameTemplate,
+		featureFlags:         opts.FeatureFlags,
 		shootDomain:          gardenerConfig.ShootDomain,
 		shootProject:         gardenerConfig.Project,
+
+		supportedKubernetesVersions: cfg.SupportedKubernetesVersions,
+		toggleableComponents:        cfg.ToggleableComponents,
+		singleInstancePlanIDs:       singleInstancePlanIDs,
+		blockedRegions:              blockedRegions,
+		defaultPriority:             cfg.DefaultOperationPriority,
 	}
 }
 
+// SetComponentTogglesValidator sets the validator rejecting the component toggles which break the dependencies between components
+func (b *ProvisionEndpoint) SetComponentTogglesValidator(validator ComponentTogglesValidator) {
+	b.togglesValidator = validator
+}
+
 // Provision creates a new service instance
 //   PUT /v2/service_instances/{instance_id}
 func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, details domain.ProvisionDetails, asyncAllowed bool) (domain.ProvisionedServiceSpec, error) {
 	operationID := uuid.New().String()
-	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "operationID": operationID, "planID": details.PlanID})
+	correlationID, _ := middleware.CorrelationIDFromContext(ctx)
+	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "operationID": operationID, "planID": details.PlanID, "correlationID": correlationID})
 	logger.Info("Provision called")
-	// validation of incoming input
-	ersContext, parameters, err := b.validateAndExtract(details, logger)
-	if err != nil {
-		errMsg := fmt.Sprintf("[instanceID: %s] %s", instanceID, err)
-		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, errMsg)
-	}
-
 	region, found := middleware.RegionFromContext(ctx)
 	if !found {
 		err := errors.New("No region specified in request.")
 		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusInternalServerError, "provisioning")
 	}
 
+	// validation of incoming input
+	ersContext, parameters, err := b.validateAndExtract(details, region, logger)
+	if err != nil {
+		errMsg := fmt.Sprintf("[instanceID: %s] %s", instanceID, err)
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, errMsg)
+	}
+
 	provisioningParameters := internal.ProvisioningParameters{
 		PlanID:         details.PlanID,
 		ServiceID:      details.ServiceID,
 		ErsContext:     ersContext,
 		Parameters:     parameters,
 		PlatformRegion: region,
+		SchemaVersion:  DefaultParametersMigrations.CurrentVersion(details.PlanID),
 	}
 
 	logger.Infof("Starting provisioning runtime: Name=%s, GlobalAccountID=%s, SubAccountID=%s PlatformRegion=%s", parameters.Name, ersContext.GlobalAccountID, ersContext.SubAccountID, region)
@@ -123,6 +206,41 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		return b.handleExistingOperation(existingOperation, provisioningParameters, logger)
 	}
 
+	// checked after the existing operation lookup, so the repeated requests for already provisioned instances still succeed
+	if err := validateRegionNotBlocked(parameters.Region, b.blockedRegions); err != nil {
+		logger.Infof("Provisioning rejected: %s", err)
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "provisioning")
+	}
+
+	// the concurrent requests can all pass this check, the duplicated instance is rejected by the storage on insert as well
+	duplicated, err := b.isDuplicatedInstance(ersContext.SubAccountID, details.PlanID, parameters.AllowMultiple)
+	if err != nil {
+		logger.Errorf("cannot check existing instances: %s", err)
+		return domain.ProvisionedServiceSpec{}, errors.New("cannot check existing instances")
+	}
+	if duplicated {
+		logger.Infof("Provisioning rejected, subaccount %s already has an instance of the plan", ersContext.SubAccountID)
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(duplicatedInstanceError(details.PlanID), http.StatusConflict, "provisioning")
+	}
+
+	// the same as for the duplicated instance, the custom domain claimed concurrently is rejected by the unique index in the storage
+	claimed, err := b.isCustomDomainClaimed(parameters.CustomDomain, instanceID)
+	if err != nil {
+		logger.Errorf("cannot check custom domain: %s", err)
+		return domain.ProvisionedServiceSpec{}, errors.New("cannot check custom domain")
+	}
+	if claimed {
+		logger.Infof("Provisioning rejected, custom domain %s is used by another instance", *parameters.CustomDomain)
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(customDomainClaimedError(*parameters.CustomDomain), http.StatusConflict, "provisioning")
+	}
+
+	if allowed, delay := b.rateLimiter.Allow(ersContext.SubAccountID); !allowed {
+		logger.Infof("Provisioning rejected, rate limit for subaccount %s exceeded", ersContext.SubAccountID)
+		middleware.SetRetryAfter(ctx, delay)
+		err := errors.Errorf("too many provisioning requests for the subaccount, retry after %s", delay.Round(time.Second))
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusTooManyRequests, "provisioning")
+	}
+
 	// create SKR shoot name
 	shootName := gardener.CreateShootName()
 	dashboardURL := fmt.Sprintf("https://console.%s.%s.%s", shootName, b.shootProject, strings.Trim(b.shootDomain, "."))
@@ -134,15 +252,10 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		return domain.ProvisionedServiceSpec{}, errors.New("cannot create new operation")
 	}
 	operation.ShootName = shootName
+	operation.CorrelationID = correlationID
 	operation.ShootDomain = fmt.Sprintf("%s.%s.%s", shootName, b.shootProject, strings.Trim(b.shootDomain, "."))
 
-	err = b.operationsStorage.InsertProvisioningOperation(operation)
-	if err != nil {
-		logger.Errorf("cannot save operation: %s", err)
-		return domain.ProvisionedServiceSpec{}, errors.New("cannot save operation")
-	}
-
-	err = b.instanceStorage.Insert(internal.Instance{
+	instance := internal.Instance{
 		InstanceID:      instanceID,
 		GlobalAccountID: ersContext.GlobalAccountID,
 		SubAccountID:    ersContext.SubAccountID,
@@ -152,14 +265,25 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		ServicePlanName: Plans(b.plansConfig)[provisioningParameters.PlanID].PlanDefinition.Name,
 		DashboardURL:    dashboardURL,
 		Parameters:      operation.ProvisioningParameters,
-	})
+	}
+	switch {
+	case b.requiresSingleInstance(details.PlanID, parameters.AllowMultiple):
+		err = b.saveSingleInstance(instance, operation, logger)
+	case instance.CustomDomain() != "":
+		err = b.saveInstanceWithCustomDomain(instance, operation, logger)
+	default:
+		err = b.saveInstance(instance, operation, logger)
+	}
 	if err != nil {
-		logger.Errorf("cannot save instance in storage: %s", err)
-		return domain.ProvisionedServiceSpec{}, errors.New("cannot save instance")
+		return domain.ProvisionedServiceSpec{}, err
 	}
 
-	logger.Info("Adding operation to provisioning queue")
-	b.queue.Add(operation.ID)
+	priority, found := middleware.OperationPriorityFromContext(ctx)
+	if !found {
+		priority = b.defaultPriority
+	}
+	logger.Infof("Adding operation to provisioning queue with priority %d", priority)
+	b.queue.AddWithPriority(operation.ID, provisioningParameters.ErsContext.SubAccountID, priority)
 
 	return domain.ProvisionedServiceSpec{
 		IsAsync:       true,
@@ -171,7 +295,79 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 	}, nil
 }
 
-func (b *ProvisionEndpoint) validateAndExtract(details domain.ProvisionDetails, l logrus.FieldLogger) (internal.ERSContext, internal.ProvisioningParametersDTO, error) {
+// saveInstance saves the operation followed by the instance
+func (b *ProvisionEndpoint) saveInstance(instance internal.Instance, operation internal.ProvisioningOperation, logger logrus.FieldLogger) error {
+	err := b.operationsStorage.InsertProvisioningOperation(operation)
+	if err != nil {
+		logger.Errorf("cannot save operation: %s", err)
+		return errors.New("cannot save operation")
+	}
+
+	err = b.instanceStorage.Insert(instance)
+	if err != nil {
+		logger.Errorf("cannot save instance in storage: %s", err)
+		return errors.New("cannot save instance")
+	}
+
+	return nil
+}
+
+// saveSingleInstance saves the instance unless it is the duplicated instance of the subaccount in the plan, followed by the operation.
+// The instance is saved first, so the rejected duplicated instance leaves no operation behind. The instance is removed
+// if the operation cannot be saved, otherwise it would be counted as the active instance and reject the retried request.
+func (b *ProvisionEndpoint) saveSingleInstance(instance internal.Instance, operation internal.ProvisioningOperation, logger logrus.FieldLogger) error {
+	inserted, err := b.instanceStorage.InsertUnlessDuplicated(instance)
+	if isCustomDomainConflict(err, instance) {
+		logger.Infof("Provisioning rejected, custom domain %s is used by another instance", instance.CustomDomain())
+		return apiresponses.NewFailureResponse(customDomainClaimedError(instance.CustomDomain()), http.StatusConflict, "provisioning")
+	}
+	if err != nil {
+		logger.Errorf("cannot save instance in storage: %s", err)
+		return errors.New("cannot save instance")
+	}
+	if !inserted {
+		logger.Infof("Provisioning rejected, subaccount %s already has an instance of the plan", instance.SubAccountID)
+		return apiresponses.NewFailureResponse(duplicatedInstanceError(instance.ServicePlanID), http.StatusConflict, "provisioning")
+	}
+
+	err = b.operationsStorage.InsertProvisioningOperation(operation)
+	if err != nil {
+		logger.Errorf("cannot save operation: %s", err)
+		if err := b.instanceStorage.Delete(instance.InstanceID); err != nil {
+			logger.Errorf("cannot remove instance without operation: %s", err)
+		}
+		return errors.New("cannot save operation")
+	}
+
+	return nil
+}
+
+// saveInstanceWithCustomDomain saves the instance unless its custom domain is used by another instance, followed by the operation.
+// Same as for the single instance, the instance is saved first, so the rejected instance leaves no operation behind.
+func (b *ProvisionEndpoint) saveInstanceWithCustomDomain(instance internal.Instance, operation internal.ProvisioningOperation, logger logrus.FieldLogger) error {
+	err := b.instanceStorage.Insert(instance)
+	if isCustomDomainConflict(err, instance) {
+		logger.Infof("Provisioning rejected, custom domain %s is used by another instance", instance.CustomDomain())
+		return apiresponses.NewFailureResponse(customDomainClaimedError(instance.CustomDomain()), http.StatusConflict, "provisioning")
+	}
+	if err != nil {
+		logger.Errorf("cannot save instance in storage: %s", err)
+		return errors.New("cannot save instance")
+	}
+
+	err = b.operationsStorage.InsertProvisioningOperation(operation)
+	if err != nil {
+		logger.Errorf("cannot save operation: %s", err)
+		if err := b.instanceStorage.Delete(instance.InstanceID); err != nil {
+			logger.Errorf("cannot remove instance without operation: %s", err)
+		}
+		return errors.New("cannot save operation")
+	}
+
+	return nil
+}
+
+func (b *ProvisionEndpoint) validateAndExtract(details domain.ProvisionDetails, platformRegion string, l logrus.FieldLogger) (internal.ERSContext, internal.ProvisioningParametersDTO, error) {
 	var ersContext internal.ERSContext
 	var parameters internal.ProvisioningParametersDTO
 
@@ -182,14 +378,11 @@ func (b *ProvisionEndpoint) validateAndExtract(details domain.ProvisionDetails,
 		return ersContext, parameters, errors.Errorf("plan ID %q is not recognized", details.PlanID)
 	}
 
-	result, err := b.plansSchemaValidator[details.PlanID].ValidateString(string(details.RawParameters))
+	rawParameters, presetName, err := b.presets.Resolve(details.RawParameters)
 	if err != nil {
-		return ersContext, parameters, errors.Wrap(err, "while executing JSON schema validator")
-	}
-
-	if !result.Valid {
-		return ersContext, parameters, errors.Wrapf(result.Error, "while validating input parameters")
+		return ersContext, parameters, errors.Wrap(err, "while resolving provisioning preset")
 	}
+	details.RawParameters = rawParameters
 
 	ersContext, err = b.extractERSContext(details)
 	logger := l.WithField("globalAccountID", ersContext.GlobalAccountID)
@@ -197,16 +390,80 @@ func (b *ProvisionEndpoint) validateAndExtract(details domain.ProvisionDetails,
 		return ersContext, parameters, errors.Wrap(err, "while extracting ers context")
 	}
 
+	// the name is derived before the schema validation, so the name required by the schema can be omitted
+	details.RawParameters, err = b.nameTemplate.Apply(details.RawParameters, InstanceNameData{
+		GlobalAccountID: ersContext.GlobalAccountID,
+		SubAccountID:    ersContext.SubAccountID,
+		PlanName:        PlanNamesMapping[details.PlanID],
+		Region:          platformRegion,
+	})
+	if err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while applying instance name template")
+	}
+
+	result, err := b.plansSchemaValidator[details.PlanID].ValidateString(string(details.RawParameters))
+	if err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while executing JSON schema validator")
+	}
+
+	if !result.Valid {
+		return ersContext, parameters, errors.Wrapf(result.Error, "while validating input parameters")
+	}
+
 	parameters, err = b.extractInputParameters(details)
 	if err != nil {
 		return ersContext, parameters, errors.Wrap(err, "while extracting input parameters")
 	}
+	if err := b.parametersValidators.Validate(details.PlanID, parameters); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating input parameters")
+	}
 
-	if !b.kymaVerOnDemand && parameters.KymaVersion != "" {
+	if !b.featureFlags.IsEnabled(featureflags.OnDemandVersion) && parameters.KymaVersion != "" {
 		logger.Infof("Kyma on demand functionality is disabled. Default Kyma version will be used instead %s", parameters.KymaVersion)
 		parameters.KymaVersion = ""
 	}
+	if err := validateAnnotations(parameters.Annotations); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating annotations")
+	}
+	if err := b.allowedSeeds.Validate(parameters.Seed, parameters.Region); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating seed")
+	}
+	if err := b.encryptionKeyRegions.Validate(details.PlanID, parameters.EncryptionKey, parameters.Region); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating encryption key")
+	}
+	if err := validateKubernetesVersion(parameters.KubernetesVersion, b.supportedKubernetesVersions); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating Kubernetes version")
+	}
+	if err := validatePrivateCluster(parameters.PrivateCluster, parameters.AllowedCIDRs); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating private cluster")
+	}
+	parameters.CustomDomain, err = normalizeCustomDomain(parameters.CustomDomain)
+	if err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating custom domain")
+	}
+	parameters.ComponentToggles, err = validateComponentToggles(parameters.ComponentToggles, b.toggleableComponents)
+	if err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating component toggles")
+	}
+	if b.togglesValidator != nil {
+		if err := b.togglesValidator.ValidateToggles(parameters.ComponentToggles); err != nil {
+			return ersContext, parameters, errors.Wrap(err, "while validating component toggles")
+		}
+	}
+	if err := validateIngress(details.PlanID, parameters.Ingress); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating ingress")
+	}
+	if err := b.resourceQuotas.Validate(details.PlanID, parameters.ResourceQuota); err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating resource quota")
+	}
+	machineType, err := b.machineTypes.Apply(details.PlanID, parameters.MachineType)
+	if err != nil {
+		return ersContext, parameters, errors.Wrap(err, "while validating machine type")
+	}
+	parameters.MachineType = machineType
 	parameters.LicenceType = b.determineLicenceType(details.PlanID)
+	parameters.ControlPlaneHA = ControlPlaneHAOrDefault(details.PlanID, parameters.ControlPlaneHA)
+	parameters.Preset = presetName
 
 	found := b.builderFactory.IsPlanSupport(details.PlanID)
 	if !found {
@@ -228,6 +485,33 @@ func (b *ProvisionEndpoint) validateAndExtract(details domain.ProvisionDetails,
 	return ersContext, parameters, nil
 }
 
+// isDuplicatedInstance returns true if the subaccount already has an active instance of the plan which allows only one instance
+// per subaccount, the instances being deprovisioned are not taken into account. The allowMultiple parameter overrides the check.
+func (b *ProvisionEndpoint) isDuplicatedInstance(subAccountID, planID string, allowMultiple *bool) (bool, error) {
+	if !b.requiresSingleInstance(planID, allowMultiple) {
+		return false, nil
+	}
+
+	count, err := b.instanceStorage.GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID)
+	if err != nil {
+		return false, errors.Wrap(err, "while counting instances of the subaccount")
+	}
+
+	return count > 0, nil
+}
+
+// requiresSingleInstance returns true if the plan allows only one instance per subaccount and the allowMultiple parameter does not override it
+func (b *ProvisionEndpoint) requiresSingleInstance(planID string, allowMultiple *bool) bool {
+	if _, enabled := b.singleInstancePlanIDs[planID]; !enabled {
+		return false
+	}
+	return allowMultiple == nil || !*allowMultiple
+}
+
+func duplicatedInstanceError(planID string) error {
+	return errors.Errorf("the subaccount already has an instance of the %s plan, set the allowMultiple parameter to provision another one", PlanNamesMapping[planID])
+}
+
 func (b *ProvisionEndpoint) extractERSContext(details domain.ProvisionDetails) (internal.ERSContext, error) {
 	var ersContext internal.ERSContext
 	err := json.Unmarshal(details.RawContext, &ersContext)
@@ -3,21 +3,28 @@ package broker_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/compass/components/director/pkg/jsonschema"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/gardener"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -43,8 +50,8 @@ func TestProvision_Provision(t *testing.T) {
 		// #setup memory storage
 		memoryStorage := storage.NewMemoryStorage()
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		factoryBuilder := &automock.PlanValidator{}
 		factoryBuilder.On("IsPlanSupport", planID).Return(true)
@@ -59,8 +66,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -96,6 +102,117 @@ func TestProvision_Provision(t *testing.T) {
 		assert.Equal(t, instance.GlobalAccountID, globalAccountID)
 	})
 
+	t.Run("instance name follows the name template", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		nameTemplate, err := broker.NewInstanceNameTemplate("{{.PlanName}}-{{.Region}}")
+		require.NoError(t, err)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				NameTemplate: nameTemplate,
+			},
+			logrus.StandardLogger(),
+		)
+		provision := func(instanceID, rawParameters string) (domain.ProvisionedServiceSpec, error) {
+			return provisionEndpoint.Provision(fixReqCtxWithRegion(t, "req-region"), instanceID, domain.ProvisionDetails{
+				ServiceID:     serviceID,
+				PlanID:        planID,
+				RawParameters: json.RawMessage(rawParameters),
+				RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+			}, true)
+		}
+
+		// when
+		response, err := provision("derived-name", `{"region": "westeurope"}`)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "azure-westeurope", response.Metadata.Labels["Name"])
+		instance, err := memoryStorage.Instances().GetByID("derived-name")
+		require.NoError(t, err)
+		assert.Equal(t, "azure-westeurope", instance.Parameters.Parameters.Name)
+
+		// when
+		response, err = provision("specified-name", `{"name": "azure-westeurope-dev", "region": "westeurope"}`)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "azure-westeurope-dev", response.Metadata.Labels["Name"])
+
+		// when
+		_, err = provision("violating-name", fmt.Sprintf(`{"name": "%s", "region": "westeurope"}`, clusterName))
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, apiErr.ValidatedStatusCode(nil))
+		_, err = memoryStorage.Instances().GetByID("violating-name")
+		assert.True(t, dberr.IsNotFound(err))
+	})
+
+	t.Run("operation will be queued with the requested or the default priority", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			ctx      context.Context
+			expected int
+		}{
+			"default":   {ctx: fixReqCtxWithRegion(t, "req-region"), expected: 5},
+			"requested": {ctx: middleware.WithOperationPriority(fixReqCtxWithRegion(t, "req-region"), 20), expected: 20},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				memoryStorage := storage.NewMemoryStorage()
+
+				queue := &automock.ProvisioningQueue{}
+				queue.On("AddWithPriority", mock.AnythingOfType("string"), subAccountID, tc.expected).Once()
+
+				factoryBuilder := &automock.PlanValidator{}
+				factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+				provisionEndpoint := broker.NewProvision(
+					broker.Config{EnablePlans: []string{"gcp", "azure"}, DefaultOperationPriority: 5},
+					gardener.Config{Project: "test", ShootDomain: "example.com"},
+					memoryStorage.Operations(),
+					memoryStorage.Instances(),
+					queue,
+					factoryBuilder,
+					fixAlwaysPassJSONValidator(),
+					broker.PlansConfig{},
+					broker.ProvisionOptions{},
+					logrus.StandardLogger(),
+				)
+
+				// when
+				_, err := provisionEndpoint.Provision(tc.ctx, instanceID, domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s"}`, clusterName)),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+
+				// then
+				require.NoError(t, err)
+				queue.AssertExpectations(t)
+			})
+		}
+	})
+
 	t.Run("existing operation ID will be return", func(t *testing.T) {
 		// given
 		// #setup memory storage
@@ -116,7 +233,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -159,7 +276,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -188,8 +305,8 @@ func TestProvision_Provision(t *testing.T) {
 		})
 		assert.NoError(t, err)
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		factoryBuilder := &automock.PlanValidator{}
 		factoryBuilder.On("IsPlanSupport", broker.TrialPlanID).Return(true)
@@ -203,7 +320,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -245,8 +362,8 @@ func TestProvision_Provision(t *testing.T) {
 			ServicePlanID:   broker.TrialPlanID,
 		})
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		factoryBuilder := &automock.PlanValidator{}
 		factoryBuilder.On("IsPlanSupport", broker.TrialPlanID).Return(true)
@@ -260,7 +377,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -314,7 +431,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -341,8 +458,8 @@ func TestProvision_Provision(t *testing.T) {
 		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
 		require.NoError(t, err)
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		provisionEndpoint := broker.NewProvision(
 			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
@@ -353,7 +470,9 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixValidator,
 			broker.PlansConfig{},
-			true,
+			broker.ProvisionOptions{
+				FeatureFlags: featureflags.Static{featureflags.OnDemandVersion: true},
+			},
 			logrus.StandardLogger(),
 		)
 
@@ -393,7 +512,9 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixValidator,
 			broker.PlansConfig{},
-			true,
+			broker.ProvisionOptions{
+				FeatureFlags: featureflags.Static{featureflags.OnDemandVersion: true},
+			},
 			logrus.StandardLogger(),
 		)
 
@@ -419,8 +540,8 @@ func TestProvision_Provision(t *testing.T) {
 		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
 		require.NoError(t, err)
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		provisionEndpoint := broker.NewProvision(
 			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
@@ -431,7 +552,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixValidator,
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -464,8 +585,8 @@ func TestProvision_Provision(t *testing.T) {
 		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
 		require.NoError(t, err)
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		provisionEndpoint := broker.NewProvision(
 			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
@@ -476,7 +597,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixValidator,
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -506,8 +627,8 @@ func TestProvision_Provision(t *testing.T) {
 		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
 		require.NoError(t, err)
 
-		queue := &automock.Queue{}
-		queue.On("Add", mock.AnythingOfType("string"))
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
 		provisionEndpoint := broker.NewProvision(
 			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite", "trial"}, OnlySingleTrialPerGA: true},
@@ -518,7 +639,7 @@ func TestProvision_Provision(t *testing.T) {
 			factoryBuilder,
 			fixValidator,
 			broker.PlansConfig{},
-			false,
+			broker.ProvisionOptions{},
 			logrus.StandardLogger(),
 		)
 
@@ -537,53 +658,1169 @@ func TestProvision_Provision(t *testing.T) {
 
 		assert.Equal(t, ptr.String(internal.LicenceTypeLite), operation.ProvisioningParameters.Parameters.LicenceType)
 	})
-}
 
-func fixExistOperation() internal.ProvisioningOperation {
-	provisioningOperation := fixture.FixProvisioningOperation(existOperationID, instanceID)
-	provisioningOperation.ProvisioningParameters = internal.ProvisioningParameters{
-		PlanID:    planID,
-		ServiceID: serviceID,
-		ErsContext: internal.ERSContext{
-			SubAccountID:    subAccountID,
-			GlobalAccountID: globalAccountID,
-		},
-		Parameters: internal.ProvisioningParametersDTO{
-			Name: clusterName,
-		},
-		PlatformRegion: region,
-	}
+	t.Run("preset parameters should be resolved and recorded in the operation", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
 
-	return provisioningOperation
-}
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
 
-func fixAlwaysPassJSONValidator() broker.PlansSchemaValidator {
-	validatorMock := &automock.JSONSchemaValidator{}
-	validatorMock.On("ValidateString", mock.Anything).Return(jsonschema.ValidationResult{Valid: true}, nil)
+		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
+		require.NoError(t, err)
 
-	fixValidator := broker.PlansSchemaValidator{
-		broker.GCPPlanID:   validatorMock,
-		broker.AzurePlanID: validatorMock,
-		broker.TrialPlanID: validatorMock,
-	}
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
 
-	return fixValidator
-}
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixValidator,
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				Presets: fixProvisioningPresets(),
+			},
+			logrus.StandardLogger(),
+		)
 
-func fixInstance() internal.Instance {
-	return fixture.FixInstance(instanceID)
-}
+		// when
+		response, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "preset": "small", "autoScalerMax": 20}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+		require.NoError(t, err)
 
-func fixReqCtxWithRegion(t *testing.T, region string) context.Context {
-	t.Helper()
+		// then
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(response.OperationData)
+		require.NoError(t, err)
 
-	req, err := http.NewRequest("GET", "http://url.io", nil)
-	require.NoError(t, err)
-	var ctx context.Context
-	spyHandler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
-		ctx = req.Context()
+		parameters := operation.ProvisioningParameters.Parameters
+		assert.Equal(t, "small", parameters.Preset)
+		assert.Equal(t, ptr.String("westeurope"), parameters.Region)
+		assert.Equal(t, ptr.Integer(2), parameters.AutoScalerMin)
+		assert.Equal(t, ptr.Integer(20), parameters.AutoScalerMax)
 	})
 
-	middleware.AddRegionToContext(region).Middleware(spyHandler).ServeHTTP(httptest.NewRecorder(), req)
-	return ctx
+	t.Run("should reject unknown preset", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				Presets: fixProvisioningPresets(),
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "preset": "unknown"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while resolving provisioning preset: provisioning preset "unknown" does not exist`)
+	})
+
+	t.Run("should limit provisioning requests per subaccount", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				RateLimiter: broker.NewSubaccountRateLimiter(broker.RateLimit{Interval: time.Hour, Burst: 1}, nil),
+			},
+			logrus.StandardLogger(),
+		)
+		provision := func(instanceID, subAccountID string) error {
+			_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+				ServiceID:     serviceID,
+				PlanID:        planID,
+				RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s"}`, clusterName)),
+				RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+			}, true)
+			return err
+		}
+
+		// when
+		err := provision(instanceID, subAccountID)
+
+		// then
+		require.NoError(t, err)
+
+		// when
+		err = provision(otherInstanceID, subAccountID)
+
+		// then
+		require.Error(t, err)
+		failure, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusTooManyRequests, failure.ValidatedStatusCode(nil))
+
+		// when
+		err = provision(otherInstanceID, "other-subaccount")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject provisioning in a blocked region", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		existingOperation := fixExistOperation()
+		existingOperation.ProvisioningParameters.Parameters.Region = ptr.String("westeurope")
+		err := memoryStorage.Operations().InsertProvisioningOperation(existingOperation)
+		require.NoError(t, err)
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true, BlockedRegions: []string{"westeurope"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+		provision := func(instanceID string, hyperscalerRegion string) (domain.ProvisionedServiceSpec, error) {
+			return provisionEndpoint.Provision(fixReqCtxWithRegion(t, region), instanceID, domain.ProvisionDetails{
+				ServiceID:     serviceID,
+				PlanID:        planID,
+				RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s"}`, clusterName, hyperscalerRegion)),
+				RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+			}, true)
+		}
+
+		// when
+		_, err = provision(otherInstanceID, "westeurope")
+
+		// then
+		require.Error(t, err)
+		failure, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, failure.ValidatedStatusCode(nil))
+		assert.Contains(t, err.Error(), "being decommissioned")
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(otherInstanceID)
+		assert.True(t, dberr.IsNotFound(err))
+
+		// when
+		response, err := provision(instanceID, "westeurope")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.AlreadyExists)
+		assert.Equal(t, existOperationID, response.OperationData)
+
+		// when
+		_, err = provision(otherInstanceID, "northeurope")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject duplicated instance of the subaccount in the plan", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			singleInstancePlans broker.EnablePlans
+			rawParameters       string
+			deprovisioningState domain.LastOperationState
+			suspended           bool
+			expectedStatusCode  int
+		}{
+			"duplicated instance": {
+				singleInstancePlans: broker.EnablePlans{"azure"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s"}`, clusterName),
+				expectedStatusCode:  http.StatusConflict,
+			},
+			"allowed multiple instances": {
+				singleInstancePlans: broker.EnablePlans{"azure"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s", "allowMultiple": true}`, clusterName),
+			},
+			"check disabled for the plan": {
+				singleInstancePlans: broker.EnablePlans{"gcp"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s"}`, clusterName),
+			},
+			"instance being deprovisioned": {
+				singleInstancePlans: broker.EnablePlans{"azure"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s"}`, clusterName),
+				deprovisioningState: domain.InProgress,
+			},
+			"instance which deprovisioning failed": {
+				singleInstancePlans: broker.EnablePlans{"azure"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s"}`, clusterName),
+				deprovisioningState: domain.Failed,
+				expectedStatusCode:  http.StatusConflict,
+			},
+			"suspended instance": {
+				singleInstancePlans: broker.EnablePlans{"azure"},
+				rawParameters:       fmt.Sprintf(`{"name": "%s"}`, clusterName),
+				deprovisioningState: domain.Succeeded,
+				suspended:           true,
+				expectedStatusCode:  http.StatusConflict,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				memoryStorage := storage.NewMemoryStorage()
+				instance := fixInstance()
+				instance.InstanceID = otherInstanceID
+				instance.SubAccountID = subAccountID
+				instance.ServicePlanID = planID
+				err := memoryStorage.Instances().Insert(instance)
+				require.NoError(t, err)
+				if tc.deprovisioningState != "" {
+					deprovisioning := fixture.FixDeprovisioningOperation("deprovisioning-id", otherInstanceID)
+					deprovisioning.State = tc.deprovisioningState
+					deprovisioning.Temporary = tc.suspended
+					err = memoryStorage.Operations().InsertDeprovisioningOperation(deprovisioning)
+					require.NoError(t, err)
+				}
+
+				queue := &automock.ProvisioningQueue{}
+				queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+				factoryBuilder := &automock.PlanValidator{}
+				factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+				provisionEndpoint := broker.NewProvision(
+					broker.Config{EnablePlans: []string{"gcp", "azure"}, SingleInstancePlans: tc.singleInstancePlans},
+					gardener.Config{Project: "test", ShootDomain: "example.com"},
+					memoryStorage.Operations(),
+					memoryStorage.Instances(),
+					queue,
+					factoryBuilder,
+					fixAlwaysPassJSONValidator(),
+					broker.PlansConfig{},
+					broker.ProvisionOptions{},
+					logrus.StandardLogger(),
+				)
+
+				// when
+				_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(tc.rawParameters),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+
+				// then
+				if tc.expectedStatusCode == 0 {
+					require.NoError(t, err)
+					return
+				}
+				require.Error(t, err)
+				failure, ok := err.(*apiresponses.FailureResponse)
+				require.True(t, ok)
+				assert.Equal(t, tc.expectedStatusCode, failure.ValidatedStatusCode(nil))
+
+				_, err = memoryStorage.Instances().GetByID(instanceID)
+				assert.True(t, dberr.IsNotFound(err))
+				_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+				assert.True(t, dberr.IsNotFound(err))
+			})
+		}
+	})
+
+	t.Run("should provision only one of the concurrent instances of the subaccount in the plan", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}, SingleInstancePlans: broker.EnablePlans{"azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			slowCountingInstances{Instances: memoryStorage.Instances()},
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+			conflicts int
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), fmt.Sprintf("instance-%d", i), domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s"}`, clusterName)),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					succeeded++
+				} else if failure, ok := err.(*apiresponses.FailureResponse); ok && failure.ValidatedStatusCode(nil) == http.StatusConflict {
+					conflicts++
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, 1, succeeded)
+		assert.Equal(t, 4, conflicts)
+		operations := 0
+		for i := 0; i < 5; i++ {
+			if _, err := memoryStorage.Operations().GetProvisioningOperationByInstanceID(fmt.Sprintf("instance-%d", i)); err == nil {
+				operations++
+			}
+		}
+		assert.Equal(t, 1, operations)
+	})
+
+	t.Run("should remove the single instance of the subaccount when the operation cannot be saved", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		newProvisionEndpoint := func(operations storage.Operations) *broker.ProvisionEndpoint {
+			return broker.NewProvision(
+				broker.Config{EnablePlans: []string{"gcp", "azure"}, SingleInstancePlans: broker.EnablePlans{"azure"}},
+				gardener.Config{Project: "test", ShootDomain: "example.com"},
+				operations,
+				memoryStorage.Instances(),
+				queue,
+				factoryBuilder,
+				fixAlwaysPassJSONValidator(),
+				broker.PlansConfig{},
+				broker.ProvisionOptions{},
+				logrus.StandardLogger(),
+			)
+		}
+		details := domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}
+
+		// when
+		_, err := newProvisionEndpoint(failingOperations{Operations: memoryStorage.Operations()}).Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, details, true)
+
+		// then
+		require.EqualError(t, err, "cannot save operation")
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+
+		// when
+		_, err = newProvisionEndpoint(memoryStorage.Operations()).Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, details, true)
+
+		// then
+		require.NoError(t, err)
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject custom domain used by another instance", func(t *testing.T) {
+		for name, customDomain := range map[string]string{
+			"the same domain":              "kyma.example.com",
+			"the domain in other case":     "Kyma.Example.COM",
+			"the domain with trailing dot": "kyma.example.com.",
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				memoryStorage := storage.NewMemoryStorage()
+				instance := fixInstance()
+				instance.InstanceID = otherInstanceID
+				instance.Parameters.Parameters.CustomDomain = ptr.String("kyma.example.com")
+				err := memoryStorage.Instances().Insert(instance)
+				require.NoError(t, err)
+
+				queue := &automock.ProvisioningQueue{}
+				factoryBuilder := &automock.PlanValidator{}
+				factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+				provisionEndpoint := broker.NewProvision(
+					broker.Config{EnablePlans: []string{"gcp", "azure"}},
+					gardener.Config{Project: "test", ShootDomain: "example.com"},
+					memoryStorage.Operations(),
+					memoryStorage.Instances(),
+					queue,
+					factoryBuilder,
+					fixAlwaysPassJSONValidator(),
+					broker.PlansConfig{},
+					broker.ProvisionOptions{},
+					logrus.StandardLogger(),
+				)
+
+				// when
+				_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "customDomain": "%s"}`, clusterName, customDomain)),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+
+				// then
+				require.Error(t, err)
+				failure, ok := err.(*apiresponses.FailureResponse)
+				require.True(t, ok)
+				assert.Equal(t, http.StatusConflict, failure.ValidatedStatusCode(nil))
+				queue.AssertNotCalled(t, "AddWithPriority", mock.Anything, mock.Anything, mock.Anything)
+
+				_, err = memoryStorage.Instances().GetByID(instanceID)
+				assert.True(t, dberr.IsNotFound(err))
+				_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+				assert.True(t, dberr.IsNotFound(err))
+			})
+		}
+	})
+
+	t.Run("should reject custom domain claimed concurrently by another instance", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		instance := fixInstance()
+		instance.InstanceID = otherInstanceID
+		instance.Parameters.Parameters.CustomDomain = ptr.String("kyma.example.com")
+		err := memoryStorage.Instances().Insert(instance)
+		require.NoError(t, err)
+
+		queue := &automock.ProvisioningQueue{}
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			unclaimedCustomDomainInstances{Instances: memoryStorage.Instances()},
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "customDomain": "kyma.example.com"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		require.Error(t, err)
+		failure, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusConflict, failure.ValidatedStatusCode(nil))
+
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+	})
+
+	t.Run("should allow custom domain released by the deprovisioned instance", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		instance := fixInstance()
+		instance.InstanceID = otherInstanceID
+		instance.Parameters.Parameters.CustomDomain = ptr.String("kyma.example.com")
+		err := memoryStorage.Instances().Insert(instance)
+		require.NoError(t, err)
+		// the instance is removed at the end of the deprovisioning
+		err = memoryStorage.Instances().Delete(otherInstanceID)
+		require.NoError(t, err)
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "customDomain": "Kyma.Example.com"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		require.NoError(t, err)
+		inst, err := memoryStorage.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, "kyma.example.com", inst.CustomDomain())
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject custom domain which is not a valid domain name", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		memoryStorage := storage.NewMemoryStorage()
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			&automock.ProvisioningQueue{},
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "customDomain": "kyma_example.com"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		require.Error(t, err)
+		failure, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, failure.ValidatedStatusCode(nil))
+		assert.Contains(t, err.Error(), "custom domain")
+	})
+
+	t.Run("should reject seed which is not allowed in the region", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				AllowedSeeds: broker.AllowedSeeds{"westeurope": {"az-eu1"}},
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "westeurope", "seed": "az-us1"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating seed: seed "az-us1" is not allowed in region "westeurope"`)
+	})
+
+	t.Run("should reject encryption key for plan other than GCP", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				EncryptionKeyRegions: broker.EncryptionKeyRegions{"gcp": {"europe-west3"}},
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "westeurope", "encryptionKey": "https://kyma-vault.vault.azure.net/keys/disks"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, "while validating encryption key: customer-managed encryption keys are supported only for the GCP plan")
+	})
+
+	t.Run("should reject machine type which is not allowed for the plan", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				MachineTypes: broker.PlanMachineTypes{"azure": {Default: "Standard_D8_v3", Allowed: []string{"Standard_D8_v3"}}},
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "machineType": "Standard_D16_v3"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating machine type: machine type "Standard_D16_v3" is not allowed for the plan, allowed machine types: Standard_D8_v3`)
+	})
+
+	t.Run("should save requested Kubernetes version", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			rawParameters   string
+			expectedVersion *string
+		}{
+			"pinned version": {
+				rawParameters:   fmt.Sprintf(`{"name": "%s", "kubernetesVersion": "1.19.10"}`, clusterName),
+				expectedVersion: ptr.String("1.19.10"),
+			},
+			"default version": {
+				rawParameters:   fmt.Sprintf(`{"name": "%s"}`, clusterName),
+				expectedVersion: nil,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				memoryStorage := storage.NewMemoryStorage()
+
+				queue := &automock.ProvisioningQueue{}
+				queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+				factoryBuilder := &automock.PlanValidator{}
+				factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+				provisionEndpoint := broker.NewProvision(
+					broker.Config{EnablePlans: []string{"gcp", "azure"}, SupportedKubernetesVersions: []string{"1.18.17", "1.19.10"}},
+					gardener.Config{Project: "test", ShootDomain: "example.com"},
+					memoryStorage.Operations(),
+					memoryStorage.Instances(),
+					queue,
+					factoryBuilder,
+					fixAlwaysPassJSONValidator(),
+					broker.PlansConfig{},
+					broker.ProvisionOptions{},
+					logrus.StandardLogger(),
+				)
+
+				// when
+				response, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "req-region"), instanceID, domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(tc.rawParameters),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+
+				// then
+				require.NoError(t, err)
+
+				operation, err := memoryStorage.Operations().GetProvisioningOperationByID(response.OperationData)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedVersion, operation.ProvisioningParameters.Parameters.KubernetesVersion)
+			})
+		}
+	})
+
+	t.Run("should reject unsupported Kubernetes version", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}, SupportedKubernetesVersions: []string{"1.18.17", "1.19.10"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "kubernetesVersion": "1.15.4"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating Kubernetes version: Kubernetes version "1.15.4" is not supported, supported versions: 1.18.17, 1.19.10`)
+	})
+
+	t.Run("should save allowed component toggles", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		queue := &automock.ProvisioningQueue{}
+		queue.On("AddWithPriority", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("int"))
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}, ToggleableComponents: []string{"kiali", "dex"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			queue,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		response, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "req-region"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "componentToggles": {"enable": ["Kiali"], "disable": ["dex"]}}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		require.NoError(t, err)
+
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(response.OperationData)
+		require.NoError(t, err)
+		assert.Equal(t, &internal.ComponentToggles{Enable: []string{"kiali"}, Disable: []string{"dex"}}, operation.ProvisioningParameters.Parameters.ComponentToggles)
+	})
+
+	t.Run("should reject toggling component which is not allowed", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			rawParameters string
+			expectedError string
+		}{
+			"enable": {
+				rawParameters: fmt.Sprintf(`{"name": "%s", "componentToggles": {"enable": ["backup"]}}`, clusterName),
+				expectedError: `while validating component toggles: component "backup" cannot be toggled, allowed components: kiali, dex`,
+			},
+			"disable": {
+				rawParameters: fmt.Sprintf(`{"name": "%s", "componentToggles": {"disable": ["istio"]}}`, clusterName),
+				expectedError: `while validating component toggles: component "istio" cannot be toggled, allowed components: kiali, dex`,
+			},
+			"enable and disable": {
+				rawParameters: fmt.Sprintf(`{"name": "%s", "componentToggles": {"enable": ["dex"], "disable": ["DEX"]}}`, clusterName),
+				expectedError: `while validating component toggles: component "dex" cannot be enabled and disabled at the same time`,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				factoryBuilder := &automock.PlanValidator{}
+				factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+				provisionEndpoint := broker.NewProvision(
+					broker.Config{EnablePlans: []string{"gcp", "azure"}, ToggleableComponents: []string{"kiali", "dex"}},
+					gardener.Config{Project: "test", ShootDomain: "example.com"},
+					nil,
+					nil,
+					nil,
+					factoryBuilder,
+					fixAlwaysPassJSONValidator(),
+					broker.PlansConfig{},
+					broker.ProvisionOptions{},
+					logrus.StandardLogger(),
+				)
+
+				// when
+				_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+					ServiceID:     serviceID,
+					PlanID:        planID,
+					RawParameters: json.RawMessage(tc.rawParameters),
+					RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+				}, true)
+
+				// then
+				assert.EqualError(t, err, tc.expectedError)
+			})
+		}
+	})
+
+	t.Run("should reject component toggles breaking the dependencies between components", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		optComponentsSvc := runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{
+			"kiali": runtime.NewGenericComponentDisabler("kiali"),
+		})
+		optComponentsSvc.AddToggleableComponents("dex")
+		require.NoError(t, optComponentsSvc.SetComponentDependencies([]string{"kiali:dex"}))
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}, ToggleableComponents: []string{"kiali", "dex"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+		provisionEndpoint.SetComponentTogglesValidator(optComponentsSvc)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "componentToggles": {"enable": ["kiali"], "disable": ["dex"]}}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating component toggles: component "dex" cannot be disabled, it is required by the component "kiali"`)
+	})
+
+	t.Run("should reject ingress with not supported load balancer type", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "ingress": {"loadBalancerType": "NodePort"}}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating ingress: load balancer type "NodePort" is not supported, supported types: external, internal`)
+	})
+
+	t.Run("should reject resource quota exceeding the maximum of the plan", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				ResourceQuotas: broker.PlanResourceQuotas{broker.PlanNamesMapping[planID]: {Namespaces: 100, PVCSizeGb: 200}},
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "resourceQuota": {"namespaces": 150}}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating resource quota: resource quota namespaces 150 exceeds the maximum 100 of the plan`)
+	})
+
+	t.Run("should reject parameters violating the cross-field rule", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				ParametersValidators: broker.NewDefaultParametersValidators(),
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "autoScalerMin": 5, "autoScalerMax": 3}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, `while validating input parameters: invalid provisioning parameters: autoScalerMin: must not be greater than autoScalerMax 3`)
+	})
+
+	t.Run("should validate parameters against the plan schema before the plan validators", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
+		require.NoError(t, err)
+
+		validatorCalled := false
+		parametersValidators := broker.PlanParametersValidators{}
+		parametersValidators.Register(planID, broker.ParametersValidatorFunc(func(internal.ProvisioningParametersDTO) []broker.ParameterError {
+			validatorCalled = true
+			return nil
+		}))
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixValidator,
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				ParametersValidators: parametersValidators,
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "machineType": "not-existing-machine-type"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.Error(t, err)
+		assert.False(t, validatorCalled)
+	})
+
+	t.Run("should reject preset merged with parameters which does not match the plan schema", func(t *testing.T) {
+		// given
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", planID).Return(true)
+
+		fixValidator, err := broker.NewPlansSchemaValidator(broker.PlansConfig{})
+		require.NoError(t, err)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "azure_lite"}, OnlySingleTrialPerGA: true},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			nil,
+			nil,
+			nil,
+			factoryBuilder,
+			fixValidator,
+			broker.PlansConfig{},
+			broker.ProvisionOptions{
+				Presets: broker.ProvisioningPresets{
+					"invalid": {"machineType": "not-existing-machine-type"},
+				},
+			},
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err = provisionEndpoint.Provision(fixReqCtxWithRegion(t, "dummy"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "preset": "invalid"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func fixExistOperation() internal.ProvisioningOperation {
+	provisioningOperation := fixture.FixProvisioningOperation(existOperationID, instanceID)
+	provisioningOperation.ProvisioningParameters = internal.ProvisioningParameters{
+		PlanID:    planID,
+		ServiceID: serviceID,
+		ErsContext: internal.ERSContext{
+			SubAccountID:    subAccountID,
+			GlobalAccountID: globalAccountID,
+		},
+		Parameters: internal.ProvisioningParametersDTO{
+			Name:           clusterName,
+			ControlPlaneHA: ptr.Bool(false),
+		},
+		PlatformRegion: region,
+	}
+
+	return provisioningOperation
+}
+
+func fixAlwaysPassJSONValidator() broker.PlansSchemaValidator {
+	validatorMock := &automock.JSONSchemaValidator{}
+	validatorMock.On("ValidateString", mock.Anything).Return(jsonschema.ValidationResult{Valid: true}, nil)
+
+	fixValidator := broker.PlansSchemaValidator{
+		broker.GCPPlanID:   validatorMock,
+		broker.AzurePlanID: validatorMock,
+		broker.TrialPlanID: validatorMock,
+	}
+
+	return fixValidator
+}
+
+func fixProvisioningPresets() broker.ProvisioningPresets {
+	return broker.ProvisioningPresets{
+		"small": {
+			"region":        "westeurope",
+			"autoScalerMin": 2,
+			"autoScalerMax": 4,
+		},
+	}
+}
+
+func fixInstance() internal.Instance {
+	return fixture.FixInstance(instanceID)
+}
+
+func fixReqCtxWithRegion(t *testing.T, region string) context.Context {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", "http://url.io", nil)
+	require.NoError(t, err)
+	var ctx context.Context
+	spyHandler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		ctx = req.Context()
+	})
+
+	middleware.AddRegionToContext(region).Middleware(spyHandler).ServeHTTP(httptest.NewRecorder(), req)
+	return ctx
+}
+
+// slowCountingInstances widens the window between the check of the duplicated instance and the insert of the new one
+type slowCountingInstances struct {
+	storage.Instances
+}
+
+func (s slowCountingInstances) GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error) {
+	time.Sleep(10 * time.Millisecond)
+	return s.Instances.GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID)
+}
+
+// unclaimedCustomDomainInstances finds no instance by the custom domain, the same as when the domain is claimed
+// between the check of the custom domain and the insert of the new instance
+type unclaimedCustomDomainInstances struct {
+	storage.Instances
+}
+
+func (unclaimedCustomDomainInstances) GetByCustomDomain(customDomain string) (*internal.Instance, error) {
+	return nil, dberr.NotFound("instance with custom domain %s not exist", customDomain)
+}
+
+// failingOperations fails to save every provisioning operation
+type failingOperations struct {
+	storage.Operations
+}
+
+func (failingOperations) InsertProvisioningOperation(operation internal.ProvisioningOperation) error {
+	return errors.New("database is unavailable")
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -25,6 +26,8 @@ type DeprovisionEndpoint struct {
 	operationsStorage storage.Deprovisioning
 
 	queue Queue
+	// instanceLocks rejects the deprovisioning of the instance changed by another operation
+	instanceLocks InstanceLocks
 }
 
 func NewDeprovision(instancesStorage storage.Instances, operationsStorage storage.Operations, q Queue, log logrus.FieldLogger) *DeprovisionEndpoint {
@@ -37,10 +40,16 @@ func NewDeprovision(instancesStorage storage.Instances, operationsStorage storag
 	}
 }
 
+// SetInstanceLocks makes the endpoint reject the deprovisioning of the instance locked by another operation with 409 Conflict
+func (b *DeprovisionEndpoint) SetInstanceLocks(locks InstanceLocks) {
+	b.instanceLocks = locks
+}
+
 // Deprovision deletes an existing service instance
 //  DELETE /v2/service_instances/{instance_id}
 func (b *DeprovisionEndpoint) Deprovision(ctx context.Context, instanceID string, details domain.DeprovisionDetails, asyncAllowed bool) (domain.DeprovisionServiceSpec, error) {
-	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID})
+	correlationID, _ := middleware.CorrelationIDFromContext(ctx)
+	logger := b.log.WithFields(logrus.Fields{"instanceID": instanceID, "correlationID": correlationID})
 	logger.Infof("Deprovisioning triggered, details: %+v", details)
 
 	instance, err := b.instancesStorage.GetByID(instanceID)
@@ -82,6 +91,11 @@ func (b *DeprovisionEndpoint) Deprovision(ctx context.Context, instanceID string
 			OperationData: existingOperation.ID,
 		}, nil
 	}
+	if err := checkInstanceLock(b.instanceLocks, instanceID); err != nil {
+		logger.Infof("Deprovisioning rejected: %s", err)
+		return domain.DeprovisionServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusConflict, "deprovision")
+	}
+
 	// create and save new operation
 	operationID := uuid.New().String()
 	logger = logger.WithField("operationID", operationID)
@@ -90,6 +104,7 @@ func (b *DeprovisionEndpoint) Deprovision(ctx context.Context, instanceID string
 		logger.Errorf("cannot create new operation: %s", err)
 		return domain.DeprovisionServiceSpec{}, errors.New("cannot create new operation")
 	}
+	operation.CorrelationID = correlationID
 	err = b.operationsStorage.InsertDeprovisioningOperation(operation)
 	if err != nil {
 		logger.Errorf("cannot save operation: %s", err)
@@ -114,3 +129,14 @@ func (b *DeprovisionEndpoint) reprocessOperation(operation *internal.Deprovision
 	}
 	return nil
 }
+
+// checkInstanceLock returns an error when another operation holds the lock of the instance, the nil locks do not lock any instance
+func checkInstanceLock(locks InstanceLocks, instanceID string) error {
+	if locks == nil {
+		return nil
+	}
+	if holder, locked := locks.Holder(instanceID); locked {
+		return fmt.Errorf("operation %s is in progress on the instance, retry when it is finished", holder)
+	}
+	return nil
+}
@@ -2,13 +2,16 @@ package broker
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker/automock"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -110,6 +113,38 @@ func TestDeprovisionEndpoint_DeprovisionExistingOperationFailed(t *testing.T) {
 	assert.Equal(t, domain.InProgress, operation.State)
 }
 
+func TestDeprovisionEndpoint_DeprovisionLockedInstance(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Instances().Insert(fixInstance())
+	require.NoError(t, err)
+
+	queue := &automock.Queue{}
+	queue.On("Add", mock.AnythingOfType("string"))
+
+	svc := NewDeprovision(memoryStorage.Instances(), memoryStorage.Operations(), queue, logrus.StandardLogger())
+	svc.SetInstanceLocks(fakeInstanceLocks{instanceID: "upgrade-operation-id"})
+
+	// when
+	_, err = svc.Deprovision(context.TODO(), instanceID, domain.DeprovisionDetails{}, true)
+
+	// then
+	require.Error(t, err)
+	failure, ok := err.(*apiresponses.FailureResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusConflict, failure.ValidatedStatusCode(nil))
+	queue.AssertNotCalled(t, "Add", mock.Anything)
+	_, err = memoryStorage.Operations().GetDeprovisioningOperationByInstanceID(instanceID)
+	assert.True(t, dberr.IsNotFound(err))
+}
+
+type fakeInstanceLocks map[string]string
+
+func (l fakeInstanceLocks) Holder(instanceID string) (string, bool) {
+	holder, found := l[instanceID]
+	return holder, found
+}
+
 func fixDeprovisioningOperation(state domain.LastOperationState) internal.DeprovisioningOperation {
 	deprovisioningOperation := fixture.FixDeprovisioningOperation(operationID, instanceID)
 	deprovisioningOperation.State = state
@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
 	"github.com/pivotal-cf/brokerapi/v7/domain"
@@ -12,6 +13,8 @@ import (
 
 type GetInstanceEndpoint struct {
 	instancesStorage storage.Instances
+	// instanceResumer resumes the instance suspended by the broker when the instance is fetched
+	instanceResumer InstanceResumer
 
 	log logrus.FieldLogger
 }
@@ -23,7 +26,13 @@ func NewGetInstance(instancesStorage storage.Instances, log logrus.FieldLogger)
 	}
 }
 
-// GetInstance fetches information about a service instance
+// SetInstanceResumer makes the endpoint resume the instance suspended by the broker, e.g. because it was idle
+func (b *GetInstanceEndpoint) SetInstanceResumer(resumer InstanceResumer) {
+	b.instanceResumer = resumer
+}
+
+// GetInstance fetches information about a service instance, the provisioning parameters
+// with the secrets redacted are returned only when requested with the fetch_parameters query parameter
 //   GET /v2/service_instances/{instance_id}
 func (b *GetInstanceEndpoint) GetInstance(ctx context.Context, instanceID string) (domain.GetInstanceDetailsSpec, error) {
 	logger := b.log.WithField("instanceID", instanceID)
@@ -33,12 +42,23 @@ func (b *GetInstanceEndpoint) GetInstance(ctx context.Context, instanceID string
 	if err != nil {
 		return domain.GetInstanceDetailsSpec{}, errors.Wrapf(err, "while getting instance from storage")
 	}
+	// the instance is returned even if it cannot be resumed, the next call retries the resumption
+	if resumed, err := resumeInstance(b.instanceResumer, b.instancesStorage, inst, logger); err != nil {
+		logger.Errorf("unable to resume instance: %s", err)
+	} else {
+		inst = resumed
+	}
 
 	spec := domain.GetInstanceDetailsSpec{
 		ServiceID:    inst.ServiceID,
 		PlanID:       inst.ServicePlanID,
 		DashboardURL: inst.DashboardURL,
-		Parameters:   inst.Parameters,
+	}
+	if middleware.FetchParametersFromContext(ctx) {
+		spec.Parameters, err = SanitizeProvisioningParameters(inst.Parameters)
+		if err != nil {
+			return domain.GetInstanceDetailsSpec{}, errors.Wrapf(err, "while sanitizing instance parameters")
+		}
 	}
 	return spec, nil
 }
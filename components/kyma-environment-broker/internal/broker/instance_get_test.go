@@ -0,0 +1,114 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstanceEndpoint_GetInstance(t *testing.T) {
+	t.Run("should not return parameters if not requested", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Instances().Insert(fixInstance())
+		require.NoError(t, err)
+
+		svc := broker.NewGetInstance(memoryStorage.Instances(), logrus.StandardLogger())
+
+		// when
+		spec, err := svc.GetInstance(context.TODO(), instanceID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fixture.ServiceId, spec.ServiceID)
+		assert.Equal(t, fixture.PlanId, spec.PlanID)
+		assert.Equal(t, fixture.InstanceDashboardURL, spec.DashboardURL)
+		assert.Nil(t, spec.Parameters)
+	})
+
+	t.Run("should return parameters with secrets redacted", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		instance := fixInstance()
+		instance.Parameters.ErsContext.ServiceManager.Credentials.BasicAuth.Password = "sm-password-001"
+		instance.Parameters.Parameters.TargetSecret = ptr.String("azrspn-secret-001")
+		err := memoryStorage.Instances().Insert(instance)
+		require.NoError(t, err)
+
+		svc := broker.NewGetInstance(memoryStorage.Instances(), logrus.StandardLogger())
+
+		// when
+		spec, err := svc.GetInstance(middleware.WithFetchParameters(context.TODO(), true), instanceID)
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, spec.Parameters)
+
+		raw, err := json.Marshal(spec.Parameters)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "sm-password-001")
+		assert.NotContains(t, string(raw), "azrspn-secret-001")
+
+		var params struct {
+			PlanID     string `json:"plan_id"`
+			ErsContext struct {
+				SubAccountID   string      `json:"subaccount_id"`
+				ServiceManager interface{} `json:"sm_platform_credentials"`
+			} `json:"ers_context"`
+			Parameters struct {
+				Name         string      `json:"name"`
+				Region       string      `json:"region"`
+				TargetSecret interface{} `json:"targetSecret"`
+			} `json:"parameters"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &params))
+		assert.Equal(t, fixture.PlanId, params.PlanID)
+		assert.Equal(t, instance.Parameters.ErsContext.SubAccountID, params.ErsContext.SubAccountID)
+		assert.Equal(t, broker.RedactedValue, params.ErsContext.ServiceManager)
+		assert.Equal(t, instance.Parameters.Parameters.Name, params.Parameters.Name)
+		assert.Equal(t, fixture.Region, params.Parameters.Region)
+		assert.Equal(t, broker.RedactedValue, params.Parameters.TargetSecret)
+	})
+	t.Run("should resume the instance suspended by the broker", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		instance := fixInstance()
+		instance.SuspensionReason = internal.SuspensionReasonIdle
+		err := memoryStorage.Instances().Insert(instance)
+		require.NoError(t, err)
+
+		svc := broker.NewGetInstance(memoryStorage.Instances(), logrus.StandardLogger())
+		svc.SetInstanceResumer(idleResumer{})
+
+		// when
+		spec, err := svc.GetInstance(context.TODO(), instanceID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fixture.InstanceDashboardURL, spec.DashboardURL)
+		stored, err := memoryStorage.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.SuspensionReason)
+	})
+}
+
+type idleResumer struct{}
+
+func (idleResumer) Resume(instance *internal.Instance) (bool, error) {
+	if instance.SuspensionReason != internal.SuspensionReasonIdle {
+		return false, nil
+	}
+	instance.SuspensionReason = ""
+	return true, nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 
@@ -14,9 +17,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// OperationTimingsKey is the key of the operation timings in the response of the verbose last operation request
+const OperationTimingsKey = "timings"
+
+// OperationTimings tells when the operation and each of its steps started and finished
+type OperationTimings struct {
+	CreatedAt time.Time             `json:"createdAt"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+	Steps     []internal.StepTiming `json:"steps,omitempty"`
+}
+
 type LastOperationEndpoint struct {
 	operationStorage storage.Operations
 	instancesStorage storage.Instances
+	cache            *OperationCache
 
 	log logrus.FieldLogger
 }
@@ -29,7 +43,12 @@ func NewLastOperation(os storage.Operations, is storage.Instances, log logrus.Fi
 	}
 }
 
-// LastOperation fetches last operation state for a service instance
+// SetOperationCache makes the endpoint serve the repeated polls of the operation from the cache
+func (b *LastOperationEndpoint) SetOperationCache(cache *OperationCache) {
+	b.cache = cache
+}
+
+// LastOperation fetches last operation state for a service instance, the verbose request gets the operation timings too
 //   GET /v2/service_instances/{instance_id}/last_operation
 func (b *LastOperationEndpoint) LastOperation(ctx context.Context, instanceID string, details domain.PollDetails) (domain.LastOperation, error) {
 	logger := b.log.WithField("instanceID", instanceID).WithField("operationID", details.OperationData)
@@ -43,9 +62,10 @@ func (b *LastOperationEndpoint) LastOperation(ctx context.Context, instanceID st
 				logger.Errorf("cannot get operation from storage: %s", err)
 				return domain.LastOperation{}, errors.Wrapf(err, "while getting last operation from storage")
 			}
+			setOperationTimings(ctx, *lastOp)
 			return domain.LastOperation{
 				State:       lastOp.State,
-				Description: lastOp.Description,
+				Description: lastOperationDescription(*lastOp),
 			}, nil
 		case dberr.IsNotFound(err):
 			return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.Errorf("instance does not exist"), http.StatusGone, fmt.Sprintf("instance with ID %s is not found in DB", instanceID))
@@ -55,7 +75,7 @@ func (b *LastOperationEndpoint) LastOperation(ctx context.Context, instanceID st
 		}
 	}
 
-	operation, err := b.operationStorage.GetOperationByID(details.OperationData)
+	operation, err := b.getOperation(details.OperationData)
 	if err != nil {
 		logger.Errorf("cannot get operation from storage: %s", err)
 		return domain.LastOperation{}, errors.Wrapf(err, "while getting operation from storage")
@@ -66,8 +86,57 @@ func (b *LastOperationEndpoint) LastOperation(ctx context.Context, instanceID st
 		return domain.LastOperation{}, apiresponses.NewFailureResponseBuilder(err, http.StatusBadRequest, err.Error())
 	}
 
+	setOperationTimings(ctx, *operation)
 	return domain.LastOperation{
 		State:       operation.State,
-		Description: operation.Description,
+		Description: lastOperationDescription(*operation),
 	}, nil
 }
+
+// getOperation returns the cached operation, the operation which is not cached is read from the storage and cached
+func (b *LastOperationEndpoint) getOperation(operationID string) (*internal.Operation, error) {
+	if operation, found := b.cache.Get(operationID); found {
+		return &operation, nil
+	}
+	operation, err := b.operationStorage.GetOperationByID(operationID)
+	if err != nil {
+		return nil, err
+	}
+	b.cache.Set(*operation)
+	return operation, nil
+}
+
+// lastOperationDescription extends the description of a failed operation with the code and the remediation hint
+// of its failure reason and the description of the operation in progress with its latest progress message,
+// the OSB API does not allow to return the structured failure reason nor the progress
+func lastOperationDescription(operation internal.Operation) string {
+	if latest := operation.LatestProgress(); latest != nil && operation.State == domain.InProgress {
+		return fmt.Sprintf("%s (progress: %s)", operation.Description, latest.Message)
+	}
+	if operation.FailureReason == nil {
+		return operation.Description
+	}
+	description := fmt.Sprintf("%s (code: %s", operation.Description, operation.FailureReason.Code)
+	if operation.FailureReason.Remediation != "" {
+		description = fmt.Sprintf("%s, remediation: %s", description, operation.FailureReason.Remediation)
+	}
+	return description + ")"
+}
+
+// setOperationTimings attaches the timings to the response of the verbose request. The step which finished
+// the operation has no finish recorded, it finished when the operation was updated for the last time.
+func setOperationTimings(ctx context.Context, operation internal.Operation) {
+	if !middleware.VerboseFromContext(ctx) {
+		return
+	}
+	timings := OperationTimings{
+		CreatedAt: operation.CreatedAt,
+		UpdatedAt: operation.UpdatedAt,
+		Steps:     append([]internal.StepTiming(nil), operation.StepTimings...),
+	}
+	if last := len(timings.Steps) - 1; last >= 0 && timings.Steps[last].FinishedAt == nil && operation.IsFinished() {
+		updatedAt := operation.UpdatedAt
+		timings.Steps[last].FinishedAt = &updatedAt
+	}
+	middleware.SetVerboseDetail(ctx, OperationTimingsKey, timings)
+}
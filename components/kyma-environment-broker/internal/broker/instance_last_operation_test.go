@@ -2,15 +2,24 @@ package broker_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
@@ -61,6 +70,157 @@ func TestLastOperation_LastOperation(t *testing.T) {
 			Description: operationDescription,
 		}, response)
 	})
+	t.Run("Should return failure reason in the description of failed operation", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixOperation()
+		operation.State = domain.Failed
+		operation.FailureReason = &internal.FailureReason{
+			Code:        "REGION_NOT_ENTITLED",
+			Message:     operationDescription,
+			Remediation: "enable the region in the entitlements of the subaccount",
+		}
+		err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+		assert.NoError(t, err)
+
+		lastOperationEndpoint := broker.NewLastOperation(memoryStorage.Operations(), memoryStorage.Instances(), logrus.StandardLogger())
+
+		// when
+		response, err := lastOperationEndpoint.LastOperation(context.TODO(), instID, domain.PollDetails{OperationData: operationID})
+		assert.NoError(t, err)
+
+		// then
+		assert.Equal(t, domain.LastOperation{
+			State:       domain.Failed,
+			Description: operationDescription + " (code: REGION_NOT_ENTITLED, remediation: enable the region in the entitlements of the subaccount)",
+		}, response)
+	})
+	t.Run("Should return latest progress in the description of operation in progress", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixOperation()
+		operation.State = domain.InProgress
+		err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+		assert.NoError(t, err)
+
+		// the step reports its progress
+		manager := process.NewProvisionOperationManager(memoryStorage.Operations())
+		operation, when := manager.ReportProgress(operation, "shoot created", logrus.StandardLogger())
+		assert.Zero(t, when)
+		operation, when = manager.ReportProgress(operation, "nodes joining", logrus.StandardLogger())
+		assert.Zero(t, when)
+
+		lastOperationEndpoint := broker.NewLastOperation(memoryStorage.Operations(), memoryStorage.Instances(), logrus.StandardLogger())
+
+		// when
+		response, err := lastOperationEndpoint.LastOperation(context.TODO(), instID, domain.PollDetails{OperationData: operationID})
+		assert.NoError(t, err)
+
+		// then
+		assert.Equal(t, domain.LastOperation{
+			State:       domain.InProgress,
+			Description: operationDescription + " (progress: nodes joining)",
+		}, response)
+	})
+
+	t.Run("Should return operation timings only for verbose request", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixOperation()
+		startedAt := operation.CreatedAt.Add(time.Second)
+		finishedAt := startedAt.Add(time.Minute)
+		operation.StartStepTiming("Create_Runtime", startedAt)
+		operation.FinishStepTiming("Create_Runtime", finishedAt)
+		operation.StartStepTiming("Check_Runtime", finishedAt)
+		err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+		require.NoError(t, err)
+
+		lastOperationEndpoint := broker.NewLastOperation(memoryStorage.Operations(), memoryStorage.Instances(), logrus.StandardLogger())
+		handler := middleware.AddVerboseDetails(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			response, err := lastOperationEndpoint.LastOperation(req.Context(), instID, domain.PollDetails{OperationData: operationID})
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"state": response.State, "description": response.Description})
+		}))
+
+		for name, tc := range map[string]struct {
+			query           string
+			expectedTimings bool
+		}{
+			"verbose":     {query: "?verbose=true", expectedTimings: true},
+			"not verbose": {query: "", expectedTimings: false},
+		} {
+			t.Run(name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/v2/service_instances/"+instID+"/last_operation"+tc.query, nil)
+				recorder := httptest.NewRecorder()
+
+				// when
+				handler.ServeHTTP(recorder, req)
+
+				// then
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var body struct {
+					State   string                   `json:"state"`
+					Timings *broker.OperationTimings `json:"timings"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				assert.Equal(t, string(domain.Succeeded), body.State)
+				if !tc.expectedTimings {
+					assert.Nil(t, body.Timings)
+					return
+				}
+				require.NotNil(t, body.Timings)
+				require.Len(t, body.Timings.Steps, 2)
+				assert.Equal(t, "Create_Runtime", body.Timings.Steps[0].Step)
+				assert.True(t, startedAt.Equal(body.Timings.Steps[0].StartedAt))
+				assert.True(t, finishedAt.Equal(*body.Timings.Steps[0].FinishedAt))
+				// the last step finished the operation
+				assert.Equal(t, "Check_Runtime", body.Timings.Steps[1].Step)
+				require.NotNil(t, body.Timings.Steps[1].FinishedAt)
+				assert.True(t, body.Timings.UpdatedAt.Equal(*body.Timings.Steps[1].FinishedAt))
+			})
+		}
+	})
+}
+
+func TestLastOperation_OperationCache(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixOperation()
+	operation.State = domain.InProgress
+	err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+	require.NoError(t, err)
+
+	eventBroker := event.NewPubSub(logrus.New())
+	cache := broker.NewOperationCache(broker.OperationCacheConfig{TTL: time.Minute, TerminalTTL: time.Hour, Size: 10})
+	cache.Subscribe(eventBroker)
+	lastOperationEndpoint := broker.NewLastOperation(memoryStorage.Operations(), memoryStorage.Instances(), logrus.StandardLogger())
+	lastOperationEndpoint.SetOperationCache(cache)
+
+	response, err := lastOperationEndpoint.LastOperation(context.TODO(), instID, domain.PollDetails{OperationData: operationID})
+	require.NoError(t, err)
+	assert.Equal(t, domain.InProgress, response.State)
+
+	stored, err := memoryStorage.Operations().GetProvisioningOperationByID(operationID)
+	require.NoError(t, err)
+	stored.State = domain.Succeeded
+	updated, err := memoryStorage.Operations().UpdateProvisioningOperation(*stored)
+	require.NoError(t, err)
+
+	// when
+	response, err = lastOperationEndpoint.LastOperation(context.TODO(), instID, domain.PollDetails{OperationData: operationID})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, domain.InProgress, response.State, "the poll within the TTL is served from the cache")
+
+	// when
+	eventBroker.Publish(context.TODO(), process.ProvisioningStepProcessed{Operation: *updated})
+
+	// then
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		response, err := lastOperationEndpoint.LastOperation(context.TODO(), instID, domain.PollDetails{OperationData: operationID})
+		return err == nil && response.State == domain.Succeeded, nil
+	}))
 }
 
 func fixOperation() internal.ProvisioningOperation {
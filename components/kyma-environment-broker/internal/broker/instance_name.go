@@ -0,0 +1,102 @@
+package broker
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/pkg/errors"
+)
+
+var instanceNameRegexp = regexp.MustCompile("^[a-zA-Z0-9-]+$")
+
+// InstanceNameData holds the values which can be used in the instance name template
+type InstanceNameData struct {
+	GlobalAccountID string
+	SubAccountID    string
+	PlanName        string
+	// Region is the requested region or the platform region of the request if the region is not requested
+	Region string
+}
+
+// InstanceNameTemplate enforces the naming convention of the instances, e.g. {{.PlanName}}-{{.Region}}.
+// The name rendered from the template is used when the client does not specify the name, the name specified
+// by the client must be the rendered name or the rendered name followed by a dash and a suffix.
+// The zero value accepts any name.
+type InstanceNameTemplate struct {
+	tmpl *template.Template
+}
+
+// NewInstanceNameTemplate parses the template and checks if it renders a valid name, empty text means no convention
+func NewInstanceNameTemplate(text string) (InstanceNameTemplate, error) {
+	if text == "" {
+		return InstanceNameTemplate{}, nil
+	}
+	tmpl, err := template.New("instanceName").Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return InstanceNameTemplate{}, errors.Wrap(err, "while parsing instance name template")
+	}
+	nameTemplate := InstanceNameTemplate{tmpl: tmpl}
+	_, err = nameTemplate.render(InstanceNameData{
+		GlobalAccountID: "3e64ebae-38b5-46a0-b1ed-9ccee153a0ae",
+		SubAccountID:    "39ba9a66-2c1a-4fe4-a28e-6e5db434084e",
+		PlanName:        AzurePlanName,
+		Region:          "westeurope",
+	})
+	if err != nil {
+		return InstanceNameTemplate{}, errors.Wrap(err, "while validating instance name template")
+	}
+
+	return nameTemplate, nil
+}
+
+// Apply sets the name rendered from the template in the raw parameters if the name is not specified,
+// otherwise it checks if the specified name follows the convention
+func (t InstanceNameTemplate) Apply(rawParameters json.RawMessage, data InstanceNameData) (json.RawMessage, error) {
+	if t.tmpl == nil {
+		return rawParameters, nil
+	}
+
+	parameters := map[string]interface{}{}
+	if len(rawParameters) > 0 {
+		if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+			return nil, errors.Wrap(err, "while unmarshaling raw parameters")
+		}
+	}
+	if region, ok := parameters["region"].(string); ok && region != "" {
+		data.Region = region
+	}
+	expected, err := t.render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name, found := parameters["name"]
+	if !found {
+		parameters["name"] = expected
+		result, err := json.Marshal(parameters)
+		if err != nil {
+			return nil, errors.Wrap(err, "while marshaling parameters with instance name")
+		}
+		return result, nil
+	}
+	if specified, ok := name.(string); !ok || (specified != expected && !strings.HasPrefix(specified, expected+"-")) {
+		return nil, errors.Errorf("name %v does not follow the naming convention, the name must be %q or start with %q", name, expected, expected+"-")
+	}
+
+	return rawParameters, nil
+}
+
+func (t InstanceNameTemplate) render(data InstanceNameData) (string, error) {
+	var name bytes.Buffer
+	if err := t.tmpl.Execute(&name, data); err != nil {
+		return "", errors.Wrap(err, "while rendering instance name")
+	}
+	if !instanceNameRegexp.MatchString(name.String()) {
+		return "", errors.Errorf("rendered instance name %q must be non-empty and contain only alphanumeric characters and dashes", name.String())
+	}
+	return name.String(), nil
+}
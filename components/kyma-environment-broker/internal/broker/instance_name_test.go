@@ -0,0 +1,81 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceNameTemplate(t *testing.T) {
+	// given
+	nameTemplate, err := NewInstanceNameTemplate("{{.PlanName}}-{{.Region}}-{{.SubAccountID | trunc 8}}")
+	require.NoError(t, err)
+	data := InstanceNameData{GlobalAccountID: "ga-id", SubAccountID: "39ba9a66-2c1a", PlanName: GCPPlanName, Region: "cf-eu10"}
+
+	t.Run("should derive the name if it is not specified", func(t *testing.T) {
+		// when
+		raw, err := nameTemplate.Apply(json.RawMessage(`{"region": "europe-west3"}`), data)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "gcp-europe-west3-39ba9a66", "region": "europe-west3"}`, string(raw))
+	})
+
+	t.Run("should derive the name with the platform region", func(t *testing.T) {
+		// when
+		raw, err := nameTemplate.Apply(nil, data)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "gcp-cf-eu10-39ba9a66"}`, string(raw))
+	})
+
+	t.Run("should accept the name following the convention", func(t *testing.T) {
+		for _, name := range []string{"gcp-europe-west3-39ba9a66", "gcp-europe-west3-39ba9a66-dev"} {
+			// when
+			raw, err := nameTemplate.Apply(json.RawMessage(`{"name": "`+name+`", "region": "europe-west3"}`), data)
+
+			// then
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"name": "`+name+`", "region": "europe-west3"}`, string(raw))
+		}
+	})
+
+	t.Run("should reject the name violating the convention", func(t *testing.T) {
+		for _, name := range []string{`"my-cluster"`, `"gcp-europe-west3-39ba9a66dev"`, `5`} {
+			// when
+			_, err := nameTemplate.Apply(json.RawMessage(`{"name": `+name+`, "region": "europe-west3"}`), data)
+
+			// then
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("should accept any name without the template", func(t *testing.T) {
+		// when
+		raw, err := InstanceNameTemplate{}.Apply(json.RawMessage(`{"name": "my-cluster"}`), data)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "my-cluster"}`, string(raw))
+	})
+}
+
+func TestNewInstanceNameTemplate(t *testing.T) {
+	for name, text := range map[string]string{
+		"syntax error":       "{{.PlanName",
+		"unknown field":      "{{.Cluster}}",
+		"invalid characters": "{{.PlanName}}_{{.Region}}",
+		"empty name":         `{{ "" }}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			_, err := NewInstanceNameTemplate(text)
+
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
@@ -4,12 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,26 +25,50 @@ type ContextUpdateHandler interface {
 	Handle(instance *internal.Instance, newCtx internal.ERSContext) error
 }
 
+// InstanceResumer resumes the instance suspended on the broker's own initiative, it returns false if the instance
+// was not suspended so. The instance must be stored by the caller.
+type InstanceResumer interface {
+	Resume(instance *internal.Instance) (bool, error)
+}
+
 type UpdateEndpoint struct {
 	log logrus.FieldLogger
 
 	instanceStorage      storage.Instances
 	contextUpdateHandler ContextUpdateHandler
-	processingEnabled    bool
+	featureFlags         featureflags.Provider
 
 	operationStorage storage.Operations
+	// overridesQueue processes the updates which change only the Kyma overrides
+	overridesQueue Queue
+
+	// instanceLocks rejects the update of the instance changed by another operation
+	instanceLocks InstanceLocks
+	// instanceResumer resumes the instance suspended by the broker before the update is processed
+	instanceResumer InstanceResumer
 }
 
-func NewUpdate(instanceStorage storage.Instances, operationStorage storage.Operations, ctxUpdateHandler ContextUpdateHandler, processingEnabled bool, log logrus.FieldLogger) *UpdateEndpoint {
+func NewUpdate(instanceStorage storage.Instances, operationStorage storage.Operations, ctxUpdateHandler ContextUpdateHandler, featureFlags featureflags.Provider, overridesQueue Queue, log logrus.FieldLogger) *UpdateEndpoint {
 	return &UpdateEndpoint{
 		log:                  log.WithField("service", "UpdateEndpoint"),
 		instanceStorage:      instanceStorage,
 		operationStorage:     operationStorage,
 		contextUpdateHandler: ctxUpdateHandler,
-		processingEnabled:    processingEnabled,
+		featureFlags:         featureFlags,
+		overridesQueue:       overridesQueue,
 	}
 }
 
+// SetInstanceLocks makes the endpoint reject the update creating an operation of the instance locked by another operation with 409 Conflict
+func (b *UpdateEndpoint) SetInstanceLocks(locks InstanceLocks) {
+	b.instanceLocks = locks
+}
+
+// SetInstanceResumer makes the endpoint resume the instance suspended by the broker, e.g. because it was idle
+func (b *UpdateEndpoint) SetInstanceResumer(resumer InstanceResumer) {
+	b.instanceResumer = resumer
+}
+
 // Update modifies an existing service instance
 //  PATCH /v2/service_instances/{instance_id}
 func (b *UpdateEndpoint) Update(ctx context.Context, instanceID string, details domain.UpdateDetails, asyncAllowed bool) (domain.UpdateServiceSpec, error) {
@@ -51,6 +83,12 @@ func (b *UpdateEndpoint) Update(ctx context.Context, instanceID string, details
 	}
 	logger.Infof("Plan ID/Name: %s/%s", instance.ServicePlanID, PlanNamesMapping[instance.ServicePlanID])
 
+	instance, err = resumeInstance(b.instanceResumer, b.instanceStorage, instance, logger)
+	if err != nil {
+		logger.Errorf("unable to resume instance: %s", err.Error())
+		return domain.UpdateServiceSpec{}, errors.New("unable to resume the instance")
+	}
+
 	var ersContext internal.ERSContext
 	err = json.Unmarshal(details.RawContext, &ersContext)
 	if err != nil {
@@ -70,7 +108,8 @@ func (b *UpdateEndpoint) Update(ctx context.Context, instanceID string, details
 		logger.Info(k)
 	}
 
-	if b.processingEnabled {
+	operationID := ""
+	if b.featureFlags.IsEnabled(featureflags.UpdateProcessing) {
 		// todo: remove the code below when we are sure the ERSContext contains required values.
 		// This code is done because the PATCH request contains only some of fields and that requests made the ERS context empty in the past.
 		provOperation, err := b.operationStorage.GetProvisioningOperationByInstanceID(instanceID)
@@ -107,6 +146,17 @@ func (b *UpdateEndpoint) Update(ctx context.Context, instanceID string, details
 			instance.Parameters.ErsContext.Active = ersContext.Active
 		}
 
+		if len(details.RawParameters) > 0 {
+			operationID, err = b.processParametersUpdate(instance, provOperation, details.RawParameters, asyncAllowed, logger)
+			if err != nil {
+				return domain.UpdateServiceSpec{
+					IsAsync:       false,
+					DashboardURL:  instance.DashboardURL,
+					OperationData: "",
+				}, err
+			}
+		}
+
 		_, err = b.instanceStorage.Update(*instance)
 		if err != nil {
 			logger.Errorf("processing context updated failed: %s", err.Error())
@@ -119,12 +169,138 @@ func (b *UpdateEndpoint) Update(ctx context.Context, instanceID string, details
 	}
 
 	return domain.UpdateServiceSpec{
-		IsAsync:       false,
+		IsAsync:       operationID != "",
 		DashboardURL:  instance.DashboardURL,
-		OperationData: "",
+		OperationData: operationID,
 	}, nil
 }
 
+// processParametersUpdate applies the changed parameters which affect only the Kyma overrides. The overrides steps
+// are re-run by the upgrade Kyma operation followed by the Kyma reconcile, the cluster is not changed.
+// It returns the ID of the created operation or an empty string if no parameter was changed.
+func (b *UpdateEndpoint) processParametersUpdate(instance *internal.Instance, provOperation *internal.ProvisioningOperation, rawParameters json.RawMessage, asyncAllowed bool, log logrus.FieldLogger) (string, error) {
+	// the parameters stored with the previous plan schema version are migrated before they are compared and updated
+	if err := migrateStoredParameters(instance, provOperation); err != nil {
+		log.Errorf("unable to migrate stored parameters: %s", err)
+		return "", apiresponses.NewFailureResponse(errors.New("unable to migrate the stored parameters"), http.StatusUnprocessableEntity, "update")
+	}
+
+	changed, err := changedParameters(provOperation.ProvisioningParameters.Parameters, rawParameters)
+	if err != nil {
+		log.Errorf("unable to compare parameters: %s", err)
+		return "", apiresponses.NewFailureResponse(errors.New("unable to unmarshal parameters"), http.StatusBadRequest, "update")
+	}
+	if len(changed) == 0 {
+		return "", nil
+	}
+	if notSupported := requireFullUpgrade(changed); len(notSupported) > 0 {
+		err := fmt.Errorf("changing parameters %s requires a full upgrade which is not supported by the update", strings.Join(notSupported, ", "))
+		return "", apiresponses.NewFailureResponse(err, http.StatusUnprocessableEntity, "update")
+	}
+	if !asyncAllowed {
+		return "", apiresponses.ErrAsyncRequired
+	}
+	if err := checkInstanceLock(b.instanceLocks, instance.InstanceID); err != nil {
+		log.Infof("Update rejected: %s", err)
+		return "", apiresponses.NewFailureResponse(err, http.StatusConflict, "update")
+	}
+
+	lastOperation, err := b.operationStorage.GetLastOperation(instance.InstanceID)
+	if err != nil {
+		log.Errorf("unable to get last operation: %s", err)
+		return "", errors.New("unable to process the update")
+	}
+	if !lastOperation.IsFinished() {
+		err := fmt.Errorf("operation %s is in progress, the parameters cannot be updated until it is finished", lastOperation.ID)
+		return "", apiresponses.NewFailureResponse(err, http.StatusUnprocessableEntity, "update")
+	}
+
+	var requested internal.ProvisioningParametersDTO
+	if err := json.Unmarshal(rawParameters, &requested); err != nil {
+		return "", apiresponses.NewFailureResponse(errors.New("unable to unmarshal parameters"), http.StatusBadRequest, "update")
+	}
+	// the upgrade reads the parameters from the provisioning operation, so later upgrades keep the change as well
+	provOperation.ProvisioningParameters.Parameters.OptionalComponentsToInstall = requested.OptionalComponentsToInstall
+	if _, err := b.operationStorage.UpdateProvisioningOperation(*provOperation); err != nil {
+		log.Errorf("unable to update provisioning parameters: %s", err)
+		return "", errors.New("unable to process the update")
+	}
+	instance.Parameters.Parameters.OptionalComponentsToInstall = requested.OptionalComponentsToInstall
+
+	operation := newOverridesUpdateOperation(*instance, provOperation.ProvisioningParameters)
+	if err := b.operationStorage.InsertUpgradeKymaOperation(operation); err != nil {
+		log.Errorf("unable to save operation: %s", err)
+		return "", errors.New("unable to process the update")
+	}
+	log.Infof("Updating parameters %s by operation %s", strings.Join(changed, ", "), operation.Operation.ID)
+	b.overridesQueue.Add(operation.Operation.ID)
+
+	return operation.Operation.ID, nil
+}
+
+func migrateStoredParameters(instance *internal.Instance, provOperation *internal.ProvisioningOperation) error {
+	migrated, err := DefaultParametersMigrations.Migrate(provOperation.ProvisioningParameters)
+	if err != nil {
+		return err
+	}
+	provOperation.ProvisioningParameters = migrated
+
+	// the instance is stored at the end of the update, so the migrated parameters are kept even if no parameter was changed
+	migrated, err = DefaultParametersMigrations.Migrate(instance.Parameters)
+	if err != nil {
+		return err
+	}
+	instance.Parameters = migrated
+
+	return nil
+}
+
+// newOverridesUpdateOperation creates the upgrade Kyma operation which is not a part of any orchestration,
+// it starts in progress so it is not held back by the orchestration checks
+func newOverridesUpdateOperation(instance internal.Instance, parameters internal.ProvisioningParameters) internal.UpgradeKymaOperation {
+	id := uuid.New().String()
+	return internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:                     id,
+			CreatedAt:              time.Now(),
+			UpdatedAt:              time.Now(),
+			Type:                   internal.OperationTypeUpgradeKyma,
+			InstanceID:             instance.InstanceID,
+			State:                  domain.InProgress,
+			Description:            "Operation created: update of overrides",
+			ProvisioningParameters: parameters,
+			InstanceDetails:        instance.InstanceDetails,
+		},
+		RuntimeOperation: orchestration.RuntimeOperation{
+			ID: id,
+			Runtime: orchestration.Runtime{
+				InstanceID:      instance.InstanceID,
+				RuntimeID:       instance.RuntimeID,
+				GlobalAccountID: instance.GlobalAccountID,
+				SubAccountID:    instance.SubAccountID,
+			},
+		},
+	}
+}
+
+// resumeInstance resumes the instance suspended by the broker and stores it, the instance is returned unchanged
+// if no resumer is set or the instance was not suspended by the broker
+func resumeInstance(resumer InstanceResumer, instances storage.Instances, instance *internal.Instance, log logrus.FieldLogger) (*internal.Instance, error) {
+	if resumer == nil {
+		return instance, nil
+	}
+	resumed, err := resumer.Resume(instance)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return instance, nil
+	}
+	log.Infof("Instance suspended by the broker resumed")
+
+	return instances.Update(*instance)
+}
+
 func (b *UpdateEndpoint) exctractActiveValue(id string, provisioning internal.ProvisioningOperation) (*bool, error) {
 	deprovisioning, dErr := b.operationStorage.GetDeprovisioningOperationByInstanceID(id)
 	if dErr != nil && !dberr.IsNotFound(dErr) {
@@ -3,16 +3,25 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 type handler struct {
@@ -26,6 +35,19 @@ func (h *handler) Handle(inst *internal.Instance, ers internal.ERSContext) error
 	return nil
 }
 
+type resumer struct {
+	resumed []string
+}
+
+func (r *resumer) Resume(inst *internal.Instance) (bool, error) {
+	if inst.SuspensionReason != internal.SuspensionReasonIdle {
+		return false, nil
+	}
+	inst.SuspensionReason = ""
+	r.resumed = append(r.resumed, inst.InstanceID)
+	return true, nil
+}
+
 func TestUpdateEndpoint_UpdateSuspension(t *testing.T) {
 	// given
 	instance := internal.Instance{
@@ -49,7 +71,7 @@ func TestUpdateEndpoint_UpdateSuspension(t *testing.T) {
 	st.Operations().InsertProvisioningOperation(fixProvisioningOperation("02"))
 
 	handler := &handler{}
-	svc := NewUpdate(st.Instances(), st.Operations(), handler, true, logrus.New())
+	svc := NewUpdate(st.Instances(), st.Operations(), handler, featureflags.Static{featureflags.UpdateProcessing: true}, &automock.Queue{}, logrus.New())
 
 	// when
 	svc.Update(context.Background(), instanceID, domain.UpdateDetails{
@@ -99,7 +121,7 @@ func TestUpdateEndpoint_UpdateUnsuspension(t *testing.T) {
 	st.Operations().InsertDeprovisioningOperation(fixSuspensionOperation())
 
 	handler := &handler{}
-	svc := NewUpdate(st.Instances(), st.Operations(), handler, true, logrus.New())
+	svc := NewUpdate(st.Instances(), st.Operations(), handler, featureflags.Static{featureflags.UpdateProcessing: true}, &automock.Queue{}, logrus.New())
 
 	// when
 	svc.Update(context.Background(), instanceID, domain.UpdateDetails{
@@ -127,6 +149,53 @@ func TestUpdateEndpoint_UpdateUnsuspension(t *testing.T) {
 	assert.False(t, *handler.Instance.Parameters.ErsContext.Active)
 }
 
+func TestUpdateEndpoint_UpdateResumesIdleInstance(t *testing.T) {
+	for name, tc := range map[string]struct {
+		suspensionReason string
+		expectedResumed  []string
+	}{
+		"instance suspended because of idleness": {
+			suspensionReason: internal.SuspensionReasonIdle,
+			expectedResumed:  []string{instanceID},
+		},
+		"instance suspended by the platform": {
+			suspensionReason: internal.SuspensionReasonTrialExpired,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			instance := fixture.FixInstance(instanceID)
+			instance.ServicePlanID = TrialPlanID
+			instance.SuspensionReason = tc.suspensionReason
+			st := storage.NewMemoryStorage()
+			st.Instances().Insert(instance)
+			st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01"))
+			st.Operations().InsertDeprovisioningOperation(fixSuspensionOperation())
+
+			instanceResumer := &resumer{}
+			svc := NewUpdate(st.Instances(), st.Operations(), &handler{}, featureflags.Static{featureflags.UpdateProcessing: true}, &automock.Queue{}, logrus.New())
+			svc.SetInstanceResumer(instanceResumer)
+
+			// when
+			_, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+				PlanID:     TrialPlanID,
+				RawContext: json.RawMessage("{\"active\":false}"),
+			}, true)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResumed, instanceResumer.resumed)
+			inst, err := st.Instances().GetByID(instanceID)
+			require.NoError(t, err)
+			if tc.expectedResumed != nil {
+				assert.Empty(t, inst.SuspensionReason)
+			} else {
+				assert.Equal(t, tc.suspensionReason, inst.SuspensionReason)
+			}
+		})
+	}
+}
+
 func assertServiceManagerCreds(t *testing.T, dto *internal.ServiceManagerEntryDTO) {
 	assert.Equal(t, &internal.ServiceManagerEntryDTO{
 		Credentials: internal.ServiceManagerCredentials{
@@ -157,7 +226,7 @@ func TestUpdateEndpoint_UpdateInstanceWithWrongActiveValue(t *testing.T) {
 	st.Instances().Insert(instance)
 	st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01"))
 	handler := &handler{}
-	svc := NewUpdate(st.Instances(), st.Operations(), handler, true, logrus.New())
+	svc := NewUpdate(st.Instances(), st.Operations(), handler, featureflags.Static{featureflags.UpdateProcessing: true}, &automock.Queue{}, logrus.New())
 
 	// when
 	svc.Update(context.Background(), instanceID, domain.UpdateDetails{
@@ -182,6 +251,147 @@ func TestUpdateEndpoint_UpdateInstanceWithWrongActiveValue(t *testing.T) {
 	assert.True(t, *handler.Instance.Parameters.ErsContext.Active)
 }
 
+func TestUpdateEndpoint_UpdateProcessingToggledAtRuntime(t *testing.T) {
+	// given
+	instance := fixture.FixInstance(instanceID)
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	require.NoError(t, st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01")))
+
+	sch := runtime.NewScheme()
+	require.NoError(t, coreV1.AddToScheme(sch))
+	cli := fake.NewFakeClientWithScheme(sch)
+	flagsConfig := featureflags.Config{Namespace: "kcp-system", Name: "feature-flags"}
+	flags := featureflags.NewConfigMapProvider(cli, flagsConfig, map[string]bool{featureflags.UpdateProcessing: false}, logrus.New())
+	require.NoError(t, flags.Refresh(context.Background()))
+
+	handler := &handler{}
+	svc := NewUpdate(st.Instances(), st.Operations(), handler, flags, &automock.Queue{}, logrus.New())
+	details := domain.UpdateDetails{
+		PlanID:     instance.ServicePlanID,
+		RawContext: json.RawMessage("{\"active\":false}"),
+	}
+
+	// when
+	_, err := svc.Update(context.Background(), instanceID, details, true)
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, handler.Instance.InstanceID)
+
+	// when the flag is enabled in the ConfigMap
+	require.NoError(t, cli.Create(context.Background(), &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: flagsConfig.Name, Namespace: flagsConfig.Namespace},
+		Data:       map[string]string{featureflags.UpdateProcessing: "true"},
+	}))
+	require.NoError(t, flags.Refresh(context.Background()))
+	_, err = svc.Update(context.Background(), instanceID, details, true)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, instanceID, handler.Instance.InstanceID)
+	assert.Equal(t, ptr.Bool(false), handler.ersContext.Active)
+}
+
+func TestUpdateEndpoint_UpdateOverridesOnly(t *testing.T) {
+	// given
+	instance := fixture.FixInstance(instanceID)
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	require.NoError(t, st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01")))
+
+	queue := &automock.Queue{}
+	queue.On("Add", mock.AnythingOfType("string")).Return().Once()
+	svc := NewUpdate(st.Instances(), st.Operations(), &handler{}, featureflags.Static{featureflags.UpdateProcessing: true}, queue, logrus.New())
+
+	// when
+	response, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+		PlanID:        instance.ServicePlanID,
+		RawParameters: json.RawMessage(`{"name": "cluster-test", "components": ["Kiali"]}`),
+		RawContext:    json.RawMessage("{}"),
+	}, true)
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, response.IsAsync)
+	queue.AssertExpectations(t)
+
+	operation, err := st.Operations().GetUpgradeKymaOperationByID(response.OperationData)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InProgress, operation.State)
+	assert.Empty(t, operation.OrchestrationID)
+	assert.Equal(t, []string{"Kiali"}, operation.ProvisioningParameters.Parameters.OptionalComponentsToInstall)
+
+	provisioning, err := st.Operations().GetProvisioningOperationByInstanceID(instanceID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Kiali"}, provisioning.ProvisioningParameters.Parameters.OptionalComponentsToInstall)
+	inst, err := st.Instances().GetByID(instanceID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Kiali"}, inst.Parameters.Parameters.OptionalComponentsToInstall)
+}
+
+func TestUpdateEndpoint_UpdateRequiringFullUpgrade(t *testing.T) {
+	// given
+	instance := fixture.FixInstance(instanceID)
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	require.NoError(t, st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01")))
+
+	queue := &automock.Queue{}
+	svc := NewUpdate(st.Instances(), st.Operations(), &handler{}, featureflags.Static{featureflags.UpdateProcessing: true}, queue, logrus.New())
+
+	// when
+	_, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+		PlanID:        instance.ServicePlanID,
+		RawParameters: json.RawMessage(`{"machineType": "m5.2xlarge", "components": ["Kiali"]}`),
+		RawContext:    json.RawMessage("{}"),
+	}, true)
+
+	// then
+	require.Error(t, err)
+	failure, ok := err.(*apiresponses.FailureResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, failure.ValidatedStatusCode(nil))
+	assert.Contains(t, err.Error(), "machineType")
+	queue.AssertNotCalled(t, "Add", mock.Anything)
+
+	_, err = st.Operations().GetUpgradeKymaOperationByInstanceID(instanceID)
+	assert.Error(t, err)
+	provisioning, err := st.Operations().GetProvisioningOperationByInstanceID(instanceID)
+	require.NoError(t, err)
+	assert.Empty(t, provisioning.ProvisioningParameters.Parameters.OptionalComponentsToInstall)
+}
+
+func TestUpdateEndpoint_UpdateRejectsCustomDomainChange(t *testing.T) {
+	// given
+	instance := fixture.FixInstance(instanceID)
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	require.NoError(t, st.Operations().InsertProvisioningOperation(fixProvisioningOperation("01")))
+
+	queue := &automock.Queue{}
+	svc := NewUpdate(st.Instances(), st.Operations(), &handler{}, featureflags.Static{featureflags.UpdateProcessing: true}, queue, logrus.New())
+
+	// when
+	_, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+		PlanID:        instance.ServicePlanID,
+		RawParameters: json.RawMessage(`{"customDomain": "kyma.example.com"}`),
+		RawContext:    json.RawMessage("{}"),
+	}, true)
+
+	// then
+	require.Error(t, err)
+	failure, ok := err.(*apiresponses.FailureResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, failure.ValidatedStatusCode(nil))
+	assert.Contains(t, err.Error(), "customDomain")
+	queue.AssertNotCalled(t, "Add", mock.Anything)
+
+	inst, err := st.Instances().GetByID(instanceID)
+	require.NoError(t, err)
+	assert.Empty(t, inst.CustomDomain())
+}
+
 func fixProvisioningOperation(id string) internal.ProvisioningOperation {
 	provisioningOperation := fixture.FixProvisioningOperation(id, instanceID)
 	provisioningOperation.ProvisioningParameters.ErsContext.ServiceManager.URL = ""
@@ -0,0 +1,138 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	minKubeletMaxPods      = 16
+	maxKubeletMaxPods      = 250
+	minKubeletPodPIDsLimit = 100
+)
+
+// evictionSignals are the kubelet hard eviction signals which can be requested in the kubernetesConfig parameter
+var evictionSignals = []string{
+	internal.EvictionMemoryAvailable,
+	internal.EvictionImageFSAvailable,
+	internal.EvictionImageFSInodesFree,
+	internal.EvictionNodeFSAvailable,
+	internal.EvictionNodeFSInodesFree,
+}
+
+// KubernetesConfigAllowlist lists the feature gates of the kubelet and the API server which can be requested
+// in the kubernetesConfig provisioning parameter, the feature gates outside the lists are rejected
+type KubernetesConfigAllowlist struct {
+	KubeletFeatureGates   []string
+	APIServerFeatureGates []string
+}
+
+// Register adds the validators of the kubelet and API server options, the trial plan always uses the defaults
+func (a KubernetesConfigAllowlist) Register(validators PlanParametersValidators) {
+	for _, planID := range PlanIDsMapping {
+		if planID == TrialPlanID {
+			continue
+		}
+		validators.Register(planID, ParametersValidatorFunc(a.validate))
+	}
+	validators.Register(TrialPlanID, ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		if parameters.KubernetesConfig == nil {
+			return nil
+		}
+		return []ParameterError{{Parameter: "kubernetesConfig", Message: "is not supported for the plan"}}
+	}))
+}
+
+func (a KubernetesConfigAllowlist) validate(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	config := parameters.KubernetesConfig
+	if config == nil {
+		return nil
+	}
+
+	var violations []ParameterError
+	if kubelet := config.Kubelet; kubelet != nil {
+		violations = append(violations, validateFeatureGates("kubernetesConfig.kubelet.featureGates", kubelet.FeatureGates, a.KubeletFeatureGates)...)
+		violations = append(violations, validateEvictionThresholds(kubelet.EvictionHard)...)
+		if kubelet.MaxPods != nil && (*kubelet.MaxPods < minKubeletMaxPods || *kubelet.MaxPods > maxKubeletMaxPods) {
+			violations = append(violations, ParameterError{
+				Parameter: "kubernetesConfig.kubelet.maxPods",
+				Message:   fmt.Sprintf("must be between %d and %d, got %d", minKubeletMaxPods, maxKubeletMaxPods, *kubelet.MaxPods),
+			})
+		}
+		if kubelet.PodPIDsLimit != nil && *kubelet.PodPIDsLimit < minKubeletPodPIDsLimit {
+			violations = append(violations, ParameterError{
+				Parameter: "kubernetesConfig.kubelet.podPidsLimit",
+				Message:   fmt.Sprintf("must be at least %d, got %d", minKubeletPodPIDsLimit, *kubelet.PodPIDsLimit),
+			})
+		}
+	}
+	if apiServer := config.APIServer; apiServer != nil {
+		violations = append(violations, validateFeatureGates("kubernetesConfig.apiServer.featureGates", apiServer.FeatureGates, a.APIServerFeatureGates)...)
+	}
+	return violations
+}
+
+func validateFeatureGates(parameter string, featureGates map[string]bool, allowed []string) []ParameterError {
+	names := make([]string, 0, len(featureGates))
+	for name := range featureGates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []ParameterError
+	for _, name := range names {
+		if contains(allowed, name) {
+			continue
+		}
+		message := fmt.Sprintf("feature gate %q cannot be requested", name)
+		if len(allowed) > 0 {
+			message = fmt.Sprintf("%s, allowed feature gates: %s", message, strings.Join(allowed, ", "))
+		}
+		violations = append(violations, ParameterError{Parameter: parameter, Message: message})
+	}
+	return violations
+}
+
+// validateEvictionThresholds checks if the thresholds are set for the known signals and are quantities, e.g. 100Mi,
+// or percentages of the available resource, e.g. 10%
+func validateEvictionThresholds(thresholds map[string]string) []ParameterError {
+	signals := make([]string, 0, len(thresholds))
+	for signal := range thresholds {
+		signals = append(signals, signal)
+	}
+	sort.Strings(signals)
+
+	var violations []ParameterError
+	for _, signal := range signals {
+		parameter := fmt.Sprintf("kubernetesConfig.kubelet.evictionHard.%s", signal)
+		if !contains(evictionSignals, signal) {
+			violations = append(violations, ParameterError{
+				Parameter: parameter,
+				Message:   fmt.Sprintf("eviction signal is not supported, supported signals: %s", strings.Join(evictionSignals, ", ")),
+			})
+			continue
+		}
+		if !isEvictionThreshold(thresholds[signal]) {
+			violations = append(violations, ParameterError{
+				Parameter: parameter,
+				Message:   fmt.Sprintf("must be a quantity or a percentage between 0%% and 100%%, got %q", thresholds[signal]),
+			})
+		}
+	}
+	return violations
+}
+
+func isEvictionThreshold(value string) bool {
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		return err == nil && percentage > 0 && percentage < 100
+	}
+	quantity, err := resource.ParseQuantity(value)
+	return err == nil && quantity.Sign() > 0
+}
@@ -0,0 +1,86 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesConfigAllowlist(t *testing.T) {
+	// given
+	validators := PlanParametersValidators{}
+	KubernetesConfigAllowlist{
+		KubeletFeatureGates:   []string{"CPUManager"},
+		APIServerFeatureGates: []string{"TTLAfterFinished"},
+	}.Register(validators)
+
+	for name, tc := range map[string]struct {
+		planID         string
+		config         *internal.KubernetesConfig
+		expectedErrors []ParameterError
+	}{
+		"defaults": {
+			planID: AzurePlanID,
+		},
+		"allowed options": {
+			planID: GCPPlanID,
+			config: &internal.KubernetesConfig{
+				Kubelet: &internal.KubeletConfig{
+					FeatureGates: map[string]bool{"CPUManager": true},
+					EvictionHard: map[string]string{internal.EvictionMemoryAvailable: "200Mi", internal.EvictionNodeFSAvailable: "10%"},
+					MaxPods:      ptr.Integer(110),
+					PodPIDsLimit: ptr.Integer(4096),
+				},
+				APIServer: &internal.APIServerConfig{FeatureGates: map[string]bool{"TTLAfterFinished": false}},
+			},
+		},
+		"disallowed feature gate": {
+			planID: AWSPlanID,
+			config: &internal.KubernetesConfig{
+				APIServer: &internal.APIServerConfig{FeatureGates: map[string]bool{"TTLAfterFinished": true, "EphemeralContainers": true}},
+			},
+			expectedErrors: []ParameterError{
+				{Parameter: "kubernetesConfig.apiServer.featureGates", Message: `feature gate "EphemeralContainers" cannot be requested, allowed feature gates: TTLAfterFinished`},
+			},
+		},
+		"invalid kubelet values": {
+			planID: AzureLitePlanID,
+			config: &internal.KubernetesConfig{
+				Kubelet: &internal.KubeletConfig{
+					EvictionHard: map[string]string{internal.EvictionImageFSAvailable: "120%", "pid.available": "10%"},
+					MaxPods:      ptr.Integer(500),
+					PodPIDsLimit: ptr.Integer(10),
+				},
+			},
+			expectedErrors: []ParameterError{
+				{Parameter: "kubernetesConfig.kubelet.evictionHard.imageFSAvailable", Message: `must be a quantity or a percentage between 0% and 100%, got "120%"`},
+				{Parameter: "kubernetesConfig.kubelet.evictionHard.pid.available", Message: "eviction signal is not supported, supported signals: memoryAvailable, imageFSAvailable, imageFSInodesFree, nodeFSAvailable, nodeFSInodesFree"},
+				{Parameter: "kubernetesConfig.kubelet.maxPods", Message: "must be between 16 and 250, got 500"},
+				{Parameter: "kubernetesConfig.kubelet.podPidsLimit", Message: "must be at least 100, got 10"},
+			},
+		},
+		"trial plan": {
+			planID: TrialPlanID,
+			config: &internal.KubernetesConfig{Kubelet: &internal.KubeletConfig{MaxPods: ptr.Integer(110)}},
+			expectedErrors: []ParameterError{
+				{Parameter: "kubernetesConfig", Message: "is not supported for the plan"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validators.Validate(tc.planID, internal.ProvisioningParametersDTO{KubernetesConfig: tc.config})
+
+			// then
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, ParametersValidationError{}, err)
+			assert.Equal(t, tc.expectedErrors, err.(ParametersValidationError).Errors)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package broker
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validateKubernetesVersion checks if the requested Kubernetes version is one of the supported versions.
+// No version means that the default version from the configuration is used.
+func validateKubernetesVersion(version *string, supported []string) error {
+	if version == nil {
+		return nil
+	}
+	for _, v := range supported {
+		if v == *version {
+			return nil
+		}
+	}
+	if len(supported) == 0 {
+		return errors.New("requesting the Kubernetes version is not supported")
+	}
+
+	return errors.Errorf("Kubernetes version %q is not supported, supported versions: %s", *version, strings.Join(supported, ", "))
+}
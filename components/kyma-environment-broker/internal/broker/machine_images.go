@@ -0,0 +1,116 @@
+package broker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const openstackHyperscaler = "openstack"
+
+var machineImageHyperscalers = map[string]string{
+	AzurePlanID:     azureHyperscaler,
+	AzureLitePlanID: azureHyperscaler,
+	AWSPlanID:       awsHyperscaler,
+	GCPPlanID:       gcpHyperscaler,
+	OpenStackPlanID: openstackHyperscaler,
+}
+
+// MachineImage defines the operating system of the nodes, e.g. gardenlinux, and its versions which can be requested.
+// The version of the image determines the container runtime of the nodes.
+type MachineImage struct {
+	Name     string   `yaml:"name"`
+	Versions []string `yaml:"versions"`
+}
+
+// MachineImages maps a hyperscaler (azure, aws, gcp, openstack) to the machine images of the nodes which can be requested,
+// the hyperscalers which are not configured use the default machine image from the configuration
+type MachineImages map[string][]MachineImage
+
+// NewMachineImagesFromFile reads the supported machine images from the YAML file, empty path means no machine image can be requested
+func NewMachineImagesFromFile(path string) (MachineImages, error) {
+	if path == "" {
+		return MachineImages{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with machine images", path)
+	}
+	var machineImagesConfig struct {
+		Hyperscalers MachineImages `yaml:"hyperscalers"`
+	}
+	err = yaml.Unmarshal(yamlFile, &machineImagesConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with machine images")
+	}
+	if machineImagesConfig.Hyperscalers == nil {
+		return MachineImages{}, nil
+	}
+	for hyperscaler, images := range machineImagesConfig.Hyperscalers {
+		for _, image := range images {
+			if image.Name == "" || len(image.Versions) == 0 {
+				return nil, errors.Errorf("machine image of hyperscaler %q must have a name and at least one version", hyperscaler)
+			}
+		}
+	}
+
+	return machineImagesConfig.Hyperscalers, nil
+}
+
+// Register adds the validators of the requested machine image to the plans supporting it
+func (m MachineImages) Register(validators PlanParametersValidators) {
+	for planID, hyperscaler := range machineImageHyperscalers {
+		validators.Register(planID, m.validator(hyperscaler))
+	}
+	validators.Register(TrialPlanID, ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		if parameters.MachineImage == nil && parameters.MachineImageVersion == nil {
+			return nil
+		}
+		return []ParameterError{{Parameter: "machineImage", Message: "is not supported for the plan"}}
+	}))
+}
+
+// validator checks if the machine image and its version are supported by the hyperscaler.
+// No machine image means that the default one from the configuration is used.
+func (m MachineImages) validator(hyperscaler string) ParametersValidator {
+	return ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		if parameters.MachineImage == nil && parameters.MachineImageVersion == nil {
+			return nil
+		}
+		// the default version from the configuration belongs to the default image, so the image and the version are requested together
+		if parameters.MachineImage == nil {
+			return []ParameterError{{Parameter: "machineImage", Message: "must be specified together with machineImageVersion"}}
+		}
+		if parameters.MachineImageVersion == nil {
+			return []ParameterError{{Parameter: "machineImageVersion", Message: "must be specified together with machineImage"}}
+		}
+
+		var supportedNames []string
+		for _, image := range m[hyperscaler] {
+			if image.Name != *parameters.MachineImage {
+				supportedNames = append(supportedNames, image.Name)
+				continue
+			}
+			if contains(image.Versions, *parameters.MachineImageVersion) {
+				return nil
+			}
+			return []ParameterError{{
+				Parameter: "machineImageVersion",
+				Message:   fmt.Sprintf("version %q of machine image %q is not supported, supported versions: %s", *parameters.MachineImageVersion, image.Name, strings.Join(image.Versions, ", ")),
+			}}
+		}
+		if len(supportedNames) == 0 {
+			return []ParameterError{{Parameter: "machineImage", Message: "requesting the machine image is not supported"}}
+		}
+
+		return []ParameterError{{
+			Parameter: "machineImage",
+			Message:   fmt.Sprintf("machine image %q is not supported, supported machine images: %s", *parameters.MachineImage, strings.Join(supportedNames, ", ")),
+		}}
+	})
+}
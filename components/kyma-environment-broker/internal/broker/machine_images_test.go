@@ -0,0 +1,97 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMachineImages_Register(t *testing.T) {
+	// given
+	machineImages, err := NewMachineImagesFromFile("testdata/machine_images.yaml")
+	require.NoError(t, err)
+	validators := PlanParametersValidators{}
+	machineImages.Register(validators)
+
+	for name, tc := range map[string]struct {
+		planID              string
+		machineImage        *string
+		machineImageVersion *string
+		expectErr           bool
+	}{
+		"default machine image": {
+			planID: AzurePlanID,
+		},
+		"supported machine image and version": {
+			planID:              AWSPlanID,
+			machineImage:        ptr.String("ubuntu"),
+			machineImageVersion: ptr.String("18.4.20201029"),
+		},
+		"supported machine image and version of the plan hyperscaler": {
+			planID:              AzureLitePlanID,
+			machineImage:        ptr.String("gardenlinux"),
+			machineImageVersion: ptr.String("184.0.0"),
+		},
+		"machine image not supported by the hyperscaler": {
+			planID:              AzurePlanID,
+			machineImage:        ptr.String("ubuntu"),
+			machineImageVersion: ptr.String("18.4.20201029"),
+			expectErr:           true,
+		},
+		"version not supported for the machine image": {
+			planID:              AWSPlanID,
+			machineImage:        ptr.String("gardenlinux"),
+			machineImageVersion: ptr.String("184.0.0"),
+			expectErr:           true,
+		},
+		"hyperscaler without machine images": {
+			planID:              GCPPlanID,
+			machineImage:        ptr.String("gardenlinux"),
+			machineImageVersion: ptr.String("318.8.0"),
+			expectErr:           true,
+		},
+		"machine image without version": {
+			planID:       AWSPlanID,
+			machineImage: ptr.String("gardenlinux"),
+			expectErr:    true,
+		},
+		"version without machine image": {
+			planID:              AWSPlanID,
+			machineImageVersion: ptr.String("318.8.0"),
+			expectErr:           true,
+		},
+		"trial plan": {
+			planID:              TrialPlanID,
+			machineImage:        ptr.String("gardenlinux"),
+			machineImageVersion: ptr.String("318.8.0"),
+			expectErr:           true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validators.Validate(tc.planID, internal.ProvisioningParametersDTO{
+				MachineImage:        tc.machineImage,
+				MachineImageVersion: tc.machineImageVersion,
+			})
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewMachineImagesFromFile_EmptyPath(t *testing.T) {
+	// when
+	machineImages, err := NewMachineImagesFromFile("")
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, machineImages)
+}
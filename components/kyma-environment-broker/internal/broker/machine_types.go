@@ -0,0 +1,81 @@
+package broker
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// PlanMachineType defines the machine type used when the client does not specify one
+// and the machine types which can be requested for the plan
+type PlanMachineType struct {
+	Default string   `yaml:"default"`
+	Allowed []string `yaml:"allowed"`
+}
+
+// PlanMachineTypes maps a plan name to its machine types, plans which are not configured
+// use the default machine type of the hyperscaler and the machine types from the plan schema
+type PlanMachineTypes map[string]PlanMachineType
+
+// NewPlanMachineTypesFromFile reads the machine types of the plans from the YAML file, empty path means no plan is configured
+func NewPlanMachineTypesFromFile(path string) (PlanMachineTypes, error) {
+	if path == "" {
+		return PlanMachineTypes{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with plan machine types", path)
+	}
+	var machineTypesConfig struct {
+		Plans PlanMachineTypes `yaml:"plans"`
+	}
+	err = yaml.Unmarshal(yamlFile, &machineTypesConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with plan machine types")
+	}
+	if machineTypesConfig.Plans == nil {
+		return PlanMachineTypes{}, nil
+	}
+	for planName, machineTypes := range machineTypesConfig.Plans {
+		if _, found := PlanIDsMapping[planName]; !found {
+			return nil, errors.Errorf("machine types are configured for unknown plan %q", planName)
+		}
+		if machineTypes.Default != "" && len(machineTypes.Allowed) > 0 && !contains(machineTypes.Allowed, machineTypes.Default) {
+			return nil, errors.Errorf("default machine type %q of plan %q is not allowed", machineTypes.Default, planName)
+		}
+	}
+
+	return machineTypesConfig.Plans, nil
+}
+
+// Apply validates the machine type requested for the plan or, if no machine type is requested,
+// returns the default one of the plan. Nil means that the default machine type of the hyperscaler is used.
+func (m PlanMachineTypes) Apply(planID string, machineType *string) (*string, error) {
+	machineTypes, found := m[PlanNamesMapping[planID]]
+	if !found {
+		return machineType, nil
+	}
+	if machineType == nil {
+		if machineTypes.Default == "" {
+			return nil, nil
+		}
+		defaultMachineType := machineTypes.Default
+		return &defaultMachineType, nil
+	}
+	if len(machineTypes.Allowed) > 0 && !contains(machineTypes.Allowed, *machineType) {
+		return nil, errors.Errorf("machine type %q is not allowed for the plan, allowed machine types: %s", *machineType, strings.Join(machineTypes.Allowed, ", "))
+	}
+
+	return machineType, nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
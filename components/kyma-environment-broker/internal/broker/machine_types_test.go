@@ -0,0 +1,69 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanMachineTypes_Apply(t *testing.T) {
+	// given
+	machineTypes, err := NewPlanMachineTypesFromFile("testdata/machine_types.yaml")
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		planID              string
+		machineType         *string
+		expectedMachineType *string
+		expectErr           bool
+	}{
+		"default machine type of the plan": {
+			planID:              AWSPlanID,
+			expectedMachineType: ptr.String("m5.2xlarge"),
+		},
+		"allowed machine type": {
+			planID:              AWSPlanID,
+			machineType:         ptr.String("m5.4xlarge"),
+			expectedMachineType: ptr.String("m5.4xlarge"),
+		},
+		"not allowed machine type": {
+			planID:      AzurePlanID,
+			machineType: ptr.String("Standard_D16_v3"),
+			expectErr:   true,
+		},
+		"plan without machine types": {
+			planID:              GCPPlanID,
+			machineType:         ptr.String("n1-standard-8"),
+			expectedMachineType: ptr.String("n1-standard-8"),
+		},
+		"plan without machine types and no machine type requested": {
+			planID: GCPPlanID,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			machineType, err := machineTypes.Apply(tc.planID, tc.machineType)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMachineType, machineType)
+		})
+	}
+}
+
+func TestNewPlanMachineTypesFromFile_EmptyPath(t *testing.T) {
+	// when
+	machineTypes, err := NewPlanMachineTypesFromFile("")
+
+	// then
+	require.NoError(t, err)
+	machineType, err := machineTypes.Apply(AzurePlanID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, machineType)
+}
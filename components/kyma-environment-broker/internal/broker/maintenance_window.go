@@ -0,0 +1,35 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+// MaintenanceWindowBeginLayout is the layout of the local time at which the maintenance window starts
+const MaintenanceWindowBeginLayout = "15:04"
+
+// validateMaintenanceWindow checks that the maintenance window starts at a valid local time in a valid IANA time zone,
+// no maintenance window means the default window of the landscape is used
+func validateMaintenanceWindow(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	window := parameters.MaintenanceWindow
+	if window == nil {
+		return nil
+	}
+	var violations []ParameterError
+	if _, err := time.Parse(MaintenanceWindowBeginLayout, window.Begin); err != nil {
+		violations = append(violations, ParameterError{
+			Parameter: "maintenanceWindow.begin",
+			Message:   fmt.Sprintf("must be a time in the HH:MM format, got %q", window.Begin),
+		})
+	}
+	// time.LoadLocation accepts also the empty name as UTC and "Local" as the zone of the broker, they are not IANA zones
+	if _, err := time.LoadLocation(window.TimeZone); err != nil || window.TimeZone == "" || window.TimeZone == "Local" {
+		violations = append(violations, ParameterError{
+			Parameter: "maintenanceWindow.timeZone",
+			Message:   fmt.Sprintf("must be an IANA time zone, e.g. Europe/Berlin, got %q", window.TimeZone),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowValidator(t *testing.T) {
+	// given
+	validators := NewDefaultParametersValidators()
+
+	for name, tc := range map[string]struct {
+		window         *internal.MaintenanceWindow
+		expectedErrors []ParameterError
+	}{
+		"default window": {},
+		"window in time zone": {
+			window: &internal.MaintenanceWindow{Begin: "22:30", TimeZone: "Europe/Berlin"},
+		},
+		"invalid time zone": {
+			window: &internal.MaintenanceWindow{Begin: "22:30", TimeZone: "Europe/Atlantis"},
+			expectedErrors: []ParameterError{
+				{Parameter: "maintenanceWindow.timeZone", Message: `must be an IANA time zone, e.g. Europe/Berlin, got "Europe/Atlantis"`},
+			},
+		},
+		"local time zone of the broker": {
+			window: &internal.MaintenanceWindow{Begin: "22:30", TimeZone: "Local"},
+			expectedErrors: []ParameterError{
+				{Parameter: "maintenanceWindow.timeZone", Message: `must be an IANA time zone, e.g. Europe/Berlin, got "Local"`},
+			},
+		},
+		"invalid begin and missing time zone": {
+			window: &internal.MaintenanceWindow{Begin: "24:00"},
+			expectedErrors: []ParameterError{
+				{Parameter: "maintenanceWindow.begin", Message: `must be a time in the HH:MM format, got "24:00"`},
+				{Parameter: "maintenanceWindow.timeZone", Message: `must be an IANA time zone, e.g. Europe/Berlin, got ""`},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validators.Validate(AzurePlanID, internal.ProvisioningParametersDTO{MaintenanceWindow: tc.window})
+
+			// then
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, ParametersValidationError{}, err)
+			assert.Equal(t, tc.expectedErrors, err.(ParametersValidationError).Errors)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cache"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+)
+
+type OperationCacheConfig struct {
+	// TTL defines how long the operation in progress polled by the last operation endpoint is served from the memory,
+	// zero disables the cache
+	TTL time.Duration `envconfig:"default=0"`
+	// TerminalTTL defines how long the succeeded or failed operation is served from the memory
+	TerminalTTL time.Duration `envconfig:"default=1m"`
+	// Size is the maximum number of the cached operations
+	Size int `envconfig:"default=1000"`
+}
+
+// OperationCache keeps the operations polled by the last operation endpoint, so the repeated polls do not read the storage.
+// The entry of the operation is invalidated when the operation manager publishes the processed step or the failure of the operation.
+// The events are processed asynchronously, so the invalidated entry keeps the version of the operation and the operations
+// of the older versions, read from the storage before the update, are not cached any more.
+// The updates which are not published (e.g. made after the step was processed) are visible after the TTL at the latest,
+// which is why the operations in progress are cached shortly and the finished operations, which do not change any more, longer.
+// The entries are removed only in the memory of this process, the updates made by the other replicas are not visible until the TTL passes.
+type OperationCache struct {
+	terminalTTL time.Duration
+	cache       *cache.Expiring
+
+	// mu makes the version check and the write of the entry atomic
+	mu sync.Mutex
+}
+
+// invalidatedOperation is the entry of the operation updated to the version after the operation was cached
+type invalidatedOperation struct {
+	version int
+}
+
+// NewOperationCache returns the cache of the operations, the cache is disabled if the TTL or the size is not positive
+func NewOperationCache(cfg OperationCacheConfig) *OperationCache {
+	return &OperationCache{
+		terminalTTL: cfg.TerminalTTL,
+		cache:       cache.NewExpiring(cfg.TTL, cfg.Size),
+	}
+}
+
+// Subscribe makes the cache drop the operations whose step was processed or which failed
+func (c *OperationCache) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(process.ProvisioningStepProcessed{}, c.onStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, c.onStepProcessed)
+	sub.Subscribe(process.UpgradeKymaStepProcessed{}, c.onStepProcessed)
+	sub.Subscribe(process.UpgradeClusterStepProcessed{}, c.onStepProcessed)
+	sub.Subscribe(process.OperationFailed{}, c.onStepProcessed)
+}
+
+// Get returns the cached operation, false is returned if the operation is not cached or its entry expired
+func (c *OperationCache) Get(operationID string) (internal.Operation, bool) {
+	if c == nil {
+		return internal.Operation{}, false
+	}
+	cached, found := c.cache.Get(operationID)
+	if !found {
+		return internal.Operation{}, false
+	}
+	operation, ok := cached.(internal.Operation)
+	return operation, ok
+}
+
+// Set caches the operation read from the storage, the operation is not cached if the cached or invalidated one is newer
+func (c *OperationCache) Set(operation internal.Operation) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version, found := c.version(operation.ID); found && version > operation.Version {
+		return
+	}
+	if operation.State == domain.Succeeded || operation.State == domain.Failed {
+		c.cache.SetWithTTL(operation.ID, operation, c.terminalTTL)
+		return
+	}
+	c.cache.Set(operation.ID, operation)
+}
+
+// Invalidate removes the operation from the cache, the next poll reads it from the storage.
+// The operations older than the given one are not cached until the TTL passes.
+func (c *OperationCache) Invalidate(operation internal.Operation) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version, found := c.version(operation.ID); found && version > operation.Version {
+		return
+	}
+	c.cache.Set(operation.ID, invalidatedOperation{version: operation.Version})
+}
+
+// version returns the version of the cached or invalidated operation
+func (c *OperationCache) version(operationID string) (int, bool) {
+	cached, found := c.cache.Get(operationID)
+	if !found {
+		return 0, false
+	}
+	switch entry := cached.(type) {
+	case internal.Operation:
+		return entry.Version, true
+	case invalidatedOperation:
+		return entry.version, true
+	}
+	return 0, false
+}
+
+func (c *OperationCache) onStepProcessed(_ context.Context, ev interface{}) error {
+	switch e := ev.(type) {
+	case process.ProvisioningStepProcessed:
+		c.Invalidate(e.Operation.Operation)
+	case process.DeprovisioningStepProcessed:
+		c.Invalidate(e.Operation.Operation)
+	case process.UpgradeKymaStepProcessed:
+		c.Invalidate(e.Operation.Operation)
+	case process.UpgradeClusterStepProcessed:
+		c.Invalidate(e.Operation.Operation)
+	case process.OperationFailed:
+		c.Invalidate(e.Operation)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	expiring "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cache"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationCache_TTL(t *testing.T) {
+	// given
+	now := time.Now()
+	cache := NewOperationCache(OperationCacheConfig{TTL: 5 * time.Second, TerminalTTL: time.Minute, Size: 10})
+	cache.cache = expiring.NewExpiringWithClock(5*time.Second, 10, func() time.Time { return now })
+
+	inProgress := fixOperationInState("op-in-progress", domain.InProgress)
+	succeeded := fixOperationInState("op-succeeded", domain.Succeeded)
+	cache.Set(inProgress)
+	cache.Set(succeeded)
+
+	// when
+	cached, found := cache.Get("op-in-progress")
+
+	// then
+	assert.True(t, found)
+	assert.Equal(t, inProgress, cached)
+
+	// when
+	now = now.Add(10 * time.Second)
+
+	// then
+	_, found = cache.Get("op-in-progress")
+	assert.False(t, found)
+	_, found = cache.Get("op-succeeded")
+	assert.True(t, found)
+
+	// when
+	now = now.Add(time.Minute)
+
+	// then
+	_, found = cache.Get("op-succeeded")
+	assert.False(t, found)
+}
+
+func TestOperationCache_Size(t *testing.T) {
+	// given
+	now := time.Now()
+	cache := NewOperationCache(OperationCacheConfig{TTL: time.Minute, TerminalTTL: time.Minute, Size: 2})
+	cache.cache = expiring.NewExpiringWithClock(time.Minute, 2, func() time.Time { return now })
+
+	// when
+	cache.Set(fixOperationInState("op-1", domain.InProgress))
+	now = now.Add(time.Second)
+	cache.Set(fixOperationInState("op-2", domain.InProgress))
+	cache.Set(fixOperationInState("op-3", domain.InProgress))
+
+	// then
+	_, found := cache.Get("op-1")
+	assert.False(t, found)
+	_, found = cache.Get("op-2")
+	assert.True(t, found)
+	_, found = cache.Get("op-3")
+	assert.True(t, found)
+}
+
+func TestOperationCache_Disabled(t *testing.T) {
+	// given
+	cache := NewOperationCache(OperationCacheConfig{TTL: 0, TerminalTTL: time.Minute, Size: 10})
+
+	// when
+	cache.Set(fixOperationInState("op-1", domain.Succeeded))
+
+	// then
+	_, found := cache.Get("op-1")
+	assert.False(t, found)
+}
+
+func TestOperationCache_RejectsOutdatedOperations(t *testing.T) {
+	// given
+	cache := NewOperationCache(OperationCacheConfig{TTL: time.Minute, TerminalTTL: time.Minute, Size: 10})
+	polled := fixOperationInState("op-1", domain.InProgress)
+	polled.Version = 1
+	failed := fixOperationInState("op-1", domain.Failed)
+	failed.Version = 2
+
+	// when
+	cache.Invalidate(failed)
+	cache.Set(polled)
+
+	// then
+	_, found := cache.Get("op-1")
+	assert.False(t, found)
+
+	// when
+	cache.Set(failed)
+	cache.Set(polled)
+
+	// then
+	cached, found := cache.Get("op-1")
+	assert.True(t, found)
+	assert.Equal(t, failed, cached)
+
+	// when
+	cache.Invalidate(polled)
+
+	// then
+	cached, found = cache.Get("op-1")
+	assert.True(t, found)
+	assert.Equal(t, failed, cached)
+}
+
+func TestOperationCache_InvalidatesFailedOperations(t *testing.T) {
+	// given
+	cache := NewOperationCache(OperationCacheConfig{TTL: time.Minute, TerminalTTL: time.Minute, Size: 10})
+	inProgress := fixOperationInState("op-1", domain.InProgress)
+	cache.Set(inProgress)
+	failed := inProgress
+	failed.State = domain.Failed
+	failed.Version = inProgress.Version + 1
+
+	// when
+	err := cache.onStepProcessed(context.TODO(), process.OperationFailed{Operation: failed})
+
+	// then
+	require.NoError(t, err)
+	_, found := cache.Get("op-1")
+	assert.False(t, found)
+}
+
+func fixOperationInState(id string, state domain.LastOperationState) internal.Operation {
+	operation := fixture.FixOperation(id, "instance-id", internal.OperationTypeProvision)
+	operation.State = state
+	return operation
+}
@@ -0,0 +1,151 @@
+package broker
+
+import (
+	"encoding/json"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/kyma-incubator/compass/components/director/pkg/jsonschema"
+	"github.com/pkg/errors"
+)
+
+// ParametersMigration transforms the provisioning parameters stored with the previous plan schema version,
+// the parameters are passed as the decoded JSON object so the fields removed from the schema can be migrated as well
+type ParametersMigration func(parameters map[string]interface{}) (map[string]interface{}, error)
+
+// ParametersMigrationKey identifies the migration of the plan parameters between the schema versions
+type ParametersMigrationKey struct {
+	PlanID      string
+	FromVersion int
+	ToVersion   int
+}
+
+// parametersMigrations lists the migrations of the stored provisioning parameters, add an entry when a plan schema
+// changes in a way that the parameters stored with the previous schema version do not validate anymore
+var parametersMigrations = map[ParametersMigrationKey]ParametersMigration{}
+
+// DefaultParametersMigrations is the registry of the migrations applied by the update and upgrade paths
+var DefaultParametersMigrations = NewParametersMigrationRegistry(parametersMigrations)
+
+// ParametersMigrationRegistry migrates the stored provisioning parameters forward to the current plan schema version
+type ParametersMigrationRegistry struct {
+	migrations map[ParametersMigrationKey]ParametersMigration
+	versions   map[string]int
+}
+
+func NewParametersMigrationRegistry(migrations map[ParametersMigrationKey]ParametersMigration) *ParametersMigrationRegistry {
+	registry := &ParametersMigrationRegistry{
+		migrations: map[ParametersMigrationKey]ParametersMigration{},
+		versions:   map[string]int{},
+	}
+	for key, migration := range migrations {
+		registry.migrations[key] = migration
+		if key.ToVersion > registry.versions[key.PlanID] {
+			registry.versions[key.PlanID] = key.ToVersion
+		}
+	}
+
+	return registry
+}
+
+// CurrentVersion returns the current schema version of the plan, the plans without any migration have version 0
+func (r *ParametersMigrationRegistry) CurrentVersion(planID string) int {
+	return r.versions[planID]
+}
+
+// Migrate applies the migrations registered for the plan of the parameters, one after another, starting from the schema version
+// the parameters were stored with. The migrated parameters are validated against the current plan schema.
+// The parameters are returned unchanged if they are already in the current schema version.
+func (r *ParametersMigrationRegistry) Migrate(pp internal.ProvisioningParameters) (internal.ProvisioningParameters, error) {
+	current := r.CurrentVersion(pp.PlanID)
+	if pp.SchemaVersion >= current {
+		return pp, nil
+	}
+
+	raw, err := json.Marshal(pp.Parameters)
+	if err != nil {
+		return pp, errors.Wrap(err, "while marshalling provisioning parameters")
+	}
+	parameters := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &parameters); err != nil {
+		return pp, errors.Wrap(err, "while unmarshalling provisioning parameters")
+	}
+
+	for version := pp.SchemaVersion; version < current; {
+		key, found := r.next(pp.PlanID, version)
+		if !found {
+			return pp, errors.Errorf("missing migration of plan %s parameters from schema version %d", pp.PlanID, version)
+		}
+		parameters, err = r.migrations[key](parameters)
+		if err != nil {
+			return pp, errors.Wrapf(err, "while migrating plan %s parameters from schema version %d to %d", pp.PlanID, key.FromVersion, key.ToVersion)
+		}
+		version = key.ToVersion
+	}
+
+	if err := validateMigratedParameters(pp.PlanID, parameters); err != nil {
+		return pp, err
+	}
+
+	raw, err = json.Marshal(parameters)
+	if err != nil {
+		return pp, errors.Wrap(err, "while marshalling migrated provisioning parameters")
+	}
+	migrated := pp
+	migrated.Parameters = internal.ProvisioningParametersDTO{}
+	if err := json.Unmarshal(raw, &migrated.Parameters); err != nil {
+		return pp, errors.Wrap(err, "while unmarshalling migrated provisioning parameters")
+	}
+	migrated.SchemaVersion = current
+
+	return migrated, nil
+}
+
+// next returns the migration starting from the given version which goes the furthest
+func (r *ParametersMigrationRegistry) next(planID string, fromVersion int) (ParametersMigrationKey, bool) {
+	var next ParametersMigrationKey
+	found := false
+	for key := range r.migrations {
+		if key.PlanID != planID || key.FromVersion != fromVersion || key.ToVersion <= fromVersion {
+			continue
+		}
+		if !found || key.ToVersion > next.ToVersion {
+			next = key
+			found = true
+		}
+	}
+
+	return next, found
+}
+
+func validateMigratedParameters(planID string, parameters map[string]interface{}) error {
+	plan, found := Plans(PlansConfig{})[planID]
+	if !found {
+		return errors.Errorf("plan ID %q is not recognized", planID)
+	}
+	validator, err := jsonschema.NewValidatorFromStringSchema(string(plan.provisioningRawSchema))
+	if err != nil {
+		return errors.Wrapf(err, "while creating schema validator for Plan ID %s", planID)
+	}
+
+	// the stored parameters contain nulls for the fields which were not set, the schema does not allow them
+	set := map[string]interface{}{}
+	for name, value := range parameters {
+		if value != nil {
+			set[name] = value
+		}
+	}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		return errors.Wrap(err, "while marshalling migrated provisioning parameters")
+	}
+	result, err := validator.ValidateString(string(raw))
+	if err != nil {
+		return errors.Wrap(err, "while executing JSON schema validator")
+	}
+	if !result.Valid {
+		return errors.Wrapf(result.Error, "while validating migrated parameters")
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package broker_test
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParametersMigrationRegistry_Migrate(t *testing.T) {
+	t.Run("should migrate old parameters to pass the new schema", func(t *testing.T) {
+		// given
+		registry := broker.NewParametersMigrationRegistry(map[broker.ParametersMigrationKey]broker.ParametersMigration{
+			{PlanID: broker.AWSPlanID, FromVersion: 0, ToVersion: 1}: func(parameters map[string]interface{}) (map[string]interface{}, error) {
+				// the new schema requires at least 2 nodes
+				if min, ok := parameters["autoScalerMin"].(float64); ok && min < 2 {
+					parameters["autoScalerMin"] = 2
+				}
+				return parameters, nil
+			},
+			{PlanID: broker.AWSPlanID, FromVersion: 1, ToVersion: 2}: func(parameters map[string]interface{}) (map[string]interface{}, error) {
+				parameters["name"] = "migrated-" + parameters["name"].(string)
+				return parameters, nil
+			},
+		})
+		stored := internal.ProvisioningParameters{
+			PlanID: broker.AWSPlanID,
+			Parameters: internal.ProvisioningParametersDTO{
+				Name:          "my-cluster",
+				AutoScalerMin: ptr.Integer(1),
+				AutoScalerMax: ptr.Integer(4),
+			},
+		}
+
+		// when
+		migrated, err := registry.Migrate(stored)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, registry.CurrentVersion(broker.AWSPlanID))
+		assert.Equal(t, 2, migrated.SchemaVersion)
+		assert.Equal(t, "migrated-my-cluster", migrated.Parameters.Name)
+		assert.Equal(t, ptr.Integer(2), migrated.Parameters.AutoScalerMin)
+		assert.Equal(t, ptr.Integer(4), migrated.Parameters.AutoScalerMax)
+		assert.Equal(t, ptr.Integer(1), stored.Parameters.AutoScalerMin)
+	})
+
+	t.Run("should not migrate parameters in the current schema version", func(t *testing.T) {
+		// given
+		called := false
+		registry := broker.NewParametersMigrationRegistry(map[broker.ParametersMigrationKey]broker.ParametersMigration{
+			{PlanID: broker.AWSPlanID, FromVersion: 0, ToVersion: 1}: func(parameters map[string]interface{}) (map[string]interface{}, error) {
+				called = true
+				return parameters, nil
+			},
+		})
+		stored := internal.ProvisioningParameters{
+			PlanID:        broker.AWSPlanID,
+			SchemaVersion: 1,
+			Parameters: internal.ProvisioningParametersDTO{
+				Name:          "my-cluster",
+				AutoScalerMin: ptr.Integer(1),
+			},
+		}
+
+		// when
+		migrated, err := registry.Migrate(stored)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, called)
+		assert.Equal(t, stored, migrated)
+	})
+
+	t.Run("should not migrate parameters of the plan without migrations", func(t *testing.T) {
+		// given
+		registry := broker.NewParametersMigrationRegistry(map[broker.ParametersMigrationKey]broker.ParametersMigration{})
+		stored := internal.ProvisioningParameters{
+			PlanID:     broker.GCPPlanID,
+			Parameters: internal.ProvisioningParametersDTO{Name: "my-cluster"},
+		}
+
+		// when
+		migrated, err := registry.Migrate(stored)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, stored, migrated)
+	})
+
+	t.Run("should fail if migrated parameters do not pass the new schema", func(t *testing.T) {
+		// given
+		registry := broker.NewParametersMigrationRegistry(map[broker.ParametersMigrationKey]broker.ParametersMigration{
+			{PlanID: broker.AWSPlanID, FromVersion: 0, ToVersion: 1}: func(parameters map[string]interface{}) (map[string]interface{}, error) {
+				return parameters, nil
+			},
+		})
+		stored := internal.ProvisioningParameters{
+			PlanID: broker.AWSPlanID,
+			Parameters: internal.ProvisioningParametersDTO{
+				Name:          "my-cluster",
+				AutoScalerMin: ptr.Integer(1),
+			},
+		}
+
+		// when
+		_, err := registry.Migrate(stored)
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail if migration is missing", func(t *testing.T) {
+		// given
+		registry := broker.NewParametersMigrationRegistry(map[broker.ParametersMigrationKey]broker.ParametersMigration{
+			{PlanID: broker.AWSPlanID, FromVersion: 1, ToVersion: 2}: func(parameters map[string]interface{}) (map[string]interface{}, error) {
+				return parameters, nil
+			},
+		})
+		stored := internal.ProvisioningParameters{
+			PlanID:     broker.AWSPlanID,
+			Parameters: internal.ProvisioningParametersDTO{Name: "my-cluster"},
+		}
+
+		// when
+		_, err := registry.Migrate(stored)
+
+		// then
+		assert.EqualError(t, err, "missing migration of plan "+broker.AWSPlanID+" parameters from schema version 0")
+	})
+}
@@ -0,0 +1,128 @@
+package broker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+// ParametersValidator checks the rules which cannot be expressed by the JSON schema of the plan,
+// e.g. the relations between the provisioning parameters. It is invoked after the schema validation passed.
+type ParametersValidator interface {
+	Validate(parameters internal.ProvisioningParametersDTO) []ParameterError
+}
+
+// ParametersValidatorFunc allows to use a function as the ParametersValidator
+type ParametersValidatorFunc func(parameters internal.ProvisioningParametersDTO) []ParameterError
+
+func (f ParametersValidatorFunc) Validate(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	return f(parameters)
+}
+
+// ParameterError describes the provisioning parameter which violates the rule
+type ParameterError struct {
+	Parameter string
+	Message   string
+}
+
+func (e ParameterError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Parameter, e.Message)
+}
+
+// ParametersValidationError holds all the violations found by the validators of the plan
+type ParametersValidationError struct {
+	Errors []ParameterError
+}
+
+func (e ParametersValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("invalid provisioning parameters: %s", strings.Join(msgs, "; "))
+}
+
+// PlanParametersValidators maps a plan ID to the validators of its provisioning parameters
+type PlanParametersValidators map[string][]ParametersValidator
+
+// NewDefaultParametersValidators registers the built-in validators of the common cross-field rules for all plans
+// and the validators of the etcd settings limited per plan and of the maintenance window
+func NewDefaultParametersValidators() PlanParametersValidators {
+	validators := PlanParametersValidators{}
+	for _, planID := range PlanIDsMapping {
+		validators.Register(planID, ParametersValidatorFunc(validateAutoScaler), ParametersValidatorFunc(validateRollingUpdate), ParametersValidatorFunc(validateMaintenanceWindow))
+	}
+	validators.Register(GCPPlanID, ParametersValidatorFunc(validateZonesInRegion))
+	validators.Register(AWSPlanID, ParametersValidatorFunc(validateZonesInRegion))
+	registerControlPlaneValidators(validators)
+
+	return validators
+}
+
+// Register adds the validators of the plan, they are invoked in the order of the registration
+func (v PlanParametersValidators) Register(planID string, validators ...ParametersValidator) {
+	v[planID] = append(v[planID], validators...)
+}
+
+// Validate invokes all validators of the plan and returns ParametersValidationError with all found violations
+func (v PlanParametersValidators) Validate(planID string, parameters internal.ProvisioningParametersDTO) error {
+	var violations []ParameterError
+	for _, validator := range v[planID] {
+		violations = append(violations, validator.Validate(parameters)...)
+	}
+	if len(violations) > 0 {
+		return ParametersValidationError{Errors: violations}
+	}
+
+	return nil
+}
+
+// validateAutoScaler checks that the minimum number of nodes does not exceed the maximum
+func validateAutoScaler(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	if parameters.AutoScalerMin == nil || parameters.AutoScalerMax == nil {
+		return nil
+	}
+	if *parameters.AutoScalerMin > *parameters.AutoScalerMax {
+		return []ParameterError{{
+			Parameter: "autoScalerMin",
+			Message:   fmt.Sprintf("must not be greater than autoScalerMax %d", *parameters.AutoScalerMax),
+		}}
+	}
+
+	return nil
+}
+
+// validateRollingUpdate checks that the rolling update of the nodes can proceed, Gardener rejects
+// the worker pool in which both maxSurge and maxUnavailable are 0
+func validateRollingUpdate(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	if parameters.MaxSurge == nil || parameters.MaxUnavailable == nil {
+		return nil
+	}
+	if *parameters.MaxSurge == 0 && *parameters.MaxUnavailable == 0 {
+		return []ParameterError{{
+			Parameter: "maxUnavailable",
+			Message:   "must be greater than 0 when maxSurge is 0",
+		}}
+	}
+
+	return nil
+}
+
+// validateZonesInRegion checks that the zones belong to the region, the GCP and AWS zone names start with the region name
+func validateZonesInRegion(parameters internal.ProvisioningParametersDTO) []ParameterError {
+	if parameters.Region == nil || *parameters.Region == "" {
+		return nil
+	}
+	var violations []ParameterError
+	for _, zone := range parameters.Zones {
+		if !strings.HasPrefix(zone, *parameters.Region) {
+			violations = append(violations, ParameterError{
+				Parameter: "zones",
+				Message:   fmt.Sprintf("zone %q is not in the region %q", zone, *parameters.Region),
+			})
+		}
+	}
+
+	return violations
+}
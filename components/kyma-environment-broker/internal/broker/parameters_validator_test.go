@@ -0,0 +1,102 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanParametersValidators_Validate(t *testing.T) {
+	// given
+	validators := NewDefaultParametersValidators()
+
+	for name, tc := range map[string]struct {
+		planID         string
+		parameters     internal.ProvisioningParametersDTO
+		expectedErrors []ParameterError
+	}{
+		"no parameters": {
+			planID: GCPPlanID,
+		},
+		"auto scaler minimum equal to maximum": {
+			planID:     AzurePlanID,
+			parameters: internal.ProvisioningParametersDTO{AutoScalerMin: ptr.Integer(3), AutoScalerMax: ptr.Integer(3)},
+		},
+		"auto scaler minimum greater than maximum": {
+			planID:     AzurePlanID,
+			parameters: internal.ProvisioningParametersDTO{AutoScalerMin: ptr.Integer(4), AutoScalerMax: ptr.Integer(3)},
+			expectedErrors: []ParameterError{
+				{Parameter: "autoScalerMin", Message: "must not be greater than autoScalerMax 3"},
+			},
+		},
+		"rolling update without surge and unavailable nodes": {
+			planID:     AWSPlanID,
+			parameters: internal.ProvisioningParametersDTO{MaxSurge: ptr.Integer(0), MaxUnavailable: ptr.Integer(0)},
+			expectedErrors: []ParameterError{
+				{Parameter: "maxUnavailable", Message: "must be greater than 0 when maxSurge is 0"},
+			},
+		},
+		"zones in the region": {
+			planID:     GCPPlanID,
+			parameters: internal.ProvisioningParametersDTO{Region: ptr.String("europe-west3"), Zones: []string{"europe-west3-a", "europe-west3-b"}},
+		},
+		"zone outside of the region": {
+			planID:     AWSPlanID,
+			parameters: internal.ProvisioningParametersDTO{Region: ptr.String("eu-central-1"), Zones: []string{"eu-central-1a", "us-east-1a"}},
+			expectedErrors: []ParameterError{
+				{Parameter: "zones", Message: `zone "us-east-1a" is not in the region "eu-central-1"`},
+			},
+		},
+		"azure zones are not checked against the region": {
+			planID:     AzurePlanID,
+			parameters: internal.ProvisioningParametersDTO{Region: ptr.String("westeurope"), Zones: []string{"1", "2"}},
+		},
+		"all violations are returned": {
+			planID: GCPPlanID,
+			parameters: internal.ProvisioningParametersDTO{
+				AutoScalerMin: ptr.Integer(4), AutoScalerMax: ptr.Integer(3),
+				Region: ptr.String("europe-west3"), Zones: []string{"us-east1-a"},
+			},
+			expectedErrors: []ParameterError{
+				{Parameter: "autoScalerMin", Message: "must not be greater than autoScalerMax 3"},
+				{Parameter: "zones", Message: `zone "us-east1-a" is not in the region "europe-west3"`},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validators.Validate(tc.planID, tc.parameters)
+
+			// then
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.IsType(t, ParametersValidationError{}, err)
+			assert.Equal(t, tc.expectedErrors, err.(ParametersValidationError).Errors)
+		})
+	}
+}
+
+func TestPlanParametersValidators_Register(t *testing.T) {
+	// given
+	validators := PlanParametersValidators{}
+	validators.Register(AzurePlanID, ParametersValidatorFunc(func(parameters internal.ProvisioningParametersDTO) []ParameterError {
+		if parameters.Purpose != nil && *parameters.Purpose == "production" && parameters.AutoScalerMin != nil && *parameters.AutoScalerMin < 3 {
+			return []ParameterError{{Parameter: "autoScalerMin", Message: "must be at least 3 for production"}}
+		}
+		return nil
+	}))
+	parameters := internal.ProvisioningParametersDTO{Purpose: ptr.String("production"), AutoScalerMin: ptr.Integer(2)}
+
+	// when
+	azureErr := validators.Validate(AzurePlanID, parameters)
+	gcpErr := validators.Validate(GCPPlanID, parameters)
+
+	// then
+	assert.EqualError(t, azureErr, "invalid provisioning parameters: autoScalerMin: must be at least 3 for production")
+	assert.NoError(t, gcpErr)
+}
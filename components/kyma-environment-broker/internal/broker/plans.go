@@ -100,6 +100,11 @@ func OpenStackSchema(machineTypes []string) []byte {
 
 func GCPSchema(machineTypes []string) []byte {
 	properties := NewProvisioningProperties(machineTypes, GCPRegions())
+	// the Provisioner applies customer-managed encryption keys only to GCP clusters
+	properties.EncryptionKey = &Type{
+		Type:        "string",
+		Description: "Specifies the Cloud KMS key used to encrypt the cluster disks",
+	}
 	schema := NewSchema(properties, DefaultControlsOrder())
 
 	bytes, err := json.Marshal(schema)
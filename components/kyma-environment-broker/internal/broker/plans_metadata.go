@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"io/ioutil"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// PlanCatalogMetadata defines the cost and SLA metadata of the plan which is added to the plan metadata in the catalog
+type PlanCatalogMetadata struct {
+	CostTier     string `yaml:"costTier"`
+	SLA          string `yaml:"sla"`
+	SupportLevel string `yaml:"supportLevel"`
+}
+
+// PlansCatalogMetadata maps a plan ID to its catalog metadata, the metadata of plans which are not configured is not changed
+type PlansCatalogMetadata map[string]PlanCatalogMetadata
+
+// NewPlansCatalogMetadataFromFile reads the catalog metadata of the plans from the YAML file, empty path means no plan is configured
+func NewPlansCatalogMetadataFromFile(path string) (PlansCatalogMetadata, error) {
+	if path == "" {
+		return PlansCatalogMetadata{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with plans catalog metadata", path)
+	}
+	var catalogMetadataConfig struct {
+		Plans PlansCatalogMetadata `yaml:"plans"`
+	}
+	err = yaml.Unmarshal(yamlFile, &catalogMetadataConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with plans catalog metadata")
+	}
+	if catalogMetadataConfig.Plans == nil {
+		return PlansCatalogMetadata{}, nil
+	}
+	for planID := range catalogMetadataConfig.Plans {
+		if _, found := PlanNamesMapping[planID]; !found {
+			return nil, errors.Errorf("catalog metadata is configured for unknown plan ID %q", planID)
+		}
+	}
+
+	return catalogMetadataConfig.Plans, nil
+}
+
+// Enrich adds the configured metadata of the plan to the additional metadata of the catalog plan
+func (m PlansCatalogMetadata) Enrich(planID string, metadata *domain.ServicePlanMetadata) *domain.ServicePlanMetadata {
+	planMetadata, found := m[planID]
+	if !found {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = &domain.ServicePlanMetadata{}
+	}
+	enriched := *metadata
+	enriched.AdditionalMetadata = map[string]interface{}{}
+	for k, v := range metadata.AdditionalMetadata {
+		enriched.AdditionalMetadata[k] = v
+	}
+	for key, value := range map[string]string{
+		"costTier":     planMetadata.CostTier,
+		"sla":          planMetadata.SLA,
+		"supportLevel": planMetadata.SupportLevel,
+	} {
+		if value != "" {
+			enriched.AdditionalMetadata[key] = value
+		}
+	}
+
+	return &enriched
+}
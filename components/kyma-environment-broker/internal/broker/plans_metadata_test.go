@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlansCatalogMetadataFromFile(t *testing.T) {
+	t.Run("should read the metadata of the plans", func(t *testing.T) {
+		// when
+		catalogMetadata, err := NewPlansCatalogMetadataFromFile("testdata/catalog_metadata.yaml")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, PlanCatalogMetadata{CostTier: "standard", SLA: "99.5", SupportLevel: "enterprise"}, catalogMetadata[AzurePlanID])
+		assert.Equal(t, PlanCatalogMetadata{CostTier: "free"}, catalogMetadata[TrialPlanID])
+	})
+
+	t.Run("should return no metadata for empty path", func(t *testing.T) {
+		// when
+		catalogMetadata, err := NewPlansCatalogMetadataFromFile("")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, catalogMetadata)
+	})
+
+	t.Run("should reject unknown plan ID", func(t *testing.T) {
+		// given
+		dir, err := ioutil.TempDir("", "catalog-metadata")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "catalog_metadata.yaml")
+		err = ioutil.WriteFile(path, []byte("plans:\n  unknown-plan-id:\n    costTier: standard\n"), 0644)
+		require.NoError(t, err)
+
+		// when
+		_, err = NewPlansCatalogMetadataFromFile(path)
+
+		// then
+		assert.EqualError(t, err, `catalog metadata is configured for unknown plan ID "unknown-plan-id"`)
+	})
+}
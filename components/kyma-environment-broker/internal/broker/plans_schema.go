@@ -13,11 +13,30 @@ type RootSchema struct {
 }
 
 type ProvisioningProperties struct {
-	Name          Type  `json:"name"`
-	Region        *Type `json:"region,omitempty"`
-	MachineType   *Type `json:"machineType,omitempty"`
-	AutoScalerMin *Type `json:"autoScalerMin,omitempty"`
-	AutoScalerMax *Type `json:"autoScalerMax,omitempty"`
+	Name                Type  `json:"name"`
+	Region              *Type `json:"region,omitempty"`
+	MachineType         *Type `json:"machineType,omitempty"`
+	AutoScalerMin       *Type `json:"autoScalerMin,omitempty"`
+	AutoScalerMax       *Type `json:"autoScalerMax,omitempty"`
+	Preset              *Type `json:"preset,omitempty"`
+	Annotations         *Type `json:"annotations,omitempty"`
+	Seed                *Type `json:"seed,omitempty"`
+	EncryptionKey       *Type `json:"encryptionKey,omitempty"`
+	KubernetesVersion   *Type `json:"kubernetesVersion,omitempty"`
+	PrivateCluster      *Type `json:"privateCluster,omitempty"`
+	AllowedCIDRs        *Type `json:"allowedCIDRs,omitempty"`
+	ComponentToggles    *Type `json:"componentToggles,omitempty"`
+	AllowMultiple       *Type `json:"allowMultiple,omitempty"`
+	Ingress             *Type `json:"ingress,omitempty"`
+	ResourceQuota       *Type `json:"resourceQuota,omitempty"`
+	MachineImage        *Type `json:"machineImage,omitempty"`
+	MachineImageVersion *Type `json:"machineImageVersion,omitempty"`
+	EtcdEncryption      *Type `json:"etcdEncryption,omitempty"`
+	BackupRetentionDays *Type `json:"backupRetentionDays,omitempty"`
+	MaintenanceWindow   *Type `json:"maintenanceWindow,omitempty"`
+	ControlPlaneHA      *Type `json:"controlPlaneHA,omitempty"`
+	KubernetesConfig    *Type `json:"kubernetesConfig,omitempty"`
+	CustomDomain        *Type `json:"customDomain,omitempty"`
 }
 
 type Type struct {
@@ -35,9 +54,15 @@ type Type struct {
 	Default         interface{}   `json:"default,omitempty"`
 	Example         interface{}   `json:"example,omitempty"`
 	Enum            []interface{} `json:"enum,omitempty"`
-	Items           []Type        `json:"items,omitempty"`
+	Items           *Type         `json:"items,omitempty"`
 	AdditionalItems *bool         `json:"additionalItems,omitempty"`
 	UniqueItems     *bool         `json:"uniqueItems,omitempty"`
+
+	// Properties, AdditionalProperties and Required describe the fields of the object type
+	Properties map[string]Type `json:"properties,omitempty"`
+	// AdditionalProperties is either false, which rejects the fields not listed in Properties, or the type of all fields
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
 }
 
 func NameProperty() Type {
@@ -76,6 +101,128 @@ func NewProvisioningProperties(machineTypes []string, regions []string) Provisio
 			Default:     10,
 			Description: "Specifies the maximum number of virtual machines to create",
 		},
+		Preset: &Type{
+			Type:        "string",
+			Description: "Specifies the name of the provisioning preset which values are used for the parameters not specified in the request",
+		},
+		Annotations: &Type{
+			Type:                 "object",
+			Description:          "Specifies the custom annotations added to the cluster",
+			AdditionalProperties: &Type{Type: "string"},
+		},
+		Seed: &Type{
+			Type:        "string",
+			Description: "Specifies the Gardener seed which hosts the control plane of the cluster, only the seeds allowed in the region can be requested",
+		},
+		KubernetesVersion: &Type{
+			Type:        "string",
+			Description: "Specifies the version of Kubernetes installed on the cluster",
+		},
+		PrivateCluster: &Type{
+			Type:        "boolean",
+			Description: "If true, the API server of the cluster is accessible only from the allowed CIDRs",
+		},
+		AllowedCIDRs: &Type{
+			Type:        "array",
+			Description: "Specifies the CIDRs from which the API server of the private cluster is accessible",
+			Items:       &Type{Type: "string"},
+		},
+		ComponentToggles: &Type{
+			Type:        "object",
+			Description: "Specifies the components installed or removed on top of the default components of the plan",
+			Properties: map[string]Type{
+				"enable":  {Type: "array", Items: &Type{Type: "string"}},
+				"disable": {Type: "array", Items: &Type{Type: "string"}},
+			},
+			AdditionalProperties: false,
+		},
+		AllowMultiple: &Type{
+			Type:        "boolean",
+			Description: "If true, the instance is provisioned even if the subaccount already has an instance of the plan",
+		},
+		Ingress: &Type{
+			Type:        "object",
+			Description: "Specifies the load balancer which exposes the ingress gateway of the runtime",
+			Properties: map[string]Type{
+				"loadBalancerType": {Type: "string"},
+				"loadBalancerSKU":  {Type: "string"},
+				"annotations":      {Type: "object", AdditionalProperties: &Type{Type: "string"}},
+			},
+			AdditionalProperties: false,
+		},
+		ResourceQuota: &Type{
+			Type:        "object",
+			Description: "Specifies the limits of the resources of the runtime, the limits which are not specified are taken from the defaults",
+			Properties: map[string]Type{
+				"namespaces": {Type: "integer"},
+				"pvcSizeGb":  {Type: "integer"},
+			},
+			AdditionalProperties: false,
+		},
+		MachineImage: &Type{
+			Type:        "string",
+			Description: "Specifies the operating system of the nodes, it is requested together with the machine image version",
+		},
+		MachineImageVersion: &Type{
+			Type:        "string",
+			Description: "Specifies the version of the machine image of the nodes",
+		},
+		EtcdEncryption: &Type{
+			Type:        "object",
+			Description: "Specifies the encryption of the etcd of the cluster, the customer-managed key is used if the key reference is set",
+			Properties: map[string]Type{
+				"enabled": {Type: "boolean"},
+				"keyRef":  {Type: "string"},
+			},
+			AdditionalProperties: false,
+			Required:             []string{"enabled"},
+		},
+		BackupRetentionDays: &Type{
+			Type:        "integer",
+			Description: "Specifies the number of days the backups of the etcd are kept",
+		},
+		MaintenanceWindow: &Type{
+			Type:        "object",
+			Description: "Specifies the start of the daily maintenance of the cluster as the local time in the HH:MM format in the IANA time zone",
+			Properties: map[string]Type{
+				"begin":    {Type: "string"},
+				"timeZone": {Type: "string"},
+			},
+			AdditionalProperties: false,
+		},
+		ControlPlaneHA: &Type{
+			Type:        "boolean",
+			Description: "If true, the etcd and the API server of the cluster are replicated across the zones",
+		},
+		KubernetesConfig: &Type{
+			Type:        "object",
+			Description: "Specifies the options of the kubelet and the API server of the cluster, only the allowed feature gates can be requested",
+			Properties: map[string]Type{
+				"kubelet": {
+					Type: "object",
+					Properties: map[string]Type{
+						"featureGates": {Type: "object", AdditionalProperties: &Type{Type: "boolean"}},
+						"evictionHard": {Type: "object", AdditionalProperties: &Type{Type: "string"}},
+						"maxPods":      {Type: "integer"},
+						"podPidsLimit": {Type: "integer"},
+					},
+					AdditionalProperties: false,
+				},
+				"apiServer": {
+					Type: "object",
+					Properties: map[string]Type{
+						"featureGates": {Type: "object", AdditionalProperties: &Type{Type: "boolean"}},
+					},
+					AdditionalProperties: false,
+				},
+			},
+			AdditionalProperties: false,
+		},
+		CustomDomain: &Type{
+			Type:        "string",
+			Description: "Specifies the domain under which the runtime exposes its services instead of the generated one, the domain can be used by only one instance",
+			MaxLength:   253,
+		},
 	}
 }
 
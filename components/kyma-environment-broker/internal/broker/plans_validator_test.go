@@ -33,6 +33,31 @@ func TestNewPlansSchemaValidatorErrors(t *testing.T) {
 			inputJSON:    `{"region": "munich"}`,
 			expErr:       `(root): name is required, region: region must be one of the following: "eastus", "centralus", "westus2", "uksouth", "northeurope", "westeurope", "japaneast", "southeastasia"`,
 		},
+		"not valid annotations": {
+			againstPlans: []string{AzurePlanID},
+			inputJSON:    `{"name": "annotated", "annotations": {"team": 1}}`,
+			expErr:       `annotations.team: Invalid type. Expected: string, given: integer`,
+		},
+		"not valid private cluster": {
+			againstPlans: []string{AzurePlanID},
+			inputJSON:    `{"name": "private", "privateCluster": "yes"}`,
+			expErr:       `privateCluster: Invalid type. Expected: boolean, given: string`,
+		},
+		"unknown ingress field": {
+			againstPlans: []string{AzurePlanID},
+			inputJSON:    `{"name": "ingress", "ingress": {"type": "internal"}}`,
+			expErr:       `ingress: Additional property type is not allowed`,
+		},
+		"missing etcd encryption enabled": {
+			againstPlans: []string{AzurePlanID},
+			inputJSON:    `{"name": "etcd", "etcdEncryption": {"keyRef": "key"}}`,
+			expErr:       `etcdEncryption: enabled is required`,
+		},
+		"not valid kubelet max pods": {
+			againstPlans: []string{AzurePlanID},
+			inputJSON:    `{"name": "kubelet", "kubernetesConfig": {"kubelet": {"maxPods": "many"}}}`,
+			expErr:       `kubernetesConfig.kubelet.maxPods: Invalid type. Expected: integer, given: string`,
+		},
 	}
 	for tN, tC := range tests {
 		t.Run(tN, func(t *testing.T) {
@@ -0,0 +1,122 @@
+package broker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// PresetParameterName is the name of the provisioning parameter which references a preset
+const PresetParameterName = "preset"
+
+// ProvisioningPresets holds named sets of provisioning parameters. A client can reference a preset
+// by its name in the provisioning request instead of sending the whole set of parameters.
+type ProvisioningPresets map[string]map[string]interface{}
+
+// NewProvisioningPresetsFromFile reads presets from the YAML file, empty path means no presets
+func NewProvisioningPresetsFromFile(path string) (ProvisioningPresets, error) {
+	if path == "" {
+		return ProvisioningPresets{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with provisioning presets", path)
+	}
+	var presetsConfig struct {
+		Presets ProvisioningPresets `yaml:"presets"`
+	}
+	err = yaml.Unmarshal(yamlFile, &presetsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with provisioning presets")
+	}
+	if presetsConfig.Presets == nil {
+		return ProvisioningPresets{}, nil
+	}
+	for name, preset := range presetsConfig.Presets {
+		for key, value := range preset {
+			converted, err := convertYAMLValue(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "while converting parameter %q of provisioning preset %q", key, name)
+			}
+			preset[key] = converted
+		}
+	}
+
+	return presetsConfig.Presets, nil
+}
+
+// convertYAMLValue converts the nested maps decoded by YAML, which have the interface{} keys, to the maps with the string keys,
+// so the parameters from the preset can be marshaled to JSON
+func convertYAMLValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("key %v is not a string", key)
+			}
+			c, err := convertYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[name] = c
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			c, err := convertYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = c
+		}
+		return converted, nil
+	default:
+		return value, nil
+	}
+}
+
+// Resolve merges the preset referenced in the raw parameters with the parameters itself.
+// Parameters specified explicitly in the request override values from the preset.
+// Returns the merged parameters and the name of the resolved preset (empty if no preset was referenced).
+func (p ProvisioningPresets) Resolve(rawParameters json.RawMessage) (json.RawMessage, string, error) {
+	if len(rawParameters) == 0 {
+		return rawParameters, "", nil
+	}
+
+	var parameters map[string]interface{}
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return nil, "", errors.Wrap(err, "while unmarshaling raw parameters")
+	}
+	name, found := parameters[PresetParameterName]
+	if !found {
+		return rawParameters, "", nil
+	}
+	presetName, ok := name.(string)
+	if !ok || presetName == "" {
+		return nil, "", errors.Errorf("parameter %q must be a non-empty string", PresetParameterName)
+	}
+	preset, found := p[presetName]
+	if !found {
+		return nil, "", errors.Errorf("provisioning preset %q does not exist", presetName)
+	}
+
+	merged := make(map[string]interface{}, len(preset)+len(parameters))
+	for key, value := range preset {
+		merged[key] = value
+	}
+	for key, value := range parameters {
+		merged[key] = value
+	}
+
+	result, err := json.Marshal(merged)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "while marshaling parameters merged with preset %q", presetName)
+	}
+
+	return result, presetName, nil
+}
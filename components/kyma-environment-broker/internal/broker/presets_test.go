@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisioningPresets_Resolve(t *testing.T) {
+	// given
+	presets, err := NewProvisioningPresetsFromFile("testdata/presets.yaml")
+	require.NoError(t, err)
+
+	t.Run("should merge preset with parameters", func(t *testing.T) {
+		// when
+		raw, name, err := presets.Resolve(json.RawMessage(`{"name": "cluster", "preset": "small"}`))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "small", name)
+		assert.JSONEq(t, `{
+			"name": "cluster",
+			"preset": "small",
+			"machineType": "Standard_D4_v3",
+			"autoScalerMin": 2,
+			"autoScalerMax": 4,
+			"region": "westeurope"
+		}`, string(raw))
+	})
+
+	t.Run("should override preset values with request parameters", func(t *testing.T) {
+		// when
+		raw, name, err := presets.Resolve(json.RawMessage(`{"name": "cluster", "preset": "small", "autoScalerMax": 10, "region": "northeurope"}`))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "small", name)
+		assert.JSONEq(t, `{
+			"name": "cluster",
+			"preset": "small",
+			"machineType": "Standard_D4_v3",
+			"autoScalerMin": 2,
+			"autoScalerMax": 10,
+			"region": "northeurope"
+		}`, string(raw))
+	})
+
+	t.Run("should merge preset with nested parameters", func(t *testing.T) {
+		// when
+		raw, name, err := presets.Resolve(json.RawMessage(`{"name": "cluster", "preset": "restricted"}`))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "restricted", name)
+		assert.JSONEq(t, `{
+			"name": "cluster",
+			"preset": "restricted",
+			"machineType": "Standard_D8_v3",
+			"resourceQuota": {"namespaces": 20, "pvcSizeGb": 100},
+			"kubernetesConfig": {"kubelet": {"featureGates": {"EphemeralContainers": true}}},
+			"allowedCIDRs": ["10.0.0.0/16"]
+		}`, string(raw))
+	})
+
+	t.Run("should return parameters unchanged when preset is not referenced", func(t *testing.T) {
+		// when
+		raw, name, err := presets.Resolve(json.RawMessage(`{"name": "cluster"}`))
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, name)
+		assert.JSONEq(t, `{"name": "cluster"}`, string(raw))
+	})
+
+	t.Run("should reject unknown preset", func(t *testing.T) {
+		// when
+		_, _, err := presets.Resolve(json.RawMessage(`{"name": "cluster", "preset": "unknown"}`))
+
+		// then
+		assert.EqualError(t, err, `provisioning preset "unknown" does not exist`)
+	})
+}
+
+func TestNewProvisioningPresetsFromFile_EmptyPath(t *testing.T) {
+	// when
+	presets, err := NewProvisioningPresetsFromFile("")
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+}
@@ -0,0 +1,29 @@
+package broker
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// validatePrivateCluster checks the CIDRs from which the API server of a private cluster is accessible.
+// The control plane CIDRs are added to the list by the input builder, so an empty list is accepted.
+func validatePrivateCluster(privateCluster *bool, allowedCIDRs []string) error {
+	if privateCluster == nil || !*privateCluster {
+		if len(allowedCIDRs) > 0 {
+			return errors.New("allowedCIDRs can be specified only for a private cluster")
+		}
+		return nil
+	}
+	for _, cidr := range allowedCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Errorf("allowed CIDR %q is not valid", cidr)
+		}
+		if ones, _ := network.Mask.Size(); ones == 0 {
+			return errors.Errorf("allowed CIDR %q exposes the API server publicly", cidr)
+		}
+	}
+
+	return nil
+}
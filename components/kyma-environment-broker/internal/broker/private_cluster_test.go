@@ -0,0 +1,57 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePrivateCluster(t *testing.T) {
+	for name, tc := range map[string]struct {
+		privateCluster *bool
+		allowedCIDRs   []string
+		expectErr      bool
+	}{
+		"public cluster": {},
+		"private cluster without allowed CIDRs": {
+			privateCluster: ptr.Bool(true),
+		},
+		"private cluster with allowed CIDRs": {
+			privateCluster: ptr.Bool(true),
+			allowedCIDRs:   []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::/32"},
+		},
+		"allowed CIDRs for a public cluster": {
+			privateCluster: ptr.Bool(false),
+			allowedCIDRs:   []string{"10.0.0.0/8"},
+			expectErr:      true,
+		},
+		"allowed IP without prefix length": {
+			privateCluster: ptr.Bool(true),
+			allowedCIDRs:   []string{"10.0.0.1"},
+			expectErr:      true,
+		},
+		"allowed CIDR with invalid address": {
+			privateCluster: ptr.Bool(true),
+			allowedCIDRs:   []string{"10.0.0.300/24"},
+			expectErr:      true,
+		},
+		"allowed CIDR matching all addresses": {
+			privateCluster: ptr.Bool(true),
+			allowedCIDRs:   []string{"10.0.0.0/8", "0.0.0.0/0"},
+			expectErr:      true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := validatePrivateCluster(tc.privateCluster, tc.allowedCIDRs)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v2"
+)
+
+// RateLimitConfig configures the limit of provisioning requests per subaccount
+type RateLimitConfig struct {
+	// Interval is the time after which the subaccount gets a next request, zero disables the limit
+	Interval time.Duration `envconfig:"default=0s"`
+	// Burst is the number of requests the subaccount can send at once
+	Burst int `envconfig:"default=10"`
+	// OverridesFilePath defines a path to the file with limits for specific subaccounts
+	OverridesFilePath string `envconfig:"optional"`
+}
+
+// RateLimit describes a token bucket which is refilled with one token every interval up to the burst size
+type RateLimit struct {
+	Interval time.Duration `yaml:"interval"`
+	Burst    int           `yaml:"burst"`
+}
+
+// NewRateLimitOverridesFromFile reads limits for specific subaccounts from the YAML file, empty path means no overrides
+func NewRateLimitOverridesFromFile(path string) (map[string]RateLimit, error) {
+	if path == "" {
+		return map[string]RateLimit{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with rate limit overrides", path)
+	}
+	var overridesConfig struct {
+		Overrides map[string]RateLimit `yaml:"overrides"`
+	}
+	err = yaml.Unmarshal(yamlFile, &overridesConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with rate limit overrides")
+	}
+	if overridesConfig.Overrides == nil {
+		return map[string]RateLimit{}, nil
+	}
+
+	return overridesConfig.Overrides, nil
+}
+
+// SubaccountRateLimiter keeps a separate token bucket for every subaccount
+type SubaccountRateLimiter struct {
+	defaults  RateLimit
+	overrides map[string]RateLimit
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewSubaccountRateLimiter(defaults RateLimit, overrides map[string]RateLimit) *SubaccountRateLimiter {
+	return &SubaccountRateLimiter{
+		defaults:  defaults,
+		overrides: overrides,
+		limiters:  map[string]*rate.Limiter{},
+	}
+}
+
+// Allow takes a token from the bucket of the subaccount. If the bucket is empty
+// it returns false and the time after which the request can be retried.
+func (l *SubaccountRateLimiter) Allow(subaccountID string) (bool, time.Duration) {
+	limiter := l.limiter(subaccountID)
+
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, l.limitFor(subaccountID).Interval
+	}
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+func (l *SubaccountRateLimiter) limiter(subaccountID string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, found := l.limiters[subaccountID]
+	if !found {
+		limit := l.limitFor(subaccountID)
+		every := rate.Inf
+		if limit.Interval > 0 {
+			every = rate.Every(limit.Interval)
+		}
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(every, burst)
+		l.limiters[subaccountID] = limiter
+	}
+
+	return limiter
+}
+
+func (l *SubaccountRateLimiter) limitFor(subaccountID string) RateLimit {
+	if limit, found := l.overrides[subaccountID]; found {
+		return limit
+	}
+	return l.defaults
+}
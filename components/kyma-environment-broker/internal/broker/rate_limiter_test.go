@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubaccountRateLimiter_Allow(t *testing.T) {
+	// given
+	limiter := NewSubaccountRateLimiter(RateLimit{Interval: time.Hour, Burst: 2}, map[string]RateLimit{
+		"trusted": {Interval: time.Hour, Burst: 3},
+	})
+
+	// when requests within the limit
+	for i := 0; i < 2; i++ {
+		allowed, delay := limiter.Allow("sa-1")
+
+		// then
+		assert.True(t, allowed)
+		assert.Zero(t, delay)
+	}
+
+	// when the limit is exceeded
+	allowed, delay := limiter.Allow("sa-1")
+
+	// then
+	assert.False(t, allowed)
+	assert.True(t, delay > 0 && delay <= time.Hour)
+
+	// when other subaccounts send requests
+	allowed, _ = limiter.Allow("sa-2")
+
+	// then
+	assert.True(t, allowed)
+	for i := 0; i < 3; i++ {
+		allowed, _ = limiter.Allow("trusted")
+		assert.True(t, allowed)
+	}
+	allowed, _ = limiter.Allow("trusted")
+	assert.False(t, allowed)
+}
+
+func TestSubaccountRateLimiter_Unlimited(t *testing.T) {
+	// given
+	limiter := NewSubaccountRateLimiter(RateLimit{}, nil)
+
+	for i := 0; i < 100; i++ {
+		// when
+		allowed, _ := limiter.Allow("sa-1")
+
+		// then
+		assert.True(t, allowed)
+	}
+}
@@ -0,0 +1,84 @@
+package broker
+
+import (
+	"io/ioutil"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ResourceQuotaLimits defines the maximum values of the resource quota which can be requested for the plan,
+// a zero value means that the limit cannot be requested
+type ResourceQuotaLimits struct {
+	Namespaces int `yaml:"namespaces"`
+	PVCSizeGb  int `yaml:"pvcSizeGb"`
+}
+
+// PlanResourceQuotas maps a plan name to its resource quota limits, in plans which are not configured
+// the resource quota cannot be requested and the defaults of the broker are used
+type PlanResourceQuotas map[string]ResourceQuotaLimits
+
+// NewPlanResourceQuotasFromFile reads the resource quota limits of the plans from the YAML file, empty path means no plan is configured
+func NewPlanResourceQuotasFromFile(path string) (PlanResourceQuotas, error) {
+	if path == "" {
+		return PlanResourceQuotas{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with plan resource quotas", path)
+	}
+	var resourceQuotasConfig struct {
+		Plans PlanResourceQuotas `yaml:"plans"`
+	}
+	err = yaml.Unmarshal(yamlFile, &resourceQuotasConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with plan resource quotas")
+	}
+	if resourceQuotasConfig.Plans == nil {
+		return PlanResourceQuotas{}, nil
+	}
+	for planName, limits := range resourceQuotasConfig.Plans {
+		if _, found := PlanIDsMapping[planName]; !found {
+			return nil, errors.Errorf("resource quotas are configured for unknown plan %q", planName)
+		}
+		if limits.Namespaces < 0 || limits.PVCSizeGb < 0 {
+			return nil, errors.Errorf("resource quota limits of plan %q must not be negative", planName)
+		}
+	}
+
+	return resourceQuotasConfig.Plans, nil
+}
+
+// Validate checks the resource quota requested for the plan against the limits of the plan.
+// No requested quota means that the defaults of the broker are used.
+func (q PlanResourceQuotas) Validate(planID string, quota *internal.ResourceQuota) error {
+	if quota == nil {
+		return nil
+	}
+	limits := q[PlanNamesMapping[planID]]
+	if err := validateResourceQuotaLimit("namespaces", quota.Namespaces, limits.Namespaces); err != nil {
+		return err
+	}
+	if err := validateResourceQuotaLimit("pvcSizeGb", quota.PVCSizeGb, limits.PVCSizeGb); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateResourceQuotaLimit(name string, value *int, limit int) error {
+	switch {
+	case value == nil:
+		return nil
+	case *value < 1:
+		return errors.Errorf("resource quota %s must be greater than 0", name)
+	case limit == 0:
+		return errors.Errorf("resource quota %s cannot be requested for the plan", name)
+	case *value > limit:
+		return errors.Errorf("resource quota %s %d exceeds the maximum %d of the plan", name, *value, limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanResourceQuotas_Validate(t *testing.T) {
+	// given
+	resourceQuotas, err := NewPlanResourceQuotasFromFile("testdata/resource_quotas.yaml")
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		planID    string
+		quota     *internal.ResourceQuota
+		expectErr bool
+	}{
+		"no resource quota requested": {
+			planID: GCPPlanID,
+		},
+		"resource quota within the limits": {
+			planID: AzurePlanID,
+			quota:  &internal.ResourceQuota{Namespaces: ptr.Integer(200), PVCSizeGb: ptr.Integer(100)},
+		},
+		"namespaces over the limit": {
+			planID:    AzurePlanID,
+			quota:     &internal.ResourceQuota{Namespaces: ptr.Integer(201)},
+			expectErr: true,
+		},
+		"not positive PVC size": {
+			planID:    AzurePlanID,
+			quota:     &internal.ResourceQuota{PVCSizeGb: ptr.Integer(0)},
+			expectErr: true,
+		},
+		"PVC size without limit in the plan": {
+			planID:    TrialPlanID,
+			quota:     &internal.ResourceQuota{PVCSizeGb: ptr.Integer(10)},
+			expectErr: true,
+		},
+		"plan without resource quotas": {
+			planID:    GCPPlanID,
+			quota:     &internal.ResourceQuota{Namespaces: ptr.Integer(10)},
+			expectErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := resourceQuotas.Validate(tc.planID, tc.quota)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewPlanResourceQuotasFromFile_EmptyPath(t *testing.T) {
+	// when
+	resourceQuotas, err := NewPlanResourceQuotasFromFile("")
+
+	// then
+	require.NoError(t, err)
+	assert.NoError(t, resourceQuotas.Validate(AzurePlanID, nil))
+	assert.Error(t, resourceQuotas.Validate(AzurePlanID, &internal.ResourceQuota{Namespaces: ptr.Integer(10)}))
+}
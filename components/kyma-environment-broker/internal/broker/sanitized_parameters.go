@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces the values of the secret-bearing provisioning parameters
+const RedactedValue = "REDACTED"
+
+// secretParameterFields classifies the provisioning parameters which carry secrets or the references to them,
+// the fields are identified by the dot-separated path of their JSON names. Values of the listed fields
+// (including all nested fields) are redacted before the parameters are returned to the client.
+var secretParameterFields = []string{
+	"ers_context.sm_platform_credentials",
+	"parameters.targetSecret",
+}
+
+// SanitizeProvisioningParameters returns the provisioning parameters with the secret-bearing fields redacted
+func SanitizeProvisioningParameters(pp internal.ProvisioningParameters) (map[string]interface{}, error) {
+	raw, err := json.Marshal(pp)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshalling provisioning parameters")
+	}
+	sanitized := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &sanitized); err != nil {
+		return nil, errors.Wrap(err, "while unmarshalling provisioning parameters")
+	}
+
+	for _, field := range secretParameterFields {
+		redact(sanitized, strings.Split(field, "."))
+	}
+
+	return sanitized, nil
+}
+
+func redact(values map[string]interface{}, path []string) {
+	value, found := values[path[0]]
+	if !found || value == nil {
+		return
+	}
+	if len(path) == 1 {
+		values[path[0]] = RedactedValue
+		return
+	}
+	if nested, ok := value.(map[string]interface{}); ok {
+		redact(nested, path[1:])
+	}
+}
@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// AllowedSeeds maps a region to the Gardener seeds which can be requested for clusters in that region
+type AllowedSeeds map[string][]string
+
+// NewAllowedSeedsFromFile reads allowed seeds from the YAML file, empty path means no seed can be requested
+func NewAllowedSeedsFromFile(path string) (AllowedSeeds, error) {
+	if path == "" {
+		return AllowedSeeds{}, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with allowed seeds", path)
+	}
+	var seedsConfig struct {
+		Seeds AllowedSeeds `yaml:"seeds"`
+	}
+	err = yaml.Unmarshal(yamlFile, &seedsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with allowed seeds")
+	}
+	if seedsConfig.Seeds == nil {
+		return AllowedSeeds{}, nil
+	}
+
+	return seedsConfig.Seeds, nil
+}
+
+// Validate checks if the seed can be requested for a cluster in the given region.
+// No seed means that Gardener chooses the seed.
+func (s AllowedSeeds) Validate(seed, region *string) error {
+	if seed == nil {
+		return nil
+	}
+	if *seed == "" {
+		return errors.New("seed must not be empty")
+	}
+	if region == nil || *region == "" {
+		return errors.New("region must be specified together with the seed")
+	}
+	for _, allowed := range s[*region] {
+		if allowed == *seed {
+			return nil
+		}
+	}
+
+	return errors.Errorf("seed %q is not allowed in region %q", *seed, *region)
+}
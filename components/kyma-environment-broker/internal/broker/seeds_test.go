@@ -0,0 +1,71 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedSeeds_Validate(t *testing.T) {
+	// given
+	seeds, err := NewAllowedSeedsFromFile("testdata/seeds.yaml")
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		seed      *string
+		region    *string
+		expectErr bool
+	}{
+		"default seed": {
+			seed:   nil,
+			region: ptr.String("westeurope"),
+		},
+		"permitted seed": {
+			seed:   ptr.String("az-eu2"),
+			region: ptr.String("westeurope"),
+		},
+		"seed permitted in other region": {
+			seed:      ptr.String("az-us1"),
+			region:    ptr.String("westeurope"),
+			expectErr: true,
+		},
+		"unknown seed": {
+			seed:      ptr.String("az-unknown"),
+			region:    ptr.String("eastus"),
+			expectErr: true,
+		},
+		"seed without region": {
+			seed:      ptr.String("az-eu1"),
+			expectErr: true,
+		},
+		"empty seed": {
+			seed:      ptr.String(""),
+			region:    ptr.String("westeurope"),
+			expectErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := seeds.Validate(tc.seed, tc.region)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewAllowedSeedsFromFile_EmptyPath(t *testing.T) {
+	// when
+	seeds, err := NewAllowedSeedsFromFile("")
+
+	// then
+	require.NoError(t, err)
+	assert.Error(t, seeds.Validate(ptr.String("az-eu1"), ptr.String("westeurope")))
+	assert.NoError(t, seeds.Validate(nil, nil))
+}
@@ -1,6 +1,10 @@
 package broker
 
 import (
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
 	"code.cloudfoundry.org/lager"
 	"github.com/gorilla/mux"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
@@ -8,19 +12,22 @@ import (
 	"github.com/pivotal-cf/brokerapi/v7/middlewares"
 )
 
-// copied from github.com/pivotal-cf/brokerapi/api.go
-func AttachRoutes(router *mux.Router, serviceBroker domain.ServiceBroker, logger lager.Logger) *mux.Router {
+// copied from github.com/pivotal-cf/brokerapi/api.go,
+// the bodies of the PUT and PATCH requests bigger than maxRequestBodySize bytes are rejected before they are decoded
+func AttachRoutes(router *mux.Router, serviceBroker domain.ServiceBroker, maxRequestBodySize int, logger lager.Logger) *mux.Router {
 	apiHandler := handlers.NewApiHandler(serviceBroker, logger)
-	router.HandleFunc("/v2/catalog", apiHandler.Catalog).Methods("GET")
+	limitBody := middleware.LimitRequestBody(maxRequestBodySize)
+	// the catalog is large and fetched repeatedly, so it is sent with the ETag and compressed
+	router.Handle("/v2/catalog", middleware.AddETagAndGzip(http.HandlerFunc(apiHandler.Catalog))).Methods("GET")
 
 	router.HandleFunc("/v2/service_instances/{instance_id}", apiHandler.GetInstance).Methods("GET")
-	router.HandleFunc("/v2/service_instances/{instance_id}", apiHandler.Provision).Methods("PUT")
+	router.Handle("/v2/service_instances/{instance_id}", limitBody(http.HandlerFunc(apiHandler.Provision))).Methods("PUT")
 	router.HandleFunc("/v2/service_instances/{instance_id}", apiHandler.Deprovision).Methods("DELETE")
 	router.HandleFunc("/v2/service_instances/{instance_id}/last_operation", apiHandler.LastOperation).Methods("GET")
-	router.HandleFunc("/v2/service_instances/{instance_id}", apiHandler.Update).Methods("PATCH")
+	router.Handle("/v2/service_instances/{instance_id}", limitBody(http.HandlerFunc(apiHandler.Update))).Methods("PATCH")
 
 	router.HandleFunc("/v2/service_instances/{instance_id}/service_bindings/{binding_id}", apiHandler.GetBinding).Methods("GET")
-	router.HandleFunc("/v2/service_instances/{instance_id}/service_bindings/{binding_id}", apiHandler.Bind).Methods("PUT")
+	router.Handle("/v2/service_instances/{instance_id}/service_bindings/{binding_id}", limitBody(http.HandlerFunc(apiHandler.Bind))).Methods("PUT")
 	router.HandleFunc("/v2/service_instances/{instance_id}/service_bindings/{binding_id}", apiHandler.Unbind).Methods("DELETE")
 
 	router.HandleFunc("/v2/service_instances/{instance_id}/service_bindings/{binding_id}/last_operation", apiHandler.LastBindingOperation).Methods("GET")
@@ -0,0 +1,84 @@
+package broker_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachRoutes_ETagAndGzip(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	require.NoError(t, db.Instances().Insert(internal.Instance{
+		InstanceID:    "instance-id",
+		ServiceID:     broker.KymaServiceID,
+		ServicePlanID: broker.AzurePlanID,
+	}))
+	kymaEnvBroker := &broker.KymaEnvironmentBroker{
+		ServicesEndpoint: broker.NewServices(broker.Config{EnablePlans: []string{"gcp", "azure", "aws"}}, map[string]broker.Service{
+			broker.KymaServiceName: {},
+		}, broker.PlansCatalogMetadata{}, logrus.StandardLogger()),
+		GetInstanceEndpoint: broker.NewGetInstance(db.Instances(), logrus.StandardLogger()),
+	}
+	router := mux.NewRouter()
+	broker.AttachRoutes(router, kymaEnvBroker, 64, lager.NewLogger("test"))
+
+	t.Run("should send the catalog with the ETag and compressed", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/v2/catalog", nil)
+		req.Header.Set("X-Broker-API-Version", "2.14")
+		req.Header.Set("Accept-Encoding", "gzip")
+		recorder := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotEmpty(t, recorder.Header().Get("ETag"))
+		assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("should reject the oversized provisioning request before decoding it", func(t *testing.T) {
+		// given
+		body := `{"service_id": "` + broker.KymaServiceID + `", "plan_id": "` + broker.AzurePlanID + `", "parameters": {"name": "` + strings.Repeat("x", 64) + `"}}`
+		req := httptest.NewRequest(http.MethodPut, "/v2/service_instances/new-instance-id?accepts_incomplete=true", strings.NewReader(body))
+		req.Header.Set("X-Broker-API-Version", "2.14")
+		recorder := httptest.NewRecorder()
+
+		// when
+		// the broker has no provisioning endpoint, the request decoded by brokerapi would make the handler panic
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "exceeds the limit of 64 bytes")
+	})
+
+	t.Run("should not buffer the other endpoints", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/v2/service_instances/instance-id", nil)
+		req.Header.Set("X-Broker-API-Version", "2.14")
+		req.Header.Set("Accept-Encoding", "gzip")
+		recorder := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("ETag"))
+		assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	})
+}
@@ -14,11 +14,13 @@ type ServicesEndpoint struct {
 	log            logrus.FieldLogger
 	cfg            Config
 	servicesConfig ServicesConfig
+	// catalogMetadata holds the cost and SLA metadata merged into the metadata of the plans
+	catalogMetadata PlansCatalogMetadata
 
 	enabledPlanIDs map[string]struct{}
 }
 
-func NewServices(cfg Config, servicesConfig ServicesConfig, log logrus.FieldLogger) *ServicesEndpoint {
+func NewServices(cfg Config, servicesConfig ServicesConfig, catalogMetadata PlansCatalogMetadata, log logrus.FieldLogger) *ServicesEndpoint {
 	enabledPlanIDs := map[string]struct{}{}
 	for _, planName := range cfg.EnablePlans {
 		id := PlanIDsMapping[planName]
@@ -30,6 +32,8 @@ func NewServices(cfg Config, servicesConfig ServicesConfig, log logrus.FieldLogg
 		cfg:            cfg,
 		servicesConfig: servicesConfig,
 		enabledPlanIDs: enabledPlanIDs,
+
+		catalogMetadata: catalogMetadata,
 	}
 }
 
@@ -54,6 +58,7 @@ func (b *ServicesEndpoint) Services(ctx context.Context) ([]domain.Service, erro
 			b.log.Errorf("while unmarshal schema: %s", err)
 			return nil, err
 		}
+		p.Metadata = b.catalogMetadata.Enrich(p.ID, p.Metadata)
 		availableServicePlans = append(availableServicePlans, p)
 	}
 
@@ -64,6 +69,7 @@ func (b *ServicesEndpoint) Services(ctx context.Context) ([]domain.Service, erro
 			Description:          class.Description,
 			Bindable:             true,
 			InstancesRetrievable: true,
+			BindingsRetrievable:  true,
 			Tags: []string{
 				"SAP",
 				"Kyma",
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +29,7 @@ func TestServices_Services(t *testing.T) {
 			},
 		},
 	}
-	servicesEndpoint := broker.NewServices(cfg, servicesConfig, logrus.StandardLogger())
+	servicesEndpoint := broker.NewServices(cfg, servicesConfig, broker.PlansCatalogMetadata{}, logrus.StandardLogger())
 
 	// when
 	services, err := servicesEndpoint.Services(context.TODO())
@@ -41,3 +42,38 @@ func TestServices_Services(t *testing.T) {
 	assert.Equal(t, name, services[0].Metadata.DisplayName)
 	assert.Equal(t, supportURL, services[0].Metadata.SupportUrl)
 }
+
+func TestServices_ServicesWithCatalogMetadata(t *testing.T) {
+	// given
+	cfg := broker.Config{
+		EnablePlans: []string{"gcp", "azure", "trial"},
+	}
+	servicesConfig := map[string]broker.Service{
+		broker.KymaServiceName: {},
+	}
+	catalogMetadata, err := broker.NewPlansCatalogMetadataFromFile("testdata/catalog_metadata.yaml")
+	require.NoError(t, err)
+	servicesEndpoint := broker.NewServices(cfg, servicesConfig, catalogMetadata, logrus.StandardLogger())
+
+	// when
+	services, err := servicesEndpoint.Services(context.TODO())
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	plans := map[string]domain.ServicePlan{}
+	for _, plan := range services[0].Plans {
+		plans[plan.ID] = plan
+	}
+	require.Len(t, plans, 3)
+
+	azure := plans[broker.AzurePlanID].Metadata
+	assert.Equal(t, "AZURE", azure.DisplayName)
+	assert.Equal(t, map[string]interface{}{
+		"costTier":     "standard",
+		"sla":          "99.5",
+		"supportLevel": "enterprise",
+	}, azure.AdditionalMetadata)
+	assert.Equal(t, map[string]interface{}{"costTier": "free"}, plans[broker.TrialPlanID].Metadata.AdditionalMetadata)
+	assert.Equal(t, &domain.ServicePlanMetadata{DisplayName: "GCP"}, plans[broker.GCPPlanID].Metadata)
+}
@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+)
+
+// overridesOnlyParameters are the parameters which change only the Kyma component overrides, not the cluster,
+// so they can be applied by re-running the overrides steps and reconciling Kyma instead of a full upgrade
+var overridesOnlyParameters = map[string]struct{}{
+	"components": {},
+}
+
+// changedParameters returns the names of the parameters sent in the update request with values different from the current ones
+func changedParameters(current internal.ProvisioningParametersDTO, rawParameters json.RawMessage) ([]string, error) {
+	var requested map[string]interface{}
+	if err := json.Unmarshal(rawParameters, &requested); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling requested parameters")
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshaling current parameters")
+	}
+	var currentValues map[string]interface{}
+	if err := json.Unmarshal(currentJSON, &currentValues); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling current parameters")
+	}
+
+	var changed []string
+	for name, value := range requested {
+		if !reflect.DeepEqual(value, currentValues[name]) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// requireFullUpgrade returns the changed parameters which cannot be applied by the overrides-only update
+func requireFullUpgrade(changed []string) []string {
+	var result []string
+	for _, name := range changed {
+		if _, ok := overridesOnlyParameters[name]; !ok {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Expiring keeps the values in the memory of the process for their TTL, at most size values are kept.
+// When the cache is full, the expired values are removed, or the value which expires first if none has expired yet.
+// The cache is disabled if the TTL or the size is not positive, it keeps no values then.
+type Expiring struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[interface{}]entry
+}
+
+// NewExpiring returns the cache which keeps at most size values for the given TTL
+func NewExpiring(ttl time.Duration, size int) *Expiring {
+	return NewExpiringWithClock(ttl, size, time.Now)
+}
+
+// NewExpiringWithClock returns the cache which expires the values by the time returned by the given clock
+func NewExpiringWithClock(ttl time.Duration, size int, now func() time.Time) *Expiring {
+	return &Expiring{
+		ttl:     ttl,
+		size:    size,
+		now:     now,
+		entries: map[interface{}]entry{},
+	}
+}
+
+// Enabled returns false if the cache keeps no values
+func (c *Expiring) Enabled() bool {
+	return c != nil && c.ttl > 0 && c.size > 0
+}
+
+// Get returns the value of the key, false is returned if the key is not cached or its value expired
+func (c *Expiring) Get(key interface{}) (interface{}, bool) {
+	if !c.Enabled() {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, found := c.entries[key]
+	if !found || !c.now().Before(e.expiresAt) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set caches the value of the key for the TTL of the cache
+func (c *Expiring) Set(key, value interface{}) {
+	if !c.Enabled() {
+		return
+	}
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL caches the value of the key for the given TTL
+func (c *Expiring) SetWithTTL(key, value interface{}, ttl time.Duration) {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.size {
+		c.evict()
+	}
+	c.entries[key] = entry{value: value, expiresAt: c.now().Add(ttl)}
+}
+
+// Delete removes the value of the key, the next Get does not find it
+func (c *Expiring) Delete(key interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Len returns the number of the cached values including the expired ones which were not removed yet
+func (c *Expiring) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evict removes the expired entries, or the entry which expires first if none has expired yet
+func (c *Expiring) evict() {
+	now := c.now()
+	var oldest interface{}
+	found := false
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if !found || e.expiresAt.Before(c.entries[oldest].expiresAt) {
+			oldest = key
+			found = true
+		}
+	}
+	if len(c.entries) >= c.size && found {
+		delete(c.entries, oldest)
+	}
+}
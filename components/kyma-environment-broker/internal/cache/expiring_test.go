@@ -0,0 +1,86 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compositeKey struct {
+	subAccountID string
+	planName     string
+}
+
+func TestExpiring_TTL(t *testing.T) {
+	// given
+	now := time.Now()
+	c := cache.NewExpiringWithClock(5*time.Second, 10, func() time.Time { return now })
+	c.Set("short", 1)
+	c.SetWithTTL(compositeKey{subAccountID: "subaccount", planName: "plan"}, 2, time.Minute)
+
+	// when
+	value, found := c.Get("short")
+
+	// then
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	// when
+	now = now.Add(10 * time.Second)
+
+	// then
+	_, found = c.Get("short")
+	assert.False(t, found)
+	value, found = c.Get(compositeKey{subAccountID: "subaccount", planName: "plan"})
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	// when
+	c.Delete(compositeKey{subAccountID: "subaccount", planName: "plan"})
+
+	// then
+	_, found = c.Get(compositeKey{subAccountID: "subaccount", planName: "plan"})
+	assert.False(t, found)
+}
+
+func TestExpiring_Size(t *testing.T) {
+	// given
+	now := time.Now()
+	c := cache.NewExpiringWithClock(time.Minute, 2, func() time.Time { return now })
+
+	// when
+	for _, key := range []string{"first", "second", "third"} {
+		c.Set(key, key)
+		now = now.Add(time.Second)
+	}
+
+	// then
+	assert.Equal(t, 2, c.Len())
+	_, found := c.Get("first")
+	assert.False(t, found)
+	_, found = c.Get("second")
+	assert.True(t, found)
+	_, found = c.Get("third")
+	assert.True(t, found)
+}
+
+func TestExpiring_Disabled(t *testing.T) {
+	for name, c := range map[string]*cache.Expiring{
+		"zero TTL":  cache.NewExpiring(0, 10),
+		"zero size": cache.NewExpiring(time.Minute, 0),
+		"nil cache": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			c.Set("key", "value")
+
+			// then
+			_, found := c.Get("key")
+			assert.False(t, found)
+			assert.False(t, c.Enabled())
+		})
+	}
+}
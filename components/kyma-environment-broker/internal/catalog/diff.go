@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/pkg/errors"
+)
+
+// Diff describes the changes of the plans between two catalog versions
+type Diff struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	AddedPlans   []PlanRef    `json:"addedPlans"`
+	RemovedPlans []PlanRef    `json:"removedPlans"`
+	ChangedPlans []PlanChange `json:"changedPlans"`
+}
+
+type PlanRef struct {
+	ServiceID string `json:"serviceID"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+}
+
+// PlanChange lists the changed fields of the plan using the OSB API names, the changed schemas
+// are listed as schemas.<resource>.<action>, for example schemas.service_instance.create
+type PlanChange struct {
+	PlanRef
+	Changes []string `json:"changes"`
+}
+
+type catalogDocument struct {
+	Services []struct {
+		ID    string                   `json:"id"`
+		Plans []map[string]interface{} `json:"plans"`
+	} `json:"services"`
+}
+
+type plan struct {
+	ref    PlanRef
+	fields map[string]interface{}
+}
+
+// NewDiff returns the changes of the plans from the first to the second catalog snapshot
+func NewDiff(from, to internal.CatalogSnapshot) (Diff, error) {
+	fromPlans, err := plansOf(from)
+	if err != nil {
+		return Diff{}, err
+	}
+	toPlans, err := plansOf(to)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := Diff{
+		From:         from.ETag,
+		To:           to.ETag,
+		AddedPlans:   []PlanRef{},
+		RemovedPlans: []PlanRef{},
+		ChangedPlans: []PlanChange{},
+	}
+	fromByKey := indexPlans(fromPlans)
+	toByKey := indexPlans(toPlans)
+	for _, p := range toPlans {
+		previous, found := fromByKey[planKey(p.ref)]
+		if !found {
+			diff.AddedPlans = append(diff.AddedPlans, p.ref)
+			continue
+		}
+		if changes := planChanges(previous.fields, p.fields); len(changes) > 0 {
+			diff.ChangedPlans = append(diff.ChangedPlans, PlanChange{PlanRef: p.ref, Changes: changes})
+		}
+	}
+	for _, p := range fromPlans {
+		if _, found := toByKey[planKey(p.ref)]; !found {
+			diff.RemovedPlans = append(diff.RemovedPlans, p.ref)
+		}
+	}
+
+	return diff, nil
+}
+
+func plansOf(snapshot internal.CatalogSnapshot) ([]plan, error) {
+	var doc catalogDocument
+	if err := json.Unmarshal([]byte(snapshot.Catalog), &doc); err != nil {
+		return nil, errors.Wrapf(err, "while decoding catalog snapshot %s", snapshot.ETag)
+	}
+
+	var plans []plan
+	for _, service := range doc.Services {
+		for _, fields := range service.Plans {
+			id, _ := fields["id"].(string)
+			name, _ := fields["name"].(string)
+			plans = append(plans, plan{
+				ref:    PlanRef{ServiceID: service.ID, ID: id, Name: name},
+				fields: fields,
+			})
+		}
+	}
+	return plans, nil
+}
+
+func indexPlans(plans []plan) map[string]plan {
+	index := make(map[string]plan, len(plans))
+	for _, p := range plans {
+		index[planKey(p.ref)] = p
+	}
+	return index
+}
+
+func planKey(ref PlanRef) string {
+	return ref.ServiceID + "/" + ref.ID
+}
+
+func planChanges(from, to map[string]interface{}) []string {
+	var changes []string
+	for _, key := range unionKeys(from, to) {
+		if key == "schemas" {
+			changes = append(changes, schemaChanges(from[key], to[key])...)
+			continue
+		}
+		if !reflect.DeepEqual(from[key], to[key]) {
+			changes = append(changes, key)
+		}
+	}
+	return changes
+}
+
+func schemaChanges(from, to interface{}) []string {
+	var changes []string
+	fromResources, toResources := asMap(from), asMap(to)
+	for _, resource := range unionKeys(fromResources, toResources) {
+		fromActions, toActions := asMap(fromResources[resource]), asMap(toResources[resource])
+		for _, action := range unionKeys(fromActions, toActions) {
+			if !reflect.DeepEqual(fromActions[action], toActions[action]) {
+				changes = append(changes, fmt.Sprintf("schemas.%s.%s", resource, action))
+			}
+		}
+	}
+	return changes
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
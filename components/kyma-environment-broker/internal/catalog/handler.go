@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type Handler struct {
+	snapshots storage.CatalogSnapshots
+}
+
+func NewHandler(snapshots storage.CatalogSnapshots) *Handler {
+	return &Handler{
+		snapshots: snapshots,
+	}
+}
+
+func (h *Handler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/catalog/diff", h.getDiff).Methods(http.MethodGet)
+}
+
+// getDiff returns the changes of the plans between the catalog versions identified by the ETags
+// given in the from and to query parameters
+func (h *Handler) getDiff(w http.ResponseWriter, req *http.Request) {
+	from, to := req.URL.Query().Get("from"), req.URL.Query().Get("to")
+	if from == "" || to == "" {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New("the from and to query parameters are required"))
+		return
+	}
+
+	fromSnapshot, code, err := h.getSnapshot(from)
+	if err != nil {
+		httputil.WriteErrorResponse(w, code, err)
+		return
+	}
+	toSnapshot, code, err := h.getSnapshot(to)
+	if err != nil {
+		httputil.WriteErrorResponse(w, code, err)
+		return
+	}
+
+	diff, err := NewDiff(*fromSnapshot, *toSnapshot)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while comparing catalog snapshots"))
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, diff)
+}
+
+func (h *Handler) getSnapshot(etag string) (*internal.CatalogSnapshot, int, error) {
+	snapshot, err := h.snapshots.GetByETag(normalizeETag(etag))
+	switch {
+	case dberr.IsNotFound(err):
+		return nil, http.StatusNotFound, fmt.Errorf("catalog version %s not found", etag)
+	case err != nil:
+		return nil, http.StatusInternalServerError, errors.Wrapf(err, "while getting catalog snapshot %s", etag)
+	}
+	return snapshot, 0, nil
+}
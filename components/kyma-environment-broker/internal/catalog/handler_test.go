@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetDiff(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	snapshotter := NewSnapshotter(db.CatalogSnapshots(), 10, logrus.New())
+
+	azure := fixPlan("azure-id", "azure", map[string]interface{}{"type": "object"})
+	fromETag, err := snapshotter.Save(fixServices(azure))
+	require.NoError(t, err)
+
+	changedAzure := fixPlan("azure-id", "azure", map[string]interface{}{"type": "object", "required": []string{"name"}})
+	trial := fixPlan("trial-id", "trial", map[string]interface{}{"type": "object"})
+	toETag, err := snapshotter.Save(fixServices(changedAzure, trial))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	NewHandler(db.CatalogSnapshots()).AttachRoutes(router)
+
+	t.Run("should return added plan and changed schema", func(t *testing.T) {
+		// when
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, fixDiffRequest(fmt.Sprintf(`W/"%s"`, fromETag), toETag))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var diff Diff
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &diff))
+		assert.Equal(t, fromETag, diff.From)
+		assert.Equal(t, toETag, diff.To)
+		assert.Equal(t, []PlanRef{{ServiceID: "service-id", ID: "trial-id", Name: "trial"}}, diff.AddedPlans)
+		assert.Empty(t, diff.RemovedPlans)
+		assert.Equal(t, []PlanChange{{
+			PlanRef: PlanRef{ServiceID: "service-id", ID: "azure-id", Name: "azure"},
+			Changes: []string{"schemas.service_instance.create"},
+		}}, diff.ChangedPlans)
+	})
+
+	t.Run("should return removed plan", func(t *testing.T) {
+		// when
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, fixDiffRequest(toETag, fromETag))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var diff Diff
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &diff))
+		assert.Empty(t, diff.AddedPlans)
+		assert.Equal(t, []PlanRef{{ServiceID: "service-id", ID: "trial-id", Name: "trial"}}, diff.RemovedPlans)
+	})
+
+	t.Run("should return 404 for unknown ETag", func(t *testing.T) {
+		// when
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, fixDiffRequest(fromETag, "unknown"))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should return 400 without ETags", func(t *testing.T) {
+		// when
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/catalog/diff", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestSnapshotter_Retention(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	snapshotter := NewSnapshotter(db.CatalogSnapshots(), 1, logrus.New())
+
+	// when
+	first, err := snapshotter.Save(fixServices(fixPlan("azure-id", "azure", nil)))
+	require.NoError(t, err)
+	second, err := snapshotter.Save(fixServices(fixPlan("gcp-id", "gcp", nil)))
+	require.NoError(t, err)
+
+	// then
+	_, err = db.CatalogSnapshots().GetByETag(first)
+	assert.Error(t, err)
+	_, err = db.CatalogSnapshots().GetByETag(second)
+	assert.NoError(t, err)
+}
+
+func fixDiffRequest(from, to string) *http.Request {
+	query := url.Values{"from": {from}, "to": {to}}
+	return httptest.NewRequest(http.MethodGet, "/catalog/diff?"+query.Encode(), nil)
+}
+
+func fixServices(plans ...domain.ServicePlan) []domain.Service {
+	return []domain.Service{
+		{
+			ID:    "service-id",
+			Name:  "kymaruntime",
+			Plans: plans,
+		},
+	}
+}
+
+func fixPlan(id, name string, createSchema map[string]interface{}) domain.ServicePlan {
+	return domain.ServicePlan{
+		ID:   id,
+		Name: name,
+		Schemas: &domain.ServiceSchemas{
+			Instance: domain.ServiceInstanceSchema{
+				Create: domain.Schema{Parameters: createSchema},
+			},
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Snapshotter saves the snapshots of the catalog served by the broker, so the differences between
+// the catalog versions can be returned after the broker is restarted with a new catalog
+type Snapshotter struct {
+	snapshots storage.CatalogSnapshots
+	retention int
+	log       logrus.FieldLogger
+}
+
+func NewSnapshotter(snapshots storage.CatalogSnapshots, retention int, log logrus.FieldLogger) *Snapshotter {
+	return &Snapshotter{
+		snapshots: snapshots,
+		retention: retention,
+		log:       log.WithField("service", "CatalogSnapshotter"),
+	}
+}
+
+// Save stores the catalog of the given services and returns its ETag, the oldest snapshots above the retention count are removed
+func (s *Snapshotter) Save(services []domain.Service) (string, error) {
+	body, err := encode(services)
+	if err != nil {
+		return "", errors.Wrap(err, "while encoding catalog")
+	}
+	etag := normalizeETag(middleware.ETag(body))
+
+	err = s.snapshots.Save(internal.CatalogSnapshot{
+		ETag:      etag,
+		Catalog:   string(body),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "while saving catalog snapshot %s", etag)
+	}
+	if err := s.snapshots.DeleteAllExceptNewest(s.retention); err != nil {
+		return "", errors.Wrap(err, "while removing old catalog snapshots")
+	}
+	s.log.Infof("Saved catalog snapshot %s", etag)
+
+	return etag, nil
+}
+
+// encode returns the catalog the way it is encoded by the OSB API catalog endpoint,
+// so the ETag of the snapshot is the one the clients got from the endpoint
+func encode(services []domain.Service) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(apiresponses.CatalogResponse{Services: services}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeETag strips the weak validator prefix and the quotes, so both the header value and the bare ETag can be used
+func normalizeETag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), `"`)
+}
@@ -0,0 +1,13 @@
+package internal
+
+import (
+	"time"
+)
+
+// CatalogSnapshot is the catalog served by the broker identified by its ETag, the snapshots of the previous
+// catalog versions are kept to return the differences between the versions
+type CatalogSnapshot struct {
+	ETag      string
+	Catalog   string
+	CreatedAt time.Time
+}
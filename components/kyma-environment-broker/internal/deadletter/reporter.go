@@ -0,0 +1,167 @@
+package deadletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is the context of the permanently failed operation sent to the dead-letter sink.
+// The parameters do not contain the ERS context and are sanitized, so no credentials are ever sent.
+type Entry struct {
+	OperationID     string                 `json:"operationID"`
+	OperationType   internal.OperationType `json:"operationType"`
+	InstanceID      string                 `json:"instanceID"`
+	RuntimeID       string                 `json:"runtimeID,omitempty"`
+	OrchestrationID string                 `json:"orchestrationID,omitempty"`
+	GlobalAccountID string                 `json:"globalAccountID"`
+	SubAccountID    string                 `json:"subAccountID"`
+	PlanID          string                 `json:"planID"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	Description     string                 `json:"description"`
+	FailedStep      string                 `json:"failedStep"`
+	StepError       string                 `json:"stepError,omitempty"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+	FailedAt        time.Time              `json:"failedAt"`
+}
+
+// Reporter sends the operations to the dead-letter sink when they become failed.
+// It is called asynchronously by the event broker, so sending never blocks processing of the operations.
+type Reporter struct {
+	sink          Sink
+	retries       int
+	retryInterval time.Duration
+	log           logrus.FieldLogger
+}
+
+func NewReporter(sink Sink, retries int, retryInterval time.Duration, log logrus.FieldLogger) *Reporter {
+	return &Reporter{
+		sink:          sink,
+		retries:       retries,
+		retryInterval: retryInterval,
+		log:           log.WithField("service", "DeadLetterReporter"),
+	}
+}
+
+// Subscribe registers the reporter for the step events published by the operation managers
+func (r *Reporter) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(process.ProvisioningStepProcessed{}, r.OnProvisioningStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, r.OnDeprovisioningStepProcessed)
+	sub.Subscribe(process.UpgradeKymaStepProcessed{}, r.OnUpgradeKymaStepProcessed)
+	sub.Subscribe(process.UpgradeClusterStepProcessed{}, r.OnUpgradeClusterStepProcessed)
+	sub.Subscribe(process.OperationFailed{}, r.OnOperationFailed)
+}
+
+func (r *Reporter) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	e, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return errors.New("expected process.ProvisioningStepProcessed")
+	}
+	return r.report(ctx, e.OldOperation.Operation, e.Operation.Operation, e.StepProcessed, e.Operation.RuntimeID)
+}
+
+func (r *Reporter) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	e, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return errors.New("expected process.DeprovisioningStepProcessed")
+	}
+	return r.report(ctx, e.OldOperation.Operation, e.Operation.Operation, e.StepProcessed, e.Operation.RuntimeID)
+}
+
+func (r *Reporter) OnUpgradeKymaStepProcessed(ctx context.Context, ev interface{}) error {
+	e, ok := ev.(process.UpgradeKymaStepProcessed)
+	if !ok {
+		return errors.New("expected process.UpgradeKymaStepProcessed")
+	}
+	return r.report(ctx, e.OldOperation.Operation, e.Operation.Operation, e.StepProcessed, e.Operation.RuntimeOperation.RuntimeID)
+}
+
+func (r *Reporter) OnUpgradeClusterStepProcessed(ctx context.Context, ev interface{}) error {
+	e, ok := ev.(process.UpgradeClusterStepProcessed)
+	if !ok {
+		return errors.New("expected process.UpgradeClusterStepProcessed")
+	}
+	return r.report(ctx, e.OldOperation.Operation, e.Operation.Operation, e.StepProcessed, e.Operation.RuntimeOperation.RuntimeID)
+}
+
+// OnOperationFailed reports the operation failed by the manager because of a non-retryable step error,
+// the step event of such failure carries the operation still in progress
+func (r *Reporter) OnOperationFailed(ctx context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationFailed)
+	if !ok {
+		return errors.New("expected process.OperationFailed")
+	}
+	return r.send(ctx, e.Operation, e.StepProcessed, e.RuntimeID)
+}
+
+func (r *Reporter) report(ctx context.Context, old, operation internal.Operation, step process.StepProcessed, runtimeID string) error {
+	// only the transition to the failed state is reported, so the operation is sent once
+	if operation.State != domain.Failed || old.State == domain.Failed {
+		return nil
+	}
+	return r.send(ctx, operation, step, runtimeID)
+}
+
+func (r *Reporter) send(ctx context.Context, operation internal.Operation, step process.StepProcessed, runtimeID string) error {
+	if event.IsReplay(ctx) {
+		return nil
+	}
+	log := r.log.WithFields(logrus.Fields{"operationID": operation.ID, "instanceID": operation.InstanceID})
+	entry, err := newEntry(operation, step, runtimeID)
+	if err != nil {
+		log.Errorf("Unable to send failed operation to the dead-letter sink: %s", err)
+		return nil
+	}
+
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(r.retryInterval)
+		}
+		if err = r.sink.Send(ctx, entry); err == nil {
+			log.Info("Failed operation sent to the dead-letter sink")
+			return nil
+		}
+		log.Warnf("Unable to send failed operation to the dead-letter sink (attempt %d of %d): %s", attempt+1, r.retries+1, err)
+	}
+	log.Errorf("Giving up sending failed operation to the dead-letter sink: %s", err)
+
+	return nil
+}
+
+func newEntry(operation internal.Operation, step process.StepProcessed, runtimeID string) (Entry, error) {
+	sanitized, err := broker.SanitizeProvisioningParameters(operation.ProvisioningParameters)
+	if err != nil {
+		return Entry{}, errors.Wrap(err, "while sanitizing provisioning parameters")
+	}
+	parameters, _ := sanitized["parameters"].(map[string]interface{})
+
+	entry := Entry{
+		OperationID:     operation.ID,
+		OperationType:   operation.Type,
+		InstanceID:      operation.InstanceID,
+		RuntimeID:       runtimeID,
+		OrchestrationID: operation.OrchestrationID,
+		GlobalAccountID: operation.ProvisioningParameters.ErsContext.GlobalAccountID,
+		SubAccountID:    operation.ProvisioningParameters.ErsContext.SubAccountID,
+		PlanID:          operation.ProvisioningParameters.PlanID,
+		Parameters:      parameters,
+		Description:     operation.Description,
+		FailedStep:      step.StepName,
+		CreatedAt:       operation.CreatedAt,
+		UpdatedAt:       operation.UpdatedAt,
+		FailedAt:        time.Now(),
+	}
+	if step.Error != nil {
+		entry.StepError = step.Error.Error()
+	}
+	return entry, nil
+}
@@ -0,0 +1,168 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter_SendsFailedOperationWithRetry(t *testing.T) {
+	// given
+	var (
+		mu       sync.Mutex
+		calls    int
+		received Entry
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	reporter := NewReporter(NewHTTPSink(server.URL, server.Client()), 2, time.Millisecond, logrus.New())
+	ev := fixFailedProvisioningEvent()
+
+	// when
+	err := reporter.OnProvisioningStepProcessed(context.Background(), ev)
+
+	// then
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "op-id", received.OperationID)
+	assert.Equal(t, internal.OperationTypeProvision, received.OperationType)
+	assert.Equal(t, "instance-id", received.InstanceID)
+	assert.Equal(t, "Create_Runtime", received.FailedStep)
+	assert.Equal(t, "provisioner is not available", received.StepError)
+	assert.Equal(t, ev.Operation.ProvisioningParameters.PlanID, received.PlanID)
+	assert.Equal(t, ev.Operation.ProvisioningParameters.Parameters.Name, received.Parameters["name"])
+	assert.Equal(t, broker.RedactedValue, received.Parameters["targetSecret"])
+	assert.Equal(t, ev.Operation.ProvisioningParameters.ErsContext.GlobalAccountID, received.GlobalAccountID)
+}
+
+func TestReporter_SendsOperationFailedWithNonRetryableError(t *testing.T) {
+	// given
+	sink := &recordingSink{}
+	reporter := NewReporter(sink, 2, time.Millisecond, logrus.New())
+	failed := fixFailedProvisioningEvent()
+
+	// when
+	err := reporter.OnOperationFailed(context.Background(), process.OperationFailed{
+		StepProcessed: failed.StepProcessed,
+		Operation:     failed.Operation.Operation,
+		RuntimeID:     "runtime-id",
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, "op-id", sink.entries[0].OperationID)
+	assert.Equal(t, "runtime-id", sink.entries[0].RuntimeID)
+	assert.Equal(t, "Create_Runtime", sink.entries[0].FailedStep)
+	assert.Equal(t, "provisioner is not available", sink.entries[0].StepError)
+	assert.Equal(t, broker.RedactedValue, sink.entries[0].Parameters["targetSecret"])
+}
+
+func TestReporter_GivesUpAfterRetries(t *testing.T) {
+	// given
+	sink := &failingSink{}
+	reporter := NewReporter(sink, 2, time.Millisecond, logrus.New())
+
+	// when
+	err := reporter.OnProvisioningStepProcessed(context.Background(), fixFailedProvisioningEvent())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, sink.calls)
+}
+
+func TestReporter_IgnoresNotFailedOperations(t *testing.T) {
+	// given
+	sink := &failingSink{}
+	reporter := NewReporter(sink, 2, time.Millisecond, logrus.New())
+	inProgress := fixFailedProvisioningEvent()
+	inProgress.Operation.State = domain.InProgress
+	alreadyFailed := fixFailedProvisioningEvent()
+	alreadyFailed.OldOperation.State = domain.Failed
+
+	// when
+	require.NoError(t, reporter.OnProvisioningStepProcessed(context.Background(), inProgress))
+	require.NoError(t, reporter.OnProvisioningStepProcessed(context.Background(), alreadyFailed))
+
+	// then
+	assert.Zero(t, sink.calls)
+}
+
+func TestReporter_IgnoresReplayedEvents(t *testing.T) {
+	// given
+	sink := &failingSink{}
+	reporter := NewReporter(sink, 2, time.Millisecond, logrus.New())
+
+	// when
+	err := reporter.OnProvisioningStepProcessed(event.WithReplay(context.Background()), fixFailedProvisioningEvent())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, sink.calls)
+}
+
+func fixFailedProvisioningEvent() process.ProvisioningStepProcessed {
+	operation := fixture.FixProvisioningOperation("op-id", "instance-id")
+	operation.State = domain.InProgress
+	failed := operation
+	failed.State = domain.Failed
+	failed.Description = "Operation failed"
+	failed.ProvisioningParameters.Parameters.TargetSecret = ptr.String("secret-name")
+
+	return process.ProvisioningStepProcessed{
+		StepProcessed: process.StepProcessed{
+			StepName: "Create_Runtime",
+			Error:    errors.New("provisioner is not available"),
+		},
+		OldOperation: operation,
+		Operation:    failed,
+	}
+}
+
+type recordingSink struct {
+	entries []Entry
+}
+
+func (s *recordingSink) Send(_ context.Context, entry Entry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+type failingSink struct {
+	calls int
+}
+
+func (s *failingSink) Send(_ context.Context, _ Entry) error {
+	s.calls++
+	return errors.New("sink is not available")
+}
@@ -0,0 +1,85 @@
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SinkHTTP sends the entries as JSON in the POST request body
+	SinkHTTP = "http"
+	// SinkKafka is reserved for the Kafka producer, which is not available yet
+	SinkKafka = "kafka"
+)
+
+type Config struct {
+	// Sink selects where the permanently failed operations are sent, the dead-letter sink is disabled if empty
+	Sink          string        `envconfig:"optional"`
+	URL           string        `envconfig:"optional"`
+	Timeout       time.Duration `envconfig:"default=10s"`
+	Retries       int           `envconfig:"default=3"`
+	RetryInterval time.Duration `envconfig:"default=5s"`
+}
+
+// Sink receives the context of the operations which failed permanently
+type Sink interface {
+	Send(ctx context.Context, entry Entry) error
+}
+
+// NewSink creates the sink selected in the configuration, nil means the dead-letter sink is disabled
+func NewSink(cfg Config) (Sink, error) {
+	switch cfg.Sink {
+	case "":
+		return nil, nil
+	case SinkHTTP:
+		if cfg.URL == "" {
+			return nil, errors.New("URL is required for the http dead-letter sink")
+		}
+		return NewHTTPSink(cfg.URL, &http.Client{Timeout: cfg.Timeout}), nil
+	case SinkKafka:
+		return nil, errors.New("the kafka dead-letter sink is not supported yet")
+	default:
+		return nil, fmt.Errorf("unknown dead-letter sink %q", cfg.Sink)
+	}
+}
+
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *HTTPSink) Send(ctx context.Context, entry Entry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "while marshaling dead-letter entry")
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "while creating request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "while sending dead-letter entry to %s", s.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("dead-letter sink %s returned status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -18,6 +18,10 @@ type ProvisioningParameters struct {
 	//  - `Platform` is a place where KEB is registered and which later sends request to KEB.
 	//  - `Region` value is use e.g. for billing integration such as EDP.
 	PlatformRegion string `json:"platform_region"`
+
+	// SchemaVersion is the version of the plan schema the parameters were stored with,
+	// the parameters stored with the previous versions are migrated before they are used by updates and upgrades
+	SchemaVersion int `json:"schema_version,omitempty"`
 }
 
 func (p ProvisioningParameters) IsEqual(input ProvisioningParameters) bool {
@@ -69,6 +73,118 @@ type ProvisioningParametersDTO struct {
 	KymaVersion                 string   `json:"kymaVersion"`
 	//Provider - used in Trial plan to determine which cloud provider to use during provisioning
 	Provider *TrialCloudProvider `json:"provider"`
+	// Preset - name of the provisioning preset which values were merged with the request parameters
+	Preset string `json:"preset"`
+	// Annotations - custom metadata added to the cluster as annotations
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Seed - name of the Gardener seed which hosts the control plane of the cluster, if empty Gardener chooses the seed
+	Seed *string `json:"seed,omitempty"`
+	// EncryptionKey - reference to the customer-managed key used to encrypt the cluster disks, if empty platform-managed keys are used
+	EncryptionKey *string `json:"encryptionKey,omitempty"`
+	// MachineImage - operating system of the nodes, requested together with its MachineImageVersion which determines the container runtime,
+	// if empty the default machine image is used
+	MachineImage        *string `json:"machineImage,omitempty"`
+	MachineImageVersion *string `json:"machineImageVersion,omitempty"`
+	// KubernetesVersion - version of Kubernetes installed on the cluster, if empty the default version is used
+	KubernetesVersion *string `json:"kubernetesVersion,omitempty"`
+	// PrivateCluster - if true, the API server of the cluster is accessible only from the AllowedCIDRs and the control plane
+	PrivateCluster *bool    `json:"privateCluster,omitempty"`
+	AllowedCIDRs   []string `json:"allowedCIDRs,omitempty"`
+	// ComponentToggles - components enabled or disabled by the client, only the components from the broker allowlist can be toggled
+	ComponentToggles *ComponentToggles `json:"componentToggles,omitempty"`
+	// AllowMultiple - if true, the instance is provisioned even if the subaccount already has an instance of the plan
+	AllowMultiple *bool `json:"allowMultiple,omitempty"`
+	// Ingress - configuration of the load balancer which exposes the ingress gateway of the runtime, if empty the defaults are used
+	Ingress *IngressConfig `json:"ingress,omitempty"`
+	// ResourceQuota - limits of the resources of the runtime, the limits which are not specified are taken from the broker defaults
+	ResourceQuota *ResourceQuota `json:"resourceQuota,omitempty"`
+	// EtcdEncryption - encryption of the etcd of the cluster, if empty the defaults of the landscape are used
+	EtcdEncryption *EtcdEncryption `json:"etcdEncryption,omitempty"`
+	// BackupRetentionDays - number of days the backups of the etcd are kept, if empty the default retention is used
+	BackupRetentionDays *int `json:"backupRetentionDays,omitempty"`
+	// ControlPlaneHA - replicates the etcd and the API server of the cluster across the zones, if empty the default of the plan is used
+	ControlPlaneHA *bool `json:"controlPlaneHA,omitempty"`
+	// MaintenanceWindow - start time and time zone of the maintenance window of the cluster, if empty the default window of the landscape is used
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// CustomDomain - domain under which the runtime exposes its services instead of the domain generated for the cluster,
+	// the domain can be used by only one instance until the instance is deprovisioned
+	CustomDomain *string `json:"customDomain,omitempty"`
+	// KubernetesConfig - options of the kubelet and the API server of the cluster, only the allowlisted feature gates
+	// can be requested, the options which are not specified use the defaults
+	KubernetesConfig *KubernetesConfig `json:"kubernetesConfig,omitempty"`
+}
+
+// ComponentToggles lists the components which are installed or removed on top of the default components of the plan
+type ComponentToggles struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+}
+
+const (
+	IngressLoadBalancerExternal = "external"
+	IngressLoadBalancerInternal = "internal"
+)
+
+// LoadBalancerSKUs lists the load balancer SKUs supported by the hyperscalers, the first SKU is the default of the hyperscaler
+var LoadBalancerSKUs = map[string][]string{
+	"azure":     {"standard"},
+	"aws":       {"classic", "nlb"},
+	"gcp":       {"premium", "standard"},
+	"openstack": {"f5", "octavia"},
+}
+
+// IngressConfig configures the service of the ingress gateway, the annotations are passed to the service as they are
+type IngressConfig struct {
+	LoadBalancerType *string `json:"loadBalancerType,omitempty"`
+	// LoadBalancerSKU - SKU or type of the load balancer supported by the hyperscaler of the plan, if empty the default of the hyperscaler is used
+	LoadBalancerSKU *string           `json:"loadBalancerSKU,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+}
+
+// ResourceQuota limits the number of namespaces and the size of a persistent volume claim in the runtime
+type ResourceQuota struct {
+	Namespaces *int `json:"namespaces,omitempty"`
+	PVCSizeGb  *int `json:"pvcSizeGb,omitempty"`
+}
+
+// EtcdEncryption enables the encryption of the etcd of the cluster, the customer-managed key is used if the KeyRef is set
+type EtcdEncryption struct {
+	Enabled bool    `json:"enabled"`
+	KeyRef  *string `json:"keyRef,omitempty"`
+}
+
+// MaintenanceWindow defines the start of the daily maintenance of the cluster as the local time in the HH:MM format
+// in the IANA time zone, e.g. 22:00 in Europe/Berlin
+type MaintenanceWindow struct {
+	Begin    string `json:"begin"`
+	TimeZone string `json:"timeZone"`
+}
+
+const (
+	EvictionMemoryAvailable   = "memoryAvailable"
+	EvictionImageFSAvailable  = "imageFSAvailable"
+	EvictionImageFSInodesFree = "imageFSInodesFree"
+	EvictionNodeFSAvailable   = "nodeFSAvailable"
+	EvictionNodeFSInodesFree  = "nodeFSInodesFree"
+)
+
+// KubernetesConfig holds the options of the kubelet on the nodes and of the API server of the cluster
+type KubernetesConfig struct {
+	Kubelet   *KubeletConfig   `json:"kubelet,omitempty"`
+	APIServer *APIServerConfig `json:"apiServer,omitempty"`
+}
+
+// KubeletConfig holds the feature gates, the hard eviction thresholds by the eviction signal and the limits of the pods on a node
+type KubeletConfig struct {
+	FeatureGates map[string]bool   `json:"featureGates,omitempty"`
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+	MaxPods      *int              `json:"maxPods,omitempty"`
+	PodPIDsLimit *int              `json:"podPidsLimit,omitempty"`
+}
+
+// APIServerConfig holds the feature gates of the API server
+type APIServerConfig struct {
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
 
 type ERSContext struct {
@@ -78,6 +194,8 @@ type ERSContext struct {
 	ServiceManager  *ServiceManagerEntryDTO `json:"sm_platform_credentials,omitempty"`
 	Active          *bool                   `json:"active,omitempty"`
 	UserID          string                  `json:"user_id"`
+	// SuspensionReason is passed together with the deactivation of the instance
+	SuspensionReason string `json:"suspension_reason,omitempty"`
 }
 
 type ServiceManagerEntryDTO struct {
@@ -35,6 +35,8 @@ type Config struct {
 	Environment string `envconfig:"default=prod"`
 	Required    bool   `envconfig:"default=false"`
 	Disabled    bool
+	// PropagatedLabels lists the customer labels added to the data tenant metadata
+	PropagatedLabels []string `envconfig:"optional"`
 }
 
 type Client struct {
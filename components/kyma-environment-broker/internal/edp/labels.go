@@ -0,0 +1,56 @@
+package edp
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaasConsumerLabelKeyPrefix prefixes the metadata keys of the customer labels, e.g. maasConsumerLabel.owner
+	MaasConsumerLabelKeyPrefix = "maasConsumerLabel."
+
+	maxMetadataValueLength = 1024
+)
+
+// LabelsMetadata returns the metadata of the customer labels which are configured to be propagated to EDP.
+// The labels with values which EDP does not accept are skipped and reported in the returned error.
+func LabelsMetadata(propagatedLabels []string, labels map[string]string) ([]MetadataTenantPayload, error) {
+	var metadata []MetadataTenantPayload
+	var invalid []string
+	for _, key := range propagatedLabels {
+		value, found := labels[key]
+		if !found {
+			continue
+		}
+		if err := validateMetadataValue(value); err != nil {
+			invalid = append(invalid, key+": "+err.Error())
+			continue
+		}
+		metadata = append(metadata, MetadataTenantPayload{
+			Key:   MaasConsumerLabelKeyPrefix + key,
+			Value: value,
+		})
+	}
+	sort.Slice(metadata, func(i, j int) bool { return metadata[i].Key < metadata[j].Key })
+
+	if len(invalid) > 0 {
+		return metadata, errors.Errorf("labels not accepted by EDP: %s", strings.Join(invalid, ", "))
+	}
+	return metadata, nil
+}
+
+func validateMetadataValue(value string) error {
+	switch {
+	case value == "":
+		return errors.New("value must not be empty")
+	case utf8.RuneCountInString(value) > maxMetadataValueLength:
+		return errors.Errorf("value must be no more than %d characters", maxMetadataValueLength)
+	case strings.IndexFunc(value, unicode.IsControl) >= 0:
+		return errors.New("value must not contain control characters")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package edp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsMetadata(t *testing.T) {
+	// given
+	propagatedLabels := []string{"owner", "environment", "team"}
+	labels := map[string]string{
+		"owner":       "zespół A",
+		"environment": "prod\n",
+		"cost-center": "1234",
+	}
+
+	// when
+	metadata, err := LabelsMetadata(propagatedLabels, labels)
+
+	// then
+	assert.EqualError(t, err, "labels not accepted by EDP: environment: value must not contain control characters")
+	assert.Equal(t, []MetadataTenantPayload{
+		{Key: "maasConsumerLabel.owner", Value: "zespół A"},
+	}, metadata)
+}
@@ -0,0 +1,39 @@
+package entitlements
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cache"
+)
+
+// RegionsProvider returns the regions in which the subaccount can provision runtimes
+type RegionsProvider interface {
+	EntitledRegions(subAccountID string) ([]string, error)
+}
+
+// CachedClient keeps the entitlements of the subaccounts for the configured TTL, so the steps which check
+// the entitlements of the same subaccount do not call the entitlements service each time. Errors are not cached.
+type CachedClient struct {
+	client RegionsProvider
+	cache  *cache.Expiring
+}
+
+// NewCachedClient returns the client which caches the responses of the given client. The cache is disabled if the TTL or the size is not positive.
+func NewCachedClient(client RegionsProvider, cfg Config) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  cache.NewExpiring(cfg.CacheTTL, cfg.CacheSize),
+	}
+}
+
+func (c *CachedClient) EntitledRegions(subAccountID string) ([]string, error) {
+	if regions, found := c.cache.Get(subAccountID); found {
+		return regions.([]string), nil
+	}
+
+	regions, err := c.client.EntitledRegions(subAccountID)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(subAccountID, regions)
+
+	return regions, nil
+}
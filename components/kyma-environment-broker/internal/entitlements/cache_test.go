@@ -0,0 +1,106 @@
+package entitlements
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedClient_EntitledRegions(t *testing.T) {
+	t.Run("should return cached regions within TTL", func(t *testing.T) {
+		// given
+		client := &countingClient{regions: []string{"westeurope"}}
+		cached := NewCachedClient(client, Config{CacheTTL: time.Minute, CacheSize: 10})
+		now := time.Now()
+		cached.cache = cache.NewExpiringWithClock(time.Minute, 10, func() time.Time { return now })
+
+		// when
+		first, err := cached.EntitledRegions("subaccount")
+		require.NoError(t, err)
+		now = now.Add(30 * time.Second)
+		second, err := cached.EntitledRegions("subaccount")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []string{"westeurope"}, first)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, client.calls)
+
+		// when
+		now = now.Add(time.Minute)
+		_, err = cached.EntitledRegions("subaccount")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 2, client.calls)
+	})
+
+	t.Run("should not cache errors", func(t *testing.T) {
+		// given
+		client := &countingClient{err: errors.New("service unavailable")}
+		cached := NewCachedClient(client, Config{CacheTTL: time.Minute, CacheSize: 10})
+
+		// when
+		_, err := cached.EntitledRegions("subaccount")
+		require.Error(t, err)
+		client.err = nil
+		client.regions = []string{"westeurope"}
+		regions, err := cached.EntitledRegions("subaccount")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"westeurope"}, regions)
+		assert.Equal(t, 2, client.calls)
+	})
+
+	t.Run("should evict entry which expires first if cache is full", func(t *testing.T) {
+		// given
+		client := &countingClient{regions: []string{"westeurope"}}
+		cached := NewCachedClient(client, Config{CacheTTL: time.Minute, CacheSize: 2})
+		now := time.Now()
+		cached.cache = cache.NewExpiringWithClock(time.Minute, 2, func() time.Time { return now })
+
+		// when
+		for _, subAccountID := range []string{"first", "second", "third"} {
+			_, err := cached.EntitledRegions(subAccountID)
+			require.NoError(t, err)
+			now = now.Add(time.Second)
+		}
+
+		// then
+		assert.Equal(t, 2, cached.cache.Len())
+		_, found := cached.cache.Get("first")
+		assert.False(t, found)
+	})
+
+	t.Run("should call client if cache is disabled", func(t *testing.T) {
+		// given
+		client := &countingClient{regions: []string{"westeurope"}}
+		cached := NewCachedClient(client, Config{CacheSize: 10})
+
+		// when
+		_, err := cached.EntitledRegions("subaccount")
+		require.NoError(t, err)
+		_, err = cached.EntitledRegions("subaccount")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 2, client.calls)
+	})
+}
+
+type countingClient struct {
+	regions []string
+	err     error
+	calls   int
+}
+
+func (c *countingClient) EntitledRegions(string) ([]string, error) {
+	c.calls++
+	return c.regions, c.err
+}
@@ -0,0 +1,73 @@
+package entitlements
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+
+	"github.com/pkg/errors"
+)
+
+const entitledRegionsTmpl = "%s/subaccounts/%s/regions"
+
+type Config struct {
+	// Disabled skips the validation of the requested region against the entitlements of the subaccount
+	Disabled bool          `envconfig:"default=true"`
+	URL      string        `envconfig:"optional"`
+	Timeout  time.Duration `envconfig:"default=10s"`
+
+	// CacheTTL is the time for which the entitlements of a subaccount are reused by the next lookups, 0 disables the cache
+	CacheTTL time.Duration `envconfig:"default=1m"`
+	// CacheSize limits the number of subaccounts which entitlements are cached
+	CacheSize int `envconfig:"default=1000"`
+}
+
+type entitledRegionsResponse struct {
+	Regions []string `json:"regions"`
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// EntitledRegions returns the hyperscaler regions in which the subaccount can provision runtimes.
+// Nil means that the regions of the subaccount are not restricted.
+func (c *Client) EntitledRegions(subAccountID string) ([]string, error) {
+	URL := fmt.Sprintf(entitledRegionsTmpl, c.config.URL, url.PathEscape(subAccountID))
+	response, err := c.httpClient.Get(URL)
+	if err != nil {
+		return nil, kebError.AsTemporaryError(err, "while requesting entitled regions of subaccount %s", subAccountID)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case response.StatusCode == http.StatusRequestTimeout, response.StatusCode >= http.StatusInternalServerError:
+		return nil, kebError.NewTemporaryError("entitlements service returned status %d for subaccount %s", response.StatusCode, subAccountID)
+	case response.StatusCode != http.StatusOK:
+		return nil, errors.Errorf("entitlements service returned status %d for subaccount %s", response.StatusCode, subAccountID)
+	}
+
+	var body entitledRegionsResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "while decoding entitled regions response")
+	}
+	if body.Regions == nil {
+		return []string{}, nil
+	}
+
+	return body.Regions, nil
+}
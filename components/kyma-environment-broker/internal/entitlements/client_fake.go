@@ -0,0 +1,14 @@
+package entitlements
+
+// FakeClient returns the entitled regions from the map, subaccounts which are not in the map are not restricted
+type FakeClient struct {
+	regions map[string][]string
+}
+
+func NewFakeClient(regions map[string][]string) *FakeClient {
+	return &FakeClient{regions: regions}
+}
+
+func (f *FakeClient) EntitledRegions(subAccountID string) ([]string, error) {
+	return f.regions[subAccountID], nil
+}
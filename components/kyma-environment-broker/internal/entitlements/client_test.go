@@ -0,0 +1,60 @@
+package entitlements
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_EntitledRegions(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subaccounts/restricted/regions":
+			w.Write([]byte(`{"regions": ["westeurope", "northeurope"]}`))
+		case "/subaccounts/nothing-entitled/regions":
+			w.Write([]byte(`{"regions": []}`))
+		case "/subaccounts/unavailable/regions":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, Timeout: time.Second})
+
+	// when
+	restricted, err := client.EntitledRegions("restricted")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"westeurope", "northeurope"}, restricted)
+
+	// when
+	nothingEntitled, err := client.EntitledRegions("nothing-entitled")
+
+	// then
+	require.NoError(t, err)
+	assert.NotNil(t, nothingEntitled)
+	assert.Empty(t, nothingEntitled)
+
+	// when
+	notRestricted, err := client.EntitledRegions("unknown")
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, notRestricted)
+
+	// when
+	_, err = client.EntitledRegions("unavailable")
+
+	// then
+	require.Error(t, err)
+	assert.True(t, kebError.IsTemporaryError(err))
+}
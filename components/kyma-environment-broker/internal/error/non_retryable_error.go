@@ -0,0 +1,39 @@
+package error
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// NonRetryableError is returned by the steps when retrying cannot succeed, e.g. the parameters are invalid
+// or the request was rejected by a dependency, the step managers fail the operation immediately
+type NonRetryableError struct {
+	message string
+}
+
+func NewNonRetryableError(msg string, args ...interface{}) *NonRetryableError {
+	return &NonRetryableError{message: fmt.Sprintf(msg, args...)}
+}
+
+func AsNonRetryableError(err error, context string, args ...interface{}) *NonRetryableError {
+	errCtx := fmt.Sprintf(context, args...)
+	msg := fmt.Sprintf("%s: %s", errCtx, err.Error())
+
+	return &NonRetryableError{message: msg}
+}
+
+func (nre NonRetryableError) Error() string { return nre.message }
+func (NonRetryableError) Retryable() bool   { return false }
+
+// RetryClassifier decides if the step which returned the error can be retried
+type RetryClassifier func(err error) bool
+
+// IsRetryable is the default RetryClassifier, errors are retryable unless their cause reports otherwise
+func IsRetryable(err error) bool {
+	cause := errors.Cause(err)
+	re, ok := cause.(interface {
+		Retryable() bool
+	})
+	return !ok || re.Retryable()
+}
@@ -0,0 +1,32 @@
+package error
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonRetryableError(t *testing.T) {
+	// given
+	err1 := fmt.Errorf("some error: %s", "argErr")
+	err2 := fmt.Errorf("some error: %s", "argErr")
+	err3 := NewNonRetryableError("some error: %s", fmt.Errorf("argErr"))
+	err4 := NewTemporaryError("some error: %s", fmt.Errorf("argErr"))
+
+	// when
+	e1 := errors.Wrapf(err1, "wrap err %s", "arg1")
+	e2 := AsNonRetryableError(err2, "wrap err %s", "arg1")
+	e3 := errors.Wrapf(err3, "wrap err %s", "arg1")
+	e4 := errors.Wrapf(err4, "wrap err %s", "arg1")
+
+	// then
+	assert.True(t, IsRetryable(e1))
+	assert.False(t, IsRetryable(e2))
+	assert.False(t, IsRetryable(e3))
+	assert.True(t, IsRetryable(e4))
+
+	assert.Equal(t, "wrap err arg1: some error: argErr", e2.Error())
+	assert.Equal(t, "wrap err arg1: some error: argErr", e3.Error())
+}
@@ -0,0 +1,16 @@
+package event
+
+import "context"
+
+type replayKey struct{}
+
+// WithReplay marks the events published with the returned context as replayed from the storage
+func WithReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+// IsReplay returns true if the event was replayed, handlers with external side effects should skip such events
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
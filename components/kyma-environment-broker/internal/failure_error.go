@@ -0,0 +1,38 @@
+package internal
+
+import "github.com/pkg/errors"
+
+// FailureError is returned by the steps which fail the operation for a known reason,
+// the reason is stored on the operation by the OperationFailedWithError methods of the operation managers
+type FailureError struct {
+	reason FailureReason
+}
+
+// NewFailureError returns the error with the given failure reason, the message of the reason is the message of the error
+func NewFailureError(reason FailureReason) *FailureError {
+	return &FailureError{reason: reason}
+}
+
+// AsFailureError returns the error with the given failure reason, the message of the error is used if the reason has no message
+func AsFailureError(err error, reason FailureReason) *FailureError {
+	if reason.Message == "" {
+		reason.Message = err.Error()
+	}
+	return &FailureError{reason: reason}
+}
+
+func (fe FailureError) Error() string                { return fe.reason.Message }
+func (fe FailureError) FailureReason() FailureReason { return fe.reason }
+
+// FailureReasonOf returns the failure reason of the FailureError, nil is returned for other errors
+func FailureReasonOf(err error) *FailureReason {
+	cause := errors.Cause(err)
+	fe, ok := cause.(interface {
+		FailureReason() FailureReason
+	})
+	if !ok {
+		return nil
+	}
+	reason := fe.FailureReason()
+	return &reason
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureError(t *testing.T) {
+	// given
+	reason := FailureReason{
+		Code:        "QUOTA_EXCEEDED",
+		Remediation: "Increase the quota of the hyperscaler account",
+		Subsystem:   "provisioner",
+	}
+
+	// when
+	err1 := errors.Wrap(AsFailureError(fmt.Errorf("quota exceeded"), reason), "while provisioning")
+	err2 := NewFailureError(FailureReason{Code: "REGION_NOT_ENTITLED", Message: "region is not entitled", Retryable: true})
+	err3 := errors.New("quota exceeded")
+
+	// then
+	got := FailureReasonOf(err1)
+	require.NotNil(t, got)
+	assert.Equal(t, "QUOTA_EXCEEDED", got.Code)
+	assert.Equal(t, "quota exceeded", got.Message)
+	assert.Equal(t, "Increase the quota of the hyperscaler account", got.Remediation)
+	assert.Equal(t, "provisioner", got.Subsystem)
+	assert.False(t, got.Retryable)
+	assert.Equal(t, "while provisioning: quota exceeded", err1.Error())
+
+	got = FailureReasonOf(err2)
+	require.NotNil(t, got)
+	assert.True(t, got.Retryable)
+	assert.Equal(t, "region is not entitled", err2.Error())
+
+	assert.Nil(t, FailureReasonOf(err3))
+	assert.Nil(t, FailureReasonOf(nil))
+}
@@ -0,0 +1,122 @@
+package featureflags
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// InstanceFlagsDTO holds the flags of the instance, setting the flags replaces all flags of the instance
+type InstanceFlagsDTO struct {
+	Flags map[string]bool `json:"flags"`
+}
+
+type instanceFlagsHandler struct {
+	instances storage.Instances
+	flags     storage.InstanceFlags
+	known     map[string]struct{}
+	log       logrus.FieldLogger
+}
+
+// NewInstanceFlagsHandler exposes the GET and PUT /admin/instances/{instance_id}/flags endpoints, only the known flags can be set
+func NewInstanceFlagsHandler(instances storage.Instances, flags storage.InstanceFlags, known []string, log logrus.FieldLogger) http.Handler {
+	h := &instanceFlagsHandler{
+		instances: instances,
+		flags:     flags,
+		known:     map[string]struct{}{},
+		log:       log.WithField("service", "InstanceFlagsHandler"),
+	}
+	for _, flag := range known {
+		h.known[flag] = struct{}{}
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/instances/{instance_id}/flags", h.getFlags).Methods(http.MethodGet)
+	router.HandleFunc("/admin/instances/{instance_id}/flags", h.setFlags).Methods(http.MethodPut)
+
+	return router
+}
+
+func (h *instanceFlagsHandler) getFlags(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	if !h.instanceExists(w, instanceID) {
+		return
+	}
+
+	flags, err := h.flags.GetByInstanceID(instanceID)
+	if err != nil {
+		h.log.Errorf("while getting flags of instance %s: %v", instanceID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while getting flags of instance %s", instanceID))
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, InstanceFlagsDTO{Flags: flags})
+}
+
+func (h *instanceFlagsHandler) setFlags(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	var req InstanceFlagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while decoding request body"))
+		return
+	}
+	if err := h.validate(req.Flags); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if !h.instanceExists(w, instanceID) {
+		return
+	}
+
+	if err := h.flags.Set(instanceID, req.Flags); err != nil {
+		h.log.Errorf("while setting flags of instance %s: %v", instanceID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while setting flags of instance %s", instanceID))
+		return
+	}
+	h.log.Infof("Flags of instance %s were set to %v", instanceID, req.Flags)
+
+	httputil.WriteResponse(w, http.StatusOK, req)
+}
+
+func (h *instanceFlagsHandler) validate(flags map[string]bool) error {
+	var unknown []string
+	for flag := range flags {
+		if _, found := h.known[flag]; !found {
+			unknown = append(unknown, flag)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	known := make([]string, 0, len(h.known))
+	for flag := range h.known {
+		known = append(known, flag)
+	}
+	sort.Strings(known)
+
+	return errors.Errorf("unknown flags: %s, known flags: %s", strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
+
+func (h *instanceFlagsHandler) instanceExists(w http.ResponseWriter, instanceID string) bool {
+	_, err := h.instances.GetByID(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "while getting instance %s", instanceID))
+		return false
+	case err != nil:
+		h.log.Errorf("while getting instance %s: %v", instanceID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while getting instance %s", instanceID))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package featureflags
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceFlagsHandler(t *testing.T) {
+	t.Run("should set and get the flags of the instance", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		require.NoError(t, s.Instances().Insert(fixture.FixInstance("instance-1")))
+		handler := NewInstanceFlagsHandler(s.Instances(), s.InstanceFlags(), []string{"flag-a", "flag-b"}, logrus.New())
+
+		// when
+		rr := callInstanceFlags(t, handler, http.MethodPut, "instance-1", &InstanceFlagsDTO{Flags: map[string]bool{"flag-a": false}})
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		flags, err := s.InstanceFlags().GetByInstanceID("instance-1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"flag-a": false}, flags)
+
+		// when
+		rr = callInstanceFlags(t, handler, http.MethodGet, "instance-1", nil)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out InstanceFlagsDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, map[string]bool{"flag-a": false}, out.Flags)
+	})
+
+	t.Run("should reject unknown flags", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		require.NoError(t, s.Instances().Insert(fixture.FixInstance("instance-1")))
+		handler := NewInstanceFlagsHandler(s.Instances(), s.InstanceFlags(), []string{"flag-a"}, logrus.New())
+
+		// when
+		rr := callInstanceFlags(t, handler, http.MethodPut, "instance-1", &InstanceFlagsDTO{Flags: map[string]bool{"flag-a": true, "unknown": true}})
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		flags, err := s.InstanceFlags().GetByInstanceID("instance-1")
+		require.NoError(t, err)
+		assert.Empty(t, flags)
+	})
+
+	t.Run("should return not found for unknown instance", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		handler := NewInstanceFlagsHandler(s.Instances(), s.InstanceFlags(), []string{"flag-a"}, logrus.New())
+
+		// when
+		rr := callInstanceFlags(t, handler, http.MethodPut, "instance-1", &InstanceFlagsDTO{Flags: map[string]bool{"flag-a": true}})
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func callInstanceFlags(t *testing.T, handler http.Handler, method, instanceID string, body *InstanceFlagsDTO) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&payload).Encode(body))
+	}
+	req, err := http.NewRequest(method, "/admin/instances/"+instanceID+"/flags", &payload)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
@@ -0,0 +1,124 @@
+package featureflags
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Names of the flags which can be toggled at runtime. They are the keys in the feature flags ConfigMap.
+const (
+	UpdateProcessing = "UpdateProcessingEnabled"
+	OnDemandVersion  = "EnableOnDemandVersion"
+	EDP              = "EDPEnabled"
+)
+
+type Config struct {
+	Namespace       string        `envconfig:"default=kcp-system"`
+	Name            string        `envconfig:"default=kyma-environment-broker-feature-flags"`
+	RefreshInterval time.Duration `envconfig:"default=1m"`
+}
+
+// Provider tells if the feature is enabled at the moment of the call
+type Provider interface {
+	IsEnabled(flag string) bool
+}
+
+// Static is a Provider with fixed values of the flags, not listed flags are disabled
+type Static map[string]bool
+
+func (s Static) IsEnabled(flag string) bool {
+	return s[flag]
+}
+
+// ConfigMapProvider serves the flags from the ConfigMap which is periodically re-read.
+// Only flags with the default value can be toggled, if the ConfigMap does not contain
+// the flag or the ConfigMap does not exist the default value is used.
+type ConfigMapProvider struct {
+	k8sClient client.Client
+	cfg       Config
+	defaults  map[string]bool
+
+	mu    sync.RWMutex
+	flags map[string]bool
+
+	log logrus.FieldLogger
+}
+
+func NewConfigMapProvider(cli client.Client, cfg Config, defaults map[string]bool, log logrus.FieldLogger) *ConfigMapProvider {
+	flags := make(map[string]bool, len(defaults))
+	for flag, enabled := range defaults {
+		flags[flag] = enabled
+	}
+
+	return &ConfigMapProvider{
+		k8sClient: cli,
+		cfg:       cfg,
+		defaults:  defaults,
+		flags:     flags,
+		log:       log,
+	}
+}
+
+// Run refreshes the flags until the context is done
+func (p *ConfigMapProvider) Run(ctx context.Context) {
+	wait.Until(func() {
+		if err := p.Refresh(ctx); err != nil {
+			p.log.Errorf("unable to refresh feature flags: %s", err)
+		}
+	}, p.cfg.RefreshInterval, ctx.Done())
+}
+
+// Refresh reads the current values of the flags from the ConfigMap
+func (p *ConfigMapProvider) Refresh(ctx context.Context) error {
+	config := &v1.ConfigMap{}
+	key := client.ObjectKey{Namespace: p.cfg.Namespace, Name: p.cfg.Name}
+	err := p.k8sClient.Get(ctx, key, config)
+	switch {
+	case apierr.IsNotFound(err):
+		config.Data = map[string]string{}
+	case err != nil:
+		return errors.Wrap(err, "while getting feature flags config map")
+	}
+
+	flags := make(map[string]bool, len(p.defaults))
+	for flag, enabled := range p.defaults {
+		flags[flag] = enabled
+		value, found := config.Data[flag]
+		if !found {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			p.log.Warnf("invalid value %q of feature flag %s, using default %t", value, flag, enabled)
+			continue
+		}
+		flags[flag] = parsed
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for flag, enabled := range flags {
+		if p.flags[flag] != enabled {
+			p.log.Infof("feature flag %s changed to %t", flag, enabled)
+		}
+	}
+	p.flags = flags
+
+	return nil
+}
+
+func (p *ConfigMapProvider) IsEnabled(flag string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.flags[flag]
+}
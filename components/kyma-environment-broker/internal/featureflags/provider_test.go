@@ -0,0 +1,69 @@
+package featureflags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapProvider_Refresh(t *testing.T) {
+	// given
+	cfg := Config{Namespace: "kcp-system", Name: "feature-flags"}
+	cli := fixClient(t)
+	provider := NewConfigMapProvider(cli, cfg, map[string]bool{
+		UpdateProcessing: false,
+		OnDemandVersion:  true,
+		EDP:              true,
+	}, logrus.New())
+
+	// when
+	err := provider.Refresh(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, provider.IsEnabled(UpdateProcessing))
+	assert.True(t, provider.IsEnabled(OnDemandVersion))
+	assert.True(t, provider.IsEnabled(EDP))
+
+	// when the ConfigMap is created
+	configMap := &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace},
+		Data: map[string]string{
+			UpdateProcessing: "true",
+			OnDemandVersion:  "not-a-bool",
+			"UnknownFlag":    "true",
+		},
+	}
+	require.NoError(t, cli.Create(context.Background(), configMap))
+	err = provider.Refresh(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, provider.IsEnabled(UpdateProcessing))
+	assert.True(t, provider.IsEnabled(OnDemandVersion))
+	assert.True(t, provider.IsEnabled(EDP))
+	assert.False(t, provider.IsEnabled("UnknownFlag"))
+
+	// when the ConfigMap is removed
+	require.NoError(t, cli.Delete(context.Background(), configMap))
+	err = provider.Refresh(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, provider.IsEnabled(UpdateProcessing))
+}
+
+func fixClient(t *testing.T) client.Client {
+	sch := runtime.NewScheme()
+	require.NoError(t, coreV1.AddToScheme(sch))
+
+	return fake.NewFakeClientWithScheme(sch)
+}
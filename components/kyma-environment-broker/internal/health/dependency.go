@@ -0,0 +1,157 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type DependencyConfig struct {
+	// ProvisionerURL is the readiness endpoint of the Provisioner, the check is disabled if empty
+	ProvisionerURL string `envconfig:"optional"`
+	// CheckInterval defines how often the dependencies are checked
+	CheckInterval time.Duration `envconfig:"default=10s"`
+	// FailureThreshold is the number of consecutive failed checks after which the dependency is reported as unhealthy
+	FailureThreshold int `envconfig:"default=3"`
+
+	// ClsURL, EmsURL and IasURL are the readiness endpoints of the subsystems checked by /readyz,
+	// the check of a subsystem is disabled if its URL is empty or the subsystem is disabled
+	ClsURL string `envconfig:"optional"`
+	EmsURL string `envconfig:"optional"`
+	IasURL string `envconfig:"optional"`
+}
+
+// DisabledSubsystems holds the Disabled flags of the subsystems, the same flags which exclude the subsystem steps
+// from the operations
+type DisabledSubsystems struct {
+	Cls bool
+	Ems bool
+	IAS bool
+}
+
+// EnabledSubsystems returns the readiness endpoints of the subsystems by the subsystem name, the disabled subsystems
+// are not included, so they are not probed and cannot make the broker unready
+func (c DependencyConfig) EnabledSubsystems(disabled DisabledSubsystems) map[string]string {
+	urls := map[string]string{}
+	for name, subsystem := range map[string]struct {
+		url      string
+		disabled bool
+	}{
+		"cls": {url: c.ClsURL, disabled: disabled.Cls},
+		"ems": {url: c.EmsURL, disabled: disabled.Ems},
+		"ias": {url: c.IasURL, disabled: disabled.IAS},
+	} {
+		if subsystem.url == "" || subsystem.disabled {
+			continue
+		}
+		urls[name] = subsystem.url
+	}
+	return urls
+}
+
+// DependencyChecker periodically calls the readiness endpoint of a dependency.
+// The dependency is healthy until the check fails FailureThreshold times in a row,
+// a single successful check makes it healthy again.
+type DependencyChecker struct {
+	name      string
+	url       string
+	threshold int
+	client    *http.Client
+	log       logrus.FieldLogger
+
+	mu       sync.RWMutex
+	failures int
+	healthy  bool
+	passed   bool
+	lastErr  error
+}
+
+func NewDependencyChecker(name, url string, threshold int, log logrus.FieldLogger) *DependencyChecker {
+	return &DependencyChecker{
+		name:      name,
+		url:       url,
+		threshold: threshold,
+		client:    &http.Client{Timeout: 5 * time.Second},
+		log:       log.WithField("dependency", name),
+		healthy:   true,
+	}
+}
+
+// Run checks the dependency every interval until the stop channel is closed
+func (c *DependencyChecker) Run(stop <-chan struct{}, interval time.Duration) {
+	go wait.Until(c.Check, interval, stop)
+}
+
+func (c *DependencyChecker) Check() {
+	err := c.probe()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = err
+	if err != nil {
+		c.failures++
+		if c.healthy && c.failures >= c.threshold {
+			c.healthy = false
+			c.log.Errorf("Dependency is unhealthy after %d failed checks: %s", c.failures, err)
+		}
+		return
+	}
+	c.failures = 0
+	c.passed = true
+	if !c.healthy {
+		c.healthy = true
+		c.log.Info("Dependency is healthy again")
+	}
+}
+
+func (c *DependencyChecker) Healthy() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.healthy
+}
+
+// Ready reports if the dependency is healthy and passed at least one check, the dependency is healthy
+// before the first check but it is not known yet if it is ready
+func (c *DependencyChecker) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.healthy && c.passed
+}
+
+func (c *DependencyChecker) Name() string {
+	return c.name
+}
+
+// Status returns the state of the dependency reported by the status endpoint
+func (c *DependencyChecker) Status() interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status := DependencyStatus{Healthy: c.healthy, ConsecutiveFailures: c.failures}
+	if c.lastErr != nil {
+		status.LastError = c.lastErr.Error()
+	}
+	return status
+}
+
+func (c *DependencyChecker) probe() error {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return errors.Wrapf(err, "while calling %s", c.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", c.url, resp.StatusCode)
+	}
+	return nil
+}
+
+type DependencyStatus struct {
+	Healthy             bool   `json:"healthy"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	LastError           string `json:"lastError,omitempty"`
+}
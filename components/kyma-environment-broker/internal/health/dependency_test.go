@@ -0,0 +1,142 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyChecker(t *testing.T) {
+	// given
+	var status int32 = http.StatusServiceUnavailable
+	dependency := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer dependency.Close()
+
+	checker := NewDependencyChecker("provisioner", dependency.URL, 2, logrus.New())
+
+	// when
+	checker.Check()
+
+	// then
+	assert.True(t, checker.Healthy())
+	assert.False(t, checker.Ready())
+
+	// when
+	checker.Check()
+
+	// then
+	assert.False(t, checker.Healthy())
+	assert.Equal(t, 2, checker.Status().(DependencyStatus).ConsecutiveFailures)
+
+	// when
+	atomic.StoreInt32(&status, http.StatusOK)
+	checker.Check()
+
+	// then
+	assert.True(t, checker.Healthy())
+	assert.True(t, checker.Ready())
+	assert.Equal(t, DependencyStatus{Healthy: true}, checker.Status())
+}
+
+func TestServer_Status(t *testing.T) {
+	// given
+	srv := NewServer("localhost", "8080", httputil.TLSConfig{}, logrus.New())
+	srv.AddStatus("provisioningQueue", func() interface{} { return map[string]bool{"paused": true} })
+	recorder := httptest.NewRecorder()
+
+	// when
+	srv.statusHandler(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var status map[string]map[string]bool
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	assert.True(t, status["provisioningQueue"]["paused"])
+}
+
+func TestServer_RegisteredHandler(t *testing.T) {
+	// given
+	srv := NewServer("localhost", "8080", httputil.TLSConfig{}, logrus.New())
+	srv.AddHandler("/events/replay", fixNamedHandler("replay"))
+	srv.AddHandler("/admin/", fixNamedHandler("admin"))
+	srv.AddHandler("/admin/orchestrations/", fixNamedHandler("orchestrations"))
+
+	for path, expected := range map[string]string{
+		"/events/replay":                          "replay",
+		"/admin/instances":                        "admin",
+		"/admin/orchestrations/id/force-complete": "orchestrations",
+	} {
+		recorder := httptest.NewRecorder()
+
+		// when
+		srv.registeredHandler(recorder, httptest.NewRequest(http.MethodPost, path, nil))
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, expected, recorder.Body.String())
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.registeredHandler(recorder, httptest.NewRequest(http.MethodPost, "/events/replay/other", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func fixNamedHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(name))
+	})
+}
+
+func TestDependencyConfig_EnabledSubsystems(t *testing.T) {
+	// given
+	cfg := DependencyConfig{
+		ClsURL: "http://cls/ready",
+		EmsURL: "http://ems/ready",
+	}
+
+	// when
+	urls := cfg.EnabledSubsystems(DisabledSubsystems{Cls: false, Ems: true, IAS: false})
+
+	// then
+	assert.Equal(t, map[string]string{"cls": "http://cls/ready"}, urls)
+}
+
+func TestServer_Readiness(t *testing.T) {
+	// given
+	srv := NewServer("localhost", "8080", httputil.TLSConfig{}, logrus.New())
+	subsystemURLs := DependencyConfig{ClsURL: "http://cls/ready", EmsURL: "http://ems/ready"}.
+		EnabledSubsystems(DisabledSubsystems{Ems: true})
+	ready := map[string]bool{"cls": true, "ems": false}
+	for name := range subsystemURLs {
+		name := name
+		srv.AddReadinessCheck(name, func() bool { return ready[name] })
+	}
+	recorder := httptest.NewRecorder()
+
+	// when
+	srv.readinessHandler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// when
+	ready["cls"] = false
+	recorder = httptest.NewRecorder()
+	srv.readinessHandler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// then
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var readiness map[string]bool
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &readiness))
+	assert.Equal(t, map[string]bool{"cls": false}, readiness)
+}
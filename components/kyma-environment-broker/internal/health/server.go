@@ -1,8 +1,13 @@
 package health
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -10,21 +15,59 @@ import (
 
 type Server struct {
 	Address string
+	TLS     httputil.TLSConfig
 	Log     log.FieldLogger
+
+	statusMu        sync.RWMutex
+	statusProviders map[string]func() interface{}
+	readinessChecks map[string]func() bool
+
+	handlersMu sync.RWMutex
+	handlers   map[string]http.Handler
 }
 
-func NewServer(host, port string, log *log.Logger) *Server {
+func NewServer(host, port string, tlsConfig httputil.TLSConfig, log *log.Logger) *Server {
 	return &Server{
 		Address: fmt.Sprintf("%s:%s", host, port),
+		TLS:     tlsConfig,
 		Log:     log.WithField("server", "health"),
+
+		statusProviders: map[string]func() interface{}{},
+		readinessChecks: map[string]func() bool{},
+		handlers:        map[string]http.Handler{},
 	}
 }
 
+// AddStatus registers the component whose state is returned under the given name by the /status endpoint
+func (srv *Server) AddStatus(name string, provider func() interface{}) {
+	srv.statusMu.Lock()
+	defer srv.statusMu.Unlock()
+	srv.statusProviders[name] = provider
+}
+
+// AddReadinessCheck registers the dependency which must be ready for the /readyz endpoint to report the broker as ready
+func (srv *Server) AddReadinessCheck(name string, ready func() bool) {
+	srv.statusMu.Lock()
+	defer srv.statusMu.Unlock()
+	srv.readinessChecks[name] = ready
+}
+
+// AddHandler registers the handler served under the given path, the handlers can be added after the server is started.
+// A path ending with a slash registers the handler of all paths with this prefix.
+func (srv *Server) AddHandler(path string, handler http.Handler) {
+	srv.handlersMu.Lock()
+	defer srv.handlersMu.Unlock()
+	srv.handlers[path] = handler
+}
+
 func (srv *Server) ServeAsync() {
 	healthRouter := mux.NewRouter()
 	healthRouter.HandleFunc("/healthz", livenessHandler())
+	healthRouter.HandleFunc("/readyz", srv.readinessHandler)
+	healthRouter.HandleFunc("/status", srv.statusHandler)
+	healthRouter.NotFoundHandler = http.HandlerFunc(srv.registeredHandler)
 	go func() {
-		err := http.ListenAndServe(srv.Address, healthRouter)
+		err := httputil.ListenAndServe(srv.Address, healthRouter, srv.TLS.StatusEnabled, srv.TLS)
 		if err != nil {
 			srv.Log.Errorf("HTTP Health server ListenAndServe: %v", err)
 		}
@@ -37,3 +80,63 @@ func livenessHandler() func(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 }
+
+func (srv *Server) statusHandler(w http.ResponseWriter, _ *http.Request) {
+	srv.statusMu.RLock()
+	status := make(map[string]interface{}, len(srv.statusProviders))
+	for name, provider := range srv.statusProviders {
+		status[name] = provider()
+	}
+	srv.statusMu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		srv.Log.Errorf("while encoding status: %s", err)
+	}
+}
+
+// readinessHandler returns 503 Service Unavailable with the readiness of the registered dependencies
+// if any of them is not ready
+func (srv *Server) readinessHandler(w http.ResponseWriter, _ *http.Request) {
+	srv.statusMu.RLock()
+	readiness := make(map[string]bool, len(srv.readinessChecks))
+	ready := true
+	for name, check := range srv.readinessChecks {
+		readiness[name] = check()
+		ready = ready && readiness[name]
+	}
+	srv.statusMu.RUnlock()
+
+	if ready {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	httputil.WriteResponse(w, http.StatusServiceUnavailable, readiness)
+}
+
+func (srv *Server) registeredHandler(w http.ResponseWriter, r *http.Request) {
+	srv.handlersMu.RLock()
+	handler, found := srv.handlers[r.URL.Path]
+	if !found {
+		handler, found = srv.prefixHandler(r.URL.Path)
+	}
+	srv.handlersMu.RUnlock()
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// prefixHandler returns the handler registered with the longest prefix of the path, the caller must hold the handlers lock
+func (srv *Server) prefixHandler(path string) (http.Handler, bool) {
+	var handler http.Handler
+	longest := 0
+	for prefix, h := range srv.handlers {
+		if strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			handler = h
+			longest = len(prefix)
+		}
+	}
+	return handler, handler != nil
+}
@@ -0,0 +1,5 @@
+package httputil
+
+// CorrelationIDHeader is the header which carries the ID correlating the incoming request
+// with the operation it triggers and with the calls made to other components
+const CorrelationIDHeader = "X-Correlation-ID"
@@ -0,0 +1,89 @@
+package httputil
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSConfig defines the HTTPS settings of the HTTP servers
+type TLSConfig struct {
+	// Enabled turns on HTTPS on the public port
+	Enabled bool `envconfig:"default=false"`
+	// StatusEnabled turns on HTTPS on the status port
+	StatusEnabled bool `envconfig:"default=false"`
+
+	CertFile string `envconfig:"optional"`
+	KeyFile  string `envconfig:"optional"`
+
+	// MinVersion is the lowest accepted TLS version, one of 1.0, 1.1, 1.2 or 1.3
+	MinVersion string `envconfig:"default=1.2"`
+	// CipherSuites lists names of the approved cipher suites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+	// If empty, Go defaults are used. TLS 1.3 cipher suites are not configurable.
+	CipherSuites []string `envconfig:"optional"`
+}
+
+// ServerTLSConfig builds the server TLS configuration enforcing the minimum version and the cipher suites
+func (c TLSConfig) ServerTLSConfig() (*tls.Config, error) {
+	minVersion, found := tlsVersions[c.MinVersion]
+	if !found {
+		return nil, errors.Errorf("unsupported minimum TLS version %q", c.MinVersion)
+	}
+
+	var cipherSuites []uint16
+	if len(c.CipherSuites) > 0 {
+		supported := make(map[string]uint16)
+		for _, suite := range tls.CipherSuites() {
+			supported[suite.Name] = suite.ID
+		}
+		for _, name := range c.CipherSuites {
+			id, found := supported[name]
+			if !found {
+				return nil, errors.Errorf("cipher suite %q is not supported or insecure", name)
+			}
+			cipherSuites = append(cipherSuites, id)
+		}
+	}
+
+	return &tls.Config{
+		MinVersion:               minVersion,
+		CipherSuites:             cipherSuites,
+		PreferServerCipherSuites: true,
+	}, nil
+}
+
+// Validate checks if the configuration is complete for the enabled servers
+func (c TLSConfig) Validate() error {
+	if (c.Enabled || c.StatusEnabled) && (c.CertFile == "" || c.KeyFile == "") {
+		return errors.New("certificate and key files must be provided when HTTPS is enabled")
+	}
+	_, err := c.ServerTLSConfig()
+	return err
+}
+
+// ListenAndServe serves the handler on the given address, over HTTPS if useTLS is set
+func ListenAndServe(addr string, handler http.Handler, useTLS bool, cfg TLSConfig) error {
+	if !useTLS {
+		return http.ListenAndServe(addr, handler)
+	}
+
+	tlsConfig, err := cfg.ServerTLSConfig()
+	if err != nil {
+		return errors.Wrap(err, "while creating server TLS config")
+	}
+	srv := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+
+	return srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+}
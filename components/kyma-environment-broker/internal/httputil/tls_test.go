@@ -0,0 +1,108 @@
+package httputil_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig_MinVersion(t *testing.T) {
+	// given
+	cfg := httputil.TLSConfig{
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}
+	tlsConfig, err := cfg.ServerTLSConfig()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	for name, tc := range map[string]struct {
+		minVersion   uint16
+		maxVersion   uint16
+		cipherSuites []uint16
+		expectErr    bool
+	}{
+		"TLS 1.1 client is rejected": {
+			minVersion: tls.VersionTLS10,
+			maxVersion: tls.VersionTLS11,
+			expectErr:  true,
+		},
+		"TLS 1.2 client with not approved cipher suite is rejected": {
+			maxVersion:   tls.VersionTLS12,
+			cipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			expectErr:    true,
+		},
+		"TLS 1.2 client with approved cipher suite succeeds": {
+			maxVersion:   tls.VersionTLS12,
+			cipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				MinVersion:         tc.minVersion,
+				MaxVersion:         tc.maxVersion,
+				CipherSuites:       tc.cipherSuites,
+			}}}
+
+			// when
+			resp, err := client.Get(server.URL)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestTLSConfig_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg       httputil.TLSConfig
+		expectErr bool
+	}{
+		"disabled": {
+			cfg: httputil.TLSConfig{MinVersion: "1.2"},
+		},
+		"enabled without certificate": {
+			cfg:       httputil.TLSConfig{Enabled: true, MinVersion: "1.2"},
+			expectErr: true,
+		},
+		"unknown version": {
+			cfg:       httputil.TLSConfig{MinVersion: "0.9"},
+			expectErr: true,
+		},
+		"unknown cipher suite": {
+			cfg:       httputil.TLSConfig{MinVersion: "1.2", CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			expectErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := tc.cfg.Validate()
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
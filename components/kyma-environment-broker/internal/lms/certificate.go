@@ -0,0 +1,26 @@
+package lms
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CertificateExpiry returns the end of the validity period of the first certificate in the PEM encoded data
+func CertificateExpiry(cert string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(cert))
+	if block == nil {
+		return time.Time{}, errors.New("certificate is not PEM encoded")
+	}
+	if block.Type != "CERTIFICATE" {
+		return time.Time{}, errors.Errorf("unexpected PEM block type %q", block.Type)
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "while parsing certificate")
+	}
+
+	return parsed.NotAfter, nil
+}
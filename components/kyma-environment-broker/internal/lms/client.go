@@ -11,7 +11,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/iosafety"
 
@@ -61,15 +65,62 @@ type Config struct {
 	SamlTenant string
 	Region     string `envconfig:"optional"`
 	Mandatory  bool   `envconfig:"default=true"`
+	// PlanMandatory overrides Mandatory for the given plans in the <plan>:<true|false> format, for example "trial:false"
+	PlanMandatory []string `envconfig:"optional"`
 
 	EnabledForGlobalAccounts string // "all", "none", or "{global-account-ID-1}, <global-account-ID-2>, .."
+
+	// CertExpiryWindow is the time before the expiry of the runtime certificate in which the instance is flagged for renewal
+	CertExpiryWindow time.Duration `envconfig:"default=720h"`
+	// CertExpiryCheckInterval defines how often the certificates expiry is checked, zero disables the check
+	CertExpiryCheckInterval time.Duration `envconfig:"default=24h"`
 }
 
 func (c Config) Validate() error {
 	if c.ClusterType != ClusterTypeSingleNode && c.ClusterType != ClusterTypeHA {
 		return fmt.Errorf("unknown cluster type '%s'", c.ClusterType)
 	}
-	return nil
+	_, err := c.planMandatory()
+	return err
+}
+
+// IsMandatory returns true if the failing LMS activation breaks the provisioning of the plan,
+// the plans which are not listed in PlanMandatory use the global Mandatory value
+func (c Config) IsMandatory(planID string) bool {
+	planMandatory, err := c.planMandatory()
+	if err != nil {
+		return c.Mandatory
+	}
+	mandatory, found := planMandatory[broker.PlanNamesMapping[planID]]
+	if !found {
+		return c.Mandatory
+	}
+	return mandatory
+}
+
+func (c Config) planMandatory() (map[string]bool, error) {
+	planMandatory := make(map[string]bool, len(c.PlanMandatory))
+	for _, entry := range c.PlanMandatory {
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || !isKnownPlanName(parts[0]) {
+			return nil, fmt.Errorf("invalid plan mandatory entry '%s'", entry)
+		}
+		mandatory, err := strconv.ParseBool(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid mandatory value '%s' of the plan %s", parts[1], parts[0])
+		}
+		planMandatory[parts[0]] = mandatory
+	}
+	return planMandatory, nil
+}
+
+func isKnownPlanName(name string) bool {
+	for _, planName := range broker.PlanNamesMapping {
+		if planName == name {
+			return true
+		}
+	}
+	return false
 }
 
 func NewClient(cfg Config, log logrus.FieldLogger) Client {
@@ -1,13 +1,16 @@
 package lms
 
 import (
-	"time"
-
-	"sync"
-
-	"errors"
-
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -26,6 +29,7 @@ type FakeClient struct {
 
 	timeToReady    time.Duration
 	requestedCerts map[string]struct{}
+	signedCert     string
 }
 
 // NewFakeClient creates lms fake client which response tenant ready after timeToReady duration
@@ -34,9 +38,16 @@ func NewFakeClient(timeToReady time.Duration) *FakeClient {
 		data:           make(map[string]tenantInfo, 0),
 		timeToReady:    timeToReady,
 		requestedCerts: make(map[string]struct{}, 0),
+		signedCert:     FakeSignedCertificate,
 	}
 }
 
+// WithSignedCertificate sets the certificate returned as the signed one instead of FakeSignedCertificate
+func (f *FakeClient) WithSignedCertificate(cert string) *FakeClient {
+	f.signedCert = cert
+	return f
+}
+
 type tenantInfo struct {
 	createdAt time.Time
 }
@@ -84,7 +95,7 @@ func (f *FakeClient) GetCACertificate(tenantID string) (cert string, found bool,
 }
 
 func (f *FakeClient) GetCertificateByURL(url string) (cert string, found bool, err error) {
-	return FakeSignedCertificate, true, nil
+	return f.signedCert, true, nil
 }
 
 func (f *FakeClient) RequestCertificate(tenantID string, subj pkix.Name) (id string, privateKey []byte, err error) {
@@ -101,3 +112,23 @@ func (f *FakeClient) IsCertRequestedForTenant(tenantID string) bool {
 	_, found := f.requestedCerts[tenantID]
 	return found
 }
+
+// NewFakeSignedCertificate generates a PEM encoded self-signed certificate valid until notAfter
+func NewFakeSignedCertificate(notAfter time.Time) (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fluentbit"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
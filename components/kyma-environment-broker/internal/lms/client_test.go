@@ -8,6 +8,7 @@ import (
 
 	"crypto/x509/pkix"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -193,3 +194,31 @@ func createClient(url string) Client {
 		Token:       token,
 	}, logrus.StandardLogger())
 }
+
+func TestConfig_IsMandatory(t *testing.T) {
+	// given
+	cfg := Config{ClusterType: ClusterTypeHA, Mandatory: true, PlanMandatory: []string{"trial:false", "gcp:true"}}
+
+	// then
+	require.NoError(t, cfg.Validate())
+	assert.False(t, cfg.IsMandatory(broker.TrialPlanID))
+	assert.True(t, cfg.IsMandatory(broker.GCPPlanID))
+	assert.True(t, cfg.IsMandatory(broker.AzurePlanID))
+
+	// when
+	cfg.Mandatory = false
+
+	// then
+	assert.False(t, cfg.IsMandatory(broker.AzurePlanID))
+	assert.True(t, cfg.IsMandatory(broker.GCPPlanID))
+}
+
+func TestConfig_ValidatePlanMandatory(t *testing.T) {
+	for _, entry := range []string{"trial", "unknown:false", "trial:maybe"} {
+		// given
+		cfg := Config{ClusterType: ClusterTypeHA, PlanMandatory: []string{entry}}
+
+		// then
+		assert.Error(t, cfg.Validate(), entry)
+	}
+}
@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	// StaticFields is a comma-separated list of fields added to every log, e.g. "landscape=dev,region=eu10,version=1.20.0"
+	StaticFields string `envconfig:"optional"`
+	// FieldNames is a comma-separated list which renames the default fields of the log, e.g. "time=@timestamp,msg=message".
+	// The default fields are time, level, msg, func and file.
+	FieldNames string `envconfig:"optional"`
+	// InfoSampleRate is the fraction of the info and debug logs which are written, 1 writes all of them.
+	// The warnings and errors are never sampled.
+	InfoSampleRate float64 `envconfig:"default=1"`
+	// OperationStream streams the log lines of the provisioning operations to the destination until the operation finishes
+	OperationStream OperationStreamConfig
+}
+
+// Formatter formats the logs as JSON with the static fields of the config and drops the info and debug logs
+// above the sample rate
+type Formatter struct {
+	json         *logrus.JSONFormatter
+	staticFields logrus.Fields
+	sampleRate   float64
+
+	mu      sync.Mutex
+	sampled uint64
+}
+
+func NewFormatter(cfg Config) (*Formatter, error) {
+	if cfg.InfoSampleRate <= 0 || cfg.InfoSampleRate > 1 {
+		return nil, errors.Errorf("info sample rate must be greater than 0 and not greater than 1, got %v", cfg.InfoSampleRate)
+	}
+	staticFields, err := parsePairs(cfg.StaticFields)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing static fields")
+	}
+	names, err := parsePairs(cfg.FieldNames)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing field names")
+	}
+	fieldMap := logrus.FieldMap{}
+	for name, newName := range names {
+		// the type of the FieldMap keys is not exported, the keys can be set only with the logrus constants
+		switch name {
+		case "time":
+			fieldMap[logrus.FieldKeyTime] = newName.(string)
+		case "level":
+			fieldMap[logrus.FieldKeyLevel] = newName.(string)
+		case "msg":
+			fieldMap[logrus.FieldKeyMsg] = newName.(string)
+		case "func":
+			fieldMap[logrus.FieldKeyFunc] = newName.(string)
+		case "file":
+			fieldMap[logrus.FieldKeyFile] = newName.(string)
+		default:
+			return nil, errors.Errorf("field %q is not a default field of the log", name)
+		}
+	}
+
+	return &Formatter{
+		json:         &logrus.JSONFormatter{FieldMap: fieldMap},
+		staticFields: staticFields,
+		sampleRate:   cfg.InfoSampleRate,
+	}, nil
+}
+
+func parsePairs(in string) (logrus.Fields, error) {
+	fields := logrus.Fields{}
+	if strings.TrimSpace(in) == "" {
+		return fields, nil
+	}
+	for _, pair := range strings.Split(in, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("%q must have the form <name>=<value>", pair)
+		}
+		fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return fields, nil
+}
+
+// Format implements the logrus.Formatter interface, the dropped log is formatted to no bytes
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level >= logrus.InfoLevel && !f.sample() {
+		return nil, nil
+	}
+	if len(f.staticFields) == 0 {
+		return f.json.Format(entry)
+	}
+
+	// the fields of the entry are shared with the logger entry it was created from, they must not be modified
+	fields := make(logrus.Fields, len(entry.Data)+len(f.staticFields))
+	for k, v := range f.staticFields {
+		fields[k] = v
+	}
+	for k, v := range entry.Data {
+		fields[k] = v
+	}
+	withStatic := *entry
+	withStatic.Data = fields
+	return f.json.Format(&withStatic)
+}
+
+// sample spreads the written logs evenly, i.e. with the rate 0.25 every fourth log is written
+func (f *Formatter) sample() bool {
+	if f.sampleRate >= 1 {
+		return true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := f.sampled
+	f.sampled++
+	return uint64(float64(n+1)*f.sampleRate) > uint64(float64(n)*f.sampleRate)
+}
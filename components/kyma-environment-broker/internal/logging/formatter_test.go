@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatter(t *testing.T) {
+	t.Run("should add static fields and rename default fields", func(t *testing.T) {
+		// given
+		formatter, err := NewFormatter(Config{
+			StaticFields:   "landscape=dev, region=eu10,version=1.20.0",
+			FieldNames:     "time=@timestamp,msg=message",
+			InfoSampleRate: 1,
+		})
+		require.NoError(t, err)
+		log, buffer := fixLogger(formatter)
+
+		// when
+		log.WithField("region", "overridden").WithField("instanceID", "inst-id").Info("provisioning started")
+
+		// then
+		lines := parseLines(t, buffer)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "dev", lines[0]["landscape"])
+		assert.Equal(t, "overridden", lines[0]["region"])
+		assert.Equal(t, "1.20.0", lines[0]["version"])
+		assert.Equal(t, "inst-id", lines[0]["instanceID"])
+		assert.Equal(t, "provisioning started", lines[0]["message"])
+		assert.Contains(t, lines[0], "@timestamp")
+		assert.NotContains(t, lines[0], "msg")
+	})
+
+	t.Run("should sample info logs and never sample warnings and errors", func(t *testing.T) {
+		// given
+		formatter, err := NewFormatter(Config{InfoSampleRate: 0.25})
+		require.NoError(t, err)
+		log, buffer := fixLogger(formatter)
+
+		// when
+		for i := 0; i < 100; i++ {
+			log.Info("info")
+			log.Warn("warning")
+			log.Error("error")
+		}
+
+		// then
+		counts := map[string]int{}
+		for _, line := range parseLines(t, buffer) {
+			counts[line["level"].(string)]++
+		}
+		assert.Equal(t, 25, counts["info"])
+		assert.Equal(t, 100, counts["warning"])
+		assert.Equal(t, 100, counts["error"])
+	})
+
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		for name, cfg := range map[string]Config{
+			"zero sample rate":       {InfoSampleRate: 0},
+			"sample rate above one":  {InfoSampleRate: 1.5},
+			"static field w/o value": {StaticFields: "landscape", InfoSampleRate: 1},
+			"unknown field name":     {FieldNames: "message=msg", InfoSampleRate: 1},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// when
+				_, err := NewFormatter(cfg)
+
+				// then
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func fixLogger(formatter logrus.Formatter) (*logrus.Logger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+	log := logrus.New()
+	log.SetOutput(buffer)
+	log.SetFormatter(formatter)
+	return log, buffer
+}
+
+func parseLines(t *testing.T, buffer *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(buffer)
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,256 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const operationIDField = "operationID"
+
+// finishedRetention is how long the finished operations are remembered, the lines logged for them are not streamed
+const finishedRetention = time.Hour
+
+type OperationStreamConfig struct {
+	// Destination is the URL of the sink the log lines of the operations are streamed to. The lines are POSTed
+	// to <destination>/<operationID> for the http and https URLs and appended to the <path>/<operationID>.log file
+	// for the file URLs. Empty destination disables the streaming.
+	Destination string `envconfig:"optional"`
+	// BufferSize limits the number of the lines waiting for the sink, the lines above the limit are dropped
+	BufferSize    int           `envconfig:"default=1000"`
+	FlushInterval time.Duration `envconfig:"default=5s"`
+	Timeout       time.Duration `envconfig:"default=10s"`
+}
+
+// OperationSink receives the log lines of the operation
+type OperationSink interface {
+	Write(ctx context.Context, operationID string, lines [][]byte) error
+}
+
+// NewOperationSink returns the sink of the destination URL
+func NewOperationSink(destination string) (OperationSink, error) {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing operation logs destination %q", destination)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &httpSink{url: destination, client: http.DefaultClient}, nil
+	case "file":
+		return &fileSink{dir: u.Path}, nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q of operation logs destination, supported schemes: http, https, file", u.Scheme)
+	}
+}
+
+type streamedLine struct {
+	operationID string
+	line        []byte
+	// finished marks the end of the operation, the lines of the operation are flushed
+	finished bool
+}
+
+// OperationStreamer is the logrus hook which streams the log lines of the operations to the sink for the duration
+// of the operation. The lines are buffered up to the configured size and sent in batches, the lines which do not fit
+// into the buffer and the batches rejected by the sink are dropped.
+type OperationStreamer struct {
+	sink      OperationSink
+	formatter logrus.Formatter
+	cfg       OperationStreamConfig
+	lines     chan streamedLine
+	log       logrus.FieldLogger
+
+	mu       sync.Mutex
+	finished map[string]time.Time
+	dropped  int
+
+	now func() time.Time
+}
+
+func NewOperationStreamer(cfg OperationStreamConfig, sink OperationSink, log logrus.FieldLogger) *OperationStreamer {
+	return &OperationStreamer{
+		sink:      sink,
+		formatter: &logrus.JSONFormatter{},
+		cfg:       cfg,
+		lines:     make(chan streamedLine, cfg.BufferSize),
+		log:       log.WithField("service", "OperationStreamer"),
+		finished:  map[string]time.Time{},
+		now:       time.Now,
+	}
+}
+
+// Logger returns the copy of the logger which streams the operation log lines, the lines logged with the returned
+// logger are written to the output of the given logger as well
+func (s *OperationStreamer) Logger(base *logrus.Logger) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(base.Out)
+	logger.SetFormatter(base.Formatter)
+	logger.SetLevel(base.GetLevel())
+	for _, hooks := range base.Hooks {
+		for _, hook := range hooks {
+			logger.AddHook(hook)
+		}
+	}
+	logger.AddHook(s)
+
+	return logger
+}
+
+// Levels implements the logrus.Hook interface
+func (s *OperationStreamer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus.Hook interface, it never blocks the logging
+func (s *OperationStreamer) Fire(entry *logrus.Entry) error {
+	operationID, ok := entry.Data[operationIDField].(string)
+	if !ok || operationID == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.finished[operationID]; found {
+		return nil
+	}
+	line, err := s.formatter.Format(entry)
+	if err != nil {
+		return nil
+	}
+	select {
+	case s.lines <- streamedLine{operationID: operationID, line: line}:
+	default:
+		s.dropped++
+	}
+	return nil
+}
+
+// Finish flushes the lines of the operation and stops streaming them
+func (s *OperationStreamer) Finish(operationID string) {
+	s.mu.Lock()
+	if _, found := s.finished[operationID]; found {
+		s.mu.Unlock()
+		return
+	}
+	s.finished[operationID] = s.now()
+	s.mu.Unlock()
+
+	s.lines <- streamedLine{operationID: operationID, finished: true}
+}
+
+// Run sends the buffered lines to the sink until the context is done
+func (s *OperationStreamer) Run(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.FlushInterval)
+		defer ticker.Stop()
+
+		pending := map[string][][]byte{}
+		buffered := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case l := <-s.lines:
+				if l.finished {
+					buffered -= len(pending[l.operationID])
+					s.flush(ctx, l.operationID, pending[l.operationID])
+					delete(pending, l.operationID)
+					continue
+				}
+				pending[l.operationID] = append(pending[l.operationID], l.line)
+				buffered++
+				if buffered >= s.cfg.BufferSize {
+					s.flushAll(ctx, pending)
+					buffered = 0
+				}
+			case <-ticker.C:
+				s.flushAll(ctx, pending)
+				buffered = 0
+				s.forgetFinished()
+			}
+		}
+	}()
+}
+
+func (s *OperationStreamer) flushAll(ctx context.Context, pending map[string][][]byte) {
+	for operationID, lines := range pending {
+		s.flush(ctx, operationID, lines)
+		delete(pending, operationID)
+	}
+}
+
+func (s *OperationStreamer) flush(ctx context.Context, operationID string, lines [][]byte) {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+	if dropped > 0 {
+		s.log.Warnf("Dropped %d operation log lines, the buffer was full", dropped)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	if err := s.sink.Write(ctx, operationID, lines); err != nil {
+		s.log.Warnf("Unable to stream %d log lines of the operation %s: %s", len(lines), operationID, err)
+	}
+}
+
+func (s *OperationStreamer) forgetFinished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for operationID, finishedAt := range s.finished {
+		if s.now().Sub(finishedAt) > finishedRetention {
+			delete(s.finished, operationID)
+		}
+	}
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSink) Write(ctx context.Context, operationID string, lines [][]byte) error {
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", s.url, operationID), bytes.NewReader(bytes.Join(lines, nil)))
+	if err != nil {
+		return errors.Wrap(err, "while creating request")
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "while sending lines")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("sink returned %d status code", resp.StatusCode)
+	}
+	return nil
+}
+
+type fileSink struct {
+	dir string
+}
+
+func (s *fileSink) Write(_ context.Context, operationID string, lines [][]byte) error {
+	file, err := os.OpenFile(filepath.Join(s.dir, filepath.Base(operationID)+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "while opening file")
+	}
+	defer file.Close()
+	if _, err := file.Write(bytes.Join(lines, nil)); err != nil {
+		return errors.Wrap(err, "while writing lines")
+	}
+	return nil
+}
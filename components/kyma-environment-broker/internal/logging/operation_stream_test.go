@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestOperationStreamer(t *testing.T) {
+	t.Run("should stream the lines of the operation until it finishes", func(t *testing.T) {
+		// given
+		sink := newTestSink()
+		server := httptest.NewServer(sink)
+		defer server.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		streamer, log, output := fixStreamer(t, server.URL, OperationStreamConfig{BufferSize: 10, FlushInterval: time.Hour, Timeout: time.Second})
+		streamer.Run(ctx)
+
+		// when
+		log.WithField("operationID", "op-1").Info("step started")
+		log.WithField("operationID", "op-2").Info("other operation")
+		log.Info("not operation scoped")
+		log.WithField("operationID", "op-1").Warn("step finished")
+		streamer.Finish("op-1")
+		log.WithField("operationID", "op-1").Info("logged after the operation finished")
+
+		// then
+		err := wait.PollImmediate(10*time.Millisecond, 2*time.Second, func() (bool, error) {
+			return len(sink.messages("op-1")) == 2, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"step started", "step finished"}, sink.messages("op-1"))
+		assert.Empty(t, sink.messages("op-2"))
+		assert.Equal(t, 5, strings.Count(output.String(), "\n"))
+	})
+
+	t.Run("should flush the lines when the buffer is full and tolerate sink failures", func(t *testing.T) {
+		// given
+		sink := newTestSink()
+		sink.failures = 1
+		server := httptest.NewServer(sink)
+		defer server.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		streamer, log, _ := fixStreamer(t, server.URL, OperationStreamConfig{BufferSize: 2, FlushInterval: time.Hour, Timeout: time.Second})
+		streamer.Run(ctx)
+
+		// when
+		for _, msg := range []string{"rejected 1", "rejected 2", "delivered 1", "delivered 2"} {
+			log.WithField("operationID", "op-1").Info(msg)
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// then
+		err := wait.PollImmediate(10*time.Millisecond, 2*time.Second, func() (bool, error) {
+			return len(sink.messages("op-1")) == 2, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"delivered 1", "delivered 2"}, sink.messages("op-1"))
+	})
+
+	t.Run("should drop the lines which do not fit into the buffer", func(t *testing.T) {
+		// given
+		streamer, log, output := fixStreamer(t, "http://sink.local", OperationStreamConfig{BufferSize: 1, FlushInterval: time.Hour, Timeout: time.Second})
+
+		// when the streamer is not running
+		log.WithField("operationID", "op-1").Info("buffered")
+		log.WithField("operationID", "op-1").Info("dropped")
+
+		// then
+		assert.Len(t, streamer.lines, 1)
+		assert.Equal(t, 1, streamer.dropped)
+		assert.Equal(t, 2, strings.Count(output.String(), "\n"))
+	})
+}
+
+func TestNewOperationSink(t *testing.T) {
+	for destination, valid := range map[string]bool{
+		"https://logs.local/operations": true,
+		"http://logs.local":             true,
+		"file:///var/log/operations":    true,
+		"s3://bucket/operations":        false,
+		"::":                            false,
+	} {
+		_, err := NewOperationSink(destination)
+		assert.Equal(t, valid, err == nil, destination)
+	}
+}
+
+func fixStreamer(t *testing.T, destination string, cfg OperationStreamConfig) (*OperationStreamer, *logrus.Logger, *bytes.Buffer) {
+	t.Helper()
+	sink, err := NewOperationSink(destination)
+	require.NoError(t, err)
+	cfg.Destination = destination
+
+	base := logrus.New()
+	output := &bytes.Buffer{}
+	base.SetOutput(output)
+	streamer := NewOperationStreamer(cfg, sink, logrus.New())
+
+	return streamer, streamer.Logger(base), output
+}
+
+type testSink struct {
+	mu       sync.Mutex
+	failures int
+	lines    map[string][]string
+}
+
+func newTestSink() *testSink {
+	return &testSink{lines: map[string][]string{}}
+}
+
+func (s *testSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	operationID := strings.TrimPrefix(r.URL.Path, "/")
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.lines[operationID] = append(s.lines[operationID], line["msg"].(string))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *testSink) messages(operationID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.lines[operationID]...)
+}
@@ -0,0 +1,45 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// ModeDTO is the state of the maintenance mode
+type ModeDTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+type handler struct {
+	mode *Mode
+}
+
+// NewHandler exposes the GET and PUT /admin/maintenance endpoints to check and toggle the maintenance mode
+func NewHandler(mode *Mode) http.Handler {
+	h := &handler{mode: mode}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/maintenance", h.get).Methods(http.MethodGet)
+	router.HandleFunc("/admin/maintenance", h.set).Methods(http.MethodPut)
+
+	return router
+}
+
+func (h *handler) get(w http.ResponseWriter, _ *http.Request) {
+	httputil.WriteResponse(w, http.StatusOK, ModeDTO{Enabled: h.mode.Enabled()})
+}
+
+func (h *handler) set(w http.ResponseWriter, r *http.Request) {
+	var req ModeDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while decoding request body"))
+		return
+	}
+	h.mode.Set(req.Enabled)
+
+	httputil.WriteResponse(w, http.StatusOK, ModeDTO{Enabled: h.mode.Enabled()})
+}
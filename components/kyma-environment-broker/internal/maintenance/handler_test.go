@@ -0,0 +1,50 @@
+package maintenance_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/maintenance"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	// given
+	mode := maintenance.NewMode(maintenance.Config{}, logrus.New())
+	handler := maintenance.NewHandler(mode)
+
+	// when
+	req, err := http.NewRequest(http.MethodPut, "/admin/maintenance", strings.NewReader(`{"enabled": true}`))
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	// then
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, mode.Enabled())
+
+	// when
+	resp = serve(t, handler, http.MethodGet, "/admin/maintenance")
+
+	// then
+	require.Equal(t, http.StatusOK, resp.Code)
+	var dto maintenance.ModeDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dto))
+	assert.True(t, dto.Enabled)
+
+	// when
+	req, err = http.NewRequest(http.MethodPut, "/admin/maintenance", strings.NewReader(`{"enabled"`))
+	require.NoError(t, err)
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.True(t, mode.Enabled())
+}
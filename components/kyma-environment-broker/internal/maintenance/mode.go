@@ -0,0 +1,74 @@
+package maintenance
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+	"github.com/sirupsen/logrus"
+)
+
+const unavailableMessage = "The broker is in maintenance mode, only read requests are served. Retry the request later."
+
+type Config struct {
+	// Enabled starts the broker in the maintenance mode, the mode can be also toggled with the /admin/maintenance endpoint
+	Enabled bool `envconfig:"default=false"`
+	// RetryAfter is the delay sent in the Retry-After header of the rejected requests
+	RetryAfter time.Duration `envconfig:"default=5m"`
+}
+
+// Mode is the read-only maintenance mode of the broker. In the maintenance mode the OSB API calls
+// which create, update or delete instances and bindings are rejected with 503 Service Unavailable,
+// while the catalog, the instances and the last operations are still served. The operations in progress are processed further.
+type Mode struct {
+	enabled    int32
+	retryAfter time.Duration
+	log        logrus.FieldLogger
+}
+
+func NewMode(cfg Config, log logrus.FieldLogger) *Mode {
+	m := &Mode{
+		retryAfter: cfg.RetryAfter,
+		log:        log.WithField("service", "MaintenanceMode"),
+	}
+	m.Set(cfg.Enabled)
+	return m
+}
+
+// Enabled returns true if the broker is in the maintenance mode
+func (m *Mode) Enabled() bool {
+	return atomic.LoadInt32(&m.enabled) == 1
+}
+
+// Set enables or disables the maintenance mode
+func (m *Mode) Set(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	if atomic.SwapInt32(&m.enabled, value) != value {
+		m.log.Infof("Maintenance mode enabled: %t", enabled)
+	}
+}
+
+// RejectMutatingRequests is the middleware rejecting the OSB API calls modifying instances or bindings in the maintenance mode,
+// the GET requests are always passed
+func (m *Mode) RejectMutatingRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !m.Enabled() || req.Method == http.MethodGet || req.Method == http.MethodHead {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		m.log.Infof("Rejecting %s %s request in maintenance mode", req.Method, req.URL.Path)
+		if m.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(m.retryAfter.Seconds()))))
+		}
+		httputil.WriteResponse(w, http.StatusServiceUnavailable, apiresponses.ErrorResponse{Description: unavailableMessage})
+	})
+}
@@ -0,0 +1,100 @@
+package maintenance_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/maintenance"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var osbRequests = map[string]struct {
+	method   string
+	path     string
+	mutating bool
+}{
+	"catalog":            {method: http.MethodGet, path: "/oauth/v2/catalog"},
+	"get instance":       {method: http.MethodGet, path: "/oauth/v2/service_instances/inst-id"},
+	"last operation":     {method: http.MethodGet, path: "/oauth/v2/service_instances/inst-id/last_operation"},
+	"get binding":        {method: http.MethodGet, path: "/oauth/v2/service_instances/inst-id/service_bindings/bind-id"},
+	"provision":          {method: http.MethodPut, path: "/oauth/v2/service_instances/inst-id", mutating: true},
+	"update":             {method: http.MethodPatch, path: "/oauth/v2/service_instances/inst-id", mutating: true},
+	"deprovision":        {method: http.MethodDelete, path: "/oauth/v2/service_instances/inst-id", mutating: true},
+	"bind":               {method: http.MethodPut, path: "/oauth/v2/service_instances/inst-id/service_bindings/bind-id", mutating: true},
+	"unbind":             {method: http.MethodDelete, path: "/oauth/v2/service_instances/inst-id/service_bindings/bind-id", mutating: true},
+	"region provision":   {method: http.MethodPut, path: "/oauth/cf-eu10/v2/service_instances/inst-id", mutating: true},
+	"region get catalog": {method: http.MethodGet, path: "/oauth/cf-eu10/v2/catalog"},
+}
+
+func TestMode_RejectMutatingRequests(t *testing.T) {
+	t.Run("should reject mutating requests and serve reads in maintenance mode", func(t *testing.T) {
+		// given
+		mode := maintenance.NewMode(maintenance.Config{Enabled: true, RetryAfter: 90 * time.Second}, logrus.New())
+		router := fixRouter(mode)
+
+		for name, tc := range osbRequests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				resp := serve(t, router, tc.method, tc.path)
+
+				// then
+				if !tc.mutating {
+					assert.Equal(t, http.StatusOK, resp.Code)
+					assert.Empty(t, resp.Header().Get("Retry-After"))
+					return
+				}
+				assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+				assert.Equal(t, "90", resp.Header().Get("Retry-After"))
+				var body struct {
+					Description string `json:"description"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+				assert.Contains(t, body.Description, "maintenance mode")
+			})
+		}
+	})
+
+	t.Run("should serve all requests when maintenance mode is disabled", func(t *testing.T) {
+		// given
+		mode := maintenance.NewMode(maintenance.Config{Enabled: true, RetryAfter: time.Minute}, logrus.New())
+		mode.Set(false)
+		router := fixRouter(mode)
+
+		for name, tc := range osbRequests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				resp := serve(t, router, tc.method, tc.path)
+
+				// then
+				assert.Equal(t, http.StatusOK, resp.Code)
+			})
+		}
+	})
+}
+
+func fixRouter(mode *maintenance.Mode) *mux.Router {
+	router := mux.NewRouter()
+	for _, prefix := range []string{"/oauth/", "/oauth/{region}/"} {
+		route := router.PathPrefix(prefix).Subrouter()
+		route.Use(mode.RejectMutatingRequests)
+		route.PathPrefix("/v2/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+	return router
+}
+
+func serve(t *testing.T, handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
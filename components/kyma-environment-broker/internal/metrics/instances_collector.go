@@ -1,9 +1,16 @@
 package metrics
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/sirupsen/logrus"
 )
 
 // InstancesStatsGetter provides number of all instances failed, succeeded or orphaned
@@ -12,13 +19,25 @@ import (
 // - compass_keb_global_account_id_instances_total - total number of all instances per global account
 type InstancesStatsGetter interface {
 	GetInstanceStats() (internal.InstanceStats, error)
+	GetByID(instanceID string) (*internal.Instance, error)
 }
 
+// InstancesCollector keeps the instances counts in memory. The counts are loaded from the storage
+// on reconciliation and updated from the step processed events in between, so a scrape does not query the storage.
 type InstancesCollector struct {
 	statsGetter InstancesStatsGetter
 
 	instancesDesc        *prometheus.Desc
 	instancesPerGAIDDesc *prometheus.Desc
+
+	mu    sync.Mutex
+	stats internal.InstanceStats
+	// reconciledAt is the time of the last reconciliation, instances created later are not included in the loaded stats
+	reconciledAt time.Time
+	// checked holds instances which provisioning was already processed after the last reconciliation
+	checked map[string]struct{}
+	// removed holds instances which were removed after the last reconciliation
+	removed map[string]struct{}
 }
 
 func NewInstancesCollector(statsGetter InstancesStatsGetter) *InstancesCollector {
@@ -35,6 +54,10 @@ func NewInstancesCollector(statsGetter InstancesStatsGetter) *InstancesCollector
 			"The total number of instances by Global Account ID",
 			[]string{"global_account_id"},
 			nil),
+
+		stats:   internal.InstanceStats{PerGlobalAccountID: make(map[string]int)},
+		checked: make(map[string]struct{}),
+		removed: make(map[string]struct{}),
 	}
 }
 
@@ -45,14 +68,99 @@ func (c *InstancesCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect implements the prometheus.Collector interface.
 func (c *InstancesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	collect(ch, c.instancesDesc, c.stats.TotalNumberOfInstances)
+
+	for globalAccountID, num := range c.stats.PerGlobalAccountID {
+		collect(ch, c.instancesPerGAIDDesc, num, globalAccountID)
+	}
+}
+
+// Reconcile replaces the instances counts with the ones loaded from the storage
+func (c *InstancesCollector) Reconcile() error {
+	reconciledAt := time.Now()
 	stats, err := c.statsGetter.GetInstanceStats()
 	if err != nil {
-		logrus.Error(err)
-		return
+		return errors.Wrap(err, "while getting instance stats")
+	}
+	if stats.PerGlobalAccountID == nil {
+		stats.PerGlobalAccountID = make(map[string]int)
 	}
-	collect(ch, c.instancesDesc, stats.TotalNumberOfInstances)
 
-	for globalAccountID, num := range stats.PerGlobalAccountID {
-		collect(ch, c.instancesPerGAIDDesc, num, globalAccountID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+	c.reconciledAt = reconciledAt
+	c.checked = make(map[string]struct{})
+	c.removed = make(map[string]struct{})
+
+	return nil
+}
+
+// OnProvisioningStepProcessed counts the instance if it was created after the last reconciliation.
+// Provisioning operations are also created on unsuspension, the instance creation time is checked to not count such instances twice.
+func (c *InstancesCollector) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.ProvisioningStepProcessed but got %+v", ev)
+	}
+	op := stepProcessed.Operation
+
+	c.mu.Lock()
+	reconciledAt := c.reconciledAt
+	_, checked := c.checked[op.InstanceID]
+	c.mu.Unlock()
+	if checked || !op.CreatedAt.After(reconciledAt) {
+		return nil
 	}
+
+	instance, err := c.statsGetter.GetByID(op.InstanceID)
+	if err != nil {
+		return errors.Wrapf(err, "while getting instance %s", op.InstanceID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the collector could be reconciled or the event handled concurrently in the meantime
+	if _, checked := c.checked[op.InstanceID]; checked || reconciledAt != c.reconciledAt {
+		return nil
+	}
+	c.checked[op.InstanceID] = struct{}{}
+	if instance.CreatedAt.After(c.reconciledAt) {
+		c.stats.TotalNumberOfInstances++
+		c.stats.PerGlobalAccountID[instance.GlobalAccountID]++
+	}
+
+	return nil
+}
+
+// OnDeprovisioningStepProcessed uncounts the instance removed by the succeeded deprovisioning
+func (c *InstancesCollector) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.DeprovisioningStepProcessed but got %+v", ev)
+	}
+	op := stepProcessed.Operation
+	if op.Temporary || op.State != domain.Succeeded || stepProcessed.OldOperation.State == domain.Succeeded {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, removed := c.removed[op.InstanceID]; removed {
+		return nil
+	}
+	c.removed[op.InstanceID] = struct{}{}
+	if c.stats.TotalNumberOfInstances > 0 {
+		c.stats.TotalNumberOfInstances--
+	}
+	globalAccountID := op.ProvisioningParameters.ErsContext.GlobalAccountID
+	if c.stats.PerGlobalAccountID[globalAccountID] > 1 {
+		c.stats.PerGlobalAccountID[globalAccountID]--
+	} else {
+		delete(c.stats.PerGlobalAccountID, globalAccountID)
+	}
+
+	return nil
 }
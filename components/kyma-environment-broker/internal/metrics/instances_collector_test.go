@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesCollector_CountsUpdatedOnEvents(t *testing.T) {
+	// given
+	getter := &fakeInstancesStatsGetter{
+		stats: internal.InstanceStats{
+			TotalNumberOfInstances: 2,
+			PerGlobalAccountID:     map[string]int{"ga-1": 1, "ga-2": 1},
+		},
+		instances: map[string]internal.Instance{
+			"suspended": {InstanceID: "suspended", GlobalAccountID: "ga-1", CreatedAt: time.Now().Add(-time.Hour)},
+			"removed":   {InstanceID: "removed", GlobalAccountID: "ga-2", CreatedAt: time.Now().Add(-time.Hour)},
+		},
+	}
+	collector := NewInstancesCollector(getter)
+	require.NoError(t, collector.Reconcile())
+	getter.instances["created"] = internal.Instance{InstanceID: "created", GlobalAccountID: "ga-1", CreatedAt: time.Now()}
+
+	// when
+	for _, id := range []string{"created", "created", "suspended"} {
+		err := collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+			Operation: internal.ProvisioningOperation{Operation: fixOperation(id, broker.AzurePlanID, domain.InProgress, time.Now())},
+		})
+		require.NoError(t, err)
+	}
+	suspension := fixOperation("suspended", broker.AzurePlanID, domain.InProgress, time.Now())
+	deprovisioning := fixOperation("removed", broker.AzurePlanID, domain.InProgress, time.Now())
+	deprovisioning.ProvisioningParameters.ErsContext.GlobalAccountID = "ga-2"
+	for _, ev := range []process.DeprovisioningStepProcessed{
+		{
+			OldOperation: internal.DeprovisioningOperation{Operation: suspension, Temporary: true},
+			Operation:    internal.DeprovisioningOperation{Operation: withState(suspension, domain.Succeeded), Temporary: true},
+		},
+		{
+			OldOperation: internal.DeprovisioningOperation{Operation: deprovisioning},
+			Operation:    internal.DeprovisioningOperation{Operation: withState(deprovisioning, domain.Succeeded)},
+		},
+		{
+			OldOperation: internal.DeprovisioningOperation{Operation: withState(deprovisioning, domain.Succeeded)},
+			Operation:    internal.DeprovisioningOperation{Operation: withState(deprovisioning, domain.Succeeded)},
+		},
+	} {
+		require.NoError(t, collector.OnDeprovisioningStepProcessed(context.TODO(), ev))
+	}
+
+	// then
+	assert.Equal(t, 2, collector.stats.TotalNumberOfInstances)
+	assert.Equal(t, map[string]int{"ga-1": 2}, collector.stats.PerGlobalAccountID)
+	assert.Equal(t, 1, getter.statsCalls)
+	assert.Equal(t, 2, getter.getCalls)
+}
+
+func TestInstancesCollector_Reconcile(t *testing.T) {
+	// given
+	getter := &fakeInstancesStatsGetter{
+		stats:     internal.InstanceStats{},
+		instances: map[string]internal.Instance{},
+	}
+	collector := NewInstancesCollector(getter)
+	require.NoError(t, collector.Reconcile())
+	getter.instances["created"] = internal.Instance{InstanceID: "created", GlobalAccountID: "ga-1", CreatedAt: time.Now()}
+	err := collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		Operation: internal.ProvisioningOperation{Operation: fixOperation("created", broker.AzurePlanID, domain.InProgress, time.Now())},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, collector.stats.TotalNumberOfInstances)
+
+	getter.stats = internal.InstanceStats{
+		TotalNumberOfInstances: 3,
+		PerGlobalAccountID:     map[string]int{"ga-1": 2, "ga-3": 1},
+	}
+
+	// when
+	err = collector.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, collector.stats.TotalNumberOfInstances)
+	assert.Equal(t, map[string]int{"ga-1": 2, "ga-3": 1}, collector.stats.PerGlobalAccountID)
+	assert.Empty(t, collector.checked)
+	assert.Empty(t, collector.removed)
+}
+
+type fakeInstancesStatsGetter struct {
+	stats      internal.InstanceStats
+	instances  map[string]internal.Instance
+	statsCalls int
+	getCalls   int
+}
+
+func (f *fakeInstancesStatsGetter) GetInstanceStats() (internal.InstanceStats, error) {
+	f.statsCalls++
+	perGlobalAccountID := make(map[string]int, len(f.stats.PerGlobalAccountID))
+	for id, count := range f.stats.PerGlobalAccountID {
+		perGlobalAccountID[id] = count
+	}
+	return internal.InstanceStats{
+		TotalNumberOfInstances: f.stats.TotalNumberOfInstances,
+		PerGlobalAccountID:     perGlobalAccountID,
+	}, nil
+}
+
+func (f *fakeInstancesStatsGetter) GetByID(instanceID string) (*internal.Instance, error) {
+	f.getCalls++
+	instance, found := f.instances[instanceID]
+	if !found {
+		return nil, dberr.NotFound("instance %s not found", instanceID)
+	}
+	return &instance, nil
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InstanceStatesGetter provides the states of instances for the metric:
+// - compass_keb_instances_per_plan_total - number of instances per plan, region and state
+type InstanceStatesGetter interface {
+	GetInstanceStates() ([]internal.InstanceState, error)
+	GetByID(instanceID string) (*internal.Instance, error)
+}
+
+const (
+	instanceStateProvisioning   = "provisioning"
+	instanceStateSucceeded      = "succeeded"
+	instanceStateFailed         = "failed"
+	instanceStateDeprovisioning = "deprovisioning"
+	instanceStateSuspended      = "suspended"
+)
+
+type instanceLabels struct {
+	plan   string
+	region string
+	state  string
+}
+
+type trackedInstance struct {
+	instanceLabels
+	// updatedAt is the update time of the operation of the event which updated the instance,
+	// it is zero for instances loaded from the storage
+	updatedAt time.Time
+	// removed marks the instance removed by the deprovisioning, so the events processed late do not add it again
+	removed bool
+	// removedAt is the time when the removal was processed, the removed instance is kept until the next reconciliation
+	removedAt time.Time
+}
+
+// outdatedBy returns true if the operation is older than the one which updated the instance,
+// the instance removed by the operation is not updated by the events of the same time either
+func (i trackedInstance) outdatedBy(op internal.Operation) bool {
+	if i.removed {
+		return !op.UpdatedAt.After(i.updatedAt)
+	}
+	return op.UpdatedAt.Before(i.updatedAt)
+}
+
+// InstancesPerPlanCollector keeps the plan, the region and the state of every instance in memory. The instances are loaded
+// from the storage on reconciliation and updated from the step processed events in between, so a scrape does not query the storage.
+type InstancesPerPlanCollector struct {
+	statesGetter InstanceStatesGetter
+
+	instancesDesc *prometheus.Desc
+
+	mu           sync.Mutex
+	instances    map[string]trackedInstance
+	reconciledAt time.Time
+}
+
+func NewInstancesPerPlanCollector(statesGetter InstanceStatesGetter) *InstancesPerPlanCollector {
+	return &InstancesPerPlanCollector{
+		statesGetter: statesGetter,
+
+		instancesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "instances_per_plan_total"),
+			"The number of instances by plan, region and state",
+			[]string{"plan", "region", "state"},
+			nil),
+
+		instances: make(map[string]trackedInstance),
+	}
+}
+
+func (c *InstancesPerPlanCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.instancesDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *InstancesPerPlanCollector) Collect(ch chan<- prometheus.Metric) {
+	for labels, num := range c.counts() {
+		collect(ch, c.instancesDesc, num, labels.plan, labels.region, labels.state)
+	}
+}
+
+func (c *InstancesPerPlanCollector) counts() map[instanceLabels]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[instanceLabels]int)
+	for _, instance := range c.instances {
+		if !instance.removed {
+			counts[instance.instanceLabels]++
+		}
+	}
+	return counts
+}
+
+// Reconcile replaces the instances with the ones loaded from the storage, instances updated from the events
+// during the loading are kept. The removed instances are kept until the next reconciliation, because the events
+// of their operations can still be processed.
+func (c *InstancesPerPlanCollector) Reconcile() error {
+	reconciledAt := time.Now()
+	states, err := c.statesGetter.GetInstanceStates()
+	if err != nil {
+		return errors.Wrap(err, "while getting instance states")
+	}
+	instances := make(map[string]trackedInstance, len(states))
+	for _, s := range states {
+		instances[s.InstanceID] = trackedInstance{instanceLabels: instanceLabels{
+			plan:   broker.PlanNamesMapping[s.ServicePlanID],
+			region: s.ProviderRegion,
+			state:  instanceState(s.OperationType, s.OperationState),
+		}}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, instance := range c.instances {
+		_, loaded := instances[id]
+		switch {
+		case instance.updatedAt.After(reconciledAt):
+			instances[id] = instance
+		case instance.removed && !loaded && instance.removedAt.After(c.reconciledAt):
+			instances[id] = instance
+		}
+	}
+	c.instances = instances
+	c.reconciledAt = reconciledAt
+
+	return nil
+}
+
+// OnProvisioningStepProcessed moves the instance to the state of the provisioning operation
+func (c *InstancesPerPlanCollector) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.ProvisioningStepProcessed but got %+v", ev)
+	}
+	op := stepProcessed.Operation.Operation
+
+	return c.update(op, instanceState(internal.OperationTypeProvision, op.State), false)
+}
+
+// OnDeprovisioningStepProcessed moves the instance to the state of the deprovisioning operation,
+// the instance is removed when the deprovisioning which is not a suspension succeeded
+func (c *InstancesPerPlanCollector) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.DeprovisioningStepProcessed but got %+v", ev)
+	}
+	op := stepProcessed.Operation
+
+	removed := !op.Temporary && op.State == domain.Succeeded
+	return c.update(op.Operation, instanceState(internal.OperationTypeDeprovision, op.State), removed)
+}
+
+// update stores the state of the instance from the event, the events are processed asynchronously
+// and can come out of order, so the events of the operations older than the tracked one are ignored
+func (c *InstancesPerPlanCollector) update(op internal.Operation, state string, removed bool) error {
+	c.mu.Lock()
+	instance, found := c.instances[op.InstanceID]
+	switch {
+	case found && instance.outdatedBy(op):
+		c.mu.Unlock()
+		return nil
+	case found && instance.state == state && instance.removed == removed:
+		instance.updatedAt = op.UpdatedAt
+		c.instances[op.InstanceID] = instance
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	// the region is set on the instance by the provisioning, it is not known by the operation,
+	// it is loaded from the storage without the lock, so the scrapes are not blocked by the storage
+	region := instance.region
+	if region == "" && !removed {
+		inst, err := c.statesGetter.GetByID(op.InstanceID)
+		switch {
+		case dberr.IsNotFound(err):
+		case err != nil:
+			return errors.Wrapf(err, "while getting instance %s", op.InstanceID)
+		default:
+			region = inst.ProviderRegion
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the instance could be updated by a newer event in the meantime
+	instance, found = c.instances[op.InstanceID]
+	if found && instance.outdatedBy(op) {
+		return nil
+	}
+	plan := broker.PlanNamesMapping[op.ProvisioningParameters.PlanID]
+	if plan == "" {
+		plan = instance.plan
+	}
+	if region == "" {
+		region = instance.region
+	}
+	updated := trackedInstance{
+		instanceLabels: instanceLabels{
+			plan:   plan,
+			region: region,
+			state:  state,
+		},
+		updatedAt: op.UpdatedAt,
+		removed:   removed,
+	}
+	if removed {
+		updated.removedAt = time.Now()
+	}
+	c.instances[op.InstanceID] = updated
+
+	return nil
+}
+
+// instanceState returns the state of the instance based on its last provisioning or deprovisioning operation,
+// a succeeded deprovisioning means that the instance is suspended, otherwise it would be removed
+func instanceState(operationType internal.OperationType, state domain.LastOperationState) string {
+	switch {
+	case state == domain.Failed:
+		return instanceStateFailed
+	case operationType == internal.OperationTypeProvision && state == domain.Succeeded:
+		return instanceStateSucceeded
+	case operationType == internal.OperationTypeProvision:
+		return instanceStateProvisioning
+	case state == domain.Succeeded:
+		return instanceStateSuspended
+	default:
+		return instanceStateDeprovisioning
+	}
+}
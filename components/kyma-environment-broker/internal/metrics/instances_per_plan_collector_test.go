@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesPerPlanCollector_CountsUpdatedOnEvents(t *testing.T) {
+	// given
+	getter := &fakeInstanceStatesGetter{
+		states: []internal.InstanceState{
+			{InstanceID: "suspended", ServicePlanID: broker.AzurePlanID, ProviderRegion: "westeurope", OperationType: internal.OperationTypeProvision, OperationState: domain.Succeeded},
+			{InstanceID: "removed", ServicePlanID: broker.TrialPlanID, ProviderRegion: "eastus", OperationType: internal.OperationTypeProvision, OperationState: domain.Succeeded},
+		},
+		instances: map[string]internal.Instance{
+			"created": {InstanceID: "created", ProviderRegion: "westeurope"},
+		},
+	}
+	collector := NewInstancesPerPlanCollector(getter)
+	require.NoError(t, collector.Reconcile())
+
+	// when
+	created := fixOperation("created", broker.AzurePlanID, domain.InProgress, time.Now())
+	for _, ev := range []process.ProvisioningStepProcessed{
+		{
+			OldOperation: internal.ProvisioningOperation{Operation: created},
+			Operation:    internal.ProvisioningOperation{Operation: created},
+		},
+		{
+			OldOperation: internal.ProvisioningOperation{Operation: created},
+			Operation:    internal.ProvisioningOperation{Operation: withState(created, domain.Succeeded)},
+		},
+	} {
+		require.NoError(t, collector.OnProvisioningStepProcessed(context.TODO(), ev))
+	}
+	suspension := fixOperation("suspended", broker.AzurePlanID, domain.InProgress, time.Now())
+	deprovisioning := fixOperation("removed", broker.TrialPlanID, domain.InProgress, time.Now())
+	for _, ev := range []process.DeprovisioningStepProcessed{
+		{
+			OldOperation: internal.DeprovisioningOperation{Operation: suspension, Temporary: true},
+			Operation:    internal.DeprovisioningOperation{Operation: withState(suspension, domain.Succeeded), Temporary: true},
+		},
+		{
+			OldOperation: internal.DeprovisioningOperation{Operation: deprovisioning},
+			Operation:    internal.DeprovisioningOperation{Operation: withState(deprovisioning, domain.Succeeded)},
+		},
+	} {
+		require.NoError(t, collector.OnDeprovisioningStepProcessed(context.TODO(), ev))
+	}
+
+	// then
+	assert.Equal(t, map[instanceLabels]int{
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateSucceeded}: 1,
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateSuspended}: 1,
+	}, collector.counts())
+	assert.Equal(t, 1, getter.statesCalls)
+	assert.Equal(t, 1, getter.getCalls)
+}
+
+func TestInstancesPerPlanCollector_Reconcile(t *testing.T) {
+	// given
+	getter := &fakeInstanceStatesGetter{
+		instances: map[string]internal.Instance{
+			"created": {InstanceID: "created", ProviderRegion: "westeurope"},
+		},
+	}
+	collector := NewInstancesPerPlanCollector(getter)
+	require.NoError(t, collector.Reconcile())
+	err := collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		Operation: internal.ProvisioningOperation{Operation: fixOperation("created", broker.AzurePlanID, domain.Succeeded, time.Now())},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[instanceLabels]int{
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateSucceeded}: 1,
+	}, collector.counts())
+
+	getter.states = []internal.InstanceState{
+		{InstanceID: "created", ServicePlanID: broker.AzurePlanID, ProviderRegion: "westeurope", OperationType: internal.OperationTypeDeprovision, OperationState: domain.InProgress},
+		{InstanceID: "other", ServicePlanID: broker.AzurePlanID, ProviderRegion: "westeurope", OperationType: internal.OperationTypeProvision, OperationState: domain.Failed},
+	}
+
+	// when
+	err = collector.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[instanceLabels]int{
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateDeprovisioning}: 1,
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateFailed}:         1,
+	}, collector.counts())
+}
+
+func TestInstancesPerPlanCollector_IgnoresOutdatedEvents(t *testing.T) {
+	// given
+	getter := &fakeInstanceStatesGetter{
+		instances: map[string]internal.Instance{
+			"created": {InstanceID: "created", ProviderRegion: "westeurope"},
+			"removed": {InstanceID: "removed", ProviderRegion: "eastus"},
+		},
+	}
+	collector := NewInstancesPerPlanCollector(getter)
+	require.NoError(t, collector.Reconcile())
+	startedAt := time.Now().Add(-time.Hour)
+
+	created := withUpdatedAt(fixOperation("created", broker.AzurePlanID, domain.InProgress, startedAt), startedAt)
+	provisioning := withUpdatedAt(fixOperation("removed", broker.TrialPlanID, domain.InProgress, startedAt), startedAt)
+	deprovisioning := withUpdatedAt(fixOperation("removed", broker.TrialPlanID, domain.Succeeded, startedAt), startedAt.Add(time.Minute))
+
+	// when
+	for _, op := range []internal.Operation{withUpdatedAt(withState(created, domain.Succeeded), startedAt.Add(time.Minute)), created} {
+		require.NoError(t, collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+			Operation: internal.ProvisioningOperation{Operation: op},
+		}))
+	}
+	require.NoError(t, collector.OnDeprovisioningStepProcessed(context.TODO(), process.DeprovisioningStepProcessed{
+		Operation: internal.DeprovisioningOperation{Operation: deprovisioning},
+	}))
+	getter.states = []internal.InstanceState{
+		{InstanceID: "created", ServicePlanID: broker.AzurePlanID, ProviderRegion: "westeurope", OperationType: internal.OperationTypeProvision, OperationState: domain.Succeeded},
+	}
+	require.NoError(t, collector.Reconcile())
+	require.NoError(t, collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		Operation: internal.ProvisioningOperation{Operation: provisioning},
+	}))
+
+	// then
+	assert.Equal(t, map[instanceLabels]int{
+		{plan: broker.AzurePlanName, region: "westeurope", state: instanceStateSucceeded}: 1,
+	}, collector.counts())
+}
+
+func withUpdatedAt(operation internal.Operation, updatedAt time.Time) internal.Operation {
+	operation.UpdatedAt = updatedAt
+	return operation
+}
+
+type fakeInstanceStatesGetter struct {
+	states      []internal.InstanceState
+	instances   map[string]internal.Instance
+	statesCalls int
+	getCalls    int
+}
+
+func (f *fakeInstanceStatesGetter) GetInstanceStates() ([]internal.InstanceState, error) {
+	f.statesCalls++
+	return append([]internal.InstanceState{}, f.states...), nil
+}
+
+func (f *fakeInstanceStatesGetter) GetByID(instanceID string) (*internal.Instance, error) {
+	f.getCalls++
+	instance, found := f.instances[instanceID]
+	if !found {
+		return nil, dberr.NotFound("instance %s not found", instanceID)
+	}
+	return &instance, nil
+}
@@ -1,18 +1,38 @@
 package metrics
 
 import (
+	"context"
+	"time"
+
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-func RegisterAll(sub event.Subscriber, operationStatsGetter OperationsStatsGetter, instanceStatsGetter InstancesStatsGetter) {
+type Config struct {
+	// ReconcileInterval defines how often the operations and instances counts are reloaded from the storage
+	// to correct a drift of the counts maintained from the events
+	ReconcileInterval time.Duration `envconfig:"default=10m"`
+}
+
+type reconciler interface {
+	Reconcile() error
+}
+
+func RegisterAll(ctx context.Context, sub event.Subscriber, cfg Config, operationStatsGetter OperationsStatsGetter, instanceStatsGetter InstancesStatsGetter, instanceStatesGetter InstanceStatesGetter, log logrus.FieldLogger) {
 	opResultCollector := NewOperationResultCollector()
 	opDurationCollector := NewOperationDurationCollector()
 	stepResultCollector := NewStepResultCollector()
-	prometheus.MustRegister(opResultCollector, opDurationCollector, stepResultCollector)
-	prometheus.MustRegister(NewOperationsCollector(operationStatsGetter))
-	prometheus.MustRegister(NewInstancesCollector(instanceStatsGetter))
+	stepDurationCollector := NewStepDurationCollector()
+	operationsCollector := NewOperationsCollector(operationStatsGetter)
+	instancesCollector := NewInstancesCollector(instanceStatsGetter)
+	instancesPerPlanCollector := NewInstancesPerPlanCollector(instanceStatesGetter)
+	prometheus.MustRegister(opResultCollector, opDurationCollector, stepResultCollector, stepDurationCollector)
+	prometheus.MustRegister(operationsCollector)
+	prometheus.MustRegister(instancesCollector)
+	prometheus.MustRegister(instancesPerPlanCollector)
 
 	sub.Subscribe(process.ProvisioningStepProcessed{}, opResultCollector.OnProvisioningStepProcessed)
 	sub.Subscribe(process.DeprovisioningStepProcessed{}, opResultCollector.OnDeprovisioningStepProcessed)
@@ -21,4 +41,24 @@ func RegisterAll(sub event.Subscriber, operationStatsGetter OperationsStatsGette
 	sub.Subscribe(process.DeprovisioningStepProcessed{}, opDurationCollector.OnDeprovisioningStepProcessed)
 	sub.Subscribe(process.ProvisioningStepProcessed{}, stepResultCollector.OnProvisioningStepProcessed)
 	sub.Subscribe(process.DeprovisioningStepProcessed{}, stepResultCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe(process.ProvisioningStepProcessed{}, stepDurationCollector.OnProvisioningStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, stepDurationCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe(process.ProvisioningStepProcessed{}, operationsCollector.OnProvisioningStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, operationsCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe(process.ProvisioningStepProcessed{}, instancesCollector.OnProvisioningStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, instancesCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe(process.ProvisioningStepProcessed{}, instancesPerPlanCollector.OnProvisioningStepProcessed)
+	sub.Subscribe(process.DeprovisioningStepProcessed{}, instancesPerPlanCollector.OnDeprovisioningStepProcessed)
+
+	go wait.Until(reconcileAll(log, operationsCollector, instancesCollector, instancesPerPlanCollector), cfg.ReconcileInterval, ctx.Done())
+}
+
+func reconcileAll(log logrus.FieldLogger, reconcilers ...reconciler) func() {
+	return func() {
+		for _, r := range reconcilers {
+			if err := r.Reconcile(); err != nil {
+				log.Errorf("while reconciling metrics: %s", err)
+			}
+		}
+	}
 }
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Handler exposes the metrics of the gatherer in the OpenMetrics format with the histogram exemplars
+// if the client accepts application/openmetrics-text, in the format negotiated by promhttp otherwise.
+// The OpenMetrics option of promhttp requires client_golang v1.4.0 which imports github.com/cespare/xxhash/v2,
+// dep cannot resolve such import path, so the OpenMetrics encoder of expfmt is used directly.
+func Handler(gatherer prometheus.Gatherer) http.Handler {
+	fallback := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := expfmt.NegotiateIncludingOpenMetrics(r.Header)
+		if format != expfmt.FmtOpenMetrics {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		families, err := gatherer.Gather()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("An error has occurred while gathering the metrics:\n\n%s", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", string(format))
+		encoder := expfmt.NewEncoder(w, format)
+		for _, family := range families {
+			if err := encoder.Encode(family); err != nil {
+				http.Error(w, fmt.Sprintf("An error has occurred while encoding the metrics:\n\n%s", err), http.StatusInternalServerError)
+				return
+			}
+		}
+		if closer, ok := encoder.(expfmt.Closer); ok {
+			closer.Close()
+		}
+	})
+}
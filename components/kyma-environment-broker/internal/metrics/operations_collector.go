@@ -1,11 +1,16 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
@@ -48,10 +53,19 @@ func (c *OperationStat) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.failedDeprovisioning
 }
 
+// OperationsCollector keeps the operations counts in memory. The counts are loaded from the storage
+// on reconciliation and updated from the step processed events in between, so a scrape does not query the storage.
 type OperationsCollector struct {
 	statsGetter OperationsStatsGetter
 
 	operationStats map[string]OperationStat
+
+	mu    sync.Mutex
+	stats map[string]internal.OperationStats
+	// reconciledAt is the time of the last reconciliation, operations created later are not included in the loaded stats
+	reconciledAt time.Time
+	// counted holds operations created after the last reconciliation which are already included in the stats
+	counted map[string]struct{}
 }
 
 func NewOperationsCollector(statsGetter OperationsStatsGetter) *OperationsCollector {
@@ -95,6 +109,8 @@ func NewOperationsCollector(statsGetter OperationsStatsGetter) *OperationsCollec
 	return &OperationsCollector{
 		statsGetter:    statsGetter,
 		operationStats: opStats,
+		stats:          make(map[string]internal.OperationStats),
+		counted:        make(map[string]struct{}),
 	}
 }
 
@@ -128,10 +144,9 @@ func (c *OperationsCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect implements the prometheus.Collector interface.
 func (c *OperationsCollector) Collect(ch chan<- prometheus.Metric) {
-	stats, err := c.statsGetter.GetOperationStatsByPlan()
-	if err != nil {
-		return
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
 
 	for planID, ops := range c.operationStats {
 		collect(ch,
@@ -162,6 +177,83 @@ func (c *OperationsCollector) Collect(ch chan<- prometheus.Metric) {
 
 }
 
+// Reconcile replaces the operations counts with the ones loaded from the storage
+func (c *OperationsCollector) Reconcile() error {
+	reconciledAt := time.Now()
+	stats, err := c.statsGetter.GetOperationStatsByPlan()
+	if err != nil {
+		return errors.Wrap(err, "while getting operation stats")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+	c.reconciledAt = reconciledAt
+	c.counted = make(map[string]struct{})
+
+	return nil
+}
+
+func (c *OperationsCollector) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.ProvisioningStepProcessed but got %+v", ev)
+	}
+	c.onOperation(internal.OperationTypeProvision, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+
+	return nil
+}
+
+func (c *OperationsCollector) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.DeprovisioningStepProcessed but got %+v", ev)
+	}
+	c.onOperation(internal.OperationTypeDeprovision, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+
+	return nil
+}
+
+func (c *OperationsCollector) onOperation(operationType internal.OperationType, oldOperation, operation internal.Operation) {
+	planID := operation.ProvisioningParameters.PlanID
+	if planID == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, counted := c.counted[operation.ID]
+	switch {
+	case !counted && operation.CreatedAt.After(c.reconciledAt):
+		c.counted[operation.ID] = struct{}{}
+		c.add(planID, operationType, operation.State, 1)
+	case oldOperation.State != operation.State:
+		c.add(planID, operationType, oldOperation.State, -1)
+		c.add(planID, operationType, operation.State, 1)
+	}
+}
+
+func (c *OperationsCollector) add(planID string, operationType internal.OperationType, state domain.LastOperationState, delta int) {
+	stats, found := c.stats[planID]
+	if !found {
+		stats = internal.OperationStats{
+			Provisioning:   make(map[domain.LastOperationState]int),
+			Deprovisioning: make(map[domain.LastOperationState]int),
+		}
+		c.stats[planID] = stats
+	}
+
+	counts := stats.Provisioning
+	if operationType == internal.OperationTypeDeprovision {
+		counts = stats.Deprovisioning
+	}
+	if counts[state]+delta < 0 {
+		return
+	}
+	counts[state] += delta
+}
+
 func collect(ch chan<- prometheus.Metric, desc *prometheus.Desc, value int, labelValues ...string) {
 	m, err := prometheus.NewConstMetric(
 		desc,
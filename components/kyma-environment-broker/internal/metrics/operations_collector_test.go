@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationsCollector_CountsUpdatedOnEvents(t *testing.T) {
+	// given
+	getter := &fakeOperationsStatsGetter{stats: map[string]internal.OperationStats{
+		broker.AzurePlanID: {
+			Provisioning:   map[domain.LastOperationState]int{domain.InProgress: 1, domain.Succeeded: 3},
+			Deprovisioning: map[domain.LastOperationState]int{},
+		},
+	}}
+	collector := NewOperationsCollector(getter)
+	require.NoError(t, collector.Reconcile())
+
+	existing := fixOperation("existing", broker.AzurePlanID, domain.InProgress, time.Now().Add(-time.Hour))
+	created := fixOperation("created", broker.AzurePlanID, domain.InProgress, time.Now())
+
+	// when
+	err := collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		OldOperation: internal.ProvisioningOperation{Operation: existing},
+		Operation:    internal.ProvisioningOperation{Operation: withState(existing, domain.Succeeded)},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		err = collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+			OldOperation: internal.ProvisioningOperation{Operation: created},
+			Operation:    internal.ProvisioningOperation{Operation: created},
+		})
+		require.NoError(t, err)
+	}
+	err = collector.OnDeprovisioningStepProcessed(context.TODO(), process.DeprovisioningStepProcessed{
+		OldOperation: internal.DeprovisioningOperation{Operation: fixOperation("deprovisioning", broker.AzurePlanID, domain.InProgress, time.Now())},
+		Operation:    internal.DeprovisioningOperation{Operation: fixOperation("deprovisioning", broker.AzurePlanID, domain.Failed, time.Now())},
+	})
+	require.NoError(t, err)
+
+	// then
+	stats := collector.stats[broker.AzurePlanID]
+	assert.Equal(t, 1, stats.Provisioning[domain.InProgress])
+	assert.Equal(t, 4, stats.Provisioning[domain.Succeeded])
+	assert.Equal(t, 1, stats.Deprovisioning[domain.Failed])
+	assert.Equal(t, 0, stats.Deprovisioning[domain.InProgress])
+	assert.Equal(t, 1, getter.calls)
+}
+
+func TestOperationsCollector_Reconcile(t *testing.T) {
+	// given
+	getter := &fakeOperationsStatsGetter{stats: map[string]internal.OperationStats{}}
+	collector := NewOperationsCollector(getter)
+	require.NoError(t, collector.Reconcile())
+
+	created := fixOperation("created", broker.TrialPlanID, domain.InProgress, time.Now())
+	err := collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		OldOperation: internal.ProvisioningOperation{Operation: created},
+		Operation:    internal.ProvisioningOperation{Operation: withState(created, domain.Failed)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, collector.stats[broker.TrialPlanID].Provisioning[domain.Failed])
+
+	getter.stats = map[string]internal.OperationStats{
+		broker.TrialPlanID: {
+			Provisioning:   map[domain.LastOperationState]int{domain.Succeeded: 2},
+			Deprovisioning: map[domain.LastOperationState]int{domain.Succeeded: 1},
+		},
+	}
+
+	// when
+	err = collector.Reconcile()
+
+	// then
+	require.NoError(t, err)
+	stats := collector.stats[broker.TrialPlanID]
+	assert.Equal(t, 0, stats.Provisioning[domain.Failed])
+	assert.Equal(t, 2, stats.Provisioning[domain.Succeeded])
+	assert.Equal(t, 1, stats.Deprovisioning[domain.Succeeded])
+	assert.Empty(t, collector.counted)
+}
+
+type fakeOperationsStatsGetter struct {
+	stats map[string]internal.OperationStats
+	calls int
+}
+
+func (f *fakeOperationsStatsGetter) GetOperationStatsByPlan() (map[string]internal.OperationStats, error) {
+	f.calls++
+	result := make(map[string]internal.OperationStats, len(f.stats))
+	for planID, s := range f.stats {
+		result[planID] = internal.OperationStats{
+			Provisioning:   copyCounts(s.Provisioning),
+			Deprovisioning: copyCounts(s.Deprovisioning),
+		}
+	}
+	return result, nil
+}
+
+func copyCounts(counts map[domain.LastOperationState]int) map[domain.LastOperationState]int {
+	result := make(map[domain.LastOperationState]int, len(counts))
+	for state, count := range counts {
+		result[state] = count
+	}
+	return result
+}
+
+func fixOperation(id, planID string, state domain.LastOperationState, createdAt time.Time) internal.Operation {
+	return internal.Operation{
+		ID:                     id,
+		InstanceID:             id,
+		State:                  state,
+		CreatedAt:              createdAt,
+		ProvisioningParameters: internal.ProvisioningParameters{PlanID: planID},
+	}
+}
+
+func withState(operation internal.Operation, state domain.LastOperationState) internal.Operation {
+	operation.State = state
+	return operation
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type InFlightGetter interface {
+	InFlight() map[string]int
+}
+
+// ProvisioningInFlightCollector provides the compass_keb_provisioning_in_flight{"plan"} metric,
+// the number of provisioning operations of the plan processed at the same time, only the plans with the concurrency limit are reported
+type ProvisioningInFlightCollector struct {
+	getter InFlightGetter
+	desc   *prometheus.Desc
+}
+
+func NewProvisioningInFlightCollector(getter InFlightGetter) *ProvisioningInFlightCollector {
+	return &ProvisioningInFlightCollector{
+		getter: getter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "provisioning_in_flight"),
+			"The number of provisioning operations of the plan processed at the same time",
+			[]string{"plan"},
+			nil),
+	}
+}
+
+func (c *ProvisioningInFlightCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *ProvisioningInFlightCollector) Collect(ch chan<- prometheus.Metric) {
+	for planID, count := range c.getter.InFlight() {
+		collect(ch, c.desc, count, broker.PlanNamesMapping[planID])
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PausedGetter interface {
+	Paused() bool
+}
+
+// NewQueuePausedGauge provides the compass_keb_queue_paused{"queue"} metric,
+// the value is 1 while the queue does not process new operations because of an unhealthy dependency, otherwise 0
+func NewQueuePausedGauge(queueName string, queue PausedGetter) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   prometheusNamespace,
+		Subsystem:   prometheusSubsystem,
+		Name:        "queue_paused",
+		Help:        "Indicates if the queue is paused because of an unhealthy dependency",
+		ConstLabels: prometheus.Labels{"queue": queueName},
+	}, func() float64 {
+		if queue.Paused() {
+			return 1
+		}
+		return 0
+	})
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+)
+
+const traceIDLabel = "trace_id"
+
+var stepDurationBuckets = prometheus.ExponentialBuckets(0.05, 2, 12)
+
+// StepDurationCollector provides histograms which describe the time of a single run of the operation step:
+// - compass_keb_provisioning_step_duration_seconds{"step_name", "plan_id"}
+// - compass_keb_deprovisioning_step_duration_seconds{"step_name", "plan_id"}
+// Every bucket keeps the trace ID of the last run observed in it as the exemplar, the exemplars are exposed
+// only in the OpenMetrics format (see Handler). The runs longer than the last bucket have no exemplar.
+type StepDurationCollector struct {
+	provisioningDesc   *prometheus.Desc
+	deprovisioningDesc *prometheus.Desc
+
+	mu                       sync.Mutex
+	provisioningHistograms   map[stepKey]*stepHistogram
+	deprovisioningHistograms map[stepKey]*stepHistogram
+}
+
+type stepKey struct {
+	stepName string
+	planID   string
+}
+
+type stepHistogram struct {
+	count     uint64
+	sum       float64
+	buckets   []uint64
+	exemplars []*dto.Exemplar
+}
+
+func NewStepDurationCollector() *StepDurationCollector {
+	return &StepDurationCollector{
+		provisioningDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "provisioning_step_duration_seconds"),
+			"The time of a single run of the provisioning step",
+			[]string{"step_name", "plan_id"},
+			nil),
+		deprovisioningDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "deprovisioning_step_duration_seconds"),
+			"The time of a single run of the deprovisioning step",
+			[]string{"step_name", "plan_id"},
+			nil),
+		provisioningHistograms:   map[stepKey]*stepHistogram{},
+		deprovisioningHistograms: map[stepKey]*stepHistogram{},
+	}
+}
+
+func (c *StepDurationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.provisioningDesc
+	ch <- c.deprovisioningDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *StepDurationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	collectHistograms(ch, c.provisioningDesc, c.provisioningHistograms)
+	collectHistograms(ch, c.deprovisioningDesc, c.deprovisioningHistograms)
+}
+
+func (c *StepDurationCollector) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.ProvisioningStepProcessed but got %+v", ev)
+	}
+
+	c.observe(c.provisioningHistograms, stepProcessed.StepProcessed, stepProcessed.Operation.Operation, tracing.TraceID(ctx, stepProcessed.Operation.TraceParent))
+	return nil
+}
+
+func (c *StepDurationCollector) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.DeprovisioningStepProcessed but got %+v", ev)
+	}
+
+	c.observe(c.deprovisioningHistograms, stepProcessed.StepProcessed, stepProcessed.Operation.Operation, tracing.TraceID(ctx, stepProcessed.Operation.TraceParent))
+	return nil
+}
+
+func (c *StepDurationCollector) observe(histograms map[stepKey]*stepHistogram, step process.StepProcessed, op internal.Operation, traceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := stepKey{stepName: step.StepName, planID: op.ProvisioningParameters.PlanID}
+	h, found := histograms[key]
+	if !found {
+		h = &stepHistogram{
+			buckets:   make([]uint64, len(stepDurationBuckets)),
+			exemplars: make([]*dto.Exemplar, len(stepDurationBuckets)),
+		}
+		histograms[key] = h
+	}
+
+	seconds := step.Duration.Seconds()
+	h.count++
+	h.sum += seconds
+	i := sort.SearchFloat64s(stepDurationBuckets, seconds)
+	if i == len(stepDurationBuckets) {
+		return
+	}
+	h.buckets[i]++
+	if traceID != "" {
+		h.exemplars[i] = &dto.Exemplar{
+			Label: []*dto.LabelPair{{Name: stringPtr(traceIDLabel), Value: stringPtr(traceID)}},
+			Value: &seconds,
+		}
+	}
+}
+
+func collectHistograms(ch chan<- prometheus.Metric, desc *prometheus.Desc, histograms map[stepKey]*stepHistogram) {
+	for key, h := range histograms {
+		buckets := make(map[float64]uint64, len(stepDurationBuckets))
+		exemplars := make(map[float64]*dto.Exemplar, len(stepDurationBuckets))
+		var cumulative uint64
+		for i, upperBound := range stepDurationBuckets {
+			cumulative += h.buckets[i]
+			buckets[upperBound] = cumulative
+			if h.exemplars[i] != nil {
+				exemplars[upperBound] = h.exemplars[i]
+			}
+		}
+
+		m, err := prometheus.NewConstHistogram(desc, h.count, h.sum, buckets, key.stepName, key.planID)
+		if err != nil {
+			logrus.Errorf("unable to register metric %s", err.Error())
+			continue
+		}
+		ch <- histogramWithExemplars{Metric: m, exemplars: exemplars}
+	}
+}
+
+// histogramWithExemplars adds the exemplars to the buckets of the histogram, the exemplars are keyed by the bucket upper bound
+type histogramWithExemplars struct {
+	prometheus.Metric
+	exemplars map[float64]*dto.Exemplar
+}
+
+func (h histogramWithExemplars) Write(out *dto.Metric) error {
+	if err := h.Metric.Write(out); err != nil {
+		return err
+	}
+	for _, bucket := range out.GetHistogram().GetBucket() {
+		if exemplar, found := h.exemplars[bucket.GetUpperBound()]; found {
+			bucket.Exemplar = exemplar
+		}
+	}
+	return nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
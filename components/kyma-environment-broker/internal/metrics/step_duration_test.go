@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fixTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	fixTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+func TestStepDurationCollector_Exemplars(t *testing.T) {
+	// given
+	collector := NewStepDurationCollector()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	op := fixOperation("op-id", broker.AzurePlanID, domain.InProgress, time.Now())
+	traced, _ := tracing.StartSpan(context.Background(), "Create_Runtime", fixTraceParent)
+
+	// when
+	require.NoError(t, collector.OnProvisioningStepProcessed(traced, process.ProvisioningStepProcessed{
+		Operation:     internal.ProvisioningOperation{Operation: op},
+		StepProcessed: process.StepProcessed{StepName: "Create_Runtime", Duration: 300 * time.Millisecond},
+	}))
+	require.NoError(t, collector.OnProvisioningStepProcessed(context.TODO(), process.ProvisioningStepProcessed{
+		Operation:     internal.ProvisioningOperation{Operation: op},
+		StepProcessed: process.StepProcessed{StepName: "Create_Runtime", Duration: 10 * time.Second},
+	}))
+
+	// then
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, "compass_keb_provisioning_step_duration_seconds", families[0].GetName())
+	histogram := families[0].GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(2), histogram.GetSampleCount())
+
+	var exemplars []string
+	for _, bucket := range histogram.GetBucket() {
+		if bucket.GetExemplar() == nil {
+			continue
+		}
+		for _, label := range bucket.GetExemplar().GetLabel() {
+			exemplars = append(exemplars, label.GetName()+"="+label.GetValue())
+		}
+		assert.Equal(t, 0.3, bucket.GetExemplar().GetValue())
+		assert.Equal(t, 0.4, bucket.GetUpperBound())
+	}
+	assert.Equal(t, []string{"trace_id=" + fixTraceID}, exemplars)
+}
+
+func TestStepDurationCollector_TraceIDOfOperation(t *testing.T) {
+	// given
+	collector := NewStepDurationCollector()
+	op := fixOperation("op-id", broker.AzurePlanID, domain.InProgress, time.Now())
+	op.TraceParent = fixTraceParent
+
+	// when
+	require.NoError(t, collector.OnDeprovisioningStepProcessed(context.TODO(), process.DeprovisioningStepProcessed{
+		Operation:     internal.DeprovisioningOperation{Operation: op},
+		StepProcessed: process.StepProcessed{StepName: "Remove_Runtime", Duration: time.Second},
+	}))
+
+	// then
+	h := collector.deprovisioningHistograms[stepKey{stepName: "Remove_Runtime", planID: broker.AzurePlanID}]
+	require.NotNil(t, h)
+	var traceIDs []string
+	for _, exemplar := range h.exemplars {
+		if exemplar != nil {
+			traceIDs = append(traceIDs, exemplar.GetLabel()[0].GetValue())
+		}
+	}
+	assert.Equal(t, []string{fixTraceID}, traceIDs)
+}
+
+func TestHandler(t *testing.T) {
+	// given
+	collector := NewStepDurationCollector()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	op := fixOperation("op-id", broker.AzurePlanID, domain.InProgress, time.Now())
+	traced, _ := tracing.StartSpan(context.Background(), "Create_Runtime", fixTraceParent)
+	require.NoError(t, collector.OnProvisioningStepProcessed(traced, process.ProvisioningStepProcessed{
+		Operation:     internal.ProvisioningOperation{Operation: op},
+		StepProcessed: process.StepProcessed{StepName: "Create_Runtime", Duration: 300 * time.Millisecond},
+	}))
+	handler := Handler(registry)
+
+	t.Run("should expose exemplars in OpenMetrics", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1,text/plain;version=0.0.4;q=0.5")
+		rec := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(rec, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, string(expfmt.FmtOpenMetrics), rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE compass_keb_provisioning_step_duration_seconds histogram\n")
+		assert.Contains(t, body, `compass_keb_provisioning_step_duration_seconds_bucket{plan_id="`+broker.AzurePlanID+`",step_name="Create_Runtime",le="0.4"} 1 # {trace_id="`+fixTraceID+`"} 0.3`+"\n")
+		assert.Contains(t, body, `compass_keb_provisioning_step_duration_seconds_bucket{plan_id="`+broker.AzurePlanID+`",step_name="Create_Runtime",le="+Inf"} 1`+"\n")
+		assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+	})
+
+	t.Run("should fall back to Prometheus text format", func(t *testing.T) {
+		for name, accept := range map[string]string{
+			"no accept header":     "",
+			"text format":          "text/plain",
+			"rejected openmetrics": "application/openmetrics-text;q=0,text/plain",
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+				if accept != "" {
+					req.Header.Set("Accept", accept)
+				}
+				rec := httptest.NewRecorder()
+
+				// when
+				handler.ServeHTTP(rec, req)
+
+				// then
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+				body := rec.Body.String()
+				assert.Contains(t, body, `compass_keb_provisioning_step_duration_seconds_bucket{plan_id="`+broker.AzurePlanID+`",step_name="Create_Runtime",le="0.4"} 1`+"\n")
+				assert.NotContains(t, body, "trace_id")
+				assert.NotContains(t, body, "# EOF")
+			})
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+)
+
+// LimitRequestBody rejects the requests with the body bigger than maxSize bytes with 413 Request Entity Too Large
+// before the handler decodes the body. The body is read with http.MaxBytesReader, so at most maxSize bytes
+// of a request are held in memory. A non-positive maxSize disables the limit.
+func LimitRequestBody(maxSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxSize <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Body == nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+			if req.ContentLength > int64(maxSize) {
+				rejectTooLarge(w, maxSize)
+				return
+			}
+
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, int64(maxSize)))
+			switch {
+			case err != nil && len(body) == maxSize:
+				rejectTooLarge(w, maxSize)
+				return
+			case err != nil:
+				httputil.WriteResponse(w, http.StatusBadRequest, apiresponses.ErrorResponse{Description: fmt.Sprintf("while reading request body: %s", err)})
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func rejectTooLarge(w http.ResponseWriter, maxSize int) {
+	httputil.WriteResponse(w, http.StatusRequestEntityTooLarge, apiresponses.ErrorResponse{
+		Description: fmt.Sprintf("the request body exceeds the limit of %d bytes", maxSize),
+	})
+}
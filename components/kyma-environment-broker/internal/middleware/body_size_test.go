@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitRequestBody(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body          string
+		contentLength bool
+		maxSize       int
+		expectedCode  int
+		decoded       bool
+	}{
+		"body under the limit": {
+			body:          `{"parameters": {"name": "test"}}`,
+			contentLength: true,
+			maxSize:       64,
+			expectedCode:  http.StatusOK,
+			decoded:       true,
+		},
+		"body of the limit size": {
+			body:         strings.Repeat("x", 64),
+			maxSize:      64,
+			expectedCode: http.StatusOK,
+			decoded:      true,
+		},
+		"body over the limit with content length": {
+			body:          strings.Repeat("x", 65),
+			contentLength: true,
+			maxSize:       64,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		"body over the limit without content length": {
+			body:         strings.Repeat("x", 1024),
+			maxSize:      64,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		"disabled limit": {
+			body:         strings.Repeat("x", 1024),
+			maxSize:      0,
+			expectedCode: http.StatusOK,
+			decoded:      true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req := httptest.NewRequest(http.MethodPut, "http://url.dev/v2/service_instances/instance-id", strings.NewReader(tc.body))
+			if !tc.contentLength {
+				req.ContentLength = -1
+			}
+			called := false
+			var decoded string
+			handler := middleware.LimitRequestBody(tc.maxSize)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				called = true
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				decoded = string(body)
+				w.WriteHeader(http.StatusOK)
+			}))
+			recorder := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(recorder, req)
+
+			// then
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			assert.Equal(t, tc.decoded, called)
+			if tc.decoded {
+				assert.Equal(t, tc.body, decoded)
+			} else {
+				assert.Contains(t, recorder.Body.String(), "exceeds the limit")
+			}
+		})
+	}
+}
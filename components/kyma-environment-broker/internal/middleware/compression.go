@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// minGzipSize is the size of the response body below which the compression does not pay off
+const minGzipSize = 1024
+
+// AddETagAndGzip buffers the successful responses of GET requests to send them with the ETag header, so the client
+// which sends the ETag back in the If-None-Match header gets 304 Not Modified if the response did not change.
+// The body is compressed with gzip if the client sends Accept-Encoding: gzip. Other requests and responses are passed through.
+func AddETagAndGzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		buffered := &bufferedWriter{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(buffered, req)
+		body := buffered.body.Bytes()
+
+		if buffered.code != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+			w.WriteHeader(buffered.code)
+			w.Write(body)
+			return
+		}
+
+		etag := ETag(body)
+		w.Header().Set("ETag", etag)
+		w.Header().Add("Vary", "Accept-Encoding")
+		if matchesETag(req.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if len(body) < minGzipSize || !acceptsGzip(req.Header.Get("Accept-Encoding")) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(body)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusOK)
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		gz.Write(body)
+	})
+}
+
+// ETag returns the ETag header value of the response with the given body. The ETag is weak as the gzipped
+// and the plain body are equivalent, but not byte-for-byte equal
+func ETag(body []byte) string {
+	return fmt.Sprintf(`W/"%x"`, sha256.Sum256(body))
+}
+
+type bufferedWriter struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.code = code
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// matchesETag checks the ETag against the If-None-Match header using the weak comparison
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip checks if gzip is one of the encodings from the Accept-Encoding header which is not excluded with q=0
+func acceptsGzip(acceptEncoding string) bool {
+	for _, encoding := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(encoding, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddETagAndGzip(t *testing.T) {
+	catalog := `{"services": [` + strings.Repeat(`{"name": "kymaruntime"},`, 100) + `{}]}`
+	handler := middleware.AddETagAndGzip(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(catalog))
+	}))
+
+	t.Run("should compress response if client accepts gzip", func(t *testing.T) {
+		// given
+		req, err := http.NewRequest(http.MethodGet, "http://url.dev/v2/catalog", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.NotEmpty(t, recorder.Header().Get("ETag"))
+
+		reader, err := gzip.NewReader(recorder.Body)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, catalog, string(body))
+	})
+
+	t.Run("should return plain response if client does not accept gzip", func(t *testing.T) {
+		for name, acceptEncoding := range map[string]string{
+			"no header":      "",
+			"gzip excluded":  "gzip;q=0, deflate",
+			"other encoding": "br",
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				req, err := http.NewRequest(http.MethodGet, "http://url.dev/v2/catalog", nil)
+				require.NoError(t, err)
+				req.Header.Set("Accept-Encoding", acceptEncoding)
+				recorder := httptest.NewRecorder()
+
+				// when
+				handler.ServeHTTP(recorder, req)
+
+				// then
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+				assert.NotEmpty(t, recorder.Header().Get("ETag"))
+				assert.Equal(t, catalog, recorder.Body.String())
+			})
+		}
+	})
+
+	t.Run("should return not modified if ETag matches", func(t *testing.T) {
+		// given
+		req, err := http.NewRequest(http.MethodGet, "http://url.dev/v2/catalog", nil)
+		require.NoError(t, err)
+		first := httptest.NewRecorder()
+		handler.ServeHTTP(first, req)
+		etag := first.Header().Get("ETag")
+
+		req.Header.Set("If-None-Match", etag)
+		req.Header.Set("Accept-Encoding", "gzip")
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Equal(t, etag, recorder.Header().Get("ETag"))
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("should return response if ETag does not match", func(t *testing.T) {
+		// given
+		req, err := http.NewRequest(http.MethodGet, "http://url.dev/v2/catalog", nil)
+		require.NoError(t, err)
+		req.Header.Set("If-None-Match", `W/"outdated"`)
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, catalog, recorder.Body.String())
+	})
+
+	t.Run("should pass through other requests", func(t *testing.T) {
+		// given
+		req, err := http.NewRequest(http.MethodPut, "http://url.dev/v2/service_instances/1", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", "gzip")
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("ETag"))
+		assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+		assert.Equal(t, catalog, recorder.Body.String())
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/google/uuid"
+)
+
+// maxCorrelationIDLength is the size of the column storing the correlation ID of the operation
+const maxCorrelationIDLength = 64
+
+// AddCorrelationIDToContext puts the correlation ID from the request header into the request context,
+// a new ID is generated if the caller did not send one or sent a too long one. The ID is returned in the response header
+// and written back to the request header, so the brokerapi handlers log the same ID.
+func AddCorrelationIDToContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(httputil.CorrelationIDHeader)
+		if id == "" || len(id) > maxCorrelationIDLength {
+			id = uuid.New().String()
+			req.Header.Set(httputil.CorrelationIDHeader, id)
+		}
+		w.Header().Set(httputil.CorrelationIDHeader, id)
+
+		next.ServeHTTP(w, req.WithContext(WithCorrelationID(req.Context(), id)))
+	})
+}
+
+// WithCorrelationID returns a copy of the context which carries the correlation ID
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID associated with the context if possible.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey).(string)
+	return id, ok
+}
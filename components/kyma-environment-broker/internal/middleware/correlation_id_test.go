@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCorrelationIDToContext(t *testing.T) {
+	for name, tc := range map[string]struct {
+		header   string
+		expected string
+	}{
+		"passed by the caller": {header: "caller-id", expected: "caller-id"},
+		"missing":              {header: ""},
+		"too long":             {header: strings.Repeat("a", 65)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodPut, "http://url.dev/endpoint", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set(httputil.CorrelationIDHeader, tc.header)
+			}
+
+			var gotID, gotHeader string
+			var found bool
+			handler := middleware.AddCorrelationIDToContext(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotID, found = middleware.CorrelationIDFromContext(req.Context())
+				gotHeader = req.Header.Get(httputil.CorrelationIDHeader)
+			}))
+			recorder := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(recorder, req)
+
+			// then
+			require.True(t, found)
+			assert.NotEmpty(t, gotID)
+			assert.LessOrEqual(t, len(gotID), 64)
+			if tc.expected != "" {
+				assert.Equal(t, tc.expected, gotID)
+			}
+			assert.Equal(t, gotID, gotHeader)
+			assert.Equal(t, gotID, recorder.Header().Get(httputil.CorrelationIDHeader))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// FetchParametersQueryParam is the query parameter with which the client requests the provisioning parameters of the instance
+const FetchParametersQueryParam = "fetch_parameters"
+
+// AddFetchParametersToContext puts the fetch_parameters flag from the request query into the request context,
+// the flag is false when the parameter is missing or is not a valid boolean
+func AddFetchParametersToContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetch, err := strconv.ParseBool(req.URL.Query().Get(FetchParametersQueryParam))
+		if err != nil {
+			fetch = false
+		}
+
+		next.ServeHTTP(w, req.WithContext(WithFetchParameters(req.Context(), fetch)))
+	})
+}
+
+// WithFetchParameters returns a copy of the context which carries the fetch_parameters flag
+func WithFetchParameters(ctx context.Context, fetch bool) context.Context {
+	return context.WithValue(ctx, fetchParametersKey, fetch)
+}
+
+// FetchParametersFromContext returns true if the client requested the provisioning parameters of the instance
+func FetchParametersFromContext(ctx context.Context) bool {
+	fetch, ok := ctx.Value(fetchParametersKey).(bool)
+	return ok && fetch
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFetchParametersToContext(t *testing.T) {
+	for name, tc := range map[string]struct {
+		url      string
+		expected bool
+	}{
+		"requested":     {url: "http://url.dev/endpoint?fetch_parameters=true", expected: true},
+		"not requested": {url: "http://url.dev/endpoint", expected: false},
+		"disabled":      {url: "http://url.dev/endpoint?fetch_parameters=false", expected: false},
+		"invalid":       {url: "http://url.dev/endpoint?fetch_parameters=yes-please", expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			var got bool
+			handler := middleware.AddFetchParametersToContext(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				got = middleware.FetchParametersFromContext(req.Context())
+			}))
+
+			// when
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			// then
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
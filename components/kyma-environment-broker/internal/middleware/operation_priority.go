@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
+)
+
+// OperationPriorityHeader is the header with which the client requests the priority of the triggered operation,
+// the operations with a higher priority are processed first
+const OperationPriorityHeader = "X-Operation-Priority"
+
+// AddOperationPriorityToContext puts the priority from the request header into the request context,
+// the request with the priority which is not an integer is rejected with 400 Bad Request
+func AddOperationPriorityToContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		value := req.Header.Get(OperationPriorityHeader)
+		if value == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			httputil.WriteResponse(w, http.StatusBadRequest, apiresponses.ErrorResponse{
+				Description: fmt.Sprintf("%s header must be an integer, got %q", OperationPriorityHeader, value),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, req.WithContext(WithOperationPriority(req.Context(), priority)))
+	})
+}
+
+// WithOperationPriority returns a copy of the context which carries the requested operation priority
+func WithOperationPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, operationPriorityKey, priority)
+}
+
+// OperationPriorityFromContext returns the operation priority requested by the client if possible
+func OperationPriorityFromContext(ctx context.Context) (int, bool) {
+	priority, ok := ctx.Value(operationPriorityKey).(int)
+	return priority, ok
+}
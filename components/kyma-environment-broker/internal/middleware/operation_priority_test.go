@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOperationPriorityToContext(t *testing.T) {
+	for name, tc := range map[string]struct {
+		header           string
+		expectedStatus   int
+		expectedPriority int
+		expectedFound    bool
+	}{
+		"requested":     {header: "10", expectedStatus: http.StatusOK, expectedPriority: 10, expectedFound: true},
+		"negative":      {header: "-5", expectedStatus: http.StatusOK, expectedPriority: -5, expectedFound: true},
+		"not requested": {header: "", expectedStatus: http.StatusOK},
+		"invalid":       {header: "urgent", expectedStatus: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodPut, "http://url.dev/v2/service_instances/inst", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				req.Header.Set(middleware.OperationPriorityHeader, tc.header)
+			}
+
+			var (
+				priority int
+				found    bool
+			)
+			handler := middleware.AddOperationPriorityToContext(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				priority, found = middleware.OperationPriorityFromContext(req.Context())
+			}))
+			rr := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(rr, req)
+
+			// then
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedPriority, priority)
+		})
+	}
+}
@@ -2,9 +2,14 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 
 	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 )
 
 // The key type is no exported to prevent collisions with context keys
@@ -14,15 +19,44 @@ type key int
 const (
 	// requestRegionKey is the context key for the region from the request path.
 	requestRegionKey key = iota + 1
+	// retryAfterKey is the context key for the delay sent in the Retry-After header.
+	retryAfterKey
+	// correlationIDKey is the context key for the ID correlating the request with the operation it triggers.
+	correlationIDKey
+	// fetchParametersKey is the context key for the flag requesting the provisioning parameters of the instance.
+	fetchParametersKey
+	// verboseKey is the context key for the details added to the response of the verbose request.
+	verboseKey
+	// operationPriorityKey is the context key for the priority of the operation triggered by the request.
+	operationPriorityKey
 )
 
-func AddRegionToContext(defaultRegion string) mux.MiddlewareFunc {
+// AddRegionToContext puts the region from the request path into the request context.
+// The default region is used only when the path does not contain the region.
+// If known regions are given, the region from the path is matched against them case-insensitively
+// and normalized to the configured form, unknown regions are rejected with 400 Bad Request.
+func AddRegionToContext(defaultRegion string, knownRegions ...string) mux.MiddlewareFunc {
+	known := make(map[string]string, len(knownRegions))
+	for _, r := range knownRegions {
+		known[strings.ToLower(r)] = r
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			vars := mux.Vars(req)
 			region, found := vars["region"]
-			if !found {
+			switch {
+			case !found:
 				region = defaultRegion
+			case len(known) > 0:
+				normalized, exists := known[strings.ToLower(region)]
+				if !exists {
+					httputil.WriteResponse(w, http.StatusBadRequest, apiresponses.ErrorResponse{
+						Description: fmt.Sprintf("region %q is not supported", region),
+					})
+					return
+				}
+				region = normalized
 			}
 
 			newCtx := context.WithValue(req.Context(), requestRegionKey, region)
@@ -91,3 +91,65 @@ func TestRequestRegionKeyNotFound(t *testing.T) {
 	assert.Empty(t, gotValue)
 	assert.False(t, found)
 }
+
+func TestRequestRegionKnownRegions(t *testing.T) {
+	knownRegions := []string{"cf-eu10", "cf-us10"}
+
+	for tn, tc := range map[string]struct {
+		path string
+
+		expectedCode   int
+		expectedRegion string
+	}{
+		"known region": {
+			path:           "/oauth/cf-us10/endpoint",
+			expectedCode:   http.StatusOK,
+			expectedRegion: "cf-us10",
+		},
+		"known region with different case is normalized": {
+			path:           "/oauth/CF-US10/endpoint",
+			expectedCode:   http.StatusOK,
+			expectedRegion: "cf-us10",
+		},
+		"unknown region is rejected": {
+			path:         "/oauth/cf-xx99/endpoint",
+			expectedCode: http.StatusBadRequest,
+		},
+		"default region is used when there is no region in the path": {
+			path:           "/oauth/endpoint",
+			expectedCode:   http.StatusOK,
+			expectedRegion: "cf-eu10",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodGet, "http://url.dev"+tc.path, nil)
+			require.NoError(t, err)
+
+			var gotCtx context.Context
+			spyHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotCtx = req.Context()
+			})
+
+			router := mux.NewRouter()
+			router.Use(middleware.AddRegionToContext("cf-eu10", knownRegions...))
+			router.Path("/oauth/endpoint").Handler(spyHandler)
+			router.Path("/oauth/{region}/endpoint").Handler(spyHandler)
+			rr := httptest.NewRecorder()
+
+			// when
+			router.ServeHTTP(rr, req)
+
+			// then
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusOK {
+				assert.Nil(t, gotCtx)
+				assert.Contains(t, rr.Body.String(), "cf-xx99")
+				return
+			}
+			gotRegion, found := middleware.RegionFromContext(gotCtx)
+			assert.True(t, found)
+			assert.Equal(t, tc.expectedRegion, gotRegion)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type retryAfter struct {
+	delay time.Duration
+}
+
+// AddRetryAfterHeader allows handlers to set the delay with SetRetryAfter, the delay is sent
+// in the Retry-After header of the 429 Too Many Requests response
+func AddRetryAfterHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		holder := &retryAfter{}
+		newCtx := context.WithValue(req.Context(), retryAfterKey, holder)
+		next.ServeHTTP(&retryAfterWriter{ResponseWriter: w, holder: holder}, req.WithContext(newCtx))
+	})
+}
+
+// SetRetryAfter sets the delay after which the client can retry the request
+func SetRetryAfter(ctx context.Context, delay time.Duration) {
+	if holder, ok := ctx.Value(retryAfterKey).(*retryAfter); ok {
+		holder.delay = delay
+	}
+}
+
+type retryAfterWriter struct {
+	http.ResponseWriter
+	holder *retryAfter
+}
+
+func (w *retryAfterWriter) WriteHeader(code int) {
+	if code == http.StatusTooManyRequests && w.holder.delay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(w.holder.delay.Seconds()))))
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRetryAfterHeader(t *testing.T) {
+	for name, tc := range map[string]struct {
+		code     int
+		expected string
+	}{
+		"too many requests": {code: http.StatusTooManyRequests, expected: "3"},
+		"other status":      {code: http.StatusBadRequest, expected: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodPut, "http://url.dev/endpoint", nil)
+			require.NoError(t, err)
+			handler := middleware.AddRetryAfterHeader(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				middleware.SetRetryAfter(req.Context(), 2500*time.Millisecond)
+				w.WriteHeader(tc.code)
+			}))
+			recorder := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(recorder, req)
+
+			// then
+			assert.Equal(t, tc.code, recorder.Code)
+			assert.Equal(t, tc.expected, recorder.Header().Get("Retry-After"))
+		})
+	}
+}
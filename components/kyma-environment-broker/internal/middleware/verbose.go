@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// VerboseQueryParam is the query parameter with which the client requests the details which are not returned by default,
+// e.g. the timings of the operation in the last operation response
+const VerboseQueryParam = "verbose"
+
+type verboseDetails struct {
+	mu      sync.Mutex
+	details map[string]interface{}
+}
+
+// AddVerboseDetails allows the handlers of the requests with the verbose query parameter set to true to attach
+// the details with SetVerboseDetail. The details are added to the JSON object of the 200 OK response.
+// Other requests are passed through, so the normal polling is not slowed down by buffering the response.
+func AddVerboseDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		verbose, err := strconv.ParseBool(req.URL.Query().Get(VerboseQueryParam))
+		if err != nil || !verbose {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		holder := &verboseDetails{details: map[string]interface{}{}}
+		buffered := &bufferedWriter{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(buffered, req.WithContext(context.WithValue(req.Context(), verboseKey, holder)))
+
+		body := buffered.body.Bytes()
+		if buffered.code == http.StatusOK && len(holder.details) > 0 {
+			if extended, err := withDetails(body, holder.details); err == nil {
+				body = extended
+				w.Header().Del("Content-Length")
+			}
+		}
+		w.WriteHeader(buffered.code)
+		w.Write(body)
+	})
+}
+
+// VerboseFromContext returns true if the client requested the details which are not returned by default
+func VerboseFromContext(ctx context.Context) bool {
+	_, ok := ctx.Value(verboseKey).(*verboseDetails)
+	return ok
+}
+
+// SetVerboseDetail adds the detail under the given key to the response of the verbose request, it does nothing for other requests
+func SetVerboseDetail(ctx context.Context, key string, value interface{}) {
+	if holder, ok := ctx.Value(verboseKey).(*verboseDetails); ok {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+		holder.details[key] = value
+	}
+}
+
+// withDetails adds the details to the JSON object, the body which is not a JSON object is returned with the error
+func withDetails(body []byte, details map[string]interface{}) ([]byte, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, err
+	}
+	for key, value := range details {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		object[key] = raw
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddVerboseDetails(t *testing.T) {
+	for name, tc := range map[string]struct {
+		url      string
+		code     int
+		expected map[string]interface{}
+	}{
+		"verbose request": {
+			url:      "http://url.dev/endpoint?verbose=true",
+			code:     http.StatusOK,
+			expected: map[string]interface{}{"state": "succeeded", "timings": map[string]interface{}{"step": "done"}},
+		},
+		"not verbose request": {
+			url:      "http://url.dev/endpoint",
+			code:     http.StatusOK,
+			expected: map[string]interface{}{"state": "succeeded"},
+		},
+		"verbose request with error response": {
+			url:      "http://url.dev/endpoint?verbose=true",
+			code:     http.StatusBadRequest,
+			expected: map[string]interface{}{"state": "succeeded"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			handler := middleware.AddVerboseDetails(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				middleware.SetVerboseDetail(req.Context(), "timings", map[string]string{"step": "done"})
+				w.WriteHeader(tc.code)
+				w.Write([]byte(`{"state": "succeeded"}`))
+			}))
+			recorder := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(recorder, req)
+
+			// then
+			assert.Equal(t, tc.code, recorder.Code)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			assert.Equal(t, tc.expected, body)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package internal
 
 import (
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
@@ -46,6 +48,8 @@ type LMS struct {
 	TenantID    string    `json:"tenant_id"`
 	Failed      bool      `json:"failed"`
 	RequestedAt time.Time `json:"requested_at"`
+	// CertExpiresAt is the end of the validity period of the signed certificate issued for the runtime
+	CertExpiresAt time.Time `json:"cert_expires_at"`
 }
 
 type AvsEvaluationStatus struct {
@@ -62,6 +66,11 @@ type AvsLifecycleData struct {
 
 	AVSInternalEvaluationDeleted bool `json:"avs_internal_evaluation_deleted"`
 	AVSExternalEvaluationDeleted bool `json:"avs_external_evaluation_deleted"`
+
+	// AVSInternalEvaluationDeferred and AVSExternalEvaluationDeferred mark the evaluations which were not created
+	// during the provisioning because AVS was unavailable, they are created later by the deferred evaluations reconciler
+	AVSInternalEvaluationDeferred bool `json:"avs_internal_evaluation_deferred,omitempty"`
+	AVSExternalEvaluationDeferred bool `json:"avs_external_evaluation_deferred,omitempty"`
 }
 
 // RuntimeVersionOrigin defines the possible sources of the Kyma Version parameter
@@ -114,6 +123,9 @@ type Instance struct {
 	Parameters     ProvisioningParameters
 	ProviderRegion string
 
+	// SuspensionReason is set when the instance is suspended and cleared when it is resumed
+	SuspensionReason string
+
 	InstanceDetails InstanceDetails
 
 	CreatedAt time.Time
@@ -123,6 +135,25 @@ type Instance struct {
 	Version int
 }
 
+// CustomDomain returns the custom domain requested for the instance in lower case, the domains are compared case-insensitively.
+// An empty string is returned for the instance which uses the domain generated for the cluster.
+func (i *Instance) CustomDomain() string {
+	if i.Parameters.Parameters.CustomDomain == nil {
+		return ""
+	}
+	return strings.ToLower(*i.Parameters.Parameters.CustomDomain)
+}
+
+// Suspension reasons of the instance, the reason is passed by the platform in the suspension_reason field of the context
+// except for the idle reason, which is set when the broker suspends the trial instance without activity
+const (
+	SuspensionReasonTrialExpired  = "trial_expired"
+	SuspensionReasonManual        = "manual"
+	SuspensionReasonQuotaExceeded = "quota_exceeded"
+	SuspensionReasonIdle          = "idle"
+	SuspensionReasonUnknown       = "unknown"
+)
+
 // OperationType defines the possible types of an asynchronous operation to a broker.
 type OperationType string
 
@@ -142,6 +173,13 @@ const (
 type Operation struct {
 	// following fields are serialized to JSON and stored in the storage
 	InstanceDetails
+	// Progress holds the latest progress messages reported by the steps, at most MaxProgressMessages are retained
+	Progress []ProgressMessage `json:"progress,omitempty"`
+	// StepTimings holds the time when each step was started for the first time and when it finished, in the order of the start
+	StepTimings []StepTiming `json:"step_timings,omitempty"`
+	// ProvisionerRequest holds the last request sent to the Provisioner with the secrets redacted, it is stored only
+	// when dumping the Provisioner requests is enabled
+	ProvisionerRequest json.RawMessage `json:"provisioner_request,omitempty"`
 
 	ID        string        `json:"-"`
 	Version   int           `json:"-"`
@@ -159,6 +197,86 @@ type Operation struct {
 	OrchestrationID string              `json:"-"`
 	FinishedStages  map[string]struct{} `json:"-"`
 	FinishedSteps   map[string]struct{} `json:"-"`
+	// CurrentStep holds the name of the step which is processed (or was processed last) by the operation manager
+	CurrentStep string `json:"-"`
+	// CorrelationID is the ID of the request which triggered the operation, empty for operations not triggered by a request
+	CorrelationID string `json:"-"`
+	// TraceParent is the W3C traceparent of the root span of the operation, empty if the traces are not exported
+	TraceParent string `json:"-"`
+	// FailureReason describes why the operation failed, nil if the operation did not fail or the step did not return the reason
+	FailureReason *FailureReason `json:"-"`
+}
+
+// FailureReason is the structured description of the operation failure, it is used to triage the failures
+// and to route them to the subsystem which can remediate them
+type FailureReason struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+	Retryable   bool   `json:"retryable"`
+	Subsystem   string `json:"subsystem,omitempty"`
+}
+
+// MaxProgressMessages is the number of the latest progress messages retained in the operation
+const MaxProgressMessages = 20
+
+// ProgressMessage is the timestamped message reported by the step while the operation is processed, e.g. "shoot created"
+type ProgressMessage struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// AddProgress appends the progress message and drops the oldest messages above MaxProgressMessages,
+// false is returned when the message repeats the latest one and was not added
+func (o *Operation) AddProgress(message string, at time.Time) bool {
+	if latest := o.LatestProgress(); latest != nil && latest.Message == message {
+		return false
+	}
+	o.Progress = append(o.Progress, ProgressMessage{Time: at, Message: message})
+	if len(o.Progress) > MaxProgressMessages {
+		o.Progress = append([]ProgressMessage(nil), o.Progress[len(o.Progress)-MaxProgressMessages:]...)
+	}
+	return true
+}
+
+// LatestProgress returns the latest progress message, nil if no progress was reported
+func (o *Operation) LatestProgress() *ProgressMessage {
+	if len(o.Progress) == 0 {
+		return nil
+	}
+	return &o.Progress[len(o.Progress)-1]
+}
+
+// StepTiming is the time when the step of the operation was started for the first time and when it finished,
+// the finish is not set for the step which is in progress or which finished the whole operation
+type StepTiming struct {
+	Step       string     `json:"step"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// StartStepTiming records the start of the step, false is returned when the step was already started before
+func (o *Operation) StartStepTiming(step string, at time.Time) bool {
+	for _, timing := range o.StepTimings {
+		if timing.Step == step {
+			return false
+		}
+	}
+	o.StepTimings = append(o.StepTimings, StepTiming{Step: step, StartedAt: at})
+	return true
+}
+
+// FinishStepTiming records the finish of the started step, the finish of the step which is run again is not changed
+func (o *Operation) FinishStepTiming(step string, at time.Time) {
+	for i, timing := range o.StepTimings {
+		if timing.Step != step || timing.FinishedAt != nil {
+			continue
+		}
+		// the timings are copied as the operation passed to the step shares them with the processed one
+		o.StepTimings = append([]StepTiming(nil), o.StepTimings...)
+		o.StepTimings[i].FinishedAt = &at
+		return
+	}
 }
 
 func (o *Operation) IsFinished() bool {
@@ -222,12 +340,23 @@ type ProvisioningOperation struct {
 
 	RuntimeVersion RuntimeVersionData `json:"runtime_version"`
 
+	// ExternalWebhooks holds the state of the external webhook steps by the name of the step
+	ExternalWebhooks map[string]ExternalWebhookState `json:"external_webhooks,omitempty"`
+
 	// following fields are not stored in the storage
 	InputCreator ProvisionerInputCreator `json:"-"`
 
 	SMClientFactory SMClientFactory `json:"-"`
 }
 
+// ExternalWebhookState is the state of the work requested from the async external webhook
+type ExternalWebhookState struct {
+	// StatusURL is the URL on which the status of the accepted work is polled, empty when the work must be requested again
+	StatusURL string `json:"statusUrl,omitempty"`
+	// StartedAt is the time of the first request, the step fails when the work is not finished in time
+	StartedAt time.Time `json:"startedAt"`
+}
+
 type ServiceManagerInstanceInfo struct {
 	BrokerID                string `json:"brokerId"`
 	ServiceID               string `json:"serviceId"`
@@ -277,6 +406,9 @@ type DeprovisioningOperation struct {
 
 	// Temporary indicates that this deprovisioning operation must not remove the instance
 	Temporary bool `json:"temporary"`
+
+	// CredentialsRevoked indicates that the kubeconfigs and tokens issued for the runtime were revoked
+	CredentialsRevoked bool `json:"credentials_revoked"`
 }
 
 // UpgradeKymaOperation holds all information about upgrade Kyma operation
@@ -297,6 +429,9 @@ type UpgradeClusterOperation struct {
 
 	orchestration.RuntimeOperation `json:"runtime_operation"`
 	InputCreator                   ProvisionerInputCreator `json:"-"`
+
+	// Drain holds the drain settings of the worker pool requested by the orchestration, the settings of the runtime are used if nil
+	Drain *orchestration.DrainParameters `json:"drain,omitempty"`
 }
 
 func NewRuntimeState(runtimeID, operationID string, kymaConfig *gqlschema.KymaConfigInput, clusterConfig *gqlschema.GardenerConfigInput) RuntimeState {
@@ -345,6 +480,25 @@ type InstanceStats struct {
 	PerGlobalAccountID     map[string]int
 }
 
+// InstanceState holds the plan and the region of an instance together with the type and the state
+// of its last provisioning or deprovisioning operation
+type InstanceState struct {
+	InstanceID     string
+	ServicePlanID  string
+	ProviderRegion string
+	OperationType  OperationType
+	OperationState domain.LastOperationState
+}
+
+// InstanceStateCount holds the number of instances in the plan whose last provisioning or deprovisioning operation
+// has the given type and state, the type and the state are empty for the instances without such operation
+type InstanceStateCount struct {
+	ServicePlanID  string
+	OperationType  OperationType
+	OperationState domain.LastOperationState
+	Total          int
+}
+
 // NewProvisioningOperation creates a fresh (just starting) instance of the ProvisioningOperation
 func NewProvisioningOperation(instanceID string, parameters ProvisioningParameters) (ProvisioningOperation, error) {
 	return NewProvisioningOperationWithID(uuid.New().String(), instanceID, parameters)
@@ -442,6 +596,10 @@ func (uko *UpgradeKymaOperation) ServiceManagerClient(log logrus.FieldLogger) (s
 	return uko.SMClientFactory.ForCustomerCredentials(serviceManagerRequestCreds(uko.ProvisioningParameters), log)
 }
 
+func (uko *UpgradeKymaOperation) ProvideServiceManagerCredentials(log logrus.FieldLogger) (*servicemanager.Credentials, error) {
+	return uko.SMClientFactory.ProvideCredentials(serviceManagerRequestCreds(uko.ProvisioningParameters), log)
+}
+
 type ComponentConfigurationInputList []*gqlschema.ComponentConfigurationInput
 
 func (l ComponentConfigurationInputList) DeepCopy() []*gqlschema.ComponentConfigurationInput {
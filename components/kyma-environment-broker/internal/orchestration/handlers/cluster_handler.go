@@ -56,6 +56,32 @@ func (h *clusterHandler) createOrchestration(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// validate notification targets
+	err = validateNotifications(params.Notifications)
+	if err != nil {
+		h.log.Errorf("while validating notifications: %v", err)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating notifications"))
+		return
+	}
+
+	// validate retry policy
+	err = validateRetryPolicy(params.Retry)
+	if err != nil {
+		h.log.Errorf("while validating retry policy: %v", err)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating retry policy"))
+		return
+	}
+
+	// validate drain settings
+	if params.Cluster.Drain != nil {
+		err = params.Cluster.Drain.Validate()
+		if err != nil {
+			h.log.Errorf("while validating drain settings: %v", err)
+			httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating drain settings"))
+			return
+		}
+	}
+
 	// defaults strategy if not specified to Parallel with Immediate schedule
 	defaultOrchestrationStrategy(&params.Strategy)
 
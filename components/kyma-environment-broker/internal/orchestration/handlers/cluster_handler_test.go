@@ -53,6 +53,82 @@ func TestClusterHandler_AttachRoutes(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotEmpty(t, out.OrchestrationID)
 	})
+
+	t.Run("upgrade with invalid notification target", func(t *testing.T) {
+		// given
+		handler := fixClusterHandler(t)
+
+		params := orchestration.Parameters{
+			Targets: orchestration.TargetSpec{
+				Include: []orchestration.RuntimeTarget{
+					{
+						RuntimeID: "test",
+					},
+				},
+			},
+			Notifications: []orchestration.NotificationTarget{
+				{
+					Type: orchestration.WebhookNotification,
+				},
+			},
+		}
+		p, err := json.Marshal(&params)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/upgrade/cluster", bytes.NewBuffer(p))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler.AttachRoutes(router)
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("upgrade with drain settings", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			drain        orchestration.DrainParameters
+			expectedCode int
+		}{
+			"surge and unavailability":    {drain: orchestration.DrainParameters{MaxSurge: 1, MaxUnavailable: 1}, expectedCode: http.StatusAccepted},
+			"only unavailability":         {drain: orchestration.DrainParameters{MaxUnavailable: 2}, expectedCode: http.StatusAccepted},
+			"negative surge":              {drain: orchestration.DrainParameters{MaxSurge: -1, MaxUnavailable: 1}, expectedCode: http.StatusBadRequest},
+			"negative unavailability":     {drain: orchestration.DrainParameters{MaxSurge: 1, MaxUnavailable: -1}, expectedCode: http.StatusBadRequest},
+			"no surge nor unavailability": {drain: orchestration.DrainParameters{}, expectedCode: http.StatusBadRequest},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				handler := fixClusterHandler(t)
+
+				drain := tc.drain
+				params := orchestration.Parameters{
+					Targets: orchestration.TargetSpec{
+						Include: []orchestration.RuntimeTarget{{RuntimeID: "test"}},
+					},
+					Cluster: orchestration.ClusterParameters{Drain: &drain},
+				}
+				p, err := json.Marshal(&params)
+				require.NoError(t, err)
+
+				req, err := http.NewRequest("POST", "/upgrade/cluster", bytes.NewBuffer(p))
+				require.NoError(t, err)
+
+				rr := httptest.NewRecorder()
+				router := mux.NewRouter()
+				handler.AttachRoutes(router)
+
+				// when
+				router.ServeHTTP(rr, req)
+
+				// then
+				require.Equal(t, tc.expectedCode, rr.Code)
+			})
+		}
+	})
 }
 
 func fixClusterHandler(t *testing.T) *clusterHandler {
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -56,6 +57,12 @@ func (c *Converter) UpgradeKymaOperationToDTO(op internal.UpgradeKymaOperation)
 		MaintenanceWindowEnd:   op.MaintenanceWindowEnd,
 		State:                  string(op.Operation.State),
 		Description:            op.Operation.Description,
+		CurrentStep:            op.Operation.CurrentStep,
+		Error:                  operationError(op.Operation),
+		Quarantined:            op.RuntimeOperation.Quarantined,
+		Attempt:                op.RuntimeOperation.Attempt,
+		RetriedBy:              op.RuntimeOperation.RetriedBy,
+		FailureReason:          operationFailureReason(op.Operation),
 	}, nil
 }
 
@@ -77,6 +84,58 @@ func (c *Converter) UpgradeKymaOperationListToDTO(ops []internal.UpgradeKymaOper
 	}, nil
 }
 
+func (c *Converter) UpgradeClusterOperationToDTO(op internal.UpgradeClusterOperation) (orchestration.OperationResponse, error) {
+	return orchestration.OperationResponse{
+		OperationID:            op.Operation.ID,
+		RuntimeID:              op.RuntimeOperation.RuntimeID,
+		GlobalAccountID:        op.GlobalAccountID,
+		SubAccountID:           op.RuntimeOperation.SubAccountID,
+		OrchestrationID:        op.OrchestrationID,
+		ServicePlanID:          op.ProvisioningParameters.PlanID,
+		ServicePlanName:        broker.PlanNamesMapping[op.ProvisioningParameters.PlanID],
+		DryRun:                 op.DryRun,
+		ShootName:              op.RuntimeOperation.ShootName,
+		MaintenanceWindowBegin: op.MaintenanceWindowBegin,
+		MaintenanceWindowEnd:   op.MaintenanceWindowEnd,
+		State:                  string(op.Operation.State),
+		Description:            op.Operation.Description,
+		CurrentStep:            op.Operation.CurrentStep,
+		Error:                  operationError(op.Operation),
+		Quarantined:            op.RuntimeOperation.Quarantined,
+		Attempt:                op.RuntimeOperation.Attempt,
+		RetriedBy:              op.RuntimeOperation.RetriedBy,
+		FailureReason:          operationFailureReason(op.Operation),
+	}, nil
+}
+
+func (c *Converter) UpgradeClusterOperationListToDTO(ops []internal.UpgradeClusterOperation, count, totalCount int) (orchestration.OperationResponseList, error) {
+	data := make([]orchestration.OperationResponse, 0)
+
+	for _, op := range ops {
+		o, err := c.UpgradeClusterOperationToDTO(op)
+		if err != nil {
+			return orchestration.OperationResponseList{}, errors.Wrap(err, "while converting operation to DTO")
+		}
+		data = append(data, o)
+	}
+
+	return orchestration.OperationResponseList{
+		Data:       data,
+		Count:      count,
+		TotalCount: totalCount,
+	}, nil
+}
+
+func (c *Converter) OperationStatsToSummaryDTO(stats map[string]int) *orchestration.OperationsSummary {
+	return &orchestration.OperationsSummary{
+		Pending:    stats[orchestration.Pending],
+		InProgress: stats[orchestration.InProgress] + stats[orchestration.Canceling],
+		Succeeded:  stats[orchestration.Succeeded],
+		Failed:     stats[orchestration.Failed],
+		Canceled:   stats[orchestration.Canceled],
+	}
+}
+
 func (c *Converter) UpgradeKymaOperationToDetailDTO(op internal.UpgradeKymaOperation, kymaConfig gqlschema.KymaConfigInput, clusterConfig gqlschema.GardenerConfigInput) (orchestration.OperationDetailResponse, error) {
 	resp, err := c.UpgradeKymaOperationToDTO(op)
 	if err != nil {
@@ -88,3 +147,24 @@ func (c *Converter) UpgradeKymaOperationToDetailDTO(op internal.UpgradeKymaOpera
 		ClusterConfig:     clusterConfig,
 	}, nil
 }
+
+// operationError returns the description of the failed operation, which explains the failure
+func operationError(op internal.Operation) string {
+	if op.State != orchestration.Failed {
+		return ""
+	}
+	return op.Description
+}
+
+func operationFailureReason(op internal.Operation) *runtime.FailureReason {
+	if op.FailureReason == nil {
+		return nil
+	}
+	return &runtime.FailureReason{
+		Code:        op.FailureReason.Code,
+		Message:     op.FailureReason.Message,
+		Remediation: op.FailureReason.Remediation,
+		Retryable:   op.FailureReason.Retryable,
+		Subsystem:   op.FailureReason.Subsystem,
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrOrchestrationFinished is returned when the orchestration which should be force completed is already finished
+var ErrOrchestrationFinished = errors.New("orchestration is already finished")
+
+// ForceCompleteRequest holds the terminal state of the orchestration and the reason of the manual intervention
+type ForceCompleteRequest struct {
+	State  string `json:"state"`
+	Reason string `json:"reason"`
+}
+
+// ForceCompleter transitions an orchestration which is stuck in progress to the terminal state
+type ForceCompleter struct {
+	orchestrations storage.Orchestrations
+	operations     storage.Operations
+	log            logrus.FieldLogger
+}
+
+func NewForceCompleter(orchestrations storage.Orchestrations, operations storage.Operations, logger logrus.FieldLogger) *ForceCompleter {
+	return &ForceCompleter{
+		orchestrations: orchestrations,
+		operations:     operations,
+		log:            logger,
+	}
+}
+
+// ForceComplete sets the terminal state of the orchestration and cancels its pending operations.
+// The operations in progress are not interrupted, the orchestration manager stops waiting for them.
+func (c *ForceCompleter) ForceComplete(orchestrationID, state, reason string) (*internal.Orchestration, error) {
+	if state != orchestrationExt.Succeeded && state != orchestrationExt.Failed {
+		return nil, errors.Errorf("state %q is not supported, supported states: %s, %s", state, orchestrationExt.Succeeded, orchestrationExt.Failed)
+	}
+	o, err := c.orchestrations.GetByID(orchestrationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting orchestration")
+	}
+	if o.IsFinished() {
+		return nil, ErrOrchestrationFinished
+	}
+
+	if err := c.cancelPendingOperations(o); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Orchestration was manually completed as %s", state)
+	if reason != "" {
+		description = fmt.Sprintf("%s: %s", description, reason)
+	}
+	o.UpdatedAt = time.Now()
+	o.Description = description
+	o.State = state
+	err = c.orchestrations.Update(*o)
+	if err != nil {
+		return nil, errors.Wrap(err, "while updating orchestration")
+	}
+	c.log.Infof("Orchestration %s was manually completed as %s, reason: %s", orchestrationID, state, reason)
+
+	return o, nil
+}
+
+func (c *ForceCompleter) cancelPendingOperations(o *internal.Orchestration) error {
+	filter := dbmodel.OperationFilter{States: []string{orchestrationExt.Pending}}
+	description := "Operation was canceled, the orchestration was manually completed"
+
+	switch o.Type {
+	case orchestrationExt.UpgradeKymaOrchestration:
+		ops, _, _, err := c.operations.ListUpgradeKymaOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return errors.Wrap(err, "while listing upgrade kyma operations")
+		}
+		for _, op := range ops {
+			op.State = orchestrationExt.Canceled
+			op.Description = description
+			if _, err := c.operations.UpdateUpgradeKymaOperation(op); err != nil {
+				return errors.Wrap(err, "while updating upgrade kyma operation")
+			}
+		}
+	case orchestrationExt.UpgradeClusterOrchestration:
+		ops, _, _, err := c.operations.ListUpgradeClusterOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return errors.Wrap(err, "while listing upgrade cluster operations")
+		}
+		for _, op := range ops {
+			op.State = orchestrationExt.Canceled
+			op.Description = description
+			if _, err := c.operations.UpdateUpgradeClusterOperation(op); err != nil {
+				return errors.Wrap(err, "while updating upgrade cluster operation")
+			}
+		}
+	}
+
+	return nil
+}
+
+type forceCompleteHandler struct {
+	completer *ForceCompleter
+	converter Converter
+	log       logrus.FieldLogger
+}
+
+// NewForceCompleteHandler exposes the POST /admin/orchestrations/{orchestration_id}/force-complete endpoint
+func NewForceCompleteHandler(orchestrations storage.Orchestrations, operations storage.Operations, log logrus.FieldLogger) http.Handler {
+	h := &forceCompleteHandler{
+		completer: NewForceCompleter(orchestrations, operations, log),
+		converter: Converter{},
+		log:       log,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/orchestrations/{orchestration_id}/force-complete", h.forceComplete).Methods(http.MethodPost)
+
+	return router
+}
+
+func (h *forceCompleteHandler) forceComplete(w http.ResponseWriter, r *http.Request) {
+	orchestrationID := mux.Vars(r)["orchestration_id"]
+
+	var req ForceCompleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while decoding request body"))
+		return
+	}
+	if req.State != orchestrationExt.Succeeded && req.State != orchestrationExt.Failed {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Errorf("state must be %s or %s", orchestrationExt.Succeeded, orchestrationExt.Failed))
+		return
+	}
+
+	o, err := h.completer.ForceComplete(orchestrationID, req.State, req.Reason)
+	switch {
+	case errors.Cause(err) == ErrOrchestrationFinished:
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.Wrapf(err, "while force completing orchestration %s", orchestrationID))
+		return
+	case dberr.IsNotFound(errors.Cause(err)):
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "while force completing orchestration %s", orchestrationID))
+		return
+	case err != nil:
+		h.log.Errorf("while force completing orchestration %s: %v", orchestrationID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while force completing orchestration %s", orchestrationID))
+		return
+	}
+
+	response, err := h.converter.OrchestrationToDTO(o, nil)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while converting orchestration"))
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, response)
+}
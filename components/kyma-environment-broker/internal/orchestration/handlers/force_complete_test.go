@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForceCompleteHandler(t *testing.T) {
+	t.Run("should complete stuck orchestration and cancel pending operations", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		o := fixOrchestration()
+		o.Type = orchestration.UpgradeKymaOrchestration
+		require.NoError(t, s.Orchestrations().Insert(o))
+		pending := fixture.FixUpgradeKymaOperation("pending-op", "instance-1")
+		pending.OrchestrationID = fixOrchestrationID
+		pending.State = orchestration.Pending
+		require.NoError(t, s.Operations().InsertUpgradeKymaOperation(pending))
+		inProgress := fixture.FixUpgradeKymaOperation("in-progress-op", "instance-2")
+		inProgress.OrchestrationID = fixOrchestrationID
+		inProgress.State = orchestration.InProgress
+		require.NoError(t, s.Operations().InsertUpgradeKymaOperation(inProgress))
+
+		handler := NewForceCompleteHandler(s.Orchestrations(), s.Operations(), logrus.New())
+
+		// when
+		rr := forceComplete(t, handler, ForceCompleteRequest{State: orchestration.Failed, Reason: "operations stuck after outage"})
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out orchestration.StatusResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, orchestration.Failed, out.State)
+
+		gotOrchestration, err := s.Orchestrations().GetByID(fixOrchestrationID)
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.Failed, gotOrchestration.State)
+		assert.Equal(t, "Orchestration was manually completed as failed: operations stuck after outage", gotOrchestration.Description)
+
+		gotPending, err := s.Operations().GetUpgradeKymaOperationByID("pending-op")
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.Canceled, string(gotPending.State))
+		gotInProgress, err := s.Operations().GetUpgradeKymaOperationByID("in-progress-op")
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.InProgress, string(gotInProgress.State))
+	})
+
+	t.Run("should refuse finished orchestration", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		o := fixOrchestration()
+		o.State = orchestration.Succeeded
+		require.NoError(t, s.Orchestrations().Insert(o))
+
+		handler := NewForceCompleteHandler(s.Orchestrations(), s.Operations(), logrus.New())
+
+		// when
+		rr := forceComplete(t, handler, ForceCompleteRequest{State: orchestration.Failed})
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		gotOrchestration, err := s.Orchestrations().GetByID(fixOrchestrationID)
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.Succeeded, gotOrchestration.State)
+	})
+
+	t.Run("should refuse not terminal state", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		require.NoError(t, s.Orchestrations().Insert(fixOrchestration()))
+
+		handler := NewForceCompleteHandler(s.Orchestrations(), s.Operations(), logrus.New())
+
+		// when
+		rr := forceComplete(t, handler, ForceCompleteRequest{State: orchestration.Canceling})
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return not found for unknown orchestration", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		handler := NewForceCompleteHandler(s.Orchestrations(), s.Operations(), logrus.New())
+
+		// when
+		rr := forceComplete(t, handler, ForceCompleteRequest{State: orchestration.Succeeded})
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func forceComplete(t *testing.T, handler http.Handler, body ForceCompleteRequest) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/orchestrations/%s/force-complete", fixOrchestrationID), bytes.NewBuffer(payload))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
@@ -23,6 +23,7 @@ func NewOrchestrationHandler(db storage.BrokerStorage, kymaQueue *process.Queue,
 			NewKymaHandler(db.Orchestrations(), kymaQueue, log),
 			NewClusterHandler(db.Orchestrations(), clusterQueue, log),
 			NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), defaultMaxPage, log),
+			NewRetryHandler(db.Orchestrations(), db.Operations(), kymaQueue, clusterQueue, log),
 		},
 	}
 }
@@ -40,6 +41,38 @@ func validateTarget(spec orchestration.TargetSpec) error {
 	return nil
 }
 
+func validateNotifications(targets []orchestration.NotificationTarget) error {
+	for i, target := range targets {
+		switch target.Type {
+		case orchestration.WebhookNotification:
+			if target.URL == "" {
+				return errors.Errorf("notifications[%d].url must be set for the webhook target", i)
+			}
+		case orchestration.EmailNotification:
+			if target.Email == "" {
+				return errors.Errorf("notifications[%d].email must be set for the email target", i)
+			}
+		default:
+			return errors.Errorf("notifications[%d].type %q is not supported", i, target.Type)
+		}
+	}
+	return nil
+}
+
+func validateRetryPolicy(policy orchestration.RetryPolicy) error {
+	if policy.MaxAttempts < 0 {
+		return errors.New("retry.maxAttempts must not be negative")
+	}
+	delay, err := policy.DelayDuration()
+	if err != nil {
+		return errors.Wrap(err, "while parsing retry.delay")
+	}
+	if delay < 0 {
+		return errors.New("retry.delay must not be negative")
+	}
+	return nil
+}
+
 func defaultOrchestrationStrategy(spec *orchestration.StrategySpec) {
 	if spec.Parallel.Workers == 0 {
 		spec.Parallel.Workers = 1
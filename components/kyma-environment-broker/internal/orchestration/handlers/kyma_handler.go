@@ -66,6 +66,22 @@ func (h *kymaHandler) createOrchestration(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// validate notification targets
+	err = validateNotifications(params.Notifications)
+	if err != nil {
+		h.log.Errorf("while validating notifications: %v", err)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating notifications"))
+		return
+	}
+
+	// validate retry policy
+	err = validateRetryPolicy(params.Retry)
+	if err != nil {
+		h.log.Errorf("while validating retry policy: %v", err)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating retry policy"))
+		return
+	}
+
 	// validate Kyma version
 	err = h.ValidateKymaVersion(params.Kyma.Version)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
@@ -141,23 +142,48 @@ func (h *orchestrationHandler) listOperations(w http.ResponseWriter, r *http.Req
 		States: query[commonOrchestration.StateParam],
 	}
 
-	operations, count, totalCount, err := h.operations.ListUpgradeKymaOperationsByOrchestrationID(orchestrationID, filter)
+	o, err := h.orchestrations.GetByID(orchestrationID)
+	if err != nil {
+		h.log.Errorf("while getting orchestration %s: %v", orchestrationID, err)
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), errors.Wrapf(err, "while getting orchestration %s", orchestrationID))
+		return
+	}
+
+	response, err := h.listOperationsForOrchestration(o, filter)
 	if err != nil {
 		h.log.Errorf("while getting operations: %v", err)
 		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while getting operations"))
 		return
 	}
 
-	response, err := h.converter.UpgradeKymaOperationListToDTO(operations, count, totalCount)
+	stats, err := h.operations.GetOperationStatsForOrchestration(orchestrationID)
 	if err != nil {
-		h.log.Errorf("while converting operations: %v", err)
-		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while converting operations"))
+		h.log.Errorf("while getting orchestration %s operation statistics: %v", orchestrationID, err)
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), errors.Wrapf(err, "while getting orchestration %s operation stats", orchestrationID))
 		return
 	}
+	response.Summary = h.converter.OperationStatsToSummaryDTO(stats)
 
 	httputil.WriteResponse(w, http.StatusOK, response)
 }
 
+func (h *orchestrationHandler) listOperationsForOrchestration(o *internal.Orchestration, filter dbmodel.OperationFilter) (commonOrchestration.OperationResponseList, error) {
+	switch o.Type {
+	case commonOrchestration.UpgradeClusterOrchestration:
+		operations, count, totalCount, err := h.operations.ListUpgradeClusterOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return commonOrchestration.OperationResponseList{}, err
+		}
+		return h.converter.UpgradeClusterOperationListToDTO(operations, count, totalCount)
+	default:
+		operations, count, totalCount, err := h.operations.ListUpgradeKymaOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return commonOrchestration.OperationResponseList{}, err
+		}
+		return h.converter.UpgradeKymaOperationListToDTO(operations, count, totalCount)
+	}
+}
+
 func (h *orchestrationHandler) getOperation(w http.ResponseWriter, r *http.Request) {
 	operationID := mux.Vars(r)["operation_id"]
 
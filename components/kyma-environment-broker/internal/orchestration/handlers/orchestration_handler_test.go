@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
@@ -190,6 +191,95 @@ func TestStatusHandler_AttachRoutes(t *testing.T) {
 		assert.Equal(t, dto.OperationID, fixID)
 	})
 
+	t.Run("operations of mixed-status cluster orchestration", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+
+		err := db.Orchestrations().Insert(internal.Orchestration{OrchestrationID: fixID, Type: orchestration.UpgradeClusterOrchestration})
+		require.NoError(t, err)
+		for i, state := range []domain.LastOperationState{domain.Succeeded, domain.Succeeded, domain.Failed, domain.InProgress, orchestration.Pending} {
+			id := fmt.Sprintf("op-%d", i)
+			err = db.Operations().InsertUpgradeClusterOperation(internal.UpgradeClusterOperation{
+				Operation: internal.Operation{
+					ID:              id,
+					InstanceID:      id,
+					OrchestrationID: fixID,
+					State:           state,
+					Description:     fmt.Sprintf("operation %s", state),
+					CurrentStep:     "Upgrade_Cluster",
+					CreatedAt:       time.Now().Add(time.Duration(i) * time.Minute),
+				},
+				RuntimeOperation: orchestration.RuntimeOperation{
+					Runtime: orchestration.Runtime{RuntimeID: id},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		logs := logrus.New()
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), 100, logs)
+		router := mux.NewRouter()
+		kymaHandler.AttachRoutes(router)
+
+		urlPath := fmt.Sprintf("/orchestrations/%s/operations?page=1&page_size=2", fixID)
+		req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var out orchestration.OperationResponseList
+		err = json.Unmarshal(rr.Body.Bytes(), &out)
+		require.NoError(t, err)
+		assert.Equal(t, 2, out.Count)
+		assert.Equal(t, 5, out.TotalCount)
+		require.NotNil(t, out.Summary)
+		assert.Equal(t, orchestration.OperationsSummary{Pending: 1, InProgress: 1, Succeeded: 2, Failed: 1}, *out.Summary)
+
+		// given
+		urlPath = fmt.Sprintf("/orchestrations/%s/operations?state=%s", fixID, orchestration.Failed)
+		req, err = http.NewRequest(http.MethodGet, urlPath, nil)
+		require.NoError(t, err)
+		rr = httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		out = orchestration.OperationResponseList{}
+		err = json.Unmarshal(rr.Body.Bytes(), &out)
+		require.NoError(t, err)
+		require.Len(t, out.Data, 1)
+		assert.Equal(t, "op-2", out.Data[0].RuntimeID)
+		assert.Equal(t, orchestration.Failed, out.Data[0].State)
+		assert.Equal(t, "Upgrade_Cluster", out.Data[0].CurrentStep)
+		assert.Equal(t, "operation failed", out.Data[0].Error)
+	})
+
+	t.Run("operations of not existing orchestration", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), 100, logrus.New())
+		router := mux.NewRouter()
+		kymaHandler.AttachRoutes(router)
+
+		req, err := http.NewRequest(http.MethodGet, "/orchestrations/not-existing/operations", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
 	t.Run("cancel orchestration", func(t *testing.T) {
 		// given
 		db := storage.NewMemoryStorage()
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrOrchestrationNotFinished is returned when the failed runtimes of the orchestration in progress should be retried
+	ErrOrchestrationNotFinished = errors.New("orchestration is not finished")
+	// ErrNoFailedRuntimes is returned when the orchestration which should be retried has no failed operations
+	ErrNoFailedRuntimes = errors.New("orchestration has no failed runtimes")
+)
+
+// Retrier creates the orchestration which repeats the failed operations of the finished orchestration
+type Retrier struct {
+	orchestrations storage.Orchestrations
+	operations     storage.Operations
+	log            logrus.FieldLogger
+}
+
+func NewRetrier(orchestrations storage.Orchestrations, operations storage.Operations, logger logrus.FieldLogger) *Retrier {
+	return &Retrier{
+		orchestrations: orchestrations,
+		operations:     operations,
+		log:            logger,
+	}
+}
+
+// RetryFailed stores the new orchestration of the same type, parameters and strategy as the original one,
+// which targets exactly the runtimes whose last operations failed in the original orchestration
+func (r *Retrier) RetryFailed(orchestrationID string) (*internal.Orchestration, error) {
+	o, err := r.orchestrations.GetByID(orchestrationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting orchestration")
+	}
+	if !o.IsFinished() {
+		return nil, ErrOrchestrationNotFinished
+	}
+
+	runtimeIDs, err := r.failedRuntimeIDs(o)
+	if err != nil {
+		return nil, err
+	}
+	if len(runtimeIDs) == 0 {
+		return nil, ErrNoFailedRuntimes
+	}
+
+	params := o.Parameters
+	params.Targets = orchestrationExt.TargetSpec{}
+	for _, id := range runtimeIDs {
+		params.Targets.Include = append(params.Targets.Include, orchestrationExt.RuntimeTarget{RuntimeID: id})
+	}
+	params.RetryOf = o.OrchestrationID
+
+	now := time.Now()
+	retry := internal.Orchestration{
+		OrchestrationID: uuid.New().String(),
+		Type:            o.Type,
+		State:           orchestrationExt.Pending,
+		Description:     fmt.Sprintf("queued for processing, retries failed runtimes of orchestration %s", o.OrchestrationID),
+		Parameters:      params,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = r.orchestrations.Insert(retry)
+	if err != nil {
+		return nil, errors.Wrap(err, "while inserting orchestration")
+	}
+	r.log.Infof("Orchestration %s retries %d failed runtimes of orchestration %s", retry.OrchestrationID, len(runtimeIDs), o.OrchestrationID)
+
+	return &retry, nil
+}
+
+func (r *Retrier) failedRuntimeIDs(o *internal.Orchestration) ([]string, error) {
+	filter := dbmodel.OperationFilter{States: []string{orchestrationExt.Failed}}
+	unique := map[string]struct{}{}
+
+	switch o.Type {
+	case orchestrationExt.UpgradeKymaOrchestration:
+		ops, _, _, err := r.operations.ListUpgradeKymaOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return nil, errors.Wrap(err, "while listing upgrade kyma operations")
+		}
+		for _, op := range ops {
+			if op.RuntimeOperation.RetriedBy == "" {
+				unique[op.RuntimeOperation.RuntimeID] = struct{}{}
+			}
+		}
+	case orchestrationExt.UpgradeClusterOrchestration:
+		ops, _, _, err := r.operations.ListUpgradeClusterOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return nil, errors.Wrap(err, "while listing upgrade cluster operations")
+		}
+		for _, op := range ops {
+			if op.RuntimeOperation.RetriedBy == "" {
+				unique[op.RuntimeOperation.RuntimeID] = struct{}{}
+			}
+		}
+	default:
+		return nil, errors.Errorf("orchestration type %q is not supported", o.Type)
+	}
+
+	runtimeIDs := make([]string, 0, len(unique))
+	for id := range unique {
+		runtimeIDs = append(runtimeIDs, id)
+	}
+	sort.Strings(runtimeIDs)
+	return runtimeIDs, nil
+}
+
+type retryHandler struct {
+	retrier      *Retrier
+	kymaQueue    *process.Queue
+	clusterQueue *process.Queue
+	log          logrus.FieldLogger
+}
+
+// NewRetryHandler exposes the POST /orchestrations/{orchestration_id}/retry-failed endpoint
+func NewRetryHandler(orchestrations storage.Orchestrations, operations storage.Operations, kymaQueue *process.Queue, clusterQueue *process.Queue, log logrus.FieldLogger) *retryHandler {
+	return &retryHandler{
+		retrier:      NewRetrier(orchestrations, operations, log),
+		kymaQueue:    kymaQueue,
+		clusterQueue: clusterQueue,
+		log:          log,
+	}
+}
+
+func (h *retryHandler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/orchestrations/{orchestration_id}/retry-failed", h.retryFailed).Methods(http.MethodPost)
+}
+
+func (h *retryHandler) retryFailed(w http.ResponseWriter, r *http.Request) {
+	orchestrationID := mux.Vars(r)["orchestration_id"]
+
+	o, err := h.retrier.RetryFailed(orchestrationID)
+	switch {
+	case errors.Cause(err) == ErrOrchestrationNotFinished, errors.Cause(err) == ErrNoFailedRuntimes:
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.Wrapf(err, "while retrying orchestration %s", orchestrationID))
+		return
+	case dberr.IsNotFound(errors.Cause(err)):
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "while retrying orchestration %s", orchestrationID))
+		return
+	case err != nil:
+		h.log.Errorf("while retrying orchestration %s: %v", orchestrationID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while retrying orchestration %s", orchestrationID))
+		return
+	}
+
+	switch o.Type {
+	case orchestrationExt.UpgradeClusterOrchestration:
+		h.clusterQueue.Add(o.OrchestrationID)
+	default:
+		h.kymaQueue.Add(o.OrchestrationID)
+	}
+
+	httputil.WriteResponse(w, http.StatusAccepted, orchestrationExt.UpgradeResponse{OrchestrationID: o.OrchestrationID})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryHandler(t *testing.T) {
+	t.Run("should schedule orchestration targeting only failed runtimes", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		o := fixOrchestration()
+		o.Type = orchestration.UpgradeKymaOrchestration
+		o.State = orchestration.Failed
+		o.Parameters = orchestration.Parameters{
+			Targets:  orchestration.TargetSpec{Include: []orchestration.RuntimeTarget{{Target: orchestration.TargetAll}}},
+			Strategy: orchestration.StrategySpec{Type: orchestration.ParallelStrategy, Schedule: orchestration.MaintenanceWindow, Parallel: orchestration.ParallelStrategySpec{Workers: 3}},
+			Kyma:     orchestration.KymaParameters{Version: "1.22.0"},
+		}
+		require.NoError(t, s.Orchestrations().Insert(o))
+		for id, state := range map[string]string{
+			"runtime-1": orchestration.Failed,
+			"runtime-2": orchestration.Succeeded,
+			"runtime-3": orchestration.Failed,
+			"runtime-4": orchestration.Canceled,
+		} {
+			op := fixture.FixUpgradeKymaOperation(fmt.Sprintf("op-%s", id), fmt.Sprintf("instance-%s", id))
+			op.OrchestrationID = fixOrchestrationID
+			op.State = domain.LastOperationState(state)
+			op.RuntimeOperation.RuntimeID = id
+			require.NoError(t, s.Operations().InsertUpgradeKymaOperation(op))
+		}
+		executor := &recordingExecutor{executed: make(chan string, 1)}
+		kymaQueue := process.NewQueue(executor, logrus.New())
+		handler := NewRetryHandler(s.Orchestrations(), s.Operations(), kymaQueue, process.NewQueue(&testExecutor{}, logrus.New()), logrus.New())
+
+		// when
+		rr := retryFailed(handler)
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		var out orchestration.UpgradeResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.NotEqual(t, fixOrchestrationID, out.OrchestrationID)
+
+		retry, err := s.Orchestrations().GetByID(out.OrchestrationID)
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.UpgradeKymaOrchestration, retry.Type)
+		assert.Equal(t, orchestration.Pending, retry.State)
+		assert.Equal(t, fixOrchestrationID, retry.Parameters.RetryOf)
+		assert.Equal(t, []orchestration.RuntimeTarget{{RuntimeID: "runtime-1"}, {RuntimeID: "runtime-3"}}, retry.Parameters.Targets.Include)
+		assert.Empty(t, retry.Parameters.Targets.Exclude)
+		assert.Equal(t, o.Parameters.Strategy, retry.Parameters.Strategy)
+		assert.Equal(t, o.Parameters.Kyma, retry.Parameters.Kyma)
+
+		stop := make(chan struct{})
+		defer close(stop)
+		kymaQueue.Run(stop, 1)
+		select {
+		case id := <-executor.executed:
+			assert.Equal(t, out.OrchestrationID, id)
+		case <-time.After(time.Second):
+			t.Fatal("orchestration was not queued")
+		}
+	})
+
+	t.Run("should refuse orchestration in progress", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		require.NoError(t, s.Orchestrations().Insert(fixOrchestration()))
+		handler := NewRetryHandler(s.Orchestrations(), s.Operations(), process.NewQueue(&testExecutor{}, logrus.New()), process.NewQueue(&testExecutor{}, logrus.New()), logrus.New())
+
+		// when
+		rr := retryFailed(handler)
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should refuse orchestration without failed runtimes", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		o := fixOrchestration()
+		o.Type = orchestration.UpgradeClusterOrchestration
+		o.State = orchestration.Succeeded
+		require.NoError(t, s.Orchestrations().Insert(o))
+		op := fixture.FixUpgradeClusterOperation("op-1", "instance-1")
+		op.OrchestrationID = fixOrchestrationID
+		op.State = orchestration.Succeeded
+		require.NoError(t, s.Operations().InsertUpgradeClusterOperation(op))
+		handler := NewRetryHandler(s.Orchestrations(), s.Operations(), process.NewQueue(&testExecutor{}, logrus.New()), process.NewQueue(&testExecutor{}, logrus.New()), logrus.New())
+
+		// when
+		rr := retryFailed(handler)
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should return not found for unknown orchestration", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		handler := NewRetryHandler(s.Orchestrations(), s.Operations(), process.NewQueue(&testExecutor{}, logrus.New()), process.NewQueue(&testExecutor{}, logrus.New()), logrus.New())
+
+		// when
+		rr := retryFailed(handler)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func retryFailed(handler Handler) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/orchestrations/%s/retry-failed", fixOrchestrationID), nil)
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	handler.AttachRoutes(router)
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+type recordingExecutor struct {
+	executed chan string
+}
+
+func (e *recordingExecutor) Execute(id string) (time.Duration, error) {
+	e.executed <- id
+	return 0, nil
+}
@@ -1,12 +1,16 @@
 package manager
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration/strategies"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pkg/errors"
@@ -18,6 +22,12 @@ type OperationFactory interface {
 	NewOperation(o internal.Orchestration, r orchestration.Runtime, i internal.Instance) (orchestration.RuntimeOperation, error)
 	ResumeOperations(orchestrationID string) ([]orchestration.RuntimeOperation, error)
 	CancelOperations(orchestrationID string) error
+	// FailedOperations returns the failed operations of the orchestration which were not run again
+	FailedOperations(orchestrationID string) ([]orchestration.RuntimeOperation, error)
+	// RetryOperation stores the new operation which runs the failed operation again and links the failed operation to it
+	RetryOperation(o internal.Orchestration, failed orchestration.RuntimeOperation, i internal.Instance) (orchestration.RuntimeOperation, error)
+	// Quarantine marks the failed operation which is not run again anymore as quarantined
+	Quarantine(failed orchestration.RuntimeOperation, reason string) error
 }
 
 type orchestrationManager struct {
@@ -27,6 +37,7 @@ type orchestrationManager struct {
 	resolver             orchestration.RuntimeResolver
 	factory              OperationFactory
 	executor             orchestration.OperationExecutor
+	publisher            event.Publisher
 	log                  logrus.FieldLogger
 	pollingInterval      time.Duration
 }
@@ -39,6 +50,8 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		return m.failOrchestration(o, errors.Wrap(err, "while getting orchestration"))
 	}
 
+	finished := o.IsFinished()
+	scheduled := o.State == orchestration.Pending
 	operations, err := m.resolveOperations(o)
 	if err != nil {
 		return m.failOrchestration(o, errors.Wrap(err, "while resolving operations"))
@@ -49,9 +62,18 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		logger.Errorf("while updating orchestration: %v", err)
 		return m.pollingInterval, nil
 	}
+	if scheduled {
+		m.publisher.Publish(context.TODO(), process.OrchestrationStarted{
+			Orchestration: *o,
+		})
+	}
 	// do not perform any action if the orchestration is finished
 	if o.IsFinished() {
 		m.log.Infof("Orchestration was already finished, state: %s", o.State)
+		// the orchestration without runtimes finishes when its operations are resolved
+		if !finished {
+			m.publishFinished(o)
+		}
 		return 0, nil
 	}
 
@@ -74,9 +96,26 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 	}
 
 	logger.Infof("Finished processing orchestration, state: %s", o.State)
+	m.publishFinished(o)
 	return 0, nil
 }
 
+// publishFinished notifies subscribers (e.g. report exporter) about the finished orchestration, it is published once
+// when the orchestration gets its terminal state. Handlers are executed asynchronously so they do not block the orchestration processing
+func (m *orchestrationManager) publishFinished(o *internal.Orchestration) {
+	m.publisher.Publish(context.TODO(), process.OrchestrationFinished{
+		Orchestration: *o,
+	})
+}
+
+// publishProgressed notifies subscribers (e.g. notifier) about the changed number of operations in each state
+func (m *orchestrationManager) publishProgressed(o *internal.Orchestration, stats map[string]int) {
+	m.publisher.Publish(context.TODO(), process.OrchestrationProgressed{
+		Orchestration: *o,
+		Stats:         stats,
+	})
+}
+
 func (m *orchestrationManager) resolveOperations(o *internal.Orchestration) ([]orchestration.RuntimeOperation, error) {
 	result := []orchestration.RuntimeOperation{}
 	if o.State == orchestration.Pending {
@@ -137,10 +176,11 @@ func (m *orchestrationManager) resolveStrategy(sType orchestration.StrategyType,
 // waitForCompletion waits until processing of given orchestration ends or if it's canceled
 func (m *orchestrationManager) waitForCompletion(o *internal.Orchestration, strategy orchestration.Strategy, execID string, log logrus.FieldLogger) (*internal.Orchestration, error) {
 	canceled := false
+	completed := false
 	var err error
 	var stats map[string]int
 	err = wait.PollImmediateInfinite(m.pollingInterval, func() (bool, error) {
-		// check if orchestration wasn't canceled
+		// check if orchestration wasn't canceled or completed manually
 		o, err = m.orchestrationStorage.GetByID(o.OrchestrationID)
 		switch {
 		case err == nil:
@@ -148,6 +188,11 @@ func (m *orchestrationManager) waitForCompletion(o *internal.Orchestration, stra
 				log.Info("Orchestration was canceled")
 				canceled = true
 			}
+			if o.IsFinished() {
+				log.Infof("Orchestration was completed manually, state: %s", o.State)
+				completed = true
+				return true, nil
+			}
 		case dberr.IsNotFound(err):
 			log.Errorf("while getting orchestration: %v", err)
 			return false, err
@@ -160,6 +205,9 @@ func (m *orchestrationManager) waitForCompletion(o *internal.Orchestration, stra
 			log.Errorf("while getting operations: %v", err)
 			return false, nil
 		}
+		if stats != nil && !reflect.DeepEqual(stats, s) {
+			m.publishProgressed(o, s)
+		}
 		stats = s
 
 		numberOfNotFinished := 0
@@ -182,9 +230,108 @@ func (m *orchestrationManager) waitForCompletion(o *internal.Orchestration, stra
 	if err != nil {
 		return nil, errors.Wrap(err, "while waiting for scheduled operations to finish")
 	}
+	// the state set by the manual completion is kept, the operations which were not scheduled yet are dropped
+	if completed {
+		strategy.Cancel(execID)
+		return o, nil
+	}
+
+	if o.State != orchestration.Canceling && stats[orchestration.Failed] > 0 {
+		retries, err := m.retryFailedOperations(o, log)
+		if err != nil {
+			return nil, errors.Wrap(err, "while retrying failed operations")
+		}
+		if len(retries) > 0 {
+			// the retries are scheduled by their maintenance window which starts after the delay of the retry policy
+			spec := o.Parameters.Strategy
+			spec.Schedule = orchestration.MaintenanceWindow
+			execID, err = strategy.Execute(retries, spec)
+			if err != nil {
+				return nil, errors.Wrap(err, "while executing retried operations")
+			}
+			return m.waitForCompletion(o, strategy, execID, log)
+		}
+	}
 
 	return m.resolveOrchestration(o, strategy, execID, stats)
 }
+
+// retryFailedOperations creates the next attempts of the failed operations according to the retry policy of the orchestration.
+// The operation of the runtime whose attempts failed as many times as the quarantine threshold of the strategy is quarantined,
+// the quarantined operations and the operations which exhausted their attempts are not run again
+func (m *orchestrationManager) retryFailedOperations(o *internal.Orchestration, log logrus.FieldLogger) ([]orchestration.RuntimeOperation, error) {
+	policy := o.Parameters.Retry
+	quarantineAfter := o.Parameters.Strategy.QuarantineAfter
+	if policy.MaxAttempts <= 1 && quarantineAfter <= 0 {
+		return nil, nil
+	}
+	delay, err := policy.DelayDuration()
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing retry delay %q", policy.Delay)
+	}
+	failed, err := m.factory.FailedOperations(o.OrchestrationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing failed operations")
+	}
+
+	retries := make([]orchestration.RuntimeOperation, 0)
+	for _, op := range failed {
+		if op.Quarantined {
+			continue
+		}
+		// every attempt is run by a new operation, so the attempt number of the failed operation is the number of failed attempts
+		if quarantineAfter > 0 && attempt(op) >= quarantineAfter {
+			log.Errorf("Operation %s of runtime %s failed %d times, quarantining it", op.ID, op.RuntimeID, attempt(op))
+			if err := m.factory.Quarantine(op, fmt.Sprintf("operation quarantined after %d failed attempts", attempt(op))); err != nil {
+				return nil, errors.Wrapf(err, "while quarantining operation %s", op.ID)
+			}
+			continue
+		}
+		if attempt(op) >= policy.MaxAttempts {
+			continue
+		}
+		inst, err := m.instanceStorage.GetByID(op.InstanceID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while getting instance %s", op.InstanceID)
+		}
+		op.MaintenanceWindowBegin, op.MaintenanceWindowEnd = m.resolveRetryWindow(o, op.Runtime, delay)
+		retry, err := m.factory.RetryOperation(*o, op, *inst)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while retrying operation %s", op.ID)
+		}
+		log.Infof("Operation %s of runtime %s failed, attempt %d of %d is operation %s", op.ID, op.RuntimeID, retry.Attempt, policy.MaxAttempts, retry.ID)
+		retries = append(retries, retry)
+	}
+	return retries, nil
+}
+
+// resolveRetryWindow returns the window in which the retried operation starts, not earlier than after the delay.
+// The retry which does not fit into the current maintenance window of the runtime waits for the next one.
+func (m *orchestrationManager) resolveRetryWindow(o *internal.Orchestration, r orchestration.Runtime, delay time.Duration) (time.Time, time.Time) {
+	start := time.Now().Add(delay)
+	begin, end := r.MaintenanceWindowBegin, r.MaintenanceWindowEnd
+	if o.Parameters.Strategy.Schedule != orchestration.MaintenanceWindow || end.IsZero() {
+		return start, time.Time{}
+	}
+
+	for !end.After(start) {
+		begin = begin.AddDate(0, 0, 1)
+		end = end.AddDate(0, 0, 1)
+	}
+	if begin.Before(start) {
+		begin = start
+	}
+	return begin, end
+}
+
+// attempt returns the number of the operation run for the runtime, the operations created before the attempts were counted are the first ones
+func attempt(op orchestration.RuntimeOperation) int {
+	if op.Attempt == 0 {
+		return 1
+	}
+	return op.Attempt
+}
+
 func (m *orchestrationManager) resolveOrchestration(o *internal.Orchestration, strategy orchestration.Strategy, execID string, stats map[string]int) (*internal.Orchestration, error) {
 	if o.State == orchestration.Canceling {
 		err := m.factory.CancelOperations(o.OrchestrationID)
@@ -196,7 +343,14 @@ func (m *orchestrationManager) resolveOrchestration(o *internal.Orchestration, s
 	} else {
 		state := orchestration.Succeeded
 		if stats[orchestration.Failed] > 0 {
-			state = orchestration.Failed
+			// the failed operations which were run again do not fail the orchestration, the result of the last attempt counts
+			failed, err := m.factory.FailedOperations(o.OrchestrationID)
+			if err != nil {
+				return nil, errors.Wrap(err, "while listing failed operations")
+			}
+			if len(failed) > 0 {
+				state = orchestration.Failed
+			}
 		}
 		o.State = state
 	}
@@ -225,7 +379,11 @@ func (m *orchestrationManager) resolveWindowTime(beginTime, endTime time.Time) (
 
 func (m *orchestrationManager) failOrchestration(o *internal.Orchestration, err error) (time.Duration, error) {
 	m.log.Errorf("orchestration %s failed: %s", o.OrchestrationID, err)
-	return m.updateOrchestration(o, orchestration.Failed, err.Error()), nil
+	repeat := m.updateOrchestration(o, orchestration.Failed, err.Error())
+	if repeat == 0 {
+		m.publishFinished(o)
+	}
+	return repeat, nil
 }
 
 func (m *orchestrationManager) updateOrchestration(o *internal.Orchestration, state, description string) time.Duration {
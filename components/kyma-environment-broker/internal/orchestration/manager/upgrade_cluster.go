@@ -1,11 +1,13 @@
 package manager
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -19,7 +21,7 @@ type upgradeClusterFactory struct {
 
 func NewUpgradeClusterManager(orchestrationStorage storage.Orchestrations, operationStorage storage.Operations, instanceStorage storage.Instances,
 	kymaClusterExecutor orchestration.OperationExecutor, resolver orchestration.RuntimeResolver,
-	pollingInterval time.Duration, log logrus.FieldLogger) process.Executor {
+	pollingInterval time.Duration, pub event.Publisher, log logrus.FieldLogger) process.Executor {
 	return &orchestrationManager{
 		orchestrationStorage: orchestrationStorage,
 		operationStorage:     operationStorage,
@@ -30,11 +32,16 @@ func NewUpgradeClusterManager(orchestrationStorage storage.Orchestrations, opera
 		},
 		executor:        kymaClusterExecutor,
 		pollingInterval: pollingInterval,
+		publisher:       pub,
 		log:             log,
 	}
 }
 
 func (u *upgradeClusterFactory) NewOperation(o internal.Orchestration, r orchestration.Runtime, i internal.Instance) (orchestration.RuntimeOperation, error) {
+	return u.newOperation(o, r, i, 1)
+}
+
+func (u *upgradeClusterFactory) newOperation(o internal.Orchestration, r orchestration.Runtime, i internal.Instance, attempt int) (orchestration.RuntimeOperation, error) {
 	id := uuid.New().String()
 	op := internal.UpgradeClusterOperation{
 		Operation: internal.Operation{
@@ -54,7 +61,9 @@ func (u *upgradeClusterFactory) NewOperation(o internal.Orchestration, r orchest
 			ID:      id,
 			Runtime: r,
 			DryRun:  o.Parameters.DryRun,
+			Attempt: attempt,
 		},
+		Drain: o.Parameters.Cluster.Drain,
 	}
 
 	err := u.operationStorage.InsertUpgradeClusterOperation(op)
@@ -81,6 +90,55 @@ func (u *upgradeClusterFactory) ResumeOperations(orchestrationID string) ([]orch
 	return append(inProgress, pending...), nil
 }
 
+func (u *upgradeClusterFactory) FailedOperations(orchestrationID string) ([]orchestration.RuntimeOperation, error) {
+	ops, _, _, err := u.operationStorage.ListUpgradeClusterOperationsByOrchestrationID(orchestrationID, dbmodel.OperationFilter{States: []string{orchestration.Failed}})
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing failed upgrade operations")
+	}
+
+	failed := make([]orchestration.RuntimeOperation, 0)
+	for _, op := range ops {
+		if op.RuntimeOperation.RetriedBy == "" {
+			failed = append(failed, op.RuntimeOperation)
+		}
+	}
+	return failed, nil
+}
+
+func (u *upgradeClusterFactory) RetryOperation(o internal.Orchestration, failed orchestration.RuntimeOperation, i internal.Instance) (orchestration.RuntimeOperation, error) {
+	op, err := u.operationStorage.GetUpgradeClusterOperationByID(failed.ID)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while getting failed upgrade operation")
+	}
+
+	retry, err := u.newOperation(o, failed.Runtime, i, attempt(failed)+1)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while inserting upgrade operation")
+	}
+
+	op.RuntimeOperation.RetriedBy = retry.ID
+	_, err = u.operationStorage.UpdateUpgradeClusterOperation(*op)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while updating failed upgrade operation")
+	}
+	return retry, nil
+}
+
+func (u *upgradeClusterFactory) Quarantine(failed orchestration.RuntimeOperation, reason string) error {
+	op, err := u.operationStorage.GetUpgradeClusterOperationByID(failed.ID)
+	if err != nil {
+		return errors.Wrap(err, "while getting failed upgrade operation")
+	}
+
+	op.RuntimeOperation.Quarantined = true
+	op.Description = fmt.Sprintf("%s, last error: %s", reason, op.Description)
+	_, err = u.operationStorage.UpdateUpgradeClusterOperation(*op)
+	if err != nil {
+		return errors.Wrap(err, "while updating failed upgrade operation")
+	}
+	return nil
+}
+
 func (u *upgradeClusterFactory) CancelOperations(orchestrationID string) error {
 	ops, _, _, err := u.operationStorage.ListUpgradeClusterOperationsByOrchestrationID(orchestrationID, dbmodel.OperationFilter{States: []string{orchestration.Pending}})
 	if err != nil {
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
@@ -8,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -22,7 +24,7 @@ type upgradeKymaFactory struct {
 
 func NewUpgradeKymaManager(orchestrationStorage storage.Orchestrations, operationStorage storage.Operations, instanceStorage storage.Instances,
 	kymaUpgradeExecutor orchestration.OperationExecutor, resolver orchestration.RuntimeResolver,
-	pollingInterval time.Duration, smcf *servicemanager.ClientFactory, log logrus.FieldLogger) process.Executor {
+	pollingInterval time.Duration, smcf *servicemanager.ClientFactory, pub event.Publisher, log logrus.FieldLogger) process.Executor {
 	return &orchestrationManager{
 		orchestrationStorage: orchestrationStorage,
 		operationStorage:     operationStorage,
@@ -34,11 +36,16 @@ func NewUpgradeKymaManager(orchestrationStorage storage.Orchestrations, operatio
 		},
 		executor:        kymaUpgradeExecutor,
 		pollingInterval: pollingInterval,
+		publisher:       pub,
 		log:             log,
 	}
 }
 
 func (u *upgradeKymaFactory) NewOperation(o internal.Orchestration, r orchestration.Runtime, i internal.Instance) (orchestration.RuntimeOperation, error) {
+	return u.newOperation(o, r, i, 1)
+}
+
+func (u *upgradeKymaFactory) newOperation(o internal.Orchestration, r orchestration.Runtime, i internal.Instance, attempt int) (orchestration.RuntimeOperation, error) {
 	id := uuid.New().String()
 	op := internal.UpgradeKymaOperation{
 		Operation: internal.Operation{
@@ -58,6 +65,7 @@ func (u *upgradeKymaFactory) NewOperation(o internal.Orchestration, r orchestrat
 			ID:      id,
 			Runtime: r,
 			DryRun:  o.Parameters.DryRun,
+			Attempt: attempt,
 		},
 		SMClientFactory: u.smcf,
 	}
@@ -89,6 +97,55 @@ func (u *upgradeKymaFactory) ResumeOperations(orchestrationID string) ([]orchest
 	return append(inProgress, pending...), nil
 }
 
+func (u *upgradeKymaFactory) FailedOperations(orchestrationID string) ([]orchestration.RuntimeOperation, error) {
+	ops, _, _, err := u.operationStorage.ListUpgradeKymaOperationsByOrchestrationID(orchestrationID, dbmodel.OperationFilter{States: []string{orchestration.Failed}})
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing failed upgrade operations")
+	}
+
+	failed := make([]orchestration.RuntimeOperation, 0)
+	for _, op := range ops {
+		if op.RuntimeOperation.RetriedBy == "" {
+			failed = append(failed, op.RuntimeOperation)
+		}
+	}
+	return failed, nil
+}
+
+func (u *upgradeKymaFactory) RetryOperation(o internal.Orchestration, failed orchestration.RuntimeOperation, i internal.Instance) (orchestration.RuntimeOperation, error) {
+	op, err := u.operationStorage.GetUpgradeKymaOperationByID(failed.ID)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while getting failed upgrade operation")
+	}
+
+	retry, err := u.newOperation(o, failed.Runtime, i, attempt(failed)+1)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while inserting upgrade operation")
+	}
+
+	op.RuntimeOperation.RetriedBy = retry.ID
+	_, err = u.operationStorage.UpdateUpgradeKymaOperation(*op)
+	if err != nil {
+		return orchestration.RuntimeOperation{}, errors.Wrap(err, "while updating failed upgrade operation")
+	}
+	return retry, nil
+}
+
+func (u *upgradeKymaFactory) Quarantine(failed orchestration.RuntimeOperation, reason string) error {
+	op, err := u.operationStorage.GetUpgradeKymaOperationByID(failed.ID)
+	if err != nil {
+		return errors.Wrap(err, "while getting failed upgrade operation")
+	}
+
+	op.RuntimeOperation.Quarantined = true
+	op.Description = fmt.Sprintf("%s, last error: %s", reason, op.Description)
+	_, err = u.operationStorage.UpdateUpgradeKymaOperation(*op)
+	if err != nil {
+		return errors.Wrap(err, "while updating failed upgrade operation")
+	}
+	return nil
+}
+
 func (u *upgradeKymaFactory) CancelOperations(orchestrationID string) error {
 	ops, _, _, err := u.operationStorage.ListUpgradeKymaOperationsByOrchestrationID(orchestrationID, dbmodel.OperationFilter{States: []string{orchestration.Pending}})
 	if err != nil {
@@ -1,6 +1,8 @@
 package manager_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,8 +11,11 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration/automock"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/manager"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -34,7 +39,7 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 		err := store.Orchestrations().Insert(internal.Orchestration{OrchestrationID: id, State: orchestration.Pending})
 		require.NoError(t, err)
 
-		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, 20*time.Millisecond, nil, logrus.New())
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, 20*time.Millisecond, nil, event.NewPubSub(logrus.New()), logrus.New())
 
 		// when
 		_, err = svc.Execute(id)
@@ -45,6 +50,114 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 
 		assert.Equal(t, orchestration.Succeeded, o.State)
 	})
+	t.Run("PublishesLifecycleEvents", func(t *testing.T) {
+		// given
+		store := storage.NewMemoryStorage()
+
+		resolver := &automock.RuntimeResolver{}
+		defer resolver.AssertExpectations(t)
+		resolver.On("Resolve", orchestration.TargetSpec{}).Return([]orchestration.Runtime{}, nil)
+
+		id := "id"
+		err := store.Orchestrations().Insert(internal.Orchestration{OrchestrationID: id, State: orchestration.Pending})
+		require.NoError(t, err)
+
+		pubSub := event.NewPubSub(logrus.New())
+		events := make(chan interface{}, 2)
+		collect := func(ctx context.Context, ev interface{}) error {
+			events <- ev
+			return nil
+		}
+		pubSub.Subscribe(process.OrchestrationStarted{}, collect)
+		pubSub.Subscribe(process.OrchestrationFinished{}, collect)
+
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, poolingInterval, nil, pubSub, logrus.New())
+
+		// when
+		_, err = svc.Execute(id)
+		require.NoError(t, err)
+
+		// then
+		published := map[string]string{}
+		for i := 0; i < 2; i++ {
+			select {
+			case ev := <-events:
+				switch e := ev.(type) {
+				case process.OrchestrationStarted:
+					published["started"] = e.Orchestration.OrchestrationID
+				case process.OrchestrationFinished:
+					published["finished"] = e.Orchestration.State
+				}
+			case <-time.After(time.Second):
+				t.Fatal("orchestration lifecycle events were not published")
+			}
+		}
+		assert.Equal(t, map[string]string{"started": id, "finished": orchestration.Succeeded}, published)
+	})
+	t.Run("PublishesFinishedOnce", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			state         string
+			resolveErr    error
+			expectedState string
+		}{
+			"failed orchestration": {
+				state:         orchestration.Pending,
+				resolveErr:    errors.New("resolver failed"),
+				expectedState: orchestration.Failed,
+			},
+			"already finished orchestration": {
+				state: orchestration.Succeeded,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				store := storage.NewMemoryStorage()
+
+				resolver := &automock.RuntimeResolver{}
+				if tc.resolveErr != nil {
+					resolver.On("Resolve", orchestration.TargetSpec{}).Return(nil, tc.resolveErr)
+				}
+
+				id := "id"
+				err := store.Orchestrations().Insert(internal.Orchestration{OrchestrationID: id, State: tc.state})
+				require.NoError(t, err)
+
+				pubSub := event.NewPubSub(logrus.New())
+				events := make(chan process.OrchestrationFinished, 2)
+				pubSub.Subscribe(process.OrchestrationFinished{}, func(ctx context.Context, ev interface{}) error {
+					events <- ev.(process.OrchestrationFinished)
+					return nil
+				})
+
+				svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, poolingInterval, nil, pubSub, logrus.New())
+
+				// when
+				_, err = svc.Execute(id)
+				require.NoError(t, err)
+
+				// then
+				if tc.expectedState == "" {
+					select {
+					case ev := <-events:
+						t.Fatalf("unexpected event published for the finished orchestration: %+v", ev)
+					case <-time.After(100 * time.Millisecond):
+					}
+					return
+				}
+				select {
+				case ev := <-events:
+					assert.Equal(t, tc.expectedState, ev.Orchestration.State)
+				case <-time.After(time.Second):
+					t.Fatal("orchestration finished event was not published")
+				}
+				select {
+				case ev := <-events:
+					t.Fatalf("orchestration finished event published twice: %+v", ev)
+				case <-time.After(100 * time.Millisecond):
+				}
+			})
+		}
+	})
 	t.Run("InProgress", func(t *testing.T) {
 		// given
 		store := storage.NewMemoryStorage()
@@ -65,7 +178,7 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, logrus.New())
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
 
 		// when
 		_, err = svc.Execute(id)
@@ -95,7 +208,7 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 			}})
 		require.NoError(t, err)
 
-		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, poolingInterval, nil, logrus.New())
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), nil, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
 
 		// when
 		_, err = svc.Execute(id)
@@ -154,7 +267,7 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 		err = store.Orchestrations().Insert(givenO)
 		require.NoError(t, err)
 
-		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, logrus.New())
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
 
 		// when
 		_, err = svc.Execute(id)
@@ -192,7 +305,7 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 			},
 		})
 
-		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, logrus.New())
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), &testExecutor{}, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
 
 		// when
 		_, err = svc.Execute(id)
@@ -208,6 +321,145 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 
 		assert.Equal(t, orchestration.Canceled, string(op.State))
 	})
+
+	t.Run("RetriesFailedOperations", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			failures            int
+			expectedState       string
+			expectedLastAttempt string
+			expectedOperations  int
+		}{
+			"runtime succeeds on second attempt": {
+				failures:            1,
+				expectedState:       orchestration.Succeeded,
+				expectedLastAttempt: orchestration.Succeeded,
+				expectedOperations:  2,
+			},
+			"runtime exhausts attempts": {
+				failures:            5,
+				expectedState:       orchestration.Failed,
+				expectedLastAttempt: orchestration.Failed,
+				expectedOperations:  3,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				store := storage.NewMemoryStorage()
+
+				runtime := orchestration.Runtime{InstanceID: "instance", RuntimeID: "runtime"}
+				resolver := &automock.RuntimeResolver{}
+				defer resolver.AssertExpectations(t)
+				resolver.On("Resolve", orchestration.TargetSpec{}).Return([]orchestration.Runtime{runtime}, nil).Once()
+
+				require.NoError(t, store.Instances().Insert(internal.Instance{InstanceID: runtime.InstanceID, RuntimeID: runtime.RuntimeID}))
+
+				id := "id"
+				err := store.Orchestrations().Insert(internal.Orchestration{
+					OrchestrationID: id,
+					State:           orchestration.Pending,
+					Parameters: orchestration.Parameters{
+						Strategy: orchestration.StrategySpec{
+							Type:     orchestration.ParallelStrategy,
+							Schedule: orchestration.Immediate,
+							Parallel: orchestration.ParallelStrategySpec{Workers: 1},
+						},
+						Retry: orchestration.RetryPolicy{MaxAttempts: 3, Delay: "10ms"},
+					},
+				})
+				require.NoError(t, err)
+
+				executor := &failingExecutor{operations: store.Operations(), failures: tc.failures}
+				svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), executor, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
+
+				// when
+				_, err = svc.Execute(id)
+				require.NoError(t, err)
+
+				// then
+				o, err := store.Orchestrations().GetByID(id)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedState, o.State)
+
+				ops, _, _, err := store.Operations().ListUpgradeKymaOperationsByOrchestrationID(id, dbmodel.OperationFilter{})
+				require.NoError(t, err)
+				require.Len(t, ops, tc.expectedOperations)
+				for i, op := range ops {
+					assert.Equal(t, i+1, op.RuntimeOperation.Attempt)
+					assert.Equal(t, runtime.RuntimeID, op.RuntimeOperation.RuntimeID)
+					if i < len(ops)-1 {
+						assert.Equal(t, orchestration.Failed, string(op.State))
+						assert.Equal(t, ops[i+1].Operation.ID, op.RuntimeOperation.RetriedBy)
+					}
+				}
+				last := ops[len(ops)-1]
+				assert.Equal(t, tc.expectedLastAttempt, string(last.State))
+				assert.Empty(t, last.RuntimeOperation.RetriedBy)
+			})
+		}
+	})
+
+	t.Run("QuarantinesRepeatedlyFailingRuntime", func(t *testing.T) {
+		// given
+		store := storage.NewMemoryStorage()
+
+		failing := orchestration.Runtime{InstanceID: "failing-instance", RuntimeID: "failing-runtime"}
+		healthy := orchestration.Runtime{InstanceID: "instance", RuntimeID: "runtime"}
+		resolver := &automock.RuntimeResolver{}
+		defer resolver.AssertExpectations(t)
+		resolver.On("Resolve", orchestration.TargetSpec{}).Return([]orchestration.Runtime{failing, healthy}, nil).Once()
+
+		for _, r := range []orchestration.Runtime{failing, healthy} {
+			require.NoError(t, store.Instances().Insert(internal.Instance{InstanceID: r.InstanceID, RuntimeID: r.RuntimeID}))
+		}
+
+		id := "id"
+		err := store.Orchestrations().Insert(internal.Orchestration{
+			OrchestrationID: id,
+			State:           orchestration.Pending,
+			Parameters: orchestration.Parameters{
+				Strategy: orchestration.StrategySpec{
+					Type:            orchestration.ParallelStrategy,
+					Schedule:        orchestration.Immediate,
+					Parallel:        orchestration.ParallelStrategySpec{Workers: 2},
+					QuarantineAfter: 2,
+				},
+				Retry: orchestration.RetryPolicy{MaxAttempts: 5, Delay: "10ms"},
+			},
+		})
+		require.NoError(t, err)
+
+		executor := &runtimeFailingExecutor{operations: store.Operations(), runtimeID: failing.RuntimeID}
+		svc := manager.NewUpgradeKymaManager(store.Orchestrations(), store.Operations(), store.Instances(), executor, resolver, poolingInterval, nil, event.NewPubSub(logrus.New()), logrus.New())
+
+		// when
+		_, err = svc.Execute(id)
+		require.NoError(t, err)
+
+		// then
+		o, err := store.Orchestrations().GetByID(id)
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.Failed, o.State)
+
+		ops, _, _, err := store.Operations().ListUpgradeKymaOperationsByOrchestrationID(id, dbmodel.OperationFilter{})
+		require.NoError(t, err)
+		attempts := map[string][]internal.UpgradeKymaOperation{}
+		for _, op := range ops {
+			attempts[op.RuntimeOperation.RuntimeID] = append(attempts[op.RuntimeOperation.RuntimeID], op)
+		}
+
+		require.Len(t, attempts[failing.RuntimeID], 2)
+		first, quarantined := attempts[failing.RuntimeID][0], attempts[failing.RuntimeID][1]
+		if first.RuntimeOperation.Attempt > quarantined.RuntimeOperation.Attempt {
+			first, quarantined = quarantined, first
+		}
+		assert.False(t, first.RuntimeOperation.Quarantined)
+		assert.True(t, quarantined.RuntimeOperation.Quarantined)
+		assert.Equal(t, orchestration.Failed, string(quarantined.State))
+		assert.Equal(t, "operation quarantined after 2 failed attempts, last error: upgrade failed", quarantined.Description)
+
+		require.Len(t, attempts[healthy.RuntimeID], 1)
+		assert.Equal(t, orchestration.Succeeded, string(attempts[healthy.RuntimeID][0].State))
+	})
 }
 
 type testExecutor struct{}
@@ -219,3 +471,51 @@ func (t *testExecutor) Execute(opID string) (time.Duration, error) {
 func (t *testExecutor) Reschedule(operationID string, maintenanceWindowBegin, maintenanceWindowEnd time.Time) error {
 	return nil
 }
+
+// failingExecutor fails the given number of operations before the next ones succeed
+type failingExecutor struct {
+	operations storage.Operations
+	failures   int
+}
+
+func (e *failingExecutor) Execute(opID string) (time.Duration, error) {
+	op, err := e.operations.GetUpgradeKymaOperationByID(opID)
+	if err != nil {
+		return 0, err
+	}
+	op.State = orchestration.Succeeded
+	if e.failures > 0 {
+		e.failures--
+		op.State = orchestration.Failed
+	}
+	_, err = e.operations.UpdateUpgradeKymaOperation(*op)
+	return 0, err
+}
+
+func (e *failingExecutor) Reschedule(operationID string, maintenanceWindowBegin, maintenanceWindowEnd time.Time) error {
+	return nil
+}
+
+// runtimeFailingExecutor fails every operation of the given runtime, the operations of the other runtimes succeed
+type runtimeFailingExecutor struct {
+	operations storage.Operations
+	runtimeID  string
+}
+
+func (e *runtimeFailingExecutor) Execute(opID string) (time.Duration, error) {
+	op, err := e.operations.GetUpgradeKymaOperationByID(opID)
+	if err != nil {
+		return 0, err
+	}
+	op.State = orchestration.Succeeded
+	if op.RuntimeOperation.RuntimeID == e.runtimeID {
+		op.State = orchestration.Failed
+		op.Description = "upgrade failed"
+	}
+	_, err = e.operations.UpdateUpgradeKymaOperation(*op)
+	return 0, err
+}
+
+func (e *runtimeFailingExecutor) Reschedule(operationID string, maintenanceWindowBegin, maintenanceWindowEnd time.Time) error {
+	return nil
+}
@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+)
+
+type Config struct {
+	Disabled bool          `envconfig:"default=true"`
+	Timeout  time.Duration `envconfig:"default=10s"`
+	// SMTPAddress is the host:port of the mail server used for the email notification targets,
+	// the email targets are skipped when it is not configured
+	SMTPAddress  string `envconfig:"optional"`
+	SMTPUsername string `envconfig:"optional"`
+	SMTPPassword string `envconfig:"optional"`
+	EmailFrom    string `envconfig:"optional"`
+}
+
+type Event string
+
+const (
+	Started    Event = "started"
+	Progressed Event = "progressed"
+	Finished   Event = "finished"
+)
+
+// Notification is the payload sent to the notification targets of the orchestration. It holds only the orchestration
+// state and the number of operations in each state, runtime and account details are not exposed.
+type Notification struct {
+	OrchestrationID string             `json:"orchestrationID"`
+	Type            orchestration.Type `json:"type"`
+	Event           Event              `json:"event"`
+	State           string             `json:"state"`
+	DryRun          bool               `json:"dryRun,omitempty"`
+	OperationStats  map[string]int     `json:"operationStats,omitempty"`
+	Timestamp       time.Time          `json:"timestamp"`
+}
@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier sends the orchestration lifecycle notifications to the targets defined in the orchestration parameters
+type Notifier struct {
+	senders map[orchestration.NotificationTargetType]Sender
+	log     logrus.FieldLogger
+}
+
+func NewNotifier(senders map[orchestration.NotificationTargetType]Sender, log logrus.FieldLogger) *Notifier {
+	return &Notifier{
+		senders: senders,
+		log:     log,
+	}
+}
+
+// Subscribe registers the notifier handlers for the orchestration lifecycle events
+func (n *Notifier) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(process.OrchestrationStarted{}, n.OnOrchestrationStarted)
+	sub.Subscribe(process.OrchestrationProgressed{}, n.OnOrchestrationProgressed)
+	sub.Subscribe(process.OrchestrationFinished{}, n.OnOrchestrationFinished)
+}
+
+func (n *Notifier) OnOrchestrationStarted(_ context.Context, ev interface{}) error {
+	started, ok := ev.(process.OrchestrationStarted)
+	if !ok {
+		return fmt.Errorf("expected process.OrchestrationStarted but got %+v", ev)
+	}
+	n.notify(started.Orchestration, Started, nil)
+	return nil
+}
+
+func (n *Notifier) OnOrchestrationProgressed(_ context.Context, ev interface{}) error {
+	progressed, ok := ev.(process.OrchestrationProgressed)
+	if !ok {
+		return fmt.Errorf("expected process.OrchestrationProgressed but got %+v", ev)
+	}
+	n.notify(progressed.Orchestration, Progressed, progressed.Stats)
+	return nil
+}
+
+func (n *Notifier) OnOrchestrationFinished(_ context.Context, ev interface{}) error {
+	finished, ok := ev.(process.OrchestrationFinished)
+	if !ok {
+		return fmt.Errorf("expected process.OrchestrationFinished but got %+v", ev)
+	}
+	n.notify(finished.Orchestration, Finished, nil)
+	return nil
+}
+
+// notify sends the notification to every target of the orchestration, delivery errors are only logged
+// and do not affect the orchestration processing
+func (n *Notifier) notify(o internal.Orchestration, ev Event, stats map[string]int) {
+	if len(o.Parameters.Notifications) == 0 {
+		return
+	}
+	logger := n.log.WithField("orchestrationID", o.OrchestrationID).WithField("event", ev)
+
+	notification := Notification{
+		OrchestrationID: o.OrchestrationID,
+		Type:            o.Type,
+		Event:           ev,
+		State:           o.State,
+		DryRun:          o.Parameters.DryRun,
+		OperationStats:  stats,
+		Timestamp:       time.Now(),
+	}
+	for i, target := range o.Parameters.Notifications {
+		sender, found := n.senders[target.Type]
+		if !found {
+			logger.Warnf("skipping notification target %d, the %q target type is not supported", i, target.Type)
+			continue
+		}
+		if err := sender.Send(target, notification); err != nil {
+			logger.Errorf("while sending notification to the %s target %d: %s", target.Type, i, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/notification"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orchestrationID = "orchestration-id"
+
+func TestNotifier(t *testing.T) {
+	t.Run("should notify all targets at the orchestration lifecycle points", func(t *testing.T) {
+		// given
+		webhook := &fakeSender{}
+		email := &fakeSender{}
+		notifier := notification.NewNotifier(map[orchestration.NotificationTargetType]notification.Sender{
+			orchestration.WebhookNotification: webhook,
+			orchestration.EmailNotification:   email,
+		}, logrus.New())
+		o := fixOrchestration(orchestration.InProgress)
+
+		// when
+		err := notifier.OnOrchestrationStarted(context.Background(), process.OrchestrationStarted{Orchestration: o})
+		require.NoError(t, err)
+		err = notifier.OnOrchestrationProgressed(context.Background(), process.OrchestrationProgressed{
+			Orchestration: o,
+			Stats:         map[string]int{orchestration.InProgress: 1, orchestration.Succeeded: 1},
+		})
+		require.NoError(t, err)
+		o.State = orchestration.Succeeded
+		err = notifier.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{Orchestration: o})
+		require.NoError(t, err)
+
+		// then
+		for _, sender := range []*fakeSender{webhook, email} {
+			require.Len(t, sender.notifications, 3)
+			assert.Equal(t, notification.Started, sender.notifications[0].Event)
+			assert.Equal(t, orchestration.InProgress, sender.notifications[0].State)
+			assert.Equal(t, notification.Progressed, sender.notifications[1].Event)
+			assert.Equal(t, map[string]int{orchestration.InProgress: 1, orchestration.Succeeded: 1}, sender.notifications[1].OperationStats)
+			assert.Equal(t, notification.Finished, sender.notifications[2].Event)
+			assert.Equal(t, orchestration.Succeeded, sender.notifications[2].State)
+			for _, n := range sender.notifications {
+				assert.Equal(t, orchestrationID, n.OrchestrationID)
+				assert.Equal(t, orchestration.UpgradeKymaOrchestration, n.Type)
+			}
+		}
+		assert.Equal(t, "https://hooks.example.com/orchestrations", webhook.targets[0].URL)
+		assert.Equal(t, "team@example.com", email.targets[0].Email)
+	})
+
+	t.Run("should continue when the delivery fails", func(t *testing.T) {
+		// given
+		failing := &fakeSender{err: errors.New("connection refused")}
+		email := &fakeSender{}
+		notifier := notification.NewNotifier(map[orchestration.NotificationTargetType]notification.Sender{
+			orchestration.WebhookNotification: failing,
+			orchestration.EmailNotification:   email,
+		}, logrus.New())
+
+		// when
+		err := notifier.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Failed),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, failing.targets, 1)
+		assert.Len(t, email.notifications, 1)
+	})
+
+	t.Run("should skip targets without configured sender", func(t *testing.T) {
+		// given
+		webhook := &fakeSender{}
+		notifier := notification.NewNotifier(map[orchestration.NotificationTargetType]notification.Sender{
+			orchestration.WebhookNotification: webhook,
+		}, logrus.New())
+
+		// when
+		err := notifier.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Succeeded),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, webhook.notifications, 1)
+	})
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	// given
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := notification.NewWebhookSender(notification.Config{})
+
+	// when
+	err := sender.Send(orchestration.NotificationTarget{Type: orchestration.WebhookNotification, URL: server.URL}, notification.Notification{
+		OrchestrationID: orchestrationID,
+		Type:            orchestration.UpgradeClusterOrchestration,
+		Event:           notification.Finished,
+		State:           orchestration.Succeeded,
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, orchestrationID, received["orchestrationID"])
+	assert.Equal(t, "finished", received["event"])
+	assert.Equal(t, orchestration.Succeeded, received["state"])
+	assert.NotContains(t, received, "description")
+}
+
+func TestWebhookSender_SendUnexpectedStatus(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := notification.NewWebhookSender(notification.Config{})
+
+	// when
+	err := sender.Send(orchestration.NotificationTarget{Type: orchestration.WebhookNotification, URL: server.URL}, notification.Notification{})
+
+	// then
+	assert.EqualError(t, err, "notification webhook responded with unexpected status code 500")
+}
+
+func fixOrchestration(state string) internal.Orchestration {
+	return internal.Orchestration{
+		OrchestrationID: orchestrationID,
+		Type:            orchestration.UpgradeKymaOrchestration,
+		State:           state,
+		Description:     "Scheduled 2 operations",
+		Parameters: orchestration.Parameters{
+			Notifications: []orchestration.NotificationTarget{
+				{Type: orchestration.WebhookNotification, URL: "https://hooks.example.com/orchestrations"},
+				{Type: orchestration.EmailNotification, Email: "team@example.com"},
+			},
+		},
+	}
+}
+
+type fakeSender struct {
+	err           error
+	targets       []orchestration.NotificationTarget
+	notifications []notification.Notification
+}
+
+func (s *fakeSender) Send(target orchestration.NotificationTarget, n notification.Notification) error {
+	s.targets = append(s.targets, target)
+	if s.err != nil {
+		return s.err
+	}
+	s.notifications = append(s.notifications, n)
+	return nil
+}
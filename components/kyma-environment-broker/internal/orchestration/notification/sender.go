@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+
+	"github.com/pkg/errors"
+)
+
+// Sender delivers the notification to the given target
+type Sender interface {
+	Send(target orchestration.NotificationTarget, notification Notification) error
+}
+
+// WebhookSender sends notifications as JSON documents with the POST request to the target URL
+type WebhookSender struct {
+	httpClient *http.Client
+}
+
+func NewWebhookSender(cfg Config) *WebhookSender {
+	return &WebhookSender{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (s *WebhookSender) Send(target orchestration.NotificationTarget, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Wrap(err, "while marshaling notification")
+	}
+
+	request, err := http.NewRequest(http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "while creating notification request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		// the URL may contain a token, only the cause of the failure is returned
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "while sending notification")
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+	}()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("notification webhook responded with unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// EmailSender sends notifications as plain text emails with the configured mail server
+type EmailSender struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+func NewEmailSender(cfg Config) *EmailSender {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		host := strings.Split(cfg.SMTPAddress, ":")[0]
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+	}
+	return &EmailSender{
+		address: cfg.SMTPAddress,
+		from:    cfg.EmailFrom,
+		auth:    auth,
+	}
+}
+
+func (s *EmailSender) Send(target orchestration.NotificationTarget, notification Notification) error {
+	body, err := json.MarshalIndent(notification, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "while marshaling notification")
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Orchestration %s %s\r\nContent-Type: application/json\r\n\r\n%s\r\n",
+		s.from, target.Email, notification.OrchestrationID, notification.Event, body)
+
+	err = smtp.SendMail(s.address, s.auth, s.from, []string{target.Email}, []byte(message))
+	if err != nil {
+		return errors.Wrap(err, "while sending notification email")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Exporter assembles the report of the finished orchestration and writes it to the sink
+type Exporter struct {
+	operations storage.Operations
+	sink       Sink
+	cfg        Config
+	log        logrus.FieldLogger
+}
+
+func NewExporter(operations storage.Operations, sink Sink, cfg Config, log logrus.FieldLogger) *Exporter {
+	return &Exporter{
+		operations: operations,
+		sink:       sink,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// OnOrchestrationFinished is the event handler which exports the report of the finished orchestration.
+// Sink errors are retried a bounded number of times and then only logged, the orchestration state is not affected.
+func (e *Exporter) OnOrchestrationFinished(ctx context.Context, ev interface{}) error {
+	finished, ok := ev.(process.OrchestrationFinished)
+	if !ok {
+		return fmt.Errorf("expected process.OrchestrationFinished but got %+v", ev)
+	}
+	logger := e.log.WithField("orchestrationID", finished.Orchestration.OrchestrationID)
+
+	report, err := e.Assemble(finished.Orchestration)
+	if err != nil {
+		logger.Errorf("while assembling orchestration report: %s", err)
+		return nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = e.sink.Write(report)
+		if err == nil {
+			logger.Infof("Orchestration report exported")
+			return nil
+		}
+		if attempt > e.cfg.Retries {
+			break
+		}
+		logger.Warnf("while writing orchestration report (attempt %d): %s", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			logger.Errorf("orchestration report was not exported: %s", ctx.Err())
+			return nil
+		case <-time.After(e.cfg.RetryInterval):
+		}
+	}
+
+	logger.Errorf("orchestration report was not exported after %d retries: %s", e.cfg.Retries, err)
+	return nil
+}
+
+// Assemble creates the report of the given orchestration based on the stored operations
+func (e *Exporter) Assemble(o internal.Orchestration) (Report, error) {
+	report := Report{
+		OrchestrationID: o.OrchestrationID,
+		Type:            o.Type,
+		State:           o.State,
+		Description:     o.Description,
+		CreatedAt:       o.CreatedAt,
+		FinishedAt:      o.UpdatedAt,
+		Summary:         map[string]int{},
+		Runtimes:        []RuntimeReport{},
+	}
+
+	switch o.Type {
+	case orchestration.UpgradeKymaOrchestration:
+		operations, _, _, err := e.operations.ListUpgradeKymaOperationsByOrchestrationID(o.OrchestrationID, dbmodel.OperationFilter{})
+		if err != nil {
+			return Report{}, errors.Wrap(err, "while listing upgrade kyma operations")
+		}
+		for _, op := range operations {
+			report.add(newRuntimeReport(op.Operation, op.RuntimeOperation))
+		}
+	case orchestration.UpgradeClusterOrchestration:
+		operations, _, _, err := e.operations.ListUpgradeClusterOperationsByOrchestrationID(o.OrchestrationID, dbmodel.OperationFilter{})
+		if err != nil {
+			return Report{}, errors.Wrap(err, "while listing upgrade cluster operations")
+		}
+		for _, op := range operations {
+			report.add(newRuntimeReport(op.Operation, op.RuntimeOperation))
+		}
+	default:
+		return Report{}, errors.Errorf("unsupported orchestration type %q", o.Type)
+	}
+
+	return report, nil
+}
+
+func (r *Report) add(runtime RuntimeReport) {
+	r.Runtimes = append(r.Runtimes, runtime)
+	r.Summary[runtime.State]++
+	if runtime.Quarantined {
+		r.Quarantined = append(r.Quarantined, runtime.RuntimeID)
+	}
+}
+
+func newRuntimeReport(op internal.Operation, ro orchestration.RuntimeOperation) RuntimeReport {
+	runtime := RuntimeReport{
+		OperationID:     op.ID,
+		InstanceID:      op.InstanceID,
+		RuntimeID:       ro.RuntimeID,
+		GlobalAccountID: ro.GlobalAccountID,
+		SubAccountID:    ro.SubAccountID,
+		ShootName:       ro.ShootName,
+		State:           string(op.State),
+		StartedAt:       op.CreatedAt,
+		FinishedAt:      op.UpdatedAt,
+		DurationSeconds: op.UpdatedAt.Sub(op.CreatedAt).Seconds(),
+		Quarantined:     ro.Quarantined,
+	}
+	if op.State == orchestration.Failed {
+		runtime.Error = op.Description
+	}
+
+	return runtime
+}
@@ -0,0 +1,170 @@
+package report_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/report"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orchestrationID = "orchestration-id"
+
+func TestExporter_OnOrchestrationFinished(t *testing.T) {
+	t.Run("should export report for mixed success and failure orchestration", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		start := time.Now().Add(-time.Hour)
+		fixUpgradeKymaOperation(t, db.Operations(), "op-succeeded", "runtime-1", orchestration.Succeeded, "Operation succeeded", start, start.Add(10*time.Minute))
+		fixUpgradeKymaOperation(t, db.Operations(), "op-failed", "runtime-2", orchestration.Failed, "upgrade timed out", start.Add(time.Minute), start.Add(31*time.Minute))
+
+		sink := &fakeSink{}
+		exporter := report.NewExporter(db.Operations(), sink, report.Config{Retries: 3, RetryInterval: time.Millisecond}, logrus.New())
+
+		// when
+		err := exporter.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Failed),
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, sink.reports, 1)
+
+		got := sink.reports[0]
+		assert.Equal(t, orchestrationID, got.OrchestrationID)
+		assert.Equal(t, orchestration.UpgradeKymaOrchestration, got.Type)
+		assert.Equal(t, orchestration.Failed, got.State)
+		assert.Equal(t, map[string]int{orchestration.Succeeded: 1, orchestration.Failed: 1}, got.Summary)
+		require.Len(t, got.Runtimes, 2)
+
+		runtimes := map[string]report.RuntimeReport{}
+		for _, r := range got.Runtimes {
+			runtimes[r.OperationID] = r
+		}
+		assert.Equal(t, "runtime-1", runtimes["op-succeeded"].RuntimeID)
+		assert.Equal(t, orchestration.Succeeded, runtimes["op-succeeded"].State)
+		assert.Equal(t, float64(600), runtimes["op-succeeded"].DurationSeconds)
+		assert.Empty(t, runtimes["op-succeeded"].Error)
+		assert.Equal(t, "runtime-2", runtimes["op-failed"].RuntimeID)
+		assert.Equal(t, orchestration.Failed, runtimes["op-failed"].State)
+		assert.Equal(t, float64(1800), runtimes["op-failed"].DurationSeconds)
+		assert.Equal(t, "upgrade timed out", runtimes["op-failed"].Error)
+	})
+
+	t.Run("should list quarantined runtimes", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		start := time.Now().Add(-time.Hour)
+		fixUpgradeKymaOperation(t, db.Operations(), "op-succeeded", "runtime-1", orchestration.Succeeded, "Operation succeeded", start, start.Add(10*time.Minute))
+		fixUpgradeKymaOperation(t, db.Operations(), "op-quarantined", "runtime-2", orchestration.Failed, "operation quarantined after 3 failed attempts", start, start.Add(time.Minute))
+		op, err := db.Operations().GetUpgradeKymaOperationByID("op-quarantined")
+		require.NoError(t, err)
+		op.RuntimeOperation.Quarantined = true
+		_, err = db.Operations().UpdateUpgradeKymaOperation(*op)
+		require.NoError(t, err)
+
+		sink := &fakeSink{}
+		exporter := report.NewExporter(db.Operations(), sink, report.Config{Retries: 3, RetryInterval: time.Millisecond}, logrus.New())
+
+		// when
+		err = exporter.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Failed),
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, sink.reports, 1)
+		assert.Equal(t, []string{"runtime-2"}, sink.reports[0].Quarantined)
+	})
+
+	t.Run("should retry writing report to the sink", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		sink := &fakeSink{failures: 2}
+		exporter := report.NewExporter(db.Operations(), sink, report.Config{Retries: 3, RetryInterval: time.Millisecond}, logrus.New())
+
+		// when
+		err := exporter.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Succeeded),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, sink.calls)
+		assert.Len(t, sink.reports, 1)
+	})
+
+	t.Run("should give up after bounded number of retries without returning error", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		sink := &fakeSink{failures: 100}
+		exporter := report.NewExporter(db.Operations(), sink, report.Config{Retries: 2, RetryInterval: time.Millisecond}, logrus.New())
+
+		// when
+		err := exporter.OnOrchestrationFinished(context.Background(), process.OrchestrationFinished{
+			Orchestration: fixOrchestration(orchestration.Succeeded),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, sink.calls)
+		assert.Empty(t, sink.reports)
+	})
+}
+
+type fakeSink struct {
+	failures int
+	calls    int
+	reports  []report.Report
+}
+
+func (s *fakeSink) Write(r report.Report) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	s.reports = append(s.reports, r)
+	return nil
+}
+
+func fixOrchestration(state string) internal.Orchestration {
+	return internal.Orchestration{
+		OrchestrationID: orchestrationID,
+		Type:            orchestration.UpgradeKymaOrchestration,
+		State:           state,
+		CreatedAt:       time.Now().Add(-time.Hour),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+func fixUpgradeKymaOperation(t *testing.T, operations storage.Operations, id, runtimeID, state, description string, createdAt, updatedAt time.Time) {
+	t.Helper()
+
+	err := operations.InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:              id,
+			OrchestrationID: orchestrationID,
+			State:           domain.LastOperationState(state),
+			Description:     description,
+			CreatedAt:       createdAt,
+			UpdatedAt:       updatedAt,
+		},
+		RuntimeOperation: orchestration.RuntimeOperation{
+			ID: id,
+			Runtime: orchestration.Runtime{
+				RuntimeID:    runtimeID,
+				SubAccountID: "sub-" + runtimeID,
+			},
+		},
+	})
+	require.NoError(t, err)
+}
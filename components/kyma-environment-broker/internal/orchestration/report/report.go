@@ -0,0 +1,47 @@
+package report
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+)
+
+type Config struct {
+	Disabled bool `envconfig:"default=true"`
+	// URL is the destination to which the report is sent with the PUT request,
+	// it can be an HTTP endpoint or a pre-signed object storage URL
+	URL           string        `envconfig:"optional"`
+	Retries       int           `envconfig:"default=3"`
+	RetryInterval time.Duration `envconfig:"default=10s"`
+	Timeout       time.Duration `envconfig:"default=30s"`
+}
+
+// Report holds the machine-readable summary of the finished orchestration
+type Report struct {
+	OrchestrationID string             `json:"orchestrationID"`
+	Type            orchestration.Type `json:"type"`
+	State           string             `json:"state"`
+	Description     string             `json:"description"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	FinishedAt      time.Time          `json:"finishedAt"`
+	Summary         map[string]int     `json:"summary"`
+	Runtimes        []RuntimeReport    `json:"runtimes"`
+	// Quarantined lists the IDs of the runtimes which operations were not retried anymore after repeated failures
+	Quarantined []string `json:"quarantined,omitempty"`
+}
+
+// RuntimeReport holds the outcome of the operation performed on a single runtime
+type RuntimeReport struct {
+	OperationID     string    `json:"operationID"`
+	InstanceID      string    `json:"instanceID"`
+	RuntimeID       string    `json:"runtimeID"`
+	GlobalAccountID string    `json:"globalAccountID"`
+	SubAccountID    string    `json:"subAccountID"`
+	ShootName       string    `json:"shootName"`
+	State           string    `json:"state"`
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Error           string    `json:"error,omitempty"`
+	Quarantined     bool      `json:"quarantined,omitempty"`
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Sink is the destination of the orchestration reports
+type Sink interface {
+	Write(report Report) error
+}
+
+// HTTPSink sends reports as JSON documents with the PUT request to the configured URL
+type HTTPSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewHTTPSink(cfg Config) *HTTPSink {
+	return &HTTPSink{
+		url:        cfg.URL,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (s *HTTPSink) Write(report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "while marshaling orchestration report")
+	}
+
+	request, err := http.NewRequest(http.MethodPut, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "while creating orchestration report request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "while sending orchestration report")
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+	}()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("orchestration report sink responded with unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package binding
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/provisioning"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pkg/errors"
+)
+
+// EmsCredentialsProvider returns the EMS binding credentials created during the runtime provisioning
+type EmsCredentialsProvider struct {
+	operations storage.Provisioning
+	secretKey  string
+}
+
+func NewEmsCredentialsProvider(operations storage.Provisioning, secretKey string) *EmsCredentialsProvider {
+	return &EmsCredentialsProvider{
+		operations: operations,
+		secretKey:  secretKey,
+	}
+}
+
+func (p *EmsCredentialsProvider) Credentials(instanceID string) (map[string]interface{}, bool, error) {
+	operation, err := p.operations.GetProvisioningOperationByInstanceID(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, errors.Wrapf(err, "while getting provisioning operation for instance %s", instanceID)
+	}
+	if !operation.Ems.Instance.Provisioned || operation.Ems.Overrides == "" {
+		return nil, false, nil
+	}
+
+	overrides, err := provisioning.DecryptEventingOverrides(p.secretKey, operation.Ems.Overrides)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "while decrypting EMS credentials")
+	}
+
+	return map[string]interface{}{
+		"oauthClientId":      overrides.OauthClientId,
+		"oauthClientSecret":  overrides.OauthClientSecret,
+		"oauthTokenEndpoint": overrides.OauthTokenEndpoint,
+		"publishUrl":         overrides.PublishUrl,
+		"bebNamespace":       overrides.BebNamespace,
+	}, true, nil
+}
@@ -0,0 +1,115 @@
+package binding
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	credentialsRetryInterval = 10 * time.Second
+	credentialsTimeout       = 30 * time.Minute
+	dbRetryInterval          = 5 * time.Second
+)
+
+// CredentialsProvider returns credentials for the given instance, ready is false when the credentials are not available yet
+type CredentialsProvider interface {
+	Credentials(instanceID string) (credentials map[string]interface{}, ready bool, err error)
+}
+
+// Manager processes asynchronous bind and unbind operations
+type Manager struct {
+	bindings storage.Bindings
+	provider CredentialsProvider
+
+	log logrus.FieldLogger
+}
+
+func NewManager(bindings storage.Bindings, provider CredentialsProvider, log logrus.FieldLogger) *Manager {
+	return &Manager{
+		bindings: bindings,
+		provider: provider,
+		log:      log.WithField("service", "BindingManager"),
+	}
+}
+
+// Execute processes the last operation of the binding with the given ID
+func (m *Manager) Execute(bindingID string) (time.Duration, error) {
+	logger := m.log.WithField("bindingID", bindingID)
+
+	binding, err := m.bindings.GetByID(bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		logger.Info("binding does not exist, skipping")
+		return 0, nil
+	case err != nil:
+		logger.Errorf("unable to get binding: %s", err)
+		return dbRetryInterval, nil
+	}
+	if binding.State != domain.InProgress {
+		return 0, nil
+	}
+
+	switch binding.OperationType {
+	case internal.BindingOperationTypeBind:
+		return m.bind(*binding, logger)
+	case internal.BindingOperationTypeUnbind:
+		return m.unbind(*binding, logger)
+	default:
+		return 0, errors.Errorf("unknown binding operation type %q", binding.OperationType)
+	}
+}
+
+func (m *Manager) bind(binding internal.Binding, log logrus.FieldLogger) (time.Duration, error) {
+	credentials, ready, err := m.provider.Credentials(binding.InstanceID)
+	if err != nil {
+		log.Errorf("unable to get credentials: %s", err)
+		return m.update(binding, domain.Failed, "Unable to get binding credentials", nil, log)
+	}
+	if !ready {
+		if time.Since(binding.CreatedAt) > credentialsTimeout {
+			log.Error("credentials are not ready in time")
+			return m.update(binding, domain.Failed, "Binding credentials are not ready in time", nil, log)
+		}
+		log.Info("credentials are not ready yet, retrying")
+		return credentialsRetryInterval, nil
+	}
+
+	return m.update(binding, domain.Succeeded, "Binding created", credentials, log)
+}
+
+func (m *Manager) unbind(binding internal.Binding, log logrus.FieldLogger) (time.Duration, error) {
+	if err := m.bindings.Delete(binding.ID); err != nil {
+		log.Errorf("unable to delete binding: %s", err)
+		return dbRetryInterval, nil
+	}
+	log.Info("binding deleted")
+
+	return 0, nil
+}
+
+func (m *Manager) update(binding internal.Binding, state domain.LastOperationState, description string, credentials map[string]interface{}, log logrus.FieldLogger) (time.Duration, error) {
+	binding.State = state
+	binding.Description = description
+	binding.Credentials = credentials
+	binding.UpdatedAt = time.Now()
+
+	_, err := m.bindings.Update(binding)
+	switch {
+	case dberr.IsConflict(err):
+		// the binding was changed in the meantime (e.g. unbind requested), process it again
+		return dbRetryInterval, nil
+	case err != nil:
+		log.Errorf("unable to update binding: %s", err)
+		return dbRetryInterval, nil
+	}
+	log.Infof("binding operation finished with state %s", state)
+
+	return 0, nil
+}
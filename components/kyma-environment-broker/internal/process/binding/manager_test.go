@@ -0,0 +1,107 @@
+package binding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	instanceID = "instance-001"
+	bindingID  = "binding-001"
+)
+
+func TestManager_Bind(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Bindings().Insert(fixBinding(internal.BindingOperationTypeBind))
+	require.NoError(t, err)
+
+	provider := &fakeProvider{}
+	manager := NewManager(memoryStorage.Bindings(), provider, logrus.New())
+
+	// when
+	repeat, err := manager.Execute(bindingID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, credentialsRetryInterval, repeat)
+
+	// when
+	provider.credentials = map[string]interface{}{"publishUrl": "https://ems.local"}
+	repeat, err = manager.Execute(bindingID)
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	binding, err := memoryStorage.Bindings().GetByID(bindingID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Succeeded, binding.State)
+	assert.Equal(t, provider.credentials, binding.Credentials)
+}
+
+func TestManager_BindTimeout(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	binding := fixBinding(internal.BindingOperationTypeBind)
+	binding.CreatedAt = time.Now().Add(-credentialsTimeout - time.Minute)
+	err := memoryStorage.Bindings().Insert(binding)
+	require.NoError(t, err)
+
+	manager := NewManager(memoryStorage.Bindings(), &fakeProvider{}, logrus.New())
+
+	// when
+	repeat, err := manager.Execute(bindingID)
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	got, err := memoryStorage.Bindings().GetByID(bindingID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Failed, got.State)
+}
+
+func TestManager_Unbind(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Bindings().Insert(fixBinding(internal.BindingOperationTypeUnbind))
+	require.NoError(t, err)
+
+	manager := NewManager(memoryStorage.Bindings(), &fakeProvider{}, logrus.New())
+
+	// when
+	repeat, err := manager.Execute(bindingID)
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	_, err = memoryStorage.Bindings().GetByID(bindingID)
+	assert.True(t, dberr.IsNotFound(err))
+}
+
+type fakeProvider struct {
+	credentials map[string]interface{}
+}
+
+func (p *fakeProvider) Credentials(instanceID string) (map[string]interface{}, bool, error) {
+	return p.credentials, p.credentials != nil, nil
+}
+
+func fixBinding(operationType internal.BindingOperationType) internal.Binding {
+	return internal.Binding{
+		ID:            bindingID,
+		InstanceID:    instanceID,
+		OperationID:   "operation-001",
+		OperationType: operationType,
+		State:         domain.InProgress,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
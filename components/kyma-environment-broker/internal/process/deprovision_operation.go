@@ -47,6 +47,23 @@ func (om *DeprovisionOperationManager) OperationFailed(operation internal.Deprov
 	return updatedOperation, 0, errors.New(description)
 }
 
+// OperationFailedWithError marks the operation as failed with the message of the error and stores the failure reason
+// of the FailureError on the operation, it only repeats the operation if there is a storage error
+func (om *DeprovisionOperationManager) OperationFailedWithError(operation internal.DeprovisioningOperation, err error, log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	reason := internal.FailureReasonOf(err)
+	updatedOperation, repeat := om.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+		operation.State = domain.Failed
+		operation.Description = fmt.Sprintf("%s : %s", operation.Description, err.Error())
+		operation.FailureReason = reason
+	}, log)
+	// repeat in case of storage error
+	if repeat != 0 {
+		return updatedOperation, repeat, nil
+	}
+
+	return updatedOperation, 0, err
+}
+
 // UpdateOperation updates a given operation and handles conflict situation
 func (om *DeprovisionOperationManager) UpdateOperation(operation internal.DeprovisioningOperation, overwrite func(operation *internal.DeprovisioningOperation), log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration) {
 	overwrite(&operation)
@@ -11,6 +11,21 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	avsEvaluationsRemovalFirstWeight = 1
+	// avsEvaluationsRemovalLastWeight runs the step after all the other steps, just before the Remove_Runtime step of the weight 10
+	avsEvaluationsRemovalLastWeight = 9
+)
+
+// AvsEvaluationsRemovalWeight returns the weight of the AvsEvaluationRemovalStep. By default the evaluations are removed first,
+// with removeLast they are removed at the end of the deprovisioning, so the runtime is monitored while the rest of the teardown runs.
+func AvsEvaluationsRemovalWeight(removeLast bool) int {
+	if removeLast {
+		return avsEvaluationsRemovalLastWeight
+	}
+	return avsEvaluationsRemovalFirstWeight
+}
+
 type AvsEvaluationRemovalStep struct {
 	delegator             *avs.Delegator
 	operationsStorage     storage.Operations
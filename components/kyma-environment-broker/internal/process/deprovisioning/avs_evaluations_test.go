@@ -5,10 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
 	"github.com/gorilla/mux"
@@ -27,6 +29,39 @@ const (
 	parentEvalId   = int64(91011)
 )
 
+func TestAvsEvaluationsRemovalWeight(t *testing.T) {
+	for name, tc := range map[string]struct {
+		removeLast    bool
+		expectedOrder string
+	}{
+		"evaluations removed first": {removeLast: false, expectedOrder: "init avs credentials runtime"},
+		"evaluations removed last":  {removeLast: true, expectedOrder: "init credentials avs runtime"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			operations := memoryStorage.Operations()
+			assert.NoError(t, operations.InsertDeprovisioningOperation(fixDeprovisionOperation(operationIDSuccess)))
+			assert.NoError(t, operations.InsertProvisioningOperation(fixProvisionOperation()))
+
+			manager := NewManager(operations, event.NewPubSub(logrus.New()), logrus.New())
+			manager.InitStep(&testStep{t: t, name: "init", storage: operations})
+			manager.AddStep(AvsEvaluationsRemovalWeight(tc.removeLast), &testStep{t: t, name: "avs", storage: operations})
+			manager.AddStep(3, &testStep{t: t, name: "credentials", storage: operations})
+			manager.AddStep(10, &testStep{t: t, name: "runtime", storage: operations})
+
+			// when
+			_, err := manager.Execute(operationIDSuccess)
+
+			// then
+			assert.NoError(t, err)
+			operation, err := operations.GetOperationByID(operationIDSuccess)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOrder, strings.TrimSpace(operation.Description))
+		})
+	}
+}
+
 func TestAvsEvaluationsRemovalStep_Run(t *testing.T) {
 	// given
 	logger := logrus.New()
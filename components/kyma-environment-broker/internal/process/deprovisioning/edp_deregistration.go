@@ -7,6 +7,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/edp"
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,15 +18,20 @@ type EDPClient interface {
 	DeleteMetadataTenant(name, env, key string) error
 }
 
+// EDPDeregistrationStep removes the DataTenant of the subaccount, which is shared by all instances of the subaccount.
+// The EDP markers of the instances are the references to the DataTenant, so the DataTenant is removed only
+// when the deprovisioned instance is the last one referencing it, otherwise the instance is only detached.
 type EDPDeregistrationStep struct {
-	client EDPClient
-	config edp.Config
+	markers storage.SubsystemMarkers
+	client  EDPClient
+	config  edp.Config
 }
 
-func NewEDPDeregistrationStep(client EDPClient, config edp.Config) *EDPDeregistrationStep {
+func NewEDPDeregistrationStep(markers storage.SubsystemMarkers, client EDPClient, config edp.Config) *EDPDeregistrationStep {
 	return &EDPDeregistrationStep{
-		client: client,
-		config: config,
+		markers: markers,
+		client:  client,
+		config:  config,
 	}
 }
 
@@ -34,6 +40,20 @@ func (s *EDPDeregistrationStep) Name() string {
 }
 
 func (s *EDPDeregistrationStep) Run(operation internal.DeprovisioningOperation, log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	// the marker is removed before counting, so the instances deprovisioned at the same time do not keep the DataTenant
+	err := s.markers.Delete(operation.InstanceID, internal.SubsystemEDP)
+	if err != nil {
+		return s.handleError(operation, kebError.AsTemporaryError(err, "while deleting EDP marker"), log, "cannot detach instance from DataTenant")
+	}
+	references, err := s.markers.CountBySubAccount(internal.SubsystemEDP, operation.SubAccountID)
+	if err != nil {
+		return s.handleError(operation, kebError.AsTemporaryError(err, "while counting EDP markers"), log, "cannot count instances referencing DataTenant")
+	}
+	if references > 0 {
+		log.Infof("DataTenant of subaccount %s is referenced by %d other instance(s), instance is only detached", operation.SubAccountID, references)
+		return operation, 0, nil
+	}
+
 	log.Info("Delete DataTenant metadata")
 	for _, key := range []string{
 		edp.MaasConsumerEnvironmentKey,
@@ -47,7 +67,7 @@ func (s *EDPDeregistrationStep) Run(operation internal.DeprovisioningOperation,
 	}
 
 	log.Info("Delete DataTenant")
-	err := s.client.DeleteDataTenant(operation.SubAccountID, s.config.Environment)
+	err = s.client.DeleteDataTenant(operation.SubAccountID, s.config.Environment)
 	if err != nil {
 		return s.handleError(operation, err, log, "cannot remove DataTenant")
 	}
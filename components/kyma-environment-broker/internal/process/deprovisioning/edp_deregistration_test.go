@@ -8,8 +8,10 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/edp"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -17,51 +19,114 @@ const (
 	edpEnvironment = "test"
 )
 
+var metadataTenantKeys = []string{
+	edp.MaasConsumerEnvironmentKey,
+	edp.MaasConsumerRegionKey,
+	edp.MaasConsumerSubAccountKey,
+}
+
 func TestEDPDeregistration_Run(t *testing.T) {
-	// given
+	t.Run("should deregister DataTenant of the last instance", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		client := fixEDPClientWithDataTenant()
+		fixInstanceWithEDPMarker(t, memoryStorage, "inst-1", edpName)
+		fixInstanceWithEDPMarker(t, memoryStorage, "other-subaccount-inst", "other-subaccount")
+
+		step := NewEDPDeregistrationStep(memoryStorage.SubsystemMarkers(), client, edp.Config{
+			Environment: edpEnvironment,
+		})
+
+		// when
+		_, repeat, err := step.Run(fixEDPDeprovisioningOperation("inst-1"), logrus.New())
+
+		// then
+		assert.Equal(t, 0*time.Second, repeat)
+		assert.NoError(t, err)
+
+		for _, key := range metadataTenantKeys {
+			metadataTenant, metadataTenantExists := client.GetMetadataItem(edpName, edpEnvironment, key)
+			assert.False(t, metadataTenantExists)
+			assert.Equal(t, edp.MetadataItem{}, metadataTenant)
+		}
+
+		dataTenant, dataTenantExists := client.GetDataTenantItem(edpName, edpEnvironment)
+		assert.False(t, dataTenantExists)
+		assert.Equal(t, edp.DataTenantItem{}, dataTenant)
+	})
+
+	t.Run("should only detach instance from shared DataTenant", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		client := fixEDPClientWithDataTenant()
+		fixInstanceWithEDPMarker(t, memoryStorage, "inst-1", edpName)
+		fixInstanceWithEDPMarker(t, memoryStorage, "inst-2", edpName)
+
+		step := NewEDPDeregistrationStep(memoryStorage.SubsystemMarkers(), client, edp.Config{
+			Environment: edpEnvironment,
+		})
+
+		// when
+		_, repeat, err := step.Run(fixEDPDeprovisioningOperation("inst-1"), logrus.New())
+
+		// then
+		assert.Equal(t, 0*time.Second, repeat)
+		assert.NoError(t, err)
+
+		for _, key := range metadataTenantKeys {
+			_, metadataTenantExists := client.GetMetadataItem(edpName, edpEnvironment, key)
+			assert.True(t, metadataTenantExists)
+		}
+		_, dataTenantExists := client.GetDataTenantItem(edpName, edpEnvironment)
+		assert.True(t, dataTenantExists)
+
+		references, err := memoryStorage.SubsystemMarkers().CountBySubAccount(internal.SubsystemEDP, edpName)
+		require.NoError(t, err)
+		assert.Equal(t, 1, references)
+
+		// when the last instance is deprovisioned
+		_, _, err = step.Run(fixEDPDeprovisioningOperation("inst-2"), logrus.New())
+
+		// then
+		assert.NoError(t, err)
+		_, dataTenantExists = client.GetDataTenantItem(edpName, edpEnvironment)
+		assert.False(t, dataTenantExists)
+	})
+}
+
+func fixEDPClientWithDataTenant() *edp.FakeClient {
 	client := edp.NewFakeClient()
 	client.CreateDataTenant(edp.DataTenantPayload{
 		Name:        edpName,
 		Environment: edpEnvironment,
 		Secret:      base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s%s", edpName, edpEnvironment))),
 	})
-
-	metadataTenantKeys := []string{
-		edp.MaasConsumerEnvironmentKey,
-		edp.MaasConsumerRegionKey,
-		edp.MaasConsumerSubAccountKey,
-	}
-
 	for _, key := range metadataTenantKeys {
 		client.CreateMetadataTenant(edpName, edpEnvironment, edp.MetadataTenantPayload{
 			Key:   key,
 			Value: "-",
 		})
 	}
+	return client
+}
 
-	step := NewEDPDeregistrationStep(client, edp.Config{
-		Environment: edpEnvironment,
-	})
+func fixInstanceWithEDPMarker(t *testing.T, memoryStorage storage.BrokerStorage, instanceID, subAccountID string) {
+	t.Helper()
+	require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: instanceID, SubAccountID: subAccountID}))
+	require.NoError(t, memoryStorage.SubsystemMarkers().Insert(internal.SubsystemMarker{
+		InstanceID: instanceID,
+		Subsystem:  internal.SubsystemEDP,
+		CreatedAt:  time.Now(),
+	}))
+}
 
-	// when
-	_, repeat, err := step.Run(internal.DeprovisioningOperation{
+func fixEDPDeprovisioningOperation(instanceID string) internal.DeprovisioningOperation {
+	return internal.DeprovisioningOperation{
 		Operation: internal.Operation{
+			InstanceID: instanceID,
 			InstanceDetails: internal.InstanceDetails{
 				SubAccountID: edpName,
 			},
-		}}, logrus.New())
-
-	// then
-	assert.Equal(t, 0*time.Second, repeat)
-	assert.NoError(t, err)
-
-	for _, key := range metadataTenantKeys {
-		metadataTenant, metadataTenantExists := client.GetMetadataItem(edpName, edpEnvironment, key)
-		assert.False(t, metadataTenantExists)
-		assert.Equal(t, edp.MetadataItem{}, metadataTenant)
+		},
 	}
-
-	dataTenant, dataTenantExists := client.GetDataTenantItem(edpName, edpEnvironment)
-	assert.False(t, dataTenantExists)
-	assert.Equal(t, edp.DataTenantItem{}, dataTenant)
 }
@@ -8,11 +8,14 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type Step interface {
@@ -24,14 +27,19 @@ type Manager struct {
 	log              logrus.FieldLogger
 	steps            map[int][]Step
 	operationStorage storage.Operations
+	operationManager *process.DeprovisionOperationManager
+	retryClassifier  kebError.RetryClassifier
 
-	publisher event.Publisher
+	publisher   event.Publisher
+	stepLimiter *process.StepLimiter
 }
 
 func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.FieldLogger) *Manager {
 	return &Manager{
 		log:              logger,
 		operationStorage: storage,
+		operationManager: process.NewDeprovisionOperationManager(storage),
+		retryClassifier:  kebError.IsRetryable,
 		steps:            make(map[int][]Step, 0),
 		publisher:        pub,
 	}
@@ -48,10 +56,61 @@ func (m *Manager) AddStep(weight int, step Step) {
 	m.steps[weight] = append(m.steps[weight], step)
 }
 
-func (m *Manager) runStep(step Step, operation internal.DeprovisioningOperation, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+// SetRetryClassifier replaces the classifier which decides if the step which returned an error is retried,
+// the operation fails immediately when the error is not retryable
+func (m *Manager) SetRetryClassifier(classifier kebError.RetryClassifier) {
+	m.retryClassifier = classifier
+}
+
+// SetStepLimiter sets the limiter capping the number of operations executing a step at the same time
+func (m *Manager) SetStepLimiter(limiter *process.StepLimiter) {
+	m.stepLimiter = limiter
+}
+
+func (m *Manager) saveCurrentStep(operation internal.DeprovisioningOperation, step Step, log logrus.FieldLogger) (internal.DeprovisioningOperation, error) {
+	started := operation.StartStepTiming(step.Name(), time.Now())
+	if operation.CurrentStep == step.Name() && !started {
+		return operation, nil
+	}
+	operation.CurrentStep = step.Name()
+	op, err := m.operationStorage.UpdateDeprovisioningOperation(operation)
+	if err != nil {
+		log.Errorf("Unable to save current step: %s", err)
+		return operation, err
+	}
+	return *op, nil
+}
+
+// traceOperation starts the span of the operation processing and stores the traceparent of the root span on the operation
+func (m *Manager) traceOperation(operation internal.DeprovisioningOperation, planID string, log logrus.FieldLogger) (context.Context, *trace.Span, internal.DeprovisioningOperation) {
+	ctx, span, traceParent := process.StartOperationSpan("deprovisioning", operation.Operation, planID)
+	if traceParent == "" {
+		return ctx, span, operation
+	}
+
+	traced := operation
+	traced.TraceParent = traceParent
+	op, err := m.operationStorage.UpdateDeprovisioningOperation(traced)
+	if err != nil {
+		log.Warnf("Unable to save trace parent: %s", err)
+		return ctx, span, operation
+	}
+	return ctx, span, *op
+}
+
+func (m *Manager) runStep(ctx context.Context, step Step, operation internal.DeprovisioningOperation, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	if !m.stepLimiter.Acquire(step.Name()) {
+		logger.Infof("Limit of operations executing the step at the same time reached, step will be repeated in %s", m.stepLimiter.RetryInterval())
+		return operation, m.stepLimiter.RetryInterval(), nil
+	}
+	defer m.stepLimiter.Release(step.Name())
+
+	ctx, span := trace.StartSpan(ctx, step.Name())
+
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.DeprovisioningStepProcessed{
+	tracing.EndSpan(span, err)
+	m.publisher.Publish(ctx, process.DeprovisioningStepProcessed{
 		StepProcessed: process.StepProcessed{
 			StepName: step.Name(),
 			Duration: time.Since(start),
@@ -78,7 +137,10 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 		return 0, err
 	}
 
-	logOperation := m.log.WithFields(logrus.Fields{"operation": operationID, "instanceID": operation.InstanceID, "planID": provisioningOp.ProvisioningParameters.PlanID})
+	logOperation := process.OperationLogger(m.log, operation.Operation).WithField("planID", provisioningOp.ProvisioningParameters.PlanID)
+
+	ctx, span, operation := m.traceOperation(operation, provisioningOp.ProvisioningParameters.PlanID, logOperation)
+	defer span.End()
 
 	var when time.Duration
 	logOperation.Info("Start process operation steps")
@@ -89,7 +151,28 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 			logStep := logOperation.WithField("step", step.Name())
 			logStep.Infof("Start step")
 
-			operation, when, err = m.runStep(step, operation, logStep)
+			operation, err = m.saveCurrentStep(operation, step, logStep)
+			if err != nil {
+				return time.Second, nil
+			}
+
+			operation, when, err = m.runStep(ctx, step, operation, logStep)
+			if err != nil && !operation.IsFinished() && !m.retryClassifier(err) {
+				logStep.Errorf("Process operation failed with non-retryable error: %s", err)
+				failedOperation, repeat, failErr := m.operationManager.OperationFailedWithError(operation, err, logStep)
+				if repeat == 0 {
+					m.publisher.Publish(ctx, process.OperationFailed{
+						StepProcessed: process.StepProcessed{StepName: step.Name(), Error: err},
+						Operation:     failedOperation.Operation,
+						RuntimeID:     failedOperation.RuntimeID,
+					})
+				}
+				return repeat, failErr
+			}
+			if err != nil && when != 0 && !operation.IsFinished() {
+				logStep.Warnf("Process operation attempt failed, it will be repeated in %s: %s", when, err)
+				return when, nil
+			}
 			if err != nil {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
@@ -104,6 +187,7 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 			}
 			if when == 0 {
 				logStep.Info("Process operation successful")
+				operation.FinishStepTiming(step.Name(), time.Now())
 				continue
 			}
 
@@ -112,6 +196,11 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 		}
 	}
 
+	// the finish of the last step is not stored with the start of the next one
+	if _, err := m.operationStorage.UpdateDeprovisioningOperation(operation); err != nil {
+		logOperation.Errorf("Unable to save step timings: %s", err)
+	}
+
 	logOperation.Infof("Operation %q got status %s. All steps finished.", operation.ID, operation.State)
 	return 0, nil
 }
@@ -22,13 +22,17 @@ type RemoveRuntimeStep struct {
 	operationManager  *process.DeprovisionOperationManager
 	instanceStorage   storage.Instances
 	provisionerClient provisioner.Client
+
+	// tolerateNotFound treats the runtime which does not exist in the Provisioner as already removed
+	tolerateNotFound bool
 }
 
-func NewRemoveRuntimeStep(os storage.Operations, is storage.Instances, cli provisioner.Client) *RemoveRuntimeStep {
+func NewRemoveRuntimeStep(os storage.Operations, is storage.Instances, cli provisioner.Client, tolerateNotFound bool) *RemoveRuntimeStep {
 	return &RemoveRuntimeStep{
 		operationManager:  process.NewDeprovisionOperationManager(os),
 		instanceStorage:   is,
 		provisionerClient: cli,
+		tolerateNotFound:  tolerateNotFound,
 	}
 }
 
@@ -69,8 +73,16 @@ func (s *RemoveRuntimeStep) Run(operation internal.DeprovisioningOperation, log
 	var provisionerResponse string
 	if operation.ProvisionerOperationID == "" {
 
-		provisionerResponse, err = s.provisionerClient.DeprovisionRuntime(instance.GlobalAccountID, instance.RuntimeID)
-		if err != nil {
+		provisionerResponse, err = provisioner.ForTrace(provisioner.ForOperation(s.provisionerClient, operation.CorrelationID), operation.TraceParent).DeprovisionRuntime(instance.GlobalAccountID, instance.RuntimeID)
+		switch {
+		case err == nil:
+		case s.tolerateNotFound && provisioner.IsNotFoundError(err):
+			log.Warnf("runtime does not exist in the Provisioner, treating it as already removed: %s", err)
+			if err := s.cleanUp(&operation, log); err != nil {
+				return operation, 1 * time.Second, nil
+			}
+			return s.operationManager.OperationSucceeded(operation, "runtime already removed in the Provisioner", log)
+		default:
 			log.Errorf("unable to deprovision runtime: %s", err)
 			return operation, 10 * time.Second, nil
 		}
@@ -7,9 +7,12 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	provisionerAutomock "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner/automock"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRemoveRuntimeStep_Run(t *testing.T) {
@@ -28,7 +31,7 @@ func TestRemoveRuntimeStep_Run(t *testing.T) {
 		provisionerClient := &provisionerAutomock.Client{}
 		provisionerClient.On("DeprovisionRuntime", fixGlobalAccountID, fixRuntimeID).Return(fixProvisionerOperationID, nil)
 
-		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient)
+		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, true)
 
 		// when
 		entry := log.WithFields(logrus.Fields{"step": "TEST"})
@@ -65,7 +68,7 @@ func TestRemoveRuntimeStep_Run(t *testing.T) {
 		provisionerClient := &provisionerAutomock.Client{}
 		provisionerClient.On("DeprovisionRuntime", fixGlobalAccountID, fixRuntimeID).Return(fixProvisionerOperationID, nil)
 
-		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient)
+		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, true)
 
 		// when
 		entry := log.WithFields(logrus.Fields{"step": "TEST"})
@@ -78,4 +81,104 @@ func TestRemoveRuntimeStep_Run(t *testing.T) {
 		assert.Equal(t, "", result.ProvisionerOperationID)
 		assert.Equal(t, "", result.RuntimeID)
 	})
+
+	t.Run("Should mark operation as succeeded when runtime does not exist in the Provisioner", func(t *testing.T) {
+		// given
+		log := logrus.New()
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixture.FixDeprovisioningOperation(fixOperationID, fixInstanceID)
+		operation.ProvisionerOperationID = ""
+		operation.State = domain.InProgress
+		err := memoryStorage.Operations().InsertDeprovisioningOperation(operation)
+		require.NoError(t, err)
+
+		err = memoryStorage.Instances().Insert(fixInstanceRuntimeStatus())
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("DeprovisionRuntime", fixGlobalAccountID, fixRuntimeID).
+			Return("", errors.Wrap(errors.New("failed to get last operation: Last operation not found for runtime: "+fixRuntimeID), "Failed to deprovision Runtime"))
+
+		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, true)
+
+		// when
+		entry := log.WithFields(logrus.Fields{"step": "TEST"})
+		result, repeat, err := step.Run(operation, entry)
+
+		// then
+		assert.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.Equal(t, domain.Succeeded, result.State)
+		assert.Equal(t, "", result.ProvisionerOperationID)
+
+		_, err = memoryStorage.Instances().GetByID(fixInstanceID)
+		assert.True(t, dberr.IsNotFound(err))
+	})
+
+	t.Run("Should repeat process when runtime does not exist in the Provisioner and it is not tolerated", func(t *testing.T) {
+		// given
+		log := logrus.New()
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixture.FixDeprovisioningOperation(fixOperationID, fixInstanceID)
+		operation.ProvisionerOperationID = ""
+		operation.State = domain.InProgress
+		err := memoryStorage.Operations().InsertDeprovisioningOperation(operation)
+		require.NoError(t, err)
+
+		err = memoryStorage.Instances().Insert(fixInstanceRuntimeStatus())
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("DeprovisionRuntime", fixGlobalAccountID, fixRuntimeID).
+			Return("", errors.New("Last operation not found for runtime: "+fixRuntimeID))
+
+		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, false)
+
+		// when
+		entry := log.WithFields(logrus.Fields{"step": "TEST"})
+		result, repeat, err := step.Run(operation, entry)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Second, repeat)
+		assert.Equal(t, domain.InProgress, result.State)
+
+		_, err = memoryStorage.Instances().GetByID(fixInstanceID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should repeat process when deprovisioning call to provisioner failed", func(t *testing.T) {
+		// given
+		log := logrus.New()
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixture.FixDeprovisioningOperation(fixOperationID, fixInstanceID)
+		operation.ProvisionerOperationID = ""
+		operation.State = domain.InProgress
+		err := memoryStorage.Operations().InsertDeprovisioningOperation(operation)
+		require.NoError(t, err)
+
+		err = memoryStorage.Instances().Insert(fixInstanceRuntimeStatus())
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("DeprovisionRuntime", fixGlobalAccountID, fixRuntimeID).
+			Return("", errors.New("connection refused"))
+
+		step := NewRemoveRuntimeStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, true)
+
+		// when
+		entry := log.WithFields(logrus.Fields{"step": "TEST"})
+		result, repeat, err := step.Run(operation, entry)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Second, repeat)
+		assert.Equal(t, domain.InProgress, result.State)
+
+		_, err = memoryStorage.Instances().GetByID(fixInstanceID)
+		assert.NoError(t, err)
+	})
 }
@@ -0,0 +1,73 @@
+package deprovisioning
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+// RevokeCredentialsStep revokes the admin kubeconfigs and service tokens issued for the runtime,
+// so they cannot be used until they expire
+type RevokeCredentialsStep struct {
+	operationManager  *process.DeprovisionOperationManager
+	instanceStorage   storage.Instances
+	provisionerClient provisioner.Client
+}
+
+func NewRevokeCredentialsStep(os storage.Operations, is storage.Instances, cli provisioner.Client) *RevokeCredentialsStep {
+	return &RevokeCredentialsStep{
+		operationManager:  process.NewDeprovisionOperationManager(os),
+		instanceStorage:   is,
+		provisionerClient: cli,
+	}
+}
+
+func (s *RevokeCredentialsStep) Name() string {
+	return "Revoke_Credentials"
+}
+
+func (s *RevokeCredentialsStep) Run(operation internal.DeprovisioningOperation, log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	if operation.CredentialsRevoked {
+		log.Infof("credentials already revoked")
+		return operation, 0, nil
+	}
+
+	instance, err := s.instanceStorage.GetByID(operation.InstanceID)
+	switch {
+	case err == nil:
+	case dberr.IsNotFound(err):
+		log.Infof("instance already deprovisioned, skipping credentials revocation")
+		return operation, 0, nil
+	default:
+		log.Errorf("unable to get instance from storage: %s", err)
+		return operation, 1 * time.Second, nil
+	}
+
+	if instance.RuntimeID == "" {
+		log.Infof("Runtime does not exist for instance id %q, skipping credentials revocation", instance.InstanceID)
+		return operation, 0, nil
+	}
+	log = log.WithField("runtimeID", instance.RuntimeID)
+
+	_, err = s.provisionerClient.RevokeRuntimeCredentials(instance.GlobalAccountID, instance.RuntimeID)
+	if err != nil {
+		log.Errorf("unable to revoke runtime credentials: %s", err)
+		return s.operationManager.RetryOperationWithoutFail(operation, "unable to revoke runtime credentials", 10*time.Second, 5*time.Minute, log)
+	}
+
+	operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+		operation.CredentialsRevoked = true
+	}, log)
+	if repeat != 0 {
+		return operation, repeat, nil
+	}
+
+	log.Infof("runtime credentials revoked")
+	return operation, 0, nil
+}
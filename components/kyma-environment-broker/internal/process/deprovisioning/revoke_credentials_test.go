@@ -0,0 +1,78 @@
+package deprovisioning
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	provisionerAutomock "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeCredentialsStep_Run(t *testing.T) {
+	t.Run("should revoke credentials and record it on the operation", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixture.FixDeprovisioningOperation(fixOperationID, fixInstanceID)
+		err := memoryStorage.Operations().InsertDeprovisioningOperation(operation)
+		require.NoError(t, err)
+
+		err = memoryStorage.Instances().Insert(fixInstanceRuntimeStatus())
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("RevokeRuntimeCredentials", fixGlobalAccountID, fixRuntimeID).Return(fixRuntimeID, nil).Once()
+
+		step := NewRevokeCredentialsStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient)
+
+		// when
+		result, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.True(t, result.CredentialsRevoked)
+
+		storedOperation, err := memoryStorage.Operations().GetDeprovisioningOperationByID(fixOperationID)
+		require.NoError(t, err)
+		assert.True(t, storedOperation.CredentialsRevoked)
+
+		// when the step is run again
+		result, repeat, err = step.Run(result, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.True(t, result.CredentialsRevoked)
+		provisionerClient.AssertNumberOfCalls(t, "RevokeRuntimeCredentials", 1)
+	})
+
+	t.Run("should skip revocation when runtime does not exist", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixture.FixDeprovisioningOperation(fixOperationID, fixInstanceID)
+		err := memoryStorage.Operations().InsertDeprovisioningOperation(operation)
+		require.NoError(t, err)
+
+		instance := fixInstanceRuntimeStatus()
+		instance.RuntimeID = ""
+		err = memoryStorage.Instances().Insert(instance)
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		step := NewRevokeCredentialsStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient)
+
+		// when
+		_, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		provisionerClient.AssertNotCalled(t, "RevokeRuntimeCredentials", mock.Anything, mock.Anything)
+	})
+}
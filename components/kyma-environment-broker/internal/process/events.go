@@ -36,3 +36,34 @@ type UpgradeClusterStepProcessed struct {
 	OldOperation internal.UpgradeClusterOperation
 	Operation    internal.UpgradeClusterOperation
 }
+
+// OperationFailed is published when the operation manager fails the operation because of a non-retryable step error.
+// The step event of such step carries the operation which is still in progress, the failure is stored afterwards.
+type OperationFailed struct {
+	StepProcessed
+	Operation internal.Operation
+	RuntimeID string
+}
+
+// OrchestrationStarted is published when the operations of the orchestration were scheduled
+type OrchestrationStarted struct {
+	Orchestration internal.Orchestration
+}
+
+// OrchestrationProgressed is published when the number of the orchestration operations in any state has changed
+type OrchestrationProgressed struct {
+	Orchestration internal.Orchestration
+	Stats         map[string]int
+}
+
+type OrchestrationFinished struct {
+	Orchestration internal.Orchestration
+}
+
+// LMSCertificateExpiring is published for an instance whose LMS certificate expires soon and has to be renewed
+type LMSCertificateExpiring struct {
+	InstanceID string
+	RuntimeID  string
+	TenantID   string
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,135 @@
+package process
+
+import (
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/google/uuid"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type FailedProvisioningCleanupConfig struct {
+	// Enabled turns on scheduling the cleanup of failed provisioning operations on start
+	Enabled bool `envconfig:"default=false"`
+	// Limit is the maximum number of cleanup operations scheduled on a single start
+	Limit int `envconfig:"default=20"`
+}
+
+type OperationQueue interface {
+	Add(processId string)
+}
+
+// FailedProvisioningCleaner finds failed provisioning operations which left external resources behind
+// (runtime, AVS evaluations, Service Manager instances) and schedules a temporary deprovisioning for them.
+// The temporary deprovisioning removes the resources but keeps the instance, so the platform still sees
+// the failed provisioning and can deprovision the instance on its own.
+type FailedProvisioningCleaner struct {
+	operations storage.Operations
+	instances  storage.Instances
+	queue      OperationQueue
+	limit      int
+	log        logrus.FieldLogger
+}
+
+func NewFailedProvisioningCleaner(operations storage.Operations, instances storage.Instances, queue OperationQueue, limit int, log logrus.FieldLogger) *FailedProvisioningCleaner {
+	return &FailedProvisioningCleaner{
+		operations: operations,
+		instances:  instances,
+		queue:      queue,
+		limit:      limit,
+		log:        log.WithField("service", "FailedProvisioningCleaner"),
+	}
+}
+
+// ScheduleCleanup schedules the cleanup of at most limit operations, oldest first.
+// Operations followed by a deprovisioning are skipped, so running it again does not schedule the same cleanup twice.
+func (c *FailedProvisioningCleaner) ScheduleCleanup() error {
+	operations, _, _, err := c.operations.ListOperations(dbmodel.OperationFilter{States: []string{string(domain.Failed)}})
+	switch {
+	case dberr.IsNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "while getting failed operations")
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.Before(operations[j].CreatedAt)
+	})
+
+	scheduled := 0
+	for _, operation := range operations {
+		if scheduled >= c.limit {
+			c.log.Infof("Limit of %d scheduled cleanups reached, remaining operations are cleaned up on the next start", c.limit)
+			break
+		}
+		if operation.Type != internal.OperationTypeProvision || !hasUncleanedResources(operation) {
+			continue
+		}
+		log := c.log.WithFields(logrus.Fields{"operationID": operation.ID, "instanceID": operation.InstanceID})
+
+		cleanupID, err := c.scheduleCleanup(operation, log)
+		if err != nil {
+			return errors.Wrapf(err, "while scheduling cleanup of operation %s", operation.ID)
+		}
+		if cleanupID == "" {
+			continue
+		}
+		c.queue.Add(cleanupID)
+		scheduled++
+		log.Infof("Scheduled cleanup of failed provisioning, deprovisioning operation ID: %s", cleanupID)
+	}
+
+	return nil
+}
+
+func (c *FailedProvisioningCleaner) scheduleCleanup(operation internal.Operation, log logrus.FieldLogger) (string, error) {
+	deprovisioning, err := c.operations.GetDeprovisioningOperationByInstanceID(operation.InstanceID)
+	switch {
+	case err == nil && deprovisioning.CreatedAt.After(operation.CreatedAt):
+		log.Debugf("Instance was deprovisioned after the failed provisioning by operation %s", deprovisioning.ID)
+		return "", nil
+	case err != nil && !dberr.IsNotFound(err):
+		return "", errors.Wrap(err, "while getting deprovisioning operation")
+	}
+
+	instance, err := c.instances.GetByID(operation.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		log.Debug("Instance does not exist, skipping cleanup")
+		return "", nil
+	case err != nil:
+		return "", errors.Wrap(err, "while getting instance")
+	}
+
+	cleanup := internal.NewSuspensionOperationWithID(uuid.New().String(), instance)
+	cleanup.InstanceDetails = operation.InstanceDetails
+	cleanup.Description = "Cleanup of failed provisioning"
+	if err := c.operations.InsertDeprovisioningOperation(cleanup); err != nil {
+		return "", errors.Wrap(err, "while inserting deprovisioning operation")
+	}
+
+	return cleanup.ID, nil
+}
+
+// hasUncleanedResources checks the markers set by the provisioning steps after creating external resources
+func hasUncleanedResources(operation internal.Operation) bool {
+	avs := operation.Avs
+	switch {
+	case operation.RuntimeID != "":
+		return true
+	case avs.AvsEvaluationInternalId != 0 && !avs.AVSInternalEvaluationDeleted:
+		return true
+	case avs.AVSEvaluationExternalId != 0 && !avs.AVSExternalEvaluationDeleted:
+		return true
+	case operation.XSUAA.Instance.ProvisioningTriggered && !operation.XSUAA.Instance.DeprovisioningTriggered:
+		return true
+	case operation.Ems.Instance.ProvisioningTriggered && !operation.Ems.Instance.DeprovisioningTriggered:
+		return true
+	}
+	return false
+}
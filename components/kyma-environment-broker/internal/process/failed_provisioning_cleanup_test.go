@@ -0,0 +1,105 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedProvisioningCleaner_ScheduleCleanup(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	leaked := fixFailedProvisioningOperation("leaked-op", "leaked")
+	leaked.RuntimeID = "runtime-id"
+	leaked.Avs.AvsEvaluationInternalId = 123
+	clean := fixFailedProvisioningOperation("clean-op", "clean")
+	cleanedAvs := fixFailedProvisioningOperation("cleaned-avs-op", "cleaned-avs")
+	cleanedAvs.Avs.AvsEvaluationInternalId = 123
+	cleanedAvs.Avs.AVSInternalEvaluationDeleted = true
+
+	for _, op := range []internal.ProvisioningOperation{leaked, clean, cleanedAvs} {
+		require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(op))
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: op.InstanceID}))
+	}
+	queue := &fakeOperationQueue{}
+	cleaner := NewFailedProvisioningCleaner(memoryStorage.Operations(), memoryStorage.Instances(), queue, 10, logrus.New())
+
+	// when
+	err := cleaner.ScheduleCleanup()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, queue.ids, 1)
+	cleanup, err := memoryStorage.Operations().GetDeprovisioningOperationByID(queue.ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, "leaked", cleanup.InstanceID)
+	assert.True(t, cleanup.Temporary)
+	assert.Equal(t, "runtime-id", cleanup.RuntimeID)
+	assert.Equal(t, int64(123), cleanup.Avs.AvsEvaluationInternalId)
+
+	_, err = memoryStorage.Operations().GetDeprovisioningOperationByInstanceID("clean")
+	assert.Error(t, err)
+
+	// when
+	err = cleaner.ScheduleCleanup()
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, queue.ids, 1)
+}
+
+func TestFailedProvisioningCleaner_ScheduleCleanupWithLimit(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	for i, id := range []string{"first", "second", "third"} {
+		op := fixFailedProvisioningOperation(id+"-op", id)
+		op.RuntimeID = id
+		op.CreatedAt = time.Now().Add(time.Duration(i-3) * time.Minute)
+		require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(op))
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: id}))
+	}
+	queue := &fakeOperationQueue{}
+	cleaner := NewFailedProvisioningCleaner(memoryStorage.Operations(), memoryStorage.Instances(), queue, 2, logrus.New())
+
+	// when
+	err := cleaner.ScheduleCleanup()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, queue.ids, 2)
+	_, err = memoryStorage.Operations().GetDeprovisioningOperationByInstanceID("third")
+	assert.Error(t, err)
+
+	// when
+	err = cleaner.ScheduleCleanup()
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, queue.ids, 3)
+}
+
+func fixFailedProvisioningOperation(id, instanceID string) internal.ProvisioningOperation {
+	return internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         id,
+			InstanceID: instanceID,
+			Type:       internal.OperationTypeProvision,
+			State:      domain.Failed,
+			CreatedAt:  time.Now().Add(-time.Hour),
+		},
+	}
+}
+
+type fakeOperationQueue struct {
+	ids []string
+}
+
+func (q *fakeOperationQueue) Add(processId string) {
+	q.ids = append(q.ids, processId)
+}
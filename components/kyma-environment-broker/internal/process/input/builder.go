@@ -56,6 +56,7 @@ type InputBuilderFactory struct {
 	componentsProvider         ComponentListProvider
 	disabledComponentsProvider DisabledComponentsProvider
 	trialPlatformRegionMapping map[string]string
+	planRegions                map[string]map[string]struct{}
 }
 
 func NewInputBuilderFactory(optComponentsSvc OptionalComponentService, disabledComponentsProvider DisabledComponentsProvider, componentsListProvider ComponentListProvider, config Config,
@@ -65,6 +66,10 @@ func NewInputBuilderFactory(optComponentsSvc OptionalComponentService, disabledC
 	if err != nil {
 		return &InputBuilderFactory{}, errors.Wrap(err, "while creating components list for default Kyma version")
 	}
+	planRegions, err := parsePlanRegions(config.PlanRegions)
+	if err != nil {
+		return &InputBuilderFactory{}, errors.Wrap(err, "while parsing regions of the plans")
+	}
 
 	return &InputBuilderFactory{
 		config:                     config,
@@ -74,6 +79,7 @@ func NewInputBuilderFactory(optComponentsSvc OptionalComponentService, disabledC
 		componentsProvider:         componentsListProvider,
 		disabledComponentsProvider: disabledComponentsProvider,
 		trialPlatformRegionMapping: trialPlatformRegionMapping,
+		planRegions:                planRegions,
 	}, nil
 }
 
@@ -117,6 +123,9 @@ func (f *InputBuilderFactory) CreateProvisionInput(pp internal.ProvisioningParam
 	if err != nil {
 		return nil, errors.Wrap(err, "during createing provision input")
 	}
+	if err := f.validateRegion(provider, pp); err != nil {
+		return nil, err
+	}
 
 	initInput, err := f.initProvisionRuntimeInput(provider, version)
 	if err != nil {
@@ -136,10 +145,13 @@ func (f *InputBuilderFactory) CreateProvisionInput(pp internal.ProvisioningParam
 		labels:                    make(map[string]string),
 		mutex:                     nsync.NewNamedMutex(),
 		hyperscalerInputProvider:  provider,
+		provisioningParameters:    pp,
 		optionalComponentsService: f.optComponentsSvc,
 		componentsDisabler:        runtime.NewDisabledComponentsService(disabledComponents),
 		enabledOptionalComponents: map[string]struct{}{},
 		trialNodesNumber:          f.config.TrialNodesNumber,
+		controlPlaneCIDRs:         f.config.ControlPlaneCIDRs,
+		defaultResourceQuota:      f.config.DefaultResourceQuota,
 	}, nil
 }
 
@@ -239,10 +251,13 @@ func (f *InputBuilderFactory) CreateUpgradeInput(pp internal.ProvisioningParamet
 		mutex:                     nsync.NewNamedMutex(),
 		overrides:                 make(map[string][]*gqlschema.ConfigEntryInput, 0),
 		globalOverrides:           make([]*gqlschema.ConfigEntryInput, 0),
+		hyperscalerInputProvider:  provider,
+		provisioningParameters:    pp,
 		optionalComponentsService: f.optComponentsSvc,
 		componentsDisabler:        runtime.NewDisabledComponentsService(disabledComponents),
 		enabledOptionalComponents: map[string]struct{}{},
 		trialNodesNumber:          f.config.TrialNodesNumber,
+		defaultResourceQuota:      f.config.DefaultResourceQuota,
 	}, nil
 }
 
@@ -215,6 +215,77 @@ func TestInputBuilderFactory_ForPlan(t *testing.T) {
 
 }
 
+func TestInputBuilderFactory_PlanRegions(t *testing.T) {
+	// given
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", "1.10").Return([]v1alpha1.KymaComponent{}, nil)
+
+	ibf, err := NewInputBuilderFactory(nil, runtime.NewDisabledComponentsProvider(), componentsProvider,
+		Config{PlanRegions: []string{"gcp:europe-west3+us-central1"}}, "1.10", fixTrialRegionMapping())
+	require.NoError(t, err)
+	version := internal.RuntimeVersionData{Version: "1.10", Origin: internal.Defaults}
+
+	t.Run("should create input for the region available for the plan", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "")
+		pp.Parameters.Region = ptr.String("us-central1")
+
+		// when
+		_, err := ibf.CreateProvisionInput(pp, version)
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject the region which is not available for the plan", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "")
+		pp.Parameters.Region = ptr.String("asia-south1")
+
+		// when
+		_, err := ibf.CreateProvisionInput(pp, version)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, IsRegionNotAvailableError(err))
+		assert.EqualError(t, err, "region asia-south1 is not available for the plan gcp")
+	})
+
+	t.Run("should reject the default region which is not available for the plan", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "")
+		pp.Parameters.Region = nil
+
+		// when
+		_, err := ibf.CreateProvisionInput(pp, version)
+
+		// then
+		assert.True(t, IsRegionNotAvailableError(err))
+	})
+
+	t.Run("should create input for the plan without configured regions", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+
+		// when
+		_, err := ibf.CreateProvisionInput(pp, version)
+
+		// then
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewInputBuilderFactory_InvalidPlanRegions(t *testing.T) {
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", "1.10").Return([]v1alpha1.KymaComponent{}, nil)
+
+	for _, entry := range []string{"gcp", "gcp:", "unknown:europe-west3", "gcp:europe-west3+"} {
+		_, err := NewInputBuilderFactory(nil, runtime.NewDisabledComponentsProvider(), componentsProvider,
+			Config{PlanRegions: []string{entry}}, "1.10", fixTrialRegionMapping())
+		assert.Error(t, err, entry)
+	}
+}
+
 func fixProvisioningParameters(planID, kymaVersion string) internal.ProvisioningParameters {
 	pp := fixture.FixProvisioningParameters("")
 	pp.PlanID = planID
@@ -1,13 +1,17 @@
 package input
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime/components"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -18,8 +22,37 @@ import (
 const (
 	trialSuffixLength    = 5
 	maxRuntimeNameLength = 36
+
+	// ingressGatewayAnnotationsKey is the override of the istio component with the JSON encoded annotations of the ingress gateway service
+	ingressGatewayAnnotationsKey = "gateways.istio-ingressgateway.serviceAnnotations"
+
+	// resourceQuotaNamespacesKey and resourceQuotaPVCStorageKey are the global overrides with the maximum number of namespaces
+	// and the maximum storage of a persistent volume claim in the runtime
+	resourceQuotaNamespacesKey = "global.resourceQuota.namespaces"
+	resourceQuotaPVCStorageKey = "global.resourceQuota.pvcStorage"
+
+	// customDomainKey is the global override with the domain under which the components expose their services
+	customDomainKey = "global.domainName"
 )
 
+// internalLoadBalancerAnnotations make the cloud providers expose the ingress gateway with a load balancer in the cluster network
+var internalLoadBalancerAnnotations = map[string]map[string]string{
+	"azure":     {"service.beta.kubernetes.io/azure-load-balancer-internal": "true"},
+	"aws":       {"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+	"gcp":       {"networking.gke.io/load-balancer-type": "Internal"},
+	"openstack": {"service.beta.kubernetes.io/openstack-internal-load-balancer": "true"},
+}
+
+// loadBalancerSKUAnnotations select the load balancer SKU on the service of the ingress gateway, the SKUs which are not listed
+// are the defaults of the cloud providers or, for OpenStack, are set in the infrastructure configuration of the cluster
+var loadBalancerSKUAnnotations = map[string]map[string]map[string]string{
+	"aws": {"nlb": {"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"}},
+	"gcp": {
+		"standard": {"cloud.google.com/network-tier": "Standard"},
+		"premium":  {"cloud.google.com/network-tier": "Premium"},
+	},
+}
+
 type Config struct {
 	URL                         string
 	Timeout                     time.Duration               `envconfig:"default=12h"`
@@ -29,6 +62,19 @@ type Config struct {
 	MachineImageVersion         string                      `envconfig:"optional"`
 	TrialNodesNumber            int                         `envconfig:"optional"`
 	DefaultTrialProvider        internal.TrialCloudProvider `envconfig:"default=Azure"` // could be: Azure, AWS, GCP
+	// ControlPlaneCIDRs are always allowed to access the API server of a private cluster, so that the Provisioner can install Kyma and apply the overrides
+	ControlPlaneCIDRs []string `envconfig:"optional"`
+	// DefaultResourceQuota is applied to the runtimes which do not request the resource quota in the provisioning parameters
+	DefaultResourceQuota ResourceQuotaConfig
+	// PlanRegions restricts the regions in which the plans are available, the entries have the format <plan>:<region>[+<region>].
+	// The plans which are not listed are available in every region.
+	PlanRegions []string `envconfig:"optional"`
+}
+
+// ResourceQuotaConfig defines the resource quota of the runtimes, zero values are not applied
+type ResourceQuotaConfig struct {
+	Namespaces int `envconfig:"default=0"`
+	PVCSizeGb  int `envconfig:"default=0"`
 }
 
 type RuntimeInput struct {
@@ -48,7 +94,9 @@ type RuntimeInput struct {
 	componentsDisabler        ComponentsDisabler
 	enabledOptionalComponents map[string]struct{}
 
-	trialNodesNumber int
+	trialNodesNumber     int
+	controlPlaneCIDRs    []string
+	defaultResourceQuota ResourceQuotaConfig
 }
 
 func (r *RuntimeInput) EnableOptionalComponent(componentName string) internal.ProvisionerInputCreator {
@@ -127,6 +175,18 @@ func (r *RuntimeInput) CreateProvisionRuntimeInput() (gqlschema.ProvisionRuntime
 			name:    "disabling optional components that were not selected",
 			execute: r.resolveOptionalComponentsForProvisionRuntime,
 		},
+		{
+			name:    "applying ingress configuration",
+			execute: r.applyIngressOverrides,
+		},
+		{
+			name:    "applying resource quota",
+			execute: r.applyResourceQuotaOverrides,
+		},
+		{
+			name:    "applying custom domain",
+			execute: r.applyCustomDomainOverrides,
+		},
 		{
 			name:    "applying components overrides",
 			execute: r.applyOverridesForProvisionRuntime,
@@ -165,6 +225,18 @@ func (r *RuntimeInput) CreateUpgradeRuntimeInput() (gqlschema.UpgradeRuntimeInpu
 			name:    "disabling optional components that were not selected",
 			execute: r.resolveOptionalComponentsForUpgradeRuntime,
 		},
+		{
+			name:    "applying ingress configuration",
+			execute: r.applyIngressOverrides,
+		},
+		{
+			name:    "applying resource quota",
+			execute: r.applyResourceQuotaOverrides,
+		},
+		{
+			name:    "applying custom domain",
+			execute: r.applyCustomDomainOverrides,
+		},
 		{
 			name:    "applying components overrides",
 			execute: r.applyOverridesForUpgradeRuntime,
@@ -173,10 +245,6 @@ func (r *RuntimeInput) CreateUpgradeRuntimeInput() (gqlschema.UpgradeRuntimeInpu
 			name:    "applying global overrides",
 			execute: r.applyGlobalOverridesForUpgradeRuntime,
 		},
-		{
-			name:    "set number of nodes from configuration",
-			execute: r.setNodesForTrialProvision,
-		},
 	} {
 		if err := step.execute(); err != nil {
 			return gqlschema.UpgradeRuntimeInput{}, errors.Wrapf(err, "while %s", step.name)
@@ -225,6 +293,30 @@ func (r *RuntimeInput) applyProvisioningParametersForProvisionRuntime() error {
 	if params.LicenceType != nil {
 		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.LicenceType = params.LicenceType
 	}
+	if params.Seed != nil {
+		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.Seed = params.Seed
+	}
+	updateString(&r.provisionRuntimeInput.ClusterConfig.GardenerConfig.KubernetesVersion, params.KubernetesVersion)
+	if params.MachineImage != nil && params.MachineImageVersion != nil {
+		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.MachineImage = params.MachineImage
+		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.MachineImageVersion = params.MachineImageVersion
+	}
+	if len(params.Annotations) > 0 {
+		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.Annotations = annotationsInput(params.Annotations)
+	}
+	if params.PrivateCluster != nil && *params.PrivateCluster {
+		r.provisionRuntimeInput.ClusterConfig.GardenerConfig.Networking = &gqlschema.NetworkingInput{
+			PrivateCluster: params.PrivateCluster,
+			AllowedCidrs:   mergeCIDRs(r.controlPlaneCIDRs, params.AllowedCIDRs),
+		}
+	}
+	r.provisionRuntimeInput.ClusterConfig.GardenerConfig.ControlPlane = controlPlaneInput(params)
+	maintenanceWindow, err := maintenanceWindowInput(params.MaintenanceWindow, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "while converting maintenance window")
+	}
+	r.provisionRuntimeInput.ClusterConfig.GardenerConfig.MaintenanceWindow = maintenanceWindow
+	r.provisionRuntimeInput.ClusterConfig.GardenerConfig.KubernetesConfig = kubernetesConfigInput(params.KubernetesConfig)
 
 	r.hyperscalerInputProvider.ApplyParameters(r.provisionRuntimeInput.ClusterConfig, r.provisioningParameters)
 
@@ -232,7 +324,19 @@ func (r *RuntimeInput) applyProvisioningParametersForProvisionRuntime() error {
 }
 
 func (r *RuntimeInput) applyProvisioningParametersForUpgradeShoot() error {
-	// As of now cluster upgrade doesn't support upgrading parameters which could also be specified as provisioning parameters
+	// As of now cluster upgrade doesn't support upgrading parameters which could also be specified as provisioning parameters,
+	// except the target secret which is re-resolved when the secret binding of the global account changed,
+	// the requested machine image which is kept instead of the default one
+	// and the kubelet and API server options which the Provisioner applies again on every upgrade
+	if r.provisioningParameters.Parameters.TargetSecret != nil {
+		r.upgradeShootInput.GardenerConfig.TargetSecret = r.provisioningParameters.Parameters.TargetSecret
+	}
+	params := r.provisioningParameters.Parameters
+	if params.MachineImage != nil && params.MachineImageVersion != nil {
+		r.upgradeShootInput.GardenerConfig.MachineImage = params.MachineImage
+		r.upgradeShootInput.GardenerConfig.MachineImageVersion = params.MachineImageVersion
+	}
+	r.upgradeShootInput.GardenerConfig.KubernetesConfig = kubernetesConfigInput(params.KubernetesConfig)
 	return nil
 }
 
@@ -240,12 +344,7 @@ func (r *RuntimeInput) resolveOptionalComponentsForProvisionRuntime() error {
 	r.mutex.Lock("enabledOptionalComponents")
 	defer r.mutex.Unlock("enabledOptionalComponents")
 
-	componentsToInstall := []string{}
-	componentsToInstall = append(componentsToInstall, r.provisioningParameters.Parameters.OptionalComponentsToInstall...)
-	for name := range r.enabledOptionalComponents {
-		componentsToInstall = append(componentsToInstall, name)
-	}
-	toDisable := r.optionalComponentsService.ComputeComponentsToDisable(componentsToInstall)
+	toDisable := r.componentsToDisable()
 
 	filterOut, err := r.optionalComponentsService.ExecuteDisablers(r.provisionRuntimeInput.KymaConfig.Components, toDisable...)
 	if err != nil {
@@ -261,12 +360,7 @@ func (r *RuntimeInput) resolveOptionalComponentsForUpgradeRuntime() error {
 	r.mutex.Lock("enabledOptionalComponents")
 	defer r.mutex.Unlock("enabledOptionalComponents")
 
-	componentsToInstall := []string{}
-	componentsToInstall = append(componentsToInstall, r.provisioningParameters.Parameters.OptionalComponentsToInstall...)
-	for name := range r.enabledOptionalComponents {
-		componentsToInstall = append(componentsToInstall, name)
-	}
-	toDisable := r.optionalComponentsService.ComputeComponentsToDisable(componentsToInstall)
+	toDisable := r.componentsToDisable()
 
 	filterOut, err := r.optionalComponentsService.ExecuteDisablers(r.upgradeRuntimeInput.KymaConfig.Components, toDisable...)
 	if err != nil {
@@ -278,6 +372,41 @@ func (r *RuntimeInput) resolveOptionalComponentsForUpgradeRuntime() error {
 	return nil
 }
 
+// componentsToDisable returns the names of the disablers to execute, the components toggled by the client are applied
+// on top of the optional components. The toggles are stored in the provisioning parameters, so they are reapplied by every upgrade.
+// The caller must hold the enabledOptionalComponents lock.
+func (r *RuntimeInput) componentsToDisable() []string {
+	toggles := r.provisioningParameters.Parameters.ComponentToggles
+
+	componentsToInstall := []string{}
+	componentsToInstall = append(componentsToInstall, r.provisioningParameters.Parameters.OptionalComponentsToInstall...)
+	for name := range r.enabledOptionalComponents {
+		componentsToInstall = append(componentsToInstall, name)
+	}
+	if toggles != nil {
+		componentsToInstall = append(componentsToInstall, toggles.Enable...)
+	}
+	toDisable := r.optionalComponentsService.ComputeComponentsToDisable(componentsToInstall)
+	if toggles == nil {
+		return toDisable
+	}
+
+	// the optional components which are not enabled are already disabled
+	seen := map[string]struct{}{}
+	for _, name := range toDisable {
+		seen[strings.ToLower(name)] = struct{}{}
+	}
+	for _, name := range toggles.Disable {
+		if _, found := seen[strings.ToLower(name)]; found {
+			continue
+		}
+		seen[strings.ToLower(name)] = struct{}{}
+		toDisable = append(toDisable, name)
+	}
+
+	return toDisable
+}
+
 func (r *RuntimeInput) disableComponentsForProvisionRuntime() error {
 	filterOut, err := r.componentsDisabler.DisableComponents(r.provisionRuntimeInput.KymaConfig.Components)
 	if err != nil {
@@ -320,6 +449,51 @@ func (r *RuntimeInput) applyOverridesForUpgradeRuntime() error {
 	return nil
 }
 
+// applyIngressOverrides passes the ingress configuration to the istio component. The configuration is stored in the provisioning
+// parameters, so it is reapplied by every upgrade. No configuration keeps the defaults of the component.
+func (r *RuntimeInput) applyIngressOverrides() error {
+	ingress := r.provisioningParameters.Parameters.Ingress
+	if ingress == nil {
+		return nil
+	}
+
+	annotations := map[string]string{}
+	for key, value := range ingress.Annotations {
+		annotations[key] = value
+	}
+	provider := ""
+	if r.hyperscalerInputProvider != nil {
+		provider = r.hyperscalerInputProvider.Defaults().GardenerConfig.Provider
+	}
+	if ingress.LoadBalancerSKU != nil {
+		for key, value := range loadBalancerSKUAnnotations[provider][*ingress.LoadBalancerSKU] {
+			annotations[key] = value
+		}
+	}
+	if ingress.LoadBalancerType != nil && *ingress.LoadBalancerType == internal.IngressLoadBalancerInternal {
+		internalAnnotations, found := internalLoadBalancerAnnotations[provider]
+		if !found {
+			return errors.Errorf("internal load balancer is not supported for provider %q", provider)
+		}
+		for key, value := range internalAnnotations {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(annotations)
+	if err != nil {
+		return errors.Wrap(err, "while encoding ingress annotations")
+	}
+	r.AppendOverrides(components.Istio, []*gqlschema.ConfigEntryInput{
+		{Key: ingressGatewayAnnotationsKey, Value: string(encoded)},
+	})
+
+	return nil
+}
+
 func (r *RuntimeInput) applyGlobalOverridesForProvisionRuntime() error {
 	r.provisionRuntimeInput.KymaConfig.Configuration = r.globalOverrides
 	return nil
@@ -398,3 +572,173 @@ func trimLastCharacters(s string, count int) string {
 	s = s[:len(s)-count]
 	return s
 }
+
+func annotationsInput(annotations map[string]string) []*gqlschema.AnnotationInput {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var input []*gqlschema.AnnotationInput
+	for _, key := range keys {
+		input = append(input, &gqlschema.AnnotationInput{
+			Key:   key,
+			Value: annotations[key],
+		})
+	}
+	return input
+}
+
+func mergeCIDRs(controlPlane, allowed []string) []string {
+	seen := make(map[string]struct{})
+	var cidrs []string
+	for _, cidr := range append(append([]string{}, controlPlane...), allowed...) {
+		if _, found := seen[cidr]; found {
+			continue
+		}
+		seen[cidr] = struct{}{}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs
+}
+
+// applyResourceQuotaOverrides passes the resource quota to all components. The limits requested in the provisioning parameters
+// override the defaults, the parameters are stored, so the resource quota is reapplied by every upgrade.
+func (r *RuntimeInput) applyResourceQuotaOverrides() error {
+	namespaces := r.defaultResourceQuota.Namespaces
+	pvcSizeGb := r.defaultResourceQuota.PVCSizeGb
+	if quota := r.provisioningParameters.Parameters.ResourceQuota; quota != nil {
+		updateInt(&namespaces, quota.Namespaces)
+		updateInt(&pvcSizeGb, quota.PVCSizeGb)
+	}
+
+	var overrides []*gqlschema.ConfigEntryInput
+	if namespaces > 0 {
+		overrides = append(overrides, &gqlschema.ConfigEntryInput{Key: resourceQuotaNamespacesKey, Value: strconv.Itoa(namespaces)})
+	}
+	if pvcSizeGb > 0 {
+		overrides = append(overrides, &gqlschema.ConfigEntryInput{Key: resourceQuotaPVCStorageKey, Value: fmt.Sprintf("%dGi", pvcSizeGb)})
+	}
+	if len(overrides) > 0 {
+		r.AppendGlobalOverrides(overrides)
+	}
+
+	return nil
+}
+
+// applyCustomDomainOverrides passes the custom domain requested in the provisioning parameters to all components,
+// the domain generated for the cluster is used by the components if the custom domain is not requested
+func (r *RuntimeInput) applyCustomDomainOverrides() error {
+	if r.provisioningParameters.Parameters.CustomDomain == nil {
+		return nil
+	}
+	r.AppendGlobalOverrides([]*gqlschema.ConfigEntryInput{
+		{Key: customDomainKey, Value: *r.provisioningParameters.Parameters.CustomDomain},
+	})
+
+	return nil
+}
+
+// controlPlaneInput returns the etcd settings requested in the provisioning parameters, nil means the defaults are used.
+// The single-node control plane is the default of the landscape, so only the highly available one is sent.
+func controlPlaneInput(params internal.ProvisioningParametersDTO) *gqlschema.ControlPlaneInput {
+	highAvailability := params.ControlPlaneHA != nil && *params.ControlPlaneHA
+	if params.EtcdEncryption == nil && params.BackupRetentionDays == nil && !highAvailability {
+		return nil
+	}
+	input := &gqlschema.ControlPlaneInput{
+		BackupRetentionDays: params.BackupRetentionDays,
+	}
+	if highAvailability {
+		input.HighAvailability = params.ControlPlaneHA
+	}
+	if params.EtcdEncryption != nil {
+		input.EtcdEncryption = &params.EtcdEncryption.Enabled
+		input.EtcdEncryptionKeyRef = params.EtcdEncryption.KeyRef
+	}
+	return input
+}
+
+// kubernetesConfigInput returns the kubelet and API server options requested in the provisioning parameters,
+// nil means the defaults are used
+func kubernetesConfigInput(config *internal.KubernetesConfig) *gqlschema.KubernetesConfigInput {
+	if config == nil {
+		return nil
+	}
+	input := &gqlschema.KubernetesConfigInput{}
+	if kubelet := config.Kubelet; kubelet != nil {
+		input.Kubelet = &gqlschema.KubeletConfigInput{
+			FeatureGates: featureGatesInput(kubelet.FeatureGates),
+			MaxPods:      kubelet.MaxPods,
+			PodPidsLimit: kubelet.PodPIDsLimit,
+		}
+		if len(kubelet.EvictionHard) > 0 {
+			input.Kubelet.EvictionHard = &gqlschema.EvictionThresholdsInput{
+				MemoryAvailable:   evictionThreshold(kubelet.EvictionHard, internal.EvictionMemoryAvailable),
+				ImageFSAvailable:  evictionThreshold(kubelet.EvictionHard, internal.EvictionImageFSAvailable),
+				ImageFSInodesFree: evictionThreshold(kubelet.EvictionHard, internal.EvictionImageFSInodesFree),
+				NodeFSAvailable:   evictionThreshold(kubelet.EvictionHard, internal.EvictionNodeFSAvailable),
+				NodeFSInodesFree:  evictionThreshold(kubelet.EvictionHard, internal.EvictionNodeFSInodesFree),
+			}
+		}
+	}
+	if config.APIServer != nil {
+		input.APIServer = &gqlschema.APIServerConfigInput{
+			FeatureGates: featureGatesInput(config.APIServer.FeatureGates),
+		}
+	}
+	return input
+}
+
+// featureGatesInput returns the feature gates sorted by the name, so the same parameters always give the same input
+func featureGatesInput(featureGates map[string]bool) []*gqlschema.FeatureGateInput {
+	if len(featureGates) == 0 {
+		return nil
+	}
+	input := make([]*gqlschema.FeatureGateInput, 0, len(featureGates))
+	for name, enabled := range featureGates {
+		input = append(input, &gqlschema.FeatureGateInput{Name: name, Enabled: enabled})
+	}
+	sort.Slice(input, func(i, j int) bool { return input[i].Name < input[j].Name })
+	return input
+}
+
+func evictionThreshold(thresholds map[string]string, signal string) *string {
+	threshold, found := thresholds[signal]
+	if !found {
+		return nil
+	}
+	return &threshold
+}
+
+// maintenanceWindowDuration is the length of the maintenance window starting at the requested time
+const maintenanceWindowDuration = time.Hour
+
+// maintenanceWindowInput converts the local begin of the maintenance window to the Gardener time window with the offset
+// of the time zone valid at the given time, nil means the default window of the landscape is used
+func maintenanceWindowInput(window *internal.MaintenanceWindow, now time.Time) (*gqlschema.MaintenanceWindowInput, error) {
+	if window == nil {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while loading time zone %s", window.TimeZone)
+	}
+	begin, err := time.Parse(broker.MaintenanceWindowBeginLayout, window.Begin)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing begin %s", window.Begin)
+	}
+
+	local := now.In(location)
+	beginAt := time.Date(local.Year(), local.Month(), local.Day(), begin.Hour(), begin.Minute(), 0, 0, location)
+	// the end has the same offset as the begin, so the window does not shrink or grow when the daylight saving time changes
+	_, offset := beginAt.Zone()
+	zone := time.FixedZone(window.TimeZone, offset)
+	const layout = "150405-0700"
+
+	return &gqlschema.MaintenanceWindowInput{
+		Begin: beginAt.In(zone).Format(layout),
+		End:   beginAt.In(zone).Add(maintenanceWindowDuration).Format(layout),
+	}, nil
+}
@@ -2,6 +2,7 @@ package input
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
@@ -189,6 +190,336 @@ func TestDisabledComponentsForPlanNotExist(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestShouldApplyComponentToggles(t *testing.T) {
+	// One optional component: Kiali
+	// Two default components: Tracing and dex, only dex can be toggled
+	newBuilder := func(t *testing.T) CreatorForPlan {
+		optComponentsSvc := runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{
+			components.Kiali: runtime.NewGenericComponentDisabler(components.Kiali),
+		})
+		optComponentsSvc.AddToggleableComponents(components.Kiali, "dex")
+
+		componentsProvider := &automock.ComponentListProvider{}
+		componentsProvider.On("AllComponents", mock.AnythingOfType("string")).
+			Return([]v1alpha1.KymaComponent{
+				{Name: components.Kiali},
+				{Name: components.Tracing},
+				{Name: "dex"},
+			}, nil)
+
+		builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+		require.NoError(t, err)
+		return builder
+	}
+
+	t.Run("When creating ProvisionRuntimeInput", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+		pp.Parameters.ComponentToggles = &internal.ComponentToggles{
+			Enable:  []string{components.Kiali},
+			Disable: []string{"dex"},
+		}
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: components.Kiali,
+		})
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: components.Tracing,
+		})
+		assert.Len(t, input.KymaConfig.Components, 2)
+	})
+
+	t.Run("When creating UpgradeRuntimeInput", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "1.14.0")
+		pp.Parameters.ComponentToggles = &internal.ComponentToggles{
+			Enable:  []string{components.Kiali},
+			Disable: []string{"dex"},
+		}
+
+		creator, err := newBuilder(t).CreateUpgradeInput(pp, internal.RuntimeVersionData{Version: "1.14.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateUpgradeRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: components.Kiali,
+		})
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: components.Tracing,
+		})
+		assert.Len(t, input.KymaConfig.Components, 2)
+	})
+}
+
+func TestShouldApplyIngressConfiguration(t *testing.T) {
+	newBuilder := func(t *testing.T) CreatorForPlan {
+		componentsProvider := &automock.ComponentListProvider{}
+		componentsProvider.On("AllComponents", mock.AnythingOfType("string")).
+			Return([]v1alpha1.KymaComponent{
+				{Name: components.Istio},
+				{Name: "dex"},
+			}, nil)
+
+		builder, err := NewInputBuilderFactory(runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{}), runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+		require.NoError(t, err)
+		return builder
+	}
+
+	t.Run("When creating ProvisionRuntimeInput with internal load balancer", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+		pp.Parameters.Ingress = &internal.IngressConfig{
+			LoadBalancerType: ptr.String(internal.IngressLoadBalancerInternal),
+			Annotations:      map[string]string{"service.beta.kubernetes.io/azure-load-balancer-internal-subnet": "ingress"},
+		}
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertOverrides(t, components.Istio, input.KymaConfig.Components, []*gqlschema.ConfigEntryInput{
+			{
+				Key:   ingressGatewayAnnotationsKey,
+				Value: `{"service.beta.kubernetes.io/azure-load-balancer-internal":"true","service.beta.kubernetes.io/azure-load-balancer-internal-subnet":"ingress"}`,
+			},
+		})
+	})
+
+	t.Run("When creating ProvisionRuntimeInput without ingress configuration", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		istio, found := find(input.KymaConfig.Components, components.Istio)
+		require.True(t, found)
+		assert.Empty(t, istio.Configuration)
+	})
+
+	t.Run("When creating UpgradeRuntimeInput the stored ingress configuration is reapplied", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "1.14.0")
+		pp.Parameters.Ingress = &internal.IngressConfig{
+			LoadBalancerType: ptr.String(internal.IngressLoadBalancerInternal),
+		}
+
+		creator, err := newBuilder(t).CreateUpgradeInput(pp, internal.RuntimeVersionData{Version: "1.14.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateUpgradeRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertOverrides(t, components.Istio, input.KymaConfig.Components, []*gqlschema.ConfigEntryInput{
+			{
+				Key:   ingressGatewayAnnotationsKey,
+				Value: `{"networking.gke.io/load-balancer-type":"Internal"}`,
+			},
+		})
+	})
+
+	t.Run("When creating ProvisionRuntimeInput with load balancer SKU set on the service", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AWSPlanID, "")
+		pp.Parameters.Ingress = &internal.IngressConfig{LoadBalancerSKU: ptr.String("nlb")}
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertOverrides(t, components.Istio, input.KymaConfig.Components, []*gqlschema.ConfigEntryInput{
+			{
+				Key:   ingressGatewayAnnotationsKey,
+				Value: `{"service.beta.kubernetes.io/aws-load-balancer-type":"nlb"}`,
+			},
+		})
+	})
+
+	t.Run("When creating ProvisionRuntimeInput with load balancer SKU set in the infrastructure configuration", func(t *testing.T) {
+		for sku, expectedProvider := range map[string]string{"": "f5", "octavia": "octavia"} {
+			// given
+			pp := fixProvisioningParameters(broker.OpenStackPlanID, "")
+			// the OpenStack plan has no volume size
+			pp.Parameters.VolumeSizeGb = nil
+			if sku != "" {
+				pp.Parameters.Ingress = &internal.IngressConfig{LoadBalancerSKU: ptr.String(sku)}
+			}
+
+			creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+			require.NoError(t, err)
+
+			// when
+			input, err := creator.CreateProvisionRuntimeInput()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, expectedProvider, input.ClusterConfig.GardenerConfig.ProviderSpecificConfig.OpenStackConfig.LoadBalancerProvider)
+			istio, found := find(input.KymaConfig.Components, components.Istio)
+			require.True(t, found)
+			assert.Empty(t, istio.Configuration)
+		}
+	})
+}
+
+func TestShouldApplyResourceQuota(t *testing.T) {
+	newBuilder := func(t *testing.T) CreatorForPlan {
+		componentsProvider := &automock.ComponentListProvider{}
+		componentsProvider.On("AllComponents", mock.AnythingOfType("string")).
+			Return([]v1alpha1.KymaComponent{{Name: "dex"}}, nil)
+
+		cfg := Config{DefaultResourceQuota: ResourceQuotaConfig{Namespaces: 50, PVCSizeGb: 20}}
+		builder, err := NewInputBuilderFactory(runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{}), runtime.NewDisabledComponentsProvider(), componentsProvider, cfg, "not-important", fixTrialRegionMapping())
+		require.NoError(t, err)
+		return builder
+	}
+
+	t.Run("When creating ProvisionRuntimeInput the default resource quota is applied", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []*gqlschema.ConfigEntryInput{
+			{Key: resourceQuotaNamespacesKey, Value: "50"},
+			{Key: resourceQuotaPVCStorageKey, Value: "20Gi"},
+		}, input.KymaConfig.Configuration)
+	})
+
+	t.Run("When creating ProvisionRuntimeInput the requested resource quota overrides the default", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+		pp.Parameters.ResourceQuota = &internal.ResourceQuota{Namespaces: ptr.Integer(150)}
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []*gqlschema.ConfigEntryInput{
+			{Key: resourceQuotaNamespacesKey, Value: "150"},
+			{Key: resourceQuotaPVCStorageKey, Value: "20Gi"},
+		}, input.KymaConfig.Configuration)
+	})
+
+	t.Run("When creating UpgradeRuntimeInput the requested resource quota is reapplied", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "1.14.0")
+		pp.Parameters.ResourceQuota = &internal.ResourceQuota{PVCSizeGb: ptr.Integer(100)}
+
+		creator, err := newBuilder(t).CreateUpgradeInput(pp, internal.RuntimeVersionData{Version: "1.14.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateUpgradeRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []*gqlschema.ConfigEntryInput{
+			{Key: resourceQuotaNamespacesKey, Value: "50"},
+			{Key: resourceQuotaPVCStorageKey, Value: "100Gi"},
+		}, input.KymaConfig.Configuration)
+	})
+}
+
+func TestShouldApplyCustomDomain(t *testing.T) {
+	newBuilder := func(t *testing.T) CreatorForPlan {
+		componentsProvider := &automock.ComponentListProvider{}
+		componentsProvider.On("AllComponents", mock.AnythingOfType("string")).
+			Return([]v1alpha1.KymaComponent{{Name: "dex"}}, nil)
+
+		builder, err := NewInputBuilderFactory(runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{}), runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+		require.NoError(t, err)
+		return builder
+	}
+
+	t.Run("When creating ProvisionRuntimeInput without the custom domain the domain is not overridden", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.Empty(t, input.KymaConfig.Configuration)
+	})
+
+	t.Run("When creating ProvisionRuntimeInput the custom domain is applied", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.AzurePlanID, "")
+		pp.Parameters.CustomDomain = ptr.String("kyma.example.com")
+
+		creator, err := newBuilder(t).CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.10.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateProvisionRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []*gqlschema.ConfigEntryInput{
+			{Key: customDomainKey, Value: "kyma.example.com"},
+		}, input.KymaConfig.Configuration)
+	})
+
+	t.Run("When creating UpgradeRuntimeInput the custom domain is reapplied", func(t *testing.T) {
+		// given
+		pp := fixProvisioningParameters(broker.GCPPlanID, "1.14.0")
+		pp.Parameters.CustomDomain = ptr.String("kyma.example.com")
+
+		creator, err := newBuilder(t).CreateUpgradeInput(pp, internal.RuntimeVersionData{Version: "1.14.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateUpgradeRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []*gqlschema.ConfigEntryInput{
+			{Key: customDomainKey, Value: "kyma.example.com"},
+		}, input.KymaConfig.Configuration)
+	})
+}
+
 func TestInputBuilderFactoryOverrides(t *testing.T) {
 	t.Run("should append overrides for the same components multiple times", func(t *testing.T) {
 		// given
@@ -443,6 +774,405 @@ func TestShouldSetNumberOfNodesForTrialPlan(t *testing.T) {
 	assert.Equal(t, 2, input.ClusterConfig.GardenerConfig.AutoScalerMax)
 }
 
+func TestShouldForwardAnnotations(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AzurePlanID, "")
+	pp.Parameters.Annotations = map[string]string{
+		"project":          "control-plane",
+		"example.com/team": "kyma",
+	}
+
+	creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+	require.NoError(t, err)
+	creator.SetProvisioningParameters(pp)
+
+	// when
+	input, err := creator.CreateProvisionRuntimeInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, []*gqlschema.AnnotationInput{
+		{Key: "example.com/team", Value: "kyma"},
+		{Key: "project", Value: "control-plane"},
+	}, input.ClusterConfig.GardenerConfig.Annotations)
+}
+
+func TestShouldAllowControlPlaneCIDRsForPrivateCluster(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	config := Config{ControlPlaneCIDRs: []string{"10.180.0.0/16", "10.0.0.0/8"}}
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, config, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AzurePlanID, "")
+	pp.Parameters.PrivateCluster = ptr.Bool(true)
+	pp.Parameters.AllowedCIDRs = []string{"10.0.0.0/8", "192.168.1.1/32"}
+
+	creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+	require.NoError(t, err)
+	creator.SetProvisioningParameters(pp)
+
+	// when
+	input, err := creator.CreateProvisionRuntimeInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, &gqlschema.NetworkingInput{
+		PrivateCluster: ptr.Bool(true),
+		AllowedCidrs:   []string{"10.180.0.0/16", "10.0.0.0/8", "192.168.1.1/32"},
+	}, input.ClusterConfig.GardenerConfig.Networking)
+}
+
+func TestShouldNotSetNetworkingForPublicCluster(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	config := Config{ControlPlaneCIDRs: []string{"10.180.0.0/16"}}
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, config, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AzurePlanID, "")
+
+	creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+	require.NoError(t, err)
+	creator.SetProvisioningParameters(pp)
+
+	// when
+	input, err := creator.CreateProvisionRuntimeInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Nil(t, input.ClusterConfig.GardenerConfig.Networking)
+}
+
+func TestShouldForwardControlPlaneSettings(t *testing.T) {
+	for name, tc := range map[string]struct {
+		etcdEncryption      *internal.EtcdEncryption
+		backupRetentionDays *int
+		controlPlaneHA      *bool
+		expected            *gqlschema.ControlPlaneInput
+	}{
+		"defaults": {},
+		"single-node control plane": {
+			controlPlaneHA: ptr.Bool(false),
+		},
+		"highly available control plane": {
+			controlPlaneHA: ptr.Bool(true),
+			expected:       &gqlschema.ControlPlaneInput{HighAvailability: ptr.Bool(true)},
+		},
+		"etcd encryption with customer-managed key": {
+			etcdEncryption: &internal.EtcdEncryption{Enabled: true, KeyRef: ptr.String("https://vault.vault.azure.net/keys/etcd")},
+			expected: &gqlschema.ControlPlaneInput{
+				EtcdEncryption:       ptr.Bool(true),
+				EtcdEncryptionKeyRef: ptr.String("https://vault.vault.azure.net/keys/etcd"),
+			},
+		},
+		"backup retention": {
+			backupRetentionDays: ptr.Integer(14),
+			expected:            &gqlschema.ControlPlaneInput{BackupRetentionDays: ptr.Integer(14)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+			componentsProvider := &automock.ComponentListProvider{}
+			componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+			builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+			assert.NoError(t, err)
+
+			pp := fixProvisioningParameters(broker.AzurePlanID, "")
+			pp.Parameters.EtcdEncryption = tc.etcdEncryption
+			pp.Parameters.BackupRetentionDays = tc.backupRetentionDays
+			pp.Parameters.ControlPlaneHA = tc.controlPlaneHA
+
+			creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+			require.NoError(t, err)
+			creator.SetProvisioningParameters(pp)
+
+			// when
+			input, err := creator.CreateProvisionRuntimeInput()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, tc.expected, input.ClusterConfig.GardenerConfig.ControlPlane)
+		})
+	}
+}
+
+func TestShouldForwardMaintenanceWindow(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AzurePlanID, "")
+	pp.Parameters.MaintenanceWindow = &internal.MaintenanceWindow{Begin: "22:00", TimeZone: "UTC"}
+
+	creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+	require.NoError(t, err)
+	creator.SetProvisioningParameters(pp)
+
+	// when
+	input, err := creator.CreateProvisionRuntimeInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, &gqlschema.MaintenanceWindowInput{Begin: "220000+0000", End: "230000+0000"}, input.ClusterConfig.GardenerConfig.MaintenanceWindow)
+}
+
+func TestMaintenanceWindowInput(t *testing.T) {
+	winter := time.Date(2021, time.January, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2021, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		window   *internal.MaintenanceWindow
+		now      time.Time
+		expected *gqlschema.MaintenanceWindowInput
+	}{
+		"default window": {
+			now: winter,
+		},
+		"standard time": {
+			window:   &internal.MaintenanceWindow{Begin: "22:00", TimeZone: "Europe/Berlin"},
+			now:      winter,
+			expected: &gqlschema.MaintenanceWindowInput{Begin: "220000+0100", End: "230000+0100"},
+		},
+		"daylight saving time": {
+			window:   &internal.MaintenanceWindow{Begin: "22:00", TimeZone: "Europe/Berlin"},
+			now:      summer,
+			expected: &gqlschema.MaintenanceWindowInput{Begin: "220000+0200", End: "230000+0200"},
+		},
+		"window crossing midnight with negative offset": {
+			window:   &internal.MaintenanceWindow{Begin: "23:30", TimeZone: "America/New_York"},
+			now:      winter,
+			expected: &gqlschema.MaintenanceWindowInput{Begin: "233000-0500", End: "003000-0500"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			got, err := maintenanceWindowInput(tc.window, tc.now)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		// when
+		_, err := maintenanceWindowInput(&internal.MaintenanceWindow{Begin: "22:00", TimeZone: "Europe/Atlantis"}, winter)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestShouldForwardSeed(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	for name, seed := range map[string]*string{
+		"requested seed": ptr.String("az-eu1"),
+		"default seed":   nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			pp := fixProvisioningParameters(broker.AzurePlanID, "")
+			pp.Parameters.Seed = seed
+
+			creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+			require.NoError(t, err)
+			creator.SetProvisioningParameters(pp)
+
+			// when
+			input, err := creator.CreateProvisionRuntimeInput()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, seed, input.ClusterConfig.GardenerConfig.Seed)
+		})
+	}
+}
+
+func TestShouldForwardKubernetesVersion(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{KubernetesVersion: "1.18.17"}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		requested *string
+		expected  string
+	}{
+		"requested version": {requested: ptr.String("1.19.10"), expected: "1.19.10"},
+		"default version":   {requested: nil, expected: "1.18.17"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pp := fixProvisioningParameters(broker.AzurePlanID, "")
+			pp.Parameters.KubernetesVersion = tc.requested
+
+			creator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+			require.NoError(t, err)
+			creator.SetProvisioningParameters(pp)
+
+			// when
+			input, err := creator.CreateProvisionRuntimeInput()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, tc.expected, input.ClusterConfig.GardenerConfig.KubernetesVersion)
+		})
+	}
+}
+
+func TestShouldForwardMachineImage(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{MachineImage: "gardenlinux", MachineImageVersion: "184.0.0"}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		requestedImage   *string
+		requestedVersion *string
+		expectedImage    string
+		expectedVersion  string
+	}{
+		"requested machine image": {
+			requestedImage:   ptr.String("ubuntu"),
+			requestedVersion: ptr.String("18.4.20201029"),
+			expectedImage:    "ubuntu",
+			expectedVersion:  "18.4.20201029",
+		},
+		"default machine image": {
+			expectedImage:   "gardenlinux",
+			expectedVersion: "184.0.0",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pp := fixProvisioningParameters(broker.AzurePlanID, "")
+			pp.Parameters.MachineImage = tc.requestedImage
+			pp.Parameters.MachineImageVersion = tc.requestedVersion
+
+			provisionCreator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+			require.NoError(t, err)
+			provisionCreator.SetProvisioningParameters(pp)
+			upgradeCreator, err := builder.CreateUpgradeShootInput(pp)
+			require.NoError(t, err)
+			upgradeCreator.SetProvisioningParameters(pp)
+
+			// when
+			provisionInput, err := provisionCreator.CreateProvisionRuntimeInput()
+			require.NoError(t, err)
+			upgradeInput, err := upgradeCreator.CreateUpgradeShootInput()
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, &tc.expectedImage, provisionInput.ClusterConfig.GardenerConfig.MachineImage)
+			assert.Equal(t, &tc.expectedVersion, provisionInput.ClusterConfig.GardenerConfig.MachineImageVersion)
+			assert.Equal(t, &tc.expectedImage, upgradeInput.GardenerConfig.MachineImage)
+			assert.Equal(t, &tc.expectedVersion, upgradeInput.GardenerConfig.MachineImageVersion)
+		})
+	}
+}
+
+func TestShouldForwardKubernetesConfig(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AWSPlanID, "")
+	pp.Parameters.KubernetesConfig = &internal.KubernetesConfig{
+		Kubelet: &internal.KubeletConfig{
+			FeatureGates: map[string]bool{"CPUManager": true, "BoundServiceAccountTokenVolume": false},
+			EvictionHard: map[string]string{internal.EvictionMemoryAvailable: "200Mi", internal.EvictionNodeFSAvailable: "10%"},
+			MaxPods:      ptr.Integer(110),
+		},
+		APIServer: &internal.APIServerConfig{FeatureGates: map[string]bool{"TTLAfterFinished": true}},
+	}
+	expected := &gqlschema.KubernetesConfigInput{
+		Kubelet: &gqlschema.KubeletConfigInput{
+			FeatureGates: []*gqlschema.FeatureGateInput{
+				{Name: "BoundServiceAccountTokenVolume", Enabled: false},
+				{Name: "CPUManager", Enabled: true},
+			},
+			EvictionHard: &gqlschema.EvictionThresholdsInput{MemoryAvailable: ptr.String("200Mi"), NodeFSAvailable: ptr.String("10%")},
+			MaxPods:      ptr.Integer(110),
+		},
+		APIServer: &gqlschema.APIServerConfigInput{
+			FeatureGates: []*gqlschema.FeatureGateInput{{Name: "TTLAfterFinished", Enabled: true}},
+		},
+	}
+
+	provisionCreator, err := builder.CreateProvisionInput(pp, internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults})
+	require.NoError(t, err)
+	provisionCreator.SetProvisioningParameters(pp)
+	upgradeCreator, err := builder.CreateUpgradeShootInput(pp)
+	require.NoError(t, err)
+	upgradeCreator.SetProvisioningParameters(pp)
+
+	// when
+	provisionInput, err := provisionCreator.CreateProvisionRuntimeInput()
+	require.NoError(t, err)
+	upgradeInput, err := upgradeCreator.CreateUpgradeShootInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, expected, provisionInput.ClusterConfig.GardenerConfig.KubernetesConfig)
+	assert.Equal(t, expected, upgradeInput.GardenerConfig.KubernetesConfig)
+}
+
+func TestShouldNotSetKubernetesConfigByDefault(t *testing.T) {
+	// given
+	optComponentsSvc := dummyOptionalComponentServiceMock(fixKymaComponentList())
+	componentsProvider := &automock.ComponentListProvider{}
+	componentsProvider.On("AllComponents", mock.AnythingOfType("string")).Return(fixKymaComponentList(), nil)
+
+	builder, err := NewInputBuilderFactory(optComponentsSvc, runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+	assert.NoError(t, err)
+
+	pp := fixProvisioningParameters(broker.AWSPlanID, "")
+	upgradeCreator, err := builder.CreateUpgradeShootInput(pp)
+	require.NoError(t, err)
+	upgradeCreator.SetProvisioningParameters(pp)
+
+	// when
+	upgradeInput, err := upgradeCreator.CreateUpgradeShootInput()
+	require.NoError(t, err)
+
+	// then
+	assert.Nil(t, upgradeInput.GardenerConfig.KubernetesConfig)
+}
+
 func assertOverrides(t *testing.T, componentName string, components internal.ComponentConfigurationInputList, overrides []*gqlschema.ConfigEntryInput) {
 	overriddenComponent, found := find(components, componentName)
 	require.True(t, found)
@@ -0,0 +1,70 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+
+	"github.com/pkg/errors"
+)
+
+// RegionNotAvailableError is returned when the plan is not available in the requested region
+type RegionNotAvailableError struct {
+	Plan   string
+	Region string
+}
+
+func (e RegionNotAvailableError) Error() string {
+	return fmt.Sprintf("region %s is not available for the plan %s", e.Region, e.Plan)
+}
+
+// IsRegionNotAvailableError checks if the error is caused by the region which is not available for the plan
+func IsRegionNotAvailableError(err error) bool {
+	_, ok := errors.Cause(err).(RegionNotAvailableError)
+	return ok
+}
+
+// parsePlanRegions parses the entries in the format <plan name>:<region>[+<region>] into the regions per plan ID
+func parsePlanRegions(entries []string) (map[string]map[string]struct{}, error) {
+	planRegions := map[string]map[string]struct{}{}
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.Errorf("invalid plan regions entry %q, the expected format is <plan>:<region>[+<region>]", entry)
+		}
+		planID, found := broker.PlanIDsMapping[parts[0]]
+		if !found {
+			return nil, errors.Errorf("unknown plan %q in the plan regions entry %q", parts[0], entry)
+		}
+		regions := map[string]struct{}{}
+		for _, region := range strings.Split(parts[1], "+") {
+			if region == "" {
+				return nil, errors.Errorf("empty region in the plan regions entry %q", entry)
+			}
+			regions[region] = struct{}{}
+		}
+		planRegions[planID] = regions
+	}
+	return planRegions, nil
+}
+
+// validateRegion checks the region the cluster is created in against the regions available for the plan,
+// the plans without the configured regions are available in every region
+func (f *InputBuilderFactory) validateRegion(provider HyperscalerInputProvider, pp internal.ProvisioningParameters) error {
+	regions, found := f.planRegions[pp.PlanID]
+	if !found {
+		return nil
+	}
+
+	clusterConfig := provider.Defaults()
+	updateString(&clusterConfig.GardenerConfig.Region, pp.Parameters.Region)
+	provider.ApplyParameters(clusterConfig, pp)
+	region := clusterConfig.GardenerConfig.Region
+
+	if _, available := regions[region]; !available {
+		return RegionNotAvailableError{Plan: broker.PlanNamesMapping[pp.PlanID], Region: region}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package process
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+type InstanceLockConfig struct {
+	// Enabled makes the operations changing the same instance (provisioning, deprovisioning, upgrades and updates)
+	// processed one at a time, the operations of different instances are still processed at the same time
+	Enabled bool `envconfig:"default=false"`
+	// RejectConflicts makes the broker reject the deprovisioning and the update of the instance locked by another
+	// operation with 409 Conflict, otherwise the new operation is created and waits until the lock is released
+	RejectConflicts bool `envconfig:"default=false"`
+	// Timeout defines how long the operation holds the lock at most, the lock held longer is taken by the next operation
+	Timeout time.Duration `envconfig:"default=24h"`
+	// RetryInterval defines how long the operation of the locked instance waits before it is processed again
+	RetryInterval time.Duration `envconfig:"default=1m"`
+}
+
+// InstanceLocks keeps the exclusive lock of the instance for the operation which changes it. The operation takes
+// the lock when its processing starts and keeps it until the processing ends or the lock times out.
+// The locks are kept in memory, so they are taken again by the operations reprocessed on start.
+type InstanceLocks struct {
+	timeout       time.Duration
+	retryInterval time.Duration
+
+	mu    sync.Mutex
+	locks map[string]instanceLock
+
+	now func() time.Time
+}
+
+type instanceLock struct {
+	operationID string
+	acquiredAt  time.Time
+}
+
+func NewInstanceLocks(cfg InstanceLockConfig) *InstanceLocks {
+	return &InstanceLocks{
+		timeout:       cfg.Timeout,
+		retryInterval: cfg.RetryInterval,
+		locks:         make(map[string]instanceLock),
+		now:           time.Now,
+	}
+}
+
+// Acquire takes the lock of the instance for the operation, false is returned when another operation holds the lock.
+// The operation which already holds the lock keeps it.
+func (l *InstanceLocks) Acquire(instanceID, operationID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock, found := l.locks[instanceID]
+	if found && lock.operationID == operationID {
+		return true
+	}
+	if found && !l.expired(lock) {
+		return false
+	}
+	l.locks[instanceID] = instanceLock{operationID: operationID, acquiredAt: l.now()}
+	return true
+}
+
+// Release frees the lock of the instance if it is held by the operation
+func (l *InstanceLocks) Release(instanceID, operationID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lock, found := l.locks[instanceID]; found && lock.operationID == operationID {
+		delete(l.locks, instanceID)
+	}
+}
+
+// Holder returns the ID of the operation holding the lock of the instance
+func (l *InstanceLocks) Holder(instanceID string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock, found := l.locks[instanceID]
+	if !found || l.expired(lock) {
+		return "", false
+	}
+	return lock.operationID, true
+}
+
+// RetryInterval returns the time after which the operation which did not get the lock is processed again
+func (l *InstanceLocks) RetryInterval() time.Duration {
+	return l.retryInterval
+}
+
+func (l *InstanceLocks) expired(lock instanceLock) bool {
+	return l.timeout > 0 && l.now().Sub(lock.acquiredAt) > l.timeout
+}
+
+// InstanceLockedExecutor processes the operation only when it holds the lock of its instance, the operation which does
+// not get the lock is repeated after the retry interval without running any step
+type InstanceLockedExecutor struct {
+	executor   Executor
+	operations storage.Operations
+	locks      *InstanceLocks
+	log        logrus.FieldLogger
+}
+
+func NewInstanceLockedExecutor(executor Executor, operations storage.Operations, locks *InstanceLocks, log logrus.FieldLogger) *InstanceLockedExecutor {
+	return &InstanceLockedExecutor{
+		executor:   executor,
+		operations: operations,
+		locks:      locks,
+		log:        log,
+	}
+}
+
+func (e *InstanceLockedExecutor) Execute(operationID string) (time.Duration, error) {
+	operation, err := e.operations.GetOperationByID(operationID)
+	if err != nil {
+		e.log.Errorf("Cannot fetch operation %s from storage: %s", operationID, err)
+		return 3 * time.Second, nil
+	}
+	if operation.IsFinished() {
+		e.locks.Release(operation.InstanceID, operation.ID)
+		return e.executor.Execute(operationID)
+	}
+
+	if !e.locks.Acquire(operation.InstanceID, operation.ID) {
+		holder, _ := e.locks.Holder(operation.InstanceID)
+		OperationLogger(e.log, *operation).Infof("Instance is locked by the operation %s, operation will be repeated in %s", holder, e.locks.RetryInterval())
+		return e.locks.RetryInterval(), nil
+	}
+	when, err := e.executor.Execute(operationID)
+	if when == 0 {
+		e.locks.Release(operation.InstanceID, operation.ID)
+	}
+	return when, err
+}
+
+// InstanceLockedOperationExecutor is the InstanceLockedExecutor of the operations run by the orchestrations,
+// the rescheduling of the operation is passed to the wrapped executor
+type InstanceLockedOperationExecutor struct {
+	*InstanceLockedExecutor
+	executor orchestration.OperationExecutor
+}
+
+func NewInstanceLockedOperationExecutor(executor orchestration.OperationExecutor, operations storage.Operations, locks *InstanceLocks, log logrus.FieldLogger) *InstanceLockedOperationExecutor {
+	return &InstanceLockedOperationExecutor{
+		InstanceLockedExecutor: NewInstanceLockedExecutor(executor, operations, locks, log),
+		executor:               executor,
+	}
+}
+
+func (e *InstanceLockedOperationExecutor) Reschedule(operationID string, maintenanceWindowBegin, maintenanceWindowEnd time.Time) error {
+	return e.executor.Reschedule(operationID, maintenanceWindowBegin, maintenanceWindowEnd)
+}
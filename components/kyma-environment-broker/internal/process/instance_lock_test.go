@@ -0,0 +1,104 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceLocks(t *testing.T) {
+	t.Run("should lock the instance for one operation at a time", func(t *testing.T) {
+		// given
+		locks := NewInstanceLocks(InstanceLockConfig{Timeout: time.Hour, RetryInterval: time.Minute})
+
+		// when/then
+		assert.True(t, locks.Acquire("inst-1", "upgrade"))
+		assert.True(t, locks.Acquire("inst-1", "upgrade"))
+		assert.False(t, locks.Acquire("inst-1", "deprovisioning"))
+		assert.True(t, locks.Acquire("inst-2", "deprovisioning"))
+		holder, locked := locks.Holder("inst-1")
+		assert.True(t, locked)
+		assert.Equal(t, "upgrade", holder)
+
+		// the release by the operation which does not hold the lock is ignored
+		locks.Release("inst-1", "deprovisioning")
+		assert.False(t, locks.Acquire("inst-1", "deprovisioning"))
+
+		locks.Release("inst-1", "upgrade")
+		assert.True(t, locks.Acquire("inst-1", "deprovisioning"))
+	})
+
+	t.Run("should release the lock after the timeout", func(t *testing.T) {
+		// given
+		locks := NewInstanceLocks(InstanceLockConfig{Timeout: time.Hour, RetryInterval: time.Minute})
+		now := time.Now()
+		locks.now = func() time.Time { return now }
+		require.True(t, locks.Acquire("inst-1", "upgrade"))
+
+		// when
+		now = now.Add(2 * time.Hour)
+
+		// then
+		_, locked := locks.Holder("inst-1")
+		assert.False(t, locked)
+		assert.True(t, locks.Acquire("inst-1", "deprovisioning"))
+	})
+}
+
+func TestInstanceLockedExecutor(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	for id, instanceID := range map[string]string{"upgrade": "inst-1", "deprovisioning": "inst-1", "other": "inst-2"} {
+		operation := fixture.FixUpgradeKymaOperation(id, instanceID)
+		operation.State = domain.InProgress
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+	}
+	executor := &testExecutor{repeat: map[string]time.Duration{"upgrade": time.Second, "other": time.Second}}
+	locks := NewInstanceLocks(InstanceLockConfig{Timeout: time.Hour, RetryInterval: time.Minute})
+	lockedExecutor := NewInstanceLockedExecutor(executor, memoryStorage.Operations(), locks, logrus.New())
+
+	// when the upgrade is in progress
+	when, err := lockedExecutor.Execute("upgrade")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, when)
+
+	// then the second operation of the instance waits
+	when, err = lockedExecutor.Execute("deprovisioning")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, when)
+	assert.Equal(t, []string{"upgrade"}, executor.executed)
+
+	// and the operation of another instance is processed
+	when, err = lockedExecutor.Execute("other")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, when)
+	assert.Equal(t, []string{"upgrade", "other"}, executor.executed)
+
+	// when the upgrade finishes
+	executor.repeat["upgrade"] = 0
+	_, err = lockedExecutor.Execute("upgrade")
+	require.NoError(t, err)
+
+	// then the second operation of the instance is processed
+	when, err = lockedExecutor.Execute("deprovisioning")
+	require.NoError(t, err)
+	assert.Zero(t, when)
+	assert.Equal(t, []string{"upgrade", "other", "upgrade", "deprovisioning"}, executor.executed)
+}
+
+type testExecutor struct {
+	repeat   map[string]time.Duration
+	executed []string
+}
+
+func (e *testExecutor) Execute(operationID string) (time.Duration, error) {
+	e.executed = append(e.executed, operationID)
+	return e.repeat[operationID], nil
+}
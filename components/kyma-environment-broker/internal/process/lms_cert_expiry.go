@@ -0,0 +1,96 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// LMSCertificateExpiryReconciler flags the instances with LMS certificates which expire within the window
+// by publishing the LMSCertificateExpiring event. Instances with expired certificates are flagged as well.
+type LMSCertificateExpiryReconciler struct {
+	instances  storage.Instances
+	operations storage.Operations
+	publisher  event.Publisher
+	window     time.Duration
+	log        logrus.FieldLogger
+
+	now func() time.Time
+}
+
+func NewLMSCertificateExpiryReconciler(instances storage.Instances, operations storage.Operations, publisher event.Publisher, window time.Duration, log logrus.FieldLogger) *LMSCertificateExpiryReconciler {
+	return &LMSCertificateExpiryReconciler{
+		instances:  instances,
+		operations: operations,
+		publisher:  publisher,
+		window:     window,
+		log:        log.WithField("service", "LMSCertificateExpiryReconciler"),
+		now:        time.Now,
+	}
+}
+
+// Run reconciles the instances every interval until the context is done
+func (r *LMSCertificateExpiryReconciler) Run(ctx context.Context, interval time.Duration) {
+	go wait.Until(func() {
+		if err := r.Reconcile(ctx); err != nil {
+			r.log.Errorf("while reconciling LMS certificates expiry: %s", err)
+		}
+	}, interval, ctx.Done())
+}
+
+// Reconcile checks the LMS certificates of all instances once
+func (r *LMSCertificateExpiryReconciler) Reconcile(ctx context.Context) error {
+	instances, _, _, err := r.instances.List(dbmodel.InstanceFilter{})
+	if err != nil {
+		return errors.Wrap(err, "while listing instances")
+	}
+
+	deadline := r.now().Add(r.window)
+	flagged := 0
+	for _, instance := range instances {
+		lms, found, err := r.lastIssuedCertificate(instance.InstanceID)
+		if err != nil {
+			r.log.Errorf("while getting provisioning operations for instance %s: %s", instance.InstanceID, err)
+			continue
+		}
+		if !found || lms.CertExpiresAt.After(deadline) {
+			continue
+		}
+
+		r.log.WithField("instanceID", instance.InstanceID).Warnf("LMS certificate expires at %s", lms.CertExpiresAt.Format(time.RFC3339))
+		r.publisher.Publish(ctx, LMSCertificateExpiring{
+			InstanceID: instance.InstanceID,
+			RuntimeID:  instance.RuntimeID,
+			TenantID:   lms.TenantID,
+			ExpiresAt:  lms.CertExpiresAt,
+		})
+		flagged++
+	}
+	r.log.Infof("Checked LMS certificates of %d instances, %d expire within %s", len(instances), flagged, r.window)
+
+	return nil
+}
+
+// lastIssuedCertificate returns the LMS data of the provisioning operation which issued the latest certificate
+func (r *LMSCertificateExpiryReconciler) lastIssuedCertificate(instanceID string) (internal.LMS, bool, error) {
+	operations, err := r.operations.ListProvisioningOperationsByInstanceID(instanceID)
+	if err != nil {
+		return internal.LMS{}, false, err
+	}
+
+	var last internal.LMS
+	for _, operation := range operations {
+		if operation.Lms.CertExpiresAt.After(last.CertExpiresAt) {
+			last = operation.Lms
+		}
+	}
+	return last, !last.CertExpiresAt.IsZero(), nil
+}
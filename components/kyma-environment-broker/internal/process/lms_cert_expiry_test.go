@@ -0,0 +1,84 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLMSCertificateExpiryReconciler_Reconcile(t *testing.T) {
+	// given
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	memoryStorage := storage.NewMemoryStorage()
+
+	for _, op := range []internal.ProvisioningOperation{
+		fixLMSProvisioningOperation("expiring-op", "expiring", "tenant-expiring", now.Add(24*time.Hour)),
+		fixLMSProvisioningOperation("expired-op", "expired", "tenant-expired", now.Add(-time.Hour)),
+		fixLMSProvisioningOperation("valid-op", "valid", "tenant-valid", now.Add(90*24*time.Hour)),
+		fixLMSProvisioningOperation("without-cert-op", "without-cert", "", time.Time{}),
+		// the certificate was renewed by the unsuspension
+		fixLMSProvisioningOperation("renewed-old-op", "renewed", "tenant-renewed", now.Add(time.Hour)),
+		fixLMSProvisioningOperation("renewed-new-op", "renewed", "tenant-renewed", now.Add(90*24*time.Hour)),
+	} {
+		require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(op))
+	}
+	for _, id := range []string{"expiring", "expired", "valid", "without-cert", "renewed"} {
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: id, RuntimeID: "runtime-" + id}))
+	}
+	publisher := &fakePublisher{}
+	reconciler := NewLMSCertificateExpiryReconciler(memoryStorage.Instances(), memoryStorage.Operations(), publisher, 7*24*time.Hour, logrus.New())
+	reconciler.now = func() time.Time { return now }
+
+	// when
+	err := reconciler.Reconcile(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []interface{}{
+		LMSCertificateExpiring{
+			InstanceID: "expiring",
+			RuntimeID:  "runtime-expiring",
+			TenantID:   "tenant-expiring",
+			ExpiresAt:  now.Add(24 * time.Hour),
+		},
+		LMSCertificateExpiring{
+			InstanceID: "expired",
+			RuntimeID:  "runtime-expired",
+			TenantID:   "tenant-expired",
+			ExpiresAt:  now.Add(-time.Hour),
+		},
+	}, publisher.events)
+}
+
+func fixLMSProvisioningOperation(id, instanceID, tenantID string, certExpiresAt time.Time) internal.ProvisioningOperation {
+	return internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         id,
+			InstanceID: instanceID,
+			Type:       internal.OperationTypeProvision,
+			State:      domain.Succeeded,
+			CreatedAt:  certExpiresAt,
+			InstanceDetails: internal.InstanceDetails{
+				Lms: internal.LMS{
+					TenantID:      tenantID,
+					CertExpiresAt: certExpiresAt,
+				},
+			},
+		},
+	}
+}
+
+type fakePublisher struct {
+	events []interface{}
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, ev interface{}) {
+	p.events = append(p.events, ev)
+}
@@ -0,0 +1,20 @@
+package process
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OperationLogger returns the logger which adds the operation ID, the instance ID and, if present,
+// the correlation ID of the request which triggered the operation to every entry logged by the steps
+func OperationLogger(log logrus.FieldLogger, operation internal.Operation) logrus.FieldLogger {
+	fields := logrus.Fields{
+		"operationID": operation.ID,
+		"instanceID":  operation.InstanceID,
+	}
+	if operation.CorrelationID != "" {
+		fields["correlationID"] = operation.CorrelationID
+	}
+	return log.WithFields(fields)
+}
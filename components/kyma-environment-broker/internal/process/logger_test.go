@@ -0,0 +1,30 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	logrusTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLogger(t *testing.T) {
+	// given
+	logger, hook := logrusTest.NewNullLogger()
+	operation := internal.Operation{ID: "op-id", InstanceID: "instance-id", CorrelationID: "correlation-id"}
+
+	// when
+	OperationLogger(logger, operation).Info("message")
+	operation.CorrelationID = ""
+	OperationLogger(logger, operation).Info("message")
+
+	// then
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "op-id", entries[0].Data["operationID"])
+	assert.Equal(t, "instance-id", entries[0].Data["instanceID"])
+	assert.Equal(t, "correlation-id", entries[0].Data["correlationID"])
+	assert.NotContains(t, entries[1].Data, "correlationID")
+}
@@ -0,0 +1,233 @@
+package process
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPriority is the priority of the operations added without the priority, the operations with
+// a higher priority are dispatched first
+const DefaultPriority = 0
+
+// QueuedItem describes the operation waiting in the queue
+type QueuedItem struct {
+	OperationID  string `json:"operationID"`
+	SubAccountID string `json:"subAccountID,omitempty"`
+	Priority     int    `json:"priority"`
+}
+
+// priorityLevel holds the queued items of a single priority grouped by the subaccount,
+// all items are in the same group if the queue is not fair
+type priorityLevel struct {
+	pending map[string][]string
+	order   []string
+}
+
+// priorityQueue dispatches the queued items with the highest priority first. Within a priority the items are
+// dispatched in FIFO order or, if the queue is fair, round-robin across the subaccounts with the items of a single
+// subaccount in FIFO order. Like the workqueue, it does not queue the same item twice and an item added
+// while being processed is queued again when its processing is done.
+type priorityQueue struct {
+	cond *sync.Cond
+	fair bool
+
+	subaccounts map[string]string
+	priorities  map[string]int
+	levels      map[int]*priorityLevel
+	dirty       map[string]struct{}
+	processing  map[string]struct{}
+
+	shuttingDown bool
+}
+
+func newPriorityQueue(fair bool) *priorityQueue {
+	return &priorityQueue{
+		cond:        sync.NewCond(&sync.Mutex{}),
+		fair:        fair,
+		subaccounts: map[string]string{},
+		priorities:  map[string]int{},
+		levels:      map[int]*priorityLevel{},
+		dirty:       map[string]struct{}{},
+		processing:  map[string]struct{}{},
+	}
+}
+
+// AddWithPriority adds the item of the given subaccount with the given priority, the priority and the subaccount
+// are kept until the item is forgotten, so the item added again after a retry is dispatched the same way
+func (q *priorityQueue) AddWithPriority(id, subAccountID string, priority int) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	q.subaccounts[id] = subAccountID
+	q.priorities[id] = priority
+	q.add(id)
+}
+
+func (q *priorityQueue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	q.add(item.(string))
+}
+
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(item) })
+}
+
+// AddWithPriorityAfter adds the item of the given subaccount with the given priority after the duration
+func (q *priorityQueue) AddWithPriorityAfter(id, subAccountID string, priority int, duration time.Duration) {
+	if duration <= 0 {
+		q.AddWithPriority(id, subAccountID, priority)
+		return
+	}
+	time.AfterFunc(duration, func() { q.AddWithPriority(id, subAccountID, priority) })
+}
+
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.levels) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.levels) == 0 {
+		return nil, true
+	}
+
+	priority := q.highestPriority()
+	level := q.levels[priority]
+	group := level.order[0]
+	level.order = level.order[1:]
+	id := level.pending[group][0]
+	level.pending[group] = level.pending[group][1:]
+	if len(level.pending[group]) > 0 {
+		// the subaccount goes to the end of the line, so the other subaccounts are served first
+		level.order = append(level.order, group)
+	} else {
+		delete(level.pending, group)
+	}
+	if len(level.order) == 0 {
+		delete(q.levels, priority)
+	}
+	q.processing[id] = struct{}{}
+	delete(q.dirty, id)
+
+	return id, false
+}
+
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	id := item.(string)
+	delete(q.processing, id)
+	if _, found := q.dirty[id]; found {
+		q.enqueue(id)
+	}
+}
+
+func (q *priorityQueue) Forget(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	id := item.(string)
+	if _, found := q.dirty[id]; !found {
+		delete(q.subaccounts, id)
+		delete(q.priorities, id)
+	}
+}
+
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// Snapshot returns the queued items in the order in which they are dispatched
+func (q *priorityQueue) Snapshot() []QueuedItem {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	items := []QueuedItem{}
+	for _, priority := range q.sortedPriorities() {
+		level := q.levels[priority]
+		order := append([]string(nil), level.order...)
+		pending := map[string][]string{}
+		for group, ids := range level.pending {
+			pending[group] = ids
+		}
+		for len(order) > 0 {
+			group := order[0]
+			order = order[1:]
+			id := pending[group][0]
+			pending[group] = pending[group][1:]
+			if len(pending[group]) > 0 {
+				order = append(order, group)
+			}
+			items = append(items, QueuedItem{
+				OperationID:  id,
+				SubAccountID: q.subaccounts[id],
+				Priority:     priority,
+			})
+		}
+	}
+
+	return items
+}
+
+func (q *priorityQueue) add(id string) {
+	if _, found := q.dirty[id]; found {
+		return
+	}
+	q.dirty[id] = struct{}{}
+	if _, found := q.processing[id]; found {
+		return
+	}
+	q.enqueue(id)
+}
+
+func (q *priorityQueue) enqueue(id string) {
+	priority := q.priorities[id]
+	level, found := q.levels[priority]
+	if !found {
+		level = &priorityLevel{pending: map[string][]string{}}
+		q.levels[priority] = level
+	}
+	group := ""
+	if q.fair {
+		group = q.subaccounts[id]
+	}
+	if len(level.pending[group]) == 0 {
+		level.order = append(level.order, group)
+	}
+	level.pending[group] = append(level.pending[group], id)
+	q.cond.Signal()
+}
+
+func (q *priorityQueue) highestPriority() int {
+	first := true
+	highest := DefaultPriority
+	for priority := range q.levels {
+		if first || priority > highest {
+			highest = priority
+			first = false
+		}
+	}
+	return highest
+}
+
+func (q *priorityQueue) sortedPriorities() []int {
+	priorities := make([]int, 0, len(q.levels))
+	for priority := range q.levels {
+		priorities = append(priorities, priority)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+	return priorities
+}
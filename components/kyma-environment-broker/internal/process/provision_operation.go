@@ -45,6 +45,23 @@ func (om *ProvisionOperationManager) OperationFailed(operation internal.Provisio
 	return updatedOperation, 0, errors.New(description)
 }
 
+// OperationFailedWithError marks the operation as failed with the message of the error and stores the failure reason
+// of the FailureError on the operation, it only repeats the operation if there is a storage error
+func (om *ProvisionOperationManager) OperationFailedWithError(operation internal.ProvisioningOperation, err error, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	reason := internal.FailureReasonOf(err)
+	updatedOperation, repeat := om.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.State = domain.Failed
+		operation.Description = fmt.Sprintf("%s : %s", operation.Description, err.Error())
+		operation.FailureReason = reason
+	}, log)
+	// repeat in case of storage error
+	if repeat != 0 {
+		return updatedOperation, repeat, nil
+	}
+
+	return updatedOperation, 0, err
+}
+
 // UpdateOperation updates a given operation and handles conflict situation
 func (om *ProvisionOperationManager) UpdateOperation(operation internal.ProvisioningOperation, update func(operation *internal.ProvisioningOperation), log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration) {
 	update(&operation)
@@ -71,6 +88,18 @@ func (om *ProvisionOperationManager) UpdateOperation(operation internal.Provisio
 	return *updatedOperation, 0
 }
 
+// ReportProgress appends the timestamped progress message to the operation, e.g. "shoot created", the message which repeats
+// the latest one is not stored again. The progress is shown in the last operation description while the operation is in progress.
+func (om *ProvisionOperationManager) ReportProgress(operation internal.ProvisioningOperation, message string, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration) {
+	if latest := operation.LatestProgress(); latest != nil && latest.Message == message {
+		return operation, 0
+	}
+	now := time.Now()
+	return om.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.AddProgress(message, now)
+	}, log)
+}
+
 // Deprecated: SimpleUpdateOperation updates a given operation without handling conflicts. Should be used when operation's data mutations are not clear
 func (om *ProvisionOperationManager) SimpleUpdateOperation(operation internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration) {
 	updatedOperation, err := om.storage.UpdateProvisioningOperation(operation)
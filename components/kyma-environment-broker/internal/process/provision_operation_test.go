@@ -76,3 +76,29 @@ func Test_Provision_RetryOperation(t *testing.T) {
 	assert.True(t, when > 0)
 	assert.Nil(t, err)
 }
+
+func Test_Provision_ReportProgress(t *testing.T) {
+	// given
+	memory := storage.NewMemoryStorage()
+	operations := memory.Operations()
+	opManager := NewProvisionOperationManager(operations)
+	op := internal.ProvisioningOperation{}
+	op.ID = "op-id"
+	err := operations.InsertProvisioningOperation(op)
+	require.NoError(t, err)
+
+	// when
+	for i := 0; i < internal.MaxProgressMessages+5; i++ {
+		var when time.Duration
+		op, when = opManager.ReportProgress(op, fmt.Sprintf("step %d", i), fixLogger())
+		require.Zero(t, when)
+	}
+	op, _ = opManager.ReportProgress(op, fmt.Sprintf("step %d", internal.MaxProgressMessages+4), fixLogger())
+
+	// then
+	stored, err := operations.GetProvisioningOperationByID(op.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Progress, internal.MaxProgressMessages)
+	assert.Equal(t, "step 5", stored.Progress[0].Message)
+	assert.Equal(t, fmt.Sprintf("step %d", internal.MaxProgressMessages+4), stored.LatestProgress().Message)
+}
@@ -0,0 +1,57 @@
+package provisioning
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckCredentialsAvailabilityStep fails the provisioning before any external resource is registered
+// when the account pool has no hyperscaler credentials for the global account. The credentials are claimed
+// later by the ResolveCredentialsStep.
+type CheckCredentialsAvailabilityStep struct {
+	operationManager *process.ProvisionOperationManager
+	accountProvider  hyperscaler.AccountProvider
+}
+
+func NewCheckCredentialsAvailabilityStep(os storage.Operations, accountProvider hyperscaler.AccountProvider) *CheckCredentialsAvailabilityStep {
+	return &CheckCredentialsAvailabilityStep{
+		operationManager: process.NewProvisionOperationManager(os),
+		accountProvider:  accountProvider,
+	}
+}
+
+func (s *CheckCredentialsAvailabilityStep) Name() string {
+	return "Check_Credentials_Availability"
+}
+
+func (s *CheckCredentialsAvailabilityStep) Run(operation internal.ProvisioningOperation, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	// the target secret is already resolved, or the trial runtime uses the shared credentials which are never exhausted
+	if operation.ProvisioningParameters.Parameters.TargetSecret != nil || broker.IsTrialPlan(operation.ProvisioningParameters.PlanID) {
+		return operation, 0, nil
+	}
+
+	hypType, err := getHyperscalerType(operation.ProvisioningParameters)
+	if err != nil {
+		return s.operationManager.OperationFailed(operation, err.Error(), log)
+	}
+
+	globalAccountID := operation.ProvisioningParameters.ErsContext.GlobalAccountID
+	available, err := s.accountProvider.GardenerCredentialsAvailable(hypType, globalAccountID)
+	if err != nil {
+		errMsg := fmt.Sprintf("unable to check credentials availability for global account ID %s on Hyperscaler %s: %s", globalAccountID, hypType, err)
+		return s.operationManager.RetryOperation(operation, errMsg, 10*time.Second, 10*time.Minute, log)
+	}
+	if !available {
+		log.Errorf("Aborting provisioning, no credentials are available for global account ID %s on Hyperscaler %s", globalAccountID, hypType)
+		return s.operationManager.OperationFailed(operation, fmt.Sprintf("no %s account is available in the account pool", hypType), log)
+	}
+
+	return operation, 0, nil
+}
@@ -0,0 +1,93 @@
+package provisioning
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	hyperscalerMocks "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCredentialsAvailabilityStep_Run(t *testing.T) {
+	for name, tc := range map[string]struct {
+		available     bool
+		expectedError bool
+		expectedState domain.LastOperationState
+	}{
+		"credentials available":  {available: true, expectedError: false, expectedState: ""},
+		"account pool exhausted": {available: false, expectedError: true, expectedState: domain.Failed},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			operation := fixOperationRuntimeStatus(broker.GCPPlanID)
+			err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+			require.NoError(t, err)
+
+			accountProvider := &hyperscalerMocks.AccountProvider{}
+			accountProvider.On("GardenerCredentialsAvailable", hyperscaler.GCP, statusGlobalAccountID).Return(tc.available, nil)
+
+			step := NewCheckCredentialsAvailabilityStep(memoryStorage.Operations(), accountProvider)
+
+			// when
+			operation, repeat, err := step.Run(operation, logrus.New())
+
+			// then
+			assert.Equal(t, tc.expectedError, err != nil)
+			assert.Equal(t, time.Duration(0), repeat)
+			assert.Equal(t, tc.expectedState, operation.State)
+			accountProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckCredentialsAvailabilityStep_RunForTrial(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixOperationRuntimeStatus(broker.TrialPlanID)
+	accountProvider := &hyperscalerMocks.AccountProvider{}
+
+	step := NewCheckCredentialsAvailabilityStep(memoryStorage.Operations(), accountProvider)
+
+	// when
+	_, repeat, err := step.Run(operation, logrus.New())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), repeat)
+	accountProvider.AssertNotCalled(t, "GardenerCredentialsAvailable", mock.Anything, mock.Anything)
+}
+
+func TestManager_ExecuteAbortsWhenAccountPoolExhausted(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+	require.NoError(t, err)
+
+	accountProvider := &hyperscalerMocks.AccountProvider{}
+	accountProvider.On("GardenerCredentialsAvailable", hyperscaler.Azure, mock.Anything).Return(false, nil)
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.AddStep(1, NewCheckCredentialsAvailabilityStep(memoryStorage.Operations(), accountProvider))
+	manager.AddStep(2, &testStep{name: "registration", storage: memoryStorage.Operations()})
+
+	// when
+	_, err = manager.Execute(operationIDSuccess)
+
+	// then
+	require.Error(t, err)
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Failed, operation.State)
+	assert.Equal(t, "Check_Credentials_Availability", operation.CurrentStep)
+	assert.False(t, strings.Contains(operation.Description, "registration"))
+}
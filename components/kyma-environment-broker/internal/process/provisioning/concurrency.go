@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+
+	"github.com/pkg/errors"
+)
+
+type ConcurrencyConfig struct {
+	// PlanLimits is a comma-separated list of plan names with the maximum number of provisioning operations of the plan
+	// processed at the same time, e.g. "trial=10,azure=20". The plans which are not listed are not limited.
+	PlanLimits string `envconfig:"optional"`
+	// RetryInterval defines how long the operation of the plan which reached its limit waits before it is processed again
+	RetryInterval time.Duration `envconfig:"default=1m"`
+}
+
+// ConcurrencyLimiter caps the number of provisioning operations processed at the same time per plan. An operation takes
+// a slot of its plan when its processing starts and keeps it until the processing ends, i.e. until the manager does not
+// repeat the operation any more. The slots are kept in memory, so they are taken again by the operations reprocessed on start.
+type ConcurrencyLimiter struct {
+	limits        map[string]int
+	retryInterval time.Duration
+
+	mu       sync.Mutex
+	inFlight map[string]map[string]struct{}
+}
+
+func NewConcurrencyLimiter(cfg ConcurrencyConfig) (*ConcurrencyLimiter, error) {
+	limits, err := parsePlanLimits(cfg.PlanLimits)
+	if err != nil {
+		return nil, err
+	}
+	return &ConcurrencyLimiter{
+		limits:        limits,
+		retryInterval: cfg.RetryInterval,
+		inFlight:      make(map[string]map[string]struct{}),
+	}, nil
+}
+
+func parsePlanLimits(in string) (map[string]int, error) {
+	limits := make(map[string]int)
+	if strings.TrimSpace(in) == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(in, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("plan limit %q must have the form <plan name>=<limit>", entry)
+		}
+		planID, found := broker.PlanIDsMapping[strings.TrimSpace(parts[0])]
+		if !found {
+			return nil, errors.Errorf("unknown plan %q in the plan limits", parts[0])
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || limit <= 0 {
+			return nil, errors.Errorf("limit of plan %q must be a positive number", parts[0])
+		}
+		limits[planID] = limit
+	}
+	return limits, nil
+}
+
+// Acquire takes a slot of the plan for the operation, false is returned when all slots of the plan are taken.
+// The operation which already holds the slot keeps it.
+func (l *ConcurrencyLimiter) Acquire(planID, operationID string) bool {
+	limit, limited := l.limits[planID]
+	if !limited {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	operations := l.inFlight[planID]
+	if _, found := operations[operationID]; found {
+		return true
+	}
+	if len(operations) >= limit {
+		return false
+	}
+	if operations == nil {
+		operations = make(map[string]struct{})
+		l.inFlight[planID] = operations
+	}
+	operations[operationID] = struct{}{}
+	return true
+}
+
+// Release frees the slot of the plan taken by the operation
+func (l *ConcurrencyLimiter) Release(planID, operationID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight[planID], operationID)
+}
+
+// RetryInterval returns the time after which the operation which did not get a slot is processed again
+func (l *ConcurrencyLimiter) RetryInterval() time.Duration {
+	return l.retryInterval
+}
+
+// InFlight returns the number of the operations holding a slot for every limited plan, the keys are the plan IDs
+func (l *ConcurrencyLimiter) InFlight() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counts := make(map[string]int, len(l.limits))
+	for planID := range l.limits {
+		counts[planID] = len(l.inFlight[planID])
+	}
+	return counts
+}
@@ -30,6 +30,7 @@ type CreateRuntimeStep struct {
 	instanceStorage     storage.Instances
 	runtimeStateStorage storage.RuntimeStates
 	provisionerClient   provisioner.Client
+	requestMutators     []ProvisionRequestMutator
 }
 
 func NewCreateRuntimeStep(os storage.Operations, runtimeStorage storage.RuntimeStates, is storage.Instances, cli provisioner.Client) *CreateRuntimeStep {
@@ -41,6 +42,11 @@ func NewCreateRuntimeStep(os storage.Operations, runtimeStorage storage.RuntimeS
 	}
 }
 
+// AddRequestMutators registers the mutators applied to the Provisioner request before it is submitted
+func (s *CreateRuntimeStep) AddRequestMutators(mutators ...ProvisionRequestMutator) {
+	s.requestMutators = append(s.requestMutators, mutators...)
+}
+
 func (s *CreateRuntimeStep) Name() string {
 	return "Create_Runtime"
 }
@@ -55,6 +61,10 @@ func (s *CreateRuntimeStep) Run(operation internal.ProvisioningOperation, log lo
 		log.Errorf("Unable to create provisioning input: %s", err.Error())
 		return s.operationManager.OperationFailed(operation, "invalid operation data - cannot create provisioning input", log)
 	}
+	if err := mutateProvisionRequest(&requestInput, operation, s.requestMutators); err != nil {
+		log.Errorf("Unable to mutate provisioning input: %s", err.Error())
+		return s.operationManager.OperationFailed(operation, "invalid provisioning input after the mutation", log)
+	}
 
 	var provisionerResponse gqlschema.OperationStatus
 	if operation.ProvisionerOperationID == "" {
@@ -65,7 +75,11 @@ func (s *CreateRuntimeStep) Run(operation internal.ProvisioningOperation, log lo
 			requestInput.KymaConfig.Profile,
 			requestInput.ClusterConfig.GardenerConfig.Provider)
 
-		provisionerResponse, err := s.provisionerClient.ProvisionRuntime(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.ProvisioningParameters.ErsContext.SubAccountID, requestInput)
+		dumpedRequest, err := provisioner.DumpRequest(s.provisionerClient, requestInput)
+		if err != nil {
+			log.Warnf("unable to dump provisioner request: %s", err)
+		}
+		provisionerResponse, err := provisioner.ForTrace(provisioner.ForOperation(s.provisionerClient, operation.CorrelationID), operation.TraceParent).ProvisionRuntime(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.ProvisioningParameters.ErsContext.SubAccountID, requestInput)
 		switch {
 		case kebError.IsTemporaryError(err):
 			log.Errorf("call to provisioner failed (temporary error): %s", err)
@@ -78,6 +92,7 @@ func (s *CreateRuntimeStep) Run(operation internal.ProvisioningOperation, log lo
 		repeat := time.Duration(0)
 		operation, repeat = s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
 			operation.ProvisionerOperationID = *provisionerResponse.ID
+			operation.ProvisionerRequest = dumpedRequest
 			if provisionerResponse.RuntimeID != nil {
 				operation.RuntimeID = *provisionerResponse.RuntimeID
 			}
@@ -169,6 +169,80 @@ func TestCreateRuntimeStep_RunWithBadRequestError(t *testing.T) {
 
 }
 
+func TestCreateRuntimeStep_RunWithRequestMutators(t *testing.T) {
+	t.Run("should submit the mutated request", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixOperationCreateRuntime(t, broker.GCPPlanID, "europe-west4-a")
+		assert.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+		assert.NoError(t, memoryStorage.Instances().Insert(fixInstance()))
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("ProvisionRuntime", globalAccountID, subAccountID, mock.MatchedBy(
+			func(input gqlschema.ProvisionRuntimeInput) bool {
+				return reflect.DeepEqual(input.ClusterConfig.GardenerConfig.Annotations, []*gqlschema.AnnotationInput{
+					{Key: "experimental.kyma-project.io/flag", Value: "enabled"},
+				})
+			},
+		)).Return(gqlschema.OperationStatus{
+			ID: ptr.String(provisionerOperationID),
+		}, nil)
+		provisionerClient.On("RuntimeOperationStatus", globalAccountID, provisionerOperationID).Return(gqlschema.OperationStatus{
+			ID:        ptr.String(provisionerOperationID),
+			RuntimeID: ptr.String(runtimeID),
+		}, nil)
+
+		step := NewCreateRuntimeStep(memoryStorage.Operations(), memoryStorage.RuntimeStates(), memoryStorage.Instances(), provisionerClient)
+		step.AddRequestMutators(testRequestMutator(func(request *gqlschema.ProvisionRuntimeInput) {
+			request.ClusterConfig.GardenerConfig.Annotations = append(request.ClusterConfig.GardenerConfig.Annotations,
+				&gqlschema.AnnotationInput{Key: "experimental.kyma-project.io/flag", Value: "enabled"})
+		}))
+
+		// when
+		operation, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, 1*time.Second, repeat)
+		assert.Equal(t, provisionerOperationID, operation.ProvisionerOperationID)
+		provisionerClient.AssertExpectations(t)
+	})
+
+	t.Run("should fail the operation when the mutated request is invalid", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixOperationCreateRuntime(t, broker.GCPPlanID, "europe-west4-a")
+		assert.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+		assert.NoError(t, memoryStorage.Instances().Insert(fixInstance()))
+
+		provisionerClient := &provisionerAutomock.Client{}
+
+		step := NewCreateRuntimeStep(memoryStorage.Operations(), memoryStorage.RuntimeStates(), memoryStorage.Instances(), provisionerClient)
+		step.AddRequestMutators(testRequestMutator(func(request *gqlschema.ProvisionRuntimeInput) {
+			request.ClusterConfig.GardenerConfig.Region = ""
+		}))
+
+		// when
+		operation, _, err := step.Run(operation, logrus.New())
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, domain.Failed, operation.State)
+		provisionerClient.AssertNotCalled(t, "ProvisionRuntime", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+type testRequestMutator func(request *gqlschema.ProvisionRuntimeInput)
+
+func (m testRequestMutator) Name() string {
+	return "test"
+}
+
+func (m testRequestMutator) Mutate(request *gqlschema.ProvisionRuntimeInput, _ internal.ProvisioningOperation) error {
+	m(request)
+	return nil
+}
+
 func fixOperationCreateRuntime(t *testing.T, planID, region string) internal.ProvisioningOperation {
 	provisioningOperation := fixture.FixProvisioningOperation(operationID, instanceID)
 	provisioningOperation.State = domain.InProgress
@@ -23,13 +23,15 @@ type EDPClient interface {
 
 type EDPRegistrationStep struct {
 	operationManager *process.ProvisionOperationManager
+	markers          storage.SubsystemMarkers
 	client           EDPClient
 	config           edp.Config
 }
 
-func NewEDPRegistrationStep(os storage.Operations, client EDPClient, config edp.Config) *EDPRegistrationStep {
+func NewEDPRegistrationStep(os storage.Operations, markers storage.SubsystemMarkers, client EDPClient, config edp.Config) *EDPRegistrationStep {
 	return &EDPRegistrationStep{
 		operationManager: process.NewProvisionOperationManager(os),
+		markers:          markers,
 		client:           client,
 		config:           config,
 	}
@@ -67,6 +69,28 @@ func (s *EDPRegistrationStep) Run(operation internal.ProvisioningOperation, log
 		}
 	}
 
+	labels, err := edp.LabelsMetadata(s.config.PropagatedLabels, operation.ProvisioningParameters.Parameters.Annotations)
+	if err != nil {
+		log.Warnf("Skipping labels in DataTenant metadata: %s", err)
+	}
+	for _, label := range labels {
+		err = s.client.CreateMetadataTenant(subAccountID, s.config.Environment, label)
+		if err != nil {
+			return s.handleError(operation, err, log, fmt.Sprintf("cannot create DataTenant metadata %s", label.Key))
+		}
+	}
+
+	// the missing marker only makes the instance a candidate of the EDP backfill, which registers it again
+	err = s.markers.Insert(internal.SubsystemMarker{
+		InstanceID:  operation.InstanceID,
+		Subsystem:   internal.SubsystemEDP,
+		OperationID: operation.ID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		log.Errorf("while recording EDP marker of instance %s: %s", operation.InstanceID, err)
+	}
+
 	return operation, 0, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -22,8 +23,10 @@ func TestEDPRegistration_Run(t *testing.T) {
 	// given
 	memoryStorage := storage.NewMemoryStorage()
 	client := edp.NewFakeClient()
+	err := memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-id"})
+	require.NoError(t, err)
 
-	step := NewEDPRegistrationStep(memoryStorage.Operations(), client, edp.Config{
+	step := NewEDPRegistrationStep(memoryStorage.Operations(), memoryStorage.SubsystemMarkers(), client, edp.Config{
 		Environment: edpEnvironment,
 		Required:    true,
 	})
@@ -31,6 +34,8 @@ func TestEDPRegistration_Run(t *testing.T) {
 	// when
 	_, repeat, err := step.Run(internal.ProvisioningOperation{
 		Operation: internal.Operation{
+			ID:         "op-id",
+			InstanceID: "inst-id",
 			ProvisioningParameters: internal.ProvisioningParameters{
 				PlatformRegion: edpRegion,
 				ErsContext: internal.ERSContext{
@@ -68,6 +73,53 @@ func TestEDPRegistration_Run(t *testing.T) {
 		}, metadataTenant)
 	}
 
+	withoutEDP, err := memoryStorage.SubsystemMarkers().ListInstancesWithout(internal.SubsystemEDP)
+	require.NoError(t, err)
+	assert.Empty(t, withoutEDP)
+}
+
+func TestEDPRegistration_RunWithLabels(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	client := edp.NewFakeClient()
+
+	step := NewEDPRegistrationStep(memoryStorage.Operations(), memoryStorage.SubsystemMarkers(), client, edp.Config{
+		Environment:      edpEnvironment,
+		Required:         true,
+		PropagatedLabels: []string{"owner", "environment"},
+	})
+
+	// when
+	_, repeat, err := step.Run(internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ProvisioningParameters: internal.ProvisioningParameters{
+				PlatformRegion: edpRegion,
+				ErsContext: internal.ERSContext{
+					SubAccountID: edpName,
+				},
+				Parameters: internal.ProvisioningParametersDTO{
+					Annotations: map[string]string{
+						"owner":       "team-a",
+						"environment": "",
+						"cost-center": "1234",
+					},
+				},
+			},
+		},
+	}, logger.NewLogDummy())
+
+	// then
+	assert.Equal(t, 0*time.Second, repeat)
+	assert.NoError(t, err)
+
+	owner, ownerExists := client.GetMetadataItem(edpName, edpEnvironment, edp.MaasConsumerLabelKeyPrefix+"owner")
+	assert.True(t, ownerExists)
+	assert.Equal(t, "team-a", owner.Value)
+
+	_, environmentExists := client.GetMetadataItem(edpName, edpEnvironment, edp.MaasConsumerLabelKeyPrefix+"environment")
+	assert.False(t, environmentExists)
+	_, costCenterExists := client.GetMetadataItem(edpName, edpEnvironment, edp.MaasConsumerLabelKeyPrefix+"cost-center")
+	assert.False(t, costCenterExists)
 }
 
 func TestEDPRegistrationStep_selectEnvironmentKey(t *testing.T) {
@@ -98,7 +150,7 @@ func TestEDPRegistrationStep_selectEnvironmentKey(t *testing.T) {
 	} {
 		t.Run(name, func(t *testing.T) {
 			// given
-			step := NewEDPRegistrationStep(nil, nil, edp.Config{})
+			step := NewEDPRegistrationStep(nil, nil, nil, edp.Config{})
 
 			// when
 			envKey := step.selectEnvironmentKey(tc.region, logger.NewLogDummy())
@@ -0,0 +1,91 @@
+package provisioning
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/entitlements"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	entitlementsSubsystem       = "entitlements"
+	regionNotEntitledCode       = "REGION_NOT_ENTITLED"
+	entitlementsUnavailableCode = "ENTITLEMENTS_UNAVAILABLE"
+)
+
+type EntitlementsClient interface {
+	EntitledRegions(subAccountID string) ([]string, error)
+}
+
+// EntitledRegionStep fails the provisioning when the subaccount is not entitled to the requested region,
+// so the operation does not reach the Provisioner. Operations without the requested region are not validated.
+type EntitledRegionStep struct {
+	operationManager *process.ProvisionOperationManager
+	client           EntitlementsClient
+	cfg              entitlements.Config
+}
+
+func NewEntitledRegionStep(os storage.Operations, client EntitlementsClient, cfg entitlements.Config) *EntitledRegionStep {
+	return &EntitledRegionStep{
+		operationManager: process.NewProvisionOperationManager(os),
+		client:           client,
+		cfg:              cfg,
+	}
+}
+
+func (s *EntitledRegionStep) Name() string {
+	return "Validate_Entitled_Region"
+}
+
+func (s *EntitledRegionStep) Run(operation internal.ProvisioningOperation, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	if s.cfg.Disabled {
+		log.Infof("Skipping step %s because the entitlements integration is disabled", s.Name())
+		return operation, 0, nil
+	}
+	region := operation.ProvisioningParameters.Parameters.Region
+	if region == nil || *region == "" {
+		return operation, 0, nil
+	}
+	subAccountID := operation.ProvisioningParameters.ErsContext.SubAccountID
+
+	regions, err := s.client.EntitledRegions(subAccountID)
+	switch {
+	case kebError.IsTemporaryError(err):
+		log.Warnf("Unable to get the entitled regions of subaccount %s: %s", subAccountID, err)
+		if time.Since(operation.UpdatedAt) < 10*time.Minute {
+			return operation, 10 * time.Second, nil
+		}
+		return s.operationManager.OperationFailedWithError(operation, internal.NewFailureError(internal.FailureReason{
+			Code:        entitlementsUnavailableCode,
+			Message:     fmt.Sprintf("unable to get the entitled regions of subaccount %s", subAccountID),
+			Remediation: "retry the provisioning when the entitlements service is available",
+			Retryable:   true,
+			Subsystem:   entitlementsSubsystem,
+		}), log)
+	case err != nil:
+		return s.operationManager.OperationFailed(operation, fmt.Sprintf("while getting the entitled regions of subaccount %s: %s", subAccountID, err), log)
+	case regions == nil:
+		log.Infof("Regions of subaccount %s are not restricted", subAccountID)
+		return operation, 0, nil
+	}
+
+	for _, entitled := range regions {
+		if entitled == *region {
+			return operation, 0, nil
+		}
+	}
+	return s.operationManager.OperationFailedWithError(operation, internal.NewFailureError(internal.FailureReason{
+		Code:        regionNotEntitledCode,
+		Message:     fmt.Sprintf("subaccount %s is not entitled to region %s, entitled regions: [%s]", subAccountID, *region, strings.Join(regions, ", ")),
+		Remediation: "choose one of the entitled regions or assign the region to the entitlements of the subaccount",
+		Retryable:   false,
+		Subsystem:   entitlementsSubsystem,
+	}), log)
+}
@@ -0,0 +1,74 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/entitlements"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntitledRegionStep_Run(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg                   entitlements.Config
+		region                string
+		expectedState         domain.LastOperationState
+		expectedFailureReason *internal.FailureReason
+	}{
+		"entitled region": {
+			region:        "westeurope",
+			expectedState: domain.InProgress,
+		},
+		"not entitled region": {
+			region:        "eastus",
+			expectedState: domain.Failed,
+			expectedFailureReason: &internal.FailureReason{
+				Code:        regionNotEntitledCode,
+				Message:     "subaccount " + subAccountID + " is not entitled to region eastus, entitled regions: [westeurope, northeurope]",
+				Remediation: "choose one of the entitled regions or assign the region to the entitlements of the subaccount",
+				Subsystem:   entitlementsSubsystem,
+			},
+		},
+		"entitlements integration disabled": {
+			cfg:           entitlements.Config{Disabled: true},
+			region:        "eastus",
+			expectedState: domain.InProgress,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			operation := fixOperationCreateRuntime(t, broker.AzurePlanID, tc.region)
+			require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+
+			client := entitlements.NewFakeClient(map[string][]string{
+				subAccountID: {"westeurope", "northeurope"},
+			})
+			step := NewEntitledRegionStep(memoryStorage.Operations(), client, tc.cfg)
+
+			// when
+			operation, repeat, err := step.Run(operation, logrus.New())
+
+			// then
+			assert.Equal(t, time.Duration(0), repeat)
+			assert.Equal(t, tc.expectedState, operation.State)
+			assert.Equal(t, tc.expectedFailureReason, operation.FailureReason)
+			if tc.expectedState == domain.Failed {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			stored, err := memoryStorage.Operations().GetProvisioningOperationByID(operation.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFailureReason, stored.FailureReason)
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package provisioning
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ExternalWebhookSync webhook finishes its work before it responds to the request
+	ExternalWebhookSync = "sync"
+	// ExternalWebhookAsync webhook accepts the request and reports the result on the status URL
+	ExternalWebhookAsync = "async"
+
+	ExternalWebhookStateInProgress = "in progress"
+	ExternalWebhookStateSucceeded  = "succeeded"
+	ExternalWebhookStateFailed     = "failed"
+
+	externalWebhookFailedCode      = "EXTERNAL_STEP_FAILED"
+	externalWebhookUnavailableCode = "EXTERNAL_STEP_UNAVAILABLE"
+)
+
+// ExternalWebhookConfig configures the provisioning step which is run out of the broker by the webhook of the other team
+type ExternalWebhookConfig struct {
+	// Name is the name of the step, it must be unique among the provisioning steps
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Mode is sync or async
+	Mode string `yaml:"mode"`
+	// Weight orders the step among the provisioning steps, the steps with the same weight are run in the order they were added
+	Weight int `yaml:"weight"`
+	// Timeout is the timeout of a single request to the webhook
+	Timeout time.Duration `yaml:"timeout"`
+	// RetryInterval is the interval between the retries of the failed requests and between the polls of the status
+	RetryInterval time.Duration `yaml:"retryInterval"`
+	// MaxTime is the time after which the step fails when the webhook is still not available or not finished
+	MaxTime time.Duration `yaml:"maxTime"`
+}
+
+// NewExternalWebhooksFromFile reads the configs of the external webhook steps from the YAML file with the list
+// under the webhooks key, empty path means there are no external steps
+func NewExternalWebhooksFromFile(path string) ([]ExternalWebhookConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with external webhooks", path)
+	}
+	var file struct {
+		Webhooks []ExternalWebhookConfig `yaml:"webhooks"`
+	}
+	if err := yaml.Unmarshal(yamlFile, &file); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling YAML file with external webhooks")
+	}
+	for i := range file.Webhooks {
+		if err := file.Webhooks[i].validate(); err != nil {
+			return nil, errors.Wrapf(err, "while validating external webhook %d", i)
+		}
+	}
+
+	return file.Webhooks, nil
+}
+
+func (c *ExternalWebhookConfig) validate() error {
+	if c.Name == "" || c.URL == "" {
+		return errors.New("name and url must be set")
+	}
+	switch c.Mode {
+	case "":
+		c.Mode = ExternalWebhookSync
+	case ExternalWebhookSync, ExternalWebhookAsync:
+	default:
+		return errors.Errorf("mode of webhook %s must be %s or %s, got %q", c.Name, ExternalWebhookSync, ExternalWebhookAsync, c.Mode)
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = 30 * time.Second
+	}
+	if c.MaxTime <= 0 {
+		c.MaxTime = 30 * time.Minute
+	}
+	return nil
+}
+
+// ExternalWebhookRequest is the operation context sent to the webhook, the webhook must handle the repeated requests
+// of the same operation, e.g. after the restart of the broker
+type ExternalWebhookRequest struct {
+	OperationID     string `json:"operationID"`
+	InstanceID      string `json:"instanceID"`
+	RuntimeID       string `json:"runtimeID"`
+	GlobalAccountID string `json:"globalAccountID"`
+	SubAccountID    string `json:"subAccountID"`
+	PlanID          string `json:"planID"`
+	PlatformRegion  string `json:"platformRegion"`
+	ShootName       string `json:"shootName"`
+	ShootDomain     string `json:"shootDomain"`
+}
+
+// ExternalWebhookAccepted is the response of the async webhook which accepted the request
+type ExternalWebhookAccepted struct {
+	StatusURL string `json:"statusUrl"`
+}
+
+// ExternalWebhookStatus is the status of the work of the async webhook
+type ExternalWebhookStatus struct {
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+	// Retryable tells if the failed work can be requested again
+	Retryable bool `json:"retryable,omitempty"`
+}
+
+// ExternalWebhookStep posts the operation context to the webhook of the external system. In the sync mode the step
+// finishes when the webhook responds with 2xx. In the async mode the webhook responds with 202 Accepted and the URL
+// on which the step polls the status until the external system reports the work as succeeded or failed.
+//
+// The connection errors and the 408, 429 and 5xx responses are retried, other responses and the failed status which
+// is not retryable fail the operation at once. The status URL is stored on the operation, so the polling continues
+// after the restart of the broker.
+type ExternalWebhookStep struct {
+	operationManager *process.ProvisionOperationManager
+	cfg              ExternalWebhookConfig
+	client           *http.Client
+}
+
+func NewExternalWebhookStep(os storage.Operations, cfg ExternalWebhookConfig) *ExternalWebhookStep {
+	return &ExternalWebhookStep{
+		operationManager: process.NewProvisionOperationManager(os),
+		cfg:              cfg,
+		client:           &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (s *ExternalWebhookStep) Name() string {
+	return s.cfg.Name
+}
+
+func (s *ExternalWebhookStep) Run(operation internal.ProvisioningOperation, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	state, found := operation.ExternalWebhooks[s.cfg.Name]
+	if found && state.StatusURL != "" {
+		return s.poll(operation, state, log)
+	}
+	startedAt := operation.UpdatedAt
+	if found {
+		// the work which failed is requested again, the deadline is counted from the first request
+		startedAt = state.StartedAt
+	}
+
+	accepted, err := s.post(operation)
+	switch {
+	case kebError.IsTemporaryError(err):
+		return s.retry(operation, startedAt, err, log)
+	case err != nil:
+		return s.fail(operation, err.Error(), false, log)
+	case accepted == nil:
+		log.Infof("External step %s succeeded", s.cfg.Name)
+		return operation, 0, nil
+	}
+
+	log.Infof("External step %s accepted the request, polling %s", s.cfg.Name, accepted.StatusURL)
+	return s.saveState(operation, internal.ExternalWebhookState{StatusURL: accepted.StatusURL, StartedAt: startedAt}, log)
+}
+
+func (s *ExternalWebhookStep) saveState(operation internal.ProvisioningOperation, state internal.ExternalWebhookState, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		if operation.ExternalWebhooks == nil {
+			operation.ExternalWebhooks = map[string]internal.ExternalWebhookState{}
+		}
+		operation.ExternalWebhooks[s.cfg.Name] = state
+	}, log)
+	if repeat != 0 {
+		return operation, repeat, nil
+	}
+	return operation, s.cfg.RetryInterval, nil
+}
+
+// post sends the request, nil is returned when the webhook finished the work, the status URL when it accepted the work
+func (s *ExternalWebhookStep) post(operation internal.ProvisioningOperation) (*ExternalWebhookAccepted, error) {
+	body, err := json.Marshal(ExternalWebhookRequest{
+		OperationID:     operation.ID,
+		InstanceID:      operation.InstanceID,
+		RuntimeID:       operation.RuntimeID,
+		GlobalAccountID: operation.ProvisioningParameters.ErsContext.GlobalAccountID,
+		SubAccountID:    operation.ProvisioningParameters.ErsContext.SubAccountID,
+		PlanID:          operation.ProvisioningParameters.PlanID,
+		PlatformRegion:  operation.ProvisioningParameters.PlatformRegion,
+		ShootName:       operation.ShootName,
+		ShootDomain:     operation.ShootDomain,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshaling request")
+	}
+
+	resp, err := s.client.Post(s.cfg.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, kebError.AsTemporaryError(err, "while calling webhook %s", s.cfg.URL)
+	}
+	defer resp.Body.Close()
+	if err := responseError(resp); err != nil {
+		return nil, err
+	}
+	if s.cfg.Mode == ExternalWebhookSync || resp.StatusCode != http.StatusAccepted {
+		return nil, nil
+	}
+
+	accepted := &ExternalWebhookAccepted{}
+	if err := json.NewDecoder(resp.Body).Decode(accepted); err != nil {
+		return nil, errors.Wrap(err, "while decoding response of accepted request")
+	}
+	if accepted.StatusURL == "" {
+		accepted.StatusURL = fmt.Sprintf("%s/%s", strings.TrimSuffix(s.cfg.URL, "/"), operation.ID)
+	}
+	return accepted, nil
+}
+
+func (s *ExternalWebhookStep) poll(operation internal.ProvisioningOperation, state internal.ExternalWebhookState, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	status, err := s.status(state.StatusURL)
+	switch {
+	case kebError.IsTemporaryError(err):
+		return s.retry(operation, state.StartedAt, err, log)
+	case err != nil:
+		return s.fail(operation, err.Error(), false, log)
+	}
+
+	switch status.State {
+	case ExternalWebhookStateSucceeded:
+		log.Infof("External step %s succeeded", s.cfg.Name)
+		return operation, 0, nil
+	case ExternalWebhookStateFailed:
+		if status.Retryable && time.Since(state.StartedAt) < s.cfg.MaxTime {
+			log.Warnf("External step %s failed, requesting again: %s", s.cfg.Name, status.Description)
+			return s.saveState(operation, internal.ExternalWebhookState{StartedAt: state.StartedAt}, log)
+		}
+		return s.fail(operation, fmt.Sprintf("external system reported failure: %s", status.Description), status.Retryable, log)
+	}
+
+	if time.Since(state.StartedAt) > s.cfg.MaxTime {
+		return s.fail(operation, fmt.Sprintf("external system did not finish within %s", s.cfg.MaxTime), true, log)
+	}
+	log.Infof("External step %s is in progress: %s", s.cfg.Name, status.Description)
+	return operation, s.cfg.RetryInterval, nil
+}
+
+func (s *ExternalWebhookStep) status(statusURL string) (ExternalWebhookStatus, error) {
+	resp, err := s.client.Get(statusURL)
+	if err != nil {
+		return ExternalWebhookStatus{}, kebError.AsTemporaryError(err, "while getting status from %s", statusURL)
+	}
+	defer resp.Body.Close()
+	if err := responseError(resp); err != nil {
+		return ExternalWebhookStatus{}, err
+	}
+
+	var status ExternalWebhookStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return ExternalWebhookStatus{}, errors.Wrap(err, "while decoding status")
+	}
+	switch status.State {
+	case ExternalWebhookStateInProgress, ExternalWebhookStateSucceeded, ExternalWebhookStateFailed:
+		return status, nil
+	default:
+		return ExternalWebhookStatus{}, errors.Errorf("unknown state %q of the status", status.State)
+	}
+}
+
+// responseError maps the response which is not 2xx to the error, the temporary error means the request can be retried
+func responseError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return kebError.NewTemporaryError("webhook responded with %d: %s", resp.StatusCode, body)
+	default:
+		return errors.Errorf("webhook responded with %d: %s", resp.StatusCode, body)
+	}
+}
+
+func (s *ExternalWebhookStep) retry(operation internal.ProvisioningOperation, since time.Time, err error, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	if time.Since(since) < s.cfg.MaxTime {
+		log.Warnf("External step %s will be retried in %s: %s", s.cfg.Name, s.cfg.RetryInterval, err)
+		return operation, s.cfg.RetryInterval, nil
+	}
+	return s.fail(operation, err.Error(), true, log)
+}
+
+func (s *ExternalWebhookStep) fail(operation internal.ProvisioningOperation, msg string, retryable bool, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	log.Errorf("External step %s failed: %s", s.cfg.Name, msg)
+	code := externalWebhookFailedCode
+	if retryable {
+		code = externalWebhookUnavailableCode
+	}
+	return s.operationManager.OperationFailedWithError(operation, internal.NewFailureError(internal.FailureReason{
+		Code:      code,
+		Message:   fmt.Sprintf("external step %s failed: %s", s.cfg.Name, msg),
+		Retryable: retryable,
+		Subsystem: s.cfg.Name,
+	}), log)
+}
@@ -0,0 +1,245 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalWebhookStep_Sync(t *testing.T) {
+	// given
+	var received ExternalWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixExternalWebhookOperation(t, memoryStorage)
+	step := NewExternalWebhookStep(memoryStorage.Operations(), fixExternalWebhookConfig(server.URL, ExternalWebhookSync))
+
+	// when
+	operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	assert.Equal(t, domain.InProgress, operation.State)
+	assert.Equal(t, operation.ID, received.OperationID)
+	assert.Equal(t, operation.InstanceID, received.InstanceID)
+	assert.Equal(t, operation.ProvisioningParameters.ErsContext.SubAccountID, received.SubAccountID)
+}
+
+func TestExternalWebhookStep_AsyncPollToCompletion(t *testing.T) {
+	// given
+	polls := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(ExternalWebhookAccepted{StatusURL: fmt.Sprintf("%s/status", server.URL)})
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		state := ExternalWebhookStateInProgress
+		if polls > 1 {
+			state = ExternalWebhookStateSucceeded
+		}
+		json.NewEncoder(w).Encode(ExternalWebhookStatus{State: state})
+	})
+
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixExternalWebhookOperation(t, memoryStorage)
+	step := NewExternalWebhookStep(memoryStorage.Operations(), fixExternalWebhookConfig(server.URL+"/webhook", ExternalWebhookAsync))
+
+	// when
+	operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, repeat)
+	assert.Equal(t, server.URL+"/status", operation.ExternalWebhooks["external"].StatusURL)
+
+	// when
+	operation, repeat, err = step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, repeat)
+
+	// when
+	operation, repeat, err = step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	assert.Equal(t, 2, polls)
+	stored, err := memoryStorage.Operations().GetProvisioningOperationByID(operation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/status", stored.ExternalWebhooks["external"].StatusURL)
+}
+
+func TestExternalWebhookStep_ErrorMapping(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mode           string
+		postStatus     int
+		status         *ExternalWebhookStatus
+		expectedRepeat time.Duration
+		expectedState  domain.LastOperationState
+	}{
+		"bad request is not retryable": {
+			mode:          ExternalWebhookSync,
+			postStatus:    http.StatusBadRequest,
+			expectedState: domain.Failed,
+		},
+		"service unavailable is retried": {
+			mode:           ExternalWebhookSync,
+			postStatus:     http.StatusServiceUnavailable,
+			expectedRepeat: time.Second,
+			expectedState:  domain.InProgress,
+		},
+		"too many requests is retried": {
+			mode:           ExternalWebhookSync,
+			postStatus:     http.StatusTooManyRequests,
+			expectedRepeat: time.Second,
+			expectedState:  domain.InProgress,
+		},
+		"failed status is not retryable": {
+			mode:          ExternalWebhookAsync,
+			postStatus:    http.StatusAccepted,
+			status:        &ExternalWebhookStatus{State: ExternalWebhookStateFailed, Description: "quota exceeded"},
+			expectedState: domain.Failed,
+		},
+		"retryable failed status is requested again": {
+			mode:           ExternalWebhookAsync,
+			postStatus:     http.StatusAccepted,
+			status:         &ExternalWebhookStatus{State: ExternalWebhookStateFailed, Retryable: true},
+			expectedRepeat: time.Second,
+			expectedState:  domain.InProgress,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.postStatus)
+				if tc.postStatus == http.StatusAccepted {
+					json.NewEncoder(w).Encode(ExternalWebhookAccepted{StatusURL: fmt.Sprintf("%s/status", server.URL)})
+				}
+			})
+			mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(tc.status)
+			})
+
+			memoryStorage := storage.NewMemoryStorage()
+			operation := fixExternalWebhookOperation(t, memoryStorage)
+			step := NewExternalWebhookStep(memoryStorage.Operations(), fixExternalWebhookConfig(server.URL+"/webhook", tc.mode))
+
+			// when
+			operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+			if tc.status != nil {
+				require.NoError(t, err)
+				operation, repeat, err = step.Run(operation, logger.NewLogDummy())
+			}
+
+			// then
+			assert.Equal(t, tc.expectedRepeat, repeat)
+			assert.Equal(t, tc.expectedState, operation.State)
+			if tc.expectedState == domain.Failed {
+				require.Error(t, err)
+				require.NotNil(t, operation.FailureReason)
+				assert.False(t, operation.FailureReason.Retryable)
+				assert.Equal(t, "external", operation.FailureReason.Subsystem)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExternalWebhookStep_RetryTimeExceeded(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixExternalWebhookOperation(t, memoryStorage)
+	operation.UpdatedAt = time.Now().Add(-time.Hour)
+	step := NewExternalWebhookStep(memoryStorage.Operations(), fixExternalWebhookConfig(server.URL, ExternalWebhookSync))
+
+	// when
+	operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.Error(t, err)
+	assert.Zero(t, repeat)
+	assert.Equal(t, domain.Failed, operation.State)
+	require.NotNil(t, operation.FailureReason)
+	assert.True(t, operation.FailureReason.Retryable)
+}
+
+func TestNewExternalWebhooksFromFile(t *testing.T) {
+	// when
+	webhooks, err := NewExternalWebhooksFromFile("testdata/external-webhooks.yaml")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []ExternalWebhookConfig{
+		{
+			Name:          "register_in_billing",
+			URL:           "http://billing.example.com/runtimes",
+			Mode:          ExternalWebhookAsync,
+			Weight:        10,
+			Timeout:       10 * time.Second,
+			RetryInterval: time.Minute,
+			MaxTime:       time.Hour,
+		},
+		{
+			Name:          "notify",
+			URL:           "http://notifications.example.com/runtimes",
+			Mode:          ExternalWebhookSync,
+			Weight:        10,
+			Timeout:       30 * time.Second,
+			RetryInterval: 30 * time.Second,
+			MaxTime:       30 * time.Minute,
+		},
+	}, webhooks)
+}
+
+func fixExternalWebhookConfig(url, mode string) ExternalWebhookConfig {
+	return ExternalWebhookConfig{
+		Name:          "external",
+		URL:           url,
+		Mode:          mode,
+		Timeout:       time.Second,
+		RetryInterval: time.Second,
+		MaxTime:       time.Minute,
+	}
+}
+
+func fixExternalWebhookOperation(t *testing.T, memoryStorage storage.BrokerStorage) internal.ProvisioningOperation {
+	operation := fixture.FixProvisioningOperation("op-id", "inst-id")
+	operation.State = domain.InProgress
+	operation.UpdatedAt = time.Now()
+	require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+	return operation
+}
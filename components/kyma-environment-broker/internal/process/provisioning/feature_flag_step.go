@@ -0,0 +1,40 @@
+package provisioning
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
+)
+
+// FeatureFlagStep runs the step only if the feature flag is enabled at the moment of the run
+type FeatureFlagStep struct {
+	step  Step
+	flags featureflags.Provider
+	flag  string
+}
+
+var _ Step = &FeatureFlagStep{}
+
+func NewFeatureFlagStep(flags featureflags.Provider, flag string, step Step) FeatureFlagStep {
+	return FeatureFlagStep{
+		step:  step,
+		flags: flags,
+		flag:  flag,
+	}
+}
+
+func (s FeatureFlagStep) Name() string {
+	return s.step.Name()
+}
+
+func (s FeatureFlagStep) Run(operation internal.ProvisioningOperation, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	if !s.flags.IsEnabled(s.flag) {
+		log.Infof("Skipping step %s, feature %s is disabled", s.Name(), s.flag)
+		return operation, 0, nil
+	}
+
+	return s.step.Run(operation, log)
+}
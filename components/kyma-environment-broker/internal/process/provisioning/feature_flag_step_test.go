@@ -0,0 +1,41 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/featureflags"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/provisioning/automock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlagStep_Run(t *testing.T) {
+	// given
+	log := logrus.New()
+	operation := fixOperationWithPlanID(broker.AzurePlanID)
+	flags := featureflags.Static{featureflags.EDP: false}
+
+	mockStep := new(automock.Step)
+	mockStep.On("Name").Return("Test")
+	mockStep.On("Run", operation, log).Return(operation, time.Duration(10), nil)
+	step := NewFeatureFlagStep(flags, featureflags.EDP, mockStep)
+
+	// when
+	_, repeat, err := step.Run(operation, log)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, repeat)
+	mockStep.AssertNotCalled(t, "Run", operation, log)
+
+	// when the flag is enabled
+	flags[featureflags.EDP] = true
+	_, repeat, err = step.Run(operation, log)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(10), repeat)
+	mockStep.AssertCalled(t, "Run", operation, log)
+}
@@ -132,6 +132,9 @@ func (s *InitialisationStep) initializeRuntimeInputRequest(operation internal.Pr
 	case err == nil:
 		operation.InputCreator = creator
 		return operation, 0, nil
+	case input.IsRegionNotAvailableError(err):
+		log.Errorf("cannot create input creator for plan %s: %s", operation.ProvisioningParameters.PlanID, err)
+		return s.operationManager.OperationFailed(operation, err.Error(), log)
 	case kebError.IsTemporaryError(err):
 		log.Errorf("cannot create input creator at the moment for plan %s and version %s: %s", operation.ProvisioningParameters.PlanID, operation.ProvisioningParameters.Parameters.KymaVersion, err)
 		return s.operationManager.RetryOperation(operation, err.Error(), 5*time.Second, 5*time.Minute, log)
@@ -244,7 +247,7 @@ func (s *InitialisationStep) launchPostActions(operation internal.ProvisioningOp
 	}
 
 	// action #2
-	tags, operation, repeat, err := s.createTagsForRuntime(operation, instance)
+	tags, operation, repeat, err := s.createTagsForRuntime(operation, instance, log)
 	if err != nil || repeat != 0 {
 		log.Errorf("while creating Tags for Evaluation: %s", err)
 		return operation, repeat, nil
@@ -286,7 +289,7 @@ func (s *InitialisationStep) createExternalEval(operation internal.ProvisioningO
 	return operation, 0, nil
 }
 
-func (s *InitialisationStep) createTagsForRuntime(operation internal.ProvisioningOperation, instance *internal.Instance) ([]*avs.Tag, internal.ProvisioningOperation, time.Duration, error) {
+func (s *InitialisationStep) createTagsForRuntime(operation internal.ProvisioningOperation, instance *internal.Instance, log logrus.FieldLogger) ([]*avs.Tag, internal.ProvisioningOperation, time.Duration, error) {
 
 	status, err := s.provisionerClient.RuntimeStatus(instance.GlobalAccountID, operation.RuntimeID)
 	if err != nil {
@@ -308,5 +311,11 @@ func (s *InitialisationStep) createTagsForRuntime(operation internal.Provisionin
 		},
 	}
 
+	labelTags, err := avs.LabelTags(s.internalEvalUpdater.avsConfig, operation.ProvisioningParameters.Parameters.Annotations)
+	if err != nil {
+		log.Warnf("Skipping labels in AVS evaluation tags: %s", err)
+	}
+	result = append(result, labelTags...)
+
 	return result, operation, 0 * time.Second, nil
 }
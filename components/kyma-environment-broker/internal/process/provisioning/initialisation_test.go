@@ -37,6 +37,7 @@ func TestInitialisationStep(t *testing.T) {
 		memoryStorage := storage.NewMemoryStorage()
 
 		operation := fixOperationRuntimeStatus(broker.GCPPlanID)
+		operation.ProvisioningParameters.Parameters.Annotations = map[string]string{"owner": "team-a", "cost-center": "1234"}
 		err := memoryStorage.Operations().InsertProvisioningOperation(operation)
 		assert.NoError(t, err)
 
@@ -71,6 +72,8 @@ func TestInitialisationStep(t *testing.T) {
 		mockAvsSvc.startServer()
 		defer mockAvsSvc.server.Close()
 		avsConfig := avsConfig(mockOauthServer, mockAvsSvc.server)
+		avsConfig.PropagatedLabels = []string{"owner", "environment"}
+		avsConfig.LabelTagClassId = 42
 		avsClient, err := avs.NewClient(context.TODO(), avsConfig, logrus.New())
 		assert.NoError(t, err)
 		avsDel := avs.NewDelegator(avsClient, avsConfig, memoryStorage.Operations())
@@ -106,7 +109,9 @@ func TestInitialisationStep(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, mockAvsSvc.evals, inDB.Avs.AVSEvaluationExternalId)
 		assert.Contains(t, mockAvsSvc.evals, inDB.Avs.AvsEvaluationInternalId)
-		assert.Equal(t, 4, len(mockAvsSvc.evals[inDB.Avs.AvsEvaluationInternalId].Tags))
+		tags := mockAvsSvc.evals[inDB.Avs.AvsEvaluationInternalId].Tags
+		assert.Equal(t, 5, len(tags))
+		assert.Equal(t, &avs.Tag{Content: "owner=team-a", TagClassId: 42}, tags[4])
 	})
 
 	t.Run("run unintialized", func(t *testing.T) {
@@ -43,7 +43,7 @@ type lmsCertStep struct {
 	normalizationRegexp *regexp.Regexp
 }
 
-func NewLmsCertificatesStep(certProvider LmsClient, os storage.Operations, isMandatory bool) *lmsCertStep {
+func NewLmsCertificatesStep(certProvider LmsClient, os storage.Operations, isMandatory func(planID string) bool) *lmsCertStep {
 	return &lmsCertStep{
 		LmsStep: LmsStep{
 			operationManager: process.NewProvisionOperationManager(os),
@@ -159,6 +159,9 @@ func (s *lmsCertStep) Run(operation internal.ProvisioningOperation, l logrus.Fie
 		return s.failLmsAndUpdate(operation, "getting LMS CA certificate timeout", l)
 	}
 
+	// the expiry is persisted for the certificate renewal tracking, the certificate is used even if it cannot be parsed
+	operation = s.persistCertificateExpiry(operation, signedCert, logger)
+
 	operation.InputCreator.SetLabel(kibanaURLLabelKey, fmt.Sprintf("https://kibana.%s", tenantInfo.DNS))
 
 	operation.InputCreator.AppendOverrides("logging", []*gqlschema.ConfigEntryInput{
@@ -202,10 +205,30 @@ func (s *lmsCertStep) Run(operation internal.ProvisioningOperation, l logrus.Fie
 	return operation, 0, nil
 }
 
+func (s *lmsCertStep) persistCertificateExpiry(operation internal.ProvisioningOperation, signedCert string, log logrus.FieldLogger) internal.ProvisioningOperation {
+	expiresAt, err := lms.CertificateExpiry(signedCert)
+	if err != nil {
+		log.Warnf("Unable to get the expiry of the LMS Signed Certificate: %s", err.Error())
+		return operation
+	}
+	log.Infof("LMS Signed Certificate expires at %s", expiresAt.Format(time.RFC3339))
+
+	updatedOperation, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.Lms.CertExpiresAt = expiresAt
+	}, log)
+	if retry > 0 {
+		log.Errorf("Unable to persist the expiry of the LMS Signed Certificate")
+		return operation
+	}
+	updatedOperation.InputCreator = operation.InputCreator
+	return updatedOperation
+}
+
 type LmsStep struct {
 	operationManager *process.ProvisionOperationManager
-	isMandatory      bool
-	expirationTime   time.Duration
+	// isMandatory decides if the failing LMS activation fails the provisioning of the plan
+	isMandatory    func(planID string) bool
+	expirationTime time.Duration
 }
 
 func (s *LmsStep) handleError(operation internal.ProvisioningOperation, log logrus.FieldLogger, since time.Duration, msg string, err error) (internal.ProvisioningOperation, time.Duration, error) {
@@ -223,7 +246,7 @@ func (s *LmsStep) handleError(operation internal.ProvisioningOperation, log logr
 
 func (s *LmsStep) failLmsAndUpdate(operation internal.ProvisioningOperation, msg string, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
 	operation.Lms.Failed = true
-	if s.isMandatory {
+	if s.isMandatory(operation.ProvisioningParameters.PlanID) {
 		return s.operationManager.OperationFailed(operation, msg, log)
 	}
 	modifiedOp, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
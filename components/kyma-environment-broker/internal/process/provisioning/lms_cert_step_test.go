@@ -1,6 +1,7 @@
 package provisioning
 
 import (
+	"encoding/base64"
 	"fmt"
 	"testing"
 	"time"
@@ -16,7 +17,7 @@ import (
 func TestCertStep_RunFreshOperation(t *testing.T) {
 	// given
 	repo := storage.NewMemoryStorage().Operations()
-	svc := NewLmsCertificatesStep(nil, repo, false)
+	svc := NewLmsCertificatesStep(nil, repo, lms.Config{Mandatory: false}.IsMandatory)
 	// a fresh operation
 	operation := internal.ProvisioningOperation{
 		Operation: internal.Operation{
@@ -35,7 +36,7 @@ func TestCertStep_Run(t *testing.T) {
 	// given
 	cli, tID := newFakeClientWithTenant(0)
 	repo := storage.NewMemoryStorage().Operations()
-	svc := NewLmsCertificatesStep(cli, repo, false)
+	svc := NewLmsCertificatesStep(cli, repo, lms.Config{Mandatory: false}.IsMandatory)
 	operation := internal.ProvisioningOperation{
 		Operation: internal.Operation{
 			ProvisioningParameters: internal.ProvisioningParameters{},
@@ -58,12 +59,77 @@ func TestCertStep_Run(t *testing.T) {
 	assert.True(t, cli.IsCertRequestedForTenant(tID))
 }
 
+func TestCertStep_PersistsCertificateExpiry(t *testing.T) {
+	// given
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	signedCert, err := lms.NewFakeSignedCertificate(notAfter)
+	require.NoError(t, err)
+	cli, tID := newFakeClientWithTenant(0)
+	cli.WithSignedCertificate(signedCert)
+	repo := storage.NewMemoryStorage().Operations()
+	svc := NewLmsCertificatesStep(cli, repo, lms.Config{Mandatory: false}.IsMandatory)
+	inputCreator := newInputCreator()
+	operation := internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:                     "op-id",
+			ProvisioningParameters: internal.ProvisioningParameters{},
+			InstanceDetails: internal.InstanceDetails{Lms: internal.LMS{
+				TenantID: tID,
+			}},
+		},
+		InputCreator: inputCreator,
+	}
+	repo.InsertProvisioningOperation(operation)
+
+	// when
+	op, duration, err := svc.Run(operation, fixLogger())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, duration.Seconds())
+	assert.True(t, notAfter.Equal(op.Lms.CertExpiresAt))
+
+	storedOp, err := repo.GetProvisioningOperationByID("op-id")
+	require.NoError(t, err)
+	assert.True(t, notAfter.Equal(storedOp.Lms.CertExpiresAt))
+
+	inputCreator.AssertOverride(t, "logging", gqlschema.ConfigEntryInput{
+		Key: "fluent-bit.config.outputs.forward.tls.cert", Value: base64.StdEncoding.EncodeToString([]byte(signedCert))})
+}
+
+func TestCertStep_CertificateWithoutExpiry(t *testing.T) {
+	// given
+	cli, tID := newFakeClientWithTenant(0)
+	repo := storage.NewMemoryStorage().Operations()
+	svc := NewLmsCertificatesStep(cli, repo, lms.Config{Mandatory: true}.IsMandatory)
+	operation := internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:                     "op-id",
+			ProvisioningParameters: internal.ProvisioningParameters{},
+			InstanceDetails: internal.InstanceDetails{Lms: internal.LMS{
+				TenantID: tID,
+			}},
+		},
+		InputCreator: newInputCreator(),
+	}
+	repo.InsertProvisioningOperation(operation)
+
+	// when
+	op, duration, err := svc.Run(operation, fixLogger())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, duration.Seconds())
+	assert.False(t, op.Lms.Failed)
+	assert.True(t, op.Lms.CertExpiresAt.IsZero())
+}
+
 func TestCertStep_TenantNotReady(t *testing.T) {
 	runForOptionalAndMandatory(t, func(t *testing.T, isMandatory bool, a asserter) {
 		// given
 		cli, tID := newFakeClientWithTenant(time.Hour)
 		repo := storage.NewMemoryStorage().Operations()
-		svc := NewLmsCertificatesStep(cli, repo, isMandatory)
+		svc := NewLmsCertificatesStep(cli, repo, lms.Config{Mandatory: isMandatory}.IsMandatory)
 		operation := internal.ProvisioningOperation{
 			Operation: internal.Operation{
 				ProvisioningParameters: internal.ProvisioningParameters{},
@@ -93,7 +159,7 @@ func TestCertStep_TenantNotReadyTimeout(t *testing.T) {
 		// given
 		cli, tID := newFakeClientWithTenant(time.Hour)
 		repo := storage.NewMemoryStorage().Operations()
-		svc := NewLmsCertificatesStep(cli, repo, isMandatory)
+		svc := NewLmsCertificatesStep(cli, repo, lms.Config{Mandatory: isMandatory}.IsMandatory)
 		operation := internal.ProvisioningOperation{
 			Operation: internal.Operation{
 				ProvisioningParameters: internal.ProvisioningParameters{},
@@ -123,9 +189,9 @@ func TestLmsStepsHappyPath(t *testing.T) {
 	lmsClient := lms.NewFakeClient(0)
 	opRepo := storage.NewMemoryStorage().Operations()
 	tRepo := storage.NewMemoryStorage().LMSTenants()
-	certStep := NewLmsCertificatesStep(lmsClient, opRepo, false)
+	certStep := NewLmsCertificatesStep(lmsClient, opRepo, lms.Config{Mandatory: false}.IsMandatory)
 	tManager := lms.NewTenantManager(tRepo, lmsClient, fixLogger())
-	tenantStep := NewProvideLmsTenantStep(tManager, opRepo, "eu", false)
+	tenantStep := NewProvideLmsTenantStep(tManager, opRepo, "eu", lms.Config{Mandatory: false}.IsMandatory)
 
 	inputCreator := newInputCreator()
 	operation := internal.ProvisioningOperation{
@@ -24,7 +24,7 @@ type provideLmsTenantStep struct {
 	regionOverride   string
 }
 
-func NewProvideLmsTenantStep(tp LmsTenantProvider, repo storage.Operations, regionOverride string, isMandatory bool) *provideLmsTenantStep {
+func NewProvideLmsTenantStep(tp LmsTenantProvider, repo storage.Operations, regionOverride string, isMandatory func(planID string) bool) *provideLmsTenantStep {
 	return &provideLmsTenantStep{
 		LmsStep: LmsStep{
 			operationManager: process.NewProvisionOperationManager(repo),
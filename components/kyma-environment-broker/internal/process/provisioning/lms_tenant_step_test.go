@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +18,7 @@ func TestProvideLmsTenantStep_TenantProviderWithRetry(t *testing.T) {
 	// given
 	now := time.Now()
 	opRepo := storage.NewMemoryStorage().Operations()
-	tenantStep := NewProvideLmsTenantStep(fakeErrorTenantProvider{}, opRepo, "eu", true)
+	tenantStep := NewProvideLmsTenantStep(fakeErrorTenantProvider{}, opRepo, "eu", lms.Config{Mandatory: true}.IsMandatory)
 
 	inputCreator := newInputCreator()
 	operation := internal.ProvisioningOperation{
@@ -41,7 +44,7 @@ func TestProvideLmsTenantStep_TenantProviderWithError(t *testing.T) {
 		// given
 		now := time.Now().Add(-10 * time.Hour)
 		opRepo := storage.NewMemoryStorage().Operations()
-		tenantStep := NewProvideLmsTenantStep(fakeErrorTenantProvider{}, opRepo, "eu", isMandatory)
+		tenantStep := NewProvideLmsTenantStep(fakeErrorTenantProvider{}, opRepo, "eu", lms.Config{Mandatory: isMandatory}.IsMandatory)
 
 		inputCreator := newInputCreator()
 		operation := internal.ProvisioningOperation{
@@ -64,6 +67,58 @@ func TestProvideLmsTenantStep_TenantProviderWithError(t *testing.T) {
 	})
 }
 
+func TestProvideLmsTenantStep_PlanMandatory(t *testing.T) {
+	for name, tc := range map[string]struct {
+		planID         string
+		expectedFailed bool
+	}{
+		"mandatory plan fails the provisioning": {
+			planID:         broker.AzurePlanID,
+			expectedFailed: true,
+		},
+		"not mandatory plan continues the provisioning": {
+			planID:         broker.TrialPlanID,
+			expectedFailed: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			opRepo := storage.NewMemoryStorage().Operations()
+			cfg := lms.Config{Mandatory: true, PlanMandatory: []string{"trial:false"}}
+			tenantStep := NewProvideLmsTenantStep(fakeErrorTenantProvider{}, opRepo, "eu", cfg.IsMandatory)
+
+			operation := internal.ProvisioningOperation{
+				Operation: internal.Operation{
+					ID:        "op-id",
+					State:     domain.InProgress,
+					UpdatedAt: time.Now().Add(-10 * time.Hour),
+					ProvisioningParameters: internal.ProvisioningParameters{
+						PlanID:     tc.planID,
+						Parameters: internal.ProvisioningParametersDTO{Name: "awesome"},
+					},
+					InstanceDetails: internal.InstanceDetails{Lms: internal.LMS{}},
+				},
+				InputCreator: newInputCreator(),
+			}
+			opRepo.InsertProvisioningOperation(operation)
+
+			// when
+			op, when, err := tenantStep.Run(operation, fixLogger())
+
+			// then
+			assert.Zero(t, when)
+			assert.True(t, op.Lms.Failed)
+			if tc.expectedFailed {
+				assert.Error(t, err)
+				assert.Equal(t, domain.Failed, op.State)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InProgress, op.State)
+			}
+		})
+	}
+}
+
 type fakeErrorTenantProvider struct {
 }
 
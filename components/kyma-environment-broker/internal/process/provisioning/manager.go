@@ -6,11 +6,14 @@ import (
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type Step interface {
@@ -22,6 +25,11 @@ type Manager struct {
 	log              logrus.FieldLogger
 	steps            map[int][]Step
 	operationStorage storage.Operations
+	operationManager *process.ProvisionOperationManager
+	retryClassifier  kebError.RetryClassifier
+	sandbox          *process.SandboxedOperations
+	limiter          *ConcurrencyLimiter
+	stepLimiter      *process.StepLimiter
 
 	publisher event.Publisher
 }
@@ -30,6 +38,8 @@ func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.F
 	return &Manager{
 		log:              logger,
 		operationStorage: storage,
+		operationManager: process.NewProvisionOperationManager(storage),
+		retryClassifier:  kebError.IsRetryable,
 		steps:            make(map[int][]Step, 0),
 		publisher:        pub,
 	}
@@ -46,10 +56,84 @@ func (m *Manager) AddStep(weight int, step Step) {
 	m.steps[weight] = append(m.steps[weight], step)
 }
 
-func (m *Manager) runStep(step Step, operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+// SetRetryClassifier replaces the classifier which decides if the step which returned an error is retried,
+// the operation fails immediately when the error is not retryable
+func (m *Manager) SetRetryClassifier(classifier kebError.RetryClassifier) {
+	m.retryClassifier = classifier
+}
+
+// SetSandbox makes the manager run every step in the sandbox, the operation updates made by the step are stored at once
+// when the step finishes. The steps must use the sandbox as the operations storage.
+func (m *Manager) SetSandbox(sandbox *process.SandboxedOperations) {
+	m.sandbox = sandbox
+}
+
+// SetConcurrencyLimiter makes the manager process only the limited number of operations of a plan at the same time,
+// the operations over the limit are repeated later without running any step
+func (m *Manager) SetConcurrencyLimiter(limiter *ConcurrencyLimiter) {
+	m.limiter = limiter
+}
+
+// SetStepLimiter sets the limiter capping the number of operations executing a step at the same time
+func (m *Manager) SetStepLimiter(limiter *process.StepLimiter) {
+	m.stepLimiter = limiter
+}
+
+// saveCurrentStep persists the name of the step which is going to be processed, it allows to find operations stuck at the given step.
+// The time when the step is started for the first time is persisted with it.
+func (m *Manager) saveCurrentStep(operation internal.ProvisioningOperation, step Step, log logrus.FieldLogger) (internal.ProvisioningOperation, error) {
+	started := operation.StartStepTiming(step.Name(), time.Now())
+	if operation.CurrentStep == step.Name() && !started {
+		return operation, nil
+	}
+	operation.CurrentStep = step.Name()
+	op, err := m.operationStorage.UpdateProvisioningOperation(operation)
+	if err != nil {
+		log.Errorf("Unable to save current step: %s", err)
+		return operation, err
+	}
+	return *op, nil
+}
+
+// traceOperation starts the span of the operation processing and stores the traceparent of the root span on the operation
+func (m *Manager) traceOperation(operation internal.ProvisioningOperation, log logrus.FieldLogger) (context.Context, *trace.Span, internal.ProvisioningOperation) {
+	ctx, span, traceParent := process.StartOperationSpan("provisioning", operation.Operation, operation.ProvisioningParameters.PlanID)
+	if traceParent == "" {
+		return ctx, span, operation
+	}
+
+	traced := operation
+	traced.TraceParent = traceParent
+	op, err := m.operationStorage.UpdateProvisioningOperation(traced)
+	if err != nil {
+		log.Warnf("Unable to save trace parent: %s", err)
+		return ctx, span, operation
+	}
+	return ctx, span, *op
+}
+
+func (m *Manager) runStep(ctx context.Context, step Step, operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	if !m.stepLimiter.Acquire(step.Name()) {
+		logger.Infof("Limit of operations executing the step at the same time reached, step will be repeated in %s", m.stepLimiter.RetryInterval())
+		return operation, m.stepLimiter.RetryInterval(), nil
+	}
+	defer m.stepLimiter.Release(step.Name())
+
+	ctx, span := trace.StartSpan(ctx, step.Name())
+
 	start := time.Now()
-	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.ProvisioningStepProcessed{
+	var processedOperation internal.ProvisioningOperation
+	var when time.Duration
+	var err error
+	if m.sandbox != nil {
+		processedOperation, when, err = m.sandbox.Run(operation, func(operation internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration, error) {
+			return step.Run(operation, logger)
+		})
+	} else {
+		processedOperation, when, err = step.Run(operation, logger)
+	}
+	tracing.EndSpan(span, err)
+	m.publisher.Publish(ctx, process.ProvisioningStepProcessed{
 		OldOperation: operation,
 		Operation:    processedOperation,
 		StepProcessed: process.StepProcessed{
@@ -69,10 +153,29 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 		return 3 * time.Second, nil
 	}
 
+	logOperation := process.OperationLogger(m.log, operation.Operation).WithField("planID", operation.ProvisioningParameters.PlanID)
+	if m.limiter == nil {
+		return m.process(*operation, logOperation)
+	}
+
+	planID := operation.ProvisioningParameters.PlanID
+	if !m.limiter.Acquire(planID, operation.ID) {
+		logOperation.Infof("Limit of operations of the plan processed at the same time reached, operation will be repeated in %s", m.limiter.RetryInterval())
+		return m.limiter.RetryInterval(), nil
+	}
+	when, err := m.process(*operation, logOperation)
+	if when == 0 {
+		m.limiter.Release(planID, operation.ID)
+	}
+	return when, err
+}
+
+func (m *Manager) process(operation internal.ProvisioningOperation, logOperation logrus.FieldLogger) (time.Duration, error) {
 	var when time.Duration
-	processedOperation := *operation
+	var err error
 
-	logOperation := m.log.WithFields(logrus.Fields{"operation": operationID, "instanceID": operation.InstanceID, "planID": operation.ProvisioningParameters.PlanID})
+	ctx, span, processedOperation := m.traceOperation(operation, logOperation)
+	defer span.End()
 
 	logOperation.Info("Start process operation steps")
 	for _, weightStep := range m.sortWeight() {
@@ -81,7 +184,28 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 			logStep := logOperation.WithField("step", step.Name())
 			logStep.Infof("Start step")
 
-			processedOperation, when, err = m.runStep(step, processedOperation, logStep)
+			processedOperation, err = m.saveCurrentStep(processedOperation, step, logStep)
+			if err != nil {
+				return time.Second, nil
+			}
+
+			processedOperation, when, err = m.runStep(ctx, step, processedOperation, logStep)
+			if err != nil && !processedOperation.IsFinished() && !m.retryClassifier(err) {
+				logStep.Errorf("Process operation failed with non-retryable error: %s", err)
+				failedOperation, repeat, failErr := m.operationManager.OperationFailedWithError(processedOperation, err, logStep)
+				if repeat == 0 {
+					m.publisher.Publish(ctx, process.OperationFailed{
+						StepProcessed: process.StepProcessed{StepName: step.Name(), Error: err},
+						Operation:     failedOperation.Operation,
+						RuntimeID:     failedOperation.RuntimeID,
+					})
+				}
+				return repeat, failErr
+			}
+			if err != nil && when != 0 && !processedOperation.IsFinished() {
+				logStep.Warnf("Process operation attempt failed, it will be repeated in %s: %s", when, err)
+				return when, nil
+			}
 			if err != nil {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
@@ -92,6 +216,7 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 			}
 			if when == 0 {
 				logStep.Info("Process operation successful")
+				processedOperation.FinishStepTiming(step.Name(), time.Now())
 				continue
 			}
 
@@ -100,6 +225,11 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 		}
 	}
 
+	// the finish of the last step is not stored with the start of the next one
+	if _, err := m.operationStorage.UpdateProvisioningOperation(processedOperation); err != nil {
+		logOperation.Errorf("Unable to save step timings: %s", err)
+	}
+
 	logOperation.Infof("Operation %q got status %s. All steps finished.", operation.ID, processedOperation.State)
 	return 0, nil
 }
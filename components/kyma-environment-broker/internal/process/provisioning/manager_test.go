@@ -10,6 +10,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
@@ -17,6 +18,8 @@ import (
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -32,6 +35,7 @@ func TestManager_Execute(t *testing.T) {
 		expectedError          bool
 		expectedRepeat         time.Duration
 		expectedDesc           string
+		expectedStep           string
 		expectedNumberOfEvents int
 	}{
 		"operation successful": {
@@ -39,6 +43,7 @@ func TestManager_Execute(t *testing.T) {
 			expectedError:          false,
 			expectedRepeat:         time.Duration(0),
 			expectedDesc:           "init one two final",
+			expectedStep:           "final",
 			expectedNumberOfEvents: 4,
 		},
 		"operation failed": {
@@ -51,6 +56,7 @@ func TestManager_Execute(t *testing.T) {
 			expectedError:          false,
 			expectedRepeat:         time.Duration(10),
 			expectedDesc:           "init",
+			expectedStep:           "init",
 			expectedNumberOfEvents: 1,
 		},
 	} {
@@ -90,6 +96,7 @@ func TestManager_Execute(t *testing.T) {
 				operation, err := memoryStorage.Operations().GetOperationByID(tc.operationID)
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expectedDesc, strings.Trim(operation.Description, " "))
+				assert.Equal(t, tc.expectedStep, operation.CurrentStep)
 			}
 
 			assert.NoError(t, wait.PollImmediate(20*time.Millisecond, 2*time.Second, func() (bool, error) {
@@ -99,6 +106,317 @@ func TestManager_Execute(t *testing.T) {
 	}
 }
 
+func TestManager_ExecuteInSandbox(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+	require.NoError(t, err)
+	sandbox := process.NewSandboxedOperations(memoryStorage.Operations())
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.SetSandbox(sandbox)
+	manager.AddStep(1, &testStep{name: "one", storage: sandbox})
+	manager.AddStep(2, &testStep{name: "two", storage: sandbox})
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, "one two", strings.Trim(operation.Description, " "))
+	assert.Equal(t, "two", operation.CurrentStep)
+}
+
+func TestManager_ExecuteInSandbox_VersionConflict(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+	require.NoError(t, err)
+	sandbox := process.NewSandboxedOperations(memoryStorage.Operations())
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.SetSandbox(sandbox)
+	step := &concurrentlyUpdatedStep{testStep: testStep{t: t, name: "one", storage: sandbox}, operations: memoryStorage.Operations()}
+	manager.AddStep(1, step)
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, repeat)
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, "concurrent update", operation.Description)
+	assert.Equal(t, domain.InProgress, operation.State)
+
+	// when
+	repeat, err = manager.Execute(operationIDSuccess)
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, repeat)
+	assert.Equal(t, 2, step.runs)
+	operation, err = memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, "concurrent update one", operation.Description)
+}
+
+func TestManager_Execute_StepTimings(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+	require.NoError(t, err)
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.AddStep(1, &testStep{name: "one", storage: memoryStorage.Operations()})
+	manager.AddStep(2, &testStep{name: "two", storage: memoryStorage.Operations()})
+
+	// when
+	_, err = manager.Execute(operationIDSuccess)
+	require.NoError(t, err)
+
+	// then
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	require.Len(t, operation.StepTimings, 2)
+	for i, step := range []string{"one", "two"} {
+		assert.Equal(t, step, operation.StepTimings[i].Step)
+		assert.NotNil(t, operation.StepTimings[i].FinishedAt)
+	}
+}
+
+func TestManager_Execute_Traces(t *testing.T) {
+	// given
+	exporter := &spanRecorder{}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+	require.NoError(t, err)
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.AddStep(1, &testStep{name: "one", storage: memoryStorage.Operations()})
+	manager.AddStep(2, &testStep{name: "two", storage: memoryStorage.Operations()})
+
+	// when
+	_, err = manager.Execute(operationIDSuccess)
+	require.NoError(t, err)
+
+	// then
+	spans := exporter.spans
+	require.Len(t, spans, 3)
+	one, two, root := spans[0], spans[1], spans[2]
+	assert.Equal(t, "one", one.Name)
+	assert.Equal(t, "two", two.Name)
+	assert.Equal(t, "provisioning", root.Name)
+	assert.Equal(t, trace.SpanID{}, root.ParentSpanID)
+	assert.Equal(t, root.SpanID, one.ParentSpanID)
+	assert.Equal(t, root.SpanID, two.ParentSpanID)
+	assert.Equal(t, root.TraceID, two.TraceID)
+
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", root.TraceID, root.SpanID), operation.TraceParent)
+}
+
+func TestManager_Execute_RetryClassification(t *testing.T) {
+	for name, tc := range map[string]struct {
+		stepErr          error
+		expectedRepeat   time.Duration
+		expectedErr      bool
+		expectedState    domain.LastOperationState
+		expectedFailures []string
+	}{
+		"retryable error re-queues the operation": {
+			stepErr:        fmt.Errorf("service temporarily unavailable"),
+			expectedRepeat: time.Minute,
+			expectedState:  domain.InProgress,
+		},
+		"non-retryable error fails the operation immediately": {
+			stepErr:          kebError.NewNonRetryableError("invalid parameters"),
+			expectedRepeat:   0,
+			expectedErr:      true,
+			expectedState:    domain.Failed,
+			expectedFailures: []string{"failing"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			err := memoryStorage.Operations().InsertProvisioningOperation(FixProvisionOperation(operationIDSuccess))
+			require.NoError(t, err)
+
+			eventCollector := &CollectingEventHandler{}
+			manager := NewManager(memoryStorage.Operations(), eventCollector, logrus.New())
+			manager.InitStep(&failingStep{err: tc.stepErr, when: time.Minute})
+			manager.AddStep(1, &testStep{name: "one", storage: memoryStorage.Operations()})
+
+			// when
+			repeat, err := manager.Execute(operationIDSuccess)
+
+			// then
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedRepeat, repeat)
+
+			operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedState, operation.State)
+			assert.Equal(t, "failing", operation.CurrentStep)
+			assert.Equal(t, tc.expectedFailures, eventCollector.operationsFailed)
+		})
+	}
+}
+
+func TestManager_Execute_ConcurrencyLimit(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operations := memoryStorage.Operations()
+	for _, id := range []string{"op-1", "op-2", "op-3"} {
+		err := operations.InsertProvisioningOperation(FixProvisionOperation(id))
+		require.NoError(t, err)
+	}
+	uncapped := FixProvisionOperation("op-gcp")
+	uncapped.ProvisioningParameters.PlanID = broker.GCPPlanID
+	err := operations.InsertProvisioningOperation(uncapped)
+	require.NoError(t, err)
+
+	limiter, err := NewConcurrencyLimiter(ConcurrencyConfig{PlanLimits: "azure=2", RetryInterval: 5 * time.Minute})
+	require.NoError(t, err)
+	manager := NewManager(operations, event.NewPubSub(logrus.New()), logrus.New())
+	manager.SetConcurrencyLimiter(limiter)
+	manager.AddStep(1, &failingStep{when: time.Minute})
+
+	// when
+	repeat1, err := manager.Execute("op-1")
+	require.NoError(t, err)
+	repeat2, err := manager.Execute("op-2")
+	require.NoError(t, err)
+	repeat3, err := manager.Execute("op-3")
+	require.NoError(t, err)
+	repeatUncapped, err := manager.Execute("op-gcp")
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, time.Minute, repeat1)
+	assert.Equal(t, time.Minute, repeat2)
+	assert.Equal(t, 5*time.Minute, repeat3)
+	assert.Equal(t, time.Minute, repeatUncapped)
+	assert.Equal(t, map[string]int{broker.AzurePlanID: 2}, limiter.InFlight())
+	gated, err := operations.GetProvisioningOperationByID("op-3")
+	require.NoError(t, err)
+	assert.Empty(t, gated.CurrentStep)
+
+	// when
+	finished, err := operations.GetProvisioningOperationByID("op-1")
+	require.NoError(t, err)
+	finished.State = domain.Succeeded
+	_, err = operations.UpdateProvisioningOperation(*finished)
+	require.NoError(t, err)
+	repeat1, err = manager.Execute("op-1")
+	require.NoError(t, err)
+	repeat3, err = manager.Execute("op-3")
+	require.NoError(t, err)
+
+	// then
+	assert.Zero(t, repeat1)
+	assert.Equal(t, time.Minute, repeat3)
+	assert.Equal(t, map[string]int{broker.AzurePlanID: 2}, limiter.InFlight())
+}
+
+func TestManager_Execute_StepConcurrencyLimit(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operations := memoryStorage.Operations()
+	ids := []string{"op-1", "op-2", "op-3", "op-4"}
+	for _, id := range ids {
+		err := operations.InsertProvisioningOperation(FixProvisionOperation(id))
+		require.NoError(t, err)
+	}
+
+	stepLimiter, err := process.NewStepLimiter(process.StepConcurrencyConfig{StepLimits: "blocking=2", RetryInterval: 5 * time.Minute})
+	require.NoError(t, err)
+	step := &blockingStep{release: make(chan struct{})}
+	manager := NewManager(operations, event.NewPubSub(logrus.New()), logrus.New())
+	manager.SetStepLimiter(stepLimiter)
+	manager.AddStep(1, step)
+
+	// when
+	repeats := make(chan time.Duration, len(ids))
+	for _, id := range ids {
+		go func(id string) {
+			repeat, err := manager.Execute(id)
+			assert.NoError(t, err)
+			repeats <- repeat
+		}(id)
+	}
+
+	// then
+	assert.Equal(t, 5*time.Minute, <-repeats)
+	assert.Equal(t, 5*time.Minute, <-repeats)
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		return step.maxRunning() == 2, nil
+	}))
+
+	// when
+	close(step.release)
+
+	// then
+	assert.Zero(t, <-repeats)
+	assert.Zero(t, <-repeats)
+	assert.Equal(t, 2, step.maxRunning())
+	assert.True(t, stepLimiter.Acquire("blocking"))
+	assert.True(t, stepLimiter.Acquire("blocking"))
+}
+
+func TestNewConcurrencyLimiter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		planLimits     string
+		expectedLimits map[string]int
+		expectedError  bool
+	}{
+		"no limits": {
+			planLimits:     "",
+			expectedLimits: map[string]int{},
+		},
+		"limits of plans": {
+			planLimits:     "trial=10, azure=20",
+			expectedLimits: map[string]int{broker.TrialPlanID: 10, broker.AzurePlanID: 20},
+		},
+		"unknown plan": {
+			planLimits:    "unknown=10",
+			expectedError: true,
+		},
+		"invalid limit": {
+			planLimits:    "trial=0",
+			expectedError: true,
+		},
+		"missing limit": {
+			planLimits:    "trial",
+			expectedError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			limiter, err := NewConcurrencyLimiter(ConcurrencyConfig{PlanLimits: tc.planLimits})
+
+			// then
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLimits, limiter.limits)
+		})
+	}
+}
+
 func FixProvisionOperation(ID string) internal.ProvisioningOperation {
 	provisioningOperation := fixture.FixProvisioningOperation(ID, "fea2c1a1-139d-43f6-910a-a618828a79d5")
 	provisioningOperation.FinishedStages = make(map[string]struct{})
@@ -109,6 +427,18 @@ func FixProvisionOperation(ID string) internal.ProvisioningOperation {
 	return provisioningOperation
 }
 
+// spanRecorder collects the spans in the order they end
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s *trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+}
+
 type testStep struct {
 	t       *testing.T
 	name    string
@@ -138,10 +468,78 @@ func (ts *testStep) Run(operation internal.ProvisioningOperation, logger logrus.
 	}
 }
 
+// concurrentlyUpdatedStep simulates another process updating the operation while the first run of the step is in progress
+type concurrentlyUpdatedStep struct {
+	testStep
+	operations storage.Operations
+	runs       int
+}
+
+func (s *concurrentlyUpdatedStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	s.runs++
+	if s.runs == 1 {
+		concurrent := operation
+		concurrent.Description = "concurrent update"
+		if _, err := s.operations.UpdateProvisioningOperation(concurrent); err != nil {
+			s.t.Errorf("cannot update operation concurrently: %s", err)
+		}
+	}
+	return s.testStep.Run(operation, logger)
+}
+
+type failingStep struct {
+	err  error
+	when time.Duration
+}
+
+func (s *failingStep) Name() string {
+	return "failing"
+}
+
+func (s *failingStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	return operation, s.when, s.err
+}
+
+// blockingStep runs until the release channel is closed and records the highest number of its executions at the same time
+type blockingStep struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (s *blockingStep) Name() string {
+	return "blocking"
+}
+
+func (s *blockingStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	s.mu.Lock()
+	s.running++
+	if s.running > s.max {
+		s.max = s.running
+	}
+	s.mu.Unlock()
+
+	<-s.release
+
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	return operation, 0, nil
+}
+
+func (s *blockingStep) maxRunning() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.max
+}
+
 type CollectingEventHandler struct {
-	mu             sync.Mutex
-	StepsProcessed []string // collects events from the Manager
-	stepsExecuted  []string // collects events from testing steps
+	mu               sync.Mutex
+	StepsProcessed   []string // collects events from the Manager
+	stepsExecuted    []string // collects events from testing steps
+	operationsFailed []string // collects failures of the operations from the Manager
 }
 
 func (h *CollectingEventHandler) OnStepExecuted(_ context.Context, ev interface{}) error {
@@ -162,6 +560,10 @@ func (h *CollectingEventHandler) Publish(ctx context.Context, ev interface{}) {
 	switch ev.(type) {
 	case process.ProvisioningStepProcessed:
 		h.OnStepProcessed(ctx, ev)
+	case process.OperationFailed:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.operationsFailed = append(h.operationsFailed, ev.(process.OperationFailed).StepName)
 	case string:
 		h.OnStepExecuted(ctx, ev)
 	}
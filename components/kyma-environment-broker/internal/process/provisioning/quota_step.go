@@ -0,0 +1,83 @@
+package provisioning
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/quota"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	quotaSubsystem       = "quota"
+	quotaExceededCode    = "QUOTA_EXCEEDED"
+	quotaUnavailableCode = "QUOTA_UNAVAILABLE"
+)
+
+type QuotaClient interface {
+	Remaining(subAccountID, planName string) (int, error)
+}
+
+// QuotaStep fails the provisioning when the subaccount has no runtimes of the plan remaining in its purchased quota,
+// so the operation does not reach the Provisioner.
+type QuotaStep struct {
+	operationManager *process.ProvisionOperationManager
+	client           QuotaClient
+	cfg              quota.Config
+}
+
+func NewQuotaStep(os storage.Operations, client QuotaClient, cfg quota.Config) *QuotaStep {
+	return &QuotaStep{
+		operationManager: process.NewProvisionOperationManager(os),
+		client:           client,
+		cfg:              cfg,
+	}
+}
+
+func (s *QuotaStep) Name() string {
+	return "Validate_Quota"
+}
+
+func (s *QuotaStep) Run(operation internal.ProvisioningOperation, log logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	if s.cfg.Disabled {
+		log.Infof("Skipping step %s because the quota integration is disabled", s.Name())
+		return operation, 0, nil
+	}
+	subAccountID := operation.ProvisioningParameters.ErsContext.SubAccountID
+	planName := broker.PlanNamesMapping[operation.ProvisioningParameters.PlanID]
+
+	remaining, err := s.client.Remaining(subAccountID, planName)
+	switch {
+	case kebError.IsTemporaryError(err):
+		log.Warnf("Unable to get the quota of subaccount %s: %s", subAccountID, err)
+		if time.Since(operation.UpdatedAt) < 10*time.Minute {
+			return operation, 10 * time.Second, nil
+		}
+		return s.operationManager.OperationFailedWithError(operation, internal.NewFailureError(internal.FailureReason{
+			Code:        quotaUnavailableCode,
+			Message:     fmt.Sprintf("unable to get the quota of subaccount %s", subAccountID),
+			Remediation: "retry the provisioning when the quota service is available",
+			Retryable:   true,
+			Subsystem:   quotaSubsystem,
+		}), log)
+	case err != nil:
+		return s.operationManager.OperationFailed(operation, fmt.Sprintf("while getting the quota of subaccount %s: %s", subAccountID, err), log)
+	case remaining > 0:
+		log.Infof("Subaccount %s can provision %d more runtimes of plan %s", subAccountID, remaining, planName)
+		return operation, 0, nil
+	}
+
+	return s.operationManager.OperationFailedWithError(operation, internal.NewFailureError(internal.FailureReason{
+		Code:        quotaExceededCode,
+		Message:     fmt.Sprintf("subaccount %s has no quota remaining for runtimes of plan %s", subAccountID, planName),
+		Remediation: "deprovision one of the runtimes of the plan or purchase more quota for the subaccount",
+		Retryable:   false,
+		Subsystem:   quotaSubsystem,
+	}), log)
+}
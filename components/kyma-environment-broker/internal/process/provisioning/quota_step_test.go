@@ -0,0 +1,74 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/quota"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaStep_Run(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg                   quota.Config
+		remaining             int
+		expectedState         domain.LastOperationState
+		expectedFailureReason *internal.FailureReason
+	}{
+		"quota available": {
+			remaining:     1,
+			expectedState: domain.InProgress,
+		},
+		"quota exhausted": {
+			remaining:     0,
+			expectedState: domain.Failed,
+			expectedFailureReason: &internal.FailureReason{
+				Code:        quotaExceededCode,
+				Message:     "subaccount " + subAccountID + " has no quota remaining for runtimes of plan azure",
+				Remediation: "deprovision one of the runtimes of the plan or purchase more quota for the subaccount",
+				Subsystem:   quotaSubsystem,
+			},
+		},
+		"quota integration disabled": {
+			cfg:           quota.Config{Disabled: true},
+			remaining:     0,
+			expectedState: domain.InProgress,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			operation := fixOperationCreateRuntime(t, broker.AzurePlanID, "westeurope")
+			require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+
+			client := quota.NewFakeClient(map[string]map[string]int{
+				subAccountID: {broker.AzurePlanName: tc.remaining},
+			})
+			step := NewQuotaStep(memoryStorage.Operations(), client, tc.cfg)
+
+			// when
+			operation, repeat, err := step.Run(operation, logrus.New())
+
+			// then
+			assert.Equal(t, time.Duration(0), repeat)
+			assert.Equal(t, tc.expectedState, operation.State)
+			assert.Equal(t, tc.expectedFailureReason, operation.FailureReason)
+			if tc.expectedState == domain.Failed {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			stored, err := memoryStorage.Operations().GetProvisioningOperationByID(operation.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFailureReason, stored.FailureReason)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package provisioning
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	"github.com/pkg/errors"
+)
+
+// ProvisionRequestMutator adjusts the Provisioner request before it is submitted, e.g. sets the experimental fields
+// which are not provisioning parameters yet
+type ProvisionRequestMutator interface {
+	Name() string
+	Mutate(request *gqlschema.ProvisionRuntimeInput, operation internal.ProvisioningOperation) error
+}
+
+// mutateProvisionRequest applies the mutators in the order of registration and validates the mutated request
+func mutateProvisionRequest(request *gqlschema.ProvisionRuntimeInput, operation internal.ProvisioningOperation, mutators []ProvisionRequestMutator) error {
+	if len(mutators) == 0 {
+		return nil
+	}
+	for _, mutator := range mutators {
+		if err := mutator.Mutate(request, operation); err != nil {
+			return errors.Wrapf(err, "while mutating request with %s", mutator.Name())
+		}
+	}
+	return errors.Wrap(validateProvisionRequest(request), "while validating mutated request")
+}
+
+func validateProvisionRequest(request *gqlschema.ProvisionRuntimeInput) error {
+	switch {
+	case request.RuntimeInput == nil || request.RuntimeInput.Name == "":
+		return errors.New("runtime name is missing")
+	case request.KymaConfig == nil || request.KymaConfig.Version == "":
+		return errors.New("Kyma version is missing")
+	case request.ClusterConfig == nil || request.ClusterConfig.GardenerConfig == nil:
+		return errors.New("Gardener config is missing")
+	}
+
+	gardenerConfig := request.ClusterConfig.GardenerConfig
+	for field, value := range map[string]string{
+		"kubernetesVersion": gardenerConfig.KubernetesVersion,
+		"provider":          gardenerConfig.Provider,
+		"region":            gardenerConfig.Region,
+		"machineType":       gardenerConfig.MachineType,
+	} {
+		if value == "" {
+			return errors.Errorf("Gardener config field %s is missing", field)
+		}
+	}
+	if gardenerConfig.AutoScalerMin > gardenerConfig.AutoScalerMax {
+		return errors.Errorf("autoScalerMin %d is greater than autoScalerMax %d", gardenerConfig.AutoScalerMin, gardenerConfig.AutoScalerMax)
+	}
+	for _, annotation := range gardenerConfig.Annotations {
+		if annotation == nil || annotation.Key == "" {
+			return errors.New("annotation key is missing")
+		}
+	}
+	return nil
+}
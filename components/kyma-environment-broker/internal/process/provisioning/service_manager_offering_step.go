@@ -16,9 +16,8 @@ import (
 // ServiceManagerOfferingStep checks if the ServiceManager has the expected offering and
 // stores IDs of the offering, the broker and the plan
 type ServiceManagerOfferingStep struct {
-	stepName     string
-	offeringName string
-	planName     string
+	stepName  string
+	offerings OfferingResolver
 
 	operationManager *process.ProvisionOperationManager
 	extractor        func(po *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo
@@ -31,9 +30,20 @@ func NewServiceManagerOfferingStep(stepName, offeringName, planName string,
 		operationManager: process.NewProvisionOperationManager(repo),
 		extractor:        extractor,
 
-		stepName:     stepName,
-		planName:     planName,
-		offeringName: offeringName,
+		stepName:  stepName,
+		offerings: NewStaticOfferings(offeringName, planName),
+	}
+}
+
+// NewRegionalServiceManagerOfferingStep creates the step which looks up the offering available in the platform region of the instance
+func NewRegionalServiceManagerOfferingStep(stepName string, offerings OfferingResolver,
+	extractor func(po *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo, repo storage.Operations) *ServiceManagerOfferingStep {
+	return &ServiceManagerOfferingStep{
+		operationManager: process.NewProvisionOperationManager(repo),
+		extractor:        extractor,
+
+		stepName:  stepName,
+		offerings: offerings,
 	}
 }
 
@@ -47,32 +57,38 @@ func (s *ServiceManagerOfferingStep) Run(operation internal.ProvisioningOperatio
 		return operation, 0, nil
 	}
 
+	region := operation.ProvisioningParameters.PlatformRegion
+	offering, err := s.offerings.ForRegion(region)
+	if err != nil {
+		return s.operationManager.OperationFailed(operation, err.Error(), log)
+	}
+
 	smCli, err := operation.ServiceManagerClient(log)
 	if err != nil {
 		return s.handleError(operation, err, "unable to create Service Manager client", log)
 	}
 
 	// try to find the offering
-	offerings, err := smCli.ListOfferingsByName(s.offeringName)
+	offerings, err := smCli.ListOfferingsByName(offering.OfferingName)
 	if err != nil {
 		return s.handleError(operation, err, "unable to get Service Manager offerings", log)
 	}
 	if len(offerings.ServiceOfferings) != 1 {
 		return s.operationManager.OperationFailed(operation,
-			fmt.Sprintf("expected one %s Service Manager offering, but found %d", s.offeringName, len(offerings.ServiceOfferings)), log)
+			fmt.Sprintf("expected one %s Service Manager offering in the %q region, but found %d", offering.OfferingName, region, len(offerings.ServiceOfferings)), log)
 	}
 	info.ServiceID = offerings.ServiceOfferings[0].CatalogID
 	info.BrokerID = offerings.ServiceOfferings[0].BrokerID
 	log.Infof("Found offering: catalogID=%s brokerID=%s", info.ServiceID, info.BrokerID)
 
 	// try to find the plan
-	plans, err := smCli.ListPlansByName(s.planName, offerings.ServiceOfferings[0].ID)
+	plans, err := smCli.ListPlansByName(offering.PlanName, offerings.ServiceOfferings[0].ID)
 	if err != nil {
 		return s.handleError(operation, err, "unable to get Service Manager plan", log)
 	}
 	if len(plans.ServicePlans) != 1 {
 		return s.operationManager.OperationFailed(operation,
-			fmt.Sprintf("expected one %s Service Manager plan, but found %d", s.offeringName, len(offerings.ServiceOfferings)), log)
+			fmt.Sprintf("expected one %s Service Manager plan, but found %d", offering.PlanName, len(plans.ServicePlans)), log)
 	}
 	info.PlanID = plans.ServicePlans[0].CatalogID
 	log.Infof("Found plan: catalogID=%s", info.PlanID)
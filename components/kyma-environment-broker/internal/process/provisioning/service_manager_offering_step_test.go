@@ -11,6 +11,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,6 +54,86 @@ func TestServiceManagerOfferingStep_Run(t *testing.T) {
 	assert.Equal(t, "off-br-id", storedOp.XSUAA.Instance.BrokerID)
 }
 
+func TestRegionalServiceManagerOfferingStep_Run(t *testing.T) {
+	// given
+	repo := storage.NewMemoryStorage().Operations()
+	offerings := provisioning.RegionalOfferings{
+		Default: provisioning.ServiceManagerOffering{OfferingName: provisioning.EmsOfferingName, PlanName: provisioning.EmsPlanName},
+		Regions: map[string]provisioning.ServiceManagerOffering{
+			"cf-ap21": {OfferingName: "enterprise-messaging-ap", PlanName: "standard"},
+		},
+	}
+	step := provisioning.NewRegionalServiceManagerOfferingStep("ems-offering", offerings, emsExtractor, repo)
+	operation := fixEmsOfferingOperation("op-ap", "cf-ap21")
+	require.NoError(t, repo.InsertProvisioningOperation(operation))
+
+	// when
+	op, retry, err := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	assert.Zero(t, retry)
+	assert.NoError(t, err)
+	assert.Equal(t, "ap-off-cat-id", op.Ems.Instance.ServiceID)
+	assert.Equal(t, "ap-plan-cat-id", op.Ems.Instance.PlanID)
+}
+
+func TestRegionalServiceManagerOfferingStep_RunWithoutMatchingOffering(t *testing.T) {
+	// given
+	repo := storage.NewMemoryStorage().Operations()
+	offerings := provisioning.RegionalOfferings{
+		Regions: map[string]provisioning.ServiceManagerOffering{
+			"cf-ap21": {OfferingName: "enterprise-messaging-ap", PlanName: "standard"},
+		},
+	}
+	step := provisioning.NewRegionalServiceManagerOfferingStep("ems-offering", offerings, emsExtractor, repo)
+	operation := fixEmsOfferingOperation("op-eu", "cf-eu10")
+	require.NoError(t, repo.InsertProvisioningOperation(operation))
+
+	// when
+	op, retry, _ := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	assert.Zero(t, retry)
+	assert.Equal(t, domain.Failed, op.State)
+	assert.Contains(t, op.Description, `no Service Manager offering is configured for the "cf-eu10" region`)
+	assert.Empty(t, op.Ems.Instance.ServiceID)
+}
+
+func TestRegionalOfferings_ForRegion(t *testing.T) {
+	// given
+	offerings := provisioning.NewStaticOfferings(provisioning.EmsOfferingName, provisioning.EmsPlanName)
+
+	// when
+	offering, err := offerings.ForRegion("cf-us10")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, provisioning.ServiceManagerOffering{OfferingName: provisioning.EmsOfferingName, PlanName: provisioning.EmsPlanName}, offering)
+}
+
+func fixEmsOfferingOperation(id, platformRegion string) internal.ProvisioningOperation {
+	return internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:    id,
+			State: domain.InProgress,
+			ProvisioningParameters: internal.ProvisioningParameters{
+				PlatformRegion: platformRegion,
+			},
+		},
+		SMClientFactory: servicemanager.NewFakeServiceManagerClientFactory([]types.ServiceOffering{
+			{ID: "id-001", Name: provisioning.EmsOfferingName, CatalogID: "off-cat-id", BrokerID: "off-br-id"},
+			{ID: "id-002", Name: "enterprise-messaging-ap", CatalogID: "ap-off-cat-id", BrokerID: "ap-off-br-id"},
+		}, []types.ServicePlan{
+			{ID: "plan-id", Name: provisioning.EmsPlanName, CatalogID: "plan-cat-id"},
+			{ID: "ap-plan-id", Name: "standard", CatalogID: "ap-plan-cat-id"},
+		}),
+	}
+}
+
+func emsExtractor(op *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo {
+	return &op.Ems.Instance
+}
+
 func xsuaaExtractor(op *internal.ProvisioningOperation) *internal.ServiceManagerInstanceInfo {
 	return &op.XSUAA.Instance
 }
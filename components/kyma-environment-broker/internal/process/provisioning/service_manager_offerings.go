@@ -0,0 +1,60 @@
+package provisioning
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ServiceManagerOffering identifies the offering and the plan in Service Manager
+type ServiceManagerOffering struct {
+	OfferingName string `yaml:"offeringName"`
+	PlanName     string `yaml:"planName"`
+}
+
+// OfferingResolver returns the Service Manager offering used in the given platform region
+type OfferingResolver interface {
+	ForRegion(region string) (ServiceManagerOffering, error)
+}
+
+// RegionalOfferings maps the platform region to the Service Manager offering available in it,
+// regions which are not listed use the default offering
+type RegionalOfferings struct {
+	Default ServiceManagerOffering            `yaml:"default"`
+	Regions map[string]ServiceManagerOffering `yaml:"regions"`
+}
+
+// NewStaticOfferings returns the offering which is the same in every region
+func NewStaticOfferings(offeringName, planName string) RegionalOfferings {
+	return RegionalOfferings{Default: ServiceManagerOffering{OfferingName: offeringName, PlanName: planName}}
+}
+
+// NewEmsOfferingsFromFile reads the EMS offerings per region from the YAML file,
+// empty path means the EmsOfferingName offering with the EmsPlanName plan is used in every region
+func NewEmsOfferingsFromFile(path string) (RegionalOfferings, error) {
+	if path == "" {
+		return NewStaticOfferings(EmsOfferingName, EmsPlanName), nil
+	}
+	yamlFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return RegionalOfferings{}, errors.Wrapf(err, "while reading %s file with EMS offerings", path)
+	}
+	var offerings RegionalOfferings
+	if err := yaml.Unmarshal(yamlFile, &offerings); err != nil {
+		return RegionalOfferings{}, errors.Wrap(err, "while unmarshaling YAML file with EMS offerings")
+	}
+
+	return offerings, nil
+}
+
+func (o RegionalOfferings) ForRegion(region string) (ServiceManagerOffering, error) {
+	if offering, found := o.Regions[region]; found {
+		return offering, nil
+	}
+	if o.Default.OfferingName == "" || o.Default.PlanName == "" {
+		return ServiceManagerOffering{}, fmt.Errorf("no Service Manager offering is configured for the %q region", region)
+	}
+	return o.Default, nil
+}
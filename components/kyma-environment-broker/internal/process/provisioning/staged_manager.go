@@ -8,11 +8,13 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type StagedManager struct {
@@ -77,11 +79,13 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 		return 3 * time.Second, nil
 	}
 
-	logOperation := m.log.WithFields(logrus.Fields{"operation": operationID, "instanceID": operation.InstanceID, "planID": operation.ProvisioningParameters.PlanID})
+	logOperation := process.OperationLogger(m.log, operation.Operation).WithField("planID", operation.ProvisioningParameters.PlanID)
 	logOperation.Infof("Start process operation steps for GlobalAcocunt=%s, ", operation.ProvisioningParameters.ErsContext.GlobalAccountID)
 
+	ctx, span, processedOperation := m.traceOperation(*operation, logOperation)
+	defer span.End()
+
 	var when time.Duration
-	processedOperation := *operation
 
 	for _, stage := range m.stages {
 		if processedOperation.IsStageFinished(stage.name) {
@@ -96,7 +100,12 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 				WithField("stage", stage)
 			logStep.Infof("Start step")
 
-			processedOperation, when, err = m.runStep(step, processedOperation, logStep)
+			processedOperation, err = m.saveCurrentStep(processedOperation, step, logStep)
+			if err != nil {
+				return time.Second, nil
+			}
+
+			processedOperation, when, err = m.runStep(ctx, step, processedOperation, logStep)
 			if err != nil {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
@@ -135,10 +144,43 @@ func (m *StagedManager) saveFinishedStage(operation internal.ProvisioningOperati
 	return *op, nil
 }
 
-func (m *StagedManager) runStep(step Step, operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+func (m *StagedManager) saveCurrentStep(operation internal.ProvisioningOperation, step Step, log logrus.FieldLogger) (internal.ProvisioningOperation, error) {
+	if operation.CurrentStep == step.Name() {
+		return operation, nil
+	}
+	operation.CurrentStep = step.Name()
+	op, err := m.operationStorage.UpdateProvisioningOperation(operation)
+	if err != nil {
+		log.Errorf("Unable to save current step: %s", err)
+		return operation, err
+	}
+	return *op, nil
+}
+
+// traceOperation starts the span of the operation processing and stores the traceparent of the root span on the operation
+func (m *StagedManager) traceOperation(operation internal.ProvisioningOperation, log logrus.FieldLogger) (context.Context, *trace.Span, internal.ProvisioningOperation) {
+	ctx, span, traceParent := process.StartOperationSpan("provisioning", operation.Operation, operation.ProvisioningParameters.PlanID)
+	if traceParent == "" {
+		return ctx, span, operation
+	}
+
+	traced := operation
+	traced.TraceParent = traceParent
+	op, err := m.operationStorage.UpdateProvisioningOperation(traced)
+	if err != nil {
+		log.Warnf("Unable to save trace parent: %s", err)
+		return ctx, span, operation
+	}
+	return ctx, span, *op
+}
+
+func (m *StagedManager) runStep(ctx context.Context, step Step, operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	ctx, span := trace.StartSpan(ctx, step.Name())
+
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.ProvisioningStepProcessed{
+	tracing.EndSpan(span, err)
+	m.publisher.Publish(ctx, process.ProvisioningStepProcessed{
 		OldOperation: operation,
 		Operation:    processedOperation,
 		StepProcessed: process.StepProcessed{
@@ -3,43 +3,148 @@ package process
 import (
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/util/workqueue"
 )
 
 type Executor interface {
 	Execute(operationID string) (time.Duration, error)
 }
 
+// operationQueue is the part of the workqueue used by the workers
+type operationQueue interface {
+	Add(item interface{})
+	AddAfter(item interface{}, duration time.Duration)
+	Get() (item interface{}, shutdown bool)
+	Done(item interface{})
+	Forget(item interface{})
+	ShutDown()
+}
+
 type Queue struct {
-	queue     workqueue.RateLimitingInterface
+	queue     *priorityQueue
 	executor  Executor
 	waitGroup sync.WaitGroup
 	log       logrus.FieldLogger
 
 	speedFactor int64
+
+	pauseMu           sync.RWMutex
+	pauseCondition    func() bool
+	pausePollInterval time.Duration
+	paused            int32
+
+	name      string
+	workItems storage.WorkItems
 }
 
+// NewQueue returns the queue which dispatches the operations with the highest priority first,
+// the operations with the same priority are dispatched in FIFO order
 func NewQueue(executor Executor, log logrus.FieldLogger) *Queue {
 	return &Queue{
-		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "operations"),
+		queue:     newPriorityQueue(false),
 		executor:  executor,
 		waitGroup: sync.WaitGroup{},
 		log:       log,
 
-		speedFactor: 1,
+		speedFactor:       1,
+		pausePollInterval: time.Second,
 	}
 }
 
+// NewFairQueue returns the queue which dispatches the operations with the same priority round-robin across
+// the subaccounts, so a single subaccount with many queued operations cannot take all the workers
+func NewFairQueue(executor Executor, log logrus.FieldLogger) *Queue {
+	q := NewQueue(executor, log)
+	q.queue = newPriorityQueue(true)
+	return q
+}
+
+// StoreWorkItems makes the queue store the added operations as work items until they are processed,
+// so the operations in progress can be resumed with Recover after the restart without scanning all the operations
+func (q *Queue) StoreWorkItems(name string, workItems storage.WorkItems) {
+	q.name = name
+	q.workItems = workItems
+}
+
 func (q *Queue) Add(processId string) {
+	q.storeWorkItem(processId, "", DefaultPriority)
 	q.queue.Add(processId)
 }
 
-func (q *Queue) AddAfter(processId string, duration time.Duration) {
-	q.queue.AddAfter(processId, duration)
+// AddWithSubaccount adds the operation of the given subaccount with the default priority,
+// the subaccount is used only by the fair queue
+func (q *Queue) AddWithSubaccount(processId, subAccountID string) {
+	q.AddWithPriority(processId, subAccountID, DefaultPriority)
+}
+
+// AddWithPriority adds the operation of the given subaccount, the operation is dispatched before
+// the operations queued with a lower priority
+func (q *Queue) AddWithPriority(processId, subAccountID string, priority int) {
+	q.storeWorkItem(processId, subAccountID, priority)
+	q.queue.AddWithPriority(processId, subAccountID, priority)
+}
+
+// AddAfter adds the operation of the given subaccount with the given priority after the duration,
+// the work item is stored at once so the delayed operation is resumed after the restart the same way
+func (q *Queue) AddAfter(processId, subAccountID string, priority int, duration time.Duration) {
+	q.storeWorkItem(processId, subAccountID, priority)
+	q.queue.AddWithPriorityAfter(processId, subAccountID, priority, duration)
+}
+
+// Snapshot returns the operations waiting in the queue in the order in which they are dispatched,
+// the operations being processed are not included
+func (q *Queue) Snapshot() []QueuedItem {
+	return q.queue.Snapshot()
+}
+
+// Recover adds the stored work items back to the queue, it returns the number of the resumed operations
+func (q *Queue) Recover() (int, error) {
+	if q.workItems == nil {
+		return 0, nil
+	}
+	items, err := q.workItems.ListByQueue(q.name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "while listing work items of queue %s", q.name)
+	}
+	for _, item := range items {
+		q.queue.AddWithPriority(item.OperationID, item.SubAccountID, item.Priority)
+	}
+
+	return len(items), nil
+}
+
+// storeWorkItem does not fail adding the operation, the operation is only not resumed after the restart if the work item is not stored
+func (q *Queue) storeWorkItem(processId, subAccountID string, priority int) {
+	if q.workItems == nil {
+		return
+	}
+	err := q.workItems.Insert(internal.WorkItem{
+		Queue:        q.name,
+		OperationID:  processId,
+		SubAccountID: subAccountID,
+		Priority:     priority,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		q.log.Errorf("Unable to store work item of operation %s in queue %s: %s", processId, q.name, err)
+	}
+}
+
+func (q *Queue) deleteWorkItem(processId string) {
+	if q.workItems == nil {
+		return
+	}
+	if err := q.workItems.Delete(q.name, processId); err != nil {
+		q.log.Errorf("Unable to delete work item of operation %s from queue %s: %s", processId, q.name, err)
+	}
 }
 
 func (q *Queue) ShutDown() {
@@ -53,24 +158,40 @@ func (q *Queue) Run(stop <-chan struct{}, workersAmount int) {
 	}
 }
 
+// PauseWhen makes the workers stop taking new operations from the queue while the condition is true,
+// e.g. while a dependency required by the steps is unavailable. Operations being processed are not interrupted.
+func (q *Queue) PauseWhen(condition func() bool) {
+	q.pauseMu.Lock()
+	defer q.pauseMu.Unlock()
+	q.pauseCondition = condition
+}
+
+// Paused returns true if the workers stopped taking new operations because of the pause condition
+func (q *Queue) Paused() bool {
+	return atomic.LoadInt32(&q.paused) == 1
+}
+
 // SpeedUp changes speedFactor parameter to reduce time between processing operations.
 //This method should only be used for testing purposes
 func (q *Queue) SpeedUp(speedFactor int64) {
 	q.speedFactor = speedFactor
 }
 
-func (q *Queue) createWorker(queue workqueue.RateLimitingInterface, process func(id string) (time.Duration, error), stopCh <-chan struct{}, waitGroup *sync.WaitGroup, log logrus.FieldLogger) {
+func (q *Queue) createWorker(queue operationQueue, process func(id string) (time.Duration, error), stopCh <-chan struct{}, waitGroup *sync.WaitGroup, log logrus.FieldLogger) {
 	go func() {
-		wait.Until(q.worker(queue, process, log), time.Second, stopCh)
+		wait.Until(q.worker(queue, process, stopCh, log), time.Second, stopCh)
 		waitGroup.Done()
 	}()
 }
 
-func (q *Queue) worker(queue workqueue.RateLimitingInterface, process func(key string) (time.Duration, error), log logrus.FieldLogger) func() {
+func (q *Queue) worker(queue operationQueue, process func(key string) (time.Duration, error), stopCh <-chan struct{}, log logrus.FieldLogger) func() {
 	return func() {
 		exit := false
 		for !exit {
 			exit = func() bool {
+				if !q.waitWhilePaused(stopCh) {
+					return true
+				}
 				key, quit := queue.Get()
 				if quit {
 					return true
@@ -96,8 +217,33 @@ func (q *Queue) worker(queue workqueue.RateLimitingInterface, process func(key s
 				}
 
 				queue.Forget(key)
+				q.deleteWorkItem(id)
 				return false
 			}()
 		}
 	}
 }
+
+// waitWhilePaused blocks until the pause condition is false, it returns false if the queue is stopped in the meantime
+func (q *Queue) waitWhilePaused(stopCh <-chan struct{}) bool {
+	for q.shouldPause() {
+		if atomic.CompareAndSwapInt32(&q.paused, 0, 1) {
+			q.log.Warn("Pausing the queue, new operations are not processed until the dependencies are healthy")
+		}
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(q.pausePollInterval):
+		}
+	}
+	if atomic.CompareAndSwapInt32(&q.paused, 1, 0) {
+		q.log.Info("Resuming the queue")
+	}
+	return true
+}
+
+func (q *Queue) shouldPause() bool {
+	q.pauseMu.RLock()
+	defer q.pauseMu.RUnlock()
+	return q.pauseCondition != nil && q.pauseCondition()
+}
@@ -0,0 +1,335 @@
+package process
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/driver/memory"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_PauseWhen(t *testing.T) {
+	// given
+	executor := &countingExecutor{}
+	queue := NewQueue(executor, logrus.New())
+	queue.pausePollInterval = 10 * time.Millisecond
+
+	var dependencyDown int32 = 1
+	queue.PauseWhen(func() bool { return atomic.LoadInt32(&dependencyDown) == 1 })
+
+	stop := make(chan struct{})
+	defer close(stop)
+	queue.Run(stop, 2)
+
+	// when
+	queue.Add("op-1")
+
+	// then
+	assert.Eventually(t, queue.Paused, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, executor.executed())
+
+	// when
+	atomic.StoreInt32(&dependencyDown, 0)
+
+	// then
+	assert.Eventually(t, func() bool { return len(executor.executed()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.False(t, queue.Paused())
+	assert.Equal(t, []string{"op-1"}, executor.executed())
+}
+
+func TestQueue_FairScheduling(t *testing.T) {
+	for name, tc := range map[string]struct {
+		newQueue      func(executor Executor, log logrus.FieldLogger) *Queue
+		expectedOrder []string
+	}{
+		"FIFO by default": {
+			newQueue:      NewQueue,
+			expectedOrder: []string{"a-1", "a-2", "a-3", "b-1", "b-2", "c-1"},
+		},
+		"round-robin across subaccounts": {
+			newQueue:      NewFairQueue,
+			expectedOrder: []string{"a-1", "b-1", "c-1", "a-2", "b-2", "a-3"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			executor := &countingExecutor{}
+			queue := tc.newQueue(executor, logrus.New())
+			for _, op := range []struct{ id, subAccountID string }{
+				{"a-1", "sub-a"}, {"a-2", "sub-a"}, {"a-3", "sub-a"},
+				{"b-1", "sub-b"}, {"b-2", "sub-b"},
+				{"c-1", "sub-c"},
+			} {
+				queue.AddWithSubaccount(op.id, op.subAccountID)
+			}
+
+			stop := make(chan struct{})
+			defer close(stop)
+
+			// when
+			queue.Run(stop, 1)
+
+			// then
+			assert.Eventually(t, func() bool { return len(executor.executed()) == len(tc.expectedOrder) }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tc.expectedOrder, executor.executed())
+		})
+	}
+}
+
+func TestQueue_Priority(t *testing.T) {
+	for name, tc := range map[string]struct {
+		newQueue      func(executor Executor, log logrus.FieldLogger) *Queue
+		expectedOrder []string
+	}{
+		"FIFO within a priority": {
+			newQueue:      NewQueue,
+			expectedOrder: []string{"high-1", "b-high", "a-1", "a-2", "b-1", "low-1"},
+		},
+		"round-robin within a priority": {
+			newQueue:      NewFairQueue,
+			expectedOrder: []string{"high-1", "b-high", "a-1", "b-1", "a-2", "low-1"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			executor := &countingExecutor{}
+			queue := tc.newQueue(executor, logrus.New())
+			queue.AddWithPriority("low-1", "sub-a", -1)
+			queue.AddWithSubaccount("a-1", "sub-a")
+			queue.AddWithSubaccount("a-2", "sub-a")
+			queue.AddWithSubaccount("b-1", "sub-b")
+			queue.AddWithPriority("high-1", "sub-a", 10)
+			queue.AddWithPriority("b-high", "sub-b", 10)
+
+			stop := make(chan struct{})
+			defer close(stop)
+
+			// when
+			queue.Run(stop, 1)
+
+			// then
+			assert.Eventually(t, func() bool { return len(executor.executed()) == len(tc.expectedOrder) }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tc.expectedOrder, executor.executed())
+		})
+	}
+}
+
+func TestQueue_Snapshot(t *testing.T) {
+	// given
+	queue := NewFairQueue(&countingExecutor{}, logrus.New())
+	queue.AddWithSubaccount("a-1", "sub-a")
+	queue.AddWithSubaccount("a-2", "sub-a")
+	queue.AddWithSubaccount("b-1", "sub-b")
+	queue.AddWithPriority("high-1", "sub-b", 5)
+
+	// when
+	snapshot := queue.Snapshot()
+
+	// then
+	assert.Equal(t, []QueuedItem{
+		{OperationID: "high-1", SubAccountID: "sub-b", Priority: 5},
+		{OperationID: "a-1", SubAccountID: "sub-a", Priority: DefaultPriority},
+		{OperationID: "b-1", SubAccountID: "sub-b", Priority: DefaultPriority},
+		{OperationID: "a-2", SubAccountID: "sub-a", Priority: DefaultPriority},
+	}, snapshot)
+}
+
+func TestPriorityQueue_RetriedItemKeepsPriority(t *testing.T) {
+	// given
+	queue := newPriorityQueue(false)
+	queue.AddWithPriority("high-1", "sub-a", 10)
+	item, _ := queue.Get()
+	queue.AddWithPriority("low-1", "sub-a", DefaultPriority)
+
+	// when
+	queue.Add(item)
+	queue.Done(item)
+
+	// then
+	next, _ := queue.Get()
+	assert.Equal(t, "high-1", next)
+}
+
+func TestFairQueue_ItemAddedWhileProcessing(t *testing.T) {
+	// given
+	queue := newPriorityQueue(true)
+	queue.AddWithPriority("a-1", "sub-a", DefaultPriority)
+	queue.AddWithPriority("a-1", "sub-a", DefaultPriority)
+	queue.AddWithPriority("b-1", "sub-b", DefaultPriority)
+
+	// when
+	item, _ := queue.Get()
+	queue.Add(item)
+
+	// then
+	assert.Equal(t, "a-1", item)
+	next, _ := queue.Get()
+	assert.Equal(t, "b-1", next)
+	queue.Done(next)
+
+	// when
+	queue.Done(item)
+
+	// then
+	again, shutdown := queue.Get()
+	assert.False(t, shutdown)
+	assert.Equal(t, "a-1", again)
+	queue.Done(again)
+	queue.ShutDown()
+	_, shutdown = queue.Get()
+	assert.True(t, shutdown)
+}
+
+func TestQueue_WorkItems(t *testing.T) {
+	t.Run("should resume stored work items after restart", func(t *testing.T) {
+		// given
+		workItems := memory.NewWorkItems()
+		beforeRestart := NewFairQueue(&countingExecutor{}, logrus.New())
+		beforeRestart.StoreWorkItems("provisioning", workItems)
+		beforeRestart.AddWithSubaccount("a-1", "sub-a")
+		beforeRestart.AddWithSubaccount("a-2", "sub-a")
+		beforeRestart.AddWithSubaccount("b-1", "sub-b")
+		beforeRestart.ShutDown()
+
+		executor := &countingExecutor{}
+		queue := NewFairQueue(executor, logrus.New())
+		queue.StoreWorkItems("provisioning", workItems)
+
+		// when
+		resumed, err := queue.Recover()
+		require.NoError(t, err)
+
+		stop := make(chan struct{})
+		defer close(stop)
+		queue.Run(stop, 1)
+
+		// then
+		assert.Equal(t, 3, resumed)
+		assert.Eventually(t, func() bool { return len(executor.executed()) == 3 }, time.Second, 10*time.Millisecond)
+		assert.ElementsMatch(t, []string{"a-1", "a-2", "b-1"}, executor.executed())
+		assert.Eventually(t, func() bool {
+			items, err := workItems.ListByQueue("provisioning")
+			return err == nil && len(items) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should resume stored work items with their priority", func(t *testing.T) {
+		// given
+		workItems := memory.NewWorkItems()
+		beforeRestart := NewQueue(&countingExecutor{}, logrus.New())
+		beforeRestart.StoreWorkItems("provisioning", workItems)
+		beforeRestart.AddWithSubaccount("a-1", "sub-a")
+		beforeRestart.AddWithPriority("high-1", "sub-b", 10)
+		beforeRestart.ShutDown()
+
+		queue := NewQueue(&countingExecutor{}, logrus.New())
+		queue.StoreWorkItems("provisioning", workItems)
+
+		// when
+		_, err := queue.Recover()
+		require.NoError(t, err)
+
+		// then
+		snapshot := queue.Snapshot()
+		require.Len(t, snapshot, 2)
+		assert.Equal(t, QueuedItem{OperationID: "high-1", SubAccountID: "sub-b", Priority: 10}, snapshot[0])
+	})
+
+	t.Run("should store delayed work item with its subaccount and priority", func(t *testing.T) {
+		// given
+		workItems := memory.NewWorkItems()
+		queue := NewFairQueue(&countingExecutor{}, logrus.New())
+		queue.StoreWorkItems("provisioning", workItems)
+
+		// when
+		queue.AddAfter("delayed-1", "sub-a", 10, time.Millisecond)
+
+		// then
+		items, err := workItems.ListByQueue("provisioning")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "sub-a", items[0].SubAccountID)
+		assert.Equal(t, 10, items[0].Priority)
+		assert.Eventually(t, func() bool {
+			snapshot := queue.Snapshot()
+			return len(snapshot) == 1 && snapshot[0] == QueuedItem{OperationID: "delayed-1", SubAccountID: "sub-a", Priority: 10}
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should delete work item only when operation is finished", func(t *testing.T) {
+		// given
+		workItems := memory.NewWorkItems()
+		executor := &retryingExecutor{retry: map[string]bool{"in-progress": true}}
+		queue := NewQueue(executor, logrus.New())
+		queue.StoreWorkItems("deprovisioning", workItems)
+
+		stop := make(chan struct{})
+		defer close(stop)
+		queue.Run(stop, 2)
+
+		// when
+		queue.Add("in-progress")
+		queue.Add("finished")
+
+		// then
+		assert.Eventually(t, func() bool { return len(executor.executed()) == 2 }, time.Second, 10*time.Millisecond)
+		assert.Eventually(t, func() bool {
+			items, err := workItems.ListByQueue("deprovisioning")
+			return err == nil && len(items) == 1 && items[0].OperationID == "in-progress"
+		}, time.Second, 10*time.Millisecond)
+
+		other, err := workItems.ListByQueue("provisioning")
+		require.NoError(t, err)
+		assert.Empty(t, other)
+	})
+
+	t.Run("should not resume anything without work items storage", func(t *testing.T) {
+		// given
+		queue := NewQueue(&countingExecutor{}, logrus.New())
+
+		// when
+		resumed, err := queue.Recover()
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, resumed)
+	})
+}
+
+type countingExecutor struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (e *countingExecutor) Execute(operationID string) (time.Duration, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ids = append(e.ids, operationID)
+	return 0, nil
+}
+
+func (e *countingExecutor) executed() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.ids...)
+}
+
+// retryingExecutor asks to retry the operations from the retry map after an hour
+type retryingExecutor struct {
+	countingExecutor
+	retry map[string]bool
+}
+
+func (e *retryingExecutor) Execute(operationID string) (time.Duration, error) {
+	e.countingExecutor.Execute(operationID)
+	if e.retry[operationID] {
+		return time.Hour, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// StepName is the name of the step in the replayed events
+const StepName = "Replay"
+
+// Response is returned by the replay endpoint, Replayed is false if the events of the operation were already replayed
+type Response struct {
+	OperationID   string                 `json:"operationID"`
+	OperationType internal.OperationType `json:"operationType"`
+	Replayed      bool                   `json:"replayed"`
+}
+
+// Handler republishes the state change of the stored operation through the event broker, so the subscribers
+// can be tested against real data. The events are published with the context marked by event.WithReplay.
+// The events of every operation are published only once.
+type Handler struct {
+	operations storage.Operations
+	publisher  event.Publisher
+	log        logrus.FieldLogger
+
+	mu       sync.Mutex
+	replayed map[string]struct{}
+}
+
+func NewHandler(operations storage.Operations, publisher event.Publisher, log logrus.FieldLogger) *Handler {
+	return &Handler{
+		operations: operations,
+		publisher:  publisher,
+		log:        log.WithField("service", "eventReplay"),
+		replayed:   map[string]struct{}{},
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httputil.WriteErrorResponse(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	operationID := r.URL.Query().Get("operation_id")
+	if operationID == "" {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New("operation_id query parameter is required"))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	switch {
+	case dberr.IsNotFound(err):
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "while getting operation %s", operationID))
+		return
+	case err != nil:
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while getting operation %s", operationID))
+		return
+	}
+
+	resp := Response{OperationID: operationID, OperationType: operation.Type}
+	if _, found := h.replayed[operationID]; found {
+		httputil.WriteResponse(w, http.StatusOK, resp)
+		return
+	}
+
+	ev, err := h.reconstruct(operation)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	h.publisher.Publish(event.WithReplay(context.Background()), ev)
+	h.replayed[operationID] = struct{}{}
+	h.log.Infof("Replayed the events of %s operation %s", operation.Type, operationID)
+
+	resp.Replayed = true
+	httputil.WriteResponse(w, http.StatusOK, resp)
+}
+
+// reconstruct returns the event of the last state change of the operation, the finished operation
+// transitions from the in progress state, the one which is still in progress keeps its state
+func (h *Handler) reconstruct(operation *internal.Operation) (interface{}, error) {
+	step := process.StepProcessed{StepName: StepName}
+
+	switch operation.Type {
+	case internal.OperationTypeProvision:
+		op, err := h.operations.GetProvisioningOperationByID(operation.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting provisioning operation")
+		}
+		old := *op
+		old.State = previousState(op.State)
+		return process.ProvisioningStepProcessed{StepProcessed: step, OldOperation: old, Operation: *op}, nil
+	case internal.OperationTypeDeprovision:
+		op, err := h.operations.GetDeprovisioningOperationByID(operation.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting deprovisioning operation")
+		}
+		old := *op
+		old.State = previousState(op.State)
+		return process.DeprovisioningStepProcessed{StepProcessed: step, OldOperation: old, Operation: *op}, nil
+	case internal.OperationTypeUpgradeKyma:
+		op, err := h.operations.GetUpgradeKymaOperationByID(operation.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting upgrade kyma operation")
+		}
+		old := *op
+		old.State = previousState(op.State)
+		return process.UpgradeKymaStepProcessed{StepProcessed: step, OldOperation: old, Operation: *op}, nil
+	case internal.OperationTypeUpgradeCluster:
+		op, err := h.operations.GetUpgradeClusterOperationByID(operation.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting upgrade cluster operation")
+		}
+		old := *op
+		old.State = previousState(op.State)
+		return process.UpgradeClusterStepProcessed{StepProcessed: step, OldOperation: old, Operation: *op}, nil
+	default:
+		return nil, errors.Errorf("events of the operation type %q cannot be replayed", operation.Type)
+	}
+}
+
+func previousState(state domain.LastOperationState) domain.LastOperationState {
+	if state == domain.Succeeded || state == domain.Failed {
+		return domain.InProgress
+	}
+	return state
+}
@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ReplaysOperationEventsOnce(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixture.FixProvisioningOperation("op-id", "instance-id")
+	operation.State = domain.Succeeded
+	require.NoError(t, memoryStorage.Operations().InsertProvisioningOperation(operation))
+
+	received := make(chan process.ProvisioningStepProcessed, 2)
+	replayed := make(chan bool, 2)
+	eventBroker := event.NewPubSub(logrus.New())
+	eventBroker.Subscribe(process.ProvisioningStepProcessed{}, func(ctx context.Context, ev interface{}) error {
+		replayed <- event.IsReplay(ctx)
+		received <- ev.(process.ProvisioningStepProcessed)
+		return nil
+	})
+	handler := NewHandler(memoryStorage.Operations(), eventBroker, logrus.New())
+
+	// when
+	first := replay(t, handler, "op-id")
+	second := replay(t, handler, "op-id")
+
+	// then
+	assert.Equal(t, Response{OperationID: "op-id", OperationType: internal.OperationTypeProvision, Replayed: true}, first)
+	assert.Equal(t, Response{OperationID: "op-id", OperationType: internal.OperationTypeProvision, Replayed: false}, second)
+
+	select {
+	case ev := <-received:
+		assert.True(t, <-replayed)
+		assert.Equal(t, StepName, ev.StepName)
+		assert.Equal(t, domain.InProgress, ev.OldOperation.State)
+		assert.Equal(t, domain.Succeeded, ev.Operation.State)
+		assert.Equal(t, "instance-id", ev.Operation.InstanceID)
+	case <-time.After(time.Second):
+		t.Fatal("replayed event not received")
+	}
+	select {
+	case <-received:
+		t.Fatal("events of the operation replayed twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandler_ReplaysDeprovisioningOperation(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixture.FixDeprovisioningOperation("op-id", "instance-id")
+	operation.State = domain.Failed
+	require.NoError(t, memoryStorage.Operations().InsertDeprovisioningOperation(operation))
+
+	received := make(chan process.DeprovisioningStepProcessed, 1)
+	eventBroker := event.NewPubSub(logrus.New())
+	eventBroker.Subscribe(process.DeprovisioningStepProcessed{}, func(ctx context.Context, ev interface{}) error {
+		received <- ev.(process.DeprovisioningStepProcessed)
+		return nil
+	})
+	handler := NewHandler(memoryStorage.Operations(), eventBroker, logrus.New())
+
+	// when
+	resp := replay(t, handler, "op-id")
+
+	// then
+	assert.True(t, resp.Replayed)
+	select {
+	case ev := <-received:
+		assert.Equal(t, domain.InProgress, ev.OldOperation.State)
+		assert.Equal(t, domain.Failed, ev.Operation.State)
+	case <-time.After(time.Second):
+		t.Fatal("replayed event not received")
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	// given
+	handler := NewHandler(storage.NewMemoryStorage().Operations(), event.NewPubSub(logrus.New()), logrus.New())
+
+	for name, tc := range map[string]struct {
+		method       string
+		target       string
+		expectedCode int
+	}{
+		"not existing operation": {
+			method:       http.MethodPost,
+			target:       "/events/replay?operation_id=not-existing",
+			expectedCode: http.StatusNotFound,
+		},
+		"missing operation ID": {
+			method:       http.MethodPost,
+			target:       "/events/replay",
+			expectedCode: http.StatusBadRequest,
+		},
+		"GET method": {
+			method:       http.MethodGet,
+			target:       "/events/replay?operation_id=op-id",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.target, nil))
+
+			// then
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
+
+func replay(t *testing.T, handler http.Handler, operationID string) Response {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/events/replay?operation_id="+operationID, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp Response
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	return resp
+}
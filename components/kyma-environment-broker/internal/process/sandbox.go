@@ -0,0 +1,136 @@
+package process
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pkg/errors"
+)
+
+// SandboxedOperations buffers the updates of the provisioning operations processed in the sandbox, the updates of all other
+// operations are passed to the underlying storage. The operation updated by the step is stored once when the step finishes,
+// with the optimistic version check against the version read before the step started. If the processing stops in the middle
+// of the step, nothing is stored and the operation stays in the last consistent state.
+type SandboxedOperations struct {
+	storage.Operations
+
+	mu        sync.Mutex
+	sandboxes map[string]*operationSandbox
+}
+
+type operationSandbox struct {
+	version   int
+	updated   bool
+	operation internal.ProvisioningOperation
+}
+
+func NewSandboxedOperations(operations storage.Operations) *SandboxedOperations {
+	return &SandboxedOperations{
+		Operations: operations,
+		sandboxes:  map[string]*operationSandbox{},
+	}
+}
+
+// Storage returns the broker storage which provisioning operations are served by the sandbox
+func (s *SandboxedOperations) Storage(db storage.BrokerStorage) storage.BrokerStorage {
+	return sandboxedStorage{BrokerStorage: db, operations: s}
+}
+
+// Run executes the step function in the sandbox of the operation and stores the updated operation when it finishes.
+// An error of the final update (e.g. the operation was changed by another process) is returned with the retry interval,
+// the step is executed again with the current operation.
+func (s *SandboxedOperations) Run(operation internal.ProvisioningOperation, run func(operation internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration, error)) (internal.ProvisioningOperation, time.Duration, error) {
+	s.open(operation)
+	defer s.discard(operation.ID)
+
+	processedOperation, when, err := run(operation)
+
+	committed, commitErr := s.commit(operation.ID)
+	if commitErr != nil {
+		return operation, time.Second, errors.Wrap(commitErr, "while storing the operation updated by the step")
+	}
+	if committed != nil {
+		processedOperation.Version = committed.Version
+	}
+	return processedOperation, when, err
+}
+
+func (s *SandboxedOperations) UpdateProvisioningOperation(operation internal.ProvisioningOperation) (*internal.ProvisioningOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sandbox, found := s.sandboxes[operation.ID]
+	if !found {
+		return s.Operations.UpdateProvisioningOperation(operation)
+	}
+	if sandbox.operation.Version != operation.Version {
+		return nil, dberr.Conflict("unable to update provisioning operation with id %s (for instance id %s) - conflict", operation.ID, operation.InstanceID)
+	}
+	operation.UpdatedAt = time.Now()
+	operation.Version = operation.Version + 1
+	sandbox.operation = operation
+	sandbox.updated = true
+
+	return &operation, nil
+}
+
+func (s *SandboxedOperations) GetProvisioningOperationByID(operationID string) (*internal.ProvisioningOperation, error) {
+	s.mu.Lock()
+	sandbox, found := s.sandboxes[operationID]
+	s.mu.Unlock()
+	if !found {
+		return s.Operations.GetProvisioningOperationByID(operationID)
+	}
+
+	operation := sandbox.operation
+	return &operation, nil
+}
+
+func (s *SandboxedOperations) open(operation internal.ProvisioningOperation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sandboxes[operation.ID] = &operationSandbox{
+		version:   operation.Version,
+		operation: operation,
+	}
+}
+
+// commit stores the operation updated in the sandbox, nil is returned if the operation was not updated
+func (s *SandboxedOperations) commit(operationID string) (*internal.ProvisioningOperation, error) {
+	s.mu.Lock()
+	sandbox := s.sandboxes[operationID]
+	delete(s.sandboxes, operationID)
+	s.mu.Unlock()
+
+	if !sandbox.updated {
+		return nil, nil
+	}
+	operation := sandbox.operation
+	operation.Version = sandbox.version
+	return s.Operations.UpdateProvisioningOperation(operation)
+}
+
+func (s *SandboxedOperations) discard(operationID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sandboxes, operationID)
+}
+
+type sandboxedStorage struct {
+	storage.BrokerStorage
+	operations *SandboxedOperations
+}
+
+func (s sandboxedStorage) Operations() storage.Operations {
+	return s.operations
+}
+
+func (s sandboxedStorage) Provisioning() storage.Provisioning {
+	return s.operations
+}
@@ -0,0 +1,120 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sandboxOperationID = "sandbox-operation-id"
+
+func TestSandboxedOperations_Run(t *testing.T) {
+	t.Run("should store the operation updated by the step at once", func(t *testing.T) {
+		// given
+		operations := storage.NewMemoryStorage().Operations()
+		operation := fixSandboxOperation(t, operations)
+		sandbox := NewSandboxedOperations(operations)
+
+		// when
+		processed, when, err := sandbox.Run(operation, func(op internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration, error) {
+			op.Description = "first update"
+			updated, err := sandbox.UpdateProvisioningOperation(op)
+			require.NoError(t, err)
+			stored, err := operations.GetProvisioningOperationByID(sandboxOperationID)
+			require.NoError(t, err)
+			assert.Equal(t, "initial", stored.Description)
+
+			updated.Description = "second update"
+			updated, err = sandbox.UpdateProvisioningOperation(*updated)
+			require.NoError(t, err)
+			return *updated, 0, nil
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, when)
+		stored, err := operations.GetProvisioningOperationByID(sandboxOperationID)
+		require.NoError(t, err)
+		assert.Equal(t, "second update", stored.Description)
+		assert.Equal(t, operation.Version+1, stored.Version)
+		assert.Equal(t, stored.Version, processed.Version)
+	})
+
+	t.Run("should leave the last consistent operation when the step crashes", func(t *testing.T) {
+		// given
+		operations := storage.NewMemoryStorage().Operations()
+		operation := fixSandboxOperation(t, operations)
+		sandbox := NewSandboxedOperations(operations)
+
+		// when
+		assert.Panics(t, func() {
+			sandbox.Run(operation, func(op internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration, error) {
+				op.Description = "partial update"
+				_, err := sandbox.UpdateProvisioningOperation(op)
+				require.NoError(t, err)
+				panic("crash in the middle of the step")
+			})
+		})
+
+		// then
+		stored, err := operations.GetProvisioningOperationByID(sandboxOperationID)
+		require.NoError(t, err)
+		assert.Equal(t, "initial", stored.Description)
+		assert.Equal(t, operation.Version, stored.Version)
+
+		// the sandbox is discarded, the next updates are stored directly
+		stored.Description = "after crash"
+		_, err = sandbox.UpdateProvisioningOperation(*stored)
+		require.NoError(t, err)
+		stored, err = operations.GetProvisioningOperationByID(sandboxOperationID)
+		require.NoError(t, err)
+		assert.Equal(t, "after crash", stored.Description)
+	})
+
+	t.Run("should not overwrite the operation changed by another process", func(t *testing.T) {
+		// given
+		operations := storage.NewMemoryStorage().Operations()
+		operation := fixSandboxOperation(t, operations)
+		sandbox := NewSandboxedOperations(operations)
+
+		// when
+		processed, when, err := sandbox.Run(operation, func(op internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration, error) {
+			concurrent := op
+			concurrent.Description = "concurrent update"
+			_, err := operations.UpdateProvisioningOperation(concurrent)
+			require.NoError(t, err)
+
+			op.Description = "step update"
+			updated, err := sandbox.UpdateProvisioningOperation(op)
+			require.NoError(t, err)
+			return *updated, 0, nil
+		})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, dberr.IsConflict(errors.Cause(err)))
+		assert.Equal(t, time.Second, when)
+		assert.Equal(t, operation, processed)
+		stored, err := operations.GetProvisioningOperationByID(sandboxOperationID)
+		require.NoError(t, err)
+		assert.Equal(t, "concurrent update", stored.Description)
+	})
+}
+
+func fixSandboxOperation(t *testing.T, operations storage.Operations) internal.ProvisioningOperation {
+	operation := fixture.FixProvisioningOperation(sandboxOperationID, "sandbox-instance-id")
+	operation.Description = "initial"
+	err := operations.InsertProvisioningOperation(operation)
+	require.NoError(t, err)
+
+	stored, err := operations.GetProvisioningOperationByID(sandboxOperationID)
+	require.NoError(t, err)
+	return *stored
+}
@@ -0,0 +1,96 @@
+package process
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type StartupProcessingConfig struct {
+	// Delay is the time waited after the start before the operations in progress are reprocessed
+	Delay time.Duration `envconfig:"default=0s"`
+	// WaitForDependencies defers the reprocessing until the readiness checks of the dependencies pass
+	WaitForDependencies bool `envconfig:"default=false"`
+	// DependenciesTimeout limits the time waited for the dependencies, the operations are reprocessed when it passes
+	DependenciesTimeout time.Duration `envconfig:"default=5m"`
+}
+
+// StartupProcessing defers the reprocessing of the operations in progress on start, so the queues are not flooded
+// with the reprocessed operations while the dependencies are still warming up. Without the delay and the readiness checks
+// the operations are reprocessed immediately, before the broker starts serving requests.
+type StartupProcessing struct {
+	cfg          StartupProcessingConfig
+	readiness    []func() bool
+	pollInterval time.Duration
+	log          logrus.FieldLogger
+}
+
+func NewStartupProcessing(cfg StartupProcessingConfig, log logrus.FieldLogger) *StartupProcessing {
+	return &StartupProcessing{
+		cfg:          cfg,
+		pollInterval: time.Second,
+		log:          log.WithField("service", "StartupProcessing"),
+	}
+}
+
+// WaitFor adds the readiness check of the dependency, it is used only if waiting for the dependencies is enabled
+func (s *StartupProcessing) WaitFor(ready func() bool) {
+	if s.cfg.WaitForDependencies {
+		s.readiness = append(s.readiness, ready)
+	}
+}
+
+// Run executes the reprocessing, it is executed in the background when it is deferred.
+// The reprocessing is skipped if the stop channel is closed while waiting.
+func (s *StartupProcessing) Run(stop <-chan struct{}, reprocess func()) {
+	if s.cfg.Delay <= 0 && len(s.readiness) == 0 {
+		reprocess()
+		return
+	}
+	go func() {
+		if s.Wait(stop) {
+			reprocess()
+		}
+	}()
+}
+
+// Wait blocks until the delay passed and the dependencies are ready or the waiting for them timed out,
+// false is returned if the stop channel was closed before
+func (s *StartupProcessing) Wait(stop <-chan struct{}) bool {
+	if s.cfg.Delay > 0 {
+		s.log.Infof("Deferring processing of operations in progress by %s", s.cfg.Delay)
+		select {
+		case <-time.After(s.cfg.Delay):
+		case <-stop:
+			return false
+		}
+	}
+	if len(s.readiness) == 0 {
+		return true
+	}
+
+	timeout := time.After(s.cfg.DependenciesTimeout)
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for !s.ready() {
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			s.log.Warnf("Dependencies are not ready after %s, processing operations in progress anyway", s.cfg.DependenciesTimeout)
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	s.log.Info("Dependencies are ready, processing operations in progress")
+	return true
+}
+
+func (s *StartupProcessing) ready() bool {
+	for _, ready := range s.readiness {
+		if !ready() {
+			return false
+		}
+	}
+	return true
+}
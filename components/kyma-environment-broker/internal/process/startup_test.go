@@ -0,0 +1,90 @@
+package process
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartupProcessing_Run(t *testing.T) {
+	t.Run("should reprocess immediately when nothing is configured", func(t *testing.T) {
+		// given
+		startup := NewStartupProcessing(StartupProcessingConfig{}, logrus.New())
+		startup.WaitFor(func() bool { return false })
+		reprocessed := false
+
+		// when
+		startup.Run(make(chan struct{}), func() { reprocessed = true })
+
+		// then
+		assert.True(t, reprocessed)
+	})
+
+	t.Run("should defer reprocessing by the configured delay", func(t *testing.T) {
+		// given
+		delay := 100 * time.Millisecond
+		startup := NewStartupProcessing(StartupProcessingConfig{Delay: delay}, logrus.New())
+		started := time.Now()
+		reprocessed := make(chan time.Time, 1)
+
+		// when
+		startup.Run(make(chan struct{}), func() { reprocessed <- time.Now() })
+
+		// then
+		select {
+		case at := <-reprocessed:
+			assert.True(t, at.Sub(started) >= delay)
+		case <-time.After(time.Second):
+			t.Fatal("operations were not reprocessed")
+		}
+	})
+
+	t.Run("should defer reprocessing until the dependencies are ready", func(t *testing.T) {
+		// given
+		startup := NewStartupProcessing(StartupProcessingConfig{WaitForDependencies: true, DependenciesTimeout: time.Minute}, logrus.New())
+		startup.pollInterval = 10 * time.Millisecond
+		var checks int32
+		startup.WaitFor(func() bool { return atomic.AddInt32(&checks, 1) > 3 })
+		reprocessed := make(chan struct{})
+
+		// when
+		startup.Run(make(chan struct{}), func() { close(reprocessed) })
+
+		// then
+		select {
+		case <-reprocessed:
+			assert.Equal(t, int32(4), atomic.LoadInt32(&checks))
+		case <-time.After(time.Second):
+			t.Fatal("operations were not reprocessed")
+		}
+	})
+
+	t.Run("should reprocess when waiting for the dependencies timed out", func(t *testing.T) {
+		// given
+		startup := NewStartupProcessing(StartupProcessingConfig{WaitForDependencies: true, DependenciesTimeout: 50 * time.Millisecond}, logrus.New())
+		startup.pollInterval = 10 * time.Millisecond
+		startup.WaitFor(func() bool { return false })
+
+		// when
+		ready := startup.Wait(make(chan struct{}))
+
+		// then
+		assert.True(t, ready)
+	})
+
+	t.Run("should skip reprocessing when stopped while waiting", func(t *testing.T) {
+		// given
+		startup := NewStartupProcessing(StartupProcessingConfig{Delay: time.Minute}, logrus.New())
+		stop := make(chan struct{})
+		close(stop)
+
+		// when
+		ready := startup.Wait(stop)
+
+		// then
+		assert.False(t, ready)
+	})
+}
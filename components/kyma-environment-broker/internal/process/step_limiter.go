@@ -0,0 +1,92 @@
+package process
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type StepConcurrencyConfig struct {
+	// StepLimits is a comma-separated list of step names with the maximum number of operations executing the step
+	// at the same time, e.g. "Create_Runtime=5,EDP_Registration=2". The steps which are not listed are not limited.
+	StepLimits string `envconfig:"optional"`
+	// RetryInterval defines how long the operation which reached the limit of the step waits before the step is executed again
+	RetryInterval time.Duration `envconfig:"default=10s"`
+}
+
+// StepLimiter caps the number of operations executing the step at the same time with a semaphore per step.
+// The operation which does not get a slot is not blocked, the manager repeats the step after the retry interval,
+// so the workers are free to process other operations. The nil limiter does not limit any step.
+type StepLimiter struct {
+	semaphores    map[string]chan struct{}
+	retryInterval time.Duration
+}
+
+func NewStepLimiter(cfg StepConcurrencyConfig) (*StepLimiter, error) {
+	limits, err := parseStepLimits(cfg.StepLimits)
+	if err != nil {
+		return nil, err
+	}
+	semaphores := make(map[string]chan struct{}, len(limits))
+	for step, limit := range limits {
+		semaphores[step] = make(chan struct{}, limit)
+	}
+	return &StepLimiter{
+		semaphores:    semaphores,
+		retryInterval: cfg.RetryInterval,
+	}, nil
+}
+
+func parseStepLimits(in string) (map[string]int, error) {
+	limits := make(map[string]int)
+	if strings.TrimSpace(in) == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(in, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("step limit %q must have the form <step name>=<limit>", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || limit <= 0 {
+			return nil, errors.Errorf("limit of step %q must be a positive number", parts[0])
+		}
+		limits[strings.TrimSpace(parts[0])] = limit
+	}
+	return limits, nil
+}
+
+// Acquire takes a slot of the step, false is returned when all slots of the step are taken.
+// The slot must be freed with Release when the step execution ends.
+func (l *StepLimiter) Acquire(step string) bool {
+	if l == nil {
+		return true
+	}
+	semaphore, limited := l.semaphores[step]
+	if !limited {
+		return true
+	}
+	select {
+	case semaphore <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees the slot of the step
+func (l *StepLimiter) Release(step string) {
+	if l == nil {
+		return
+	}
+	if semaphore, limited := l.semaphores[step]; limited {
+		<-semaphore
+	}
+}
+
+// RetryInterval returns the time after which the step which did not get a slot is executed again
+func (l *StepLimiter) RetryInterval() time.Duration {
+	return l.retryInterval
+}
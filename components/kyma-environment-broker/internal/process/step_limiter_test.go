@@ -0,0 +1,89 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepLimiter_Acquire(t *testing.T) {
+	// given
+	limiter, err := NewStepLimiter(StepConcurrencyConfig{StepLimits: "Create_Runtime=2", RetryInterval: time.Minute})
+	require.NoError(t, err)
+
+	// when
+	first := limiter.Acquire("Create_Runtime")
+	second := limiter.Acquire("Create_Runtime")
+	third := limiter.Acquire("Create_Runtime")
+
+	// then
+	assert.True(t, first)
+	assert.True(t, second)
+	assert.False(t, third)
+	assert.True(t, limiter.Acquire("Check_Runtime_Status"))
+	assert.Equal(t, time.Minute, limiter.RetryInterval())
+
+	// when
+	limiter.Release("Create_Runtime")
+
+	// then
+	assert.True(t, limiter.Acquire("Create_Runtime"))
+	assert.False(t, limiter.Acquire("Create_Runtime"))
+}
+
+func TestStepLimiter_Nil(t *testing.T) {
+	// given
+	var limiter *StepLimiter
+
+	// then
+	assert.True(t, limiter.Acquire("Create_Runtime"))
+	limiter.Release("Create_Runtime")
+}
+
+func TestNewStepLimiter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		stepLimits     string
+		expectedLimits map[string]int
+		expectedError  bool
+	}{
+		"no limits": {
+			stepLimits:     "",
+			expectedLimits: map[string]int{},
+		},
+		"limits of steps": {
+			stepLimits:     "Create_Runtime=5, EDP_Registration=2",
+			expectedLimits: map[string]int{"Create_Runtime": 5, "EDP_Registration": 2},
+		},
+		"invalid limit": {
+			stepLimits:    "Create_Runtime=0",
+			expectedError: true,
+		},
+		"missing limit": {
+			stepLimits:    "Create_Runtime",
+			expectedError: true,
+		},
+		"missing step name": {
+			stepLimits:    "=2",
+			expectedError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			limiter, err := NewStepLimiter(StepConcurrencyConfig{StepLimits: tc.stepLimits})
+
+			// then
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			limits := make(map[string]int, len(limiter.semaphores))
+			for step, semaphore := range limiter.semaphores {
+				limits[step] = cap(semaphore)
+			}
+			assert.Equal(t, tc.expectedLimits, limits)
+		})
+	}
+}
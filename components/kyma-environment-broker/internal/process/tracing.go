@@ -0,0 +1,24 @@
+package process
+
+import (
+	"context"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
+	"go.opencensus.io/trace"
+)
+
+// StartOperationSpan starts the span of the operation processing. The first processing starts the root span of the operation
+// and returns its traceparent which must be stored on the operation, the spans of the next processing (e.g. after the step retry)
+// are children of the stored one and no traceparent is returned. No traceparent is returned either if the span is not sampled.
+func StartOperationSpan(name string, operation internal.Operation, planID string) (context.Context, *trace.Span, string) {
+	ctx, span := tracing.StartSpan(context.Background(), name, operation.TraceParent,
+		trace.StringAttribute("operation.id", operation.ID),
+		trace.StringAttribute("instance.id", operation.InstanceID),
+		trace.StringAttribute("plan.id", planID),
+	)
+	if operation.TraceParent != "" {
+		return ctx, span, ""
+	}
+	return ctx, span, tracing.TraceParent(ctx)
+}
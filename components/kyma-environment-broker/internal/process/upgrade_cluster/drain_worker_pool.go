@@ -0,0 +1,68 @@
+package upgrade_cluster
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+)
+
+// DrainWorkerPoolStep requests the controlled drain of the worker pool: the Provisioner rolls the nodes out with
+// the surge and the unavailability requested by the orchestration instead of the settings of the runtime
+type DrainWorkerPoolStep struct {
+	operationManager *process.UpgradeClusterOperationManager
+}
+
+func NewDrainWorkerPoolStep(os storage.Operations) *DrainWorkerPoolStep {
+	return &DrainWorkerPoolStep{
+		operationManager: process.NewUpgradeClusterOperationManager(os),
+	}
+}
+
+func (s *DrainWorkerPoolStep) Name() string {
+	return "Drain_Worker_Pool"
+}
+
+func (s *DrainWorkerPoolStep) Run(operation internal.UpgradeClusterOperation, log logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
+	if operation.Drain == nil {
+		return operation, 0, nil
+	}
+	// the orchestration validates the settings, the operations created before the validation are checked again
+	if err := operation.Drain.Validate(); err != nil {
+		return s.operationManager.OperationFailed(operation, err.Error(), log)
+	}
+
+	// the input creator is not stored, so the upgrade input is shaped again every time the operation is processed
+	operation.InputCreator = &drainInputCreator{
+		ProvisionerInputCreator: operation.InputCreator,
+		drain:                   *operation.Drain,
+	}
+	log.Infof("worker pool is drained with max surge %d and max unavailable %d", operation.Drain.MaxSurge, operation.Drain.MaxUnavailable)
+
+	return operation, 0, nil
+}
+
+// drainInputCreator sets the drain settings in the upgrade shoot input created by the wrapped creator
+type drainInputCreator struct {
+	internal.ProvisionerInputCreator
+	drain orchestration.DrainParameters
+}
+
+func (c *drainInputCreator) CreateUpgradeShootInput() (gqlschema.UpgradeShootInput, error) {
+	input, err := c.ProvisionerInputCreator.CreateUpgradeShootInput()
+	if err != nil {
+		return input, err
+	}
+	if input.GardenerConfig == nil {
+		input.GardenerConfig = &gqlschema.GardenerUpgradeInput{}
+	}
+	maxSurge, maxUnavailable := c.drain.MaxSurge, c.drain.MaxUnavailable
+	input.GardenerConfig.MaxSurge = &maxSurge
+	input.GardenerConfig.MaxUnavailable = &maxUnavailable
+
+	return input, nil
+}
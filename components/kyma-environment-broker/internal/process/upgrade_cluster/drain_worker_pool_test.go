@@ -0,0 +1,90 @@
+package upgrade_cluster
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	provisionerAutomock "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainWorkerPoolStep_Run(t *testing.T) {
+	t.Run("should pass the drain settings to the Provisioner", func(t *testing.T) {
+		// given
+		log := logrus.New()
+		memoryStorage := storage.NewMemoryStorage()
+
+		operation := fixUpgradeClusterOperationWithInputCreator(t)
+		operation.Drain = &orchestration.DrainParameters{MaxSurge: 2, MaxUnavailable: 1}
+		err := memoryStorage.Operations().InsertUpgradeClusterOperation(operation)
+		require.NoError(t, err)
+
+		provisionerClient := &provisionerAutomock.Client{}
+		provisionerClient.On("UpgradeShoot", fixGlobalAccountID, fixRuntimeID, gqlschema.UpgradeShootInput{
+			GardenerConfig: &gqlschema.GardenerUpgradeInput{
+				KubernetesVersion:   ptr.String(fixKubernetesVersion),
+				MachineImage:        ptr.String(fixMachineImage),
+				MachineImageVersion: ptr.String(fixMachineImageVersion),
+				MaxSurge:            ptr.Integer(2),
+				MaxUnavailable:      ptr.Integer(1),
+			},
+		}).Return(gqlschema.OperationStatus{
+			ID:        ptr.String(fixProvisionerOperationID),
+			RuntimeID: ptr.String(fixRuntimeID),
+		}, nil)
+
+		drainStep := NewDrainWorkerPoolStep(memoryStorage.Operations())
+		upgradeStep := NewUpgradeClusterStep(memoryStorage.Operations(), memoryStorage.RuntimeStates(), provisionerClient, nil)
+
+		// when
+		operation, repeat, err := drainStep.Run(operation, log)
+		require.NoError(t, err)
+		require.Zero(t, repeat)
+		operation, _, err = upgradeStep.Run(operation, log)
+
+		// then
+		require.NoError(t, err)
+		provisionerClient.AssertExpectations(t)
+		assert.Equal(t, fixProvisionerOperationID, operation.ProvisionerOperationID)
+	})
+
+	t.Run("should keep the settings of the runtime without the drain settings", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixUpgradeClusterOperationWithInputCreator(t)
+		step := NewDrainWorkerPoolStep(memoryStorage.Operations())
+
+		// when
+		operation, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		input, err := operation.InputCreator.CreateUpgradeShootInput()
+		require.NoError(t, err)
+		assert.Nil(t, input.GardenerConfig.MaxSurge)
+		assert.Nil(t, input.GardenerConfig.MaxUnavailable)
+	})
+
+	t.Run("should fail the operation with invalid drain settings", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixUpgradeClusterOperationWithInputCreator(t)
+		operation.Drain = &orchestration.DrainParameters{MaxSurge: 0, MaxUnavailable: 0}
+		err := memoryStorage.Operations().InsertUpgradeClusterOperation(operation)
+		require.NoError(t, err)
+		step := NewDrainWorkerPoolStep(memoryStorage.Operations())
+
+		// when
+		operation, _, err = step.Run(operation, logrus.New())
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, orchestration.Failed, string(operation.State))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/input"
@@ -122,8 +123,15 @@ func (s *InitialisationStep) initializeUpgradeShootRequest(operation internal.Up
 		return operation, s.timeSchedule.Retry, nil
 	}
 
+	// the parameters stored with the previous plan schema version are migrated forward before the upgrade uses them
+	parameters, err := broker.DefaultParametersMigrations.Migrate(provisioningOperation.ProvisioningParameters)
+	if err != nil {
+		log.Errorf("while migrating provisioning parameters: %s", err)
+		return s.operationManager.OperationFailed(operation, "unable to migrate provisioning parameters", log)
+	}
+
 	operation, delay := s.operationManager.UpdateOperation(operation, func(op *internal.UpgradeClusterOperation) {
-		op.ProvisioningParameters = provisioningOperation.ProvisioningParameters
+		op.ProvisioningParameters = parameters
 	}, log)
 	if delay != 0 {
 		return operation, delay, nil
@@ -6,10 +6,13 @@ import (
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type Step interface {
@@ -21,8 +24,11 @@ type Manager struct {
 	log              logrus.FieldLogger
 	steps            map[int][]Step
 	operationStorage storage.Operations
+	operationManager *process.UpgradeClusterOperationManager
+	retryClassifier  kebError.RetryClassifier
 
-	publisher event.Publisher
+	publisher   event.Publisher
+	stepLimiter *process.StepLimiter
 }
 
 func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.FieldLogger) *Manager {
@@ -30,6 +36,8 @@ func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.F
 		log:              logger,
 		steps:            make(map[int][]Step, 0),
 		operationStorage: storage,
+		operationManager: process.NewUpgradeClusterOperationManager(storage),
+		retryClassifier:  kebError.IsRetryable,
 		publisher:        pub,
 	}
 }
@@ -45,10 +53,60 @@ func (m *Manager) AddStep(weight int, step Step) {
 	m.steps[weight] = append(m.steps[weight], step)
 }
 
-func (m *Manager) runStep(step Step, operation internal.UpgradeClusterOperation, logger logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
+// SetRetryClassifier replaces the classifier which decides if the step which returned an error is retried,
+// the operation fails immediately when the error is not retryable
+func (m *Manager) SetRetryClassifier(classifier kebError.RetryClassifier) {
+	m.retryClassifier = classifier
+}
+
+// SetStepLimiter sets the limiter capping the number of operations executing a step at the same time
+func (m *Manager) SetStepLimiter(limiter *process.StepLimiter) {
+	m.stepLimiter = limiter
+}
+
+func (m *Manager) saveCurrentStep(operation internal.UpgradeClusterOperation, step Step, log logrus.FieldLogger) (internal.UpgradeClusterOperation, error) {
+	if operation.CurrentStep == step.Name() {
+		return operation, nil
+	}
+	operation.CurrentStep = step.Name()
+	op, err := m.operationStorage.UpdateUpgradeClusterOperation(operation)
+	if err != nil {
+		log.Errorf("Unable to save current step: %s", err)
+		return operation, err
+	}
+	return *op, nil
+}
+
+// traceOperation starts the span of the operation processing and stores the traceparent of the root span on the operation
+func (m *Manager) traceOperation(operation internal.UpgradeClusterOperation, planID string, log logrus.FieldLogger) (context.Context, *trace.Span, internal.UpgradeClusterOperation) {
+	ctx, span, traceParent := process.StartOperationSpan("upgrade_cluster", operation.Operation, planID)
+	if traceParent == "" {
+		return ctx, span, operation
+	}
+
+	traced := operation
+	traced.TraceParent = traceParent
+	op, err := m.operationStorage.UpdateUpgradeClusterOperation(traced)
+	if err != nil {
+		log.Warnf("Unable to save trace parent: %s", err)
+		return ctx, span, operation
+	}
+	return ctx, span, *op
+}
+
+func (m *Manager) runStep(ctx context.Context, step Step, operation internal.UpgradeClusterOperation, logger logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
+	if !m.stepLimiter.Acquire(step.Name()) {
+		logger.Infof("Limit of operations executing the step at the same time reached, step will be repeated in %s", m.stepLimiter.RetryInterval())
+		return operation, m.stepLimiter.RetryInterval(), nil
+	}
+	defer m.stepLimiter.Release(step.Name())
+
+	ctx, span := trace.StartSpan(ctx, step.Name())
+
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.UpgradeClusterStepProcessed{
+	tracing.EndSpan(span, err)
+	m.publisher.Publish(ctx, process.UpgradeClusterStepProcessed{
 		OldOperation: operation,
 		Operation:    processedOperation,
 		StepProcessed: process.StepProcessed{
@@ -83,7 +141,10 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 	}
 
 	var when time.Duration
-	logOperation := m.log.WithFields(logrus.Fields{"operation": operationID, "instanceID": operation.InstanceID})
+	logOperation := process.OperationLogger(m.log, operation.Operation)
+
+	ctx, span, operation := m.traceOperation(operation, operation.ProvisioningParameters.PlanID, logOperation)
+	defer span.End()
 
 	logOperation.Info("Start process operation steps")
 	for _, weightStep := range m.sortWeight() {
@@ -92,7 +153,28 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 			logStep := logOperation.WithField("step", step.Name())
 			logStep.Infof("Start step")
 
-			operation, when, err = m.runStep(step, operation, logStep)
+			operation, err = m.saveCurrentStep(operation, step, logStep)
+			if err != nil {
+				return time.Second, nil
+			}
+
+			operation, when, err = m.runStep(ctx, step, operation, logStep)
+			if err != nil && !operation.IsFinished() && !m.retryClassifier(err) {
+				logStep.Errorf("Process operation failed with non-retryable error: %s", err)
+				failedOperation, repeat, failErr := m.operationManager.OperationFailedWithError(operation, err, logStep)
+				if repeat == 0 {
+					m.publisher.Publish(ctx, process.OperationFailed{
+						StepProcessed: process.StepProcessed{StepName: step.Name(), Error: err},
+						Operation:     failedOperation.Operation,
+						RuntimeID:     failedOperation.RuntimeOperation.RuntimeID,
+					})
+				}
+				return repeat, failErr
+			}
+			if err != nil && when != 0 && !operation.IsFinished() {
+				logStep.Warnf("Process operation attempt failed, it will be repeated in %s: %s", when, err)
+				return when, nil
+			}
 			if err != nil {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
@@ -0,0 +1,84 @@
+package upgrade_cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// ResolveCredentialsStep re-resolves the hyperscaler credentials of the global account. When the secret binding was rotated
+// or replaced, the new target secret is stored in the provisioning parameters and the cluster upgrade passes it to the Provisioner.
+type ResolveCredentialsStep struct {
+	operationManager *process.UpgradeClusterOperationManager
+	operationStorage storage.Operations
+	accountProvider  hyperscaler.AccountProvider
+}
+
+func NewResolveCredentialsStep(os storage.Operations, accountProvider hyperscaler.AccountProvider) *ResolveCredentialsStep {
+	return &ResolveCredentialsStep{
+		operationManager: process.NewUpgradeClusterOperationManager(os),
+		operationStorage: os,
+		accountProvider:  accountProvider,
+	}
+}
+
+func (s *ResolveCredentialsStep) Name() string {
+	return "Resolve_Target_Secret"
+}
+
+func (s *ResolveCredentialsStep) Run(operation internal.UpgradeClusterOperation, log logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
+	// the upgrade was already requested in the Provisioner, the step is re-run while the operation status is checked
+	if operation.ProvisionerOperationID != "" || operation.DryRun {
+		return operation, 0, nil
+	}
+	planID := operation.ProvisioningParameters.PlanID
+	// the shared credentials are assigned to the trial runtimes by usage, resolving them again could move the runtime to another account
+	if broker.IsTrialPlan(planID) {
+		return operation, 0, nil
+	}
+
+	hypType, err := hyperscaler.HyperscalerTypeForPlanID(planID)
+	if err != nil {
+		log.Errorf("skipping the credentials resolution: %s", err)
+		return operation, 0, nil
+	}
+	globalAccountID := operation.ProvisioningParameters.ErsContext.GlobalAccountID
+	credentials, err := s.accountProvider.GardenerCredentials(hypType, globalAccountID)
+	if err != nil {
+		msg := fmt.Sprintf("HAP lookup for credentials of global account ID %s on Hyperscaler %s has failed: %s", globalAccountID, hypType, err)
+		return s.operationManager.RetryOperationWithoutFail(operation, msg, 10*time.Second, time.Minute, log)
+	}
+
+	current := operation.ProvisioningParameters.Parameters.TargetSecret
+	if current != nil && *current == credentials.Name {
+		return operation, 0, nil
+	}
+
+	// the next upgrades read the parameters from the provisioning operation, it must hold the new target secret as well
+	provisioningOperation, err := s.operationStorage.GetProvisioningOperationByInstanceID(operation.InstanceID)
+	if err != nil {
+		log.Errorf("while getting provisioning operation from storage: %s", err)
+		return operation, 10 * time.Second, nil
+	}
+	provisioningOperation.ProvisioningParameters.Parameters.TargetSecret = &credentials.Name
+	if _, err := s.operationStorage.UpdateProvisioningOperation(*provisioningOperation); err != nil {
+		log.Errorf("while updating provisioning operation: %s", err)
+		return operation, 10 * time.Second, nil
+	}
+
+	operation, delay := s.operationManager.UpdateOperation(operation, func(op *internal.UpgradeClusterOperation) {
+		op.ProvisioningParameters.Parameters.TargetSecret = &credentials.Name
+	}, log)
+	if delay != 0 {
+		return operation, delay, nil
+	}
+	log.Infof("Resolved %s as the new target secret for global account ID %s on Hyperscaler %s", credentials.Name, globalAccountID, hypType)
+
+	return operation, 0, nil
+}
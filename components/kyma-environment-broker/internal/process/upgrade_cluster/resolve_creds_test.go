@@ -0,0 +1,85 @@
+package upgrade_cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	hyperscalerMocks "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCredentialsStep_Run(t *testing.T) {
+	for name, tc := range map[string]struct {
+		resolvedSecret  string
+		expectedSecret  string
+		expectedVersion int
+	}{
+		"changed binding":   {resolvedSecret: "gardener-secret-azure-new", expectedSecret: "gardener-secret-azure-new", expectedVersion: 1},
+		"unchanged binding": {resolvedSecret: "gardener-secret-azure", expectedSecret: "gardener-secret-azure", expectedVersion: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			log := logrus.New()
+			memoryStorage := storage.NewMemoryStorage()
+
+			provisioningOperation := fixProvisioningOperation()
+			provisioningOperation.ProvisioningParameters.Parameters.TargetSecret = ptr.String("gardener-secret-azure")
+			err := memoryStorage.Operations().InsertProvisioningOperation(provisioningOperation)
+			require.NoError(t, err)
+
+			operation := fixUpgradeClusterOperation()
+			operation.ProvisionerOperationID = ""
+			operation.ProvisioningParameters = provisioningOperation.ProvisioningParameters
+			err = memoryStorage.Operations().InsertUpgradeClusterOperation(operation)
+			require.NoError(t, err)
+
+			accountProvider := &hyperscalerMocks.AccountProvider{}
+			accountProvider.On("GardenerCredentials", hyperscaler.Azure, fixGlobalAccountID).Return(hyperscaler.Credentials{
+				Name:            tc.resolvedSecret,
+				HyperscalerType: hyperscaler.Azure,
+			}, nil)
+
+			step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProvider)
+
+			// when
+			operation, repeat, err := step.Run(operation, log)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, time.Duration(0), repeat)
+			assert.Equal(t, tc.expectedSecret, *operation.ProvisioningParameters.Parameters.TargetSecret)
+
+			storedUpgrade, err := memoryStorage.Operations().GetUpgradeClusterOperationByID(operation.Operation.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSecret, *storedUpgrade.ProvisioningParameters.Parameters.TargetSecret)
+			assert.Equal(t, tc.expectedVersion, storedUpgrade.Version)
+			storedProvisioning, err := memoryStorage.Operations().GetProvisioningOperationByInstanceID(fixInstanceID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSecret, *storedProvisioning.ProvisioningParameters.Parameters.TargetSecret)
+			accountProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveCredentialsStep_RunWhenUpgradeRequested(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixUpgradeClusterOperation()
+	accountProvider := &hyperscalerMocks.AccountProvider{}
+
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProvider)
+
+	// when
+	_, repeat, err := step.Run(operation, logrus.New())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), repeat)
+	accountProvider.AssertNotCalled(t, "GardenerCredentials", mock.Anything, mock.Anything)
+}
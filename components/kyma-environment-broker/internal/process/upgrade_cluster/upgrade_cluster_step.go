@@ -72,8 +72,12 @@ func (s *UpgradeClusterStep) Run(operation internal.UpgradeClusterOperation, log
 
 	var provisionerResponse gqlschema.OperationStatus
 	if operation.ProvisionerOperationID == "" {
+		dumpedRequest, err := provisioner.DumpRequest(s.provisionerClient, input)
+		if err != nil {
+			log.Warnf("unable to dump provisioner request: %s", err)
+		}
 		// trigger upgradeRuntime mutation
-		provisionerResponse, err = s.provisionerClient.UpgradeShoot(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.RuntimeOperation.RuntimeID, input)
+		provisionerResponse, err = provisioner.ForTrace(provisioner.ForOperation(s.provisionerClient, operation.CorrelationID), operation.TraceParent).UpgradeShoot(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.RuntimeOperation.RuntimeID, input)
 		if err != nil {
 			log.Errorf("call to provisioner failed: %s", err)
 			return operation, s.timeSchedule.Retry, nil
@@ -82,6 +86,7 @@ func (s *UpgradeClusterStep) Run(operation internal.UpgradeClusterOperation, log
 		repeat := time.Duration(0)
 		operation, repeat = s.operationManager.UpdateOperation(operation, func(op *internal.UpgradeClusterOperation) {
 			op.ProvisionerOperationID = *provisionerResponse.ID
+			op.ProvisionerRequest = dumpedRequest
 			op.Description = "cluster upgrade in progress"
 		}, log)
 		if repeat != 0 {
@@ -42,6 +42,23 @@ func (om *UpgradeClusterOperationManager) OperationFailed(operation internal.Upg
 	return updatedOperation, 0, errors.New(description)
 }
 
+// OperationFailedWithError marks the operation as failed with the message of the error and stores the failure reason
+// of the FailureError on the operation, it only repeats the operation if there is a storage error
+func (om *UpgradeClusterOperationManager) OperationFailedWithError(operation internal.UpgradeClusterOperation, err error, log logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
+	reason := internal.FailureReasonOf(err)
+	updatedOperation, repeat := om.UpdateOperation(operation, func(operation *internal.UpgradeClusterOperation) {
+		operation.State = orchestration.Failed
+		operation.Description = err.Error()
+		operation.FailureReason = reason
+	}, log)
+	// repeat in case of storage error
+	if repeat != 0 {
+		return updatedOperation, repeat, nil
+	}
+
+	return updatedOperation, 0, err
+}
+
 // OperationSucceeded marks the operation as succeeded and only repeats it if there is a storage error
 func (om *UpgradeClusterOperationManager) OperationCanceled(operation internal.UpgradeClusterOperation, description string, log logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
 	updatedOperation, repeat := om.update(operation, orchestration.Canceled, description, log)
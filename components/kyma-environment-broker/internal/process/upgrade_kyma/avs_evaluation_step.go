@@ -0,0 +1,91 @@
+package upgrade_kyma
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// AvsEvaluationReconcileStep brings the internal and the external AVS evaluations of the instance
+// back to the desired state, the evaluations may have drifted since the provisioning. The lifecycle data is kept
+// on the provisioning operation, which is the one read by the deprovisioning to delete the evaluations.
+type AvsEvaluationReconcileStep struct {
+	operationManager  *process.UpgradeKymaOperationManager
+	provisionManager  *process.ProvisionOperationManager
+	operationStorage  storage.Operations
+	instanceStorage   storage.Instances
+	delegator         *avs.Delegator
+	internalAssistant *avs.InternalEvalAssistant
+	externalAssistant *avs.ExternalEvalAssistant
+}
+
+func NewAvsEvaluationReconcileStep(os storage.Operations, is storage.Instances, delegator *avs.Delegator,
+	internalAssistant *avs.InternalEvalAssistant, externalAssistant *avs.ExternalEvalAssistant) *AvsEvaluationReconcileStep {
+	return &AvsEvaluationReconcileStep{
+		operationManager:  process.NewUpgradeKymaOperationManager(os),
+		provisionManager:  process.NewProvisionOperationManager(os),
+		operationStorage:  os,
+		instanceStorage:   is,
+		delegator:         delegator,
+		internalAssistant: internalAssistant,
+		externalAssistant: externalAssistant,
+	}
+}
+
+func (s *AvsEvaluationReconcileStep) Name() string {
+	return "Upgrade_Kyma_Avs_Evaluation_Reconcile"
+}
+
+func (s *AvsEvaluationReconcileStep) Run(operation internal.UpgradeKymaOperation, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	provisioningOperation, err := s.operationStorage.GetProvisioningOperationByInstanceID(operation.InstanceID)
+	if err != nil {
+		log.Errorf("unable to get provisioning operation: %s", err)
+		return operation, 5 * time.Second, nil
+	}
+	lifecycleData := provisioningOperation.Avs
+
+	err = s.delegator.ReconcileEvaluation(log, operation.Operation, &lifecycleData, s.internalAssistant, "")
+	if err != nil {
+		log.Errorf("unable to reconcile internal evaluation: %s", err)
+		return s.operationManager.RetryOperationWithoutFail(operation, "unable to reconcile internal AVS evaluation", 30*time.Second, 10*time.Minute, log)
+	}
+
+	instance, err := s.instanceStorage.GetByID(operation.InstanceID)
+	if err != nil {
+		log.Errorf("unable to get instance: %s", err)
+		return operation, 5 * time.Second, nil
+	}
+	err = s.delegator.ReconcileEvaluation(log, operation.Operation, &lifecycleData, s.externalAssistant, instance.DashboardURL)
+	if err != nil {
+		log.Errorf("unable to reconcile external evaluation: %s", err)
+		return s.operationManager.RetryOperationWithoutFail(operation, "unable to reconcile external AVS evaluation", 30*time.Second, 10*time.Minute, log)
+	}
+
+	if lifecycleData != provisioningOperation.Avs {
+		_, delay := s.provisionManager.UpdateOperation(*provisioningOperation, func(op *internal.ProvisioningOperation) {
+			op.Avs = lifecycleData
+		}, log)
+		if delay != 0 {
+			return operation, delay, nil
+		}
+	}
+
+	op, delay := s.operationManager.UpdateOperation(operation, func(op *internal.UpgradeKymaOperation) {
+		op.Avs = lifecycleData
+	}, log)
+	if delay != 0 {
+		return operation, delay, nil
+	}
+	operation = op
+
+	// the evaluation could be recreated, avs-bridge must point to the current one
+	if s.internalAssistant.IsAlreadyCreated(operation.Avs) {
+		s.internalAssistant.AppendOverrides(operation.InputCreator, operation.Avs.AvsEvaluationInternalId, operation.ProvisioningParameters)
+	}
+
+	return operation, 0, nil
+}
@@ -0,0 +1,159 @@
+package upgrade_kyma
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvsEvaluationReconcileStep_Run(t *testing.T) {
+	t.Run("should correct drifted evaluations", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		server, client, cfg := fixAvsServerAndClient(t)
+		avsData := createMonitors(t, client, avs.StatusActive, avs.StatusActive)
+		avsData.AvsInternalEvaluationStatus = internal.AvsEvaluationStatus{Current: avs.StatusActive}
+		avsData.AvsExternalEvaluationStatus = internal.AvsEvaluationStatus{Current: avs.StatusActive}
+
+		// drift: the tags are removed and the internal check is disabled
+		server.Evaluations.BasicEvals[avsData.AvsEvaluationInternalId].Tags = nil
+		server.Evaluations.BasicEvals[avsData.AvsEvaluationInternalId].Status = avs.StatusInactive
+		server.Evaluations.BasicEvals[avsData.AVSEvaluationExternalId].Tags = nil
+
+		inputCreator := fixAvsInputCreator(avsData.AvsEvaluationInternalId)
+		operation := fixAvsUpgradeKymaOperation(t, memoryStorage, avsData, inputCreator)
+		step := fixAvsEvaluationReconcileStep(memoryStorage, client, cfg)
+
+		// when
+		operation, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.Equal(t, avsData.AvsEvaluationInternalId, operation.Avs.AvsEvaluationInternalId)
+		assert.Equal(t, avsData.AVSEvaluationExternalId, operation.Avs.AVSEvaluationExternalId)
+
+		internalEval := server.Evaluations.BasicEvals[avsData.AvsEvaluationInternalId]
+		assert.Equal(t, avs.StatusActive, internalEval.Status)
+		assert.ElementsMatch(t, cfg.InternalTesterTags, internalEval.Tags)
+		externalEval := server.Evaluations.BasicEvals[avsData.AVSEvaluationExternalId]
+		assert.Equal(t, avs.StatusActive, externalEval.Status)
+		assert.ElementsMatch(t, cfg.ExternalTesterTags, externalEval.Tags)
+		inputCreator.AssertExpectations(t)
+
+		// when reconciled again nothing changes
+		_, repeat, err = step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.Len(t, server.Evaluations.BasicEvals[avsData.AvsEvaluationInternalId].Tags, len(cfg.InternalTesterTags))
+		assert.Len(t, server.Evaluations.BasicEvals[avsData.AVSEvaluationExternalId].Tags, len(cfg.ExternalTesterTags))
+	})
+
+	t.Run("should recreate missing evaluations", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		server, client, cfg := fixAvsServerAndClient(t)
+		avsData := createMonitors(t, client, avs.StatusActive, avs.StatusActive)
+		require.NoError(t, client.DeleteEvaluation(avsData.AvsEvaluationInternalId))
+
+		inputCreator := fixAvsInputCreator(0)
+		operation := fixAvsUpgradeKymaOperation(t, memoryStorage, avsData, inputCreator)
+		step := fixAvsEvaluationReconcileStep(memoryStorage, client, cfg)
+
+		// when
+		operation, repeat, err := step.Run(operation, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, repeat)
+		assert.NotEqual(t, avsData.AvsEvaluationInternalId, operation.Avs.AvsEvaluationInternalId)
+		assert.Equal(t, avsData.AVSEvaluationExternalId, operation.Avs.AVSEvaluationExternalId)
+		recreated, exists := server.Evaluations.BasicEvals[operation.Avs.AvsEvaluationInternalId]
+		require.True(t, exists)
+		assert.ElementsMatch(t, cfg.InternalTesterTags, recreated.Tags)
+
+		storedOperation, err := memoryStorage.Operations().GetUpgradeKymaOperationByID(operation.Operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation.Avs.AvsEvaluationInternalId, storedOperation.Avs.AvsEvaluationInternalId)
+		storedProvisioningOperation, err := memoryStorage.Operations().GetProvisioningOperationByInstanceID(fixInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, operation.Avs.AvsEvaluationInternalId, storedProvisioningOperation.Avs.AvsEvaluationInternalId)
+		inputCreator.AssertCalled(t, "AppendOverrides", avs.ComponentName,
+			mock.MatchedBy(evaluationIdOverride(operation.Avs.AvsEvaluationInternalId)))
+	})
+}
+
+func fixAvsServerAndClient(t *testing.T) (*avs.MockAvsServer, *avs.Client, avs.Config) {
+	server := avs.NewMockAvsServer(t)
+	mockServer := avs.FixMockAvsServer(server)
+	cfg := avs.Config{
+		OauthTokenEndpoint: fmt.Sprintf("%s/oauth/token", mockServer.URL),
+		ApiEndpoint:        fmt.Sprintf("%s/api/v2/evaluationmetadata", mockServer.URL),
+		InternalTesterTags: []*avs.Tag{{Content: "internal", TagClassId: 1}},
+		ExternalTesterTags: []*avs.Tag{{Content: "external", TagClassId: 2}},
+	}
+	client, err := avs.NewClient(context.TODO(), cfg, logrus.New())
+	require.NoError(t, err)
+
+	return server, client, cfg
+}
+
+func fixAvsEvaluationReconcileStep(memoryStorage storage.BrokerStorage, client *avs.Client, cfg avs.Config) *AvsEvaluationReconcileStep {
+	delegator := avs.NewDelegator(client, cfg, memoryStorage.Operations())
+	return NewAvsEvaluationReconcileStep(memoryStorage.Operations(), memoryStorage.Instances(), delegator,
+		avs.NewInternalEvalAssistant(cfg), avs.NewExternalEvalAssistant(cfg))
+}
+
+func fixAvsUpgradeKymaOperation(t *testing.T, memoryStorage storage.BrokerStorage, avsData internal.AvsLifecycleData, inputCreator internal.ProvisionerInputCreator) internal.UpgradeKymaOperation {
+	err := memoryStorage.Instances().Insert(fixInstanceRuntimeStatus())
+	require.NoError(t, err)
+
+	provisioningOperation := fixProvisioningOperation()
+	provisioningOperation.Avs = avsData
+	err = memoryStorage.Operations().InsertProvisioningOperation(provisioningOperation)
+	require.NoError(t, err)
+
+	operation := fixture.FixUpgradeKymaOperation(fixUpgradeOperationID, fixInstanceID)
+	operation.ProvisioningParameters = fixProvisioningParameters()
+	err = memoryStorage.Operations().InsertUpgradeKymaOperation(operation)
+	require.NoError(t, err)
+	operation.InputCreator = inputCreator
+
+	return operation
+}
+
+func fixAvsInputCreator(evaluationID int64) *automock.ProvisionerInputCreator {
+	inputCreator := &automock.ProvisionerInputCreator{}
+	var matcher interface{} = mock.Anything
+	if evaluationID != 0 {
+		matcher = mock.MatchedBy(evaluationIdOverride(evaluationID))
+	}
+	inputCreator.On("AppendOverrides", avs.ComponentName, matcher).Return(nil)
+
+	return inputCreator
+}
+
+func evaluationIdOverride(evaluationID int64) func([]*gqlschema.ConfigEntryInput) bool {
+	return func(overrides []*gqlschema.ConfigEntryInput) bool {
+		for _, o := range overrides {
+			if o.Key == avs.EvaluationIdKey {
+				return o.Value == strconv.FormatInt(evaluationID, 10)
+			}
+		}
+		return false
+	}
+}
@@ -12,6 +12,7 @@ import (
 	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/input"
@@ -125,7 +126,12 @@ func (s *InitialisationStep) Run(operation internal.UpgradeKymaOperation, log lo
 		log.Errorf("while getting provisioning operation from storage")
 		return operation, s.timeSchedule.Retry, nil
 	}
-	operation.ProvisioningParameters = provisioningOperation.ProvisioningParameters
+	// the parameters stored with the previous plan schema version are migrated forward before the upgrade uses them
+	operation.ProvisioningParameters, err = broker.DefaultParametersMigrations.Migrate(provisioningOperation.ProvisioningParameters)
+	if err != nil {
+		log.Errorf("while migrating provisioning parameters: %s", err)
+		return s.operationManager.OperationFailed(operation, "unable to migrate provisioning parameters", log)
+	}
 
 	if operation.ProvisionerOperationID == "" {
 		log.Info("provisioner operation ID is empty, initialize upgrade runtime input request")
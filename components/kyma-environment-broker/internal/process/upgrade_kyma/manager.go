@@ -6,10 +6,13 @@ import (
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type Step interface {
@@ -17,19 +20,43 @@ type Step interface {
 	Run(operation internal.UpgradeKymaOperation, logger logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error)
 }
 
+// Flags of the instances which enable or disable the toggled steps of the upgrade for the instance
+const (
+	DeprovisionAzureEventHubsFlag = "deprovision-azure-event-hubs"
+	ReconcileAvsEvaluationsFlag   = "reconcile-avs-evaluations"
+)
+
+// StepFlags lists the flags which can be set for the instances
+var StepFlags = []string{DeprovisionAzureEventHubsFlag, ReconcileAvsEvaluationsFlag}
+
+// StepToggle enables or disables the step for the instances which have the flag set,
+// the step of the instance without the flag is enabled by default if EnabledByDefault is true
+type StepToggle struct {
+	Flag             string
+	EnabledByDefault bool
+}
+
 type Manager struct {
 	log              logrus.FieldLogger
 	steps            map[int][]Step
+	toggles          map[string]StepToggle
 	operationStorage storage.Operations
+	operationManager *process.UpgradeKymaOperationManager
+	retryClassifier  kebError.RetryClassifier
+	instanceFlags    storage.InstanceFlags
 
-	publisher event.Publisher
+	publisher   event.Publisher
+	stepLimiter *process.StepLimiter
 }
 
 func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.FieldLogger) *Manager {
 	return &Manager{
 		log:              logger,
 		steps:            make(map[int][]Step, 0),
+		toggles:          make(map[string]StepToggle),
 		operationStorage: storage,
+		operationManager: process.NewUpgradeKymaOperationManager(storage),
+		retryClassifier:  kebError.IsRetryable,
 		publisher:        pub,
 	}
 }
@@ -45,10 +72,71 @@ func (m *Manager) AddStep(weight int, step Step) {
 	m.steps[weight] = append(m.steps[weight], step)
 }
 
-func (m *Manager) runStep(step Step, operation internal.UpgradeKymaOperation, logger logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+// AddToggledStep adds the step which can be enabled or disabled for the instance by the flag of the instance
+func (m *Manager) AddToggledStep(weight int, step Step, toggle StepToggle) {
+	m.AddStep(weight, step)
+	m.toggles[step.Name()] = toggle
+}
+
+// SetInstanceFlags sets the storage of the instance flags which toggle the steps, without it the toggled steps use their defaults
+func (m *Manager) SetInstanceFlags(flags storage.InstanceFlags) {
+	m.instanceFlags = flags
+}
+
+// SetRetryClassifier replaces the classifier which decides if the step which returned an error is retried,
+// the operation fails immediately when the error is not retryable
+func (m *Manager) SetRetryClassifier(classifier kebError.RetryClassifier) {
+	m.retryClassifier = classifier
+}
+
+// SetStepLimiter sets the limiter capping the number of operations executing a step at the same time
+func (m *Manager) SetStepLimiter(limiter *process.StepLimiter) {
+	m.stepLimiter = limiter
+}
+
+func (m *Manager) saveCurrentStep(operation internal.UpgradeKymaOperation, step Step, log logrus.FieldLogger) (internal.UpgradeKymaOperation, error) {
+	if operation.CurrentStep == step.Name() {
+		return operation, nil
+	}
+	operation.CurrentStep = step.Name()
+	op, err := m.operationStorage.UpdateUpgradeKymaOperation(operation)
+	if err != nil {
+		log.Errorf("Unable to save current step: %s", err)
+		return operation, err
+	}
+	return *op, nil
+}
+
+// traceOperation starts the span of the operation processing and stores the traceparent of the root span on the operation
+func (m *Manager) traceOperation(operation internal.UpgradeKymaOperation, planID string, log logrus.FieldLogger) (context.Context, *trace.Span, internal.UpgradeKymaOperation) {
+	ctx, span, traceParent := process.StartOperationSpan("upgrade_kyma", operation.Operation, planID)
+	if traceParent == "" {
+		return ctx, span, operation
+	}
+
+	traced := operation
+	traced.TraceParent = traceParent
+	op, err := m.operationStorage.UpdateUpgradeKymaOperation(traced)
+	if err != nil {
+		log.Warnf("Unable to save trace parent: %s", err)
+		return ctx, span, operation
+	}
+	return ctx, span, *op
+}
+
+func (m *Manager) runStep(ctx context.Context, step Step, operation internal.UpgradeKymaOperation, logger logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	if !m.stepLimiter.Acquire(step.Name()) {
+		logger.Infof("Limit of operations executing the step at the same time reached, step will be repeated in %s", m.stepLimiter.RetryInterval())
+		return operation, m.stepLimiter.RetryInterval(), nil
+	}
+	defer m.stepLimiter.Release(step.Name())
+
+	ctx, span := trace.StartSpan(ctx, step.Name())
+
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.UpgradeKymaStepProcessed{
+	tracing.EndSpan(span, err)
+	m.publisher.Publish(ctx, process.UpgradeKymaStepProcessed{
 		OldOperation: operation,
 		Operation:    processedOperation,
 		StepProcessed: process.StepProcessed{
@@ -73,16 +161,50 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 	}
 
 	var when time.Duration
-	logOperation := m.log.WithFields(logrus.Fields{"operation": operationID, "instanceID": operation.InstanceID})
+	logOperation := process.OperationLogger(m.log, operation.Operation)
+
+	flags, err := m.flagsOf(operation.InstanceID)
+	if err != nil {
+		logOperation.Errorf("Cannot fetch instance flags from storage: %s", err)
+		return 3 * time.Second, nil
+	}
+
+	ctx, span, operation := m.traceOperation(operation, operation.ProvisioningParameters.PlanID, logOperation)
+	defer span.End()
 
 	logOperation.Info("Start process operation steps")
 	for _, weightStep := range m.sortWeight() {
 		steps := m.steps[weightStep]
 		for _, step := range steps {
 			logStep := logOperation.WithField("step", step.Name())
+			if !m.enabled(step, flags) {
+				logStep.Info("Step is disabled for the instance, skipping")
+				continue
+			}
 			logStep.Infof("Start step")
 
-			operation, when, err = m.runStep(step, operation, logStep)
+			operation, err = m.saveCurrentStep(operation, step, logStep)
+			if err != nil {
+				return time.Second, nil
+			}
+
+			operation, when, err = m.runStep(ctx, step, operation, logStep)
+			if err != nil && !operation.IsFinished() && !m.retryClassifier(err) {
+				logStep.Errorf("Process operation failed with non-retryable error: %s", err)
+				failedOperation, repeat, failErr := m.operationManager.OperationFailedWithError(operation, err, logStep)
+				if repeat == 0 {
+					m.publisher.Publish(ctx, process.OperationFailed{
+						StepProcessed: process.StepProcessed{StepName: step.Name(), Error: err},
+						Operation:     failedOperation.Operation,
+						RuntimeID:     failedOperation.RuntimeOperation.RuntimeID,
+					})
+				}
+				return repeat, failErr
+			}
+			if err != nil && when != 0 && !operation.IsFinished() {
+				logStep.Warnf("Process operation attempt failed, it will be repeated in %s: %s", when, err)
+				return when, nil
+			}
 			if err != nil {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
@@ -121,6 +243,24 @@ func (m Manager) Reschedule(operationID string, maintenanceWindowBegin, maintena
 	return err
 }
 
+func (m *Manager) flagsOf(instanceID string) (map[string]bool, error) {
+	if len(m.toggles) == 0 || m.instanceFlags == nil {
+		return map[string]bool{}, nil
+	}
+	return m.instanceFlags.GetByInstanceID(instanceID)
+}
+
+func (m *Manager) enabled(step Step, flags map[string]bool) bool {
+	toggle, found := m.toggles[step.Name()]
+	if !found {
+		return true
+	}
+	if enabled, set := flags[toggle.Flag]; set {
+		return enabled
+	}
+	return toggle.EnabledByDefault
+}
+
 func (m *Manager) sortWeight() []int {
 	var weight []int
 	for w := range m.steps {
@@ -98,6 +98,53 @@ func TestManager_Execute(t *testing.T) {
 	}
 }
 
+func TestManager_ExecuteToggledSteps(t *testing.T) {
+	for name, tc := range map[string]struct {
+		flags        map[string]bool
+		expectedDesc string
+	}{
+		"instance without flags uses the defaults": {
+			expectedDesc: "init opt-out final",
+		},
+		"flagged instance includes the step disabled by default": {
+			flags:        map[string]bool{"opt-in": true},
+			expectedDesc: "init opt-in opt-out final",
+		},
+		"flagged instance skips the step enabled by default": {
+			flags:        map[string]bool{"opt-out": false},
+			expectedDesc: "init final",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			memoryStorage := storage.NewMemoryStorage()
+			operations := memoryStorage.Operations()
+			operation := fixOperation(operationIDSuccess)
+			err := operations.InsertUpgradeKymaOperation(operation)
+			assert.NoError(t, err)
+			err = memoryStorage.InstanceFlags().Set(operation.InstanceID, tc.flags)
+			assert.NoError(t, err)
+
+			manager := NewManager(operations, event.NewPubSub(logrus.New()), logrus.New())
+			manager.SetInstanceFlags(memoryStorage.InstanceFlags())
+			manager.InitStep(&testStep{t: t, name: "init", storage: operations})
+			manager.AddToggledStep(1, &testStep{t: t, name: "opt-in", storage: operations}, StepToggle{Flag: "opt-in"})
+			manager.AddToggledStep(1, &testStep{t: t, name: "opt-out", storage: operations}, StepToggle{Flag: "opt-out", EnabledByDefault: true})
+			manager.AddStep(2, &testStep{t: t, name: "final", storage: operations})
+
+			// when
+			repeat, err := manager.Execute(operationIDSuccess)
+
+			// then
+			assert.NoError(t, err)
+			assert.Zero(t, repeat)
+			stored, err := operations.GetOperationByID(operationIDSuccess)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDesc, strings.Trim(stored.Description, " "))
+		})
+	}
+}
+
 func fixOperation(ID string) internal.UpgradeKymaOperation {
 	upgradeOperation := fixture.FixUpgradeKymaOperation(ID, "fea2c1a1-139d-43f6-910a-a618828a79d5")
 	upgradeOperation.State = domain.InProgress
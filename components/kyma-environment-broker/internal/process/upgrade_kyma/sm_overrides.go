@@ -0,0 +1,56 @@
+package upgrade_kyma
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/provisioning"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+)
+
+// ServiceManagerOverridesStep sets the Service Manager credentials of the instance
+// as the overrides of the Service Manager proxy, the same way as during provisioning
+type ServiceManagerOverridesStep struct {
+	operationManager *process.UpgradeKymaOperationManager
+}
+
+func NewServiceManagerOverridesStep(os storage.Operations) *ServiceManagerOverridesStep {
+	return &ServiceManagerOverridesStep{
+		operationManager: process.NewUpgradeKymaOperationManager(os),
+	}
+}
+
+func (s *ServiceManagerOverridesStep) Name() string {
+	return "ServiceManagerOverrides"
+}
+
+func (s *ServiceManagerOverridesStep) Run(operation internal.UpgradeKymaOperation, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	creds, err := operation.ProvideServiceManagerCredentials(log)
+	if err != nil {
+		log.Errorf("unable to obtain SM credentials: %s", err)
+		return s.operationManager.OperationFailed(operation, err.Error(), log)
+	}
+
+	smOverrides := []*gqlschema.ConfigEntryInput{
+		{
+			Key:   "config.sm.url",
+			Value: creds.URL,
+		},
+		{
+			Key:   "sm.user",
+			Value: creds.Username,
+		},
+		{
+			Key:    "sm.password",
+			Value:  creds.Password,
+			Secret: ptr.Bool(true),
+		},
+	}
+	operation.InputCreator.AppendOverrides(provisioning.ServiceManagerComponentName, smOverrides)
+	return operation, 0, nil
+}
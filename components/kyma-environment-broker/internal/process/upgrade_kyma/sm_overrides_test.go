@@ -0,0 +1,45 @@
+package upgrade_kyma
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceManagerOverridesStep_Run(t *testing.T) {
+	// given
+	inputCreatorMock := &automock.ProvisionerInputCreator{}
+	inputCreatorMock.On("AppendOverrides", "service-manager-proxy", []*gqlschema.ConfigEntryInput{
+		{Key: "config.sm.url", Value: "over-url"},
+		{Key: "sm.user", Value: "over-user"},
+		{Key: "sm.password", Value: "over-pass", Secret: ptr.Bool(true)},
+	}).Return(nil).Once()
+
+	operation := internal.UpgradeKymaOperation{
+		InputCreator: inputCreatorMock,
+		SMClientFactory: servicemanager.NewClientFactory(servicemanager.Config{
+			OverrideMode: servicemanager.SMOverrideModeAlways,
+			URL:          "over-url",
+			Username:     "over-user",
+			Password:     "over-pass",
+		}),
+	}
+	step := NewServiceManagerOverridesStep(storage.NewMemoryStorage().Operations())
+
+	// when
+	_, retry, err := step.Run(operation, logger.NewLogDummy())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, retry)
+	inputCreatorMock.AssertExpectations(t)
+}
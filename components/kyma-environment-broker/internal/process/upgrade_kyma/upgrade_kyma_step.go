@@ -72,8 +72,12 @@ func (s *UpgradeKymaStep) Run(operation internal.UpgradeKymaOperation, log logru
 
 	var provisionerResponse gqlschema.OperationStatus
 	if operation.ProvisionerOperationID == "" {
+		dumpedRequest, err := provisioner.DumpRequest(s.provisionerClient, requestInput)
+		if err != nil {
+			log.Warnf("unable to dump provisioner request: %s", err)
+		}
 		// trigger upgradeRuntime mutation
-		provisionerResponse, err := s.provisionerClient.UpgradeRuntime(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.RuntimeOperation.RuntimeID, requestInput)
+		provisionerResponse, err := provisioner.ForTrace(provisioner.ForOperation(s.provisionerClient, operation.CorrelationID), operation.TraceParent).UpgradeRuntime(operation.ProvisioningParameters.ErsContext.GlobalAccountID, operation.RuntimeOperation.RuntimeID, requestInput)
 		if err != nil {
 			log.Errorf("call to provisioner failed: %s", err)
 			return operation, s.timeSchedule.Retry, nil
@@ -81,6 +85,7 @@ func (s *UpgradeKymaStep) Run(operation internal.UpgradeKymaOperation, log logru
 		repeat := time.Duration(0)
 		operation, repeat = s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
 			operation.ProvisionerOperationID = *provisionerResponse.ID
+			operation.ProvisionerRequest = dumpedRequest
 			operation.Description = "kyma upgrade in progress"
 		}, log)
 		if repeat != 0 {
@@ -60,6 +60,7 @@ func (p *GcpInput) ApplyParameters(input *gqlschema.ClusterConfigInput, pp inter
 	}
 
 	updateSlice(&input.GardenerConfig.ProviderSpecificConfig.GcpConfig.Zones, pp.Parameters.Zones)
+	input.GardenerConfig.ProviderSpecificConfig.GcpConfig.EncryptionKey = pp.Parameters.EncryptionKey
 }
 
 func (p *GcpInput) Profile() gqlschema.KymaProfile {
@@ -73,3 +73,20 @@ func TestGcpTrialInput_ApplyParametersWithRegion(t *testing.T) {
 		assert.Equal(t, "europe-west4", input.GardenerConfig.Region)
 	})
 }
+
+func TestGcpInput_ApplyParametersWithEncryptionKey(t *testing.T) {
+	// given
+	svc := GcpInput{}
+	input := svc.Defaults()
+	key := "projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks"
+
+	// when
+	svc.ApplyParameters(input, internal.ProvisioningParameters{
+		Parameters: internal.ProvisioningParametersDTO{
+			EncryptionKey: &key,
+		},
+	})
+
+	// then
+	assert.Equal(t, &key, input.GardenerConfig.ProviderSpecificConfig.GcpConfig.EncryptionKey)
+}
@@ -47,6 +47,10 @@ func (p *OpenStackInput) ApplyParameters(input *gqlschema.ClusterConfigInput, pp
 	if len(pp.Parameters.Zones) > 0 {
 		input.GardenerConfig.ProviderSpecificConfig.OpenStackConfig.Zones = pp.Parameters.Zones
 	}
+
+	if pp.Parameters.Ingress != nil && pp.Parameters.Ingress.LoadBalancerSKU != nil {
+		input.GardenerConfig.ProviderSpecificConfig.OpenStackConfig.LoadBalancerProvider = *pp.Parameters.Ingress.LoadBalancerSKU
+	}
 }
 
 func (p *OpenStackInput) Profile() gqlschema.KymaProfile {
@@ -75,6 +75,27 @@ func (_m *Client) ReconnectRuntimeAgent(accountID string, runtimeID string) (str
 	return r0, r1
 }
 
+// RevokeRuntimeCredentials provides a mock function with given fields: accountID, runtimeID
+func (_m *Client) RevokeRuntimeCredentials(accountID string, runtimeID string) (string, error) {
+	ret := _m.Called(accountID, runtimeID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(accountID, runtimeID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(accountID, runtimeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RuntimeOperationStatus provides a mock function with given fields: accountID, operationID
 func (_m *Client) RuntimeOperationStatus(accountID string, operationID string) (gqlschema.OperationStatus, error) {
 	ret := _m.Called(accountID, operationID)
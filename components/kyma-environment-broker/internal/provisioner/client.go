@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/tracing"
 
 	gcli "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/third_party/machinebox/graphql"
 	schema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -27,6 +29,7 @@ type Client interface {
 	UpgradeRuntime(accountID, runtimeID string, config schema.UpgradeRuntimeInput) (schema.OperationStatus, error)
 	UpgradeShoot(accountID, runtimeID string, config schema.UpgradeShootInput) (schema.OperationStatus, error)
 	ReconnectRuntimeAgent(accountID, runtimeID string) (string, error)
+	RevokeRuntimeCredentials(accountID, runtimeID string) (string, error)
 	RuntimeOperationStatus(accountID, operationID string) (schema.OperationStatus, error)
 	RuntimeStatus(accountID, runtimeID string) (schema.RuntimeStatus, error)
 }
@@ -35,6 +38,39 @@ type client struct {
 	graphQLClient *gcli.Client
 	queryProvider queryProvider
 	graphqlizer   Graphqlizer
+	correlationID string
+	traceParent   string
+	dumpRequests  bool
+}
+
+// correlationIDSetter is implemented by clients which are able to pass the correlation ID to the Provisioner
+type correlationIDSetter interface {
+	WithCorrelationID(correlationID string) Client
+}
+
+// ForOperation returns the client which sends the given correlation ID with every request.
+// Clients which do not support it (e.g. fakes used in tests) are returned unchanged.
+func ForOperation(c Client, correlationID string) Client {
+	setter, ok := c.(correlationIDSetter)
+	if !ok || correlationID == "" {
+		return c
+	}
+	return setter.WithCorrelationID(correlationID)
+}
+
+// traceParentSetter is implemented by clients which are able to trace the requests as children of the operation span
+type traceParentSetter interface {
+	WithTraceParent(traceParent string) Client
+}
+
+// ForTrace returns the client which traces the requests as children of the span with the given W3C traceparent.
+// Clients which do not support it (e.g. fakes used in tests) are returned unchanged.
+func ForTrace(c Client, traceParent string) Client {
+	setter, ok := c.(traceParentSetter)
+	if !ok || traceParent == "" {
+		return c
+	}
+	return setter.WithTraceParent(traceParent)
 }
 
 func NewProvisionerClient(endpoint string, queryDumping bool) Client {
@@ -49,9 +85,29 @@ func NewProvisionerClient(endpoint string, queryDumping bool) Client {
 		graphQLClient: graphQlClient,
 		queryProvider: queryProvider{},
 		graphqlizer:   Graphqlizer{},
+		dumpRequests:  queryDumping,
 	}
 }
 
+// DumpsRequests returns true if the client dumps the requests sent to the Provisioner
+func (c *client) DumpsRequests() bool {
+	return c.dumpRequests
+}
+
+// WithCorrelationID returns a copy of the client which sends the correlation ID header with every request
+func (c *client) WithCorrelationID(correlationID string) Client {
+	cpy := *c
+	cpy.correlationID = correlationID
+	return &cpy
+}
+
+// WithTraceParent returns a copy of the client which traces the requests as children of the span with the given traceparent
+func (c *client) WithTraceParent(traceParent string) Client {
+	cpy := *c
+	cpy.traceParent = traceParent
+	return &cpy
+}
+
 func (c *client) ProvisionRuntime(accountID, subAccountID string, config schema.ProvisionRuntimeInput) (schema.OperationStatus, error) {
 	provisionRuntimeIptGQL, err := c.graphqlizer.ProvisionRuntimeInputToGraphQL(config)
 	if err != nil {
@@ -134,6 +190,19 @@ func (c *client) ReconnectRuntimeAgent(accountID, runtimeID string) (string, err
 	return operationId, nil
 }
 
+func (c *client) RevokeRuntimeCredentials(accountID, runtimeID string) (string, error) {
+	query := c.queryProvider.revokeRuntimeCredentials(runtimeID)
+	req := gcli.NewRequest(query)
+	req.Header.Add(accountIDKey, accountID)
+
+	var id string
+	err := c.executeRequest(req, &id)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to revoke Runtime credentials")
+	}
+	return id, nil
+}
+
 func (c *client) RuntimeOperationStatus(accountID, operationID string) (schema.OperationStatus, error) {
 	query := c.queryProvider.runtimeOperationStatus(operationID)
 	req := gcli.NewRequest(query)
@@ -169,11 +238,17 @@ func (c *client) executeRequest(req *gcli.Request, respDestination interface{})
 		Result interface{} `json:"result"`
 	}
 
+	if c.correlationID != "" {
+		req.Header.Set(httputil.CorrelationIDHeader, c.correlationID)
+	}
+
 	wrapper := &graphQLResponseWrapper{Result: respDestination}
-	err := c.graphQLClient.Run(context.TODO(), req, wrapper)
+	ctx, span := tracing.StartSpan(context.TODO(), "provisioner", c.traceParent)
+	err := c.graphQLClient.Run(ctx, req, wrapper)
+	tracing.EndSpan(span, err)
 	switch {
 	case isClientError(err):
-		return err
+		return kebError.AsNonRetryableError(err, "the request was rejected")
 	case err != nil:
 		return kebError.AsTemporaryError(err, "failed to execute the request")
 	}
@@ -193,3 +268,19 @@ func isClientError(err error) bool {
 	}
 	return false
 }
+
+// IsNotFoundError checks if the Provisioner rejected the request because the runtime does not exist.
+// The Provisioner reports a missing runtime with the "not found" message, the 404 error code is checked for future versions.
+func IsNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if ee, ok := errors.Cause(err).(gcli.ExtendedError); ok {
+		if code, found := ee.Extensions()["error_code"]; found {
+			if errCode, ok := code.(float64); ok && errCode == 404 {
+				return true
+			}
+		}
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "not found")
+}
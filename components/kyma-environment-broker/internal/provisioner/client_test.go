@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	schema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 	"github.com/pkg/errors"
@@ -52,6 +53,23 @@ func TestClient_ProvisionRuntime(t *testing.T) {
 		assert.Equal(t, ptr.String(provisionRuntimeID), status.RuntimeID)
 
 		assert.Equal(t, "test", tr.getRuntime().name)
+		assert.Empty(t, tr.getRuntime().correlationID)
+	})
+
+	t.Run("should pass the correlation ID", func(t *testing.T) {
+		// Given
+		tr := &testResolver{t: t, runtime: &testRuntime{}}
+		testServer := fixHTTPServer(tr)
+		defer testServer.Close()
+
+		client := ForOperation(NewProvisionerClient(testServer.URL, false), "correlation-id")
+
+		// When
+		_, err := client.ProvisionRuntime(testAccountID, testSubAccountID, fixProvisionRuntimeInput())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, "correlation-id", tr.getRuntime().correlationID)
 	})
 
 	t.Run("provisioner should return error", func(t *testing.T) {
@@ -273,6 +291,7 @@ func TestClient_ReconnectRuntimeAgent(t *testing.T) {
 		// Then
 		assert.Error(t, err)
 		assert.False(t, kebError.IsTemporaryError(err))
+		assert.False(t, kebError.IsRetryable(err))
 	})
 
 	t.Run("provisioner returns temporary code error", func(t *testing.T) {
@@ -362,6 +381,7 @@ func TestClient_RuntimeOperationStatus(t *testing.T) {
 type testRuntime struct {
 	tenant                 string
 	clientID               string
+	correlationID          string
 	name                   string
 	runtimeID              string
 	provisionOperationID   string
@@ -406,6 +426,7 @@ func fixHTTPServer(tr *testResolver) *httptest.Server {
 			}
 			tr.runtime.tenant = accountID
 			tr.runtime.clientID = subAccountID
+			tr.runtime.correlationID = r.Header.Get(httputil.CorrelationIDHeader)
 
 			h.ServeHTTP(w, r)
 		})
@@ -510,6 +531,16 @@ func (tmr testMutationResolver) ReconnectRuntimeAgent(_ context.Context, id stri
 	return "", nil
 }
 
+func (tmr testMutationResolver) RevokeRuntimeCredentials(_ context.Context, id string) (string, error) {
+	tmr.t.Log("RevokeRuntimeCredentials testMutationResolver")
+
+	if tmr.failed {
+		return "", fmt.Errorf("revoke runtime credentials failed for %s", id)
+	}
+
+	return id, nil
+}
+
 func (tmr testMutationResolver) UpgradeShoot(_ context.Context, id string, config schema.UpgradeShootInput) (*schema.OperationStatus, error) {
 	tmr.t.Log("UpgradeShoot testMutationResolver")
 
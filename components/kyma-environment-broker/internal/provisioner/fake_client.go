@@ -97,6 +97,10 @@ func (c *FakeClient) ReconnectRuntimeAgent(accountID, runtimeID string) (string,
 	return "", fmt.Errorf("not implemented")
 }
 
+func (c *FakeClient) RevokeRuntimeCredentials(accountID, runtimeID string) (string, error) {
+	return runtimeID, nil
+}
+
 func (c *FakeClient) RuntimeOperationStatus(accountID, operationID string) (schema.OperationStatus, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
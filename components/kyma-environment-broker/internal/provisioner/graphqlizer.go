@@ -82,6 +82,9 @@ func (g *Graphqlizer) GardenerConfigInputToGraphQL(in gqlschema.GardenerConfigIn
 		{{- if .LicenceType }}
 		licenceType: "{{ .LicenceType }}",
 		{{- end }}
+		{{- if .Seed }}
+		seed: "{{ .Seed }}",
+		{{- end }}
         {{- if .DiskType }}
 		diskType: "{{.DiskType}}",
         {{- end }}
@@ -91,6 +94,49 @@ func (g *Graphqlizer) GardenerConfigInputToGraphQL(in gqlschema.GardenerConfigIn
         autoScalerMax: {{ .AutoScalerMax }},
         maxSurge: {{ .MaxSurge }},
 		maxUnavailable: {{ .MaxUnavailable }},
+		{{- if .Annotations }}
+		annotations: [
+			{{- range $i, $a := .Annotations }}
+			{{- if $i }},{{ end }}
+			{ key: {{ strQuote $a.Key }}, value: {{ strQuote $a.Value }} }
+			{{- end }}
+		],
+		{{- end }}
+		{{- if .Networking }}
+		networking: {
+			{{- if .Networking.PrivateCluster }}
+			privateCluster: {{ .Networking.PrivateCluster }},
+			{{- end }}
+			{{- if .Networking.AllowedCidrs }}
+			allowedCidrs: {{ .Networking.AllowedCidrs | marshal }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .ControlPlane }}
+		controlPlane: {
+			{{- if .ControlPlane.EtcdEncryption }}
+			etcdEncryption: {{ .ControlPlane.EtcdEncryption }},
+			{{- end }}
+			{{- if .ControlPlane.EtcdEncryptionKeyRef }}
+			etcdEncryptionKeyRef: {{ .ControlPlane.EtcdEncryptionKeyRef | strQuote }},
+			{{- end }}
+			{{- if .ControlPlane.BackupRetentionDays }}
+			backupRetentionDays: {{ .ControlPlane.BackupRetentionDays }},
+			{{- end }}
+			{{- if .ControlPlane.HighAvailability }}
+			highAvailability: {{ .ControlPlane.HighAvailability }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .MaintenanceWindow }}
+		maintenanceWindow: {
+			begin: {{ .MaintenanceWindow.Begin | strQuote }},
+			end: {{ .MaintenanceWindow.End | strQuote }},
+		},
+		{{- end }}
+		{{- if .KubernetesConfig }}
+		kubernetesConfig: {{ KubernetesConfigInputToGraphQL .KubernetesConfig }},
+		{{- end }}
 		{{- if .ProviderSpecificConfig }}
 		providerSpecificConfig: {
 			{{- if .ProviderSpecificConfig.AzureConfig }}
@@ -110,6 +156,60 @@ func (g *Graphqlizer) GardenerConfigInputToGraphQL(in gqlschema.GardenerConfigIn
 	}`)
 }
 
+func (g *Graphqlizer) KubernetesConfigInputToGraphQL(in gqlschema.KubernetesConfigInput) (string, error) {
+	return g.genericToGraphQL(in, `{
+		{{- with .Kubelet }}
+		kubelet: {
+			{{- if .FeatureGates }}
+			featureGates: [
+				{{- range $i, $g := .FeatureGates }}
+				{{- if $i }},{{ end }}
+				{ name: {{ strQuote $g.Name }}, enabled: {{ $g.Enabled }} }
+				{{- end }}
+			],
+			{{- end }}
+			{{- with .EvictionHard }}
+			evictionHard: {
+				{{- if .MemoryAvailable }}
+				memoryAvailable: {{ .MemoryAvailable | strQuote }},
+				{{- end }}
+				{{- if .ImageFSAvailable }}
+				imageFSAvailable: {{ .ImageFSAvailable | strQuote }},
+				{{- end }}
+				{{- if .ImageFSInodesFree }}
+				imageFSInodesFree: {{ .ImageFSInodesFree | strQuote }},
+				{{- end }}
+				{{- if .NodeFSAvailable }}
+				nodeFSAvailable: {{ .NodeFSAvailable | strQuote }},
+				{{- end }}
+				{{- if .NodeFSInodesFree }}
+				nodeFSInodesFree: {{ .NodeFSInodesFree | strQuote }},
+				{{- end }}
+			},
+			{{- end }}
+			{{- if .MaxPods }}
+			maxPods: {{ .MaxPods }},
+			{{- end }}
+			{{- if .PodPidsLimit }}
+			podPidsLimit: {{ .PodPidsLimit }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- with .APIServer }}
+		apiServer: {
+			{{- if .FeatureGates }}
+			featureGates: [
+				{{- range $i, $g := .FeatureGates }}
+				{{- if $i }},{{ end }}
+				{ name: {{ strQuote $g.Name }}, enabled: {{ $g.Enabled }} }
+				{{- end }}
+			],
+			{{- end }}
+		},
+		{{- end }}
+	}`)
+}
+
 func (g *Graphqlizer) AzureProviderConfigInputToGraphQL(in gqlschema.AzureProviderConfigInput) (string, error) {
 	return g.genericToGraphQL(in, `{
 		vnetCidr: "{{.VnetCidr}}",
@@ -120,16 +220,19 @@ func (g *Graphqlizer) AzureProviderConfigInputToGraphQL(in gqlschema.AzureProvid
 }
 
 func (g *Graphqlizer) GCPProviderConfigInputToGraphQL(in gqlschema.GCPProviderConfigInput) (string, error) {
+	if in.EncryptionKey != nil {
+		return fmt.Sprintf(`{ zones: %s, encryptionKey: "%s" }`, g.marshal(in.Zones), *in.EncryptionKey), nil
+	}
 	return fmt.Sprintf(`{ zones: %s }`, g.marshal(in.Zones)), nil
 }
 
 func (g *Graphqlizer) AWSProviderConfigInputToGraphQL(in gqlschema.AWSProviderConfigInput) (string, error) {
-	return fmt.Sprintf(`{
-		zone: "%s" ,
-		publicCidr: "%s",
-		vpcCidr: "%s",
-        internalCidr: "%s",
-}`, in.Zone, in.PublicCidr, in.VpcCidr, in.InternalCidr), nil
+	return g.genericToGraphQL(in, `{
+		zone: "{{.Zone}}",
+		publicCidr: "{{.PublicCidr}}",
+		vpcCidr: "{{.VpcCidr}}",
+		internalCidr: "{{.InternalCidr}}",
+	}`)
 }
 
 func (g *Graphqlizer) OpenStackProviderConfigInputToGraphQL(in gqlschema.OpenStackProviderConfigInput) (string, error) {
@@ -245,6 +348,18 @@ func (g Graphqlizer) UpgradeShootInputToGraphQL(in gqlschema.UpgradeShootInput)
       {{- if .EnableMachineImageVersionAutoUpdate }}
       enableMachineImageVersionAutoUpdate: {{.EnableMachineImageVersionAutoUpdate}},
       {{- end }}
+      {{- if .TargetSecret }}
+      targetSecret: "{{.TargetSecret}}",
+      {{- end }}
+      {{- if .MaxSurge }}
+      maxSurge: {{.MaxSurge}},
+      {{- end }}
+      {{- if .MaxUnavailable }}
+      maxUnavailable: {{.MaxUnavailable}},
+      {{- end }}
+      {{- if .KubernetesConfig }}
+      kubernetesConfig: {{ KubernetesConfigInputToGraphQL .KubernetesConfig }},
+      {{- end }}
     }
   }`)
 }
@@ -260,6 +375,7 @@ func (g *Graphqlizer) genericToGraphQL(obj interface{}, tmpl string) (string, er
 	fm["GCPProviderConfigInputToGraphQL"] = g.GCPProviderConfigInputToGraphQL
 	fm["AWSProviderConfigInputToGraphQL"] = g.AWSProviderConfigInputToGraphQL
 	fm["OpenStackProviderConfigInputToGraphQL"] = g.OpenStackProviderConfigInputToGraphQL
+	fm["KubernetesConfigInputToGraphQL"] = g.KubernetesConfigInputToGraphQL
 	fm["LabelsToGQL"] = g.LabelsToGQL
 	fm["strQuote"] = strconv.Quote
 
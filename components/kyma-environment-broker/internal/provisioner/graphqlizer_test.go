@@ -209,6 +209,246 @@ func Test_GardenerConfigInputToGraphQLWithMachineImage(t *testing.T) {
 	assert.Equal(t, exp, got)
 }
 
+func Test_GardenerConfigInputToGraphQLWithSeedAndAnnotations(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+		name: "c-90a3016",
+		kubernetesVersion: "1.18",
+		machineType: "Standard_D4_v3",
+		region: "europe",
+		provider: "Azure",
+		seed: "az-eu1",
+		targetSecret: "scr",
+		workerCidr: "10.250.0.0/19",
+        autoScalerMin: 0,
+        autoScalerMax: 0,
+        maxSurge: 0,
+		maxUnavailable: 0,
+		annotations: [
+			{ key: "example.com/team", value: "kyma" },
+			{ key: "project", value: "control-plane" }
+		],
+	}`
+
+	// when
+	got, err := sut.GardenerConfigInputToGraphQL(gqlschema.GardenerConfigInput{
+		Name:              "c-90a3016",
+		Region:            "europe",
+		WorkerCidr:        "10.250.0.0/19",
+		Provider:          "Azure",
+		TargetSecret:      "scr",
+		MachineType:       "Standard_D4_v3",
+		KubernetesVersion: "1.18",
+		Seed:              ptr.String("az-eu1"),
+		Annotations: []*gqlschema.AnnotationInput{
+			{Key: "example.com/team", Value: "kyma"},
+			{Key: "project", Value: "control-plane"},
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
+func Test_GardenerConfigInputToGraphQLForPrivateCluster(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+		name: "c-90a3016",
+		kubernetesVersion: "1.18",
+		machineType: "Standard_D4_v3",
+		region: "europe",
+		provider: "Azure",
+		targetSecret: "scr",
+		workerCidr: "10.250.0.0/19",
+        autoScalerMin: 0,
+        autoScalerMax: 0,
+        maxSurge: 0,
+		maxUnavailable: 0,
+		networking: {
+			privateCluster: true,
+			allowedCidrs: ["10.0.0.0/8","192.168.1.1/32"],
+		},
+	}`
+
+	// when
+	got, err := sut.GardenerConfigInputToGraphQL(gqlschema.GardenerConfigInput{
+		Name:              "c-90a3016",
+		Region:            "europe",
+		WorkerCidr:        "10.250.0.0/19",
+		Provider:          "Azure",
+		TargetSecret:      "scr",
+		MachineType:       "Standard_D4_v3",
+		KubernetesVersion: "1.18",
+		Networking: &gqlschema.NetworkingInput{
+			PrivateCluster: ptr.Bool(true),
+			AllowedCidrs:   []string{"10.0.0.0/8", "192.168.1.1/32"},
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
+func Test_GardenerConfigInputToGraphQLWithControlPlane(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+		name: "c-90a3016",
+		kubernetesVersion: "1.18",
+		machineType: "Standard_D4_v3",
+		region: "europe",
+		provider: "Azure",
+		targetSecret: "scr",
+		workerCidr: "10.250.0.0/19",
+        autoScalerMin: 0,
+        autoScalerMax: 0,
+        maxSurge: 0,
+		maxUnavailable: 0,
+		controlPlane: {
+			etcdEncryption: true,
+			etcdEncryptionKeyRef: "https://vault.vault.azure.net/keys/etcd",
+			backupRetentionDays: 14,
+			highAvailability: true,
+		},
+	}`
+
+	// when
+	got, err := sut.GardenerConfigInputToGraphQL(gqlschema.GardenerConfigInput{
+		Name:              "c-90a3016",
+		Region:            "europe",
+		WorkerCidr:        "10.250.0.0/19",
+		Provider:          "Azure",
+		TargetSecret:      "scr",
+		MachineType:       "Standard_D4_v3",
+		KubernetesVersion: "1.18",
+		ControlPlane: &gqlschema.ControlPlaneInput{
+			EtcdEncryption:       ptr.Bool(true),
+			EtcdEncryptionKeyRef: ptr.String("https://vault.vault.azure.net/keys/etcd"),
+			BackupRetentionDays:  ptr.Integer(14),
+			HighAvailability:     ptr.Bool(true),
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
+func Test_GardenerConfigInputToGraphQLWithMaintenanceWindow(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+		name: "c-90a3016",
+		kubernetesVersion: "1.18",
+		machineType: "Standard_D4_v3",
+		region: "europe",
+		provider: "Azure",
+		targetSecret: "scr",
+		workerCidr: "10.250.0.0/19",
+        autoScalerMin: 0,
+        autoScalerMax: 0,
+        maxSurge: 0,
+		maxUnavailable: 0,
+		maintenanceWindow: {
+			begin: "220000+0100",
+			end: "230000+0100",
+		},
+	}`
+
+	// when
+	got, err := sut.GardenerConfigInputToGraphQL(gqlschema.GardenerConfigInput{
+		Name:              "c-90a3016",
+		Region:            "europe",
+		WorkerCidr:        "10.250.0.0/19",
+		Provider:          "Azure",
+		TargetSecret:      "scr",
+		MachineType:       "Standard_D4_v3",
+		KubernetesVersion: "1.18",
+		MaintenanceWindow: &gqlschema.MaintenanceWindowInput{Begin: "220000+0100", End: "230000+0100"},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
+func Test_KubernetesConfigInputToGraphQL(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+		kubelet: {
+			featureGates: [
+				{ name: "BoundServiceAccountTokenVolume", enabled: false },
+				{ name: "CPUManager", enabled: true }
+			],
+			evictionHard: {
+				memoryAvailable: "200Mi",
+				nodeFSAvailable: "10%",
+			},
+			maxPods: 110,
+			podPidsLimit: 4096,
+		},
+		apiServer: {
+			featureGates: [
+				{ name: "TTLAfterFinished", enabled: true }
+			],
+		},
+	}`
+
+	// when
+	got, err := sut.KubernetesConfigInputToGraphQL(gqlschema.KubernetesConfigInput{
+		Kubelet: &gqlschema.KubeletConfigInput{
+			FeatureGates: []*gqlschema.FeatureGateInput{
+				{Name: "BoundServiceAccountTokenVolume", Enabled: false},
+				{Name: "CPUManager", Enabled: true},
+			},
+			EvictionHard: &gqlschema.EvictionThresholdsInput{MemoryAvailable: ptr.String("200Mi"), NodeFSAvailable: ptr.String("10%")},
+			MaxPods:      ptr.Integer(110),
+			PodPidsLimit: ptr.Integer(4096),
+		},
+		APIServer: &gqlschema.APIServerConfigInput{
+			FeatureGates: []*gqlschema.FeatureGateInput{{Name: "TTLAfterFinished", Enabled: true}},
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
+func Test_UpgradeShootInputToGraphQLWithKubernetesConfig(t *testing.T) {
+	// given
+	sut := Graphqlizer{}
+	exp := `{
+    gardenerConfig: {
+      kubernetesVersion: "1.18.0",
+      kubernetesConfig: {
+		kubelet: {
+			maxPods: 110,
+		},
+	},
+    }
+  }`
+
+	// when
+	got, err := sut.UpgradeShootInputToGraphQL(gqlschema.UpgradeShootInput{
+		GardenerConfig: &gqlschema.GardenerUpgradeInput{
+			KubernetesVersion: strPrt("1.18.0"),
+			KubernetesConfig: &gqlschema.KubernetesConfigInput{
+				Kubelet: &gqlschema.KubeletConfigInput{MaxPods: ptr.Integer(110)},
+			},
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, exp, got)
+}
+
 func Test_LabelsToGQL(t *testing.T) {
 
 	sut := Graphqlizer{}
@@ -306,6 +546,38 @@ func TestGCPProviderConfigInputToGraphQL(t *testing.T) {
 	// then
 	require.NoError(t, err)
 	assert.Equal(t, expected, got)
+
+	// when
+	fixInput.EncryptionKey = ptr.String("projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks")
+	got, err = g.GCPProviderConfigInputToGraphQL(fixInput)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `{ zones: ["fix-gcp-zone-1","fix-gcp-zone-2"], encryptionKey: "projects/kyma-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks" }`, got)
+}
+
+func TestAWSProviderConfigInputToGraphQL(t *testing.T) {
+	// given
+	fixInput := gqlschema.AWSProviderConfigInput{
+		Zone:         "eu-central-1a",
+		VpcCidr:      "10.250.0.0/16",
+		PublicCidr:   "10.250.96.0/22",
+		InternalCidr: "10.250.112.0/22",
+	}
+	expected := `{
+		zone: "eu-central-1a",
+		publicCidr: "10.250.96.0/22",
+		vpcCidr: "10.250.0.0/16",
+		internalCidr: "10.250.112.0/22",
+	}`
+	g := &Graphqlizer{}
+
+	// when
+	got, err := g.AWSProviderConfigInputToGraphQL(fixInput)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
 }
 
 func Test_UpgradeShootInputToGraphQL(t *testing.T) {
@@ -318,6 +590,7 @@ func Test_UpgradeShootInputToGraphQL(t *testing.T) {
       machineImageVersion: "184.0.0",
       enableKubernetesVersionAutoUpdate: true,
       enableMachineImageVersionAutoUpdate: false,
+      targetSecret: "gardener-secret",
     }
   }`
 
@@ -329,6 +602,7 @@ func Test_UpgradeShootInputToGraphQL(t *testing.T) {
 			MachineImageVersion:                 strPrt("184.0.0"),
 			EnableKubernetesVersionAutoUpdate:   boolPtr(true),
 			EnableMachineImageVersionAutoUpdate: boolPtr(false),
+			TargetSecret:                        strPrt("gardener-secret"),
 		},
 	})
 
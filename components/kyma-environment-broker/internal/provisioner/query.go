@@ -40,6 +40,12 @@ func (qp queryProvider) reconnectRuntimeAgent(runtimeID string) string {
 }`, runtimeID)
 }
 
+func (qp queryProvider) revokeRuntimeCredentials(runtimeID string) string {
+	return fmt.Sprintf(`mutation {
+	result: revokeRuntimeCredentials(id: "%s")
+}`, runtimeID)
+}
+
 func (qp queryProvider) runtimeStatus(runtimeID string) string {
 	return fmt.Sprintf(`query {
 	result: runtimeStatus(id: "%s") {
@@ -0,0 +1,68 @@
+package provisioner
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces the secret values in the dumped Provisioner requests
+const RedactedValue = "REDACTED"
+
+// requestDumper is implemented by clients which dump the requests sent to the Provisioner
+type requestDumper interface {
+	DumpsRequests() bool
+}
+
+// DumpRequest returns the JSON of the Provisioner request input with the secrets redacted, so it can be stored
+// on the operation for debugging. Nil is returned if the client does not dump the requests.
+func DumpRequest(c Client, input interface{}) (json.RawMessage, error) {
+	dumper, ok := c.(requestDumper)
+	if !ok || !dumper.DumpsRequests() {
+		return nil, nil
+	}
+	return SanitizeRequest(input)
+}
+
+// SanitizeRequest returns the JSON of the Provisioner request input with the values of the secret configuration entries
+// and the name of the target secret redacted
+func SanitizeRequest(input interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshalling provisioner request")
+	}
+	var request interface{}
+	if err := json.Unmarshal(raw, &request); err != nil {
+		return nil, errors.Wrap(err, "while unmarshalling provisioner request")
+	}
+
+	redactSecrets(request)
+
+	sanitized, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshalling sanitized provisioner request")
+	}
+	return sanitized, nil
+}
+
+func redactSecrets(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if secret, ok := v["secret"].(bool); ok && secret {
+			if _, found := v["value"]; found {
+				v["value"] = RedactedValue
+			}
+		}
+		for key, nested := range v {
+			if key == "targetSecret" && nested != nil {
+				v[key] = RedactedValue
+				continue
+			}
+			redactSecrets(nested)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			redactSecrets(nested)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	schema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeRequest(t *testing.T) {
+	// given
+	input := fixProvisionRuntimeInputWithSecrets()
+
+	// when
+	sanitized, err := SanitizeRequest(input)
+
+	// then
+	require.NoError(t, err)
+	var request schema.ProvisionRuntimeInput
+	require.NoError(t, json.Unmarshal(sanitized, &request))
+	assert.Equal(t, RedactedValue, request.ClusterConfig.GardenerConfig.TargetSecret)
+	assert.Equal(t, "westeurope", request.ClusterConfig.GardenerConfig.Region)
+	assert.Equal(t, RedactedValue, request.KymaConfig.Configuration[0].Value)
+	assert.Equal(t, "info", request.KymaConfig.Configuration[1].Value)
+	assert.Equal(t, RedactedValue, request.KymaConfig.Components[0].Configuration[0].Value)
+	assert.NotContains(t, string(sanitized), "s3cr3t")
+
+	// the input sent to the Provisioner is not changed
+	assert.Equal(t, "azure-secret", input.ClusterConfig.GardenerConfig.TargetSecret)
+	assert.Equal(t, "s3cr3t", input.KymaConfig.Configuration[0].Value)
+}
+
+func TestDumpRequest(t *testing.T) {
+	// given
+	input := fixProvisionRuntimeInputWithSecrets()
+
+	// when
+	dumped, err := DumpRequest(NewProvisionerClient("http://provisioner", true), input)
+
+	// then
+	require.NoError(t, err)
+	assert.NotEmpty(t, dumped)
+	assert.NotContains(t, string(dumped), "s3cr3t")
+
+	// when
+	dumped, err = DumpRequest(NewProvisionerClient("http://provisioner", false), input)
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, dumped)
+
+	// when
+	dumped, err = DumpRequest(NewFakeClient(), input)
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, dumped)
+}
+
+func TestRequestDumpHandler(t *testing.T) {
+	// given
+	operations := storage.NewMemoryStorage().Operations()
+	dumped, err := SanitizeRequest(fixProvisionRuntimeInputWithSecrets())
+	require.NoError(t, err)
+	withRequest := fixture.FixProvisioningOperation("op-with-request", "instance-1")
+	withRequest.ProvisionerRequest = dumped
+	require.NoError(t, operations.InsertProvisioningOperation(withRequest))
+	require.NoError(t, operations.InsertProvisioningOperation(fixture.FixProvisioningOperation("op-without-request", "instance-2")))
+
+	handler := NewRequestDumpHandler(operations, logrus.New())
+
+	// when
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/operations/op-with-request/provisioner-request", nil))
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, string(dumped), recorder.Body.String())
+	assert.NotContains(t, recorder.Body.String(), "s3cr3t")
+
+	for _, operationID := range []string{"op-without-request", "not-existing"} {
+		// when
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/operations/"+operationID+"/provisioner-request", nil))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	}
+}
+
+func fixProvisionRuntimeInputWithSecrets() schema.ProvisionRuntimeInput {
+	return schema.ProvisionRuntimeInput{
+		RuntimeInput: &schema.RuntimeInput{Name: "runtime"},
+		ClusterConfig: &schema.ClusterConfigInput{
+			GardenerConfig: &schema.GardenerConfigInput{
+				TargetSecret: "azure-secret",
+				Region:       "westeurope",
+			},
+		},
+		KymaConfig: &schema.KymaConfigInput{
+			Version: "1.22.0",
+			Configuration: []*schema.ConfigEntryInput{
+				{Key: "global.password", Value: "s3cr3t", Secret: ptr.Bool(true)},
+				{Key: "global.logLevel", Value: "info"},
+			},
+			Components: []*schema.ComponentConfigurationInput{
+				{
+					Component: "monitoring",
+					Configuration: []*schema.ConfigEntryInput{
+						{Key: "grafana.adminPassword", Value: "s3cr3t", Secret: ptr.Bool(true)},
+					},
+				},
+			},
+		},
+	}
+}
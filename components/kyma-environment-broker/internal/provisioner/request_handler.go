@@ -0,0 +1,56 @@
+package provisioner
+
+import (
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type requestDumpHandler struct {
+	operations storage.Operations
+	log        logrus.FieldLogger
+}
+
+// NewRequestDumpHandler exposes the GET /admin/operations/{operation_id}/provisioner-request endpoint which returns
+// the sanitized Provisioner request stored on the operation, the requests are stored only when dumping them is enabled
+func NewRequestDumpHandler(operations storage.Operations, log logrus.FieldLogger) http.Handler {
+	h := &requestDumpHandler{
+		operations: operations,
+		log:        log.WithField("service", "RequestDumpHandler"),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/operations/{operation_id}/provisioner-request", h.getRequest).Methods(http.MethodGet)
+
+	return router
+}
+
+func (h *requestDumpHandler) getRequest(w http.ResponseWriter, r *http.Request) {
+	operationID := mux.Vars(r)["operation_id"]
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	switch {
+	case dberr.IsNotFound(err):
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Errorf("operation %s not found", operationID))
+		return
+	case err != nil:
+		h.log.Errorf("while getting operation %s: %v", operationID, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while getting operation %s", operationID))
+		return
+	}
+	if len(operation.ProvisionerRequest) == 0 {
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Errorf("provisioner request of operation %s was not stored", operationID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(operation.ProvisionerRequest); err != nil {
+		h.log.Errorf("while writing provisioner request of operation %s: %v", operationID, err)
+	}
+}
@@ -0,0 +1,65 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+
+	"github.com/pkg/errors"
+)
+
+const remainingQuotaTmpl = "%s/subaccounts/%s/plans/%s/quota"
+
+type Config struct {
+	// Disabled skips the validation of the remaining runtime quota of the subaccount
+	Disabled bool          `envconfig:"default=true"`
+	URL      string        `envconfig:"optional"`
+	Timeout  time.Duration `envconfig:"default=10s"`
+}
+
+type remainingQuotaResponse struct {
+	Remaining int `json:"remaining"`
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Remaining returns the number of runtimes of the plan which the subaccount can still provision.
+// The subaccount which did not purchase the quota for the plan has no runtimes remaining.
+func (c *Client) Remaining(subAccountID, planName string) (int, error) {
+	URL := fmt.Sprintf(remainingQuotaTmpl, c.config.URL, url.PathEscape(subAccountID), url.PathEscape(planName))
+	response, err := c.httpClient.Get(URL)
+	if err != nil {
+		return 0, kebError.AsTemporaryError(err, "while requesting quota of subaccount %s", subAccountID)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return 0, nil
+	case response.StatusCode == http.StatusRequestTimeout, response.StatusCode >= http.StatusInternalServerError:
+		return 0, kebError.NewTemporaryError("quota service returned status %d for subaccount %s", response.StatusCode, subAccountID)
+	case response.StatusCode != http.StatusOK:
+		return 0, errors.Errorf("quota service returned status %d for subaccount %s", response.StatusCode, subAccountID)
+	}
+
+	var body remainingQuotaResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return 0, errors.Wrap(err, "while decoding quota response")
+	}
+
+	return body.Remaining, nil
+}
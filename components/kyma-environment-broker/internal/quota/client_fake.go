@@ -0,0 +1,15 @@
+package quota
+
+// FakeClient returns the remaining quota from the map by the subaccount ID and the plan name,
+// subaccounts which are not in the map have no quota remaining
+type FakeClient struct {
+	remaining map[string]map[string]int
+}
+
+func NewFakeClient(remaining map[string]map[string]int) *FakeClient {
+	return &FakeClient{remaining: remaining}
+}
+
+func (f *FakeClient) Remaining(subAccountID, planName string) (int, error) {
+	return f.remaining[subAccountID][planName], nil
+}
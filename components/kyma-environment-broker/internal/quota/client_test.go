@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Remaining(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subaccounts/purchased/plans/azure/quota":
+			w.Write([]byte(`{"remaining": 2}`))
+		case "/subaccounts/unavailable/plans/azure/quota":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(Config{URL: server.URL, Timeout: time.Second})
+
+	// when
+	purchased, err := client.Remaining("purchased", "azure")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 2, purchased)
+
+	// when
+	notPurchased, err := client.Remaining("unknown", "azure")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 0, notPurchased)
+
+	// when
+	_, err = client.Remaining("unavailable", "azure")
+
+	// then
+	require.Error(t, err)
+	assert.True(t, kebError.IsTemporaryError(err))
+}
@@ -13,4 +13,5 @@ const (
 	AvSBridge               = "avs-bridge"
 	Eventing                = "eventing"
 	CLS                     = "logging"
+	Istio                   = "istio"
 )
@@ -54,6 +54,18 @@ func (c *converter) applyOperation(source *internal.Operation, target *pkg.Opera
 		target.State = string(source.State)
 		target.Description = source.Description
 		target.OrchestrationID = source.OrchestrationID
+		if source.FailureReason != nil {
+			target.FailureReason = &pkg.FailureReason{
+				Code:        source.FailureReason.Code,
+				Message:     source.FailureReason.Message,
+				Remediation: source.FailureReason.Remediation,
+				Retryable:   source.FailureReason.Retryable,
+				Subsystem:   source.FailureReason.Subsystem,
+			}
+		}
+		for _, progress := range source.Progress {
+			target.Progress = append(target.Progress, pkg.ProgressMessage{Time: progress.Time, Message: progress.Message})
+		}
 	}
 }
 
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
@@ -18,15 +19,112 @@ type ComponentsDisablers map[string]ComponentDisabler
 // OptionalComponentsService provides functionality for executing component disablers
 type OptionalComponentsService struct {
 	registered map[string]ComponentDisabler
+	// toggleable holds the disablers of the components which are installed by default but can be disabled by the client
+	toggleable map[string]ComponentDisabler
+	// dependencies maps the component to the components it requires, the names are lower case
+	dependencies map[string][]string
+	// names maps the lower case names from the dependencies to the configured ones used in the messages
+	names map[string]string
 }
 
 // NewOptionalComponentsService returns new instance of ResourceSupervisorAggregator
 func NewOptionalComponentsService(initialList ComponentsDisablers) *OptionalComponentsService {
 	return &OptionalComponentsService{
-		registered: initialList,
+		registered:   initialList,
+		toggleable:   map[string]ComponentDisabler{},
+		dependencies: map[string][]string{},
+		names:        map[string]string{},
 	}
 }
 
+// AddToggleableComponents registers generic disablers for the components which the client can disable in the provisioning parameters.
+// Unlike the optional components, they are not disabled by default. The optional components are toggleable by their own disablers.
+func (f *OptionalComponentsService) AddToggleableComponents(names ...string) {
+	for _, name := range names {
+		if _, exists := f.registered[name]; exists {
+			continue
+		}
+		f.toggleable[name] = NewGenericComponentDisabler(name)
+	}
+}
+
+// SetComponentDependencies registers the components required by other components. The entries have the
+// <component>:<dependency>[+<dependency>] format, for example "kiali:tracing".
+func (f *OptionalComponentsService) SetComponentDependencies(entries []string) error {
+	dependencies := map[string][]string{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid component dependencies entry '%s'", entry)
+		}
+		component := f.normalizeName(parts[0])
+		for _, dependency := range strings.Split(parts[1], "+") {
+			if dependency == "" {
+				return fmt.Errorf("invalid component dependencies entry '%s'", entry)
+			}
+			dependencies[component] = append(dependencies[component], f.normalizeName(dependency))
+		}
+	}
+	f.dependencies = dependencies
+
+	return nil
+}
+
+// ValidateToggles returns an error naming the conflict if the toggled components leave a component without a component
+// it requires. Only the dependencies of the toggled components and the dependencies on the toggled components are checked,
+// the optional components are installed only if they are enabled. The names are compared as case insensitive.
+func (f *OptionalComponentsService) ValidateToggles(toggles *internal.ComponentToggles) error {
+	if toggles == nil || len(f.dependencies) == 0 {
+		return nil
+	}
+	enabled := toNormalizedMap(toggles.Enable)
+	disabled := toNormalizedMap(toggles.Disable)
+	optional := toNormalizedMap(f.GetAllOptionalComponentsNames())
+	toggled := func(name string) bool {
+		_, isEnabled := enabled[name]
+		_, isDisabled := disabled[name]
+		return isEnabled || isDisabled
+	}
+	installed := func(name string) bool {
+		if _, found := enabled[name]; found {
+			return true
+		}
+		if _, found := disabled[name]; found {
+			return false
+		}
+		_, isOptional := optional[name]
+		return !isOptional
+	}
+
+	components := make([]string, 0, len(f.dependencies))
+	for component := range f.dependencies {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		for _, dependency := range f.dependencies[component] {
+			if !toggled(component) && !toggled(dependency) {
+				continue
+			}
+			if !installed(component) || installed(dependency) {
+				continue
+			}
+			if _, found := disabled[dependency]; found {
+				return fmt.Errorf("component %q cannot be disabled, it is required by the component %q", f.names[dependency], f.names[component])
+			}
+			return fmt.Errorf("component %q requires the component %q which is not enabled", f.names[component], f.names[dependency])
+		}
+	}
+
+	return nil
+}
+
+func (f *OptionalComponentsService) normalizeName(name string) string {
+	normalized := strings.ToLower(name)
+	f.names[normalized] = name
+	return normalized
+}
+
 // GetAllOptionalComponentsNames returns list of registered components disablers names
 func (f *OptionalComponentsService) GetAllOptionalComponentsNames() []string {
 	var names []string
@@ -44,6 +142,9 @@ func (f *OptionalComponentsService) ExecuteDisablers(components internal.Compone
 	var filterOut = components
 	for _, name := range names {
 		concreteDisabler, exists := f.registered[name]
+		if !exists {
+			concreteDisabler, exists = f.toggleable[name]
+		}
 		if !exists {
 			return nil, fmt.Errorf("disabler for component %s was not found", name)
 		}
@@ -0,0 +1,65 @@
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalComponentsService_ValidateToggles(t *testing.T) {
+	// given
+	svc := runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{
+		"kiali":   runtime.NewGenericComponentDisabler("kiali"),
+		"tracing": runtime.NewGenericComponentDisabler("tracing"),
+	})
+	svc.AddToggleableComponents("dex", "console")
+	require.NoError(t, svc.SetComponentDependencies([]string{"kiali:tracing", "console:dex"}))
+
+	for name, tc := range map[string]struct {
+		toggles       *internal.ComponentToggles
+		expectedError string
+	}{
+		"no toggles": {},
+		"enabled component with enabled dependency": {
+			toggles: &internal.ComponentToggles{Enable: []string{"Kiali", "tracing"}},
+		},
+		"disabled component with disabled dependency": {
+			toggles: &internal.ComponentToggles{Disable: []string{"console", "dex"}},
+		},
+		"enabled component with optional dependency which is not enabled": {
+			toggles:       &internal.ComponentToggles{Enable: []string{"kiali"}},
+			expectedError: `component "kiali" requires the component "tracing" which is not enabled`,
+		},
+		"disabled dependency of default component": {
+			toggles:       &internal.ComponentToggles{Disable: []string{"dex"}},
+			expectedError: `component "dex" cannot be disabled, it is required by the component "console"`,
+		},
+		"disabled dependency of enabled component": {
+			toggles:       &internal.ComponentToggles{Enable: []string{"kiali"}, Disable: []string{"tracing"}},
+			expectedError: `component "tracing" cannot be disabled, it is required by the component "kiali"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			err := svc.ValidateToggles(tc.toggles)
+
+			// then
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestOptionalComponentsService_SetComponentDependencies(t *testing.T) {
+	for _, entry := range []string{"kiali", "kiali:", ":tracing", "kiali:tracing+"} {
+		err := runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{}).SetComponentDependencies([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
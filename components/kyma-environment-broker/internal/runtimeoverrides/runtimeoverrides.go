@@ -3,9 +3,11 @@ package runtimeoverrides
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
@@ -27,18 +29,43 @@ type InputAppender interface {
 	AppendGlobalOverrides(overrides []*gqlschema.ConfigEntryInput) internal.ProvisionerInputCreator
 }
 
+type Config struct {
+	// AllowedSecrets lists the patterns of the names of the secrets with overrides which can be read, e.g. "runtime-overrides-*".
+	// The pattern with a slash matches the namespace and the name of the secret, e.g. "kcp-system/*". All secrets are read if empty.
+	AllowedSecrets []string `envconfig:"optional"`
+}
+
 type runtimeOverrides struct {
-	ctx       context.Context
-	k8sClient client.Client
+	ctx            context.Context
+	k8sClient      client.Client
+	allowedSecrets []string
+	log            logrus.FieldLogger
 }
 
 func NewRuntimeOverrides(ctx context.Context, cli client.Client) *runtimeOverrides {
 	return &runtimeOverrides{
 		ctx:       ctx,
 		k8sClient: cli,
+		log:       logrus.New(),
 	}
 }
 
+// NewRuntimeOverridesWithConfig creates the appender which reads only the secrets matching the allowed patterns,
+// the other secrets with the overrides label are skipped
+func NewRuntimeOverridesWithConfig(ctx context.Context, cli client.Client, cfg Config, log logrus.FieldLogger) (*runtimeOverrides, error) {
+	for _, pattern := range cfg.AllowedSecrets {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "while checking the pattern %q of the allowed secrets", pattern)
+		}
+	}
+	return &runtimeOverrides{
+		ctx:            ctx,
+		k8sClient:      cli,
+		allowedSecrets: cfg.AllowedSecrets,
+		log:            log,
+	}, nil
+}
+
 func (ro *runtimeOverrides) Append(input InputAppender, planName, kymaVersion string) error {
 	{
 		componentsOverrides, globalOverrides, err := ro.collectFromSecrets()
@@ -78,6 +105,10 @@ func (ro *runtimeOverrides) collectFromSecrets() (map[string][]*gqlschema.Config
 	}
 
 	for _, secret := range secrets.Items {
+		if !ro.secretAllowed(secret) {
+			ro.log.Warnf("Skipping overrides from secret %s/%s, it does not match the allowed secrets %s", secret.Namespace, secret.Name, strings.Join(ro.allowedSecrets, ", "))
+			continue
+		}
 		component, global := getComponent(secret.Labels)
 		for key, value := range secret.Data {
 			if global {
@@ -99,6 +130,22 @@ func (ro *runtimeOverrides) collectFromSecrets() (map[string][]*gqlschema.Config
 	return componentsOverrides, globalOverrides, nil
 }
 
+func (ro *runtimeOverrides) secretAllowed(secret coreV1.Secret) bool {
+	if len(ro.allowedSecrets) == 0 {
+		return true
+	}
+	for _, pattern := range ro.allowedSecrets {
+		name := secret.Name
+		if strings.Contains(pattern, "/") {
+			name = fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (ro *runtimeOverrides) collectFromConfigMaps(planName, kymaVersion string) (map[string][]*gqlschema.ConfigEntryInput, []*gqlschema.ConfigEntryInput, error) {
 	componentsOverrides := make(map[string][]*gqlschema.ConfigEntryInput, 0)
 	globalOverrides := make([]*gqlschema.ConfigEntryInput, 0)
@@ -7,6 +7,8 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtimeoverrides/automock"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -153,6 +155,60 @@ func TestRuntimeOverrides_Append(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Success when only the allowed Secrets are read", func(t *testing.T) {
+		// GIVEN
+		sch := runtime.NewScheme()
+		require.NoError(t, coreV1.AddToScheme(sch))
+		client := fake.NewFakeClientWithScheme(sch, fixResources()...)
+
+		inputAppenderMock := &automock.InputAppender{}
+		defer inputAppenderMock.AssertExpectations(t)
+		inputAppenderMock.On("AppendOverrides", "core", []*gqlschema.ConfigEntryInput{
+			{
+				Key:    "test1",
+				Value:  "test1abc",
+				Secret: ptr.Bool(true),
+			},
+		}).Return(nil).Once()
+		inputAppenderMock.On("AppendGlobalOverrides", []*gqlschema.ConfigEntryInput{
+			{
+				Key:    "test4",
+				Value:  "test4abc",
+				Secret: ptr.Bool(true),
+			},
+		}).Return(nil).Once()
+		inputAppenderMock.On("AppendOverrides", "core", []*gqlschema.ConfigEntryInput{
+			{
+				Key:   "test5",
+				Value: "test5abc",
+			},
+		}).Return(nil).Once()
+		inputAppenderMock.On("AppendGlobalOverrides", []*gqlschema.ConfigEntryInput{
+			{
+				Key:   "test7",
+				Value: "test7abc",
+			},
+		}).Return(nil).Once()
+
+		runtimeOverrides, err := NewRuntimeOverridesWithConfig(context.TODO(), client, Config{AllowedSecrets: []string{"secret#1", "kcp-system/secret#4"}}, logrus.New())
+		require.NoError(t, err)
+
+		// WHEN
+		err = runtimeOverrides.Append(inputAppenderMock, "foo", "1.15.1")
+
+		// THEN
+		require.NoError(t, err)
+		inputAppenderMock.AssertNotCalled(t, "AppendOverrides", "helm", mock.Anything)
+	})
+
+	t.Run("Error when the pattern of the allowed Secrets is invalid", func(t *testing.T) {
+		// WHEN
+		_, err := NewRuntimeOverridesWithConfig(context.TODO(), nil, Config{AllowedSecrets: []string{"secret["}}, logrus.New())
+
+		// THEN
+		require.Error(t, err)
+	})
+
 	t.Run("Error when there is no ConfigMap with overrides present", func(t *testing.T) {
 		// GIVEN
 		sch := runtime.NewScheme()
@@ -19,6 +19,7 @@ type Config struct {
 
 	SecretKey string `envconfig:"optional"`
 
+	// connection pool settings applied to the DB connection, the sqlstats collector reports on the tuned pool
 	MaxOpenConns    int           `envconfig:"default=8"`
 	MaxIdleConns    int           `envconfig:"default=2"`
 	ConnMaxLifetime time.Duration `envconfig:"default=30m"`
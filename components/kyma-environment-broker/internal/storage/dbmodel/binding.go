@@ -0,0 +1,22 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type BindingDTO struct {
+	ID         string
+	InstanceID string
+
+	OperationID   string
+	OperationType string
+	State         string
+	Description   string
+
+	Credentials string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Version int
+}
@@ -0,0 +1,11 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type CatalogSnapshotDTO struct {
+	Etag      string
+	Catalog   string
+	CreatedAt time.Time
+}
@@ -44,6 +44,8 @@ type InstanceDTO struct {
 	DashboardURL           string
 	ProvisioningParameters string
 	ProviderRegion         string
+	SuspensionReason       string
+	CustomDomain           string
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -0,0 +1,11 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type InstanceFlagsDTO struct {
+	InstanceID string
+	Flags      string
+	UpdatedAt  time.Time
+}
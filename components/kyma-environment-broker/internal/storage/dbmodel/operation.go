@@ -28,6 +28,10 @@ type OperationDTO struct {
 	State                  string
 	Description            string
 	FinishedStages         sql.NullString
+	CurrentStep            sql.NullString
+	CorrelationID          sql.NullString
+	TraceParent            sql.NullString
+	FailureReason          sql.NullString
 	ProvisioningParameters sql.NullString
 
 	Type internal.OperationType
@@ -43,3 +47,18 @@ type InstanceByGlobalAccountIDStatEntry struct {
 	GlobalAccountID string
 	Total           int
 }
+
+type InstanceStateCountEntry struct {
+	ServicePlanID string
+	Type          string
+	State         string
+	Total         int
+}
+
+type InstanceStateEntry struct {
+	InstanceID     string
+	ServicePlanID  string
+	ProviderRegion string
+	Type           string
+	State          string
+}
@@ -0,0 +1,12 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type SubsystemMarkerDTO struct {
+	InstanceID  string
+	Subsystem   string
+	OperationID string
+	CreatedAt   time.Time
+}
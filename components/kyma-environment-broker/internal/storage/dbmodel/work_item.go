@@ -0,0 +1,13 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type WorkItemDTO struct {
+	Queue        string
+	OperationID  string
+	SubAccountID string
+	Priority     int
+	CreatedAt    time.Time
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type bindings struct {
+	mu sync.Mutex
+
+	data map[string]internal.Binding
+}
+
+func NewBindings() *bindings {
+	return &bindings{
+		data: make(map[string]internal.Binding),
+	}
+}
+
+func (s *bindings) Insert(binding internal.Binding) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data[binding.ID]; exists {
+		return dberr.AlreadyExists("binding with id %s already exist", binding.ID)
+	}
+	s.data[binding.ID] = binding
+
+	return nil
+}
+
+func (s *bindings) Update(binding internal.Binding) (*internal.Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, exists := s.data[binding.ID]
+	if !exists {
+		return nil, dberr.NotFound("binding %s not found", binding.ID)
+	}
+	if old.Version != binding.Version {
+		return nil, dberr.Conflict("unable to update binding %s - conflict", binding.ID)
+	}
+	binding.Version = binding.Version + 1
+	s.data[binding.ID] = binding
+
+	return &binding, nil
+}
+
+func (s *bindings) GetByID(bindingID string) (*internal.Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	binding, exists := s.data[bindingID]
+	if !exists {
+		return nil, dberr.NotFound("binding with id %s not exist", bindingID)
+	}
+
+	return &binding, nil
+}
+
+func (s *bindings) Delete(bindingID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, bindingID)
+	return nil
+}
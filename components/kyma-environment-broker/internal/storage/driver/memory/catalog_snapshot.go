@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type catalogSnapshots struct {
+	mu sync.Mutex
+
+	data map[string]internal.CatalogSnapshot
+}
+
+func NewCatalogSnapshots() *catalogSnapshots {
+	return &catalogSnapshots{
+		data: make(map[string]internal.CatalogSnapshot),
+	}
+}
+
+func (s *catalogSnapshots) Save(snapshot internal.CatalogSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[snapshot.ETag] = snapshot
+	return nil
+}
+
+func (s *catalogSnapshots) GetByETag(etag string) (*internal.CatalogSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, found := s.data[etag]
+	if !found {
+		return nil, dberr.NotFound("catalog snapshot with ETag %s not found", etag)
+	}
+	return &snapshot, nil
+}
+
+func (s *catalogSnapshots) DeleteAllExceptNewest(count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := make([]internal.CatalogSnapshot, 0, len(s.data))
+	for _, snapshot := range s.data {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	for i := count; i < len(snapshots); i++ {
+		delete(s.data, snapshots[i].ETag)
+	}
+
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"fmt"
 
@@ -88,7 +90,7 @@ func (s *instances) FindAllJoinedWithOperations(prct ...predicate.Predicate) ([]
 	}
 
 	for _, p := range prct {
-		p.ApplyToInMemory(instances)
+		instances = p.ApplyToInMemory(instances)
 	}
 
 	return instances, nil
@@ -136,6 +138,54 @@ func (s *instances) GetNumberOfInstancesForGlobalAccountID(globalAccountID strin
 	return numberOfInstances, nil
 }
 
+// GetNumberOfInstancesForSubAccountAndPlan counts the active instances, the instances which last operation
+// is the deprovisioning which did not fail are not counted unless the deprovisioning is the suspension
+func (s *instances) GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.numberOfInstancesForSubAccountAndPlan(subAccountID, planID)
+}
+
+func (s *instances) numberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error) {
+	numberOfInstances := 0
+	for id, inst := range s.instances {
+		if inst.SubAccountID != subAccountID || inst.ServicePlanID != planID {
+			continue
+		}
+		active, err := s.isActive(id)
+		if err != nil {
+			return 0, err
+		}
+		if active {
+			numberOfInstances++
+		}
+	}
+	return numberOfInstances, nil
+}
+
+func (s *instances) isActive(instanceID string) (bool, error) {
+	lastOp, err := s.operationsStorage.GetLastOperation(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return true, nil
+	case err != nil:
+		return false, err
+	case lastOp.State == domain.Failed:
+		return true, nil
+	}
+
+	deprovisioning, err := s.operationsStorage.GetDeprovisioningOperationByID(lastOp.ID)
+	switch {
+	case dberr.IsNotFound(err):
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	// the suspended instance can be resumed anytime
+	return deprovisioning.Temporary, nil
+}
+
 func (s *instances) GetByID(instanceID string) (*internal.Instance, error) {
 	inst, ok := s.instances[instanceID]
 	if !ok {
@@ -145,6 +195,32 @@ func (s *instances) GetByID(instanceID string) (*internal.Instance, error) {
 	return &inst, nil
 }
 
+func (s *instances) GetByCustomDomain(customDomain string) (*internal.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, inst := range s.instances {
+		if inst.CustomDomain() != "" && strings.EqualFold(inst.CustomDomain(), customDomain) {
+			return &inst, nil
+		}
+	}
+	return nil, dberr.NotFound("instance with custom domain %s not exist", customDomain)
+}
+
+// checkCustomDomain returns an error if the custom domain of the instance is used by another instance,
+// the same as the unique index of the custom domains in the database
+func (s *instances) checkCustomDomain(instance internal.Instance) error {
+	if instance.CustomDomain() == "" {
+		return nil
+	}
+	for id, inst := range s.instances {
+		if id != instance.InstanceID && inst.CustomDomain() == instance.CustomDomain() {
+			return dberr.AlreadyExists("instance with custom domain %s already exist", instance.CustomDomain())
+		}
+	}
+	return nil
+}
+
 func (s *instances) Delete(instanceID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -156,11 +232,33 @@ func (s *instances) Delete(instanceID string) error {
 func (s *instances) Insert(instance internal.Instance) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := s.checkCustomDomain(instance); err != nil {
+		return err
+	}
 	s.instances[instance.InstanceID] = instance
 
 	return nil
 }
 
+func (s *instances) InsertUnlessDuplicated(instance internal.Instance) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, err := s.numberOfInstancesForSubAccountAndPlan(instance.SubAccountID, instance.ServicePlanID)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	if err := s.checkCustomDomain(instance); err != nil {
+		return false, err
+	}
+	s.instances[instance.InstanceID] = instance
+
+	return true, nil
+}
+
 func (s *instances) Update(instance internal.Instance) (*internal.Instance, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -181,6 +279,46 @@ func (s *instances) GetInstanceStats() (internal.InstanceStats, error) {
 	return internal.InstanceStats{}, fmt.Errorf("not implemented")
 }
 
+func (s *instances) GetInstanceStates() ([]internal.InstanceState, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *instances) GetInstanceStateCountsForSubAccount(subAccountID string) ([]internal.InstanceStateCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := map[internal.InstanceStateCount]int{}
+	for id, inst := range s.instances {
+		if inst.SubAccountID != subAccountID {
+			continue
+		}
+		key := internal.InstanceStateCount{ServicePlanID: inst.ServicePlanID}
+		var lastCreatedAt time.Time
+		pOp, err := s.operationsStorage.GetProvisioningOperationByInstanceID(id)
+		switch {
+		case err == nil:
+			key.OperationType, key.OperationState, lastCreatedAt = internal.OperationTypeProvision, pOp.State, pOp.CreatedAt
+		case !dberr.IsNotFound(err):
+			return nil, err
+		}
+		dOp, err := s.operationsStorage.GetDeprovisioningOperationByInstanceID(id)
+		switch {
+		case err == nil && !dOp.CreatedAt.Before(lastCreatedAt):
+			key.OperationType, key.OperationState = internal.OperationTypeDeprovision, dOp.State
+		case err != nil && !dberr.IsNotFound(err):
+			return nil, err
+		}
+		counts[key]++
+	}
+
+	result := make([]internal.InstanceStateCount, 0, len(counts))
+	for key, total := range counts {
+		key.Total = total
+		result = append(result, key)
+	}
+	return result, nil
+}
+
 func (s *instances) List(filter dbmodel.InstanceFilter) ([]internal.Instance, int, int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
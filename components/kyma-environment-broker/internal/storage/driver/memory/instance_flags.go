@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"sync"
+)
+
+type instanceFlags struct {
+	mu sync.Mutex
+
+	data map[string]map[string]bool
+}
+
+func NewInstanceFlags() *instanceFlags {
+	return &instanceFlags{
+		data: make(map[string]map[string]bool),
+	}
+}
+
+func (s *instanceFlags) GetByInstanceID(instanceID string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := make(map[string]bool, len(s.data[instanceID]))
+	for flag, enabled := range s.data[instanceID] {
+		flags[flag] = enabled
+	}
+
+	return flags, nil
+}
+
+func (s *instanceFlags) Set(instanceID string, flags map[string]bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := make(map[string]bool, len(flags))
+	for flag, enabled := range flags {
+		stored[flag] = enabled
+	}
+	s.data[instanceID] = stored
+
+	return nil
+}
@@ -481,6 +481,35 @@ func (s *operations) ListOperations(filter dbmodel.OperationFilter) ([]internal.
 		nil
 }
 
+func (s *operations) ListByStepAndState(step string, state domain.LastOperationState, filter dbmodel.OperationFilter) ([]internal.Operation, int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.Operation, 0)
+	offset := pagination.ConvertPageAndPageSizeToOffset(filter.PageSize, filter.Page)
+
+	all, err := s.getAll()
+	if err != nil && !dberr.IsNotFound(err) {
+		return nil, 0, 0, errors.Wrap(err, "while listing operations")
+	}
+	operations := make([]internal.Operation, 0)
+	for _, op := range all {
+		if op.CurrentStep == step && op.State == state {
+			operations = append(operations, op)
+		}
+	}
+	s.sortByCreatedAt(operations)
+
+	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(operations); i++ {
+		result = append(result, operations[i])
+	}
+
+	return result,
+		len(result),
+		len(operations),
+		nil
+}
+
 func (s *operations) ListUpgradeKymaOperations() ([]internal.UpgradeKymaOperation, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
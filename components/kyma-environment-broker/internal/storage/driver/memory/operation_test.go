@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperations_ListByStepAndState(t *testing.T) {
+	// given
+	svc := NewOperation()
+
+	provisioning := fixture.FixProvisioningOperation("provisioning-stuck", "inst-1")
+	provisioning.CurrentStep = "Create_IAS_Application"
+	provisioning.State = domain.InProgress
+	require.NoError(t, svc.InsertProvisioningOperation(provisioning))
+
+	deprovisioning := fixture.FixDeprovisioningOperation("deprovisioning-stuck", "inst-2")
+	deprovisioning.CurrentStep = "Create_IAS_Application"
+	deprovisioning.State = domain.InProgress
+	deprovisioning.CreatedAt = deprovisioning.CreatedAt.Add(time.Minute)
+	require.NoError(t, svc.InsertDeprovisioningOperation(deprovisioning))
+
+	failed := fixture.FixProvisioningOperation("failed", "inst-3")
+	failed.CurrentStep = "Create_IAS_Application"
+	failed.State = domain.Failed
+	require.NoError(t, svc.InsertProvisioningOperation(failed))
+
+	otherStep := fixture.FixProvisioningOperation("other-step", "inst-4")
+	otherStep.CurrentStep = "Create_Runtime"
+	otherStep.State = domain.InProgress
+	require.NoError(t, svc.InsertProvisioningOperation(otherStep))
+
+	// when
+	ops, count, totalCount, err := svc.ListByStepAndState("Create_IAS_Application", domain.InProgress, dbmodel.OperationFilter{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, totalCount)
+	require.Len(t, ops, 2)
+	assert.Equal(t, "provisioning-stuck", ops[0].ID)
+	assert.Equal(t, "deprovisioning-stuck", ops[1].ID)
+
+	// when
+	ops, count, totalCount, err = svc.ListByStepAndState("Create_IAS_Application", domain.InProgress, dbmodel.OperationFilter{Page: 2, PageSize: 1})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 2, totalCount)
+	assert.Equal(t, "deprovisioning-stuck", ops[0].ID)
+
+	// when
+	ops, count, totalCount, err = svc.ListByStepAndState("Deregister_Runtime", domain.InProgress, dbmodel.OperationFilter{})
+
+	// then
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+	assert.Zero(t, count)
+	assert.Zero(t, totalCount)
+}
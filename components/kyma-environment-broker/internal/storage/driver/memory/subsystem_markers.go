@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+type subsystemMarkers struct {
+	mu sync.Mutex
+
+	// data holds the subsystems marked for the instance
+	data      map[string]map[string]internal.SubsystemMarker
+	instances *instances
+}
+
+func NewSubsystemMarkers(instances *instances) *subsystemMarkers {
+	return &subsystemMarkers{
+		data:      make(map[string]map[string]internal.SubsystemMarker),
+		instances: instances,
+	}
+}
+
+func (s *subsystemMarkers) Insert(marker internal.SubsystemMarker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	markers, found := s.data[marker.InstanceID]
+	if !found {
+		markers = make(map[string]internal.SubsystemMarker)
+		s.data[marker.InstanceID] = markers
+	}
+	if _, found := markers[marker.Subsystem]; !found {
+		markers[marker.Subsystem] = marker
+	}
+
+	return nil
+}
+
+func (s *subsystemMarkers) ListInstancesWithout(subsystem string) ([]internal.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances.mu.Lock()
+	defer s.instances.mu.Unlock()
+
+	result := make([]internal.Instance, 0)
+	for id, instance := range s.instances.instances {
+		if _, found := s.data[id][subsystem]; !found {
+			result = append(result, instance)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (s *subsystemMarkers) Delete(instanceID, subsystem string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[instanceID], subsystem)
+
+	return nil
+}
+
+func (s *subsystemMarkers) CountBySubAccount(subsystem, subAccountID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances.mu.Lock()
+	defer s.instances.mu.Unlock()
+
+	count := 0
+	for id, instance := range s.instances.instances {
+		if instance.SubAccountID != subAccountID {
+			continue
+		}
+		if _, found := s.data[id][subsystem]; found {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+type workItems struct {
+	mu sync.Mutex
+
+	data map[string]map[string]internal.WorkItem
+}
+
+func NewWorkItems() *workItems {
+	return &workItems{
+		data: make(map[string]map[string]internal.WorkItem),
+	}
+}
+
+func (s *workItems) Insert(item internal.WorkItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data[item.Queue]; !exists {
+		s.data[item.Queue] = make(map[string]internal.WorkItem)
+	}
+	if _, exists := s.data[item.Queue][item.OperationID]; !exists {
+		s.data[item.Queue][item.OperationID] = item
+	}
+
+	return nil
+}
+
+func (s *workItems) Delete(queue, operationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data[queue], operationID)
+	return nil
+}
+
+func (s *workItems) ListByQueue(queue string) ([]internal.WorkItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]internal.WorkItem, 0, len(s.data[queue]))
+	for _, item := range s.data[queue] {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+
+	return items, nil
+}
@@ -0,0 +1,117 @@
+package postsql
+
+import (
+	"encoding/json"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+)
+
+type bindings struct {
+	postsql.Factory
+
+	cipher Cipher
+}
+
+func NewBindings(sessionFactory postsql.Factory, cipher Cipher) *bindings {
+	return &bindings{
+		Factory: sessionFactory,
+		cipher:  cipher,
+	}
+}
+
+func (s *bindings) Insert(binding internal.Binding) error {
+	dto, err := s.toBindingDTO(binding)
+	if err != nil {
+		return err
+	}
+
+	return s.NewWriteSession().InsertBinding(dto)
+}
+
+func (s *bindings) Update(binding internal.Binding) (*internal.Binding, error) {
+	dto, err := s.toBindingDTO(binding)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.NewWriteSession().UpdateBinding(dto); err != nil {
+		return nil, err
+	}
+	binding.Version = binding.Version + 1
+
+	return &binding, nil
+}
+
+func (s *bindings) GetByID(bindingID string) (*internal.Binding, error) {
+	dto, err := s.NewReadSession().GetBindingByID(bindingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toBinding(dto)
+}
+
+func (s *bindings) Delete(bindingID string) error {
+	return s.NewWriteSession().DeleteBinding(bindingID)
+}
+
+func (s *bindings) toBindingDTO(binding internal.Binding) (dbmodel.BindingDTO, error) {
+	dto := dbmodel.BindingDTO{
+		ID:            binding.ID,
+		InstanceID:    binding.InstanceID,
+		OperationID:   binding.OperationID,
+		OperationType: string(binding.OperationType),
+		State:         string(binding.State),
+		Description:   binding.Description,
+		CreatedAt:     binding.CreatedAt,
+		UpdatedAt:     binding.UpdatedAt,
+		Version:       binding.Version,
+	}
+	if len(binding.Credentials) == 0 {
+		return dto, nil
+	}
+
+	credentials, err := json.Marshal(binding.Credentials)
+	if err != nil {
+		return dbmodel.BindingDTO{}, errors.Wrap(err, "while marshaling binding credentials")
+	}
+	encrypted, err := s.cipher.Encrypt(credentials)
+	if err != nil {
+		return dbmodel.BindingDTO{}, errors.Wrap(err, "while encrypting binding credentials")
+	}
+	dto.Credentials = string(encrypted)
+
+	return dto, nil
+}
+
+func (s *bindings) toBinding(dto dbmodel.BindingDTO) (*internal.Binding, error) {
+	binding := &internal.Binding{
+		ID:            dto.ID,
+		InstanceID:    dto.InstanceID,
+		OperationID:   dto.OperationID,
+		OperationType: internal.BindingOperationType(dto.OperationType),
+		State:         domain.LastOperationState(dto.State),
+		Description:   dto.Description,
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+		Version:       dto.Version,
+	}
+	if dto.Credentials == "" {
+		return binding, nil
+	}
+
+	decrypted, err := s.cipher.Decrypt([]byte(dto.Credentials))
+	if err != nil {
+		return nil, errors.Wrap(err, "while decrypting binding credentials")
+	}
+	if err := json.Unmarshal(decrypted, &binding.Credentials); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling binding credentials")
+	}
+
+	return binding, nil
+}
@@ -0,0 +1,74 @@
+package postsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindings(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("Bindings", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		binding := internal.Binding{
+			ID:            "binding-001",
+			InstanceID:    "instance-001",
+			OperationID:   "operation-001",
+			OperationType: internal.BindingOperationTypeBind,
+			State:         domain.InProgress,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+
+		svc := brokerStorage.Bindings()
+
+		// when
+		err = svc.Insert(binding)
+		require.NoError(t, err)
+
+		binding.State = domain.Succeeded
+		binding.Credentials = map[string]interface{}{"password": "secret"}
+		updated, err := svc.Update(binding)
+		require.NoError(t, err)
+
+		_, conflictErr := svc.Update(binding)
+		got, err := svc.GetByID(binding.ID)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 1, updated.Version)
+		assert.True(t, dberr.IsConflict(conflictErr))
+		assert.Equal(t, domain.Succeeded, got.State)
+		assert.Equal(t, "secret", got.Credentials["password"])
+
+		// when
+		err = svc.Delete(binding.ID)
+		require.NoError(t, err)
+		_, err = svc.GetByID(binding.ID)
+
+		// then
+		assert.True(t, dberr.IsNotFound(err))
+	})
+}
@@ -0,0 +1,54 @@
+package postsql
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+)
+
+type catalogSnapshots struct {
+	postsql.Factory
+}
+
+func NewCatalogSnapshots(sessionFactory postsql.Factory) *catalogSnapshots {
+	return &catalogSnapshots{
+		Factory: sessionFactory,
+	}
+}
+
+func (s *catalogSnapshots) Save(snapshot internal.CatalogSnapshot) error {
+	dto := dbmodel.CatalogSnapshotDTO{
+		Etag:      snapshot.ETag,
+		Catalog:   snapshot.Catalog,
+		CreatedAt: snapshot.CreatedAt,
+	}
+
+	session := s.NewWriteSession()
+	dbErr := session.InsertCatalogSnapshot(dto)
+	if dbErr != nil && dbErr.Code() == dberr.CodeAlreadyExists {
+		dbErr = session.UpdateCatalogSnapshot(dto)
+	}
+	if dbErr != nil {
+		return dbErr
+	}
+
+	return nil
+}
+
+func (s *catalogSnapshots) GetByETag(etag string) (*internal.CatalogSnapshot, error) {
+	dto, err := s.NewReadSession().GetCatalogSnapshot(etag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &internal.CatalogSnapshot{
+		ETag:      dto.Etag,
+		Catalog:   dto.Catalog,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
+
+func (s *catalogSnapshots) DeleteAllExceptNewest(count int) error {
+	return s.NewWriteSession().DeleteCatalogSnapshotsExceptNewest(count)
+}
@@ -0,0 +1,54 @@
+package postsql_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionPool(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("should apply connection pool settings", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		cfg.MaxOpenConns = 3
+		cfg.MaxIdleConns = 1
+		cfg.ConnMaxLifetime = time.Hour
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, connection, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer storage.CloseDatabase(t, connection)
+
+		assert.Equal(t, 3, connection.Stats().MaxOpenConnections)
+
+		// open more connections than the pool allows, the exceeding ones wait for a free connection
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := connection.Exec("SELECT pg_sleep(0.2)")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		stats := connection.Stats()
+		assert.LessOrEqual(t, stats.OpenConnections, 1)
+		assert.LessOrEqual(t, stats.Idle, 1)
+		assert.Greater(t, stats.WaitCount, int64(0))
+		assert.Greater(t, stats.MaxIdleClosed, int64(0))
+	})
+}
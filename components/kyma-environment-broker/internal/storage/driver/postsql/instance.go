@@ -2,12 +2,14 @@ package postsql
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/predicate"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -48,6 +50,8 @@ func (s *Instance) InsertWithoutEncryption(instance internal.Instance) error {
 		DashboardURL:           instance.DashboardURL,
 		ProvisioningParameters: string(params),
 		ProviderRegion:         instance.ProviderRegion,
+		SuspensionReason:       instance.SuspensionReason,
+		CustomDomain:           instance.CustomDomain(),
 		CreatedAt:              instance.CreatedAt,
 		UpdatedAt:              instance.UpdatedAt,
 		DeletedAt:              instance.DeletedAt,
@@ -78,21 +82,22 @@ func (s *Instance) ListWithoutDecryption(filter dbmodel.InstanceFilter) ([]inter
 			return nil, 0, 0, errors.Wrap(err, "while unmarshal parameters")
 		}
 		instance := internal.Instance{
-			InstanceID:      dto.InstanceID,
-			RuntimeID:       dto.RuntimeID,
-			GlobalAccountID: dto.GlobalAccountID,
-			SubAccountID:    dto.SubAccountID,
-			ServiceID:       dto.ServiceID,
-			ServiceName:     dto.ServiceName,
-			ServicePlanID:   dto.ServicePlanID,
-			ServicePlanName: dto.ServicePlanName,
-			DashboardURL:    dto.DashboardURL,
-			Parameters:      params,
-			ProviderRegion:  dto.ProviderRegion,
-			CreatedAt:       dto.CreatedAt,
-			UpdatedAt:       dto.UpdatedAt,
-			DeletedAt:       dto.DeletedAt,
-			Version:         dto.Version,
+			InstanceID:       dto.InstanceID,
+			RuntimeID:        dto.RuntimeID,
+			GlobalAccountID:  dto.GlobalAccountID,
+			SubAccountID:     dto.SubAccountID,
+			ServiceID:        dto.ServiceID,
+			ServiceName:      dto.ServiceName,
+			ServicePlanID:    dto.ServicePlanID,
+			ServicePlanName:  dto.ServicePlanName,
+			DashboardURL:     dto.DashboardURL,
+			Parameters:       params,
+			ProviderRegion:   dto.ProviderRegion,
+			SuspensionReason: dto.SuspensionReason,
+			CreatedAt:        dto.CreatedAt,
+			UpdatedAt:        dto.UpdatedAt,
+			DeletedAt:        dto.DeletedAt,
+			Version:          dto.Version,
 		}
 		instances = append(instances, instance)
 	}
@@ -117,6 +122,8 @@ func (s *Instance) UpdateWithoutEncryption(instance internal.Instance) (*interna
 		DashboardURL:           instance.DashboardURL,
 		ProvisioningParameters: string(params),
 		ProviderRegion:         instance.ProviderRegion,
+		SuspensionReason:       instance.SuspensionReason,
+		CustomDomain:           instance.CustomDomain(),
 		CreatedAt:              instance.CreatedAt,
 		UpdatedAt:              instance.UpdatedAt,
 		DeletedAt:              instance.DeletedAt,
@@ -260,6 +267,17 @@ func (s *Instance) GetNumberOfInstancesForGlobalAccountID(globalAccountID string
 	return result, err
 }
 
+func (s *Instance) GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error) {
+	sess := s.NewReadSession()
+	var result int
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		count, err := sess.GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID)
+		result = count
+		return err == nil, nil
+	})
+	return result, err
+}
+
 // TODO: Wrap retries in single method WithRetries
 func (s *Instance) GetByID(instanceID string) (*internal.Instance, error) {
 	sess := s.NewReadSession()
@@ -296,6 +314,32 @@ func (s *Instance) GetByID(instanceID string) (*internal.Instance, error) {
 	return &instance, nil
 }
 
+func (s *Instance) GetByCustomDomain(customDomain string) (*internal.Instance, error) {
+	sess := s.NewReadSession()
+	instanceDTO := dbmodel.InstanceDTO{}
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		instanceDTO, lastErr = sess.GetInstanceByCustomDomain(strings.ToLower(customDomain))
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("Instance with custom domain %s not exist", customDomain)
+			}
+			log.Errorf("while getting instanceDTO by custom domain %s: %v", customDomain, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+	instance, err := s.toInstance(instanceDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instance, nil
+}
+
 func (s *Instance) toInstance(dto dbmodel.InstanceDTO) (internal.Instance, error) {
 	var params internal.ProvisioningParameters
 	err := json.Unmarshal([]byte(dto.ProvisioningParameters), &params)
@@ -307,21 +351,22 @@ func (s *Instance) toInstance(dto dbmodel.InstanceDTO) (internal.Instance, error
 		return internal.Instance{}, errors.Wrap(err, "while decrypting parameters")
 	}
 	return internal.Instance{
-		InstanceID:      dto.InstanceID,
-		RuntimeID:       dto.RuntimeID,
-		GlobalAccountID: dto.GlobalAccountID,
-		SubAccountID:    dto.SubAccountID,
-		ServiceID:       dto.ServiceID,
-		ServiceName:     dto.ServiceName,
-		ServicePlanID:   dto.ServicePlanID,
-		ServicePlanName: dto.ServicePlanName,
-		DashboardURL:    dto.DashboardURL,
-		Parameters:      params,
-		ProviderRegion:  dto.ProviderRegion,
-		CreatedAt:       dto.CreatedAt,
-		UpdatedAt:       dto.UpdatedAt,
-		DeletedAt:       dto.DeletedAt,
-		Version:         dto.Version,
+		InstanceID:       dto.InstanceID,
+		RuntimeID:        dto.RuntimeID,
+		GlobalAccountID:  dto.GlobalAccountID,
+		SubAccountID:     dto.SubAccountID,
+		ServiceID:        dto.ServiceID,
+		ServiceName:      dto.ServiceName,
+		ServicePlanID:    dto.ServicePlanID,
+		ServicePlanName:  dto.ServicePlanName,
+		DashboardURL:     dto.DashboardURL,
+		Parameters:       params,
+		ProviderRegion:   dto.ProviderRegion,
+		SuspensionReason: dto.SuspensionReason,
+		CreatedAt:        dto.CreatedAt,
+		UpdatedAt:        dto.UpdatedAt,
+		DeletedAt:        dto.DeletedAt,
+		Version:          dto.Version,
 	}, nil
 }
 
@@ -339,6 +384,10 @@ func (s *Instance) Insert(instance internal.Instance) error {
 	sess := s.NewWriteSession()
 	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		err := sess.InsertInstance(dto)
+		if err != nil && err.Code() == dberr.CodeAlreadyExists {
+			// the custom domain is used by another instance, repeating the insert does not help
+			return false, err
+		}
 		if err != nil {
 			log.Errorf("while saving instance ID %s: %v", instance.InstanceID, err)
 			return false, nil
@@ -347,6 +396,40 @@ func (s *Instance) Insert(instance internal.Instance) error {
 	})
 }
 
+// InsertUnlessDuplicated checks the active instances of the subaccount in the plan and inserts the instance within
+// the transaction holding the lock of the subaccount in the plan, so the concurrent inserts are serialized across all broker replicas
+func (s *Instance) InsertUnlessDuplicated(instance internal.Instance) (bool, error) {
+	dto, err := s.toInstanceDTO(instance)
+	if err != nil {
+		return false, err
+	}
+
+	session, dbErr := s.NewSessionWithinTransaction()
+	if dbErr != nil {
+		return false, dbErr
+	}
+	defer session.RollbackUnlessCommitted()
+
+	if dbErr := session.LockSubAccountPlan(instance.SubAccountID, instance.ServicePlanID); dbErr != nil {
+		return false, dbErr
+	}
+	count, dbErr := session.GetNumberOfInstancesForSubAccountAndPlan(instance.SubAccountID, instance.ServicePlanID)
+	if dbErr != nil {
+		return false, dbErr
+	}
+	if count > 0 {
+		return false, nil
+	}
+	if dbErr := session.InsertInstance(dto); dbErr != nil {
+		return false, dbErr
+	}
+	if dbErr := session.Commit(); dbErr != nil {
+		return false, dbErr
+	}
+
+	return true, nil
+}
+
 func (s *Instance) Update(instance internal.Instance) (*internal.Instance, error) {
 	sess := s.NewWriteSession()
 	dto, err := s.toInstanceDTO(instance)
@@ -405,6 +488,8 @@ func (s *Instance) toInstanceDTO(instance internal.Instance) (dbmodel.InstanceDT
 		DashboardURL:           instance.DashboardURL,
 		ProvisioningParameters: string(params),
 		ProviderRegion:         instance.ProviderRegion,
+		SuspensionReason:       instance.SuspensionReason,
+		CustomDomain:           instance.CustomDomain(),
 		CreatedAt:              instance.CreatedAt,
 		UpdatedAt:              instance.UpdatedAt,
 		DeletedAt:              instance.DeletedAt,
@@ -433,6 +518,43 @@ func (s *Instance) GetInstanceStats() (internal.InstanceStats, error) {
 	return result, nil
 }
 
+func (s *Instance) GetInstanceStates() ([]internal.InstanceState, error) {
+	entries, err := s.NewReadSession().GetInstanceStates()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]internal.InstanceState, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, internal.InstanceState{
+			InstanceID:     e.InstanceID,
+			ServicePlanID:  e.ServicePlanID,
+			ProviderRegion: e.ProviderRegion,
+			OperationType:  internal.OperationType(e.Type),
+			OperationState: domain.LastOperationState(e.State),
+		})
+	}
+	return result, nil
+}
+
+func (s *Instance) GetInstanceStateCountsForSubAccount(subAccountID string) ([]internal.InstanceStateCount, error) {
+	entries, err := s.NewReadSession().GetInstanceStateCountsForSubAccount(subAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]internal.InstanceStateCount, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, internal.InstanceStateCount{
+			ServicePlanID:  e.ServicePlanID,
+			OperationType:  internal.OperationType(e.Type),
+			OperationState: domain.LastOperationState(e.State),
+			Total:          e.Total,
+		})
+	}
+	return result, nil
+}
+
 func (s *Instance) List(filter dbmodel.InstanceFilter) ([]internal.Instance, int, int, error) {
 	dtos, count, totalCount, err := s.NewReadSession().ListInstances(filter)
 	if err != nil {
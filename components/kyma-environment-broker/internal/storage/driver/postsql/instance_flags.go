@@ -0,0 +1,65 @@
+package postsql
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+
+	"github.com/pkg/errors"
+)
+
+type instanceFlags struct {
+	postsql.Factory
+}
+
+func NewInstanceFlags(sessionFactory postsql.Factory) *instanceFlags {
+	return &instanceFlags{
+		Factory: sessionFactory,
+	}
+}
+
+func (s *instanceFlags) GetByInstanceID(instanceID string) (map[string]bool, error) {
+	dto, dbErr := s.NewReadSession().GetInstanceFlags(instanceID)
+	switch {
+	case dbErr != nil && dbErr.Code() == dberr.CodeNotFound:
+		return map[string]bool{}, nil
+	case dbErr != nil:
+		return nil, dbErr
+	}
+
+	flags := map[string]bool{}
+	if err := json.Unmarshal([]byte(dto.Flags), &flags); err != nil {
+		return nil, errors.Wrapf(err, "while unmarshalling flags of instance %s", instanceID)
+	}
+
+	return flags, nil
+}
+
+func (s *instanceFlags) Set(instanceID string, flags map[string]bool) error {
+	if flags == nil {
+		flags = map[string]bool{}
+	}
+	marshalled, err := json.Marshal(flags)
+	if err != nil {
+		return errors.Wrapf(err, "while marshalling flags of instance %s", instanceID)
+	}
+	dto := dbmodel.InstanceFlagsDTO{
+		InstanceID: instanceID,
+		Flags:      string(marshalled),
+		UpdatedAt:  time.Now(),
+	}
+
+	session := s.NewWriteSession()
+	dbErr := session.InsertInstanceFlags(dto)
+	if dbErr != nil && dbErr.Code() == dberr.CodeAlreadyExists {
+		dbErr = session.UpdateInstanceFlags(dto)
+	}
+	if dbErr != nil {
+		return dbErr
+	}
+
+	return nil
+}
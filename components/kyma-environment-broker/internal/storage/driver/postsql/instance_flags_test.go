@@ -0,0 +1,60 @@
+package postsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceFlags(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("InstanceFlags", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		svc := brokerStorage.InstanceFlags()
+
+		// when
+		flags, err := svc.GetByInstanceID("instance-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, flags)
+
+		// when
+		require.NoError(t, svc.Set("instance-1", map[string]bool{"flag-a": true, "flag-b": false}))
+		require.NoError(t, svc.Set("instance-2", map[string]bool{"flag-a": false}))
+		flags, err = svc.GetByInstanceID("instance-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"flag-a": true, "flag-b": false}, flags)
+
+		// when
+		require.NoError(t, svc.Set("instance-1", map[string]bool{"flag-b": true}))
+		flags, err = svc.GetByInstanceID("instance-1")
+		require.NoError(t, err)
+		other, err := svc.GetByInstanceID("instance-2")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, map[string]bool{"flag-b": true}, flags)
+		assert.Equal(t, map[string]bool{"flag-a": false}, other)
+	})
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -148,6 +151,282 @@ func TestInstance(t *testing.T) {
 		assert.Equal(t, 1, numberOfInstancesC)
 	})
 
+	t.Run("Should fetch states of instances", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		// populate database with samples
+		fixInstances := []internal.Instance{
+			*fixInstance(instanceData{val: "A1"}),
+			*fixInstance(instanceData{val: "B1"}),
+			*fixInstance(instanceData{val: "C1"}),
+		}
+		for _, i := range fixInstances {
+			err = brokerStorage.Instances().Insert(i)
+			require.NoError(t, err)
+		}
+
+		provisioning := fixProvisionOperation("A1")
+		provisioning.CreatedAt = time.Now().Add(-time.Hour)
+		err = brokerStorage.Operations().InsertProvisioningOperation(provisioning)
+		require.NoError(t, err)
+		suspension := fixDeprovisionOperation("A1")
+		suspension.State = domain.Succeeded
+		err = brokerStorage.Operations().InsertDeprovisioningOperation(suspension)
+		require.NoError(t, err)
+
+		provisioning = fixProvisionOperation("B1")
+		provisioning.State = domain.InProgress
+		err = brokerStorage.Operations().InsertProvisioningOperation(provisioning)
+		require.NoError(t, err)
+		upgrade := fixUpgradeKymaOperation("B1")
+		upgrade.CreatedAt = time.Now().Add(time.Hour)
+		err = brokerStorage.Operations().InsertUpgradeKymaOperation(upgrade)
+		require.NoError(t, err)
+
+		// when
+		states, err := brokerStorage.Instances().GetInstanceStates()
+
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []internal.InstanceState{
+			{
+				InstanceID:     "A1",
+				ServicePlanID:  "A1",
+				ProviderRegion: fixture.Region,
+				OperationType:  internal.OperationTypeDeprovision,
+				OperationState: domain.Succeeded,
+			},
+			{
+				InstanceID:     "B1",
+				ServicePlanID:  "B1",
+				ProviderRegion: fixture.Region,
+				OperationType:  internal.OperationTypeProvision,
+				OperationState: domain.InProgress,
+			},
+		}, states)
+	})
+
+	t.Run("Should count instances of the subaccount in the plan", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		// populate database with samples
+		fixInstances := []internal.Instance{
+			*fixInstance(instanceData{val: "A1", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A2", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A3", subAccountID: "sa-2"}),
+			*fixInstance(instanceData{val: "A4", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A5", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A6", subAccountID: "sa-1"}),
+		}
+		fixInstances[0].ServicePlanID = "plan-a"
+		fixInstances[1].ServicePlanID = "plan-b"
+		fixInstances[2].ServicePlanID = "plan-a"
+		fixInstances[3].ServicePlanID = "plan-a"
+		fixInstances[4].ServicePlanID = "plan-a"
+		fixInstances[5].ServicePlanID = "plan-a"
+
+		for _, i := range fixInstances {
+			err = brokerStorage.Instances().Insert(i)
+			require.NoError(t, err)
+		}
+		// the instance being deprovisioned is not counted, the instance which deprovisioning failed and the suspended instance are
+		for id, deprovisioningData := range map[string]struct {
+			state     domain.LastOperationState
+			temporary bool
+		}{
+			"A4": {state: domain.InProgress},
+			"A5": {state: domain.Failed},
+			"A6": {state: domain.Succeeded, temporary: true},
+		} {
+			provisioning := fixProvisionOperation(id)
+			provisioning.CreatedAt = time.Now().Add(-time.Hour)
+			err = brokerStorage.Operations().InsertProvisioningOperation(provisioning)
+			require.NoError(t, err)
+			deprovisioning := fixDeprovisionOperation(id)
+			deprovisioning.State = deprovisioningData.state
+			deprovisioning.Temporary = deprovisioningData.temporary
+			err = brokerStorage.Operations().InsertDeprovisioningOperation(deprovisioning)
+			require.NoError(t, err)
+		}
+
+		// when
+		numberOfInstancesPlanA, err := brokerStorage.Instances().GetNumberOfInstancesForSubAccountAndPlan("sa-1", "plan-a")
+		require.NoError(t, err)
+		numberOfInstancesPlanC, err := brokerStorage.Instances().GetNumberOfInstancesForSubAccountAndPlan("sa-1", "plan-c")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 3, numberOfInstancesPlanA)
+		assert.Equal(t, 0, numberOfInstancesPlanC)
+	})
+
+	t.Run("Should insert only one of the concurrent instances of the subaccount in the plan", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		// when
+		var (
+			wg       sync.WaitGroup
+			inserted int32
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				instance := fixInstance(instanceData{val: fmt.Sprintf("A%d", i), subAccountID: "sa-1"})
+				instance.ServicePlanID = "plan-a"
+				ok, err := brokerStorage.Instances().InsertUnlessDuplicated(*instance)
+				assert.NoError(t, err)
+				if ok {
+					atomic.AddInt32(&inserted, 1)
+				}
+			}(i)
+		}
+		wg.Wait()
+		otherPlanInstance := fixInstance(instanceData{val: "B1", subAccountID: "sa-1"})
+		otherPlanInstance.ServicePlanID = "plan-b"
+		otherPlanInserted, err := brokerStorage.Instances().InsertUnlessDuplicated(*otherPlanInstance)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, int32(1), inserted)
+		numberOfInstances, err := brokerStorage.Instances().GetNumberOfInstancesForSubAccountAndPlan("sa-1", "plan-a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, numberOfInstances)
+		assert.True(t, otherPlanInserted)
+	})
+
+	t.Run("Should find instance by custom domain and reject the domain used by another instance", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		instance := fixInstance(instanceData{val: "A1"})
+		instance.Parameters.Parameters.CustomDomain = ptr.String("kyma.example.com")
+		require.NoError(t, brokerStorage.Instances().Insert(*instance))
+		require.NoError(t, brokerStorage.Instances().Insert(*fixInstance(instanceData{val: "A2"})))
+		require.NoError(t, brokerStorage.Instances().Insert(*fixInstance(instanceData{val: "A3"})))
+
+		// when
+		found, err := brokerStorage.Instances().GetByCustomDomain("Kyma.Example.com")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "A1", found.InstanceID)
+
+		// when
+		other := fixInstance(instanceData{val: "B1"})
+		other.Parameters.Parameters.CustomDomain = ptr.String("KYMA.example.com")
+		err = brokerStorage.Instances().Insert(*other)
+
+		// then
+		require.Error(t, err)
+		dbErr, ok := err.(dberr.Error)
+		require.True(t, ok)
+		assert.Equal(t, dberr.CodeAlreadyExists, dbErr.Code())
+
+		// when
+		require.NoError(t, brokerStorage.Instances().Delete("A1"))
+		_, err = brokerStorage.Instances().GetByCustomDomain("kyma.example.com")
+
+		// then
+		assert.True(t, dberr.IsNotFound(err))
+		assert.NoError(t, brokerStorage.Instances().Insert(*other))
+	})
+
+	t.Run("Should count instances of the subaccount per plan and state", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		// populate database with samples
+		fixInstances := []internal.Instance{
+			*fixInstance(instanceData{val: "A1", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A2", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A3", subAccountID: "sa-1"}),
+			*fixInstance(instanceData{val: "A4", subAccountID: "sa-2"}),
+		}
+		for _, i := range fixInstances {
+			i.ServicePlanID = "plan-a"
+			err = brokerStorage.Instances().Insert(i)
+			require.NoError(t, err)
+		}
+		for _, id := range []string{"A1", "A2", "A4"} {
+			provisioning := fixProvisionOperation(id)
+			provisioning.CreatedAt = time.Now().Add(-time.Hour)
+			err = brokerStorage.Operations().InsertProvisioningOperation(provisioning)
+			require.NoError(t, err)
+		}
+		suspension := fixDeprovisionOperation("A2")
+		suspension.State = domain.Succeeded
+		err = brokerStorage.Operations().InsertDeprovisioningOperation(suspension)
+		require.NoError(t, err)
+
+		// when
+		counts, err := brokerStorage.Instances().GetInstanceStateCountsForSubAccount("sa-1")
+		require.NoError(t, err)
+		emptyCounts, err := brokerStorage.Instances().GetInstanceStateCountsForSubAccount("sa-3")
+		require.NoError(t, err)
+
+		// then
+		assert.ElementsMatch(t, []internal.InstanceStateCount{
+			{ServicePlanID: "plan-a", OperationType: internal.OperationTypeProvision, OperationState: domain.Succeeded, Total: 1},
+			{ServicePlanID: "plan-a", OperationType: internal.OperationTypeDeprovision, OperationState: domain.Succeeded, Total: 1},
+			{ServicePlanID: "plan-a", Total: 1},
+		}, counts)
+		assert.Empty(t, emptyCounts)
+	})
+
 	t.Run("Should fetch instances along with their operations", func(t *testing.T) {
 		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
 		require.NoError(t, err)
@@ -492,7 +492,7 @@ func (s *operations) GetLastOperation(instanceID string) (*internal.Operation, e
 	if err != nil {
 		return nil, errors.New("unable to unmarshall operation data")
 	}
-	op, err = s.toOperation(&operation, op.InstanceDetails)
+	op, err = s.toOperation(&operation, op)
 	if err != nil {
 		return nil, err
 	}
@@ -524,7 +524,7 @@ func (s *operations) GetOperationByID(operationID string) (*internal.Operation,
 	if err != nil {
 		return nil, errors.New("unable to unmarshall operation data")
 	}
-	op, err = s.toOperation(&operation, op.InstanceDetails)
+	op, err = s.toOperation(&operation, op)
 	if err != nil {
 		return nil, err
 	}
@@ -632,6 +632,32 @@ func (s *operations) ListOperations(filter dbmodel.OperationFilter) ([]internal.
 	return result, size, total, err
 }
 
+func (s *operations) ListByStepAndState(step string, state domain.LastOperationState, filter dbmodel.OperationFilter) ([]internal.Operation, int, int, error) {
+	session := s.NewReadSession()
+
+	var (
+		lastErr     error
+		size, total int
+		operations  = make([]dbmodel.OperationDTO, 0)
+	)
+
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		operations, size, total, lastErr = session.ListOperationsByStepAndState(step, string(state), filter)
+		if lastErr != nil {
+			log.Errorf("while getting operations for step %s from the storage: %v", step, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, -1, -1, err
+	}
+
+	result, err := s.toOperations(operations)
+
+	return result, size, total, err
+}
+
 func (s *operations) ListUpgradeKymaOperationsByOrchestrationID(orchestrationID string, filter dbmodel.OperationFilter) ([]internal.UpgradeKymaOperation, int, int, error) {
 	session := s.NewReadSession()
 	var (
@@ -809,6 +835,14 @@ func (s *operations) operationToDB(op internal.Operation) (dbmodel.OperationDTO,
 	for s, _ := range op.FinishedStages {
 		stages = append(stages, s)
 	}
+	failureReason := ""
+	if op.FailureReason != nil {
+		reason, err := json.Marshal(op.FailureReason)
+		if err != nil {
+			return dbmodel.OperationDTO{}, errors.Wrap(err, "while marshal failure reason")
+		}
+		failureReason = string(reason)
+	}
 	return dbmodel.OperationDTO{
 		ID:                     op.ID,
 		Type:                   op.Type,
@@ -822,10 +856,15 @@ func (s *operations) operationToDB(op internal.Operation) (dbmodel.OperationDTO,
 		OrchestrationID:        storage.StringToSQLNullString(op.OrchestrationID),
 		ProvisioningParameters: storage.StringToSQLNullString(string(pp)),
 		FinishedStages:         storage.StringToSQLNullString(strings.Join(stages, ",")),
+		CurrentStep:            storage.StringToSQLNullString(op.CurrentStep),
+		CorrelationID:          storage.StringToSQLNullString(op.CorrelationID),
+		TraceParent:            storage.StringToSQLNullString(op.TraceParent),
+		FailureReason:          storage.StringToSQLNullString(failureReason),
 	}, nil
 }
 
-func (s *operations) toOperation(op *dbmodel.OperationDTO, instanceDetails internal.InstanceDetails) (internal.Operation, error) {
+// toOperation builds the operation from the columns of the DTO and the fields of the operation read from the data column
+func (s *operations) toOperation(op *dbmodel.OperationDTO, data internal.Operation) (internal.Operation, error) {
 	pp := internal.ProvisioningParameters{}
 	if op.ProvisioningParameters.Valid {
 		err := json.Unmarshal([]byte(op.ProvisioningParameters.String), &pp)
@@ -842,6 +881,13 @@ func (s *operations) toOperation(op *dbmodel.OperationDTO, instanceDetails inter
 	for _, s := range strings.Split(storage.SQLNullStringToString(op.FinishedStages), ",") {
 		stages[s] = struct{}{}
 	}
+	var failureReason *internal.FailureReason
+	if op.FailureReason.Valid && op.FailureReason.String != "" {
+		failureReason = &internal.FailureReason{}
+		if err := json.Unmarshal([]byte(op.FailureReason.String), failureReason); err != nil {
+			return internal.Operation{}, errors.Wrap(err, "while unmarshal failure reason")
+		}
+	}
 	return internal.Operation{
 		ID:                     op.ID,
 		CreatedAt:              op.CreatedAt,
@@ -854,9 +900,14 @@ func (s *operations) toOperation(op *dbmodel.OperationDTO, instanceDetails inter
 		Version:                op.Version,
 		OrchestrationID:        storage.SQLNullStringToString(op.OrchestrationID),
 		ProvisioningParameters: pp,
-		InstanceDetails:        instanceDetails,
+		InstanceDetails:        data.InstanceDetails,
+		Progress:               data.Progress,
 		FinishedStages:         stages,
 		FinishedSteps:          make(map[string]struct{}, 0),
+		CurrentStep:            storage.SQLNullStringToString(op.CurrentStep),
+		CorrelationID:          storage.SQLNullStringToString(op.CorrelationID),
+		TraceParent:            storage.SQLNullStringToString(op.TraceParent),
+		FailureReason:          failureReason,
 	}, nil
 }
 
@@ -868,7 +919,7 @@ func (s *operations) toOperations(op []dbmodel.OperationDTO) ([]internal.Operati
 		if err != nil {
 			return nil, errors.New("unable to unmarshall provisioning data")
 		}
-		operation, err = s.toOperation(&o, operation.InstanceDetails)
+		operation, err = s.toOperation(&o, operation)
 		if err != nil {
 			return nil, err
 		}
@@ -887,7 +938,7 @@ func (s *operations) toProvisioningOperation(op *dbmodel.OperationDTO) (*interna
 	if err != nil {
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
-	operation.Operation, err = s.toOperation(op, operation.InstanceDetails)
+	operation.Operation, err = s.toOperation(op, operation.Operation)
 	if err != nil {
 		return nil, err
 	}
@@ -947,7 +998,7 @@ func (s *operations) toDeprovisioningOperation(op *dbmodel.OperationDTO) (*inter
 	if err != nil {
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
-	operation.Operation, err = s.toOperation(op, operation.InstanceDetails)
+	operation.Operation, err = s.toOperation(op, operation.Operation)
 	if err != nil {
 		return nil, err
 	}
@@ -980,7 +1031,7 @@ func (s *operations) toUpgradeKymaOperation(op *dbmodel.OperationDTO) (*internal
 	if err != nil {
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
-	operation.Operation, err = s.toOperation(op, operation.InstanceDetails)
+	operation.Operation, err = s.toOperation(op, operation.Operation)
 	if err != nil {
 		return nil, err
 	}
@@ -1032,7 +1083,7 @@ func (s *operations) toUpgradeClusterOperation(op *dbmodel.OperationDTO) (*inter
 	if err != nil {
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
-	operation.Operation, err = s.toOperation(op, operation.InstanceDetails)
+	operation.Operation, err = s.toOperation(op, operation.Operation)
 	if err != nil {
 		return nil, err
 	}
@@ -364,6 +364,68 @@ func TestOperation(t *testing.T) {
 		require.NoError(t, err)
 		assertUpgradeClusterOperation(t, *op, *got)
 	})
+
+	t.Run("List by step and state", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		svc := brokerStorage.Operations()
+		for _, given := range []struct {
+			id    string
+			step  string
+			state domain.LastOperationState
+		}{
+			{id: "stuck-1", step: "Create_IAS_Application", state: domain.InProgress},
+			{id: "stuck-2", step: "Create_IAS_Application", state: domain.InProgress},
+			{id: "failed", step: "Create_IAS_Application", state: domain.Failed},
+			{id: "other-step", step: "Create_Runtime", state: domain.InProgress},
+		} {
+			operation := fixture.FixProvisioningOperation(given.id, "inst-"+given.id)
+			operation.InputCreator = nil
+			operation.CurrentStep = given.step
+			operation.State = given.state
+			err = svc.InsertProvisioningOperation(operation)
+			require.NoError(t, err)
+		}
+
+		// when
+		ops, count, totalCount, err := svc.ListByStepAndState("Create_IAS_Application", domain.InProgress, dbmodel.OperationFilter{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, totalCount)
+		assert.ElementsMatch(t, []string{"stuck-1", "stuck-2"}, []string{ops[0].ID, ops[1].ID})
+		assert.Equal(t, "Create_IAS_Application", ops[0].CurrentStep)
+
+		// when
+		ops, count, totalCount, err = svc.ListByStepAndState("Create_IAS_Application", domain.InProgress, dbmodel.OperationFilter{Page: 1, PageSize: 1})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, ops, 1)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 2, totalCount)
+
+		// when
+		ops, count, totalCount, err = svc.ListByStepAndState("Deregister_Runtime", domain.InProgress, dbmodel.OperationFilter{})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, ops)
+		assert.Zero(t, count)
+		assert.Zero(t, totalCount)
+	})
 }
 
 func assertProvisioningOperation(t *testing.T, expected, got internal.ProvisioningOperation) {
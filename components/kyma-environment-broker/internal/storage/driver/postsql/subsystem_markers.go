@@ -0,0 +1,60 @@
+package postsql
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+)
+
+type subsystemMarkers struct {
+	postsql.Factory
+	instances *Instance
+}
+
+func NewSubsystemMarkers(sessionFactory postsql.Factory, instances *Instance) *subsystemMarkers {
+	return &subsystemMarkers{
+		Factory:   sessionFactory,
+		instances: instances,
+	}
+}
+
+func (s *subsystemMarkers) Insert(marker internal.SubsystemMarker) error {
+	err := s.NewWriteSession().InsertSubsystemMarker(dbmodel.SubsystemMarkerDTO{
+		InstanceID:  marker.InstanceID,
+		Subsystem:   marker.Subsystem,
+		OperationID: marker.OperationID,
+		CreatedAt:   marker.CreatedAt,
+	})
+	if err != nil && err.Code() != dberr.CodeAlreadyExists {
+		return err
+	}
+
+	return nil
+}
+
+func (s *subsystemMarkers) Delete(instanceID, subsystem string) error {
+	return s.NewWriteSession().DeleteSubsystemMarker(instanceID, subsystem)
+}
+
+func (s *subsystemMarkers) CountBySubAccount(subsystem, subAccountID string) (int, error) {
+	return s.NewReadSession().GetNumberOfSubsystemMarkersForSubAccount(subsystem, subAccountID)
+}
+
+func (s *subsystemMarkers) ListInstancesWithout(subsystem string) ([]internal.Instance, error) {
+	dtos, err := s.NewReadSession().ListInstancesWithoutSubsystemMarker(subsystem)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]internal.Instance, 0, len(dtos))
+	for _, dto := range dtos {
+		instance, err := s.instances.toInstance(dto)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,69 @@
+package postsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemMarkers(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("SubsystemMarkers", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		for _, id := range []string{"instance-1", "instance-2", "instance-3"} {
+			require.NoError(t, brokerStorage.Instances().Insert(fixture.FixInstance(id)))
+		}
+		svc := brokerStorage.SubsystemMarkers()
+
+		// when
+		require.NoError(t, svc.Insert(internal.SubsystemMarker{InstanceID: "instance-1", Subsystem: internal.SubsystemEDP, OperationID: "op-1", CreatedAt: time.Now()}))
+		require.NoError(t, svc.Insert(internal.SubsystemMarker{InstanceID: "instance-1", Subsystem: internal.SubsystemEDP, OperationID: "op-2", CreatedAt: time.Now()}))
+		require.NoError(t, svc.Insert(internal.SubsystemMarker{InstanceID: "instance-2", Subsystem: "other", OperationID: "op-3", CreatedAt: time.Now()}))
+		instances, err := svc.ListInstancesWithout(internal.SubsystemEDP)
+
+		// then
+		require.NoError(t, err)
+		ids := make([]string, 0, len(instances))
+		for _, instance := range instances {
+			ids = append(ids, instance.InstanceID)
+		}
+		assert.ElementsMatch(t, []string{"instance-2", "instance-3"}, ids)
+
+		// when
+		count, err := svc.CountBySubAccount(internal.SubsystemEDP, "SA-instance-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// when
+		require.NoError(t, svc.Delete("instance-1", internal.SubsystemEDP))
+		require.NoError(t, svc.Delete("instance-3", internal.SubsystemEDP))
+		count, err = svc.CountBySubAccount(internal.SubsystemEDP, "SA-instance-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,57 @@
+package postsql
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+)
+
+type workItems struct {
+	postsql.Factory
+}
+
+func NewWorkItems(sessionFactory postsql.Factory) *workItems {
+	return &workItems{
+		Factory: sessionFactory,
+	}
+}
+
+func (s *workItems) Insert(item internal.WorkItem) error {
+	err := s.NewWriteSession().InsertWorkItem(dbmodel.WorkItemDTO{
+		Queue:        item.Queue,
+		OperationID:  item.OperationID,
+		SubAccountID: item.SubAccountID,
+		Priority:     item.Priority,
+		CreatedAt:    item.CreatedAt,
+	})
+	if err != nil && err.Code() != dberr.CodeAlreadyExists {
+		return err
+	}
+
+	return nil
+}
+
+func (s *workItems) Delete(queue, operationID string) error {
+	return s.NewWriteSession().DeleteWorkItem(queue, operationID)
+}
+
+func (s *workItems) ListByQueue(queue string) ([]internal.WorkItem, error) {
+	dtos, err := s.NewReadSession().ListWorkItemsByQueue(queue)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]internal.WorkItem, 0, len(dtos))
+	for _, dto := range dtos {
+		items = append(items, internal.WorkItem{
+			Queue:        dto.Queue,
+			OperationID:  dto.OperationID,
+			SubAccountID: dto.SubAccountID,
+			Priority:     dto.Priority,
+			CreatedAt:    dto.CreatedAt,
+		})
+	}
+
+	return items, nil
+}
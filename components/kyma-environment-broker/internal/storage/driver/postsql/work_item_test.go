@@ -0,0 +1,70 @@
+package postsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkItems(t *testing.T) {
+
+	ctx := context.Background()
+
+	t.Run("WorkItems", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		tablesCleanupFunc, err := storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+		defer tablesCleanupFunc()
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		svc := brokerStorage.WorkItems()
+
+		// when
+		for _, item := range []internal.WorkItem{
+			{Queue: "provisioning", OperationID: "op-2", SubAccountID: "sub-a", CreatedAt: now.Add(time.Second)},
+			{Queue: "provisioning", OperationID: "op-1", SubAccountID: "sub-b", CreatedAt: now},
+			{Queue: "deprovisioning", OperationID: "op-3", CreatedAt: now},
+		} {
+			require.NoError(t, svc.Insert(item))
+		}
+		// the operation added again to the queue is stored once
+		err = svc.Insert(internal.WorkItem{Queue: "provisioning", OperationID: "op-1", CreatedAt: now.Add(time.Minute)})
+		require.NoError(t, err)
+
+		items, err := svc.ListByQueue("provisioning")
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, items, 2)
+		assert.Equal(t, "op-1", items[0].OperationID)
+		assert.Equal(t, "sub-b", items[0].SubAccountID)
+		assert.Equal(t, "op-2", items[1].OperationID)
+
+		// when
+		err = svc.Delete("provisioning", "op-1")
+		require.NoError(t, err)
+		items, err = svc.ListByQueue("provisioning")
+		require.NoError(t, err)
+		other, err := svc.ListByQueue("deprovisioning")
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, items, 1)
+		assert.Equal(t, "op-2", items[0].OperationID)
+		assert.Len(t, other, 1)
+	})
+}
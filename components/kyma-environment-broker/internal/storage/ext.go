@@ -4,6 +4,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/predicate"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 )
 
 type Instances interface {
@@ -11,11 +12,19 @@ type Instances interface {
 	FindAllInstancesForRuntimes(runtimeIdList []string) ([]internal.Instance, error)
 	FindAllInstancesForSubAccounts(subAccountslist []string) ([]internal.Instance, error)
 	GetByID(instanceID string) (*internal.Instance, error)
+	// GetByCustomDomain returns the instance which uses the custom domain, the domain is released when the instance is removed
+	GetByCustomDomain(customDomain string) (*internal.Instance, error)
 	Insert(instance internal.Instance) error
+	// InsertUnlessDuplicated inserts the instance unless its subaccount already has an active instance of the plan,
+	// false is returned if the instance was not inserted. The check and the insert are atomic.
+	InsertUnlessDuplicated(instance internal.Instance) (bool, error)
 	Update(instance internal.Instance) (*internal.Instance, error)
 	Delete(instanceID string) error
 	GetInstanceStats() (internal.InstanceStats, error)
+	GetInstanceStates() ([]internal.InstanceState, error)
+	GetInstanceStateCountsForSubAccount(subAccountID string) ([]internal.InstanceStateCount, error)
 	GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error)
+	GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error)
 	List(dbmodel.InstanceFilter) ([]internal.Instance, int, int, error)
 
 	// todo: remove after instances parameters migration is done
@@ -37,6 +46,8 @@ type Operations interface {
 	GetOperationsForIDs(operationIDList []string) ([]internal.Operation, error)
 	GetOperationStatsForOrchestration(orchestrationID string) (map[string]int, error)
 	ListOperations(filter dbmodel.OperationFilter) ([]internal.Operation, int, int, error)
+	// ListByStepAndState returns operations which are currently processed by the given step and are in the given state
+	ListByStepAndState(step string, state domain.LastOperationState, filter dbmodel.OperationFilter) ([]internal.Operation, int, int, error)
 }
 
 type Provisioning interface {
@@ -99,3 +110,44 @@ type CLSInstances interface {
 	Update(instance internal.CLSInstance) error
 	Delete(clsInstanceID string) error
 }
+
+type Bindings interface {
+	Insert(binding internal.Binding) error
+	Update(binding internal.Binding) (*internal.Binding, error)
+	GetByID(bindingID string) (*internal.Binding, error)
+	Delete(bindingID string) error
+}
+
+type WorkItems interface {
+	// Insert stores the work item, inserting the item which already exists in the queue is not an error
+	Insert(item internal.WorkItem) error
+	Delete(queue, operationID string) error
+	ListByQueue(queue string) ([]internal.WorkItem, error)
+}
+
+type SubsystemMarkers interface {
+	// Insert records the marker of the subsystem of the instance, the first marker of the subsystem is kept
+	Insert(marker internal.SubsystemMarker) error
+	// ListInstancesWithout returns the instances for which no operation recorded the marker of the subsystem
+	ListInstancesWithout(subsystem string) ([]internal.Instance, error)
+	// Delete removes the marker of the subsystem of the instance, removing the missing marker is not an error
+	Delete(instanceID, subsystem string) error
+	// CountBySubAccount returns the number of the instances of the subaccount with the marker of the subsystem,
+	// e.g. the number of the instances referencing the EDP tenant of the subaccount
+	CountBySubAccount(subsystem, subAccountID string) (int, error)
+}
+
+type InstanceFlags interface {
+	// GetByInstanceID returns the flags set for the instance, an empty map is returned if no flag was set
+	GetByInstanceID(instanceID string) (map[string]bool, error)
+	// Set replaces the flags of the instance
+	Set(instanceID string, flags map[string]bool) error
+}
+
+type CatalogSnapshots interface {
+	// Save stores the snapshot, the snapshot which already exists is stored again with the new creation time
+	Save(snapshot internal.CatalogSnapshot) error
+	GetByETag(etag string) (*internal.CatalogSnapshot, error)
+	// DeleteAllExceptNewest removes the snapshots except the given number of the most recently saved ones
+	DeleteAllExceptNewest(count int) error
+}
@@ -21,6 +21,7 @@ type ReadSession interface {
 	FindAllInstancesForRuntimes(runtimeIdList []string) ([]dbmodel.InstanceDTO, dberr.Error)
 	FindAllInstancesForSubAccounts(subAccountslist []string) ([]dbmodel.InstanceDTO, dberr.Error)
 	GetInstanceByID(instanceID string) (dbmodel.InstanceDTO, dberr.Error)
+	GetInstanceByCustomDomain(customDomain string) (dbmodel.InstanceDTO, dberr.Error)
 	GetLastOperation(instanceID string) (dbmodel.OperationDTO, dberr.Error)
 	GetOperationByID(opID string) (dbmodel.OperationDTO, dberr.Error)
 	GetNotFinishedOperationsByType(operationType internal.OperationType) ([]dbmodel.OperationDTO, dberr.Error)
@@ -28,13 +29,17 @@ type ReadSession interface {
 	GetOperationsByTypeAndInstanceID(inID string, opType internal.OperationType) ([]dbmodel.OperationDTO, dberr.Error)
 	GetOperationsForIDs(opIdList []string) ([]dbmodel.OperationDTO, dberr.Error)
 	ListOperations(filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error)
+	ListOperationsByStepAndState(step, state string, filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error)
 	ListOperationsByType(operationType internal.OperationType) ([]dbmodel.OperationDTO, dberr.Error)
 	GetLMSTenant(name, region string) (dbmodel.LMSTenantDTO, dberr.Error)
 	GetCLSInstanceByGlobalAccountID(globalAccountID string) ([]dbmodel.CLSInstanceDTO, dberr.Error)
 	GetCLSInstanceByID(clsInstanceID string) ([]dbmodel.CLSInstanceDTO, dberr.Error)
 	GetOperationStats() ([]dbmodel.OperationStatEntry, error)
 	GetInstanceStats() ([]dbmodel.InstanceByGlobalAccountIDStatEntry, error)
+	GetInstanceStates() ([]dbmodel.InstanceStateEntry, error)
+	GetInstanceStateCountsForSubAccount(subAccountID string) ([]dbmodel.InstanceStateCountEntry, error)
 	GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error)
+	GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error)
 	GetRuntimeStateByOperationID(operationID string) (dbmodel.RuntimeStateDTO, dberr.Error)
 	ListRuntimeStateByRuntimeID(runtimeID string) ([]dbmodel.RuntimeStateDTO, dberr.Error)
 	GetOrchestrationByID(oID string) (dbmodel.OrchestrationDTO, dberr.Error)
@@ -42,11 +47,19 @@ type ReadSession interface {
 	ListInstances(filter dbmodel.InstanceFilter) ([]dbmodel.InstanceDTO, int, int, error)
 	ListOperationsByOrchestrationID(orchestrationID string, filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error)
 	GetOperationStatsForOrchestration(orchestrationID string) ([]dbmodel.OperationStatEntry, error)
+	GetBindingByID(bindingID string) (dbmodel.BindingDTO, dberr.Error)
+	ListWorkItemsByQueue(queue string) ([]dbmodel.WorkItemDTO, dberr.Error)
+	GetInstanceFlags(instanceID string) (dbmodel.InstanceFlagsDTO, dberr.Error)
+	ListInstancesWithoutSubsystemMarker(subsystem string) ([]dbmodel.InstanceDTO, dberr.Error)
+	GetNumberOfSubsystemMarkersForSubAccount(subsystem, subAccountID string) (int, dberr.Error)
+	GetCatalogSnapshot(etag string) (dbmodel.CatalogSnapshotDTO, dberr.Error)
 }
 
 //go:generate mockery -name=WriteSession
 type WriteSession interface {
 	InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
+	LockSubAccountPlan(subAccountID, planID string) dberr.Error
+	GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, dberr.Error)
 	UpdateInstance(instance dbmodel.InstanceDTO) dberr.Error
 	DeleteInstance(instanceID string) dberr.Error
 	InsertOperation(dto dbmodel.OperationDTO) dberr.Error
@@ -60,6 +73,18 @@ type WriteSession interface {
 	DeleteCLSInstance(clsInstanceID string) dberr.Error
 	InsertCLSInstanceReference(dto dbmodel.CLSInstanceReferenceDTO) dberr.Error
 	DeleteCLSInstanceReference(dto dbmodel.CLSInstanceReferenceDTO) dberr.Error
+	InsertBinding(dto dbmodel.BindingDTO) dberr.Error
+	UpdateBinding(dto dbmodel.BindingDTO) dberr.Error
+	DeleteBinding(bindingID string) dberr.Error
+	InsertWorkItem(dto dbmodel.WorkItemDTO) dberr.Error
+	DeleteWorkItem(queue, operationID string) dberr.Error
+	InsertInstanceFlags(dto dbmodel.InstanceFlagsDTO) dberr.Error
+	UpdateInstanceFlags(dto dbmodel.InstanceFlagsDTO) dberr.Error
+	InsertSubsystemMarker(dto dbmodel.SubsystemMarkerDTO) dberr.Error
+	DeleteSubsystemMarker(instanceID, subsystem string) dberr.Error
+	InsertCatalogSnapshot(dto dbmodel.CatalogSnapshotDTO) dberr.Error
+	UpdateCatalogSnapshot(dto dbmodel.CatalogSnapshotDTO) dberr.Error
+	DeleteCatalogSnapshotsExceptNewest(count int) dberr.Error
 }
 
 type Transaction interface {
@@ -21,6 +21,11 @@ const (
 	LMSTenantTableName            = "lms_tenants"
 	CLSInstanceTableName          = "cls_instances"
 	CLSInstanceReferenceTableName = "cls_instance_references"
+	BindingsTableName             = "bindings"
+	WorkItemsTableName            = "work_items"
+	InstanceFlagsTableName        = "instance_flags"
+	SubsystemMarkersTableName     = "subsystem_markers"
+	CatalogSnapshotsTableName     = "catalog_snapshots"
 	CreatedAtField                = "created_at"
 )
 
@@ -26,7 +26,7 @@ func (r readSession) getInstancesJoinedWithOperationStatement() *dbr.SelectStmt
 		Select("instances.instance_id, instances.runtime_id, instances.global_account_id, instances.service_id,"+
 			" instances.service_plan_id, instances.dashboard_url, instances.provisioning_parameters, instances.created_at,"+
 			" instances.updated_at, instances.deleted_at, instances.sub_account_id, instances.service_name, instances.service_plan_name,"+
-			" instances.provider_region, operations.state, operations.description, operations.type").
+			" instances.provider_region, instances.suspension_reason, operations.state, operations.description, operations.type").
 		From(InstancesTableName).
 		LeftJoin(OperationTableName, join)
 	return stmt
@@ -66,6 +66,25 @@ func (r readSession) GetInstanceByID(instanceID string) (dbmodel.InstanceDTO, db
 	return instance, nil
 }
 
+func (r readSession) GetInstanceByCustomDomain(customDomain string) (dbmodel.InstanceDTO, dberr.Error) {
+	var instance dbmodel.InstanceDTO
+
+	err := r.session.
+		Select("*").
+		From(InstancesTableName).
+		Where(dbr.Eq("custom_domain", customDomain)).
+		LoadOne(&instance)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.InstanceDTO{}, dberr.NotFound("Cannot find Instance for custom domain:'%s'", customDomain)
+		}
+		return dbmodel.InstanceDTO{}, dberr.Internal("Failed to get Instance: %s", err)
+	}
+
+	return instance, nil
+}
+
 func (r readSession) FindAllInstancesForRuntimes(runtimeIdList []string) ([]dbmodel.InstanceDTO, dberr.Error) {
 	var instances []dbmodel.InstanceDTO
 
@@ -160,6 +179,36 @@ func (r readSession) ListOperations(filter dbmodel.OperationFilter) ([]dbmodel.O
 		nil
 }
 
+func (r readSession) ListOperationsByStepAndState(step, state string, filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error) {
+	var operations []dbmodel.OperationDTO
+
+	stmt := r.session.Select("*").
+		From(OperationTableName).
+		Where(dbr.Eq("current_step", step)).
+		Where(dbr.Eq("state", state)).
+		OrderBy(CreatedAtField)
+
+	// Add pagination if provided
+	if filter.Page > 0 && filter.PageSize > 0 {
+		stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
+	}
+
+	_, err := stmt.Load(&operations)
+	if err != nil {
+		return nil, -1, -1, dberr.Internal("Failed to get operations for step %s: %s", step, err)
+	}
+
+	totalCount, err := r.getOperationCountByStepAndState(step, state)
+	if err != nil {
+		return nil, -1, -1, err
+	}
+
+	return operations,
+		len(operations),
+		totalCount,
+		nil
+}
+
 func (r readSession) GetOrchestrationByID(oID string) (dbmodel.OrchestrationDTO, dberr.Error) {
 	condition := dbr.Eq("orchestration_id", oID)
 	operation, err := r.getOrchestration(condition)
@@ -493,6 +542,31 @@ func (r readSession) GetInstanceStats() ([]dbmodel.InstanceByGlobalAccountIDStat
 	return rows, err
 }
 
+// GetInstanceStates returns the plan and the region of every instance together with the type and the state
+// of its last provisioning or deprovisioning operation
+func (r readSession) GetInstanceStates() ([]dbmodel.InstanceStateEntry, error) {
+	var rows []dbmodel.InstanceStateEntry
+	_, err := r.session.SelectBySql(fmt.Sprintf(`select distinct on (i.instance_id) i.instance_id, i.service_plan_id, i.provider_region, o.type, o.state
+		from %s i join %s o on o.instance_id = i.instance_id and o.type in (?, ?)
+		order by i.instance_id, o.created_at desc`, InstancesTableName, OperationTableName),
+		internal.OperationTypeProvision, internal.OperationTypeDeprovision).Load(&rows)
+	return rows, err
+}
+
+// GetInstanceStateCountsForSubAccount returns the number of instances of the subaccount grouped by the plan and by the type
+// and the state of the last provisioning or deprovisioning operation of the instance
+func (r readSession) GetInstanceStateCountsForSubAccount(subAccountID string) ([]dbmodel.InstanceStateCountEntry, error) {
+	var rows []dbmodel.InstanceStateCountEntry
+	_, err := r.session.SelectBySql(fmt.Sprintf(`select s.service_plan_id, s.type, s.state, count(*) as total from (
+			select distinct on (i.instance_id) i.instance_id, i.service_plan_id, coalesce(o.type, '') as type, coalesce(o.state, '') as state
+			from %s i left join %s o on o.instance_id = i.instance_id and o.type in (?, ?)
+			where i.sub_account_id = ?
+			order by i.instance_id, o.created_at desc) s
+		group by s.service_plan_id, s.type, s.state`, InstancesTableName, OperationTableName),
+		internal.OperationTypeProvision, internal.OperationTypeDeprovision, subAccountID).Load(&rows)
+	return rows, err
+}
+
 func (r readSession) GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error) {
 	var res struct {
 		Total int
@@ -505,6 +579,36 @@ func (r readSession) GetNumberOfInstancesForGlobalAccountID(globalAccountID stri
 	return res.Total, err
 }
 
+func (r readSession) GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, error) {
+	var res struct {
+		Total int
+	}
+	err := activeInstancesForSubAccountAndPlan(r.session.Select("count(*) as total"), subAccountID, planID).
+		LoadOne(&res)
+
+	return res.Total, err
+}
+
+// activeInstancesForSubAccountAndPlan selects the active instances of the subaccount in the plan, the instances which last operation
+// is the deprovisioning which did not fail are skipped unless the deprovisioning is the suspension, as the suspended instance
+// can be resumed anytime. The last operation is found the same way as in ListInstances.
+func activeInstancesForSubAccountAndPlan(stmt *dbr.SelectStmt, subAccountID, planID string) *dbr.SelectStmt {
+	return stmt.From(InstancesTableName).
+		LeftJoin(dbr.I(OperationTableName).As("o1"), fmt.Sprintf("%s.instance_id = o1.instance_id AND o1.state <> '%s'", InstancesTableName, orchestration.Pending)).
+		LeftJoin(dbr.I(OperationTableName).As("o2"), fmt.Sprintf("%s.instance_id = o2.instance_id AND o1.created_at < o2.created_at AND o2.state <> '%s'", InstancesTableName, orchestration.Pending)).
+		Where("o2.created_at IS NULL").
+		Where(dbr.And(
+			dbr.Eq(fmt.Sprintf("%s.sub_account_id", InstancesTableName), subAccountID),
+			dbr.Eq(fmt.Sprintf("%s.service_plan_id", InstancesTableName), planID),
+		)).
+		Where(dbr.Or(
+			dbr.Expr("o1.type IS NULL"),
+			dbr.Neq("o1.type", string(internal.OperationTypeDeprovision)),
+			dbr.Eq("o1.state", string(domain.Failed)),
+			dbr.Expr("o1.data->>'temporary' = 'true'"),
+		))
+}
+
 func (r readSession) ListInstances(filter dbmodel.InstanceFilter) ([]dbmodel.InstanceDTO, int, int, error) {
 	var instances []dbmodel.InstanceDTO
 
@@ -677,6 +781,19 @@ func (r readSession) getOperationCount(filter dbmodel.OperationFilter) (int, err
 	return res.Total, err
 }
 
+func (r readSession) getOperationCountByStepAndState(step, state string) (int, error) {
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From(OperationTableName).
+		Where(dbr.Eq("current_step", step)).
+		Where(dbr.Eq("state", state)).
+		LoadOne(&res)
+
+	return res.Total, err
+}
+
 func (r readSession) getUpgradeOperationCount(orchestrationID string, filter dbmodel.OperationFilter) (int, error) {
 	var res struct {
 		Total int
@@ -700,3 +817,108 @@ func (r readSession) getOrchestrationCount(filter dbmodel.OrchestrationFilter) (
 
 	return res.Total, err
 }
+
+func (r readSession) GetBindingByID(bindingID string) (dbmodel.BindingDTO, dberr.Error) {
+	var dto dbmodel.BindingDTO
+	err := r.session.
+		Select("*").
+		From(BindingsTableName).
+		Where(dbr.Eq("id", bindingID)).
+		LoadOne(&dto)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.BindingDTO{}, dberr.NotFound("Cannot find binding for bindingID:'%s'", bindingID)
+		}
+		return dbmodel.BindingDTO{}, dberr.Internal("Failed to get binding: %s", err)
+	}
+	return dto, nil
+}
+
+func (r readSession) ListWorkItemsByQueue(queue string) ([]dbmodel.WorkItemDTO, dberr.Error) {
+	var items []dbmodel.WorkItemDTO
+	_, err := r.session.
+		Select("*").
+		From(WorkItemsTableName).
+		Where(dbr.Eq("queue", queue)).
+		OrderBy(CreatedAtField).
+		Load(&items)
+
+	if err != nil {
+		return nil, dberr.Internal("Failed to get work items: %s", err)
+	}
+	return items, nil
+}
+
+func (r readSession) GetInstanceFlags(instanceID string) (dbmodel.InstanceFlagsDTO, dberr.Error) {
+	var dto dbmodel.InstanceFlagsDTO
+	err := r.session.
+		Select("*").
+		From(InstanceFlagsTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		LoadOne(&dto)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.InstanceFlagsDTO{}, dberr.NotFound("Cannot find flags of instance %s", instanceID)
+		}
+		return dbmodel.InstanceFlagsDTO{}, dberr.Internal("Failed to get instance flags: %s", err)
+	}
+	return dto, nil
+}
+
+func (r readSession) ListInstancesWithoutSubsystemMarker(subsystem string) ([]dbmodel.InstanceDTO, dberr.Error) {
+	var instances []dbmodel.InstanceDTO
+	join := dbr.Expr(fmt.Sprintf("%s.instance_id = %s.instance_id AND %s.subsystem = ?",
+		SubsystemMarkersTableName, InstancesTableName, SubsystemMarkersTableName), subsystem)
+
+	_, err := r.session.
+		Select(fmt.Sprintf("%s.*", InstancesTableName)).
+		From(InstancesTableName).
+		LeftJoin(SubsystemMarkersTableName, join).
+		Where(fmt.Sprintf("%s.instance_id IS NULL", SubsystemMarkersTableName)).
+		OrderBy(fmt.Sprintf("%s.created_at", InstancesTableName)).
+		Load(&instances)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get instances without marker of subsystem %s: %s", subsystem, err)
+	}
+	return instances, nil
+}
+
+func (r readSession) GetNumberOfSubsystemMarkersForSubAccount(subsystem, subAccountID string) (int, dberr.Error) {
+	var res struct {
+		Total int
+	}
+	join := fmt.Sprintf("%s.instance_id = %s.instance_id", SubsystemMarkersTableName, InstancesTableName)
+
+	err := r.session.
+		Select("count(*) as total").
+		From(SubsystemMarkersTableName).
+		Join(InstancesTableName, join).
+		Where(dbr.And(
+			dbr.Eq(fmt.Sprintf("%s.subsystem", SubsystemMarkersTableName), subsystem),
+			dbr.Eq(fmt.Sprintf("%s.sub_account_id", InstancesTableName), subAccountID),
+		)).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count markers of subsystem %s for subaccount %s: %s", subsystem, subAccountID, err)
+	}
+	return res.Total, nil
+}
+
+func (r readSession) GetCatalogSnapshot(etag string) (dbmodel.CatalogSnapshotDTO, dberr.Error) {
+	var dto dbmodel.CatalogSnapshotDTO
+	err := r.session.
+		Select("*").
+		From(CatalogSnapshotsTableName).
+		Where(dbr.Eq("etag", etag)).
+		LoadOne(&dto)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.CatalogSnapshotDTO{}, dberr.NotFound("Cannot find catalog snapshot with ETag %s", etag)
+		}
+		return dbmodel.CatalogSnapshotDTO{}, dberr.Internal("Failed to get catalog snapshot: %s", err)
+	}
+	return dto, nil
+}
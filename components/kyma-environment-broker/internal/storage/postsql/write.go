@@ -1,6 +1,7 @@
 package postsql
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -12,6 +13,8 @@ import (
 
 const (
 	UniqueViolationErrorCode = "23505"
+	// InstancesCustomDomainIndex is the unique index of the custom domains of the instances
+	InstancesCustomDomainIndex = "instances_custom_domain"
 )
 
 type writeSession struct {
@@ -32,6 +35,8 @@ func (ws writeSession) InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
 		Pair("dashboard_url", instance.DashboardURL).
 		Pair("provisioning_parameters", instance.ProvisioningParameters).
 		Pair("provider_region", instance.ProviderRegion).
+		Pair("suspension_reason", instance.SuspensionReason).
+		Pair("custom_domain", instance.CustomDomain).
 		// in postgres database it will be equal to "0001-01-01 00:00:00+00"
 		Pair("deleted_at", time.Time{}).
 		Pair("version", instance.Version).
@@ -39,6 +44,9 @@ func (ws writeSession) InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
 
 	if err != nil {
 		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode && err.Constraint == InstancesCustomDomainIndex {
+				return dberr.AlreadyExists("instance with custom domain %s already exist", instance.CustomDomain)
+			}
 			if err.Code == UniqueViolationErrorCode {
 				return dberr.AlreadyExists("operation with id %s already exist", instance.InstanceID)
 			}
@@ -49,6 +57,34 @@ func (ws writeSession) InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
 	return nil
 }
 
+// LockSubAccountPlan takes the advisory lock of the subaccount in the plan, the lock is held until the transaction ends.
+// It serializes the transactions of all broker replicas which insert the instances of the subaccount in the plan.
+func (ws writeSession) LockSubAccountPlan(subAccountID, planID string) dberr.Error {
+	if ws.transaction == nil {
+		return dberr.Internal("Failed to lock subaccount %s in plan %s: the lock requires a transaction", subAccountID, planID)
+	}
+	_, err := ws.transaction.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", fmt.Sprintf("%s/%s", subAccountID, planID))
+	if err != nil {
+		return dberr.Internal("Failed to lock subaccount %s in plan %s: %s", subAccountID, planID, err)
+	}
+
+	return nil
+}
+
+// GetNumberOfInstancesForSubAccountAndPlan counts the active instances of the subaccount in the plan within the transaction
+func (ws writeSession) GetNumberOfInstancesForSubAccountAndPlan(subAccountID, planID string) (int, dberr.Error) {
+	var res struct {
+		Total int
+	}
+	err := activeInstancesForSubAccountAndPlan(ws.selectColumns("count(*) as total"), subAccountID, planID).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count instances of subaccount %s in plan %s: %s", subAccountID, planID, err)
+	}
+
+	return res.Total, nil
+}
+
 func (ws writeSession) DeleteInstance(instanceID string) dberr.Error {
 	_, err := ws.deleteFrom(InstancesTableName).
 		Where(dbr.Eq("instance_id", instanceID)).
@@ -72,6 +108,7 @@ func (ws writeSession) UpdateInstance(instance dbmodel.InstanceDTO) dberr.Error
 		Set("dashboard_url", instance.DashboardURL).
 		Set("provisioning_parameters", instance.ProvisioningParameters).
 		Set("provider_region", instance.ProviderRegion).
+		Set("suspension_reason", instance.SuspensionReason).
 		Set("updated_at", time.Now()).
 		Set("version", instance.Version+1).
 		Exec()
@@ -105,6 +142,10 @@ func (ws writeSession) InsertOperation(op dbmodel.OperationDTO) dberr.Error {
 		Pair("orchestration_id", op.OrchestrationID.String).
 		Pair("provisioning_parameters", op.ProvisioningParameters.String).
 		Pair("finished_stages", op.FinishedStages).
+		Pair("current_step", op.CurrentStep).
+		Pair("correlation_id", op.CorrelationID).
+		Pair("trace_parent", op.TraceParent).
+		Pair("failure_reason", op.FailureReason).
 		Exec()
 
 	if err != nil {
@@ -312,6 +353,218 @@ func (ws writeSession) DeleteCLSInstanceReference(dto dbmodel.CLSInstanceReferen
 	return nil
 }
 
+func (ws writeSession) InsertBinding(dto dbmodel.BindingDTO) dberr.Error {
+	_, err := ws.insertInto(BindingsTableName).
+		Pair("id", dto.ID).
+		Pair("instance_id", dto.InstanceID).
+		Pair("operation_id", dto.OperationID).
+		Pair("operation_type", dto.OperationType).
+		Pair("state", dto.State).
+		Pair("description", dto.Description).
+		Pair("credentials", dto.Credentials).
+		Pair("created_at", dto.CreatedAt).
+		Pair("updated_at", dto.UpdatedAt).
+		Pair("version", dto.Version).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("binding with id %s already exist", dto.ID)
+			}
+		}
+		return dberr.Internal("failed to insert a record into table %s: %s", BindingsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateBinding(dto dbmodel.BindingDTO) dberr.Error {
+	res, err := ws.update(BindingsTableName).
+		Where(dbr.Eq("id", dto.ID)).
+		Where(dbr.Eq("version", dto.Version)).
+		Set("version", dto.Version+1).
+		Set("operation_id", dto.OperationID).
+		Set("operation_type", dto.OperationType).
+		Set("state", dto.State).
+		Set("description", dto.Description).
+		Set("credentials", dto.Credentials).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to update a record in table %s: %s", BindingsTableName, err)
+	}
+
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		// the optimistic locking requires numbers of rows affected
+		return dberr.Internal("unable to check number of updated rows in table %s: %s", BindingsTableName, err)
+	}
+	if rAffected == int64(0) {
+		return dberr.Conflict("unable to update binding %s - not found or stale version", dto.ID)
+	}
+
+	return nil
+}
+
+func (ws writeSession) DeleteBinding(bindingID string) dberr.Error {
+	_, err := ws.deleteFrom(BindingsTableName).
+		Where(dbr.Eq("id", bindingID)).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to delete a record from table %s: %s", BindingsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertWorkItem(dto dbmodel.WorkItemDTO) dberr.Error {
+	_, err := ws.insertInto(WorkItemsTableName).
+		Pair("queue", dto.Queue).
+		Pair("operation_id", dto.OperationID).
+		Pair("sub_account_id", dto.SubAccountID).
+		Pair("priority", dto.Priority).
+		Pair("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("work item of operation %s already exist in queue %s", dto.OperationID, dto.Queue)
+			}
+		}
+		return dberr.Internal("failed to insert a record into table %s: %s", WorkItemsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) DeleteWorkItem(queue, operationID string) dberr.Error {
+	_, err := ws.deleteFrom(WorkItemsTableName).
+		Where(dbr.Eq("queue", queue)).
+		Where(dbr.Eq("operation_id", operationID)).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to delete a record from table %s: %s", WorkItemsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertInstanceFlags(dto dbmodel.InstanceFlagsDTO) dberr.Error {
+	_, err := ws.insertInto(InstanceFlagsTableName).
+		Pair("instance_id", dto.InstanceID).
+		Pair("flags", dto.Flags).
+		Pair("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("flags of instance %s already exist", dto.InstanceID)
+			}
+		}
+		return dberr.Internal("failed to insert a record into table %s: %s", InstanceFlagsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateInstanceFlags(dto dbmodel.InstanceFlagsDTO) dberr.Error {
+	_, err := ws.update(InstanceFlagsTableName).
+		Where(dbr.Eq("instance_id", dto.InstanceID)).
+		Set("flags", dto.Flags).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to update a record in table %s: %s", InstanceFlagsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertSubsystemMarker(dto dbmodel.SubsystemMarkerDTO) dberr.Error {
+	_, err := ws.insertInto(SubsystemMarkersTableName).
+		Pair("instance_id", dto.InstanceID).
+		Pair("subsystem", dto.Subsystem).
+		Pair("operation_id", dto.OperationID).
+		Pair("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("marker of subsystem %s of instance %s already exist", dto.Subsystem, dto.InstanceID)
+			}
+		}
+		return dberr.Internal("failed to insert a record into table %s: %s", SubsystemMarkersTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) DeleteSubsystemMarker(instanceID, subsystem string) dberr.Error {
+	_, err := ws.deleteFrom(SubsystemMarkersTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		Where(dbr.Eq("subsystem", subsystem)).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to delete a record from table %s: %s", SubsystemMarkersTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertCatalogSnapshot(dto dbmodel.CatalogSnapshotDTO) dberr.Error {
+	_, err := ws.insertInto(CatalogSnapshotsTableName).
+		Pair("etag", dto.Etag).
+		Pair("catalog", dto.Catalog).
+		Pair("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("catalog snapshot with ETag %s already exist", dto.Etag)
+			}
+		}
+		return dberr.Internal("failed to insert a record into table %s: %s", CatalogSnapshotsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateCatalogSnapshot(dto dbmodel.CatalogSnapshotDTO) dberr.Error {
+	_, err := ws.update(CatalogSnapshotsTableName).
+		Where(dbr.Eq("etag", dto.Etag)).
+		Set("catalog", dto.Catalog).
+		Set("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to update a record in table %s: %s", CatalogSnapshotsTableName, err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) DeleteCatalogSnapshotsExceptNewest(count int) dberr.Error {
+	_, err := ws.deleteFrom(CatalogSnapshotsTableName).
+		Where(fmt.Sprintf("etag NOT IN (SELECT etag FROM %s ORDER BY %s DESC LIMIT ?)", CatalogSnapshotsTableName, CreatedAtField), count).
+		Exec()
+
+	if err != nil {
+		return dberr.Internal("unable to delete records from table %s: %s", CatalogSnapshotsTableName, err)
+	}
+
+	return nil
+}
+
 func (ws writeSession) UpdateOperation(op dbmodel.OperationDTO) dberr.Error {
 	res, err := ws.update(OperationTableName).
 		Where(dbr.Eq("id", op.ID)).
@@ -328,6 +581,10 @@ func (ws writeSession) UpdateOperation(op dbmodel.OperationDTO) dberr.Error {
 		Set("orchestration_id", op.OrchestrationID.String).
 		Set("provisioning_parameters", op.ProvisioningParameters.String).
 		Set("finished_stages", op.FinishedStages).
+		Set("current_step", op.CurrentStep).
+		Set("correlation_id", op.CorrelationID).
+		Set("trace_parent", op.TraceParent).
+		Set("failure_reason", op.FailureReason).
 		Exec()
 
 	if err != nil {
@@ -361,6 +618,14 @@ func (ws writeSession) RollbackUnlessCommitted() {
 	ws.transaction.RollbackUnlessCommitted()
 }
 
+func (ws writeSession) selectColumns(columns ...string) *dbr.SelectStmt {
+	if ws.transaction != nil {
+		return ws.transaction.Select(columns...)
+	}
+
+	return ws.session.Select(columns...)
+}
+
 func (ws writeSession) insertInto(table string) *dbr.InsertStmt {
 	if ws.transaction != nil {
 		return ws.transaction.InsertInto(table)
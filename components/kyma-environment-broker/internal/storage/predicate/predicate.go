@@ -12,7 +12,7 @@ import (
 
 // InMemoryPredicate allows to apply predicates for InMemory queries.
 type InMemoryPredicate interface {
-	ApplyToInMemory([]internal.InstanceWithOperation)
+	ApplyToInMemory([]internal.InstanceWithOperation) []internal.InstanceWithOperation
 }
 
 // PostgresPredicate allows to apply predicates for Postgres queries.
@@ -43,13 +43,69 @@ func (w SortByCreatedAt) ApplyToPostgres(stmt *dbr.SelectStmt) {
 }
 
 // TODO: It can be more generic but right now there's no reason to complicate it.
-func (w SortByCreatedAt) ApplyToInMemory(in []internal.InstanceWithOperation) {
+func (w SortByCreatedAt) ApplyToInMemory(in []internal.InstanceWithOperation) []internal.InstanceWithOperation {
 	sort.Slice(in, func(i, j int) bool {
 		return in[i].CreatedAt.Before(in[j].CreatedAt)
 	})
+	return in
 }
 
 var _ InMemoryPredicate = &SortByCreatedAt{}
 var _ PostgresPredicate = &SortByCreatedAt{}
 
+// InstancesPage limits the query output to the given page of the instances ordered by CreatedAt, all operations
+// of an instance are in the same page. The first page is 1.
+func InstancesPage(page, pageSize int) InstancesPageOf {
+	return InstancesPageOf{page: page, pageSize: pageSize}
+}
+
+type InstancesPageOf struct {
+	page     int
+	pageSize int
+}
+
+func (w InstancesPageOf) offset() int {
+	if w.page < 2 {
+		return 0
+	}
+	return (w.page - 1) * w.pageSize
+}
+
+func (w InstancesPageOf) ApplyToPostgres(stmt *dbr.SelectStmt) {
+	stmt.Where("instances.instance_id IN (SELECT instance_id FROM instances ORDER BY created_at, instance_id LIMIT ? OFFSET ?)",
+		w.pageSize, w.offset())
+}
+
+func (w InstancesPageOf) ApplyToInMemory(in []internal.InstanceWithOperation) []internal.InstanceWithOperation {
+	var instances []internal.InstanceWithOperation
+	seen := map[string]struct{}{}
+	for _, inst := range in {
+		if _, found := seen[inst.InstanceID]; !found {
+			seen[inst.InstanceID] = struct{}{}
+			instances = append(instances, inst)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].CreatedAt.Equal(instances[j].CreatedAt) {
+			return instances[i].InstanceID < instances[j].InstanceID
+		}
+		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
+	})
+
+	inPage := map[string]struct{}{}
+	for i := w.offset(); i < w.offset()+w.pageSize && i < len(instances); i++ {
+		inPage[instances[i].InstanceID] = struct{}{}
+	}
+	out := make([]internal.InstanceWithOperation, 0, len(in))
+	for _, inst := range in {
+		if _, found := inPage[inst.InstanceID]; found {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+var _ InMemoryPredicate = &InstancesPageOf{}
+var _ PostgresPredicate = &InstancesPageOf{}
+
 // }}}
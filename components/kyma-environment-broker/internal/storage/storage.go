@@ -17,6 +17,11 @@ type BrokerStorage interface {
 	Orchestrations() Orchestrations
 	RuntimeStates() RuntimeStates
 	CLSInstances() CLSInstances
+	Bindings() Bindings
+	WorkItems() WorkItems
+	InstanceFlags() InstanceFlags
+	SubsystemMarkers() SubsystemMarkers
+	CatalogSnapshots() CatalogSnapshots
 }
 
 const (
@@ -39,35 +44,52 @@ func NewFromConfig(cfg Config, cipher postgres.Cipher, log logrus.FieldLogger) (
 	fact := postsql.NewFactory(connection)
 
 	operation := postgres.NewOperation(fact, cipher)
+	instance := postgres.NewInstance(fact, operation, cipher)
 	return storage{
-		instance:       postgres.NewInstance(fact, operation, cipher),
-		operation:      operation,
-		lmsTenants:     postgres.NewLMSTenants(fact),
-		orchestrations: postgres.NewOrchestrations(fact),
-		runtimeStates:  postgres.NewRuntimeStates(fact, cipher),
-		clsInstances:   postgres.NewCLSInstances(fact),
+		instance:         instance,
+		operation:        operation,
+		lmsTenants:       postgres.NewLMSTenants(fact),
+		orchestrations:   postgres.NewOrchestrations(fact),
+		runtimeStates:    postgres.NewRuntimeStates(fact, cipher),
+		clsInstances:     postgres.NewCLSInstances(fact),
+		bindings:         postgres.NewBindings(fact, cipher),
+		workItems:        postgres.NewWorkItems(fact),
+		instanceFlags:    postgres.NewInstanceFlags(fact),
+		subsystemMarkers: postgres.NewSubsystemMarkers(fact, instance),
+		catalogSnapshots: postgres.NewCatalogSnapshots(fact),
 	}, connection, nil
 }
 
 func NewMemoryStorage() BrokerStorage {
 	op := memory.NewOperation()
+	instance := memory.NewInstance(op)
 	return storage{
-		operation:      op,
-		instance:       memory.NewInstance(op),
-		lmsTenants:     memory.NewLMSTenants(),
-		orchestrations: memory.NewOrchestrations(),
-		runtimeStates:  memory.NewRuntimeStates(),
-		clsInstances:   memory.NewCLSInstances(),
+		operation:        op,
+		instance:         instance,
+		lmsTenants:       memory.NewLMSTenants(),
+		orchestrations:   memory.NewOrchestrations(),
+		runtimeStates:    memory.NewRuntimeStates(),
+		clsInstances:     memory.NewCLSInstances(),
+		bindings:         memory.NewBindings(),
+		workItems:        memory.NewWorkItems(),
+		instanceFlags:    memory.NewInstanceFlags(),
+		subsystemMarkers: memory.NewSubsystemMarkers(instance),
+		catalogSnapshots: memory.NewCatalogSnapshots(),
 	}
 }
 
 type storage struct {
-	instance       Instances
-	operation      Operations
-	lmsTenants     LMSTenants
-	orchestrations Orchestrations
-	runtimeStates  RuntimeStates
-	clsInstances   CLSInstances
+	instance         Instances
+	operation        Operations
+	lmsTenants       LMSTenants
+	orchestrations   Orchestrations
+	runtimeStates    RuntimeStates
+	clsInstances     CLSInstances
+	bindings         Bindings
+	workItems        WorkItems
+	instanceFlags    InstanceFlags
+	subsystemMarkers SubsystemMarkers
+	catalogSnapshots CatalogSnapshots
 }
 
 func (s storage) Instances() Instances {
@@ -101,3 +123,23 @@ func (s storage) Orchestrations() Orchestrations {
 func (s storage) RuntimeStates() RuntimeStates {
 	return s.runtimeStates
 }
+
+func (s storage) Bindings() Bindings {
+	return s.bindings
+}
+
+func (s storage) WorkItems() WorkItems {
+	return s.workItems
+}
+
+func (s storage) InstanceFlags() InstanceFlags {
+	return s.instanceFlags
+}
+
+func (s storage) SubsystemMarkers() SubsystemMarkers {
+	return s.subsystemMarkers
+}
+
+func (s storage) CatalogSnapshots() CatalogSnapshots {
+	return s.catalogSnapshots
+}
@@ -396,11 +396,15 @@ func FixTables() map[string]string {
 			dashboard_url varchar(255) NOT NULL,
 			provisioning_parameters text NOT NULL,
 			provider_region varchar(32) NOT NULL,
+			suspension_reason varchar(32) NOT NULL DEFAULT '',
+			custom_domain varchar(255) NOT NULL DEFAULT '',
             version integer NOT NULL DEFAULT 0,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			deleted_at TIMESTAMPTZ NOT NULL DEFAULT '0001-01-01 00:00:00+00'
-		)`, postsql.InstancesTableName),
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (custom_domain) WHERE custom_domain <> ''`,
+			postsql.InstancesTableName, postsql.InstancesCustomDomainIndex, postsql.InstancesTableName),
 		postsql.OperationTableName: fmt.Sprintf(
 			`CREATE TABLE IF NOT EXISTS %s (
 			id varchar(255) PRIMARY KEY,
@@ -414,6 +418,10 @@ func FixTables() map[string]string {
 			provisioning_parameters json NOT NULL,
 			orchestration_id varchar(64),
             finished_stages text,
+			current_step varchar(255),
+			correlation_id varchar(64),
+			trace_parent varchar(64),
+			failure_reason text,
 			created_at TIMESTAMPTZ NOT NULL,
 			updated_at TIMESTAMPTZ NOT NULL
 			)`, postsql.OperationTableName),
@@ -463,15 +471,61 @@ func FixTables() map[string]string {
 			skr_instance_id varchar(255) NOT NULL,
 			FOREIGN KEY(cls_instance_id) REFERENCES %s(id) ON DELETE CASCADE);
 			`, postsql.CLSInstanceTableName, postsql.CLSInstanceReferenceTableName, postsql.CLSInstanceTableName),
+		postsql.BindingsTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id varchar(255) PRIMARY KEY,
+			instance_id varchar(255) NOT NULL,
+			operation_id varchar(255) NOT NULL,
+			operation_type varchar(32) NOT NULL,
+			state varchar(32) NOT NULL,
+			description text,
+			credentials text,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			version integer NOT NULL
+			)`, postsql.BindingsTableName),
+		postsql.WorkItemsTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			queue varchar(64) NOT NULL,
+			operation_id varchar(255) NOT NULL,
+			sub_account_id varchar(255),
+			created_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (queue, operation_id)
+			)`, postsql.WorkItemsTableName),
+		postsql.InstanceFlagsTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			instance_id varchar(255) PRIMARY KEY,
+			flags text NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+			)`, postsql.InstanceFlagsTableName),
+		postsql.SubsystemMarkersTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			instance_id varchar(255) NOT NULL,
+			subsystem varchar(64) NOT NULL,
+			operation_id varchar(255) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (instance_id, subsystem)
+			)`, postsql.SubsystemMarkersTableName),
+		postsql.CatalogSnapshotsTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			etag varchar(255) PRIMARY KEY,
+			catalog text NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+			)`, postsql.CatalogSnapshotsTableName),
 	}
 }
 
 func clearDBQuery() string {
-	return fmt.Sprintf("TRUNCATE TABLE %s, %s, %s, %s, %s RESTART IDENTITY CASCADE",
+	return fmt.Sprintf("TRUNCATE TABLE %s, %s, %s, %s, %s, %s, %s, %s, %s, %s RESTART IDENTITY CASCADE",
 		postsql.InstancesTableName,
 		postsql.OperationTableName,
 		postsql.OrchestrationTableName,
 		postsql.LMSTenantTableName,
 		postsql.RuntimeStateTableName,
+		postsql.BindingsTableName,
+		postsql.WorkItemsTableName,
+		postsql.InstanceFlagsTableName,
+		postsql.SubsystemMarkersTableName,
+		postsql.CatalogSnapshotsTableName,
 	)
 }
@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"time"
+)
+
+// SubsystemEDP is the subsystem marked when the instance is registered in EDP
+const SubsystemEDP = "edp"
+
+// SubsystemMarker records that the operation completed the setup of the subsystem for the instance,
+// the instances without the marker, e.g. provisioned before the subsystem was integrated, are backfilled
+type SubsystemMarker struct {
+	InstanceID  string
+	Subsystem   string
+	OperationID string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,86 @@
+package subsystems
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// MissingInstancesDTO lists the instances without the marker of the subsystem, the runtime IDs can be used
+// as the targets of the orchestration which backfills the subsystem
+type MissingInstancesDTO struct {
+	Subsystem string        `json:"subsystem"`
+	Data      []InstanceDTO `json:"data"`
+	Count     int           `json:"count"`
+}
+
+type InstanceDTO struct {
+	InstanceID      string    `json:"instanceID"`
+	RuntimeID       string    `json:"runtimeID"`
+	GlobalAccountID string    `json:"globalAccountID"`
+	SubAccountID    string    `json:"subAccountID"`
+	PlanName        string    `json:"planName"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type missingInstancesHandler struct {
+	markers storage.SubsystemMarkers
+	known   map[string]struct{}
+	log     logrus.FieldLogger
+}
+
+// NewMissingInstancesHandler exposes the GET /admin/subsystems/{subsystem}/missing-instances endpoint,
+// only the subsystems marked by the broker can be queried
+func NewMissingInstancesHandler(markers storage.SubsystemMarkers, known []string, log logrus.FieldLogger) http.Handler {
+	h := &missingInstancesHandler{
+		markers: markers,
+		known:   map[string]struct{}{},
+		log:     log.WithField("service", "MissingInstancesHandler"),
+	}
+	for _, subsystem := range known {
+		h.known[subsystem] = struct{}{}
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/subsystems/{subsystem}/missing-instances", h.listMissingInstances).Methods(http.MethodGet)
+
+	return router
+}
+
+func (h *missingInstancesHandler) listMissingInstances(w http.ResponseWriter, r *http.Request) {
+	subsystem := mux.Vars(r)["subsystem"]
+	if _, found := h.known[subsystem]; !found {
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Errorf("subsystem %s is not marked by the broker", subsystem))
+		return
+	}
+
+	instances, err := h.markers.ListInstancesWithout(subsystem)
+	if err != nil {
+		h.log.Errorf("while listing instances without subsystem %s: %v", subsystem, err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while listing instances without subsystem %s", subsystem))
+		return
+	}
+
+	response := MissingInstancesDTO{
+		Subsystem: subsystem,
+		Data:      make([]InstanceDTO, 0, len(instances)),
+		Count:     len(instances),
+	}
+	for _, instance := range instances {
+		response.Data = append(response.Data, InstanceDTO{
+			InstanceID:      instance.InstanceID,
+			RuntimeID:       instance.RuntimeID,
+			GlobalAccountID: instance.GlobalAccountID,
+			SubAccountID:    instance.SubAccountID,
+			PlanName:        instance.ServicePlanName,
+			CreatedAt:       instance.CreatedAt,
+		})
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,95 @@
+package subsystems
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingInstancesHandler(t *testing.T) {
+	t.Run("should list instances without marker of subsystem", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		now := time.Now()
+		for i, id := range []string{"instance-1", "instance-2", "instance-3"} {
+			instance := fixture.FixInstance(id)
+			instance.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+			require.NoError(t, s.Instances().Insert(instance))
+		}
+		require.NoError(t, s.SubsystemMarkers().Insert(internal.SubsystemMarker{
+			InstanceID:  "instance-2",
+			Subsystem:   internal.SubsystemEDP,
+			OperationID: "op-2",
+			CreatedAt:   now,
+		}))
+		// marker of other subsystem does not count
+		require.NoError(t, s.SubsystemMarkers().Insert(internal.SubsystemMarker{
+			InstanceID:  "instance-3",
+			Subsystem:   "other",
+			OperationID: "op-3",
+			CreatedAt:   now,
+		}))
+		handler := NewMissingInstancesHandler(s.SubsystemMarkers(), []string{internal.SubsystemEDP}, logrus.New())
+
+		// when
+		rr := callMissingInstances(handler, internal.SubsystemEDP)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out MissingInstancesDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, internal.SubsystemEDP, out.Subsystem)
+		assert.Equal(t, 2, out.Count)
+		require.Len(t, out.Data, 2)
+		assert.Equal(t, "instance-1", out.Data[0].InstanceID)
+		assert.Equal(t, "instance-3", out.Data[1].InstanceID)
+	})
+
+	t.Run("should return empty list when all instances are marked", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		require.NoError(t, s.Instances().Insert(fixture.FixInstance("instance-1")))
+		require.NoError(t, s.SubsystemMarkers().Insert(internal.SubsystemMarker{InstanceID: "instance-1", Subsystem: internal.SubsystemEDP, OperationID: "op-1"}))
+		handler := NewMissingInstancesHandler(s.SubsystemMarkers(), []string{internal.SubsystemEDP}, logrus.New())
+
+		// when
+		rr := callMissingInstances(handler, internal.SubsystemEDP)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out MissingInstancesDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Zero(t, out.Count)
+		assert.Empty(t, out.Data)
+	})
+
+	t.Run("should return not found for unknown subsystem", func(t *testing.T) {
+		// given
+		s := storage.NewMemoryStorage()
+		handler := NewMissingInstancesHandler(s.SubsystemMarkers(), []string{internal.SubsystemEDP}, logrus.New())
+
+		// when
+		rr := callMissingInstances(handler, "unknown")
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func callMissingInstances(handler http.Handler, subsystem string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/admin/subsystems/%s/missing-instances", subsystem), nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
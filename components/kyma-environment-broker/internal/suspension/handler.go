@@ -9,6 +9,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
@@ -40,11 +41,7 @@ func NewContextUpdateHandler(operations storage.Operations, provisioningQueue Ad
 // Handle performs suspension/unsuspension for given instance.
 // Applies only when 'Active' parameter has changes and ServicePlanID is `Trial`
 func (h *ContextUpdateHandler) Handle(instance *internal.Instance, newCtx internal.ERSContext) error {
-	l := h.log.WithFields(logrus.Fields{
-		"instanceID":      instance.InstanceID,
-		"runtimeID":       instance.RuntimeID,
-		"globalAccountID": instance.GlobalAccountID,
-	})
+	l := h.instanceLogger(instance)
 
 	if !broker.IsTrialPlan(instance.ServicePlanID) {
 		l.Info("Context update for non-trial instance, skipping")
@@ -54,6 +51,50 @@ func (h *ContextUpdateHandler) Handle(instance *internal.Instance, newCtx intern
 	return h.handleContextChange(newCtx, instance, l)
 }
 
+// Suspend suspends the trial instance on the broker's own initiative, e.g. when it is idle. The instance is marked
+// as inactive and is resumed by the next broker call for the instance, see Resume. The instance must be stored by the caller.
+func (h *ContextUpdateHandler) Suspend(instance *internal.Instance, reason string) error {
+	if !broker.IsTrialPlan(instance.ServicePlanID) {
+		return errors.Errorf("instance %s is not a trial instance", instance.InstanceID)
+	}
+
+	instance.Parameters.ErsContext.Active = ptr.Bool(false)
+	return h.suspend(instance, reason, h.instanceLogger(instance))
+}
+
+// Resume resumes the trial instance suspended by the broker because it was idle. The platform is not aware of such
+// suspension and never sends the context update activating the instance, so the instance is resumed on the next
+// broker call for it instead. The resume is deferred to the next call until the suspension is finished, so the runtime
+// is not provisioned again while it is being removed. It returns false if the instance was not resumed.
+// The instance must be stored by the caller.
+func (h *ContextUpdateHandler) Resume(instance *internal.Instance) (bool, error) {
+	if instance.SuspensionReason != internal.SuspensionReasonIdle {
+		return false, nil
+	}
+
+	l := h.instanceLogger(instance)
+	lastDeprovisioning, err := h.operations.GetDeprovisioningOperationByInstanceID(instance.InstanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && (lastDeprovisioning.State == domain.InProgress || lastDeprovisioning.State == orchestration.Pending) {
+		l.Infof("Suspension %s is in progress, deferring resume", lastDeprovisioning.ID)
+		return false, nil
+	}
+
+	l.Info("Resuming instance suspended because of idleness")
+	instance.Parameters.ErsContext.Active = ptr.Bool(true)
+	return true, h.unsuspend(instance, l)
+}
+
+func (h *ContextUpdateHandler) instanceLogger(instance *internal.Instance) logrus.FieldLogger {
+	return h.log.WithFields(logrus.Fields{
+		"instanceID":      instance.InstanceID,
+		"runtimeID":       instance.RuntimeID,
+		"globalAccountID": instance.GlobalAccountID,
+	})
+}
+
 func (h *ContextUpdateHandler) handleContextChange(newCtx internal.ERSContext, instance *internal.Instance, l logrus.FieldLogger) error {
 	isActivated := true
 	if instance.Parameters.ErsContext.Active != nil {
@@ -77,7 +118,7 @@ func (h *ContextUpdateHandler) handleContextChange(newCtx internal.ERSContext, i
 
 			if lastDeprovisioning.Temporary && lastDeprovisioning.State == domain.Failed {
 				l.Infof("Retriggering suspension for instance id %s", instance.InstanceID)
-				return h.suspend(instance, l)
+				return h.suspend(instance, suspensionReason(newCtx), l)
 			}
 			return nil
 		}
@@ -86,11 +127,28 @@ func (h *ContextUpdateHandler) handleContextChange(newCtx internal.ERSContext, i
 	if *newCtx.Active {
 		return h.unsuspend(instance, l)
 	} else {
-		return h.suspend(instance, l)
+		return h.suspend(instance, suspensionReason(newCtx), l)
 	}
 }
 
-func (h *ContextUpdateHandler) suspend(instance *internal.Instance, log logrus.FieldLogger) error {
+// suspensionReason returns the reason passed in the context, trial instances are deactivated by the platform
+// when the trial expires, so it is the reason if none is passed
+func suspensionReason(ctx internal.ERSContext) string {
+	switch ctx.SuspensionReason {
+	case "":
+		return internal.SuspensionReasonTrialExpired
+	case internal.SuspensionReasonTrialExpired, internal.SuspensionReasonManual, internal.SuspensionReasonQuotaExceeded:
+		return ctx.SuspensionReason
+	default:
+		return internal.SuspensionReasonUnknown
+	}
+}
+
+func (h *ContextUpdateHandler) suspend(instance *internal.Instance, reason string, log logrus.FieldLogger) error {
+	// the instance is stored by the caller after the context update is handled
+	instance.SuspensionReason = reason
+	log.Infof("Suspending instance, reason: %s", reason)
+
 	lastDeprovisioning, err := h.operations.GetDeprovisioningOperationByInstanceID(instance.InstanceID)
 	// there was an error - fail
 	if err != nil && !dberr.IsNotFound(err) {
@@ -114,6 +172,7 @@ func (h *ContextUpdateHandler) suspend(instance *internal.Instance, log logrus.F
 }
 
 func (h *ContextUpdateHandler) unsuspend(instance *internal.Instance, log logrus.FieldLogger) error {
+	instance.SuspensionReason = ""
 	id := uuid.New().String()
 
 	operation, err := internal.NewProvisioningOperationWithID(id, instance.InstanceID, instance.Parameters)
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
@@ -36,6 +37,47 @@ func TestSuspension(t *testing.T) {
 
 	assert.Equal(t, domain.LastOperationState("pending"), op.State)
 	assert.Equal(t, instance.InstanceID, op.InstanceID)
+	assert.Equal(t, internal.SuspensionReasonTrialExpired, instance.SuspensionReason)
+}
+
+func TestSuspension_Reason(t *testing.T) {
+	for name, tc := range map[string]struct {
+		passedReason   string
+		expectedReason string
+	}{
+		"trial expiry is the default reason": {
+			passedReason:   "",
+			expectedReason: internal.SuspensionReasonTrialExpired,
+		},
+		"manual suspension": {
+			passedReason:   internal.SuspensionReasonManual,
+			expectedReason: internal.SuspensionReasonManual,
+		},
+		"exceeded quota": {
+			passedReason:   internal.SuspensionReasonQuotaExceeded,
+			expectedReason: internal.SuspensionReasonQuotaExceeded,
+		},
+		"unsupported reason": {
+			passedReason:   "maintenance",
+			expectedReason: internal.SuspensionReasonUnknown,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			st := storage.NewMemoryStorage()
+			svc := NewContextUpdateHandler(st.Operations(), NewDummyQueue(), NewDummyQueue(), logrus.New())
+			instance := fixInstance(fixActiveErsContext())
+			ersContext := fixInactiveErsContext()
+			ersContext.SuspensionReason = tc.passedReason
+
+			// when
+			err := svc.Handle(instance, ersContext)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedReason, instance.SuspensionReason)
+		})
+	}
 }
 
 func TestSuspension_Retrigger(t *testing.T) {
@@ -127,6 +169,7 @@ func TestUnsuspension(t *testing.T) {
 	instance := fixInstance(fixInactiveErsContext())
 	instance.InstanceDetails.ShootName = "c-012345"
 	instance.InstanceDetails.ShootDomain = "c-012345.sap.com"
+	instance.SuspensionReason = internal.SuspensionReasonManual
 
 	st.Instances().Insert(*instance)
 
@@ -144,6 +187,7 @@ func TestUnsuspension(t *testing.T) {
 	assert.Equal(t, instance.InstanceID, op.InstanceID)
 	assert.Equal(t, "c-012345", op.ShootName)
 	assert.Equal(t, "c-012345.sap.com", op.ShootDomain)
+	assert.Empty(t, instance.SuspensionReason)
 }
 
 func TestUnsuspensionWithoutShootname(t *testing.T) {
@@ -176,6 +220,90 @@ func TestUnsuspensionWithoutShootname(t *testing.T) {
 	assert.Equal(t, "c-7f1eb9e.kyma-dev.shoot.canary.k8s-hana.ondemand.com", op.ShootDomain)
 }
 
+func TestResume(t *testing.T) {
+	t.Run("should resume the instance suspended because of idleness", func(t *testing.T) {
+		// given
+		provisioning := NewDummyQueue()
+		deprovisioning := NewDummyQueue()
+		st := storage.NewMemoryStorage()
+
+		svc := NewContextUpdateHandler(st.Operations(), provisioning, deprovisioning, logrus.New())
+		instance := fixInstance(fixInactiveErsContext())
+		instance.InstanceDetails.ShootName = "c-012345"
+		instance.InstanceDetails.ShootDomain = "c-012345.sap.com"
+		instance.SuspensionReason = internal.SuspensionReasonIdle
+
+		// when
+		resumed, err := svc.Resume(instance)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, resumed)
+		op, err := st.Operations().GetProvisioningOperationByInstanceID("instance-id")
+		require.NoError(t, err)
+		assertQueue(t, deprovisioning)
+		assertQueue(t, provisioning, op.ID)
+
+		assert.Equal(t, "c-012345", op.ShootName)
+		assert.Empty(t, instance.SuspensionReason)
+		assert.True(t, *instance.Parameters.ErsContext.Active)
+	})
+
+	for name, state := range map[string]domain.LastOperationState{
+		"pending":     orchestration.Pending,
+		"in progress": domain.InProgress,
+	} {
+		t.Run("should defer the resume while the suspension is "+name, func(t *testing.T) {
+			// given
+			provisioning := NewDummyQueue()
+			st := storage.NewMemoryStorage()
+
+			svc := NewContextUpdateHandler(st.Operations(), provisioning, NewDummyQueue(), logrus.New())
+			instance := fixInstance(fixInactiveErsContext())
+			instance.SuspensionReason = internal.SuspensionReasonIdle
+			suspension := internal.NewSuspensionOperationWithID("suspension-id", instance)
+			suspension.State = state
+			require.NoError(t, st.Operations().InsertDeprovisioningOperation(suspension))
+
+			// when
+			resumed, err := svc.Resume(instance)
+			require.NoError(t, err)
+
+			// then
+			assert.False(t, resumed)
+			assertQueue(t, provisioning)
+			assert.Equal(t, internal.SuspensionReasonIdle, instance.SuspensionReason)
+			assert.False(t, *instance.Parameters.ErsContext.Active)
+		})
+	}
+
+	for name, reason := range map[string]string{
+		"not suspended instance":              "",
+		"instance suspended by trial expiry":  internal.SuspensionReasonTrialExpired,
+		"instance suspended by the operators": internal.SuspensionReasonManual,
+	} {
+		t.Run("should skip "+name, func(t *testing.T) {
+			// given
+			provisioning := NewDummyQueue()
+			st := storage.NewMemoryStorage()
+
+			svc := NewContextUpdateHandler(st.Operations(), provisioning, NewDummyQueue(), logrus.New())
+			instance := fixInstance(fixInactiveErsContext())
+			instance.SuspensionReason = reason
+
+			// when
+			resumed, err := svc.Resume(instance)
+			require.NoError(t, err)
+
+			// then
+			assert.False(t, resumed)
+			assertQueue(t, provisioning)
+			assert.Equal(t, reason, instance.SuspensionReason)
+			assert.False(t, *instance.Parameters.ErsContext.Active)
+		})
+	}
+}
+
 func fixInstance(ersContext internal.ERSContext) *internal.Instance {
 	instance := fixture.FixInstance("instance-id")
 	instance.ServicePlanID = broker.TrialPlanID
@@ -0,0 +1,187 @@
+package suspension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// ActivitySourceInstance takes the last update of the instance through the broker API as its last activity
+	ActivitySourceInstance = "instance"
+	// ActivitySourceHTTP fetches the last API access of the runtime from the external service
+	ActivitySourceHTTP = "http"
+)
+
+type IdleTrialsConfig struct {
+	// Enabled makes the broker suspend the trial runtimes which show no activity for the idle period
+	Enabled bool `envconfig:"default=false"`
+	// IdlePeriod defines how long the trial runtime must show no activity to be suspended
+	IdlePeriod time.Duration `envconfig:"default=168h"`
+	// CheckInterval defines how often the activity of the trial runtimes is checked
+	CheckInterval time.Duration `envconfig:"default=1h"`
+	// ActivitySource defines where the last activity of the runtime is taken from, instance or http
+	ActivitySource string `envconfig:"default=instance"`
+	// ActivityURL defines the URL of the service returning the last API access of the runtime for the http source,
+	// the runtime ID is appended to the URL
+	ActivityURL string `envconfig:"optional"`
+	// ActivityTimeout limits the time of fetching the activity of a single runtime for the http source
+	ActivityTimeout time.Duration `envconfig:"default=10s"`
+}
+
+// ActivitySource returns the time of the last activity of the runtime of the instance,
+// false is returned when the activity is not known
+type ActivitySource interface {
+	LastActivity(instance internal.Instance) (time.Time, bool, error)
+}
+
+// NewActivitySource creates the activity source configured in the config
+func NewActivitySource(cfg IdleTrialsConfig) (ActivitySource, error) {
+	switch cfg.ActivitySource {
+	case ActivitySourceInstance:
+		return instanceActivity{}, nil
+	case ActivitySourceHTTP:
+		if cfg.ActivityURL == "" {
+			return nil, errors.New("activity URL is required for the http activity source")
+		}
+		return &httpActivity{
+			url:        strings.TrimSuffix(cfg.ActivityURL, "/"),
+			httpClient: &http.Client{Timeout: cfg.ActivityTimeout},
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown activity source %q", cfg.ActivitySource)
+	}
+}
+
+type instanceActivity struct{}
+
+func (instanceActivity) LastActivity(instance internal.Instance) (time.Time, bool, error) {
+	if instance.UpdatedAt.After(instance.CreatedAt) {
+		return instance.UpdatedAt, true, nil
+	}
+	return instance.CreatedAt, !instance.CreatedAt.IsZero(), nil
+}
+
+type httpActivity struct {
+	url        string
+	httpClient *http.Client
+}
+
+type activityDTO struct {
+	LastAccess time.Time `json:"lastAccess"`
+}
+
+func (a *httpActivity) LastActivity(instance internal.Instance) (time.Time, bool, error) {
+	if instance.RuntimeID == "" {
+		return time.Time{}, false, nil
+	}
+	resp, err := a.httpClient.Get(fmt.Sprintf("%s/%s", a.url, instance.RuntimeID))
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "while fetching activity of the runtime %s", instance.RuntimeID)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return time.Time{}, false, nil
+	default:
+		return time.Time{}, false, errors.Errorf("got unexpected status %d while fetching activity of the runtime %s", resp.StatusCode, instance.RuntimeID)
+	}
+	var activity activityDTO
+	if err := json.NewDecoder(resp.Body).Decode(&activity); err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "while decoding activity of the runtime %s", instance.RuntimeID)
+	}
+	return activity.LastAccess, !activity.LastAccess.IsZero(), nil
+}
+
+// Suspender suspends the instance on the broker's own initiative
+type Suspender interface {
+	Suspend(instance *internal.Instance, reason string) error
+}
+
+// IdleTrialsReconciler suspends the active trial instances which show no activity for the idle period,
+// the instances with unknown activity are left alone
+type IdleTrialsReconciler struct {
+	instances  storage.Instances
+	suspender  Suspender
+	activity   ActivitySource
+	idlePeriod time.Duration
+	log        logrus.FieldLogger
+
+	now func() time.Time
+}
+
+func NewIdleTrialsReconciler(instances storage.Instances, suspender Suspender, activity ActivitySource, idlePeriod time.Duration, log logrus.FieldLogger) *IdleTrialsReconciler {
+	return &IdleTrialsReconciler{
+		instances:  instances,
+		suspender:  suspender,
+		activity:   activity,
+		idlePeriod: idlePeriod,
+		log:        log.WithField("service", "IdleTrialsReconciler"),
+		now:        time.Now,
+	}
+}
+
+// Run reconciles the trial instances every interval until the context is done
+func (r *IdleTrialsReconciler) Run(ctx context.Context, interval time.Duration) {
+	go wait.Until(func() {
+		if err := r.Reconcile(); err != nil {
+			r.log.Errorf("while suspending idle trial instances: %s", err)
+		}
+	}, interval, ctx.Done())
+}
+
+// Reconcile checks the activity of all active trial instances once
+func (r *IdleTrialsReconciler) Reconcile() error {
+	instances, _, _, err := r.instances.List(dbmodel.InstanceFilter{
+		Plans:  []string{broker.TrialPlanName},
+		States: []dbmodel.InstanceState{dbmodel.InstanceSucceeded},
+	})
+	if err != nil {
+		return errors.Wrap(err, "while listing trial instances")
+	}
+
+	idleSince := r.now().Add(-r.idlePeriod)
+	suspended := 0
+	for _, instance := range instances {
+		if active := instance.Parameters.ErsContext.Active; active != nil && !*active {
+			continue
+		}
+		log := r.log.WithField("instanceID", instance.InstanceID)
+		lastActivity, known, err := r.activity.LastActivity(instance)
+		if err != nil {
+			log.Errorf("while getting last activity: %s", err)
+			continue
+		}
+		if !known || lastActivity.After(idleSince) {
+			continue
+		}
+
+		log.Infof("Trial instance is idle since %s, suspending", lastActivity.Format(time.RFC3339))
+		if err := r.suspender.Suspend(&instance, internal.SuspensionReasonIdle); err != nil {
+			log.Errorf("while suspending idle instance: %s", err)
+			continue
+		}
+		if _, err := r.instances.Update(instance); err != nil {
+			log.Errorf("while updating suspended instance: %s", err)
+			continue
+		}
+		suspended++
+	}
+	r.log.Infof("Checked activity of %d trial instances, %d idle for %s suspended", len(instances), suspended, r.idlePeriod)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package suspension
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleTrialsReconciler(t *testing.T) {
+	// given
+	now := time.Now()
+	st := storage.NewMemoryStorage()
+	deprovisioning := NewDummyQueue()
+	handler := NewContextUpdateHandler(st.Operations(), NewDummyQueue(), deprovisioning, logrus.New())
+
+	idle := fixTrialInstance("idle-instance", now.Add(-8*24*time.Hour))
+	require.NoError(t, st.Instances().Insert(*idle))
+	active := fixTrialInstance("active-instance", now.Add(-time.Hour))
+	require.NoError(t, st.Instances().Insert(*active))
+
+	reconciler := NewIdleTrialsReconciler(st.Instances(), handler, instanceActivity{}, 7*24*time.Hour, logrus.New())
+	reconciler.now = func() time.Time { return now }
+
+	// when
+	err := reconciler.Reconcile()
+
+	// then
+	require.NoError(t, err)
+
+	op, err := st.Operations().GetDeprovisioningOperationByInstanceID(idle.InstanceID)
+	require.NoError(t, err)
+	assert.True(t, op.Temporary)
+	assert.Equal(t, domain.LastOperationState("pending"), op.State)
+	assertQueue(t, deprovisioning, op.ID)
+
+	suspended, err := st.Instances().GetByID(idle.InstanceID)
+	require.NoError(t, err)
+	assert.Equal(t, internal.SuspensionReasonIdle, suspended.SuspensionReason)
+	require.NotNil(t, suspended.Parameters.ErsContext.Active)
+	assert.False(t, *suspended.Parameters.ErsContext.Active)
+
+	_, err = st.Operations().GetDeprovisioningOperationByInstanceID(active.InstanceID)
+	assert.Error(t, err)
+	notSuspended, err := st.Instances().GetByID(active.InstanceID)
+	require.NoError(t, err)
+	assert.Empty(t, notSuspended.SuspensionReason)
+	assert.True(t, *notSuspended.Parameters.ErsContext.Active)
+}
+
+func TestHTTPActivitySource(t *testing.T) {
+	// given
+	lastAccess := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/activity/runtime-known":
+			fmt.Fprintf(w, `{"lastAccess": %q}`, lastAccess.Format(time.RFC3339))
+		case "/activity/runtime-failing":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := NewActivitySource(IdleTrialsConfig{ActivitySource: ActivitySourceHTTP, ActivityURL: server.URL + "/activity/", ActivityTimeout: time.Second})
+	require.NoError(t, err)
+
+	t.Run("should return the last access of the runtime", func(t *testing.T) {
+		// when
+		activity, known, err := source.LastActivity(internal.Instance{RuntimeID: "runtime-known"})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, known)
+		assert.True(t, lastAccess.Equal(activity))
+	})
+
+	t.Run("should return unknown activity of the runtime not found", func(t *testing.T) {
+		// when
+		_, known, err := source.LastActivity(internal.Instance{RuntimeID: "runtime-unknown"})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, known)
+	})
+
+	t.Run("should return error for unexpected status", func(t *testing.T) {
+		// when
+		_, _, err := source.LastActivity(internal.Instance{RuntimeID: "runtime-failing"})
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func fixTrialInstance(id string, lastActivity time.Time) *internal.Instance {
+	instance := fixInstance(fixActiveErsContext())
+	instance.InstanceID = id
+	instance.ServicePlanName = broker.TrialPlanName
+	instance.CreatedAt = lastActivity.Add(-time.Hour)
+	instance.UpdatedAt = lastActivity
+
+	return instance
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"contrib.go.opencensus.io/exporter/zipkin"
+	"github.com/openzipkin/zipkin-go/model"
+	zipkinhttp "github.com/openzipkin/zipkin-go/reporter/http"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+// Config configures the export of the traces, the traces are not recorded if the Zipkin URL is empty
+type Config struct {
+	// ZipkinURL is the URL of the Zipkin v2 spans endpoint, e.g. http://otel-collector:9411/api/v2/spans
+	ZipkinURL   string `envconfig:"optional"`
+	ServiceName string `envconfig:"default=kyma-environment-broker"`
+}
+
+// format propagates the spans in the W3C traceparent, the same format is used to store the root spans of the operations
+var format = &tracecontext.HTTPFormat{}
+
+// Setup registers the exporter which reports every span to the configured Zipkin endpoint.
+// If no endpoint is configured, no span is sampled. The returned function flushes the spans and stops the exporter.
+func Setup(cfg Config) (func(), error) {
+	if cfg.ZipkinURL == "" {
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+		return func() {}, nil
+	}
+
+	reporter := zipkinhttp.NewReporter(cfg.ZipkinURL)
+	exporter := zipkin.NewExporter(reporter, &model.Endpoint{ServiceName: cfg.ServiceName})
+	trace.RegisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+
+	return func() {
+		trace.UnregisterExporter(exporter)
+		reporter.Close()
+	}, nil
+}
+
+// NewTransport returns the transport which traces the requests as children of the span from the request context
+// and propagates the span in the traceparent header
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return &ochttp.Transport{Base: base, Propagation: format}
+}
+
+// StartSpan starts the span which is a child of the remote span given by the W3C traceparent,
+// or of the span from the context if the traceparent is empty or invalid
+func StartSpan(ctx context.Context, name, traceParent string, attributes ...trace.Attribute) (context.Context, *trace.Span) {
+	var span *trace.Span
+	if parent, ok := ParseTraceParent(traceParent); ok {
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, name, parent)
+	} else {
+		ctx, span = trace.StartSpan(ctx, name)
+	}
+	span.AddAttributes(attributes...)
+	return ctx, span
+}
+
+// EndSpan marks the span as failed if the error is not nil and ends the span
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// TraceParent returns the W3C traceparent of the span from the context, empty if the context has no sampled span
+func TraceParent(ctx context.Context) string {
+	span := trace.FromContext(ctx)
+	if span == nil || !span.SpanContext().IsSampled() {
+		return ""
+	}
+	traceParent, _ := format.SpanContextToHeaders(span.SpanContext())
+	return traceParent
+}
+
+// ParseTraceParent parses the span context from the W3C traceparent
+func ParseTraceParent(traceParent string) (trace.SpanContext, bool) {
+	if traceParent == "" {
+		return trace.SpanContext{}, false
+	}
+	return format.SpanContextFromHeaders(traceParent, "")
+}
+
+// TraceID returns the trace ID of the sampled span from the context, or of the span given by the W3C traceparent
+// if the context has no sampled span. It returns empty string if there is no such span.
+func TraceID(ctx context.Context, traceParent string) string {
+	if span := trace.FromContext(ctx); span != nil && span.SpanContext().IsSampled() {
+		return span.SpanContext().TraceID.String()
+	}
+	if sc, ok := ParseTraceParent(traceParent); ok && sc.IsSampled() {
+		return sc.TraceID.String()
+	}
+	return ""
+}
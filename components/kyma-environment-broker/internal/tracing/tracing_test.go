@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fixTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	fixTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+func TestStartSpan(t *testing.T) {
+	t.Run("should start the child of the span given by the traceparent", func(t *testing.T) {
+		// when
+		ctx, span := StartSpan(context.Background(), "step", fixTraceParent)
+		defer span.End()
+
+		// then
+		assert.Equal(t, fixTraceID, span.SpanContext().TraceID.String())
+		assert.True(t, strings.HasPrefix(TraceParent(ctx), "00-"+fixTraceID+"-"))
+		assert.NotEqual(t, fixTraceParent, TraceParent(ctx))
+		assert.Equal(t, fixTraceID, TraceID(ctx, ""))
+	})
+
+	t.Run("should ignore the invalid traceparent", func(t *testing.T) {
+		// when
+		_, span := StartSpan(context.Background(), "step", "invalid")
+		defer span.End()
+
+		// then
+		assert.NotEqual(t, fixTraceID, span.SpanContext().TraceID.String())
+	})
+}
+
+func TestTraceID(t *testing.T) {
+	assert.Equal(t, fixTraceID, TraceID(context.Background(), fixTraceParent))
+	assert.Empty(t, TraceID(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"))
+	assert.Empty(t, TraceID(context.Background(), ""))
+}
+
+func TestNewTransport(t *testing.T) {
+	// given
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport)}
+
+	ctx, span := StartSpan(context.Background(), "step", fixTraceParent)
+	defer span.End()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	// when
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// then
+	assert.True(t, strings.HasPrefix(received, "00-"+fixTraceID+"-"))
+	assert.NotEqual(t, TraceParent(ctx), received)
+}
@@ -0,0 +1,16 @@
+package internal
+
+import (
+	"time"
+)
+
+// WorkItem is the operation added to the processing queue which is not processed yet,
+// the work items of the queue are added back to the queue after the restart of the broker
+type WorkItem struct {
+	Queue        string
+	OperationID  string
+	SubAccountID string
+	// Priority is the priority with which the operation is added back to the queue
+	Priority  int
+	CreatedAt time.Time
+}
@@ -128,6 +128,24 @@ func (r *Resolver) ReconnectRuntimeAgent(ctx context.Context, id string) (string
 	return "", nil
 }
 
+func (r *Resolver) RevokeRuntimeCredentials(ctx context.Context, runtimeID string) (string, error) {
+	log.Infof("Requested to revoke credentials for Runtime %s.", runtimeID)
+
+	_, err := r.getAndValidateTenant(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to revoke credentials for Runtime %s: %s", runtimeID, err)
+		return "", err
+	}
+
+	id, err := r.provisioning.RevokeRuntimeCredentials(runtimeID)
+	if err != nil {
+		log.Errorf("Failed to revoke credentials for Runtime %s: %s", runtimeID, err)
+		return "", err
+	}
+
+	return id, nil
+}
+
 func (r *Resolver) RuntimeStatus(ctx context.Context, runtimeID string) (*gqlschema.RuntimeStatus, error) {
 	log.Infof("Requested to get status for Runtime %s.", runtimeID)
 
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net"
+	"regexp"
 	"strings"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
@@ -12,6 +14,9 @@ import (
 
 const RuntimeAgent = "compass-runtime-agent"
 
+// maintenanceTimeRegexp matches the time of the Gardener maintenance window, e.g. 220000+0100
+var maintenanceTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3])[0-5][0-9][0-5][0-9][+-][0-9]{4}$`)
+
 //go:generate mockery -name=Validator
 type Validator interface {
 	ValidateProvisioningInput(input gqlschema.ProvisionRuntimeInput) apperrors.AppError
@@ -80,6 +85,10 @@ func (v *validator) ValidateUpgradeShootInput(input gqlschema.UpgradeShootInput)
 		return apperrors.BadRequest("empty purpose provided")
 	}
 
+	if err := v.validateKubernetesConfig(config.KubernetesConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -147,6 +156,26 @@ func (v *validator) validateClusterConfig(clusterConfig *gqlschema.ClusterConfig
 		return err
 	}
 
+	if err := v.validateEncryptionKey(gardenerConfig.ProviderSpecificConfig); err != nil {
+		return err
+	}
+
+	if err := v.validateNetworking(gardenerConfig.Networking); err != nil {
+		return err
+	}
+
+	if err := v.validateControlPlane(gardenerConfig.ControlPlane); err != nil {
+		return err
+	}
+
+	if err := v.validateMaintenanceWindow(gardenerConfig.MaintenanceWindow); err != nil {
+		return err
+	}
+
+	if err := v.validateKubernetesConfig(gardenerConfig.KubernetesConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -167,6 +196,98 @@ func (v *validator) validateOpenStackVolume(diskType *string, volumeSizeGb *int,
 	return nil
 }
 
+// Only the GCP provider extension allows to pass the customer-managed key for the worker disks
+func (v *validator) validateEncryptionKey(providerConfig *gqlschema.ProviderSpecificInput) apperrors.AppError {
+	if providerConfig == nil {
+		return nil
+	}
+	if providerConfig.AzureConfig != nil && util.NotNilOrEmpty(providerConfig.AzureConfig.EncryptionKey) {
+		return apperrors.BadRequest("error: customer-managed encryption keys are not supported for Azure clusters")
+	}
+	if providerConfig.AwsConfig != nil && util.NotNilOrEmpty(providerConfig.AwsConfig.EncryptionKey) {
+		return apperrors.BadRequest("error: customer-managed encryption keys are not supported for AWS clusters")
+	}
+	return nil
+}
+
+// The API server of a private cluster must stay reachable at least from the control plane which installs Kyma
+func (v *validator) validateNetworking(networking *gqlschema.NetworkingInput) apperrors.AppError {
+	if networking == nil {
+		return nil
+	}
+	for _, cidr := range networking.AllowedCidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return apperrors.BadRequest("error: allowed CIDR %q is not valid: %s", cidr, err.Error())
+		}
+	}
+	if !util.UnwrapBoolOrDefault(networking.PrivateCluster, false) {
+		if len(networking.AllowedCidrs) > 0 {
+			return apperrors.BadRequest("error: allowed CIDRs can be specified only for a private cluster")
+		}
+		return nil
+	}
+	if len(networking.AllowedCidrs) == 0 {
+		return apperrors.BadRequest("error: private cluster requires at least one allowed CIDR")
+	}
+	return nil
+}
+
+// The customer-managed key is used only for the encrypted etcd, the retention ranges of the plans are checked by the broker
+func (v *validator) validateControlPlane(controlPlane *gqlschema.ControlPlaneInput) apperrors.AppError {
+	if controlPlane == nil {
+		return nil
+	}
+	if util.NotNilOrEmpty(controlPlane.EtcdEncryptionKeyRef) && !util.UnwrapBoolOrDefault(controlPlane.EtcdEncryption, false) {
+		return apperrors.BadRequest("error: etcd encryption key can be specified only when the etcd encryption is enabled")
+	}
+	if controlPlane.BackupRetentionDays != nil && *controlPlane.BackupRetentionDays < 1 {
+		return apperrors.BadRequest("error: backup retention must be at least 1 day")
+	}
+	return nil
+}
+
+func (v *validator) validateMaintenanceWindow(window *gqlschema.MaintenanceWindowInput) apperrors.AppError {
+	if window == nil {
+		return nil
+	}
+	if !maintenanceTimeRegexp.MatchString(window.Begin) || !maintenanceTimeRegexp.MatchString(window.End) {
+		return apperrors.BadRequest("error: maintenance window begin and end must have the HHMMSS+ZZZZ format")
+	}
+	return nil
+}
+
+func (v *validator) validateKubernetesConfig(config *gqlschema.KubernetesConfigInput) apperrors.AppError {
+	if config == nil {
+		return nil
+	}
+	if config.Kubelet != nil {
+		if err := validateFeatureGates(config.Kubelet.FeatureGates); err != nil {
+			return err
+		}
+		if config.Kubelet.MaxPods != nil && *config.Kubelet.MaxPods < 1 {
+			return apperrors.BadRequest("error: kubelet max pods must be greater than 0")
+		}
+		if config.Kubelet.PodPidsLimit != nil && *config.Kubelet.PodPidsLimit < 1 {
+			return apperrors.BadRequest("error: kubelet pod PIDs limit must be greater than 0")
+		}
+	}
+	if config.APIServer != nil {
+		if err := validateFeatureGates(config.APIServer.FeatureGates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFeatureGates(featureGates []*gqlschema.FeatureGateInput) apperrors.AppError {
+	for _, gate := range featureGates {
+		if gate == nil || gate.Name == "" {
+			return apperrors.BadRequest("error: feature gate name must not be empty")
+		}
+	}
+	return nil
+}
+
 func configContainsRuntimeAgentComponent(components []*gqlschema.ComponentConfigurationInput) bool {
 	for _, component := range components {
 		if component.Component == RuntimeAgent {
@@ -135,6 +135,247 @@ func TestValidator_ValidateProvisioningInput(t *testing.T) {
 		//then
 		require.Error(t, err)
 	})
+
+	t.Run("should accept encryption key only for GCP clusters", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+		key := util.StringPtr("projects/my-project/locations/europe-west3/keyRings/kyma/cryptoKeys/disks")
+
+		for name, tc := range map[string]struct {
+			providerConfig *gqlschema.ProviderSpecificInput
+			expectErr      bool
+		}{
+			"gcp": {
+				providerConfig: &gqlschema.ProviderSpecificInput{GcpConfig: &gqlschema.GCPProviderConfigInput{EncryptionKey: key}},
+			},
+			"azure": {
+				providerConfig: &gqlschema.ProviderSpecificInput{AzureConfig: &gqlschema.AzureProviderConfigInput{EncryptionKey: key}},
+				expectErr:      true,
+			},
+			"aws": {
+				providerConfig: &gqlschema.ProviderSpecificInput{AwsConfig: &gqlschema.AWSProviderConfigInput{EncryptionKey: key}},
+				expectErr:      true,
+			},
+			"azure without key": {
+				providerConfig: &gqlschema.ProviderSpecificInput{AzureConfig: &gqlschema.AzureProviderConfigInput{}},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testClusterConfig, _, _ := initializeConfigs()
+				testClusterConfig.GardenerConfig.ProviderSpecificConfig = tc.providerConfig
+
+				config := gqlschema.ProvisionRuntimeInput{
+					RuntimeInput:  runtimeInput,
+					ClusterConfig: testClusterConfig,
+					KymaConfig:    kymaConfig,
+				}
+
+				//when
+				err := validator.ValidateProvisioningInput(config)
+
+				//then
+				if tc.expectErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validate networking of a private cluster", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+		private := util.BoolPtr(true)
+
+		for name, tc := range map[string]struct {
+			networking *gqlschema.NetworkingInput
+			expectErr  bool
+		}{
+			"private with allowed CIDRs": {
+				networking: &gqlschema.NetworkingInput{PrivateCluster: private, AllowedCidrs: []string{"10.0.0.0/8", "192.168.1.1/32"}},
+			},
+			"private without allowed CIDRs": {
+				networking: &gqlschema.NetworkingInput{PrivateCluster: private},
+				expectErr:  true,
+			},
+			"private with invalid CIDR": {
+				networking: &gqlschema.NetworkingInput{PrivateCluster: private, AllowedCidrs: []string{"10.0.0.0"}},
+				expectErr:  true,
+			},
+			"public with allowed CIDRs": {
+				networking: &gqlschema.NetworkingInput{AllowedCidrs: []string{"10.0.0.0/8"}},
+				expectErr:  true,
+			},
+			"public": {
+				networking: &gqlschema.NetworkingInput{PrivateCluster: util.BoolPtr(false)},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testClusterConfig, _, _ := initializeConfigs()
+				testClusterConfig.GardenerConfig.Networking = tc.networking
+
+				config := gqlschema.ProvisionRuntimeInput{
+					RuntimeInput:  runtimeInput,
+					ClusterConfig: testClusterConfig,
+					KymaConfig:    kymaConfig,
+				}
+
+				//when
+				err := validator.ValidateProvisioningInput(config)
+
+				//then
+				if tc.expectErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validate etcd settings", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		for name, tc := range map[string]struct {
+			controlPlane *gqlschema.ControlPlaneInput
+			expectErr    bool
+		}{
+			"encrypted etcd with key and backup retention": {
+				controlPlane: &gqlschema.ControlPlaneInput{EtcdEncryption: util.BoolPtr(true), EtcdEncryptionKeyRef: util.StringPtr("key"), BackupRetentionDays: util.IntPtr(7)},
+			},
+			"key without encryption": {
+				controlPlane: &gqlschema.ControlPlaneInput{EtcdEncryptionKeyRef: util.StringPtr("key")},
+				expectErr:    true,
+			},
+			"backup retention below one day": {
+				controlPlane: &gqlschema.ControlPlaneInput{BackupRetentionDays: util.IntPtr(0)},
+				expectErr:    true,
+			},
+			"defaults": {},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testClusterConfig, _, _ := initializeConfigs()
+				testClusterConfig.GardenerConfig.ControlPlane = tc.controlPlane
+
+				config := gqlschema.ProvisionRuntimeInput{
+					RuntimeInput:  runtimeInput,
+					ClusterConfig: testClusterConfig,
+					KymaConfig:    kymaConfig,
+				}
+
+				//when
+				err := validator.ValidateProvisioningInput(config)
+
+				//then
+				if tc.expectErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validate maintenance window", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		for name, tc := range map[string]struct {
+			window    *gqlschema.MaintenanceWindowInput
+			expectErr bool
+		}{
+			"window with time zone offset": {
+				window: &gqlschema.MaintenanceWindowInput{Begin: "220000+0200", End: "230000+0200"},
+			},
+			"window with invalid time": {
+				window:    &gqlschema.MaintenanceWindowInput{Begin: "250000+0000", End: "010000+0000"},
+				expectErr: true,
+			},
+			"window without time zone offset": {
+				window:    &gqlschema.MaintenanceWindowInput{Begin: "22:00", End: "23:00"},
+				expectErr: true,
+			},
+			"default window": {},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testClusterConfig, _, _ := initializeConfigs()
+				testClusterConfig.GardenerConfig.MaintenanceWindow = tc.window
+
+				config := gqlschema.ProvisionRuntimeInput{
+					RuntimeInput:  runtimeInput,
+					ClusterConfig: testClusterConfig,
+					KymaConfig:    kymaConfig,
+				}
+
+				//when
+				err := validator.ValidateProvisioningInput(config)
+
+				//then
+				if tc.expectErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validate kubelet and API server options", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		for name, tc := range map[string]struct {
+			kubernetesConfig *gqlschema.KubernetesConfigInput
+			expectErr        bool
+		}{
+			"kubelet and API server options": {
+				kubernetesConfig: &gqlschema.KubernetesConfigInput{
+					Kubelet: &gqlschema.KubeletConfigInput{
+						FeatureGates: []*gqlschema.FeatureGateInput{{Name: "CPUManager", Enabled: true}},
+						MaxPods:      util.IntPtr(110),
+						PodPidsLimit: util.IntPtr(4096),
+					},
+					APIServer: &gqlschema.APIServerConfigInput{
+						FeatureGates: []*gqlschema.FeatureGateInput{{Name: "TTLAfterFinished", Enabled: true}},
+					},
+				},
+			},
+			"feature gate without name": {
+				kubernetesConfig: &gqlschema.KubernetesConfigInput{
+					APIServer: &gqlschema.APIServerConfigInput{FeatureGates: []*gqlschema.FeatureGateInput{{Enabled: true}}},
+				},
+				expectErr: true,
+			},
+			"zero max pods": {
+				kubernetesConfig: &gqlschema.KubernetesConfigInput{Kubelet: &gqlschema.KubeletConfigInput{MaxPods: util.IntPtr(0)}},
+				expectErr:        true,
+			},
+			"defaults": {},
+		} {
+			t.Run(name, func(t *testing.T) {
+				testClusterConfig, _, _ := initializeConfigs()
+				testClusterConfig.GardenerConfig.KubernetesConfig = tc.kubernetesConfig
+
+				config := gqlschema.ProvisionRuntimeInput{
+					RuntimeInput:  runtimeInput,
+					ClusterConfig: testClusterConfig,
+					KymaConfig:    kymaConfig,
+				}
+
+				//when
+				err := validator.ValidateProvisioningInput(config)
+
+				//then
+				if tc.expectErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	})
 }
 
 func TestValidator_ValidateUpgradeInput(t *testing.T) {
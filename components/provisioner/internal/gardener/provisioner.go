@@ -27,6 +27,11 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 )
 
+const (
+	operationAnnotation                  = "gardener.cloud/operation"
+	rotateKubeconfigCredentialsOperation = "rotate-kubeconfig-credentials"
+)
+
 //go:generate mockery -name=Client
 type Client interface {
 	Create(ctx context.Context, shoot *v1beta1.Shoot, opts v1.CreateOptions) (*v1beta1.Shoot, error)
@@ -151,6 +156,40 @@ func (g *GardenerProvisioner) HibernateCluster(clusterID string, gardenerConfig
 	return nil
 }
 
+// RevokeCredentials requests the rotation of the Shoot kubeconfig credentials, which invalidates the previously issued kubeconfigs and tokens.
+// A Shoot which does not exist or which rotation is already requested is treated as revoked.
+func (g *GardenerProvisioner) RevokeCredentials(clusterID string, gardenerConfig model.GardenerConfig) apperrors.AppError {
+	shoot, err := g.shootClient.Get(context.Background(), gardenerConfig.Name, v1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			logrus.Infof("Shoot %s for cluster ID %s does not exist, credentials already revoked", gardenerConfig.Name, clusterID)
+			return nil
+		}
+		appErr := util.K8SErrorToAppError(err)
+		return appErr.Append("error getting Shoot for cluster ID %s and name %s", clusterID, gardenerConfig.Name)
+	}
+
+	if shoot.Annotations[operationAnnotation] == rotateKubeconfigCredentialsOperation {
+		return nil
+	}
+	if shoot.Annotations == nil {
+		shoot.Annotations = map[string]string{}
+	}
+	shoot.Annotations[operationAnnotation] = rotateKubeconfigCredentialsOperation
+
+	err = retry.Do(func() error {
+		_, err := g.shootClient.Update(context.Background(), shoot, v1.UpdateOptions{})
+		return err
+	}, retry.Attempts(5))
+
+	if err != nil {
+		apperr := util.K8SErrorToAppError(err)
+		return apperr.Append("error requesting Shoot credentials rotation")
+	}
+
+	return nil
+}
+
 func (g *GardenerProvisioner) DeprovisionCluster(cluster model.Cluster, operationId string) (model.Operation, apperrors.AppError) {
 	shoot, err := g.shootClient.Get(context.Background(), cluster.ClusterConfig.Name, v1.GetOptions{})
 	if err != nil {
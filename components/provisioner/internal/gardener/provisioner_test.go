@@ -159,13 +159,16 @@ func TestGardenerProvisioner_UpgradeCluster(t *testing.T) {
 	initialShoot := testkit.NewTestShoot(clusterName).
 		InNamespace(gardenerNamespace).
 		WithAutoUpdate(false, false).
+		WithSecretBindingName("rotated-secret").
 		WithWorkers(testkit.NewTestWorker("peon").ToWorker()).
 		ToShoot()
 
+	// the secret binding is replaced with the target secret of the cluster
 	expectedShoot := testkit.NewTestShoot(clusterName).
 		InNamespace(gardenerNamespace).
 		WithKubernetesVersion("1.16").
 		WithAutoUpdate(false, false).
+		WithSecretBindingName("secret").
 		WithWorkers(
 			testkit.NewTestWorker("peon").
 				WithMachineType("n1-standard-4").
@@ -325,6 +328,70 @@ func TestGardenerProvisioner_HibernateCluster(t *testing.T) {
 	})
 }
 
+func TestGardenerProvisioner_RevokeCredentials(t *testing.T) {
+	gcpGardenerConfig, err := model.NewGCPGardenerConfig(&gqlschema.GCPProviderConfigInput{Zones: []string{"zone-1"}})
+	require.NoError(t, err)
+	cluster := newClusterConfig(clusterName, nil, gcpGardenerConfig, region)
+
+	t.Run("should request credentials rotation", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).
+			InNamespace(gardenerNamespace).
+			ToShoot()
+
+		clientset := fake.NewSimpleClientset(shoot)
+		shootClient := clientset.CoreV1beta1().Shoots(gardenerNamespace)
+
+		sessionFactory := &sessionMocks.Factory{}
+		provisioner := NewProvisioner(gardenerNamespace, shootClient, sessionFactory, auditLogsPolicyCMName, "")
+
+		// when
+		apperr := provisioner.RevokeCredentials(cluster.ID, cluster.ClusterConfig)
+
+		// then
+		require.NoError(t, apperr)
+		updatedShoot, err := shootClient.Get(context.Background(), clusterName, v1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, rotateKubeconfigCredentialsOperation, updatedShoot.Annotations[operationAnnotation])
+	})
+
+	t.Run("should not update shoot when rotation is already requested", func(t *testing.T) {
+		// given
+		shoot := testkit.NewTestShoot(clusterName).
+			InNamespace(gardenerNamespace).
+			ToShoot()
+		shoot.Annotations = map[string]string{operationAnnotation: rotateKubeconfigCredentialsOperation}
+
+		shootClient := &gardenerMocks.Client{}
+		shootClient.On("Get", mock.Anything, clusterName, mock.Anything).Return(shoot, nil)
+
+		sessionFactory := &sessionMocks.Factory{}
+		provisioner := NewProvisioner(gardenerNamespace, shootClient, sessionFactory, auditLogsPolicyCMName, "")
+
+		// when
+		apperr := provisioner.RevokeCredentials(cluster.ID, cluster.ClusterConfig)
+
+		// then
+		require.NoError(t, apperr)
+		shootClient.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should treat not existing shoot as revoked", func(t *testing.T) {
+		// given
+		clientset := fake.NewSimpleClientset()
+		shootClient := clientset.CoreV1beta1().Shoots(gardenerNamespace)
+
+		sessionFactory := &sessionMocks.Factory{}
+		provisioner := NewProvisioner(gardenerNamespace, shootClient, sessionFactory, auditLogsPolicyCMName, "")
+
+		// when
+		apperr := provisioner.RevokeCredentials(cluster.ID, cluster.ClusterConfig)
+
+		// then
+		require.NoError(t, apperr)
+	})
+}
+
 func TestGardenerProvisioner_GetHibernationStatus(t *testing.T) {
 	gcpGardenerConfig, err := model.NewGCPGardenerConfig(&gqlschema.GCPProviderConfigInput{Zones: []string{"zone-1"}})
 	require.NoError(t, err)
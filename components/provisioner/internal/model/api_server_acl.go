@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	apimachineryRuntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// APIServerACLExtensionType is the type of the Gardener extension which restricts the access to the API server of the Shoot
+	APIServerACLExtensionType = "acl"
+
+	aclActionAllow  = "ALLOW"
+	aclTypeRemoteIP = "remote_ip"
+)
+
+// This types mirror the provider config of the ACL extension https://github.com/stackitcloud/gardener-extension-acl
+
+// APIServerACLConfig is the provider config of the ACL extension
+type APIServerACLConfig struct {
+	Rule *APIServerACLRule `json:"rule"`
+}
+
+// APIServerACLRule allows the traffic to the API server only from the listed CIDRs
+type APIServerACLRule struct {
+	Cidrs  []string `json:"cidrs"`
+	Action string   `json:"action"`
+	Type   string   `json:"type"`
+}
+
+// NewAPIServerACLExtension returns the Shoot extension which exposes the API server only to the allowed CIDRs
+func NewAPIServerACLExtension(allowedCIDRs []string) (gardener_types.Extension, error) {
+	config := APIServerACLConfig{
+		Rule: &APIServerACLRule{
+			Cidrs:  allowedCIDRs,
+			Action: aclActionAllow,
+			Type:   aclTypeRemoteIP,
+		},
+	}
+	jsonData, err := json.Marshal(config)
+	if err != nil {
+		return gardener_types.Extension{}, err
+	}
+
+	return gardener_types.Extension{
+		Type:           APIServerACLExtensionType,
+		ProviderConfig: &apimachineryRuntime.RawExtension{Raw: jsonData},
+	}, nil
+}
@@ -20,6 +20,13 @@ const (
 	AccountLabel    = "account"
 
 	LicenceTypeAnnotation = "kcp.provisioner.kyma-project.io/licence-type"
+
+	// The etcd settings of the cluster are recorded on the Shoot for the etcd and backup configuration of the landscape
+	EtcdEncryptionAnnotation       = "kcp.provisioner.kyma-project.io/etcd-encryption"
+	EtcdEncryptionKeyRefAnnotation = "kcp.provisioner.kyma-project.io/etcd-encryption-key-ref"
+	BackupRetentionDaysAnnotation  = "kcp.provisioner.kyma-project.io/backup-retention-days"
+	// The control plane high availability is recorded on the Shoot for the placement of the control plane on the seed
+	ControlPlaneHighAvailabilityAnnotation = "kcp.provisioner.kyma-project.io/control-plane-high-availability"
 )
 
 type GardenerConfig struct {
@@ -48,6 +55,103 @@ type GardenerConfig struct {
 	EnableMachineImageVersionAutoUpdate bool
 	AllowPrivilegedContainers           bool
 	GardenerProviderConfig              GardenerProviderConfig
+
+	// Annotations are custom annotations added to the Shoot when the cluster is created, they are not persisted
+	Annotations map[string]string
+	// PrivateCluster restricts the access to the API server of the Shoot to the AllowedCIDRs, they are not persisted
+	PrivateCluster bool
+	AllowedCIDRs   []string
+	// ControlPlane holds the etcd settings requested for the cluster, the defaults of the landscape are used if nil, it is not persisted
+	ControlPlane *ControlPlaneConfig
+	// MaintenanceWindow is the time window of the maintenance of the Shoot, the default window of the landscape is used if nil, it is not persisted
+	MaintenanceWindow *MaintenanceWindowConfig
+	// KubernetesConfig holds the kubelet and API server options requested for the cluster, the defaults of Gardener
+	// are used if nil, it is not persisted and is set again by every upgrade
+	KubernetesConfig *KubernetesConfig
+}
+
+// KubernetesConfig holds the options of the kubelet and the API server of the Shoot, the unset fields use the defaults
+type KubernetesConfig struct {
+	KubeletFeatureGates   map[string]bool
+	EvictionHard          *EvictionThresholds
+	MaxPods               *int
+	PodPIDsLimit          *int
+	APIServerFeatureGates map[string]bool
+}
+
+// EvictionThresholds holds the hard eviction thresholds of the kubelet, as quantities or percentages
+type EvictionThresholds struct {
+	MemoryAvailable   *string
+	ImageFSAvailable  *string
+	ImageFSInodesFree *string
+	NodeFSAvailable   *string
+	NodeFSInodesFree  *string
+}
+
+func (c *KubernetesConfig) apply(kubernetes *gardener_types.Kubernetes) {
+	if c == nil {
+		return
+	}
+
+	kubelet := &gardener_types.KubeletConfig{
+		KubernetesConfig: gardener_types.KubernetesConfig{FeatureGates: c.KubeletFeatureGates},
+	}
+	if c.EvictionHard != nil {
+		kubelet.EvictionHard = &gardener_types.KubeletConfigEviction{
+			MemoryAvailable:   c.EvictionHard.MemoryAvailable,
+			ImageFSAvailable:  c.EvictionHard.ImageFSAvailable,
+			ImageFSInodesFree: c.EvictionHard.ImageFSInodesFree,
+			NodeFSAvailable:   c.EvictionHard.NodeFSAvailable,
+			NodeFSInodesFree:  c.EvictionHard.NodeFSInodesFree,
+		}
+	}
+	if c.MaxPods != nil {
+		maxPods := int32(*c.MaxPods)
+		kubelet.MaxPods = &maxPods
+	}
+	if c.PodPIDsLimit != nil {
+		podPIDsLimit := int64(*c.PodPIDsLimit)
+		kubelet.PodPIDsLimit = &podPIDsLimit
+	}
+	kubernetes.Kubelet = kubelet
+
+	if kubernetes.KubeAPIServer == nil {
+		kubernetes.KubeAPIServer = &gardener_types.KubeAPIServerConfig{}
+	}
+	kubernetes.KubeAPIServer.FeatureGates = c.APIServerFeatureGates
+}
+
+// MaintenanceWindowConfig holds the begin and the end of the maintenance window in the HHMMSS+ZZZZ format
+type MaintenanceWindowConfig struct {
+	Begin string
+	End   string
+}
+
+// ControlPlaneConfig configures the encryption and the backups of the etcd of the cluster and the replication
+// of the etcd and the API server across the zones, the unset fields use the defaults
+type ControlPlaneConfig struct {
+	EtcdEncryption       *bool
+	EtcdEncryptionKeyRef *string
+	BackupRetentionDays  *int
+	HighAvailability     *bool
+}
+
+func (c *ControlPlaneConfig) annotate(annotations map[string]string) {
+	if c == nil {
+		return
+	}
+	if c.EtcdEncryption != nil {
+		annotations[EtcdEncryptionAnnotation] = fmt.Sprintf("%t", *c.EtcdEncryption)
+	}
+	if util.NotNilOrEmpty(c.EtcdEncryptionKeyRef) {
+		annotations[EtcdEncryptionKeyRefAnnotation] = *c.EtcdEncryptionKeyRef
+	}
+	if c.BackupRetentionDays != nil {
+		annotations[BackupRetentionDaysAnnotation] = fmt.Sprintf("%d", *c.BackupRetentionDays)
+	}
+	if c.HighAvailability != nil {
+		annotations[ControlPlaneHighAvailabilityAnnotation] = fmt.Sprintf("%t", *c.HighAvailability)
+	}
 }
 
 func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subAccountId string) (*gardener_types.Shoot, apperrors.AppError) {
@@ -64,9 +168,13 @@ func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subA
 	}
 
 	annotations := make(map[string]string)
+	for key, value := range c.Annotations {
+		annotations[key] = value
+	}
 	if c.LicenceType != nil {
 		annotations[LicenceTypeAnnotation] = *c.LicenceType
 	}
+	c.ControlPlane.annotate(annotations)
 
 	shoot := &gardener_types.Shoot{
 		ObjectMeta: v1.ObjectMeta{
@@ -103,6 +211,23 @@ func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subA
 		},
 	}
 
+	if c.MaintenanceWindow != nil {
+		shoot.Spec.Maintenance.TimeWindow = &gardener_types.MaintenanceTimeWindow{
+			Begin: c.MaintenanceWindow.Begin,
+			End:   c.MaintenanceWindow.End,
+		}
+	}
+
+	c.KubernetesConfig.apply(&shoot.Spec.Kubernetes)
+
+	if c.PrivateCluster {
+		extension, err := NewAPIServerACLExtension(c.AllowedCIDRs)
+		if err != nil {
+			return nil, apperrors.Internal("error encoding API server access config: %s", err.Error())
+		}
+		shoot.Spec.Extensions = append(shoot.Spec.Extensions, extension)
+	}
+
 	err := c.GardenerProviderConfig.ExtendShootConfig(c, shoot)
 	if err != nil {
 		return nil, err.Append("error extending shoot config with Provider")
@@ -182,6 +307,14 @@ func (c GCPGardenerConfig) ExtendShootConfig(gardenerConfig GardenerConfig, shoo
 
 	workers := []gardener_types.Worker{getWorkerConfig(gardenerConfig, c.input.Zones)}
 
+	if util.NotNilOrEmpty(c.input.EncryptionKey) {
+		jsonWorkerData, err := json.Marshal(NewGCPWorkerConfig(*c.input.EncryptionKey))
+		if err != nil {
+			return apperrors.Internal("error encoding worker config: %s", err.Error())
+		}
+		workers[0].ProviderConfig = &apimachineryRuntime.RawExtension{Raw: jsonWorkerData}
+	}
+
 	gcpInfra := NewGCPInfrastructure(gardenerConfig.WorkerCidr)
 	jsonData, err := json.Marshal(gcpInfra)
 	if err != nil {
@@ -403,6 +536,14 @@ func updateShootConfig(upgradeConfig GardenerConfig, shoot *gardener_types.Shoot
 		shoot.Spec.Purpose = &purpose
 	}
 
+	// the secret binding is replaced when the credentials to the target provider were rotated
+	if upgradeConfig.TargetSecret != "" {
+		shoot.Spec.SecretBindingName = upgradeConfig.TargetSecret
+	}
+
+	// the kubelet and API server options are not persisted, they are applied again when provided with the upgrade
+	upgradeConfig.KubernetesConfig.apply(&shoot.Spec.Kubernetes)
+
 	shoot.Spec.Maintenance.AutoUpdate.KubernetesVersion = upgradeConfig.EnableKubernetesVersionAutoUpdate
 	shoot.Spec.Maintenance.AutoUpdate.MachineImageVersion = upgradeConfig.EnableMachineImageVersionAutoUpdate
 
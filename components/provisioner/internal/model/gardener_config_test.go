@@ -338,6 +338,183 @@ func TestGardenerConfig_ToShootTemplate(t *testing.T) {
 
 }
 
+func TestGardenerConfig_ToShootTemplateWithAnnotations(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	gardenerConfig.LicenceType = util.StringPtr("TestDevelopmentAndDemo")
+	gardenerConfig.Annotations = map[string]string{
+		"team":                "kyma",
+		LicenceTypeAnnotation: "overridden",
+	}
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"team":                "kyma",
+		LicenceTypeAnnotation: "TestDevelopmentAndDemo",
+	}, template.Annotations)
+}
+
+func TestGardenerConfig_ToShootTemplateWithEncryptionKey(t *testing.T) {
+	// given
+	input := fixGCPGardenerInput([]string{"fix-zone-1"})
+	input.EncryptionKey = util.StringPtr("projects/my-project/locations/eu/keyRings/kyma/cryptoKeys/disks")
+	gcpGardenerProvider, err := NewGCPGardenerConfig(input)
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("gcp", gcpGardenerProvider)
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, template.Spec.Provider.Workers, 1)
+	require.NotNil(t, template.Spec.Provider.Workers[0].ProviderConfig)
+	assert.JSONEq(t,
+		`{"kind":"WorkerConfig","apiVersion":"gcp.provider.extensions.gardener.cloud/v1alpha1","volume":{"encryption":{"kmsKeyName":"projects/my-project/locations/eu/keyRings/kyma/cryptoKeys/disks"}}}`,
+		string(template.Spec.Provider.Workers[0].ProviderConfig.Raw))
+}
+
+func TestGardenerConfig_ToShootTemplateWithControlPlane(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	gardenerConfig.ControlPlane = &ControlPlaneConfig{
+		EtcdEncryption:       util.BoolPtr(true),
+		EtcdEncryptionKeyRef: util.StringPtr("arn:aws:kms:eu-central-1:123456789012:key/etcd"),
+		BackupRetentionDays:  util.IntPtr(14),
+		HighAvailability:     util.BoolPtr(true),
+	}
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "true", template.Annotations[EtcdEncryptionAnnotation])
+	assert.Equal(t, "arn:aws:kms:eu-central-1:123456789012:key/etcd", template.Annotations[EtcdEncryptionKeyRefAnnotation])
+	assert.Equal(t, "14", template.Annotations[BackupRetentionDaysAnnotation])
+	assert.Equal(t, "true", template.Annotations[ControlPlaneHighAvailabilityAnnotation])
+}
+
+func TestGardenerConfig_ToShootTemplateWithMaintenanceWindow(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	gardenerConfig.MaintenanceWindow = &MaintenanceWindowConfig{Begin: "220000+0200", End: "230000+0200"}
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	require.NotNil(t, template.Spec.Maintenance.TimeWindow)
+	assert.Equal(t, "220000+0200", template.Spec.Maintenance.TimeWindow.Begin)
+	assert.Equal(t, "230000+0200", template.Spec.Maintenance.TimeWindow.End)
+	assert.NotNil(t, template.Spec.Maintenance.AutoUpdate)
+}
+
+func TestGardenerConfig_ToShootTemplateWithKubernetesConfig(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	gardenerConfig.KubernetesConfig = fixKubernetesConfig()
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	assertKubernetesConfig(t, template.Spec.Kubernetes)
+	assert.False(t, *template.Spec.Kubernetes.KubeAPIServer.EnableBasicAuthentication)
+}
+
+func TestEditShootConfig_ReappliesKubernetesConfig(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	shoot := testkit.NewTestShoot("shoot").
+		WithAutoUpdate(false, false).
+		WithWorkers(testkit.NewTestWorker("peon").ToWorker()).
+		ToShoot()
+	upgradeConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	upgradeConfig.KubernetesConfig = fixKubernetesConfig()
+
+	// when
+	err = awsGardenerProvider.EditShootConfig(upgradeConfig, shoot)
+
+	// then
+	require.NoError(t, err)
+	assertKubernetesConfig(t, shoot.Spec.Kubernetes)
+
+	// when
+	upgradeConfig.KubernetesConfig = nil
+	err = awsGardenerProvider.EditShootConfig(upgradeConfig, shoot)
+
+	// then
+	require.NoError(t, err)
+	assertKubernetesConfig(t, shoot.Spec.Kubernetes)
+}
+
+func fixKubernetesConfig() *KubernetesConfig {
+	return &KubernetesConfig{
+		KubeletFeatureGates:   map[string]bool{"CPUManager": true},
+		EvictionHard:          &EvictionThresholds{MemoryAvailable: util.StringPtr("200Mi"), NodeFSAvailable: util.StringPtr("10%")},
+		MaxPods:               util.IntPtr(110),
+		PodPIDsLimit:          util.IntPtr(4096),
+		APIServerFeatureGates: map[string]bool{"TTLAfterFinished": true},
+	}
+}
+
+func assertKubernetesConfig(t *testing.T, kubernetes gardener_types.Kubernetes) {
+	require.NotNil(t, kubernetes.Kubelet)
+	assert.Equal(t, map[string]bool{"CPUManager": true}, kubernetes.Kubelet.FeatureGates)
+	require.NotNil(t, kubernetes.Kubelet.EvictionHard)
+	assert.Equal(t, "200Mi", *kubernetes.Kubelet.EvictionHard.MemoryAvailable)
+	assert.Equal(t, "10%", *kubernetes.Kubelet.EvictionHard.NodeFSAvailable)
+	assert.Nil(t, kubernetes.Kubelet.EvictionHard.ImageFSAvailable)
+	assert.Equal(t, int32(110), *kubernetes.Kubelet.MaxPods)
+	assert.Equal(t, int64(4096), *kubernetes.Kubelet.PodPIDsLimit)
+	require.NotNil(t, kubernetes.KubeAPIServer)
+	assert.Equal(t, map[string]bool{"TTLAfterFinished": true}, kubernetes.KubeAPIServer.FeatureGates)
+}
+
+func TestGardenerConfig_ToShootTemplateForPrivateCluster(t *testing.T) {
+	// given
+	awsGardenerProvider, err := NewAWSGardenerConfig(fixAWSGardenerInput())
+	require.NoError(t, err)
+
+	gardenerConfig := fixGardenerConfig("aws", awsGardenerProvider)
+	gardenerConfig.PrivateCluster = true
+	gardenerConfig.AllowedCIDRs = []string{"10.0.0.0/8", "192.168.1.1/32"}
+
+	// when
+	template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, template.Spec.Extensions, 1)
+	assert.Equal(t, APIServerACLExtensionType, template.Spec.Extensions[0].Type)
+	require.NotNil(t, template.Spec.Extensions[0].ProviderConfig)
+	assert.JSONEq(t,
+		`{"rule":{"cidrs":["10.0.0.0/8","192.168.1.1/32"],"action":"ALLOW","type":"remote_ip"}}`,
+		string(template.Spec.Extensions[0].ProviderConfig.Raw))
+}
+
 func TestEditShootConfig(t *testing.T) {
 	zones := []string{"fix-zone-1", "fix-zone-2"}
 
@@ -350,6 +527,7 @@ func TestEditShootConfig(t *testing.T) {
 		WithKubernetesVersion("1.15").
 		WithAutoUpdate(true, false).
 		WithPurpose("testing").
+		WithSecretBindingName("gardener-secret").
 		WithWorkers(
 			testkit.NewTestWorker("peon").
 				WithMachineType("machine").
@@ -12,6 +12,7 @@ import (
 const (
 	infrastructureConfigKind = "InfrastructureConfig"
 	controlPlaneConfigKind   = "ControlPlaneConfig"
+	workerConfigKind         = "WorkerConfig"
 
 	gcpAPIVersion       = "gcp.provider.extensions.gardener.cloud/v1alpha1"
 	azureAPIVersion     = "azure.provider.extensions.gardener.cloud/v1alpha1"
@@ -42,6 +43,20 @@ func NewGCPControlPlane(zones []string) *gcp.ControlPlaneConfig {
 	}
 }
 
+func NewGCPWorkerConfig(encryptionKey string) *gcp.WorkerConfig {
+	return &gcp.WorkerConfig{
+		TypeMeta: v1.TypeMeta{
+			Kind:       workerConfigKind,
+			APIVersion: gcpAPIVersion,
+		},
+		Volume: &gcp.Volume{
+			Encryption: &gcp.DiskEncryption{
+				KmsKeyName: &encryptionKey,
+			},
+		},
+	}
+}
+
 func NewAzureInfrastructure(workerCIDR string, azConfig AzureGardenerConfig) *azure.InfrastructureConfig {
 	isZoned := len(azConfig.input.Zones) > 0
 	return &azure.InfrastructureConfig{
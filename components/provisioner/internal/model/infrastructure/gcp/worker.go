@@ -0,0 +1,26 @@
+package gcp
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// This types are copied from https://github.com/gardener/gardener-extension-provider-gcp/blob/master/pkg/apis/gcp/types_worker.go
+
+// WorkerConfig contains configuration settings for the worker nodes.
+type WorkerConfig struct {
+	metav1.TypeMeta
+
+	// Volume contains configuration for the root disks attached to VMs.
+	Volume *Volume `json:"volume,omitempty"`
+}
+
+// Volume contains configuration for the disks attached to VMs.
+type Volume struct {
+	// Encryption refers to the disk encryption details for this volume
+	Encryption *DiskEncryption `json:"encryption,omitempty"`
+}
+
+// DiskEncryption encapsulates the encryption configuration for a disk.
+type DiskEncryption struct {
+	// KmsKeyName specifies the customer-managed encryption key in Cloud KMS used to encrypt the disk.
+	// The format is projects/projectId/locations/<zone>/keyRings/<kms-key-ring>/cryptoKeys/<key-name>
+	KmsKeyName *string `json:"kmsKeyName,omitempty"`
+}
@@ -112,9 +112,104 @@ func (c converter) gardenerConfigFromInput(runtimeID string, input *gqlschema.Ga
 		AllowPrivilegedContainers:           allowPrivilegedContainers,
 		ClusterID:                           runtimeID,
 		GardenerProviderConfig:              providerSpecificConfig,
+		Annotations:                         annotationsFromInput(input.Annotations),
+		PrivateCluster:                      privateClusterFromInput(input.Networking),
+		AllowedCIDRs:                        allowedCIDRsFromInput(input.Networking),
+		ControlPlane:                        controlPlaneFromInput(input.ControlPlane),
+		MaintenanceWindow:                   maintenanceWindowFromInput(input.MaintenanceWindow),
+		KubernetesConfig:                    kubernetesConfigFromInput(input.KubernetesConfig),
 	}, nil
 }
 
+func controlPlaneFromInput(input *gqlschema.ControlPlaneInput) *model.ControlPlaneConfig {
+	if input == nil {
+		return nil
+	}
+	return &model.ControlPlaneConfig{
+		EtcdEncryption:       input.EtcdEncryption,
+		EtcdEncryptionKeyRef: input.EtcdEncryptionKeyRef,
+		BackupRetentionDays:  input.BackupRetentionDays,
+		HighAvailability:     input.HighAvailability,
+	}
+}
+
+func maintenanceWindowFromInput(input *gqlschema.MaintenanceWindowInput) *model.MaintenanceWindowConfig {
+	if input == nil {
+		return nil
+	}
+	return &model.MaintenanceWindowConfig{
+		Begin: input.Begin,
+		End:   input.End,
+	}
+}
+
+func kubernetesConfigFromInput(input *gqlschema.KubernetesConfigInput) *model.KubernetesConfig {
+	if input == nil {
+		return nil
+	}
+	config := &model.KubernetesConfig{}
+	if input.Kubelet != nil {
+		config.KubeletFeatureGates = featureGatesFromInput(input.Kubelet.FeatureGates)
+		config.MaxPods = input.Kubelet.MaxPods
+		config.PodPIDsLimit = input.Kubelet.PodPidsLimit
+		if eviction := input.Kubelet.EvictionHard; eviction != nil {
+			config.EvictionHard = &model.EvictionThresholds{
+				MemoryAvailable:   eviction.MemoryAvailable,
+				ImageFSAvailable:  eviction.ImageFSAvailable,
+				ImageFSInodesFree: eviction.ImageFSInodesFree,
+				NodeFSAvailable:   eviction.NodeFSAvailable,
+				NodeFSInodesFree:  eviction.NodeFSInodesFree,
+			}
+		}
+	}
+	if input.APIServer != nil {
+		config.APIServerFeatureGates = featureGatesFromInput(input.APIServer.FeatureGates)
+	}
+	return config
+}
+
+func featureGatesFromInput(input []*gqlschema.FeatureGateInput) map[string]bool {
+	if len(input) == 0 {
+		return nil
+	}
+	featureGates := make(map[string]bool, len(input))
+	for _, gate := range input {
+		if gate == nil {
+			continue
+		}
+		featureGates[gate.Name] = gate.Enabled
+	}
+	return featureGates
+}
+
+func privateClusterFromInput(input *gqlschema.NetworkingInput) bool {
+	if input == nil {
+		return false
+	}
+	return util.UnwrapBoolOrDefault(input.PrivateCluster, false)
+}
+
+func allowedCIDRsFromInput(input *gqlschema.NetworkingInput) []string {
+	if input == nil {
+		return nil
+	}
+	return input.AllowedCidrs
+}
+
+func annotationsFromInput(input []*gqlschema.AnnotationInput) map[string]string {
+	if len(input) == 0 {
+		return nil
+	}
+	annotations := make(map[string]string, len(input))
+	for _, annotation := range input {
+		if annotation == nil {
+			continue
+		}
+		annotations[annotation.Key] = annotation.Value
+	}
+	return annotations
+}
+
 func (c converter) shouldAllowPrivilegedContainers(inputAllowPrivilegedContainers *bool, tillerYaml string) bool {
 	if c.forceAllowPrivilegedContainers {
 		return true
@@ -143,7 +238,6 @@ func (c converter) UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpg
 		ProjectName:               config.ProjectName,
 		Provider:                  config.Provider,
 		Seed:                      config.Seed,
-		TargetSecret:              config.TargetSecret,
 		Region:                    config.Region,
 		LicenceType:               config.LicenceType,
 		AllowPrivilegedContainers: config.AllowPrivilegedContainers,
@@ -161,7 +255,9 @@ func (c converter) UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpg
 		MaxUnavailable:                      util.UnwrapIntOrDefault(input.MaxUnavailable, config.MaxUnavailable),
 		EnableKubernetesVersionAutoUpdate:   util.UnwrapBoolOrDefault(input.EnableKubernetesVersionAutoUpdate, config.EnableKubernetesVersionAutoUpdate),
 		EnableMachineImageVersionAutoUpdate: util.UnwrapBoolOrDefault(input.EnableMachineImageVersionAutoUpdate, config.EnableMachineImageVersionAutoUpdate),
+		TargetSecret:                        util.UnwrapStrOrDefault(input.TargetSecret, config.TargetSecret),
 		GardenerProviderConfig:              providerSpecificConfig,
+		KubernetesConfig:                    kubernetesConfigFromInput(input.KubernetesConfig),
 	}, nil
 }
 
@@ -739,6 +739,23 @@ func Test_UpgradeShootInputToGardenerConfig(t *testing.T) {
 				MaxUnavailable:    1,
 			},
 		},
+		{description: "shoot upgrade with rotated target secret",
+			upgradeInput: newUpgradeShootInputWithTargetSecret("new-secret"),
+			initialConfig: model.GardenerConfig{
+				KubernetesVersion: "version",
+				MachineType:       "1",
+				TargetSecret:      "old-secret",
+				AutoScalerMin:     1,
+				AutoScalerMax:     2,
+			},
+			upgradedConfig: model.GardenerConfig{
+				KubernetesVersion: "version",
+				MachineType:       "1",
+				TargetSecret:      "new-secret",
+				AutoScalerMin:     1,
+				AutoScalerMax:     2,
+			},
+		},
 	}
 
 	casesWithErrors := []struct {
@@ -878,6 +895,12 @@ func newAzureUpgradeShootInput(newPurpose string) gqlschema.UpgradeShootInput {
 	return input
 }
 
+func newUpgradeShootInputWithTargetSecret(targetSecret string) gqlschema.UpgradeShootInput {
+	input := newUpgradeShootInputWithNilValues()
+	input.GardenerConfig.TargetSecret = &targetSecret
+	return input
+}
+
 func newUpgradeShootInputWithoutProviderConfig(newPurpose string) gqlschema.UpgradeShootInput {
 	input := newUpgradeShootInputAwsAzureGCP(newPurpose)
 	input.GardenerConfig.ProviderSpecificConfig = &gqlschema.ProviderSpecificInput{
@@ -110,6 +110,29 @@ func (_m *Service) ReconnectRuntimeAgent(id string) (string, apperrors.AppError)
 	return r0, r1
 }
 
+// RevokeRuntimeCredentials provides a mock function with given fields: runtimeID
+func (_m *Service) RevokeRuntimeCredentials(runtimeID string) (string, apperrors.AppError) {
+	ret := _m.Called(runtimeID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(runtimeID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // RollBackLastUpgrade provides a mock function with given fields: runtimeID
 func (_m *Service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError) {
 	ret := _m.Called(runtimeID)
@@ -74,6 +74,7 @@ func (ws writeSession) UpdateGardenerClusterConfig(config model.GardenerConfig)
 		Set("kubernetes_version", config.KubernetesVersion).
 		Set("purpose", config.Purpose).
 		Set("seed", config.Seed).
+		Set("target_secret", config.TargetSecret).
 		Set("region", config.Region).
 		Set("provider", config.Provider).
 		Set("machine_type", config.MachineType).
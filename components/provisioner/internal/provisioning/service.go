@@ -34,6 +34,7 @@ type Service interface {
 	RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError)
 	RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	HibernateCluster(clusterID string) (*gqlschema.OperationStatus, apperrors.AppError)
+	RevokeRuntimeCredentials(runtimeID string) (string, apperrors.AppError)
 }
 
 //go:generate mockery -name=Provisioner
@@ -43,6 +44,7 @@ type Provisioner interface {
 	UpgradeCluster(clusterID string, upgradeConfig model.GardenerConfig) apperrors.AppError
 	HibernateCluster(clusterID string, upgradeConfig model.GardenerConfig) apperrors.AppError
 	GetHibernationStatus(clusterID string, gardenerConfig model.GardenerConfig) (model.HibernationStatus, apperrors.AppError)
+	RevokeCredentials(clusterID string, gardenerConfig model.GardenerConfig) apperrors.AppError
 }
 
 type service struct {
@@ -270,6 +272,29 @@ func (r *service) HibernateCluster(runtimeID string) (*gqlschema.OperationStatus
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+// RevokeRuntimeCredentials revokes the kubeconfigs and tokens issued for the Runtime, a Runtime which does not exist has no credentials to revoke
+func (r *service) RevokeRuntimeCredentials(runtimeID string) (string, apperrors.AppError) {
+	log.Infof("Revoking credentials for Runtime '%s'...", runtimeID)
+
+	session := r.dbSessionFactory.NewReadSession()
+
+	cluster, dberr := session.GetCluster(runtimeID)
+	if dberr != nil {
+		if dberr.Code() == dberrors.CodeNotFound {
+			log.Infof("Runtime '%s' does not exist, there are no credentials to revoke", runtimeID)
+			return runtimeID, nil
+		}
+		return "", apperrors.Internal("Failed to find shoot cluster to revoke credentials in database: %s", dberr.Error())
+	}
+
+	err := r.provisioner.RevokeCredentials(cluster.ID, cluster.ClusterConfig)
+	if err != nil {
+		return "", apperrors.Internal("Failed to revoke Cluster credentials: %s", err.Error())
+	}
+
+	return runtimeID, nil
+}
+
 func (r *service) verifyLastOperationFinished(session dbsession.ReadSession, runtimeId string) apperrors.AppError {
 	lastOperation, dberr := session.GetLastOperation(runtimeId)
 	if dberr != nil {
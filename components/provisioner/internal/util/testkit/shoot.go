@@ -64,6 +64,12 @@ func (ts *TestShoot) WithPurpose(purpose string) *TestShoot {
 	return ts
 }
 
+// WithSecretBindingName sets value of shoot.Spec.SecretBindingName field
+func (ts *TestShoot) WithSecretBindingName(name string) *TestShoot {
+	ts.shoot.Spec.SecretBindingName = name
+	return ts
+}
+
 // WithWorkers adds v1beta1 Workers to shoot.Spec.Provider.Workers.
 // See also testkit.TestWorker
 func (ts *TestShoot) WithWorkers(workers ...v1beta1.Worker) *TestShoot {
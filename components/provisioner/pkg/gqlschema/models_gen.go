@@ -12,6 +12,10 @@ type ProviderSpecificConfig interface {
 	IsProviderSpecificConfig()
 }
 
+type APIServerConfigInput struct {
+	FeatureGates []*FeatureGateInput `json:"featureGates"`
+}
+
 type AWSProviderConfig struct {
 	Zone         *string `json:"zone"`
 	VpcCidr      *string `json:"vpcCidr"`
@@ -22,10 +26,16 @@ type AWSProviderConfig struct {
 func (AWSProviderConfig) IsProviderSpecificConfig() {}
 
 type AWSProviderConfigInput struct {
-	Zone         string `json:"zone"`
-	VpcCidr      string `json:"vpcCidr"`
-	PublicCidr   string `json:"publicCidr"`
-	InternalCidr string `json:"internalCidr"`
+	Zone          string  `json:"zone"`
+	VpcCidr       string  `json:"vpcCidr"`
+	PublicCidr    string  `json:"publicCidr"`
+	InternalCidr  string  `json:"internalCidr"`
+	EncryptionKey *string `json:"encryptionKey"`
+}
+
+type AnnotationInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 type AzureProviderConfig struct {
@@ -36,8 +46,9 @@ type AzureProviderConfig struct {
 func (AzureProviderConfig) IsProviderSpecificConfig() {}
 
 type AzureProviderConfigInput struct {
-	VnetCidr string   `json:"vnetCidr"`
-	Zones    []string `json:"zones"`
+	VnetCidr      string   `json:"vnetCidr"`
+	Zones         []string `json:"zones"`
+	EncryptionKey *string  `json:"encryptionKey"`
 }
 
 type ClusterConfigInput struct {
@@ -71,10 +82,30 @@ type ConfigEntryInput struct {
 	Secret *bool  `json:"secret"`
 }
 
+type ControlPlaneInput struct {
+	EtcdEncryption       *bool   `json:"etcdEncryption"`
+	EtcdEncryptionKeyRef *string `json:"etcdEncryptionKeyRef"`
+	BackupRetentionDays  *int    `json:"backupRetentionDays"`
+	HighAvailability     *bool   `json:"highAvailability"`
+}
+
 type Error struct {
 	Message *string `json:"message"`
 }
 
+type EvictionThresholdsInput struct {
+	MemoryAvailable   *string `json:"memoryAvailable"`
+	ImageFSAvailable  *string `json:"imageFSAvailable"`
+	ImageFSInodesFree *string `json:"imageFSInodesFree"`
+	NodeFSAvailable   *string `json:"nodeFSAvailable"`
+	NodeFSInodesFree  *string `json:"nodeFSInodesFree"`
+}
+
+type FeatureGateInput struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
 type GCPProviderConfig struct {
 	Zones []string `json:"zones"`
 }
@@ -82,7 +113,8 @@ type GCPProviderConfig struct {
 func (GCPProviderConfig) IsProviderSpecificConfig() {}
 
 type GCPProviderConfigInput struct {
-	Zones []string `json:"zones"`
+	Zones         []string `json:"zones"`
+	EncryptionKey *string  `json:"encryptionKey"`
 }
 
 type GardenerConfig struct {
@@ -111,28 +143,33 @@ type GardenerConfig struct {
 }
 
 type GardenerConfigInput struct {
-	Name                                string                 `json:"name"`
-	KubernetesVersion                   string                 `json:"kubernetesVersion"`
-	Provider                            string                 `json:"provider"`
-	TargetSecret                        string                 `json:"targetSecret"`
-	Region                              string                 `json:"region"`
-	MachineType                         string                 `json:"machineType"`
-	MachineImage                        *string                `json:"machineImage"`
-	MachineImageVersion                 *string                `json:"machineImageVersion"`
-	DiskType                            *string                `json:"diskType"`
-	VolumeSizeGb                        *int                   `json:"volumeSizeGB"`
-	WorkerCidr                          string                 `json:"workerCidr"`
-	AutoScalerMin                       int                    `json:"autoScalerMin"`
-	AutoScalerMax                       int                    `json:"autoScalerMax"`
-	MaxSurge                            int                    `json:"maxSurge"`
-	MaxUnavailable                      int                    `json:"maxUnavailable"`
-	Purpose                             *string                `json:"purpose"`
-	LicenceType                         *string                `json:"licenceType"`
-	EnableKubernetesVersionAutoUpdate   *bool                  `json:"enableKubernetesVersionAutoUpdate"`
-	EnableMachineImageVersionAutoUpdate *bool                  `json:"enableMachineImageVersionAutoUpdate"`
-	AllowPrivilegedContainers           *bool                  `json:"allowPrivilegedContainers"`
-	ProviderSpecificConfig              *ProviderSpecificInput `json:"providerSpecificConfig"`
-	Seed                                *string                `json:"seed"`
+	Name                                string                  `json:"name"`
+	KubernetesVersion                   string                  `json:"kubernetesVersion"`
+	Provider                            string                  `json:"provider"`
+	TargetSecret                        string                  `json:"targetSecret"`
+	Region                              string                  `json:"region"`
+	MachineType                         string                  `json:"machineType"`
+	MachineImage                        *string                 `json:"machineImage"`
+	MachineImageVersion                 *string                 `json:"machineImageVersion"`
+	DiskType                            *string                 `json:"diskType"`
+	VolumeSizeGb                        *int                    `json:"volumeSizeGB"`
+	WorkerCidr                          string                  `json:"workerCidr"`
+	AutoScalerMin                       int                     `json:"autoScalerMin"`
+	AutoScalerMax                       int                     `json:"autoScalerMax"`
+	MaxSurge                            int                     `json:"maxSurge"`
+	MaxUnavailable                      int                     `json:"maxUnavailable"`
+	Purpose                             *string                 `json:"purpose"`
+	LicenceType                         *string                 `json:"licenceType"`
+	EnableKubernetesVersionAutoUpdate   *bool                   `json:"enableKubernetesVersionAutoUpdate"`
+	EnableMachineImageVersionAutoUpdate *bool                   `json:"enableMachineImageVersionAutoUpdate"`
+	AllowPrivilegedContainers           *bool                   `json:"allowPrivilegedContainers"`
+	ProviderSpecificConfig              *ProviderSpecificInput  `json:"providerSpecificConfig"`
+	Seed                                *string                 `json:"seed"`
+	Annotations                         []*AnnotationInput      `json:"annotations"`
+	Networking                          *NetworkingInput        `json:"networking"`
+	ControlPlane                        *ControlPlaneInput      `json:"controlPlane"`
+	MaintenanceWindow                   *MaintenanceWindowInput `json:"maintenanceWindow"`
+	KubernetesConfig                    *KubernetesConfigInput  `json:"kubernetesConfig"`
 }
 
 type GardenerUpgradeInput struct {
@@ -150,6 +187,8 @@ type GardenerUpgradeInput struct {
 	EnableKubernetesVersionAutoUpdate   *bool                  `json:"enableKubernetesVersionAutoUpdate"`
 	EnableMachineImageVersionAutoUpdate *bool                  `json:"enableMachineImageVersionAutoUpdate"`
 	ProviderSpecificConfig              *ProviderSpecificInput `json:"providerSpecificConfig"`
+	TargetSecret                        *string                `json:"targetSecret"`
+	KubernetesConfig                    *KubernetesConfigInput `json:"kubernetesConfig"`
 }
 
 type HibernationStatus struct {
@@ -157,6 +196,18 @@ type HibernationStatus struct {
 	HibernationPossible *bool `json:"hibernationPossible"`
 }
 
+type KubeletConfigInput struct {
+	FeatureGates []*FeatureGateInput      `json:"featureGates"`
+	EvictionHard *EvictionThresholdsInput `json:"evictionHard"`
+	MaxPods      *int                     `json:"maxPods"`
+	PodPidsLimit *int                     `json:"podPidsLimit"`
+}
+
+type KubernetesConfigInput struct {
+	Kubelet   *KubeletConfigInput   `json:"kubelet"`
+	APIServer *APIServerConfigInput `json:"apiServer"`
+}
+
 type KymaConfig struct {
 	Version       *string                   `json:"version"`
 	Profile       *KymaProfile              `json:"profile"`
@@ -172,6 +223,16 @@ type KymaConfigInput struct {
 	ConflictStrategy *ConflictStrategy              `json:"conflictStrategy"`
 }
 
+type MaintenanceWindowInput struct {
+	Begin string `json:"begin"`
+	End   string `json:"end"`
+}
+
+type NetworkingInput struct {
+	PrivateCluster *bool    `json:"privateCluster"`
+	AllowedCidrs   []string `json:"allowedCidrs"`
+}
+
 type OpenStackProviderConfig struct {
 	Zones                []string `json:"zones"`
 	FloatingPoolName     string   `json:"floatingPoolName"`
@@ -117,6 +117,7 @@ type ComplexityRoot struct {
 		HibernateRuntime         func(childComplexity int, id string) int
 		ProvisionRuntime         func(childComplexity int, config ProvisionRuntimeInput) int
 		ReconnectRuntimeAgent    func(childComplexity int, id string) int
+		RevokeRuntimeCredentials func(childComplexity int, id string) int
 		RollBackUpgradeOperation func(childComplexity int, id string) int
 		UpgradeRuntime           func(childComplexity int, id string, config UpgradeRuntimeInput) int
 		UpgradeShoot             func(childComplexity int, id string, config UpgradeShootInput) int
@@ -169,6 +170,7 @@ type MutationResolver interface {
 	HibernateRuntime(ctx context.Context, id string) (*OperationStatus, error)
 	RollBackUpgradeOperation(ctx context.Context, id string) (*RuntimeStatus, error)
 	ReconnectRuntimeAgent(ctx context.Context, id string) (string, error)
+	RevokeRuntimeCredentials(ctx context.Context, id string) (string, error)
 }
 type QueryResolver interface {
 	RuntimeStatus(ctx context.Context, id string) (*RuntimeStatus, error)
@@ -539,6 +541,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.ReconnectRuntimeAgent(childComplexity, args["id"].(string)), true
 
+	case "Mutation.revokeRuntimeCredentials":
+		if e.complexity.Mutation.RevokeRuntimeCredentials == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_revokeRuntimeCredentials_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RevokeRuntimeCredentials(childComplexity, args["id"].(string)), true
+
 	case "Mutation.rollBackUpgradeOperation":
 		if e.complexity.Mutation.RollBackUpgradeOperation == nil {
 			break
@@ -971,6 +985,11 @@ input GardenerConfigInput {
     allowPrivilegedContainers: Boolean              # Allow Privileged Containers indicates whether privileged containers are allowed in the Shoot
     providerSpecificConfig: ProviderSpecificInput!  # Additional parameters, vary depending on the target provider
     seed: String                                    # Name of the seed cluster that runs the control plane of the Shoot. If not provided will be assigned automatically
+    annotations: [AnnotationInput]                  # Custom annotations added to the Shoot
+    networking: NetworkingInput                     # Networking configuration of the cluster
+    controlPlane: ControlPlaneInput                 # Configuration of the etcd of the cluster, the defaults of the landscape are used if not provided
+    maintenanceWindow: MaintenanceWindowInput       # Time window in which the maintenance of the Shoot is performed, the default window of the landscape is used if not provided
+    kubernetesConfig: KubernetesConfigInput         # Kubelet and API server options of the cluster, the defaults are used for the options which are not provided
 }
 
 input ProviderSpecificInput {
@@ -982,11 +1001,13 @@ input ProviderSpecificInput {
 
 input GCPProviderConfigInput {
     zones: [String!]!      # Zones in which to create the cluster
+    encryptionKey: String  # Customer-managed Cloud KMS key used to encrypt the disks of the cluster, platform-managed keys are used if empty
 }
 
 input AzureProviderConfigInput {
     vnetCidr: String!   # Classless Inter-Domain Routing for the Azure Virtual Network
     zones: [String!]      # Zones in which to create the cluster
+    encryptionKey: String # Customer-managed Key Vault key used to encrypt the disks of the cluster, platform-managed keys are used if empty
 }
 
 input AWSProviderConfigInput {
@@ -994,6 +1015,7 @@ input AWSProviderConfigInput {
     vpcCidr: String!        # Classless Inter-Domain Routing for the virtual public cloud
     publicCidr: String!     # Classless Inter-Domain Routing for the public subnet
     internalCidr: String!   # Classless Inter-Domain Routing for the private subnet
+    encryptionKey: String   # Customer-managed KMS key used to encrypt the disks of the cluster, platform-managed keys are used if empty
 }
 
 input OpenStackProviderConfigInput {
@@ -1011,6 +1033,57 @@ input KymaConfigInput {
     conflictStrategy: ConflictStrategy        # Defines merging strategy if conflicts occur for global overrides
 }
 
+input NetworkingInput {
+    privateCluster: Boolean   # Specifies if the API server of the cluster is accessible only from the allowed CIDRs
+    allowedCidrs: [String!]   # Classless Inter-Domain Routing ranges from which the API server of the private cluster is accessible
+}
+
+input ControlPlaneInput {
+    etcdEncryption: Boolean         # Specifies if the etcd of the cluster is encrypted
+    etcdEncryptionKeyRef: String    # Customer-managed key used to encrypt the etcd, platform-managed keys are used if empty
+    backupRetentionDays: Int        # Number of days the backups of the etcd are kept
+    highAvailability: Boolean       # Specifies if the etcd and the API server of the cluster are replicated across the zones
+}
+
+input MaintenanceWindowInput {
+    begin: String!      # Start of the maintenance window in the HHMMSS+ZZZZ format, e.g. 220000+0100
+    end: String!        # End of the maintenance window in the HHMMSS+ZZZZ format, e.g. 230000+0100
+}
+
+input KubernetesConfigInput {
+    kubelet: KubeletConfigInput         # Options of the kubelet of the nodes
+    apiServer: APIServerConfigInput     # Options of the API server
+}
+
+input KubeletConfigInput {
+    featureGates: [FeatureGateInput]            # Feature gates enabled or disabled in the kubelet
+    evictionHard: EvictionThresholdsInput       # Thresholds of the available resources of the node below which the pods are evicted
+    maxPods: Int                                # Maximum number of pods on the node
+    podPidsLimit: Int                           # Maximum number of processes in a pod
+}
+
+input APIServerConfigInput {
+    featureGates: [FeatureGateInput]    # Feature gates enabled or disabled in the API server
+}
+
+input FeatureGateInput {
+    name: String!       # Feature gate name
+    enabled: Boolean!   # Specifies if the feature gate is enabled
+}
+
+input EvictionThresholdsInput {
+    memoryAvailable: String     # Threshold of the available memory, a quantity or a percentage, e.g. 100Mi or 5%
+    imageFSAvailable: String    # Threshold of the available space of the image file system
+    imageFSInodesFree: String   # Threshold of the free inodes of the image file system
+    nodeFSAvailable: String     # Threshold of the available space of the node file system
+    nodeFSInodesFree: String    # Threshold of the free inodes of the node file system
+}
+
+input AnnotationInput {
+    key: String!        # Annotation key
+    value: String!      # Annotation value
+}
+
 input ConfigEntryInput {
     key: String!        # Configuration property key
     value: String!      # Configuration property value
@@ -1050,6 +1123,8 @@ input GardenerUpgradeInput {
     enableKubernetesVersionAutoUpdate: Boolean    # Enable KubernetesVersion AutoUpdate indicates whether the patch Kubernetes version may be automatically updated
     enableMachineImageVersionAutoUpdate: Boolean  # Enable MachineImageVersion AutoUpdate indicates whether the machine image version may be automatically updated
     providerSpecificConfig: ProviderSpecificInput # Additional parameters, vary depending on the target provider
+    targetSecret: String                          # Secret in Gardener containing credentials to the target provider, changed when the credentials are rotated
+    kubernetesConfig: KubernetesConfigInput       # Kubelet and API server options of the cluster, applied again with each upgrade
 }
 
 type Mutation {
@@ -1067,6 +1142,9 @@ type Mutation {
 
     # Compass Runtime Agent Connection Management
     reconnectRuntimeAgent(id: String!): String!
+
+    # Credentials Management; revokes the admin kubeconfigs and tokens issued for the Runtime
+    revokeRuntimeCredentials(id: String!): String!
 }
 
 type Query {
@@ -1138,6 +1216,20 @@ func (ec *executionContext) field_Mutation_reconnectRuntimeAgent_args(ctx contex
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_revokeRuntimeCredentials_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_rollBackUpgradeOperation_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -3044,6 +3136,50 @@ func (ec *executionContext) _Mutation_reconnectRuntimeAgent(ctx context.Context,
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
+func (ec *executionContext) _Mutation_revokeRuntimeCredentials(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+		ec.Tracer.EndFieldExecution(ctx)
+	}()
+	rctx := &graphql.ResolverContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_revokeRuntimeCredentials_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().RevokeRuntimeCredentials(rctx, args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
 func (ec *executionContext) _OpenStackProviderConfig_zones(ctx context.Context, field graphql.CollectedField, obj *OpenStackProviderConfig) (ret graphql.Marshaler) {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() {
@@ -4985,6 +5121,24 @@ func (ec *executionContext) ___Type_ofType(ctx context.Context, field graphql.Co
 
 // region    **************************** input.gotpl *****************************
 
+func (ec *executionContext) unmarshalInputAPIServerConfigInput(ctx context.Context, obj interface{}) (APIServerConfigInput, error) {
+	var it APIServerConfigInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "featureGates":
+			var err error
+			it.FeatureGates, err = ec.unmarshalOFeatureGateInput2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputAWSProviderConfigInput(ctx context.Context, obj interface{}) (AWSProviderConfigInput, error) {
 	var it AWSProviderConfigInput
 	var asMap = obj.(map[string]interface{})
@@ -5015,6 +5169,36 @@ func (ec *executionContext) unmarshalInputAWSProviderConfigInput(ctx context.Con
 			if err != nil {
 				return it, err
 			}
+		case "encryptionKey":
+			var err error
+			it.EncryptionKey, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputAnnotationInput(ctx context.Context, obj interface{}) (AnnotationInput, error) {
+	var it AnnotationInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "key":
+			var err error
+			it.Key, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "value":
+			var err error
+			it.Value, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -5039,6 +5223,12 @@ func (ec *executionContext) unmarshalInputAzureProviderConfigInput(ctx context.C
 			if err != nil {
 				return it, err
 			}
+		case "encryptionKey":
+			var err error
+			it.EncryptionKey, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -5135,6 +5325,108 @@ func (ec *executionContext) unmarshalInputConfigEntryInput(ctx context.Context,
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputControlPlaneInput(ctx context.Context, obj interface{}) (ControlPlaneInput, error) {
+	var it ControlPlaneInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "etcdEncryption":
+			var err error
+			it.EtcdEncryption, err = ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "etcdEncryptionKeyRef":
+			var err error
+			it.EtcdEncryptionKeyRef, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "backupRetentionDays":
+			var err error
+			it.BackupRetentionDays, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "highAvailability":
+			var err error
+			it.HighAvailability, err = ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputEvictionThresholdsInput(ctx context.Context, obj interface{}) (EvictionThresholdsInput, error) {
+	var it EvictionThresholdsInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "memoryAvailable":
+			var err error
+			it.MemoryAvailable, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "imageFSAvailable":
+			var err error
+			it.ImageFSAvailable, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "imageFSInodesFree":
+			var err error
+			it.ImageFSInodesFree, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "nodeFSAvailable":
+			var err error
+			it.NodeFSAvailable, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "nodeFSInodesFree":
+			var err error
+			it.NodeFSInodesFree, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputFeatureGateInput(ctx context.Context, obj interface{}) (FeatureGateInput, error) {
+	var it FeatureGateInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "name":
+			var err error
+			it.Name, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "enabled":
+			var err error
+			it.Enabled, err = ec.unmarshalNBoolean2bool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputGCPProviderConfigInput(ctx context.Context, obj interface{}) (GCPProviderConfigInput, error) {
 	var it GCPProviderConfigInput
 	var asMap = obj.(map[string]interface{})
@@ -5147,6 +5439,12 @@ func (ec *executionContext) unmarshalInputGCPProviderConfigInput(ctx context.Con
 			if err != nil {
 				return it, err
 			}
+		case "encryptionKey":
+			var err error
+			it.EncryptionKey, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -5291,6 +5589,36 @@ func (ec *executionContext) unmarshalInputGardenerConfigInput(ctx context.Contex
 			if err != nil {
 				return it, err
 			}
+		case "annotations":
+			var err error
+			it.Annotations, err = ec.unmarshalOAnnotationInput2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "networking":
+			var err error
+			it.Networking, err = ec.unmarshalONetworkingInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐNetworkingInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "controlPlane":
+			var err error
+			it.ControlPlane, err = ec.unmarshalOControlPlaneInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐControlPlaneInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "maintenanceWindow":
+			var err error
+			it.MaintenanceWindow, err = ec.unmarshalOMaintenanceWindowInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐMaintenanceWindowInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "kubernetesConfig":
+			var err error
+			it.KubernetesConfig, err = ec.unmarshalOKubernetesConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubernetesConfigInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -5387,6 +5715,78 @@ func (ec *executionContext) unmarshalInputGardenerUpgradeInput(ctx context.Conte
 			if err != nil {
 				return it, err
 			}
+		case "targetSecret":
+			var err error
+			it.TargetSecret, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "kubernetesConfig":
+			var err error
+			it.KubernetesConfig, err = ec.unmarshalOKubernetesConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubernetesConfigInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputKubeletConfigInput(ctx context.Context, obj interface{}) (KubeletConfigInput, error) {
+	var it KubeletConfigInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "featureGates":
+			var err error
+			it.FeatureGates, err = ec.unmarshalOFeatureGateInput2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "evictionHard":
+			var err error
+			it.EvictionHard, err = ec.unmarshalOEvictionThresholdsInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐEvictionThresholdsInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "maxPods":
+			var err error
+			it.MaxPods, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "podPidsLimit":
+			var err error
+			it.PodPidsLimit, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputKubernetesConfigInput(ctx context.Context, obj interface{}) (KubernetesConfigInput, error) {
+	var it KubernetesConfigInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "kubelet":
+			var err error
+			it.Kubelet, err = ec.unmarshalOKubeletConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubeletConfigInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "apiServer":
+			var err error
+			it.APIServer, err = ec.unmarshalOAPIServerConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAPIServerConfigInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -5435,6 +5835,54 @@ func (ec *executionContext) unmarshalInputKymaConfigInput(ctx context.Context, o
 	return it, nil
 }
 
+func (ec *executionContext) unmarshalInputMaintenanceWindowInput(ctx context.Context, obj interface{}) (MaintenanceWindowInput, error) {
+	var it MaintenanceWindowInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "begin":
+			var err error
+			it.Begin, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "end":
+			var err error
+			it.End, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputNetworkingInput(ctx context.Context, obj interface{}) (NetworkingInput, error) {
+	var it NetworkingInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "privateCluster":
+			var err error
+			it.PrivateCluster, err = ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "allowedCidrs":
+			var err error
+			it.AllowedCidrs, err = ec.unmarshalOString2ᚕstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
 func (ec *executionContext) unmarshalInputOpenStackProviderConfigInput(ctx context.Context, obj interface{}) (OpenStackProviderConfigInput, error) {
 	var it OpenStackProviderConfigInput
 	var asMap = obj.(map[string]interface{})
@@ -5970,6 +6418,11 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
+		case "revokeRuntimeCredentials":
+			out.Values[i] = ec._Mutation_revokeRuntimeCredentials(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -6887,6 +7340,18 @@ func (ec *executionContext) marshalN__TypeKind2string(ctx context.Context, sel a
 	return res
 }
 
+func (ec *executionContext) unmarshalOAPIServerConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAPIServerConfigInput(ctx context.Context, v interface{}) (APIServerConfigInput, error) {
+	return ec.unmarshalInputAPIServerConfigInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOAPIServerConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAPIServerConfigInput(ctx context.Context, v interface{}) (*APIServerConfigInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOAPIServerConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAPIServerConfigInput(ctx, v)
+	return &res, err
+}
+
 func (ec *executionContext) unmarshalOAWSProviderConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAWSProviderConfigInput(ctx context.Context, v interface{}) (AWSProviderConfigInput, error) {
 	return ec.unmarshalInputAWSProviderConfigInput(ctx, v)
 }
@@ -6899,6 +7364,38 @@ func (ec *executionContext) unmarshalOAWSProviderConfigInput2ᚖgithubᚗcomᚋk
 	return &res, err
 }
 
+func (ec *executionContext) unmarshalOAnnotationInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx context.Context, v interface{}) (AnnotationInput, error) {
+	return ec.unmarshalInputAnnotationInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOAnnotationInput2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx context.Context, v interface{}) ([]*AnnotationInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		if tmp1, ok := v.([]interface{}); ok {
+			vSlice = tmp1
+		} else {
+			vSlice = []interface{}{v}
+		}
+	}
+	var err error
+	res := make([]*AnnotationInput, len(vSlice))
+	for i := range vSlice {
+		res[i], err = ec.unmarshalOAnnotationInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalOAnnotationInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx context.Context, v interface{}) (*AnnotationInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOAnnotationInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAnnotationInput(ctx, v)
+	return &res, err
+}
+
 func (ec *executionContext) unmarshalOAzureProviderConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐAzureProviderConfigInput(ctx context.Context, v interface{}) (AzureProviderConfigInput, error) {
 	return ec.unmarshalInputAzureProviderConfigInput(ctx, v)
 }
@@ -7104,6 +7601,18 @@ func (ec *executionContext) marshalOConflictStrategy2ᚖgithubᚗcomᚋkymaᚑpr
 	return v
 }
 
+func (ec *executionContext) unmarshalOControlPlaneInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐControlPlaneInput(ctx context.Context, v interface{}) (ControlPlaneInput, error) {
+	return ec.unmarshalInputControlPlaneInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOControlPlaneInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐControlPlaneInput(ctx context.Context, v interface{}) (*ControlPlaneInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOControlPlaneInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐControlPlaneInput(ctx, v)
+	return &res, err
+}
+
 func (ec *executionContext) marshalOError2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐError(ctx context.Context, sel ast.SelectionSet, v []*Error) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -7144,6 +7653,50 @@ func (ec *executionContext) marshalOError2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋ
 	return ret
 }
 
+func (ec *executionContext) unmarshalOEvictionThresholdsInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐEvictionThresholdsInput(ctx context.Context, v interface{}) (EvictionThresholdsInput, error) {
+	return ec.unmarshalInputEvictionThresholdsInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOEvictionThresholdsInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐEvictionThresholdsInput(ctx context.Context, v interface{}) (*EvictionThresholdsInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOEvictionThresholdsInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐEvictionThresholdsInput(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOFeatureGateInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx context.Context, v interface{}) (FeatureGateInput, error) {
+	return ec.unmarshalInputFeatureGateInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOFeatureGateInput2ᚕᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx context.Context, v interface{}) ([]*FeatureGateInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		if tmp1, ok := v.([]interface{}); ok {
+			vSlice = tmp1
+		} else {
+			vSlice = []interface{}{v}
+		}
+	}
+	var err error
+	res := make([]*FeatureGateInput, len(vSlice))
+	for i := range vSlice {
+		res[i], err = ec.unmarshalOFeatureGateInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalOFeatureGateInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx context.Context, v interface{}) (*FeatureGateInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOFeatureGateInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐFeatureGateInput(ctx, v)
+	return &res, err
+}
+
 func (ec *executionContext) unmarshalOGCPProviderConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐGCPProviderConfigInput(ctx context.Context, v interface{}) (GCPProviderConfigInput, error) {
 	return ec.unmarshalInputGCPProviderConfigInput(ctx, v)
 }
@@ -7260,6 +7813,54 @@ func (ec *executionContext) marshalOLabels2ᚖgithubᚗcomᚋkymaᚑprojectᚋco
 	return v
 }
 
+func (ec *executionContext) unmarshalOKubeletConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubeletConfigInput(ctx context.Context, v interface{}) (KubeletConfigInput, error) {
+	return ec.unmarshalInputKubeletConfigInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOKubeletConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubeletConfigInput(ctx context.Context, v interface{}) (*KubeletConfigInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOKubeletConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubeletConfigInput(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOKubernetesConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubernetesConfigInput(ctx context.Context, v interface{}) (KubernetesConfigInput, error) {
+	return ec.unmarshalInputKubernetesConfigInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOKubernetesConfigInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubernetesConfigInput(ctx context.Context, v interface{}) (*KubernetesConfigInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOKubernetesConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐKubernetesConfigInput(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOMaintenanceWindowInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐMaintenanceWindowInput(ctx context.Context, v interface{}) (MaintenanceWindowInput, error) {
+	return ec.unmarshalInputMaintenanceWindowInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOMaintenanceWindowInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐMaintenanceWindowInput(ctx context.Context, v interface{}) (*MaintenanceWindowInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOMaintenanceWindowInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐMaintenanceWindowInput(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalONetworkingInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐNetworkingInput(ctx context.Context, v interface{}) (NetworkingInput, error) {
+	return ec.unmarshalInputNetworkingInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalONetworkingInput2ᚖgithubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐNetworkingInput(ctx context.Context, v interface{}) (*NetworkingInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalONetworkingInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐNetworkingInput(ctx, v)
+	return &res, err
+}
+
 func (ec *executionContext) unmarshalOOpenStackProviderConfigInput2githubᚗcomᚋkymaᚑprojectᚋcontrolᚑplaneᚋcomponentsᚋprovisionerᚋpkgᚋgqlschemaᚐOpenStackProviderConfigInput(ctx context.Context, v interface{}) (OpenStackProviderConfigInput, error) {
 	return ec.unmarshalInputOpenStackProviderConfigInput(ctx, v)
 }
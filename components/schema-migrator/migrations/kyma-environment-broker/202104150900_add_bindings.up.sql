@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS bindings (
+    id varchar(255) PRIMARY KEY,
+    instance_id varchar(255) NOT NULL,
+    operation_id varchar(255) NOT NULL,
+    operation_type varchar(32) NOT NULL,
+    state varchar(32) NOT NULL,
+    description text,
+    credentials text,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    version integer NOT NULL);
+
+CREATE INDEX bindings_instance_id ON bindings (instance_id);
@@ -0,0 +1,4 @@
+DROP INDEX operations_by_current_step_and_state;
+
+ALTER TABLE operations
+    DROP COLUMN current_step;
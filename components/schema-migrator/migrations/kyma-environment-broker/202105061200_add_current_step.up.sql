@@ -0,0 +1,4 @@
+ALTER TABLE operations
+    ADD COLUMN current_step varchar(255);
+
+CREATE INDEX operations_by_current_step_and_state ON operations USING btree (current_step, state);
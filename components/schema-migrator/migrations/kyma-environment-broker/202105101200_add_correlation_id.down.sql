@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    DROP COLUMN correlation_id;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    ADD COLUMN correlation_id varchar(64);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    DROP COLUMN trace_parent;
\ No newline at end of file
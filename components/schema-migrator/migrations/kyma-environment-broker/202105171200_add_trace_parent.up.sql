@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    ADD COLUMN trace_parent varchar(64);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX instances_by_sub_account_id_and_service_plan_id;
\ No newline at end of file
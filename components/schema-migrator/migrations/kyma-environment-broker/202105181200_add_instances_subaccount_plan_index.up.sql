@@ -0,0 +1 @@
+CREATE INDEX instances_by_sub_account_id_and_service_plan_id ON instances USING btree (sub_account_id, service_plan_id);
\ No newline at end of file
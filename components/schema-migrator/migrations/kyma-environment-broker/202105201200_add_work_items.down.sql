@@ -0,0 +1 @@
+DROP TABLE work_items;
\ No newline at end of file
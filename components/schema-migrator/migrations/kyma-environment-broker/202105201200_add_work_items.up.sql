@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS work_items (
+    queue varchar(64) NOT NULL,
+    operation_id varchar(255) NOT NULL,
+    sub_account_id varchar(255),
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (queue, operation_id));
+
+-- the operations in progress are resumed from the work items after the restart, so the ones started before the table existed are added here
+INSERT INTO work_items (queue, operation_id, sub_account_id, created_at)
+SELECT CASE type WHEN 'provision' THEN 'provisioning' ELSE 'deprovisioning' END, id, provisioning_parameters->'ers_context'->>'subaccount_id', created_at
+FROM operations
+WHERE type IN ('provision', 'deprovision') AND state IN ('in progress', 'pending');
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    DROP COLUMN failure_reason;
\ No newline at end of file
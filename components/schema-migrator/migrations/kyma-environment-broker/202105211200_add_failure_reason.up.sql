@@ -0,0 +1,2 @@
+ALTER TABLE operations
+    ADD COLUMN failure_reason text;
\ No newline at end of file
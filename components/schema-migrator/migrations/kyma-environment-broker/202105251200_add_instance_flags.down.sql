@@ -0,0 +1 @@
+DROP TABLE instance_flags;
\ No newline at end of file
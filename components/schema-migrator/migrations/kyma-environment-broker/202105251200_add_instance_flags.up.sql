@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS instance_flags (
+    instance_id varchar(255) PRIMARY KEY,
+    flags text NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE instances
+    DROP COLUMN suspension_reason;
\ No newline at end of file
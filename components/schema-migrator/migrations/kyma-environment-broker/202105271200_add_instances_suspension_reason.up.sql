@@ -0,0 +1,2 @@
+ALTER TABLE instances
+    ADD COLUMN suspension_reason varchar(32) NOT NULL DEFAULT '';
\ No newline at end of file
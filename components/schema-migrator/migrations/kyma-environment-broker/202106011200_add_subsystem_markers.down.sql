@@ -0,0 +1 @@
+DROP TABLE subsystem_markers;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS subsystem_markers (
+    instance_id varchar(255) NOT NULL,
+    subsystem varchar(64) NOT NULL,
+    operation_id varchar(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (instance_id, subsystem));
+
+-- the EDP markers are the references to the DataTenant shared in the subaccount, so the instances registered in EDP before the table existed are marked here
+INSERT INTO subsystem_markers (instance_id, subsystem, operation_id, created_at)
+SELECT DISTINCT ON (o.instance_id) o.instance_id, 'edp', o.id, o.updated_at
+FROM operations o
+JOIN instances i ON i.instance_id = o.instance_id
+WHERE o.type = 'provision' AND o.state = 'succeeded'
+ORDER BY o.instance_id, o.updated_at DESC
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE work_items
+    DROP COLUMN priority;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE work_items
+    ADD COLUMN priority integer NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE catalog_snapshots;
\ No newline at end of file
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS catalog_snapshots (
+    etag varchar(255) PRIMARY KEY,
+    catalog text NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL);
\ No newline at end of file
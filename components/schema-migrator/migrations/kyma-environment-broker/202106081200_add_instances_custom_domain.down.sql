@@ -0,0 +1,4 @@
+DROP INDEX instances_custom_domain;
+
+ALTER TABLE instances
+    DROP COLUMN custom_domain;
@@ -0,0 +1,4 @@
+ALTER TABLE instances
+    ADD COLUMN custom_domain varchar(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX instances_custom_domain ON instances USING btree (custom_domain) WHERE custom_domain <> '';